@@ -7,6 +7,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 )
 
 // Database represents the database command
@@ -145,6 +146,24 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction tag index table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSearchIndex))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction search index table maintained successfully")
+
+	userCount, transactionCount, err := services.TransactionSearchIndexes.BuildMissingTransactionSearchIndexes(c)
+
+	if err != nil {
+		return err
+	}
+
+	if userCount > 0 {
+		log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction search index of %d transactions of %d users built successfully", transactionCount, userCount)
+	}
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionTemplate))
 
 	if err != nil {
@@ -225,6 +244,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] asset table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.AssetTransaction))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] asset transaction table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestorDeal))
 
 	if err != nil {
@@ -241,6 +268,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investor_payment table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestorScheduleTransaction))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investor schedule transaction table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.Budget))
 
 	if err != nil {
@@ -257,6 +292,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] obligation table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.ObligationPayment))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] obligation_payment table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TaxRecord))
 
 	if err != nil {
@@ -265,5 +308,45 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] tax_record table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TaxRegime))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] tax_regime table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.StatusHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] status_history table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.NotificationPreference))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] notification_preference table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.MCPAuditLog))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] mcp_audit_log table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.ExchangeRateHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] exchange_rate_history table maintained successfully")
+
 	return nil
 }
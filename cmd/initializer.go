@@ -186,31 +186,39 @@ func getConfigWithoutSensitiveData(config *settings.Config) *settings.Config {
 		clonedConfig.WebDAVConfig.Password = "****"
 	}
 
-	if clonedConfig.ReceiptImageRecognitionLLMConfig != nil {
-		if clonedConfig.ReceiptImageRecognitionLLMConfig.OpenAIAPIKey != "" {
-			clonedConfig.ReceiptImageRecognitionLLMConfig.OpenAIAPIKey = "****"
-		}
+	hideLLMConfigSensitiveData(clonedConfig.ReceiptImageRecognitionLLMConfig)
+	hideLLMConfigSensitiveData(clonedConfig.TransactionImportCategorizationLLMConfig)
+	hideLLMConfigSensitiveData(clonedConfig.TransactionQueryLLMConfig)
 
-		if clonedConfig.ReceiptImageRecognitionLLMConfig.OpenAICompatibleAPIKey != "" {
-			clonedConfig.ReceiptImageRecognitionLLMConfig.OpenAICompatibleAPIKey = "****"
-		}
+	if clonedConfig.OAuth2ClientSecret != "" {
+		clonedConfig.OAuth2ClientSecret = "****"
+	}
 
-		if clonedConfig.ReceiptImageRecognitionLLMConfig.OpenRouterAPIKey != "" {
-			clonedConfig.ReceiptImageRecognitionLLMConfig.OpenRouterAPIKey = "****"
-		}
+	return clonedConfig
+}
 
-		if clonedConfig.ReceiptImageRecognitionLLMConfig.LMStudioToken != "" {
-			clonedConfig.ReceiptImageRecognitionLLMConfig.LMStudioToken = "****"
-		}
+func hideLLMConfigSensitiveData(llmConfig *settings.LLMConfig) {
+	if llmConfig == nil {
+		return
+	}
 
-		if clonedConfig.ReceiptImageRecognitionLLMConfig.GoogleAIAPIKey != "" {
-			clonedConfig.ReceiptImageRecognitionLLMConfig.GoogleAIAPIKey = "****"
-		}
+	if llmConfig.OpenAIAPIKey != "" {
+		llmConfig.OpenAIAPIKey = "****"
 	}
 
-	if clonedConfig.OAuth2ClientSecret != "" {
-		clonedConfig.OAuth2ClientSecret = "****"
+	if llmConfig.OpenAICompatibleAPIKey != "" {
+		llmConfig.OpenAICompatibleAPIKey = "****"
 	}
 
-	return clonedConfig
+	if llmConfig.OpenRouterAPIKey != "" {
+		llmConfig.OpenRouterAPIKey = "****"
+	}
+
+	if llmConfig.LMStudioToken != "" {
+		llmConfig.LMStudioToken = "****"
+	}
+
+	if llmConfig.GoogleAIAPIKey != "" {
+		llmConfig.GoogleAIAPIKey = "****"
+	}
 }
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli/v3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/api"
+	clis "github.com/mayswind/ezbookkeeping/pkg/cli"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpStdioClientAddress = "127.0.0.1:0"
+
+// MCPServer represents the mcp server command
+var MCPServer = &cli.Command{
+	Name:  "mcp",
+	Usage: "ezBookkeeping MCP (Model Context Protocol) server",
+	Commands: []*cli.Command{
+		{
+			Name:   "serve",
+			Usage:  "Serve MCP over stdin and stdout for specified user (all logs are written to stderr)",
+			Action: bindAction(serveMCPOverStdio),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
+	},
+}
+
+// getMCPJSONRPCApiHandlers returns the handlers of all supported MCP JSON-RPC methods
+func getMCPJSONRPCApiHandlers() map[string]core.JSONRPCApiHandlerFunc {
+	return map[string]core.JSONRPCApiHandlerFunc{
+		"initialize":     api.ModelContextProtocols.InitializeHandler,
+		"resources/list": api.ModelContextProtocols.ListResourcesHandler,
+		"resources/read": api.ModelContextProtocols.ReadResourceHandler,
+		"tools/list":     api.ModelContextProtocols.ListToolsHandler,
+		"tools/call":     api.ModelContextProtocols.CallToolHandler,
+		"prompts/list":   api.ModelContextProtocols.ListPromptsHandler,
+		"prompts/get":    api.ModelContextProtocols.GetPromptHandler,
+		"ping":           api.ModelContextProtocols.PingHandler,
+	}
+}
+
+func serveMCPOverStdio(c *core.CliContext) error {
+	// stdout is reserved for MCP messages, so all console logs are written to stderr
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	gin.DefaultWriter = os.Stderr
+	gin.SetMode(gin.ReleaseMode)
+
+	config, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	if !config.EnableMCPServer {
+		log.CliErrorf(c, "[mcp_server.serveMCPOverStdio] mcp server is not enabled")
+		return errs.ErrMCPServerNotEnabled
+	}
+
+	err = mcp.InitializeMCPHandlers(config)
+
+	if err != nil {
+		log.CliErrorf(c, "[mcp_server.serveMCPOverStdio] initializes mcp handlers failed, because %s", err.Error())
+		return err
+	}
+
+	username := c.String("username")
+	user, err := clis.UserData.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[mcp_server.serveMCPOverStdio] error occurs when getting user data")
+		return err
+	}
+
+	if user.Disabled {
+		log.CliErrorf(c, "[mcp_server.serveMCPOverStdio] user \"%s\" is disabled", username)
+		return errs.ErrUserIsDisabled
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		log.CliErrorf(c, "[mcp_server.serveMCPOverStdio] user \"%s\" is not permitted to access mcp server", username)
+		return errs.ErrNotPermittedToPerformThisAction
+	}
+
+	log.CliInfof(c, "[mcp_server.serveMCPOverStdio] mcp server is serving user \"%s\" over stdio", username)
+
+	tokenClaims := &core.UserTokenClaims{
+		Uid:      user.Uid,
+		Username: user.Username,
+		Type:     core.USER_TOKEN_TYPE_MCP,
+		IssuedAt: time.Now().Unix(),
+	}
+
+	return serveMCPStdioMessages(c, user, tokenClaims, getMCPJSONRPCApiHandlers(), os.Stdin, stdout)
+}
+
+// serveMCPStdioMessages handles the newline-delimited JSON-RPC messages read from input and writes the responses to output until input is closed
+func serveMCPStdioMessages(c *core.CliContext, user *models.User, tokenClaims *core.UserTokenClaims, handlers map[string]core.JSONRPCApiHandlerFunc, input io.Reader, output io.Writer) error {
+	reader := bufio.NewReader(input)
+	writer := bufio.NewWriter(output)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			response := handleMCPStdioMessage(user, tokenClaims, handlers, line)
+
+			if response != nil {
+				writeErr := writeMCPStdioResponse(writer, response)
+
+				if writeErr != nil {
+					log.CliErrorf(c, "[mcp_server.serveMCPStdioMessages] failed to write response, because %s", writeErr.Error())
+					return writeErr
+				}
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			log.CliInfof(c, "[mcp_server.serveMCPStdioMessages] stdin is closed, mcp server exits")
+			return nil
+		} else if err != nil {
+			log.CliErrorf(c, "[mcp_server.serveMCPStdioMessages] failed to read request, because %s", err.Error())
+			return err
+		}
+	}
+}
+
+func handleMCPStdioMessage(user *models.User, tokenClaims *core.UserTokenClaims, handlers map[string]core.JSONRPCApiHandlerFunc, message []byte) *core.JSONRPCResponse {
+	var jsonRPCRequest core.JSONRPCRequest
+
+	if err := json.Unmarshal(message, &jsonRPCRequest); err != nil {
+		return utils.GetJSONRPCErrorResponse(nil, errs.NewIncompleteOrIncorrectSubmissionError(err))
+	}
+
+	// notifications (e.g. "notifications/initialized") do not have id and must not be replied
+	if jsonRPCRequest.ID == nil {
+		return nil
+	}
+
+	fn, exists := handlers[jsonRPCRequest.Method]
+
+	if !exists {
+		return utils.GetJSONRPCErrorResponse(&jsonRPCRequest, errs.ErrApiNotFound)
+	}
+
+	c := newMCPStdioWebContext(tokenClaims)
+	result, err := fn(c, &jsonRPCRequest)
+
+	if err != nil {
+		log.Warnf(c, "[mcp_server.handleMCPStdioMessage] failed to handle method \"%s\" for user \"uid:%d\", because %s", jsonRPCRequest.Method, user.Uid, err.Error())
+		return utils.GetJSONRPCErrorResponse(&jsonRPCRequest, err)
+	}
+
+	return core.NewJSONRPCResponse(jsonRPCRequest.ID, result)
+}
+
+// newMCPStdioWebContext returns a web context which acts as a request from local machine authorized by the specified token claims,
+// so that the handlers of http transport can be reused
+func newMCPStdioWebContext(tokenClaims *core.UserTokenClaims) *core.WebContext {
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	ginCtx.Request.RemoteAddr = mcpStdioClientAddress
+
+	_, utcOffset := time.Now().Zone()
+	ginCtx.Request.Header.Set(core.ClientTimezoneOffsetHeaderName, strconv.Itoa(utcOffset/60))
+
+	c := core.WrapWebContext(ginCtx)
+	c.SetTokenClaims(tokenClaims)
+
+	return c
+}
+
+func writeMCPStdioResponse(writer *bufio.Writer, response *core.JSONRPCResponse) error {
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(content); err != nil {
+		return err
+	}
+
+	if err = writer.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// initMCPStdioTestUserStore replaces the user store with an in-memory database which contains the specified user
+func initMCPStdioTestUserStore(t *testing.T, user *models.User) {
+	t.Helper()
+
+	engine, err := xorm.NewEngine("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	assert.Nil(t, engine.Sync2(new(models.User)))
+
+	_, err = engine.Insert(user)
+	assert.Nil(t, err)
+
+	group, err := xorm.NewEngineGroup(engine, []*xorm.Engine{})
+	assert.Nil(t, err)
+
+	testContainer := datastore.NewContainerForTest(datastore.NewDatabaseForTest(group, "sqlite3"))
+	originalUserStore := datastore.Container.UserStore
+	datastore.Container.UserStore = testContainer.UserStore
+
+	t.Cleanup(func() {
+		datastore.Container.UserStore = originalUserStore
+		engine.Close()
+	})
+}
+
+func TestServeMCPStdioMessages(t *testing.T) {
+	user := &models.User{
+		Uid:                1,
+		Username:           "test",
+		Email:              "test@example.com",
+		Nickname:           "Tester",
+		FeatureRestriction: core.UserFeatureRestrictions(0).Add(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS),
+	}
+	initMCPStdioTestUserStore(t, user)
+
+	tokenClaims := &core.UserTokenClaims{
+		Uid:      user.Uid,
+		Username: user.Username,
+		Type:     core.USER_TOKEN_TYPE_MCP,
+	}
+
+	tests := []struct {
+		name              string
+		message           string
+		expectedNoOutput  bool
+		expectedId        any
+		expectedErrorCode int
+		expectedErrorData string
+	}{
+		{
+			name:       "valid request",
+			message:    `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			expectedId: float64(1),
+		},
+		{
+			name:             "notification without id",
+			message:          `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			expectedNoOutput: true,
+		},
+		{
+			name:              "unknown method",
+			message:           `{"jsonrpc":"2.0","id":"2","method":"unknown/method"}`,
+			expectedId:        "2",
+			expectedErrorCode: core.JSONRPCMethodNotFoundError.Code,
+		},
+		{
+			name:              "parse error",
+			message:           `{"jsonrpc":"2.0","id":3,`,
+			expectedId:        nil,
+			expectedErrorCode: core.JSONRPCParseError.Code,
+		},
+		{
+			name:              "tools/call of user restricted from mcp access",
+			message:           `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"query_all_accounts","arguments":{}}}`,
+			expectedId:        float64(4),
+			expectedErrorCode: core.JSONRPCInternalError.Code,
+			expectedErrorData: errs.ErrNotPermittedToPerformThisAction.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := core.WrapCilContext(context.Background(), MCPServer)
+			output := &bytes.Buffer{}
+
+			err := serveMCPStdioMessages(c, user, tokenClaims, getMCPJSONRPCApiHandlers(), strings.NewReader(test.message+"\n"), output)
+			assert.Nil(t, err)
+
+			if test.expectedNoOutput {
+				assert.Equal(t, "", output.String())
+				return
+			}
+
+			lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+			assert.Equal(t, 1, len(lines))
+
+			var response map[string]any
+			assert.Nil(t, json.Unmarshal([]byte(lines[0]), &response))
+			assert.Equal(t, core.JSONRPCVersion, response["jsonrpc"])
+			assert.Equal(t, test.expectedId, response["id"])
+
+			if test.expectedErrorCode == 0 {
+				assert.Nil(t, response["error"])
+				assert.Equal(t, map[string]any{}, response["result"])
+			} else {
+				assert.Nil(t, response["result"])
+				assert.Equal(t, float64(test.expectedErrorCode), response["error"].(map[string]any)["code"])
+
+				if test.expectedErrorData != "" {
+					assert.Equal(t, test.expectedErrorData, response["error"].(map[string]any)["data"])
+				}
+			}
+		})
+	}
+}
+
+func TestServeMCPStdioMessages_MultipleMessages(t *testing.T) {
+	user := &models.User{Uid: 1, Username: "test"}
+	tokenClaims := &core.UserTokenClaims{Uid: user.Uid, Username: user.Username, Type: core.USER_TOKEN_TYPE_MCP}
+	c := core.WrapCilContext(context.Background(), MCPServer)
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	}, "\n")
+	output := &bytes.Buffer{}
+
+	// the last message without trailing newline is still handled before stdin is closed
+	err := serveMCPStdioMessages(c, user, tokenClaims, getMCPJSONRPCApiHandlers(), strings.NewReader(input), output)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"jsonrpc\":\"2.0\",\"result\":{},\"id\":1}\n{\"jsonrpc\":\"2.0\",\"result\":{},\"id\":2}\n", output.String())
+}
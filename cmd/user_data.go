@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	clis "github.com/mayswind/ezbookkeeping/pkg/cli"
+	"github.com/mayswind/ezbookkeeping/pkg/converters"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -339,6 +340,19 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "transaction-search-index-rebuild",
+			Usage:  "Rebuild the transaction search index data of specified user",
+			Action: bindAction(rebuildTransactionSearchIndex),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
 		{
 			Name:   "transaction-import",
 			Usage:  "Import transactions to specified user",
@@ -385,7 +399,7 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Export file type, support csv or tsv, default is csv",
+					Usage:    "Export file type, support csv, tsv, ofx, qif, beancount, ledger or xlsx, default is csv",
 				},
 			},
 		},
@@ -843,6 +857,29 @@ func fixTransactionTagIndexNotHaveTransactionTime(c *core.CliContext) error {
 	return nil
 }
 
+func rebuildTransactionSearchIndex(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+
+	log.CliInfof(c, "[user_data.rebuildTransactionSearchIndex] starting rebuilding user \"%s\" transaction search index data", username)
+
+	count, err := clis.UserData.RebuildTransactionSearchIndex(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.rebuildTransactionSearchIndex] error occurs when rebuilding user data")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.rebuildTransactionSearchIndex] search index of %d transactions has been rebuilt successfully", count)
+
+	return nil
+}
+
 func exportUserTransaction(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -858,7 +895,11 @@ func exportUserTransaction(c *core.CliContext) error {
 		fileType = "csv"
 	}
 
-	if fileType != "csv" && fileType != "tsv" {
+	if fileType == "csv" || fileType == "tsv" {
+		fileType = "ezbookkeeping_" + fileType
+	}
+
+	if converters.GetTransactionDataExporter(fileType) == nil {
 		log.CliErrorf(c, "[user_data.exportUserTransaction] export file type is not supported")
 		return errs.ErrNotSupported
 	}
@@ -4,16 +4,23 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/requestid"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
+const maxBackfillExchangeRatesDays = 3660
+
 // Utilities represents the utilities command
 var Utilities = &cli.Command{
 	Name:  "utility",
@@ -43,6 +50,23 @@ var Utilities = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "backfill-exchange-rates",
+			Usage:  "Request the historical exchange rates of specified date range from the current exchange rates data source and save them into the exchange rates history",
+			Action: bindAction(backfillExchangeRates),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "start-date",
+					Required: true,
+					Usage:    "Start date (YYYY-MM-DD)",
+				},
+				&cli.StringFlag{
+					Name:     "end-date",
+					Required: false,
+					Usage:    "End date (YYYY-MM-DD), default is today",
+				},
+			},
+		},
 	},
 }
 
@@ -102,6 +126,69 @@ func sendTestMail(c *core.CliContext) error {
 	return nil
 }
 
+func backfillExchangeRates(c *core.CliContext) error {
+	config, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	if !exchangerates.Container.SupportsHistoricalExchangeRates() {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] exchange rates data source \"%s\" does not support historical exchange rates", config.ExchangeRatesDataSource)
+		return errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	startDate, err := models.ParseExchangeRateHistoryDate(c.String("start-date"))
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] start date is invalid, because %s", err.Error())
+		return errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	endDate, err := models.ParseExchangeRateHistoryDate(models.GetExchangeRateHistoryDate(time.Now().Unix()))
+
+	if c.String("end-date") != "" {
+		endDate, err = models.ParseExchangeRateHistoryDate(c.String("end-date"))
+	}
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] end date is invalid, because %s", err.Error())
+		return errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	if endDate.Before(startDate) || endDate.Sub(startDate) > maxBackfillExchangeRatesDays*24*time.Hour {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] date range must be between 1 and %d days", maxBackfillExchangeRatesDays)
+		return errs.ErrExchangeRateHistoryDateRangeInvalid
+	}
+
+	savedDates := make(map[string]bool)
+	failedCount := 0
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		exchangeRates, err := exchangerates.Container.GetHistoricalExchangeRates(c, 0, date, config)
+
+		if err != nil {
+			log.CliWarnf(c, "[utility.backfillExchangeRates] failed to request exchange rates of %s, because %s", date.Format(models.ExchangeRateHistoryDateFormat), err.Error())
+			failedCount++
+			continue
+		}
+
+		rateDate, err := services.ExchangeRateHistories.SaveExchangeRates(c, config.ExchangeRatesDataSource, exchangeRates)
+
+		if err != nil {
+			log.CliErrorf(c, "[utility.backfillExchangeRates] failed to save exchange rates of %s, because %s", date.Format(models.ExchangeRateHistoryDateFormat), err.Error())
+			return err
+		}
+
+		log.CliInfof(c, "[utility.backfillExchangeRates] exchange rates of %s have been saved", rateDate)
+		savedDates[rateDate] = true
+	}
+
+	log.CliInfof(c, "[utility.backfillExchangeRates] exchange rates of %d dates have been saved, %d requests failed", len(savedDates), failedCount)
+
+	return nil
+}
+
 func printRequestIdInfo(requestId string, requestIdInfo *requestid.RequestIdInfo, newRequestIdInfo *requestid.RequestIdInfo) {
 	fmt.Printf("[RequestId] %s\n", requestId)
 	fmt.Printf("[ServerUniqId] %d (Current Server %d)\n", requestIdInfo.ServerUniqId, newRequestIdInfo.ServerUniqId)
@@ -117,6 +117,9 @@ func startWebServer(c *core.CliContext) error {
 		_ = v.RegisterValidation("validHexRGBColor", validators.ValidHexRGBColor)
 		_ = v.RegisterValidation("validAmountFilter", validators.ValidAmountFilter)
 		_ = v.RegisterValidation("validTagFilter", validators.ValidTagFilter)
+		_ = v.RegisterValidation("validGeoBoundsFilter", validators.ValidGeoBoundsFilter)
+		_ = v.RegisterValidation("validCommentFilter", validators.ValidCommentFilter)
+		_ = v.RegisterValidation("validConditionGroups", validators.ValidConditionGroups)
 		_ = v.RegisterValidation("validFiscalYearStart", validators.ValidateFiscalYearStart)
 	}
 
@@ -238,14 +241,7 @@ func startWebServer(c *core.CliContext) error {
 		mcpRoute.Use(bindMiddleware(middlewares.MCPServerIpLimit(config)))
 		mcpRoute.Use(bindMiddleware(middlewares.JWTMCPAuthorization(config)))
 		{
-			mcpRoute.POST("", bindJSONRPCApi(map[string]core.JSONRPCApiHandlerFunc{
-				"initialize":     api.ModelContextProtocols.InitializeHandler,
-				"resources/list": api.ModelContextProtocols.ListResourcesHandler,
-				"resources/read": api.ModelContextProtocols.ReadResourceHandler,
-				"tools/list":     api.ModelContextProtocols.ListToolsHandler,
-				"tools/call":     api.ModelContextProtocols.CallToolHandler,
-				"ping":           api.ModelContextProtocols.PingHandler,
-			}, map[string]int{
+			mcpRoute.POST("", bindJSONRPCApi(getMCPJSONRPCApiHandlers(), map[string]int{
 				"notifications/initialized": http.StatusAccepted,
 			}))
 			mcpRoute.GET("", bindApi(api.Default.MethodNotAllowed))
@@ -324,6 +320,7 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/tokens/revoke.json", bindApi(api.Tokens.TokenRevokeHandler))
 			apiV1Route.POST("/tokens/revoke_all.json", bindApi(api.Tokens.TokenRevokeAllHandler))
 			apiV1Route.POST("/tokens/refresh.json", bindApiWithTokenUpdate(api.Tokens.TokenRefreshHandler, config))
+			apiV1Route.GET("/tokens/mcp/audit_logs/list.json", bindApi(api.MCPAuditLogsAPI.AuditLogListHandler))
 
 			// Users
 			apiV1Route.GET("/users/profile/get.json", bindApi(api.Users.UserProfileHandler))
@@ -367,6 +364,11 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+				apiV1Route.GET("/data/export.ofx", bindDataFile(api.DataManagements.ExportDataToOFXHandler, "application/x-ofx; charset=utf-8"))
+				apiV1Route.GET("/data/export.qif", bindDataFile(api.DataManagements.ExportDataToQIFHandler, "application/qif; charset=utf-8"))
+				apiV1Route.GET("/data/export.beancount", bindDataFile(api.DataManagements.ExportDataToBeancountHandler, "text/plain; charset=utf-8"))
+				apiV1Route.GET("/data/export.ledger", bindDataFile(api.DataManagements.ExportDataToLedgerHandler, "text/plain; charset=utf-8"))
+				apiV1Route.GET("/data/export.xlsx", bindDataFile(api.DataManagements.ExportDataToXlsxHandler, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"))
 			}
 
 			// Accounts
@@ -473,10 +475,16 @@ func startWebServer(c *core.CliContext) error {
 			// Assets
 			apiV1Route.GET("/assets/list.json", bindApi(api.Assets.AssetListHandler))
 			apiV1Route.GET("/assets/get.json", bindApi(api.Assets.AssetGetHandler))
+			apiV1Route.GET("/assets/depreciation_schedule.json", bindApi(api.Assets.AssetDepreciationScheduleHandler))
+			apiV1Route.GET("/assets/transactions/list.json", bindApi(api.Assets.AssetTransactionListHandler))
 			apiV1Route.POST("/assets/add.json", bindApi(api.Assets.AssetCreateHandler))
 			apiV1Route.POST("/assets/modify.json", bindApi(api.Assets.AssetModifyHandler))
 			apiV1Route.POST("/assets/hide.json", bindApi(api.Assets.AssetHideHandler))
 			apiV1Route.POST("/assets/move.json", bindApi(api.Assets.AssetMoveHandler))
+			apiV1Route.POST("/assets/production_outputs/save.json", bindApi(api.Assets.AssetProductionOutputSaveHandler))
+			apiV1Route.POST("/assets/dispose.json", bindApi(api.Assets.AssetDisposeHandler))
+			apiV1Route.POST("/assets/purchase_transactions/link.json", bindApi(api.Assets.AssetPurchaseTransactionLinkHandler))
+			apiV1Route.POST("/assets/purchase_transactions/unlink.json", bindApi(api.Assets.AssetPurchaseTransactionUnlinkHandler))
 			apiV1Route.POST("/assets/delete.json", bindApi(api.Assets.AssetDeleteHandler))
 
 			// Investor Deals
@@ -485,6 +493,8 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/investor/deals/add.json", bindApi(api.InvestorDeals.DealCreateHandler))
 			apiV1Route.POST("/investor/deals/modify.json", bindApi(api.InvestorDeals.DealModifyHandler))
 			apiV1Route.POST("/investor/deals/delete.json", bindApi(api.InvestorDeals.DealDeleteHandler))
+			apiV1Route.GET("/investor/deals/schedule.json", bindApi(api.InvestorDeals.ScheduleGetHandler))
+			apiV1Route.POST("/investor/deals/schedule/planned_transactions.json", bindApi(api.InvestorDeals.SchedulePlannedTransactionsCreateHandler))
 
 			// Investor Payments
 			apiV1Route.GET("/investor/payments/list.json", bindApi(api.InvestorDeals.PaymentListHandler))
@@ -495,25 +505,48 @@ func startWebServer(c *core.CliContext) error {
 			// Budgets
 			apiV1Route.GET("/budgets/list.json", bindApi(api.BudgetsAPI.BudgetListHandler))
 			apiV1Route.POST("/budgets/save.json", bindApi(api.BudgetsAPI.BudgetSaveHandler))
+			apiV1Route.POST("/budgets/copy.json", bindApi(api.BudgetsAPI.BudgetCopyHandler))
+			apiV1Route.POST("/budgets/spread.json", bindApi(api.BudgetsAPI.BudgetSpreadHandler))
+			apiV1Route.POST("/budgets/from_actuals.json", bindApi(api.BudgetsAPI.BudgetFromActualsHandler))
+			apiV1Route.GET("/budgets/versions/list.json", bindApi(api.BudgetsAPI.BudgetVersionListHandler))
 			apiV1Route.GET("/budgets/planfact.json", bindApi(api.BudgetsAPI.PlanFactHandler))
+			apiV1Route.GET("/budgets/planfact/months.json", bindApi(api.BudgetsAPI.PlanFactByMonthsHandler))
+			apiV1Route.GET("/budgets/planfact/cfos.json", bindApi(api.BudgetsAPI.PlanFactByCfosHandler))
 
 			// Obligations
 			apiV1Route.GET("/obligations/list.json", bindApi(api.ObligationsAPI.ObligationListHandler))
 			apiV1Route.POST("/obligations/add.json", bindApi(api.ObligationsAPI.ObligationCreateHandler))
 			apiV1Route.POST("/obligations/modify.json", bindApi(api.ObligationsAPI.ObligationModifyHandler))
 			apiV1Route.POST("/obligations/delete.json", bindApi(api.ObligationsAPI.ObligationDeleteHandler))
+			apiV1Route.POST("/obligations/allocate.json", bindApi(api.ObligationsAPI.ObligationAllocateHandler))
+			apiV1Route.GET("/obligations/payments/list.json", bindApi(api.ObligationsAPI.ObligationPaymentListHandler))
+			apiV1Route.POST("/obligations/payments/delete.json", bindApi(api.ObligationsAPI.ObligationPaymentDeleteHandler))
+			apiV1Route.GET("/obligations/status_histories/list.json", bindApi(api.ObligationsAPI.ObligationStatusHistoryListHandler))
 
 			// Tax Records
 			apiV1Route.GET("/tax-records/list.json", bindApi(api.TaxRecordsAPI.TaxRecordListHandler))
 			apiV1Route.POST("/tax-records/add.json", bindApi(api.TaxRecordsAPI.TaxRecordCreateHandler))
 			apiV1Route.POST("/tax-records/modify.json", bindApi(api.TaxRecordsAPI.TaxRecordModifyHandler))
 			apiV1Route.POST("/tax-records/delete.json", bindApi(api.TaxRecordsAPI.TaxRecordDeleteHandler))
+			apiV1Route.GET("/tax-records/calculate.json", bindApi(api.TaxRecordsAPI.TaxRecordCalculateHandler))
+			apiV1Route.POST("/tax-records/generate.json", bindApi(api.TaxRecordsAPI.TaxRecordGenerateHandler))
+			apiV1Route.GET("/tax-records/status_histories/list.json", bindApi(api.TaxRecordsAPI.TaxRecordStatusHistoryListHandler))
+
+			// Tax Regimes
+			apiV1Route.GET("/tax-regimes/list.json", bindApi(api.TaxRegimesAPI.TaxRegimeListHandler))
+			apiV1Route.POST("/tax-regimes/save.json", bindApi(api.TaxRegimesAPI.TaxRegimeSaveHandler))
+			apiV1Route.POST("/tax-regimes/delete.json", bindApi(api.TaxRegimesAPI.TaxRegimeDeleteHandler))
+
+			// Notifications
+			apiV1Route.GET("/notifications/preferences/get.json", bindApi(api.NotificationsAPI.NotificationPreferenceGetHandler))
+			apiV1Route.POST("/notifications/preferences/save.json", bindApi(api.NotificationsAPI.NotificationPreferenceSaveHandler))
 
 			// Reports
 			apiV1Route.GET("/reports/cashflow.json", bindApi(api.ReportsAPI.CashFlowHandler))
 			apiV1Route.GET("/reports/pnl.json", bindApi(api.ReportsAPI.PnLHandler))
 			apiV1Route.GET("/reports/balance.json", bindApi(api.ReportsAPI.BalanceHandler))
 			apiV1Route.GET("/reports/payment-calendar.json", bindApi(api.ReportsAPI.PaymentCalendarHandler))
+			apiV1Route.GET("/reports/aging.json", bindApi(api.ReportsAPI.AgingHandler))
 
 			// Transaction Templates
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
@@ -539,11 +572,25 @@ func startWebServer(c *core.CliContext) error {
 			if config.ReceiptImageRecognitionLLMConfig != nil && config.ReceiptImageRecognitionLLMConfig.LLMProvider != "" {
 				if config.TransactionFromAIImageRecognition {
 					apiV1Route.POST("/llm/transactions/recognize_receipt_image.json", bindApi(api.LargeLanguageModels.RecognizeReceiptImageHandler))
+					apiV1Route.POST("/llm/transactions/recognize_receipt_files.json", bindApi(api.LargeLanguageModels.RecognizeReceiptFilesHandler))
+				}
+			}
+
+			if config.TransactionImportCategorizationLLMConfig != nil && config.TransactionImportCategorizationLLMConfig.LLMProvider != "" {
+				if config.TransactionImportAICategorization {
+					apiV1Route.POST("/llm/transactions/suggest_import_categories.json", bindApi(api.LargeLanguageModels.SuggestImportTransactionCategoriesHandler))
+				}
+			}
+
+			if config.TransactionQueryLLMConfig != nil && config.TransactionQueryLLMConfig.LLMProvider != "" {
+				if config.TransactionNaturalLanguageQuery {
+					apiV1Route.POST("/llm/transactions/query.json", bindApi(api.LargeLanguageModels.QueryTransactionsHandler))
 				}
 			}
 
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/history.json", bindApi(api.ExchangeRates.HistoricalExchangeRateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler))
 
@@ -713,6 +760,19 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindDataFile(fn core.DataHandlerFunc, contentType string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, contentType, fileName, result)
+		}
+	}
+}
+
 func bindImage(fn core.ImageHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
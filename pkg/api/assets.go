@@ -8,6 +8,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // AssetsApi represents asset api
@@ -67,6 +68,40 @@ func (a *AssetsApi) AssetGetHandler(c *core.WebContext) (any, *errs.Error) {
 	return asset.ToAssetInfoResponse(), nil
 }
 
+// AssetDepreciationScheduleHandler returns the month-by-month depreciation schedule of one specific asset of current user
+func (a *AssetsApi) AssetDepreciationScheduleHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleReq models.AssetDepreciationScheduleRequest
+	err := c.ShouldBindQuery(&scheduleReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetDepreciationScheduleHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetDepreciationScheduleHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	asset, err := a.assets.GetAssetByAssetId(c, uid, scheduleReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetDepreciationScheduleHandler] failed to get asset \"id:%d\" for user \"uid:%d\", because %s", scheduleReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return &models.AssetDepreciationScheduleResponse{
+		Id:                 asset.AssetId,
+		DepreciationMethod: asset.DepreciationMethod,
+		PurchaseCost:       asset.PurchaseCost,
+		SalvageValue:       asset.SalvageValue,
+		Items:              a.assets.GetAssetDepreciationSchedule(asset, clientTimezone),
+	}, nil
+}
+
 // AssetCreateHandler saves a new asset by request parameters for current user
 func (a *AssetsApi) AssetCreateHandler(c *core.WebContext) (any, *errs.Error) {
 	var assetCreateReq models.AssetCreateRequest
@@ -86,6 +121,18 @@ func (a *AssetsApi) AssetCreateHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	if assetCreateReq.DepreciationMethod == models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION && assetCreateReq.InstalledCapacityWatts <= 0 {
+		return nil, errs.ErrAssetInstalledCapacityRequired
+	}
+
+	if (assetCreateReq.AccountId > 0) != (assetCreateReq.DepreciationCategoryId > 0) {
+		return nil, errs.ErrAssetDepreciationPostingIncomplete
+	}
+
+	if assetCreateReq.DepreciationMethod == 0 {
+		assetCreateReq.DepreciationMethod = models.ASSET_DEPRECIATION_METHOD_STRAIGHT_LINE
+	}
+
 	asset := &models.Asset{
 		Uid:                    uid,
 		Name:                   assetCreateReq.Name,
@@ -96,10 +143,14 @@ func (a *AssetsApi) AssetCreateHandler(c *core.WebContext) (any, *errs.Error) {
 		PurchaseCost:           assetCreateReq.PurchaseCost,
 		UsefulLifeMonths:       assetCreateReq.UsefulLifeMonths,
 		SalvageValue:           assetCreateReq.SalvageValue,
+		DepreciationMethod:     assetCreateReq.DepreciationMethod,
 		Status:                 assetCreateReq.Status,
 		CommissionDate:         assetCreateReq.CommissionDate,
 		DecommissionDate:       assetCreateReq.DecommissionDate,
 		InstalledCapacityWatts: assetCreateReq.InstalledCapacityWatts,
+		AccountId:              assetCreateReq.AccountId,
+		DepreciationCategoryId: assetCreateReq.DepreciationCategoryId,
+		TimezoneUtcOffset:      assetCreateReq.UtcOffset,
 		Comment:                assetCreateReq.Comment,
 		DisplayOrder:           maxOrderId + 1,
 	}
@@ -134,6 +185,18 @@ func (a *AssetsApi) AssetModifyHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	if assetModifyReq.DepreciationMethod == models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION && assetModifyReq.InstalledCapacityWatts <= 0 {
+		return nil, errs.ErrAssetInstalledCapacityRequired
+	}
+
+	if (assetModifyReq.AccountId > 0) != (assetModifyReq.DepreciationCategoryId > 0) {
+		return nil, errs.ErrAssetDepreciationPostingIncomplete
+	}
+
+	if assetModifyReq.DepreciationMethod == 0 {
+		assetModifyReq.DepreciationMethod = models.ASSET_DEPRECIATION_METHOD_STRAIGHT_LINE
+	}
+
 	newAsset := &models.Asset{
 		AssetId:                asset.AssetId,
 		Uid:                    uid,
@@ -145,10 +208,14 @@ func (a *AssetsApi) AssetModifyHandler(c *core.WebContext) (any, *errs.Error) {
 		PurchaseCost:           assetModifyReq.PurchaseCost,
 		UsefulLifeMonths:       assetModifyReq.UsefulLifeMonths,
 		SalvageValue:           assetModifyReq.SalvageValue,
+		DepreciationMethod:     assetModifyReq.DepreciationMethod,
 		Status:                 assetModifyReq.Status,
 		CommissionDate:         assetModifyReq.CommissionDate,
 		DecommissionDate:       assetModifyReq.DecommissionDate,
 		InstalledCapacityWatts: assetModifyReq.InstalledCapacityWatts,
+		AccountId:              assetModifyReq.AccountId,
+		DepreciationCategoryId: assetModifyReq.DepreciationCategoryId,
+		TimezoneUtcOffset:      assetModifyReq.UtcOffset,
 		Comment:                assetModifyReq.Comment,
 		Hidden:                 assetModifyReq.Hidden,
 		DisplayOrder:           asset.DisplayOrder,
@@ -164,10 +231,14 @@ func (a *AssetsApi) AssetModifyHandler(c *core.WebContext) (any, *errs.Error) {
 		newAsset.PurchaseCost == asset.PurchaseCost &&
 		newAsset.UsefulLifeMonths == asset.UsefulLifeMonths &&
 		newAsset.SalvageValue == asset.SalvageValue &&
+		newAsset.DepreciationMethod == asset.DepreciationMethod &&
 		newAsset.Status == asset.Status &&
 		newAsset.CommissionDate == asset.CommissionDate &&
 		newAsset.DecommissionDate == asset.DecommissionDate &&
 		newAsset.InstalledCapacityWatts == asset.InstalledCapacityWatts &&
+		newAsset.AccountId == asset.AccountId &&
+		newAsset.DepreciationCategoryId == asset.DepreciationCategoryId &&
+		newAsset.TimezoneUtcOffset == asset.TimezoneUtcOffset &&
 		newAsset.Comment == asset.Comment &&
 		newAsset.Hidden == asset.Hidden {
 		return nil, errs.ErrNothingWillBeUpdated
@@ -185,6 +256,139 @@ func (a *AssetsApi) AssetModifyHandler(c *core.WebContext) (any, *errs.Error) {
 	return newAsset.ToAssetInfoResponse(), nil
 }
 
+// AssetTransactionListHandler returns the purchase, depreciation and disposal transactions linked to one specific asset of current user
+func (a *AssetsApi) AssetTransactionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var assetTransactionListReq models.AssetTransactionListRequest
+	err := c.ShouldBindQuery(&assetTransactionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetTransactionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	assetTransactions, err := a.assets.GetAssetTransactionsByAssetId(c, uid, assetTransactionListReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetTransactionListHandler] failed to get transactions of asset \"id:%d\" for user \"uid:%d\", because %s", assetTransactionListReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	assetTransactionResps := make([]*models.AssetTransactionInfoResponse, len(assetTransactions))
+
+	for i := 0; i < len(assetTransactions); i++ {
+		assetTransactionResps[i] = assetTransactions[i].ToAssetTransactionInfoResponse()
+	}
+
+	return assetTransactionResps, nil
+}
+
+// AssetPurchaseTransactionLinkHandler links purchase transactions to an asset by request parameters for current user
+func (a *AssetsApi) AssetPurchaseTransactionLinkHandler(c *core.WebContext) (any, *errs.Error) {
+	var linkReq models.AssetPurchaseTransactionLinkRequest
+	err := c.ShouldBindJSON(&linkReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetPurchaseTransactionLinkHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(linkReq.TransactionIds)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetPurchaseTransactionLinkHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	asset, err := a.assets.LinkPurchaseTransactions(c, uid, linkReq.Id, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetPurchaseTransactionLinkHandler] failed to link purchase transactions to asset \"id:%d\" for user \"uid:%d\", because %s", linkReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[assets.AssetPurchaseTransactionLinkHandler] user \"uid:%d\" has linked %d purchase transactions to asset \"id:%d\"", uid, len(transactionIds), linkReq.Id)
+
+	return asset.ToAssetInfoResponse(), nil
+}
+
+// AssetPurchaseTransactionUnlinkHandler unlinks purchase transactions from an asset by request parameters for current user
+func (a *AssetsApi) AssetPurchaseTransactionUnlinkHandler(c *core.WebContext) (any, *errs.Error) {
+	var unlinkReq models.AssetPurchaseTransactionLinkRequest
+	err := c.ShouldBindJSON(&unlinkReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetPurchaseTransactionUnlinkHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(unlinkReq.TransactionIds)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetPurchaseTransactionUnlinkHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	asset, err := a.assets.UnlinkPurchaseTransactions(c, uid, unlinkReq.Id, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetPurchaseTransactionUnlinkHandler] failed to unlink purchase transactions from asset \"id:%d\" for user \"uid:%d\", because %s", unlinkReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[assets.AssetPurchaseTransactionUnlinkHandler] user \"uid:%d\" has unlinked %d purchase transactions from asset \"id:%d\"", uid, len(transactionIds), unlinkReq.Id)
+
+	return asset.ToAssetInfoResponse(), nil
+}
+
+// AssetProductionOutputSaveHandler saves the production output of units-of-production asset in a calendar month by request parameters for current user
+func (a *AssetsApi) AssetProductionOutputSaveHandler(c *core.WebContext) (any, *errs.Error) {
+	var outputSaveReq models.AssetProductionOutputSaveRequest
+	err := c.ShouldBindJSON(&outputSaveReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetProductionOutputSaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	asset, err := a.assets.SaveAssetProductionOutput(c, uid, &outputSaveReq)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetProductionOutputSaveHandler] failed to save production output of %04d-%02d for asset \"id:%d\" for user \"uid:%d\", because %s", outputSaveReq.Year, outputSaveReq.Month, outputSaveReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[assets.AssetProductionOutputSaveHandler] user \"uid:%d\" has saved production output %d of %04d-%02d for asset \"id:%d\"", uid, outputSaveReq.Output, outputSaveReq.Year, outputSaveReq.Month, asset.AssetId)
+
+	return asset.ToAssetInfoResponse(), nil
+}
+
+// AssetDisposeHandler sells or decommissions an asset by request parameters for current user
+func (a *AssetsApi) AssetDisposeHandler(c *core.WebContext) (any, *errs.Error) {
+	var assetDisposeReq models.AssetDisposeRequest
+	err := c.ShouldBindJSON(&assetDisposeReq)
+
+	if err != nil {
+		log.Warnf(c, "[assets.AssetDisposeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	asset, err := a.assets.DisposeAsset(c, uid, &assetDisposeReq)
+
+	if err != nil {
+		log.Errorf(c, "[assets.AssetDisposeHandler] failed to dispose asset \"id:%d\" for user \"uid:%d\", because %s", assetDisposeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[assets.AssetDisposeHandler] user \"uid:%d\" has disposed asset \"id:%d\" with proceeds %d and book value %d", uid, asset.AssetId, asset.DisposalProceeds, asset.DisposalBookValue)
+
+	return asset.ToAssetInfoResponse(), nil
+}
+
 // AssetHideHandler hides an asset by request parameters for current user
 func (a *AssetsApi) AssetHideHandler(c *core.WebContext) (any, *errs.Error) {
 	var assetHideReq models.AssetHideRequest
@@ -14,11 +14,12 @@ import (
 type BudgetsApi struct {
 	budgets    services.BudgetProvider
 	categories *services.TransactionCategoryService
+	users      *services.UserService
 }
 
 // NewBudgetsApi creates a new BudgetsApi instance
 func NewBudgetsApi(b services.BudgetProvider, c *services.TransactionCategoryService) *BudgetsApi {
-	return &BudgetsApi{budgets: b, categories: c}
+	return &BudgetsApi{budgets: b, categories: c, users: services.Users}
 }
 
 // Initialize a budgets api singleton instance
@@ -37,7 +38,7 @@ func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	uid := c.GetCurrentUid()
-	budgets, err := a.budgets.GetBudgetsByYearMonth(c, uid, budgetListReq.Year, budgetListReq.Month, budgetListReq.CfoId)
+	budgets, err := a.budgets.GetBudgetsByYearMonth(c, uid, budgetListReq.Year, budgetListReq.Month, budgetListReq.CfoId, budgetListReq.Version)
 
 	if err != nil {
 		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
@@ -65,7 +66,7 @@ func (a *BudgetsApi) BudgetSaveHandler(c *core.WebContext) (any, *errs.Error) {
 
 	uid := c.GetCurrentUid()
 
-	err = a.budgets.SaveBudgets(c, uid, budgetSaveReq.Year, budgetSaveReq.Month, budgetSaveReq.CfoId, budgetSaveReq.Budgets)
+	err = a.budgets.SaveBudgets(c, uid, budgetSaveReq.Year, budgetSaveReq.Month, budgetSaveReq.CfoId, budgetSaveReq.Version, budgetSaveReq.Budgets)
 
 	if err != nil {
 		log.Errorf(c, "[budgets.BudgetSaveHandler] failed to save budgets for user \"uid:%d\", because %s", uid, err.Error())
@@ -77,6 +78,102 @@ func (a *BudgetsApi) BudgetSaveHandler(c *core.WebContext) (any, *errs.Error) {
 	return true, nil
 }
 
+// BudgetCopyHandler copies the budget of one month to a range of months
+func (a *BudgetsApi) BudgetCopyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCopyReq models.BudgetCopyRequest
+	err := c.ShouldBindJSON(&budgetCopyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCopyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	count, err := a.budgets.CopyBudgets(c, uid, &budgetCopyReq)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCopyHandler] failed to copy budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCopyHandler] user \"uid:%d\" has copied budgets of %d-%02d to %d-%02d..%d-%02d (%d lines)", uid, budgetCopyReq.SourceYear, budgetCopyReq.SourceMonth, budgetCopyReq.TargetStartYear, budgetCopyReq.TargetStartMonth, budgetCopyReq.TargetEndYear, budgetCopyReq.TargetEndMonth, count)
+
+	return count, nil
+}
+
+// BudgetSpreadHandler spreads annual amounts over the months of a year
+func (a *BudgetsApi) BudgetSpreadHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetSpreadReq models.BudgetSpreadRequest
+	err := c.ShouldBindJSON(&budgetSpreadReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetSpreadHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	count, err := a.budgets.SpreadAnnualBudgets(c, uid, &budgetSpreadReq)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetSpreadHandler] failed to spread budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetSpreadHandler] user \"uid:%d\" has spread annual budgets for %d (%d lines)", uid, budgetSpreadReq.Year, count)
+
+	return count, nil
+}
+
+// BudgetFromActualsHandler derives the budget of a month from actual amounts of previous months
+func (a *BudgetsApi) BudgetFromActualsHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetFromActualsReq models.BudgetFromActualsRequest
+	err := c.ShouldBindJSON(&budgetFromActualsReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetFromActualsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budgets, err := a.budgets.CreateBudgetsFromActuals(c, uid, &budgetFromActualsReq)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetFromActualsHandler] failed to create budgets from actuals for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetFromActualsHandler] user \"uid:%d\" has created budgets for %d-%02d from actuals of %d months", uid, budgetFromActualsReq.Year, budgetFromActualsReq.Month, budgetFromActualsReq.Months)
+
+	budgetResps := make([]*models.BudgetInfoResponse, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	return budgetResps, nil
+}
+
+// BudgetVersionListHandler returns all budget versions
+func (a *BudgetsApi) BudgetVersionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetVersionListReq models.BudgetVersionListRequest
+	err := c.ShouldBindQuery(&budgetVersionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetVersionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	versions, err := a.budgets.GetBudgetVersions(c, uid, budgetVersionListReq.Year, budgetVersionListReq.CfoId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetVersionListHandler] failed to get budget versions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return versions, nil
+}
+
 // PlanFactHandler returns plan-fact analysis
 func (a *BudgetsApi) PlanFactHandler(c *core.WebContext) (any, *errs.Error) {
 	var planFactReq models.PlanFactRequest
@@ -87,14 +184,21 @@ func (a *BudgetsApi) PlanFactHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.PlanFactHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
 	uid := c.GetCurrentUid()
 
-	// Calculate time range for the month (UTC)
-	startTime := time.Date(int(planFactReq.Year), time.Month(planFactReq.Month), 1, 0, 0, 0, 0, time.UTC).Unix()
-	endTime := time.Date(int(planFactReq.Year), time.Month(planFactReq.Month)+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	// Calculate time range for the month in client timezone
+	startTime := time.Date(int(planFactReq.Year), time.Month(planFactReq.Month), 1, 0, 0, 0, 0, clientTimezone).UnixMilli()
+	endTime := time.Date(int(planFactReq.Year), time.Month(planFactReq.Month)+1, 1, 0, 0, 0, 0, clientTimezone).UnixMilli()
 
 	// Get budgets
-	budgets, err := a.budgets.GetBudgetsByYearMonth(c, uid, planFactReq.Year, planFactReq.Month, planFactReq.CfoId)
+	budgets, err := a.budgets.GetBudgetsByYearMonth(c, uid, planFactReq.Year, planFactReq.Month, planFactReq.CfoId, planFactReq.Version)
 
 	if err != nil {
 		log.Errorf(c, "[budgets.PlanFactHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
@@ -170,9 +274,60 @@ func (a *BudgetsApi) PlanFactHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	return &models.PlanFactResponse{
-		Year:  planFactReq.Year,
-		Month: planFactReq.Month,
-		CfoId: planFactReq.CfoId,
-		Lines: lines,
+		Year:    planFactReq.Year,
+		Month:   planFactReq.Month,
+		CfoId:   planFactReq.CfoId,
+		Version: planFactReq.Version,
+		Lines:   lines,
 	}, nil
 }
+
+// PlanFactByMonthsHandler returns plan-fact analysis of a period with month columns
+func (a *BudgetsApi) PlanFactByMonthsHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.getPlanFactTable(c, false)
+}
+
+// PlanFactByCfosHandler returns plan-fact analysis of a period as a CFO × category matrix
+func (a *BudgetsApi) PlanFactByCfosHandler(c *core.WebContext) (any, *errs.Error) {
+	return a.getPlanFactTable(c, true)
+}
+
+func (a *BudgetsApi) getPlanFactTable(c *core.WebContext, byCfos bool) (*models.PlanFactTableResponse, *errs.Error) {
+	var planFactRangeReq models.PlanFactRangeRequest
+	err := c.ShouldBindQuery(&planFactRangeReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.getPlanFactTable] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.getPlanFactTable] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.getPlanFactTable] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var result *models.PlanFactTableResponse
+
+	if byCfos {
+		result, err = a.budgets.GetPlanFactByCfos(c, uid, &planFactRangeReq, user.FiscalYearStart, clientTimezone)
+	} else {
+		result, err = a.budgets.GetPlanFactByMonths(c, uid, &planFactRangeReq, user.FiscalYearStart, clientTimezone)
+	}
+
+	if err != nil {
+		log.Errorf(c, "[budgets.getPlanFactTable] failed to get plan-fact analysis for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
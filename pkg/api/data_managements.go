@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -33,6 +35,8 @@ type DataManagementsApi struct {
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
 	insightsExploreres      *services.InsightsExplorerService
 	counterparties          *services.CounterpartyService
+	cfos                    *services.CFOService
+	transactionSplits       *services.TransactionSplitService
 }
 
 // Initialize a data management api singleton instance
@@ -53,6 +57,8 @@ var (
 		userCustomExchangeRates: services.UserCustomExchangeRates,
 		insightsExploreres:      services.InsightsExplorers,
 		counterparties:          services.Counterparties,
+		cfos:                    services.CFOs,
+		transactionSplits:       services.TransactionSplits,
 	}
 )
 
@@ -66,6 +72,31 @@ func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebCont
 	return a.getExportedFileContent(c, "tsv")
 }
 
+// ExportDataToOFXHandler returns exported data in open financial exchange (ofx) format
+func (a *DataManagementsApi) ExportDataToOFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ofx")
+}
+
+// ExportDataToQIFHandler returns exported data in quicken interchange format (qif)
+func (a *DataManagementsApi) ExportDataToQIFHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qif")
+}
+
+// ExportDataToBeancountHandler returns exported data in beancount format
+func (a *DataManagementsApi) ExportDataToBeancountHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "beancount")
+}
+
+// ExportDataToLedgerHandler returns exported data in ledger-cli format
+func (a *DataManagementsApi) ExportDataToLedgerHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ledger")
+}
+
+// ExportDataToXlsxHandler returns exported data in xlsx format
+func (a *DataManagementsApi) ExportDataToXlsxHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "xlsx")
+}
+
 // DataStatisticsHandler returns user data statistics
 func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
@@ -442,7 +473,7 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, &models.TransactionQueryParams{
+	queryParams := &models.TransactionQueryParams{
 		Uid:                uid,
 		MaxTransactionTime: maxTransactionTime,
 		MinTransactionTime: minTransactionTime,
@@ -454,13 +485,26 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		AmountFilter:       exportTransactionDataReq.AmountFilter,
 		Keyword:            exportTransactionDataReq.Keyword,
 		NoDuplicated:       true,
-	}, pageCountForDataExport)
+	}
+
+	err = Transactions.setTransactionAdvancedFilterQueryParams(c, uid, &exportTransactionDataReq.TransactionAdvancedFilterRequest, queryParams)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.getExportedFileContent] parse transaction advanced filters error, because %s", err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, queryParams, pageCountForDataExport)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
+	if fileType != "csv" && fileType != "tsv" {
+		return a.getExportedFileContentByExporter(c, user, clientTimezone, fileType, allTransactions, accountMap, categoryMap, tagMap, tagIndexes, counterpartyMap)
+	}
+
 	// Build custom export in user's Excel-compatible format
 	// Columns: Дата | Сумма | Счет | Валюта | Контрагент | ИНН контрагент | Статья | Род. статья | Описание | tag_group_1 | tag_group_2 | tag_group_3 ...
 
@@ -634,6 +678,60 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 	return result, fileName, nil
 }
 
+func (a *DataManagementsApi) getExportedFileContentByExporter(c *core.WebContext, user *models.User, clientTimezone *time.Location, fileType string, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, tagIndexes map[int64][]int64, counterpartyMap map[int64]*models.Counterparty) ([]byte, string, *errs.Error) {
+	uid := user.Uid
+	dataExporter := converters.GetTransactionDataExporter(fileType)
+
+	if dataExporter == nil {
+		return nil, "", errs.ErrNotImplemented
+	}
+
+	cfos, err := a.cfos.GetAllCFOsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContentByExporter] failed to get cfos for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	cfoMap := make(map[int64]*models.CFO, len(cfos))
+
+	for i := 0; i < len(cfos); i++ {
+		cfoMap[cfos[i].CfoId] = cfos[i]
+	}
+
+	transactionIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds = append(transactionIds, transactions[i].TransactionId)
+	}
+
+	allSplits, err := a.transactionSplits.GetSplitsByTransactionIds(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContentByExporter] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	var result []byte
+
+	if relatedDataExporter, ok := dataExporter.(converter.TransactionDataRelatedDataExporter); ok {
+		result, err = relatedDataExporter.ToExportedContentWithRelatedData(c, uid, transactions, accountMap, categoryMap, tagMap, tagIndexes, &converter.TransactionDataExportRelatedData{
+			CounterpartyMap: counterpartyMap,
+			CfoMap:          cfoMap,
+			AllSplits:       allSplits,
+		})
+	} else {
+		result, err = dataExporter.ToExportedContent(c, uid, transactions, accountMap, categoryMap, tagMap, tagIndexes)
+	}
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContentByExporter] failed to export %s data for user \"uid:%d\", because %s", fileType, uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, a.getFileName(user, clientTimezone, fileType), nil
+}
+
 func (a *DataManagementsApi) getFileName(user *models.User, clientTimezone *time.Location, fileExtension string) string {
 	currentTime := utils.FormatUnixTimeToLongDateTimeWithoutSecond(time.Now().Unix(), clientTimezone)
 	currentTime = strings.Replace(currentTime, "-", "_", -1)
@@ -15,6 +15,7 @@ type ExchangeRatesApi struct {
 	ApiUsingConfig
 	users                   *services.UserService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	exchangeRateHistories   *services.ExchangeRateHistoryService
 }
 
 // Initialize a exchange rate api singleton instance
@@ -25,6 +26,7 @@ var (
 		},
 		users:                   services.Users,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		exchangeRateHistories:   services.ExchangeRateHistories,
 	}
 )
 
@@ -39,6 +41,54 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// HistoricalExchangeRateHandler returns the exchange rates in effect on the specified date from the exchange rates history
+func (a *ExchangeRatesApi) HistoricalExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
+	var historicalExchangeRateReq models.HistoricalExchangeRateRequest
+	err := c.ShouldBindQuery(&historicalExchangeRateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if _, err = models.ParseExchangeRateHistoryDate(historicalExchangeRateReq.Date); err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] date \"%s\" is invalid", historicalExchangeRateReq.Date)
+		return nil, errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+	var histories models.ExchangeRateHistorySlice
+
+	if historicalExchangeRateReq.Currency != "" {
+		history, err := a.exchangeRateHistories.GetExchangeRate(c, dataSource, historicalExchangeRateReq.Currency, historicalExchangeRateReq.Date)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		histories = models.ExchangeRateHistorySlice{history}
+	} else {
+		histories, err = a.exchangeRateHistories.GetAllExchangeRatesByDate(c, dataSource, historicalExchangeRateReq.Date)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	exchangeRates := make([]*models.HistoricalExchangeRate, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		exchangeRates[i] = histories[i].ToHistoricalExchangeRate()
+	}
+
+	return &models.HistoricalExchangeRateResponse{
+		DataSource:    dataSource,
+		Date:          historicalExchangeRateReq.Date,
+		BaseCurrency:  histories[0].BaseCurrency,
+		ExchangeRates: exchangeRates,
+	}, nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
@@ -12,18 +12,19 @@ import (
 
 // InvestorDealsApi represents investor deals api
 type InvestorDealsApi struct {
-	deals    services.InvestorDealProvider
-	payments services.InvestorPaymentProvider
+	deals     services.InvestorDealProvider
+	payments  services.InvestorPaymentProvider
+	schedules services.InvestorScheduleProvider
 }
 
 // NewInvestorDealsApi creates a new InvestorDealsApi instance
-func NewInvestorDealsApi(d services.InvestorDealProvider, p services.InvestorPaymentProvider) *InvestorDealsApi {
-	return &InvestorDealsApi{deals: d, payments: p}
+func NewInvestorDealsApi(d services.InvestorDealProvider, p services.InvestorPaymentProvider, s services.InvestorScheduleProvider) *InvestorDealsApi {
+	return &InvestorDealsApi{deals: d, payments: p, schedules: s}
 }
 
 // Initialize an investor deals api singleton instance
 var (
-	InvestorDeals = NewInvestorDealsApi(services.InvestorDeals, services.InvestorPayments)
+	InvestorDeals = NewInvestorDealsApi(services.InvestorDeals, services.InvestorPayments, services.InvestorSchedules)
 )
 
 // DealListHandler returns investor deal list of current user
@@ -88,6 +89,7 @@ func (a *InvestorDealsApi) DealCreateHandler(c *core.WebContext) (any, *errs.Err
 		InvestmentAmount:   dealCreateReq.InvestmentAmount,
 		Currency:           dealCreateReq.Currency,
 		DealType:           dealCreateReq.DealType,
+		RepaymentMethod:    dealCreateReq.RepaymentMethod,
 		AnnualRate:         dealCreateReq.AnnualRate,
 		ProfitSharePct:     dealCreateReq.ProfitSharePct,
 		FixedPayment:       dealCreateReq.FixedPayment,
@@ -136,6 +138,7 @@ func (a *InvestorDealsApi) DealModifyHandler(c *core.WebContext) (any, *errs.Err
 		InvestmentAmount:   dealModifyReq.InvestmentAmount,
 		Currency:           dealModifyReq.Currency,
 		DealType:           dealModifyReq.DealType,
+		RepaymentMethod:    dealModifyReq.RepaymentMethod,
 		AnnualRate:         dealModifyReq.AnnualRate,
 		ProfitSharePct:     dealModifyReq.ProfitSharePct,
 		FixedPayment:       dealModifyReq.FixedPayment,
@@ -151,6 +154,7 @@ func (a *InvestorDealsApi) DealModifyHandler(c *core.WebContext) (any, *errs.Err
 		newDeal.InvestmentAmount == existingDeal.InvestmentAmount &&
 		newDeal.Currency == existingDeal.Currency &&
 		newDeal.DealType == existingDeal.DealType &&
+		newDeal.RepaymentMethod == existingDeal.RepaymentMethod &&
 		newDeal.AnnualRate == existingDeal.AnnualRate &&
 		newDeal.ProfitSharePct == existingDeal.ProfitSharePct &&
 		newDeal.FixedPayment == existingDeal.FixedPayment &&
@@ -337,3 +341,47 @@ func (a *InvestorDealsApi) PaymentDeleteHandler(c *core.WebContext) (any, *errs.
 	log.Infof(c, "[investor_deals.PaymentDeleteHandler] user \"uid:%d\" has deleted payment \"id:%d\"", uid, paymentDeleteReq.Id)
 	return true, nil
 }
+
+// ScheduleGetHandler returns the repayment schedule of an investor deal compared with its recorded payments
+func (a *InvestorDealsApi) ScheduleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleGetReq models.InvestorScheduleGetRequest
+	err := c.ShouldBindQuery(&scheduleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[investor_deals.ScheduleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	schedule, err := a.schedules.GetSchedule(c, uid, scheduleGetReq.DealId)
+
+	if err != nil {
+		log.Errorf(c, "[investor_deals.ScheduleGetHandler] failed to get schedule of deal \"id:%d\" for user \"uid:%d\", because %s", scheduleGetReq.DealId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return schedule, nil
+}
+
+// SchedulePlannedTransactionsCreateHandler creates planned transactions for future instalments of an investor deal for current user
+func (a *InvestorDealsApi) SchedulePlannedTransactionsCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var createReq models.InvestorSchedulePlannedTransactionsCreateRequest
+	err := c.ShouldBindJSON(&createReq)
+
+	if err != nil {
+		log.Warnf(c, "[investor_deals.SchedulePlannedTransactionsCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	count, err := a.schedules.CreatePlannedTransactions(c, uid, &createReq)
+
+	if err != nil {
+		log.Errorf(c, "[investor_deals.SchedulePlannedTransactionsCreateHandler] failed to create planned transactions of deal \"id:%d\" for user \"uid:%d\", because %s", createReq.DealId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investor_deals.SchedulePlannedTransactionsCreateHandler] user \"uid:%d\" has created %d planned transactions of deal \"id:%d\" successfully", uid, count, createReq.DealId)
+
+	return &models.InvestorSchedulePlannedTransactionsCreateResponse{Count: count}, nil
+}
@@ -0,0 +1,418 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/llm"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/data"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var transactionNaturalLanguageQueryTagFilterTypes = map[string]models.TransactionTagFilterType{
+	"has_any":     models.TRANSACTION_TAG_FILTER_HAS_ANY,
+	"has_all":     models.TRANSACTION_TAG_FILTER_HAS_ALL,
+	"not_has_any": models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY,
+	"not_has_all": models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL,
+}
+
+// QueryTransactionsHandler returns the transactions matching the filter which is converted from natural language query
+func (a *LargeLanguageModelsApi) QueryTransactionsHandler(c *core.WebContext) (any, *errs.Error) {
+	if a.CurrentConfig().TransactionQueryLLMConfig == nil || a.CurrentConfig().TransactionQueryLLMConfig.LLMProvider == "" || !a.CurrentConfig().TransactionNaturalLanguageQuery {
+		return nil, errs.ErrLargeLanguageModelProviderNotEnabled
+	}
+
+	var queryReq models.TransactionNaturalLanguageQueryRequest
+	err := c.ShouldBindJSON(&queryReq)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.QueryTransactionsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.QueryTransactionsHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[large_language_models.QueryTransactionsHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	counterparties, err := a.counterparties.GetAllCounterpartiesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get counterparties for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountNames := make([]string, 0, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		if accounts[i].Hidden {
+			continue
+		}
+
+		accountNames = append(accountNames, accounts[i].Name)
+	}
+
+	tagNames := make([]string, 0, len(tags))
+
+	for i := 0; i < len(tags); i++ {
+		if tags[i].Hidden {
+			continue
+		}
+
+		tagNames = append(tagNames, tags[i].Name)
+	}
+
+	counterpartyNames := make([]string, 0, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		if counterparties[i].Hidden {
+			continue
+		}
+
+		counterpartyNames = append(counterpartyNames, counterparties[i].Name)
+	}
+
+	systemPrompt, err := templates.GetTemplate(templates.SYSTEM_PROMPT_TRANSACTION_QUERY)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get system prompt template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	systemPromptParams := map[string]any{
+		"CurrentDateTime":          utils.FormatUnixTimeToLongDateTime(time.Now().Unix(), clientTimezone),
+		"AllExpenseCategoryNames":  strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_EXPENSE), "\n"),
+		"AllIncomeCategoryNames":   strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_INCOME), "\n"),
+		"AllTransferCategoryNames": strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_TRANSFER), "\n"),
+		"AllAccountNames":          strings.Join(accountNames, "\n"),
+		"AllTagNames":              strings.Join(tagNames, "\n"),
+		"AllCounterpartyNames":     strings.Join(counterpartyNames, "\n"),
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = systemPrompt.Execute(&bodyBuffer, systemPromptParams)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get final system prompt from template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	llmRequest := &data.LargeLanguageModelRequest{
+		Stream:         false,
+		SystemPrompt:   strings.ReplaceAll(bodyBuffer.String(), "\r\n", "\n"),
+		UserPrompt:     []byte(queryReq.Query),
+		UserPromptType: data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_TEXT,
+	}
+
+	llmResponse, err := llm.Container.GetJsonResponseByTransactionQueryModel(c, uid, a.CurrentConfig(), llmRequest)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get llm response user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var result *models.TransactionNaturalLanguageQueryResult
+
+	if llmResponse != nil && len(llmResponse.Content) > 0 {
+		if err := json.Unmarshal([]byte(llmResponse.Content), &result); err != nil {
+			log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to unmarshal transaction query result from llm response \"%s\" for user \"uid:%d\", because %s", llmResponse.Content, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	filter := a.parseTransactionNaturalLanguageQueryResult(c, clientTimezone, result, accounts, categories, tags, counterparties)
+	queryParams, err := a.getTransactionNaturalLanguageQueryParams(c, uid, filter)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	queryParams.Count = queryReq.Count
+	queryParams.NeedOneMoreItem = true
+	queryParams.NoDuplicated = true
+
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, queryParams)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var nextTimeSequenceId *int64
+
+	if len(transactions) > int(queryReq.Count) {
+		nextTimeSequenceId = &transactions[queryReq.Count].TransactionTime
+		transactions = transactions[:queryReq.Count]
+	}
+
+	transactionResult, err := Transactions.getTransactionResponseListResult(c, user, transactions, clientTimezone, false, true, true, true)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.QueryTransactionsHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return &models.TransactionNaturalLanguageQueryResponse{
+		Filter:             filter,
+		Items:              transactionResult,
+		NextTimeSequenceId: nextTimeSequenceId,
+	}, nil
+}
+
+// parseTransactionNaturalLanguageQueryResult converts the names in llm result to the entities of user, the names which do not match any entity are returned in unmatched names
+func (a *LargeLanguageModelsApi) parseTransactionNaturalLanguageQueryResult(c *core.WebContext, clientTimezone *time.Location, queryResult *models.TransactionNaturalLanguageQueryResult, accounts []*models.Account, categories []*models.TransactionCategory, tags []*models.TransactionTag, counterparties []*models.Counterparty) *models.TransactionNaturalLanguageQueryFilter {
+	filter := &models.TransactionNaturalLanguageQueryFilter{}
+
+	if queryResult == nil {
+		return filter
+	}
+
+	if queryResult.Type == "income" {
+		filter.Type = models.TRANSACTION_TYPE_INCOME
+	} else if queryResult.Type == "expense" {
+		filter.Type = models.TRANSACTION_TYPE_EXPENSE
+	} else if queryResult.Type == "transfer" {
+		filter.Type = models.TRANSACTION_TYPE_TRANSFER
+	} else if len(queryResult.Type) > 0 {
+		log.Warnf(c, "[large_language_models.parseTransactionNaturalLanguageQueryResult] transaction type \"%s\" is invalid", queryResult.Type)
+	}
+
+	if len(queryResult.StartTime) > 0 {
+		startTime, err := utils.ParseFromLongDateTimeInTimeZone(a.getLongDateTime(queryResult.StartTime), clientTimezone)
+
+		if err != nil {
+			log.Warnf(c, "[large_language_models.parseTransactionNaturalLanguageQueryResult] start time \"%s\" is invalid", queryResult.StartTime)
+		} else {
+			filter.MinTime = utils.GetMinTransactionTimeFromUnixTime(startTime.Unix())
+		}
+	}
+
+	if len(queryResult.EndTime) > 0 {
+		endTime := queryResult.EndTime
+
+		if utils.IsValidLongDateFormat(endTime) {
+			endTime = endTime + " 23:59:59"
+		}
+
+		maxTime, err := utils.ParseFromLongDateTimeInTimeZone(a.getLongDateTime(endTime), clientTimezone)
+
+		if err != nil {
+			log.Warnf(c, "[large_language_models.parseTransactionNaturalLanguageQueryResult] end time \"%s\" is invalid", queryResult.EndTime)
+		} else {
+			filter.MaxTime = utils.GetMaxTransactionTimeFromUnixTime(maxTime.Unix())
+		}
+	}
+
+	for i := 0; i < len(queryResult.CategoryNames); i++ {
+		categoryIds := a.getVisibleCategoryAndSubCategoryIdsByName(categories, queryResult.CategoryNames[i], filter.Type)
+
+		if len(categoryIds) < 1 {
+			filter.UnmatchedNames = append(filter.UnmatchedNames, queryResult.CategoryNames[i])
+			continue
+		}
+
+		for j := 0; j < len(categoryIds); j++ {
+			filter.CategoryIds = append(filter.CategoryIds, utils.Int64ToString(categoryIds[j]))
+		}
+	}
+
+	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
+
+	for i := 0; i < len(queryResult.AccountNames); i++ {
+		account, exists := accountMap[queryResult.AccountNames[i]]
+
+		if !exists {
+			filter.UnmatchedNames = append(filter.UnmatchedNames, queryResult.AccountNames[i])
+			continue
+		}
+
+		filter.AccountIds = append(filter.AccountIds, utils.Int64ToString(account.AccountId))
+	}
+
+	tagMap := a.transactionTags.GetVisibleTagNameMapByList(tags)
+	tagIds := make([]string, 0, len(queryResult.TagNames))
+
+	for i := 0; i < len(queryResult.TagNames); i++ {
+		tag, exists := tagMap[queryResult.TagNames[i]]
+
+		if !exists {
+			filter.UnmatchedNames = append(filter.UnmatchedNames, queryResult.TagNames[i])
+			continue
+		}
+
+		tagIds = append(tagIds, utils.Int64ToString(tag.TagId))
+	}
+
+	if len(tagIds) > 0 {
+		tagFilterType, exists := transactionNaturalLanguageQueryTagFilterTypes[queryResult.TagFilterType]
+
+		if !exists {
+			tagFilterType = models.TRANSACTION_TAG_FILTER_HAS_ANY
+		}
+
+		filter.TagFilter = utils.IntToString(int(tagFilterType)) + ":" + strings.Join(tagIds, ",")
+	}
+
+	if queryResult.AmountFilter != nil {
+		filter.AmountFilter = a.getTransactionNaturalLanguageQueryAmountFilter(c, queryResult.AmountFilter)
+	}
+
+	filter.Keyword = strings.TrimSpace(queryResult.Keyword)
+
+	if len(queryResult.CounterpartyName) > 0 {
+		for i := 0; i < len(counterparties); i++ {
+			if !counterparties[i].Hidden && counterparties[i].Name == queryResult.CounterpartyName {
+				filter.CounterpartyId = counterparties[i].CounterpartyId
+				break
+			}
+		}
+
+		if filter.CounterpartyId == 0 {
+			filter.UnmatchedNames = append(filter.UnmatchedNames, queryResult.CounterpartyName)
+		}
+	}
+
+	return filter
+}
+
+// getTransactionNaturalLanguageQueryParams returns the transaction query parameters according to the parsed filter
+func (a *LargeLanguageModelsApi) getTransactionNaturalLanguageQueryParams(c *core.WebContext, uid int64, filter *models.TransactionNaturalLanguageQueryFilter) (*models.TransactionQueryParams, error) {
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, strings.Join(filter.AccountIds, ","), uid)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryParams] get account error, because %s", err.Error())
+		return nil, err
+	}
+
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, strings.Join(filter.CategoryIds, ","), uid)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryParams] get transaction category error, because %s", err.Error())
+		return nil, err
+	}
+
+	tagFilters, err := models.ParseTransactionTagFilter(filter.TagFilter)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryParams] parse transaction tag filters error, because %s", err.Error())
+		return nil, err
+	}
+
+	return &models.TransactionQueryParams{
+		Uid:                uid,
+		MaxTransactionTime: filter.MaxTime,
+		MinTransactionTime: filter.MinTime,
+		TransactionType:    filter.Type,
+		CategoryIds:        allCategoryIds,
+		AccountIds:         allAccountIds,
+		TagFilters:         tagFilters,
+		AmountFilter:       filter.AmountFilter,
+		Keyword:            filter.Keyword,
+		CounterpartyId:     filter.CounterpartyId,
+	}, nil
+}
+
+// getVisibleCategoryAndSubCategoryIdsByName returns the ids of visible categories with the specified name and all their visible sub-categories
+func (a *LargeLanguageModelsApi) getVisibleCategoryAndSubCategoryIdsByName(categories []*models.TransactionCategory, categoryName string, transactionType models.TransactionType) []int64 {
+	categoryType := models.TransactionCategoryType(0)
+
+	if transactionType == models.TRANSACTION_TYPE_INCOME {
+		categoryType = models.CATEGORY_TYPE_INCOME
+	} else if transactionType == models.TRANSACTION_TYPE_EXPENSE {
+		categoryType = models.CATEGORY_TYPE_EXPENSE
+	} else if transactionType == models.TRANSACTION_TYPE_TRANSFER {
+		categoryType = models.CATEGORY_TYPE_TRANSFER
+	}
+
+	categoryIds := make([]int64, 0)
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden || category.Name != categoryName || (categoryType > 0 && category.Type != categoryType) {
+			continue
+		}
+
+		categoryIds = append(categoryIds, category.CategoryId)
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		for j := 0; j < len(categories); j++ {
+			if !categories[j].Hidden && categories[j].ParentCategoryId == category.CategoryId {
+				categoryIds = append(categoryIds, categories[j].CategoryId)
+			}
+		}
+	}
+
+	return utils.ToUniqueInt64Slice(categoryIds)
+}
+
+// getTransactionNaturalLanguageQueryAmountFilter returns the amount filter in the format of transaction list request, or empty string if the amount filter is invalid
+func (a *LargeLanguageModelsApi) getTransactionNaturalLanguageQueryAmountFilter(c *core.WebContext, amountFilter *models.TransactionNaturalLanguageQueryAmountFilter) string {
+	amount, err := utils.ParseAmount(amountFilter.Amount)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryAmountFilter] amount \"%s\" is invalid", amountFilter.Amount)
+		return ""
+	}
+
+	switch amountFilter.Operator {
+	case "gt", "lt", "eq", "ne":
+		return amountFilter.Operator + ":" + utils.Int64ToString(amount)
+	case "bt", "nb":
+		amount2, err := utils.ParseAmount(amountFilter.Amount2)
+
+		if err != nil || amount2 < amount {
+			log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryAmountFilter] upper bound amount \"%s\" is invalid", amountFilter.Amount2)
+			return ""
+		}
+
+		return amountFilter.Operator + ":" + utils.Int64ToString(amount) + ":" + utils.Int64ToString(amount2)
+	default:
+		log.Warnf(c, "[large_language_models.getTransactionNaturalLanguageQueryAmountFilter] amount operator \"%s\" is invalid", amountFilter.Operator)
+		return ""
+	}
+}
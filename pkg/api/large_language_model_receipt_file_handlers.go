@@ -0,0 +1,444 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/llm"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/data"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maximumAIRecognitionFileCount = 10
+
+// RecognizeReceiptFilesHandler returns the proposed transactions recognized from the uploaded receipt images or invoice documents
+func (a *LargeLanguageModelsApi) RecognizeReceiptFilesHandler(c *core.WebContext) (any, *errs.Error) {
+	if a.CurrentConfig().ReceiptImageRecognitionLLMConfig == nil || a.CurrentConfig().ReceiptImageRecognitionLLMConfig.LLMProvider == "" || !a.CurrentConfig().TransactionFromAIImageRecognition {
+		return nil, errs.ErrLargeLanguageModelProviderNotEnabled
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_CREATE_TRANSACTION_FROM_AI_IMAGE_RECOGNITION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	files := form.File["file"]
+
+	if len(files) < 1 {
+		log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] there is no file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoAIRecognitionImage
+	}
+
+	if len(files) > maximumAIRecognitionFileCount {
+		log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] the file count \"%d\" exceeds the maximum count \"%d\" for user \"uid:%d\"", len(files), maximumAIRecognitionFileCount, uid)
+		return nil, errs.ErrExceedMaxAIRecognitionFileCount
+	}
+
+	fileExtensions := make([]string, len(files))
+	contentTypes := make([]string, len(files))
+
+	for i := 0; i < len(files); i++ {
+		if files[i].Size < 1 {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] the size of file \"#%d\" in request is zero for user \"uid:%d\"", i, uid)
+			return nil, errs.ErrAIRecognitionImageIsEmpty
+		}
+
+		if files[i].Size > int64(a.CurrentConfig().MaxAIRecognitionPictureFileSize) {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] the upload file size \"%d\" of file \"#%d\" exceeds the maximum size \"%d\" for user \"uid:%d\"", files[i].Size, i, a.CurrentConfig().MaxAIRecognitionPictureFileSize, uid)
+			return nil, errs.ErrExceedMaxAIRecognitionImageFileSize
+		}
+
+		fileExtensions[i] = utils.GetFileNameExtension(files[i].Filename)
+		contentTypes[i] = utils.GetImageContentType(fileExtensions[i])
+
+		if contentTypes[i] == "" {
+			contentTypes[i] = utils.GetDocumentContentType(fileExtensions[i])
+		}
+
+		if contentTypes[i] == "" {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] the file extension \"%s\" of file \"#%d\" in request is not supported for user \"uid:%d\"", fileExtensions[i], i, uid)
+			return nil, errs.ErrImageTypeNotSupported
+		}
+	}
+
+	splitByCategory := false
+
+	if values := form.Value["splitByCategory"]; len(values) > 0 {
+		splitByCategory = values[0] == "true"
+	}
+
+	references, err := a.getReceiptRecognitionReferences(c, uid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	counterparties, err := a.counterparties.GetAllCounterpartiesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get counterparties for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	counterpartyNames := make([]string, 0, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		if counterparties[i].Hidden {
+			continue
+		}
+
+		counterpartyNames = append(counterpartyNames, counterparties[i].Name)
+	}
+
+	systemPrompt, err := templates.GetTemplate(templates.SYSTEM_PROMPT_RECEIPT_FILE_RECOGNITION)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get system prompt template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	systemPromptParams := map[string]any{
+		"CurrentDateTime":          utils.FormatUnixTimeToLongDateTime(time.Now().Unix(), clientTimezone),
+		"AllExpenseCategoryNames":  strings.Join(references.expenseCategoryNames, "\n"),
+		"AllIncomeCategoryNames":   strings.Join(references.incomeCategoryNames, "\n"),
+		"AllTransferCategoryNames": strings.Join(references.transferCategoryNames, "\n"),
+		"AllAccountNames":          strings.Join(references.accountNames, "\n"),
+		"AllTagNames":              strings.Join(references.tagNames, "\n"),
+		"AllCounterpartyNames":     strings.Join(counterpartyNames, "\n"),
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = systemPrompt.Execute(&bodyBuffer, systemPromptParams)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get final system prompt from template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	finalSystemPrompt := strings.ReplaceAll(bodyBuffer.String(), "\r\n", "\n")
+	response := &models.RecognizedReceiptFilesResponse{
+		Transactions: make([]*models.RecognizedReceiptImageResponse, 0),
+	}
+
+	// each file is recognized separately, so that the pages of one invoice document are kept together and the images of different receipts are not mixed up
+	for i := 0; i < len(files); i++ {
+		fileData, err := a.readMultipartFileData(files[i])
+
+		if err != nil {
+			log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to read file \"#%d\" from request for user \"uid:%d\", because %s", i, uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+
+		llmRequest := &data.LargeLanguageModelRequest{
+			Stream:                false,
+			SystemPrompt:          finalSystemPrompt,
+			UserPrompt:            fileData,
+			UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+			UserPromptContentType: contentTypes[i],
+		}
+
+		llmResponse, err := llm.Container.GetJsonResponseByReceiptImageRecognitionModel(c, uid, a.CurrentConfig(), llmRequest)
+
+		if err != nil {
+			log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to get llm response of file \"#%d\" for user \"uid:%d\", because %s", i, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if llmResponse == nil || len(llmResponse.Content) == 0 || strings.HasPrefix(llmResponse.Content, "{}") {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] there is no transaction information in file \"#%d\" for user \"uid:%d\"", i, uid)
+			continue
+		}
+
+		var result *models.RecognizedReceiptFileResult
+
+		if err := json.Unmarshal([]byte(llmResponse.Content), &result); err != nil {
+			log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to unmarshal recognized receipt file result from llm response \"%s\" for user \"uid:%d\", because %s", llmResponse.Content, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if result == nil || len(result.Transactions) < 1 {
+			log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] there is no transaction information in file \"#%d\" for user \"uid:%d\"", i, uid)
+			continue
+		}
+
+		fileTransactions := make([]*models.RecognizedReceiptImageResponse, 0, len(result.Transactions))
+
+		for j := 0; j < len(result.Transactions); j++ {
+			recognizedTransaction := result.Transactions[j]
+
+			if recognizedTransaction == nil {
+				continue
+			}
+
+			transaction, parseErr := a.parseRecognizedReceiptImageResponse(c, uid, clientTimezone, &recognizedTransaction.RecognizedReceiptImageResult, references)
+
+			if parseErr != nil {
+				log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] skip transaction \"#%d\" in file \"#%d\" for user \"uid:%d\", because %s", j, i, uid, parseErr.Error())
+				continue
+			}
+
+			transaction.CounterpartyId = a.getRecognizedCounterpartyId(counterparties, recognizedTransaction.CounterpartyName)
+			lineItemTransactions, err := a.getRecognizedReceiptFileLineItemTransactions(transaction, recognizedTransaction.Items, references, splitByCategory)
+
+			if err != nil {
+				log.Warnf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] skip line items of transaction \"#%d\" in file \"#%d\" for user \"uid:%d\", because %s", j, i, uid, err.Error())
+				fileTransactions = append(fileTransactions, transaction)
+				continue
+			}
+
+			fileTransactions = append(fileTransactions, lineItemTransactions...)
+		}
+
+		if len(fileTransactions) < 1 {
+			continue
+		}
+
+		if a.CurrentConfig().EnableTransactionPictures && files[i].Size <= int64(a.CurrentConfig().MaxTransactionPictureFileSize) {
+			pictureInfo, err := a.saveRecognizedReceiptFile(c, uid, files[i], fileExtensions[i])
+
+			if err != nil {
+				log.Errorf(c, "[large_language_model_receipt_file_handlers.RecognizeReceiptFilesHandler] failed to save file \"#%d\" as transaction picture for user \"uid:%d\", because %s", i, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			pictureId := utils.Int64ToString(pictureInfo.PictureId)
+
+			for j := 0; j < len(fileTransactions); j++ {
+				fileTransactions[j].PictureIds = append(fileTransactions[j].PictureIds, pictureId)
+			}
+
+			response.Pictures = append(response.Pictures, a.GetTransactionPictureInfoResponse(pictureInfo))
+		}
+
+		response.Transactions = append(response.Transactions, fileTransactions...)
+	}
+
+	if len(response.Transactions) < 1 {
+		return nil, errs.ErrNoTransactionInformationInImage
+	}
+
+	return response, nil
+}
+
+// getRecognizedCounterpartyId returns the id of the visible counterparty whose name matches the recognized name, the exact match takes precedence over the case-insensitive match
+func (a *LargeLanguageModelsApi) getRecognizedCounterpartyId(counterparties []*models.Counterparty, counterpartyName string) int64 {
+	counterpartyName = strings.TrimSpace(counterpartyName)
+
+	if counterpartyName == "" {
+		return 0
+	}
+
+	var caseInsensitiveMatchedCounterparty *models.Counterparty
+
+	for i := 0; i < len(counterparties); i++ {
+		counterparty := counterparties[i]
+
+		if counterparty.Hidden {
+			continue
+		}
+
+		if counterparty.Name == counterpartyName {
+			return counterparty.CounterpartyId
+		}
+
+		if caseInsensitiveMatchedCounterparty == nil && strings.EqualFold(counterparty.Name, counterpartyName) {
+			caseInsensitiveMatchedCounterparty = counterparty
+		}
+	}
+
+	if caseInsensitiveMatchedCounterparty != nil {
+		return caseInsensitiveMatchedCounterparty.CounterpartyId
+	}
+
+	return 0
+}
+
+// getRecognizedReceiptFileLineItemTransactions returns the proposed transactions of the recognized transaction with line items,
+// the line items are merged into the splits per category of one transaction if splitByCategory is true, otherwise each line item becomes a separate transaction
+func (a *LargeLanguageModelsApi) getRecognizedReceiptFileLineItemTransactions(transaction *models.RecognizedReceiptImageResponse, items []*models.RecognizedReceiptFileLineItemResult, references *receiptRecognitionReferences, splitByCategory bool) ([]*models.RecognizedReceiptImageResponse, error) {
+	var categoryMap map[string]*models.TransactionCategory
+
+	if transaction.Type == models.TRANSACTION_TYPE_EXPENSE {
+		categoryMap = references.expenseCategoryMap
+	} else if transaction.Type == models.TRANSACTION_TYPE_INCOME {
+		categoryMap = references.incomeCategoryMap
+	}
+
+	if categoryMap == nil || len(items) < 1 {
+		return []*models.RecognizedReceiptImageResponse{transaction}, nil
+	}
+
+	lineItems := make([]*models.TransactionSplitResponse, 0, len(items))
+	descriptions := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if item == nil || item.Amount == "" {
+			continue
+		}
+
+		amount, err := utils.ParseAmount(item.Amount)
+
+		if err != nil {
+			return nil, err
+		}
+
+		lineItem := &models.TransactionSplitResponse{
+			CategoryId: transaction.CategoryId,
+			Amount:     amount,
+			TagIds:     make([]string, 0, len(item.TagNames)),
+		}
+
+		if category, exists := categoryMap[item.CategoryName]; exists {
+			lineItem.CategoryId = category.CategoryId
+		}
+
+		for j := 0; j < len(item.TagNames); j++ {
+			if tag, exists := references.tagMap[item.TagNames[j]]; exists {
+				lineItem.TagIds = append(lineItem.TagIds, utils.Int64ToString(tag.TagId))
+			}
+		}
+
+		lineItems = append(lineItems, lineItem)
+		descriptions = append(descriptions, item.Description)
+	}
+
+	if len(lineItems) < 1 {
+		return []*models.RecognizedReceiptImageResponse{transaction}, nil
+	}
+
+	if !splitByCategory {
+		transactions := make([]*models.RecognizedReceiptImageResponse, len(lineItems))
+
+		for i := 0; i < len(lineItems); i++ {
+			lineItemTransaction := *transaction
+			lineItemTransaction.CategoryId = lineItems[i].CategoryId
+			lineItemTransaction.SourceAmount = lineItems[i].Amount
+			lineItemTransaction.TagIds = lineItems[i].TagIds
+
+			if descriptions[i] != "" {
+				lineItemTransaction.Comment = descriptions[i]
+			}
+
+			transactions[i] = &lineItemTransaction
+		}
+
+		return transactions, nil
+	}
+
+	splits := make([]models.TransactionSplitResponse, 0, len(lineItems))
+	splitIndexes := make(map[int64]int, len(lineItems))
+	splitTagIds := make(map[int64]map[string]bool, len(lineItems))
+
+	for i := 0; i < len(lineItems); i++ {
+		lineItem := lineItems[i]
+		splitIndex, exists := splitIndexes[lineItem.CategoryId]
+
+		if !exists {
+			splitIndexes[lineItem.CategoryId] = len(splits)
+			splitTagIds[lineItem.CategoryId] = make(map[string]bool, len(lineItem.TagIds))
+			splits = append(splits, models.TransactionSplitResponse{
+				CategoryId: lineItem.CategoryId,
+				TagIds:     make([]string, 0, len(lineItem.TagIds)),
+			})
+			splitIndex = len(splits) - 1
+		}
+
+		splits[splitIndex].Amount += lineItem.Amount
+
+		for j := 0; j < len(lineItem.TagIds); j++ {
+			if !splitTagIds[lineItem.CategoryId][lineItem.TagIds[j]] {
+				splitTagIds[lineItem.CategoryId][lineItem.TagIds[j]] = true
+				splits[splitIndex].TagIds = append(splits[splitIndex].TagIds, lineItem.TagIds[j])
+			}
+		}
+	}
+
+	// the split of the transaction requires the category, so the line items without matched category can only be kept in the transaction without splits
+	if _, exists := splitIndexes[0]; exists {
+		return []*models.RecognizedReceiptImageResponse{transaction}, nil
+	}
+
+	transaction.CategoryId = splits[0].CategoryId
+
+	if len(splits) > 1 {
+		transaction.Splits = splits
+		transaction.SourceAmount = 0
+
+		for i := 0; i < len(splits); i++ {
+			transaction.SourceAmount += splits[i].Amount
+		}
+	}
+
+	return []*models.RecognizedReceiptImageResponse{transaction}, nil
+}
+
+// saveRecognizedReceiptFile saves the recognized receipt file as an unused transaction picture, which would be attached to the transaction when the transaction is created
+func (a *LargeLanguageModelsApi) saveRecognizedReceiptFile(c *core.WebContext, uid int64, fileHeader *multipart.FileHeader, fileExtension string) (*models.TransactionPictureInfo, error) {
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	pictureInfo := &models.TransactionPictureInfo{
+		Uid:              uid,
+		TransactionId:    models.TransactionPictureNewPictureTransactionId,
+		PictureExtension: fileExtension,
+		CreatedIp:        c.ClientIP(),
+	}
+
+	err = a.pictures.UploadPicture(c, pictureInfo, file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pictureInfo, nil
+}
+
+func (a *LargeLanguageModelsApi) readMultipartFileData(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"math"
 	"strings"
 	"time"
 
@@ -19,12 +21,32 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
+const importTransactionCategorizationHistoryQueryCount = 500
+const importTransactionCategorizationHistoryMaxCount = 200
+
+// receiptRecognitionReferences represents the names and name maps of visible accounts, categories and tags used in receipt recognition
+type receiptRecognitionReferences struct {
+	accountMap            map[string]*models.Account
+	accountNames          []string
+	expenseCategoryMap    map[string]*models.TransactionCategory
+	expenseCategoryNames  []string
+	incomeCategoryMap     map[string]*models.TransactionCategory
+	incomeCategoryNames   []string
+	transferCategoryMap   map[string]*models.TransactionCategory
+	transferCategoryNames []string
+	tagMap                map[string]*models.TransactionTag
+	tagNames              []string
+}
+
 // LargeLanguageModelsApi represents large language models api
 type LargeLanguageModelsApi struct {
 	ApiUsingConfig
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
 	accounts              *services.AccountService
+	counterparties        *services.CounterpartyService
+	transactions          *services.TransactionService
+	pictures              *services.TransactionPictureService
 	users                 *services.UserService
 }
 
@@ -37,6 +59,9 @@ var (
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
 		accounts:              services.Accounts,
+		counterparties:        services.Counterparties,
+		transactions:          services.Transactions,
+		pictures:              services.TransactionPictures,
 		users:                 services.Users,
 	}
 )
@@ -117,63 +142,115 @@ func (a *LargeLanguageModelsApi) RecognizeReceiptImageHandler(c *core.WebContext
 		return nil, errs.ErrOperationFailed
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	references, err := a.getReceiptRecognitionReferences(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
-	accountNames := make([]string, 0, len(accounts))
+	systemPrompt, err := templates.GetTemplate(templates.SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION)
 
-	for i := 0; i < len(accounts); i++ {
-		if accounts[i].Hidden || accounts[i].Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
-			continue
-		}
+	if err != nil {
+		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get system prompt template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-		accountNames = append(accountNames, accounts[i].Name)
+	systemPromptParams := map[string]any{
+		"CurrentDateTime":          utils.FormatUnixTimeToLongDateTime(time.Now().Unix(), clientTimezone),
+		"AllExpenseCategoryNames":  strings.Join(references.expenseCategoryNames, "\n"),
+		"AllIncomeCategoryNames":   strings.Join(references.incomeCategoryNames, "\n"),
+		"AllTransferCategoryNames": strings.Join(references.transferCategoryNames, "\n"),
+		"AllAccountNames":          strings.Join(references.accountNames, "\n"),
+		"AllTagNames":              strings.Join(references.tagNames, "\n"),
 	}
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0)
+	var bodyBuffer bytes.Buffer
+	err = systemPrompt.Execute(&bodyBuffer, systemPromptParams)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get final system prompt from template for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	incomeCategoryMap := make(map[string]*models.TransactionCategory)
-	incomeCategoryNames := make([]string, 0)
+	llmRequest := &data.LargeLanguageModelRequest{
+		Stream:                false,
+		SystemPrompt:          strings.ReplaceAll(bodyBuffer.String(), "\r\n", "\n"),
+		UserPrompt:            imageData,
+		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+		UserPromptContentType: contentType,
+	}
 
-	expenseCategoryMap := make(map[string]*models.TransactionCategory)
-	expenseCategoryNames := make([]string, 0)
+	llmResponse, err := llm.Container.GetJsonResponseByReceiptImageRecognitionModel(c, c.GetCurrentUid(), a.CurrentConfig(), llmRequest)
 
-	transferCategoryMap := make(map[string]*models.TransactionCategory)
-	transferCategoryNames := make([]string, 0)
+	if err != nil {
+		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get llm response user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-	for i := 0; i < len(categories); i++ {
-		category := categories[i]
+	if llmResponse == nil || len(llmResponse.Content) == 0 || strings.HasPrefix(llmResponse.Content, "{}") {
+		return nil, errs.ErrNoTransactionInformationInImage
+	}
 
-		if category.Hidden {
-			continue
-		}
+	var result *models.RecognizedReceiptImageResult
 
-		if category.Type == models.CATEGORY_TYPE_INCOME {
-			incomeCategoryMap[category.Name] = category
-			incomeCategoryNames = append(incomeCategoryNames, category.Name)
-		} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
-			expenseCategoryMap[category.Name] = category
-			expenseCategoryNames = append(expenseCategoryNames, category.Name)
-		} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
-			transferCategoryMap[category.Name] = category
-			transferCategoryNames = append(transferCategoryNames, category.Name)
+	if err := json.Unmarshal([]byte(llmResponse.Content), &result); err != nil {
+		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to unmarshal recognized receipt image result from llm response \"%s\" for user \"uid:%d\", because %s", llmResponse.Content, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.parseRecognizedReceiptImageResponse(c, uid, clientTimezone, result, references)
+}
+
+// SuggestImportTransactionCategoriesHandler returns the suggested categories, counterparties and tags of imported transactions
+func (a *LargeLanguageModelsApi) SuggestImportTransactionCategoriesHandler(c *core.WebContext) (any, *errs.Error) {
+	if a.CurrentConfig().TransactionImportCategorizationLLMConfig == nil || a.CurrentConfig().TransactionImportCategorizationLLMConfig.LLMProvider == "" || !a.CurrentConfig().TransactionImportAICategorization {
+		return nil, errs.ErrLargeLanguageModelProviderNotEnabled
+	}
+
+	var suggestReq models.ImportTransactionCategorizationSuggestRequest
+	err := c.ShouldBindJSON(&suggestReq)
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
 		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetVisibleCategoryNameMapByList(categories)
+	categoryMap := a.transactionCategories.GetCategoryMapByList(categories)
+
 	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
@@ -188,60 +265,259 @@ func (a *LargeLanguageModelsApi) RecognizeReceiptImageHandler(c *core.WebContext
 		tagNames = append(tagNames, tags[i].Name)
 	}
 
-	systemPrompt, err := templates.GetTemplate(templates.SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION)
+	counterparties, err := a.counterparties.GetAllCounterpartiesByUid(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get system prompt template for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get counterparties for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	counterpartyMap := make(map[string]*models.Counterparty, len(counterparties))
+	counterpartyNames := make([]string, 0, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		if counterparties[i].Hidden {
+			continue
+		}
+
+		counterpartyMap[counterparties[i].Name] = counterparties[i]
+		counterpartyNames = append(counterpartyNames, counterparties[i].Name)
+	}
+
+	transactionHistory, err := a.getImportTransactionCategorizationHistory(c, uid, categoryMap, counterparties, tags)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	systemPrompt, err := templates.GetTemplate(templates.SYSTEM_PROMPT_IMPORT_TRANSACTION_CATEGORIZATION)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get system prompt template for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	systemPromptParams := map[string]any{
 		"CurrentDateTime":          utils.FormatUnixTimeToLongDateTime(time.Now().Unix(), clientTimezone),
-		"AllExpenseCategoryNames":  strings.Join(expenseCategoryNames, "\n"),
-		"AllIncomeCategoryNames":   strings.Join(incomeCategoryNames, "\n"),
-		"AllTransferCategoryNames": strings.Join(transferCategoryNames, "\n"),
-		"AllAccountNames":          strings.Join(accountNames, "\n"),
+		"AllExpenseCategoryNames":  strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_EXPENSE), "\n"),
+		"AllIncomeCategoryNames":   strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_INCOME), "\n"),
+		"AllTransferCategoryNames": strings.Join(a.getCategoryTreeNames(categories, models.CATEGORY_TYPE_TRANSFER), "\n"),
+		"AllCounterpartyNames":     strings.Join(counterpartyNames, "\n"),
 		"AllTagNames":              strings.Join(tagNames, "\n"),
+		"TransactionHistory":       strings.Join(transactionHistory, "\n"),
 	}
 
 	var bodyBuffer bytes.Buffer
 	err = systemPrompt.Execute(&bodyBuffer, systemPromptParams)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get final system prompt from template for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get final system prompt from template for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	userPrompt, err := json.Marshal(a.getImportTransactionCategorizationPromptItems(suggestReq.Transactions, counterparties))
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to serialize imported transactions for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	llmRequest := &data.LargeLanguageModelRequest{
-		Stream:                false,
-		SystemPrompt:          strings.ReplaceAll(bodyBuffer.String(), "\r\n", "\n"),
-		UserPrompt:            imageData,
-		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
-		UserPromptContentType: contentType,
+		Stream:         false,
+		SystemPrompt:   strings.ReplaceAll(bodyBuffer.String(), "\r\n", "\n"),
+		UserPrompt:     userPrompt,
+		UserPromptType: data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_TEXT,
 	}
 
-	llmResponse, err := llm.Container.GetJsonResponseByReceiptImageRecognitionModel(c, c.GetCurrentUid(), a.CurrentConfig(), llmRequest)
+	llmResponse, err := llm.Container.GetJsonResponseByTransactionImportCategorizationModel(c, uid, a.CurrentConfig(), llmRequest)
 
 	if err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get llm response user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to get llm response user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	suggestions := make([]*models.ImportTransactionCategorizationSuggestion, 0, len(suggestReq.Transactions))
+
 	if llmResponse == nil || len(llmResponse.Content) == 0 || strings.HasPrefix(llmResponse.Content, "{}") {
-		return nil, errs.ErrNoTransactionInformationInImage
+		return suggestions, nil
 	}
 
-	var result *models.RecognizedReceiptImageResult
+	var result *models.ImportTransactionCategorizationResult
 
 	if err := json.Unmarshal([]byte(llmResponse.Content), &result); err != nil {
-		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to unmarshal recognized receipt image result from llm response \"%s\" for user \"uid:%d\", because %s", llmResponse.Content, uid, err.Error())
+		log.Errorf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] failed to unmarshal categorization result from llm response \"%s\" for user \"uid:%d\", because %s", llmResponse.Content, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	return a.parseRecognizedReceiptImageResponse(c, uid, clientTimezone, result, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+	if result == nil {
+		return suggestions, nil
+	}
+
+	transactionTypes := make(map[int]models.TransactionType, len(suggestReq.Transactions))
+
+	for i := 0; i < len(suggestReq.Transactions); i++ {
+		transactionTypes[suggestReq.Transactions[i].Index] = suggestReq.Transactions[i].Type
+	}
+
+	for i := 0; i < len(result.Suggestions); i++ {
+		item := result.Suggestions[i]
+
+		if item == nil {
+			continue
+		}
+
+		transactionType, exists := transactionTypes[item.Index]
+
+		if !exists {
+			log.Warnf(c, "[large_language_models.SuggestImportTransactionCategoriesHandler] suggested transaction index \"%d\" does not exist in request for user \"uid:%d\"", item.Index, uid)
+			continue
+		}
+
+		suggestion := &models.ImportTransactionCategorizationSuggestion{
+			Index:      item.Index,
+			Confidence: math.Max(0, math.Min(1, item.Confidence)),
+		}
+
+		if len(item.CategoryName) > 0 {
+			var category *models.TransactionCategory
+
+			if transactionType == models.TRANSACTION_TYPE_INCOME {
+				category = incomeCategoryMap[item.CategoryName]
+			} else if transactionType == models.TRANSACTION_TYPE_EXPENSE {
+				category = expenseCategoryMap[item.CategoryName]
+			} else if transactionType == models.TRANSACTION_TYPE_TRANSFER {
+				category = transferCategoryMap[item.CategoryName]
+			}
+
+			if category != nil {
+				suggestion.CategoryId = category.CategoryId
+			}
+		}
+
+		if len(item.CounterpartyName) > 0 {
+			counterparty, exists := counterpartyMap[item.CounterpartyName]
+
+			if exists {
+				suggestion.CounterpartyId = counterparty.CounterpartyId
+			}
+		}
+
+		if len(item.TagNames) > 0 {
+			tagIds := make([]int64, 0, len(item.TagNames))
+
+			for j := 0; j < len(item.TagNames); j++ {
+				tag, exists := tagMap[item.TagNames[j]]
+
+				if exists {
+					tagIds = append(tagIds, tag.TagId)
+				}
+			}
+
+			tagIds = utils.ToUniqueInt64Slice(tagIds)
+
+			for j := 0; j < len(tagIds) && j < models.MaximumTagsCountOfTransaction; j++ {
+				suggestion.TagIds = append(suggestion.TagIds, utils.Int64ToString(tagIds[j]))
+			}
+		}
+
+		if suggestion.CategoryId == 0 && suggestion.CounterpartyId == 0 && len(suggestion.TagIds) < 1 {
+			continue
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
 }
 
-func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.WebContext, uid int64, clientTimezone *time.Location, recognizedResult *models.RecognizedReceiptImageResult, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (*models.RecognizedReceiptImageResponse, *errs.Error) {
+// getReceiptRecognitionReferences returns the visible accounts, categories and tags of user which the recognized receipts can refer to
+func (a *LargeLanguageModelsApi) getReceiptRecognitionReferences(c *core.WebContext, uid int64) (*receiptRecognitionReferences, error) {
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.getReceiptRecognitionReferences] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
+	accountNames := make([]string, 0, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		if accounts[i].Hidden || accounts[i].Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		accountNames = append(accountNames, accounts[i].Name)
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.getReceiptRecognitionReferences] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	incomeCategoryMap := make(map[string]*models.TransactionCategory)
+	incomeCategoryNames := make([]string, 0)
+
+	expenseCategoryMap := make(map[string]*models.TransactionCategory)
+	expenseCategoryNames := make([]string, 0)
+
+	transferCategoryMap := make(map[string]*models.TransactionCategory)
+	transferCategoryNames := make([]string, 0)
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden {
+			continue
+		}
+
+		if category.Type == models.CATEGORY_TYPE_INCOME {
+			incomeCategoryMap[category.Name] = category
+			incomeCategoryNames = append(incomeCategoryNames, category.Name)
+		} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+			expenseCategoryMap[category.Name] = category
+			expenseCategoryNames = append(expenseCategoryNames, category.Name)
+		} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
+			transferCategoryMap[category.Name] = category
+			transferCategoryNames = append(transferCategoryNames, category.Name)
+		}
+	}
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.getReceiptRecognitionReferences] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	tagMap := a.transactionTags.GetVisibleTagNameMapByList(tags)
+	tagNames := make([]string, 0, len(tags))
+
+	for i := 0; i < len(tags); i++ {
+		if tags[i].Hidden {
+			continue
+		}
+
+		tagNames = append(tagNames, tags[i].Name)
+	}
+
+	return &receiptRecognitionReferences{
+		accountMap:            accountMap,
+		accountNames:          accountNames,
+		expenseCategoryMap:    expenseCategoryMap,
+		expenseCategoryNames:  expenseCategoryNames,
+		incomeCategoryMap:     incomeCategoryMap,
+		incomeCategoryNames:   incomeCategoryNames,
+		transferCategoryMap:   transferCategoryMap,
+		transferCategoryNames: transferCategoryNames,
+		tagMap:                tagMap,
+		tagNames:              tagNames,
+	}, nil
+}
+
+func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.WebContext, uid int64, clientTimezone *time.Location, recognizedResult *models.RecognizedReceiptImageResult, references *receiptRecognitionReferences) (*models.RecognizedReceiptImageResponse, *errs.Error) {
 	recognizedReceiptImageResponse := &models.RecognizedReceiptImageResponse{
 		Type: models.TRANSACTION_TYPE_EXPENSE,
 	}
@@ -255,7 +531,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 		recognizedReceiptImageResponse.Type = models.TRANSACTION_TYPE_INCOME
 
 		if len(recognizedResult.CategoryName) > 0 {
-			category, exists := incomeCategoryMap[recognizedResult.CategoryName]
+			category, exists := references.incomeCategoryMap[recognizedResult.CategoryName]
 
 			if exists {
 				recognizedReceiptImageResponse.CategoryId = category.CategoryId
@@ -265,7 +541,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 		recognizedReceiptImageResponse.Type = models.TRANSACTION_TYPE_EXPENSE
 
 		if len(recognizedResult.CategoryName) > 0 {
-			category, exists := expenseCategoryMap[recognizedResult.CategoryName]
+			category, exists := references.expenseCategoryMap[recognizedResult.CategoryName]
 
 			if exists {
 				recognizedReceiptImageResponse.CategoryId = category.CategoryId
@@ -275,7 +551,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 		recognizedReceiptImageResponse.Type = models.TRANSACTION_TYPE_TRANSFER
 
 		if len(recognizedResult.CategoryName) > 0 {
-			category, exists := transferCategoryMap[recognizedResult.CategoryName]
+			category, exists := references.transferCategoryMap[recognizedResult.CategoryName]
 
 			if exists {
 				recognizedReceiptImageResponse.CategoryId = category.CategoryId
@@ -322,7 +598,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 	}
 
 	if len(recognizedResult.AccountName) > 0 {
-		account, exists := accountMap[recognizedResult.AccountName]
+		account, exists := references.accountMap[recognizedResult.AccountName]
 
 		if exists {
 			recognizedReceiptImageResponse.SourceAccountId = account.AccountId
@@ -330,7 +606,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 	}
 
 	if len(recognizedResult.DestinationAccountName) > 0 {
-		account, exists := accountMap[recognizedResult.DestinationAccountName]
+		account, exists := references.accountMap[recognizedResult.DestinationAccountName]
 
 		if exists {
 			recognizedReceiptImageResponse.DestinationAccountId = account.AccountId
@@ -342,7 +618,7 @@ func (a *LargeLanguageModelsApi) parseRecognizedReceiptImageResponse(c *core.Web
 
 		for i := 0; i < len(recognizedResult.TagNames); i++ {
 			tagName := recognizedResult.TagNames[i]
-			tag, exists := tagMap[tagName]
+			tag, exists := references.tagMap[tagName]
 
 			if exists {
 				tagIds = append(tagIds, utils.Int64ToString(tag.TagId))
@@ -374,3 +650,149 @@ func (a *LargeLanguageModelsApi) getLongDateTime(dateTime string) string {
 
 	return dateTime
 }
+
+// getCategoryTreeNames returns the names of visible categories in specified type, the secondary categories are indented under their primary categories
+func (a *LargeLanguageModelsApi) getCategoryTreeNames(categories []*models.TransactionCategory, categoryType models.TransactionCategoryType) []string {
+	categoryNames := make([]string, 0, len(categories))
+
+	for i := 0; i < len(categories); i++ {
+		primaryCategory := categories[i]
+
+		if primaryCategory.Hidden || primaryCategory.Type != categoryType || primaryCategory.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		categoryNames = append(categoryNames, primaryCategory.Name)
+
+		for j := 0; j < len(categories); j++ {
+			secondaryCategory := categories[j]
+
+			if secondaryCategory.Hidden || secondaryCategory.ParentCategoryId != primaryCategory.CategoryId {
+				continue
+			}
+
+			categoryNames = append(categoryNames, "  - "+secondaryCategory.Name)
+		}
+	}
+
+	return categoryNames
+}
+
+// getImportTransactionCategorizationHistory returns the latest transactions of user with distinct descriptions which are used as the context of categorization
+func (a *LargeLanguageModelsApi) getImportTransactionCategorizationHistory(c *core.WebContext, uid int64, categoryMap map[int64]*models.TransactionCategory, counterparties []*models.Counterparty, tags []*models.TransactionTag) ([]string, error) {
+	transactions, err := a.transactions.GetAllTransactionsByMaxTime(c, uid, utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix()), importTransactionCategorizationHistoryQueryCount, true)
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.getImportTransactionCategorizationHistory] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, a.transactions.GetTransactionIds(transactions))
+
+	if err != nil {
+		log.Errorf(c, "[large_language_models.getImportTransactionCategorizationHistory] failed to get transaction tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	counterpartyMap := make(map[int64]*models.Counterparty, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		counterpartyMap[counterparties[i].CounterpartyId] = counterparties[i]
+	}
+
+	tagMap := a.transactionTags.GetTagMapByList(tags)
+	existedDescriptions := make(map[string]bool, len(transactions))
+	history := make([]string, 0, importTransactionCategorizationHistoryMaxCount)
+
+	for i := 0; i < len(transactions) && len(history) < importTransactionCategorizationHistoryMaxCount; i++ {
+		transaction := transactions[i]
+		category, exists := categoryMap[transaction.CategoryId]
+
+		if !exists || transaction.Comment == "" {
+			continue
+		}
+
+		if _, exists := existedDescriptions[transaction.Comment]; exists {
+			continue
+		}
+
+		existedDescriptions[transaction.Comment] = true
+
+		counterpartyName := ""
+
+		if counterparty, exists := counterpartyMap[transaction.CounterpartyId]; exists {
+			counterpartyName = counterparty.Name
+		}
+
+		tagIds := allTransactionTagIds[transaction.TransactionId]
+		tagNames := make([]string, 0, len(tagIds))
+
+		for j := 0; j < len(tagIds); j++ {
+			if tag, exists := tagMap[tagIds[j]]; exists {
+				tagNames = append(tagNames, tag.Name)
+			}
+		}
+
+		history = append(history, fmt.Sprintf("%s | %s | %s | %s | %s", a.getTransactionTypeName(transaction.Type), strings.ReplaceAll(transaction.Comment, "\n", " "), category.Name, counterpartyName, strings.Join(tagNames, ", ")))
+	}
+
+	return history, nil
+}
+
+// getImportTransactionCategorizationPromptItems returns the imported transactions in the format described in system prompt
+func (a *LargeLanguageModelsApi) getImportTransactionCategorizationPromptItems(transactions []*models.ImportTransactionCategorizationItem, counterparties []*models.Counterparty) []map[string]any {
+	counterpartyMap := make(map[int64]*models.Counterparty, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		counterpartyMap[counterparties[i].CounterpartyId] = counterparties[i]
+	}
+
+	items := make([]map[string]any, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		counterpartyName := transaction.OriginalCounterpartyName
+
+		if counterpartyName == "" {
+			if counterparty, exists := counterpartyMap[transaction.CounterpartyId]; exists {
+				counterpartyName = counterparty.Name
+			}
+		}
+
+		transactionType := ""
+
+		if transaction.Type == models.TRANSACTION_TYPE_INCOME {
+			transactionType = "income"
+		} else if transaction.Type == models.TRANSACTION_TYPE_EXPENSE {
+			transactionType = "expense"
+		} else if transaction.Type == models.TRANSACTION_TYPE_TRANSFER {
+			transactionType = "transfer"
+		}
+
+		items = append(items, map[string]any{
+			"index":                 transaction.Index,
+			"type":                  transactionType,
+			"time":                  utils.FormatUnixTimeToLongDateTime(transaction.Time, time.FixedZone("Transaction Timezone", int(transaction.UtcOffset)*60)),
+			"amount":                utils.FormatAmount(transaction.SourceAmount),
+			"currency":              transaction.OriginalSourceAccountCurrency,
+			"original_category":     transaction.OriginalCategoryName,
+			"original_counterparty": counterpartyName,
+			"description":           transaction.Comment,
+		})
+	}
+
+	return items
+}
+
+// getTransactionTypeName returns the transaction type name used in prompts
+func (a *LargeLanguageModelsApi) getTransactionTypeName(transactionType models.TransactionDbType) string {
+	if transactionType == models.TRANSACTION_DB_TYPE_INCOME {
+		return "income"
+	} else if transactionType == models.TRANSACTION_DB_TYPE_EXPENSE {
+		return "expense"
+	} else if transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return "transfer"
+	}
+
+	return "balance_modification"
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestLargeLanguageModelsApiGetCategoryTreeNames(t *testing.T) {
+	categories := []*models.TransactionCategory{
+		{CategoryId: 1, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		{CategoryId: 2, Name: "Restaurant", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1},
+		{CategoryId: 3, Name: "Groceries", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1, Hidden: true},
+		{CategoryId: 4, Name: "Salary", Type: models.CATEGORY_TYPE_INCOME},
+		{CategoryId: 5, Name: "Bonus", Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 4},
+	}
+
+	assert.Equal(t, []string{"Food", "  - Restaurant"}, LargeLanguageModels.getCategoryTreeNames(categories, models.CATEGORY_TYPE_EXPENSE))
+	assert.Equal(t, []string{"Salary", "  - Bonus"}, LargeLanguageModels.getCategoryTreeNames(categories, models.CATEGORY_TYPE_INCOME))
+	assert.Equal(t, []string{}, LargeLanguageModels.getCategoryTreeNames(categories, models.CATEGORY_TYPE_TRANSFER))
+}
+
+func TestLargeLanguageModelsApiGetImportTransactionCategorizationPromptItems(t *testing.T) {
+	transactions := []*models.ImportTransactionCategorizationItem{
+		{Index: 0, Type: models.TRANSACTION_TYPE_EXPENSE, Time: 1700000000, UtcOffset: 0, SourceAmount: 1234, OriginalSourceAccountCurrency: "USD", Comment: "Coffee", CounterpartyId: 10},
+		{Index: 1, Type: models.TRANSACTION_TYPE_INCOME, Time: 1700000000, UtcOffset: 60, SourceAmount: 100000, OriginalCounterpartyName: "ACME"},
+	}
+	counterparties := []*models.Counterparty{
+		{CounterpartyId: 10, Name: "Cafe"},
+	}
+
+	items := LargeLanguageModels.getImportTransactionCategorizationPromptItems(transactions, counterparties)
+
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, "expense", items[0]["type"])
+	assert.Equal(t, "2023-11-14 22:13:20", items[0]["time"])
+	assert.Equal(t, "12.34", items[0]["amount"])
+	assert.Equal(t, "Cafe", items[0]["original_counterparty"])
+	assert.Equal(t, "income", items[1]["type"])
+	assert.Equal(t, "2023-11-14 23:13:20", items[1]["time"])
+	assert.Equal(t, "ACME", items[1]["original_counterparty"])
+}
+
+func TestLargeLanguageModelsApiGetTransactionTypeName(t *testing.T) {
+	assert.Equal(t, "income", LargeLanguageModels.getTransactionTypeName(models.TRANSACTION_DB_TYPE_INCOME))
+	assert.Equal(t, "expense", LargeLanguageModels.getTransactionTypeName(models.TRANSACTION_DB_TYPE_EXPENSE))
+	assert.Equal(t, "transfer", LargeLanguageModels.getTransactionTypeName(models.TRANSACTION_DB_TYPE_TRANSFER_IN))
+	assert.Equal(t, "balance_modification", LargeLanguageModels.getTransactionTypeName(models.TRANSACTION_DB_TYPE_MODIFY_BALANCE))
+}
+
+func TestLargeLanguageModelsApiParseTransactionNaturalLanguageQueryResult(t *testing.T) {
+	categories := []*models.TransactionCategory{
+		{CategoryId: 1, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		{CategoryId: 2, Name: "Coffee", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1},
+		{CategoryId: 3, Name: "Restaurant", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1},
+		{CategoryId: 4, Name: "Food", Type: models.CATEGORY_TYPE_INCOME},
+	}
+	accounts := []*models.Account{
+		{AccountId: 10, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT},
+	}
+	tags := []*models.TransactionTag{
+		{TagId: 20, Name: "Travel"},
+		{TagId: 21, Name: "Work"},
+	}
+	counterparties := []*models.Counterparty{
+		{CounterpartyId: 30, Name: "Starbucks"},
+	}
+	queryResult := &models.TransactionNaturalLanguageQueryResult{
+		Type:          "expense",
+		StartTime:     "2024-03-01 00:00:00",
+		EndTime:       "2024-03-31",
+		CategoryNames: []string{"Food", "Unknown"},
+		AccountNames:  []string{"Cash"},
+		TagNames:      []string{"Travel", "Work"},
+		TagFilterType: "has_all",
+		AmountFilter: &models.TransactionNaturalLanguageQueryAmountFilter{
+			Operator: "gt",
+			Amount:   "500",
+		},
+		Keyword:          " Moscow ",
+		CounterpartyName: "Starbucks",
+	}
+
+	filter := LargeLanguageModels.parseTransactionNaturalLanguageQueryResult(nil, time.UTC, queryResult, accounts, categories, tags, counterparties)
+
+	assert.Equal(t, models.TRANSACTION_TYPE_EXPENSE, filter.Type)
+	assert.Equal(t, int64(1709251200000), filter.MinTime)
+	assert.Equal(t, int64(1711929599999), filter.MaxTime)
+	assert.Equal(t, []string{"1", "2", "3"}, filter.CategoryIds)
+	assert.Equal(t, []string{"10"}, filter.AccountIds)
+	assert.Equal(t, "1:20,21", filter.TagFilter)
+	assert.Equal(t, "gt:50000", filter.AmountFilter)
+	assert.Equal(t, "Moscow", filter.Keyword)
+	assert.Equal(t, int64(30), filter.CounterpartyId)
+	assert.Equal(t, []string{"Unknown"}, filter.UnmatchedNames)
+}
+
+func TestLargeLanguageModelsApiParseTransactionNaturalLanguageQueryResult_EmptyResult(t *testing.T) {
+	filter := LargeLanguageModels.parseTransactionNaturalLanguageQueryResult(nil, time.UTC, nil, nil, nil, nil, nil)
+	assert.Equal(t, &models.TransactionNaturalLanguageQueryFilter{}, filter)
+}
+
+func TestLargeLanguageModelsApiGetRecognizedCounterpartyId(t *testing.T) {
+	counterparties := []*models.Counterparty{
+		{CounterpartyId: 1, Name: "acme corp"},
+		{CounterpartyId: 2, Name: "ACME Corp"},
+		{CounterpartyId: 3, Name: "Old Supplier", Hidden: true},
+	}
+
+	assert.Equal(t, int64(2), LargeLanguageModels.getRecognizedCounterpartyId(counterparties, "ACME Corp"))
+	assert.Equal(t, int64(1), LargeLanguageModels.getRecognizedCounterpartyId(counterparties, " Acme CORP "))
+	assert.Equal(t, int64(0), LargeLanguageModels.getRecognizedCounterpartyId(counterparties, "Old Supplier"))
+	assert.Equal(t, int64(0), LargeLanguageModels.getRecognizedCounterpartyId(counterparties, ""))
+}
+
+func TestLargeLanguageModelsApiGetRecognizedReceiptFileLineItemTransactions(t *testing.T) {
+	references := &receiptRecognitionReferences{
+		expenseCategoryMap: map[string]*models.TransactionCategory{
+			"Office":   {CategoryId: 1, Name: "Office"},
+			"Software": {CategoryId: 2, Name: "Software"},
+		},
+		tagMap: map[string]*models.TransactionTag{
+			"Work": {TagId: 10, Name: "Work"},
+		},
+	}
+	items := []*models.RecognizedReceiptFileLineItemResult{
+		{Amount: "10.00", CategoryName: "Office", Description: "Paper"},
+		{Amount: "25.50", CategoryName: "Software", TagNames: []string{"Work"}, Description: "License"},
+		{Amount: "4.50", CategoryName: "Office", TagNames: []string{"Work", "Unknown"}, Description: "Pens"},
+	}
+
+	transaction := &models.RecognizedReceiptImageResponse{Type: models.TRANSACTION_TYPE_EXPENSE, SourceAmount: 4000, Comment: "Invoice"}
+	transactions, err := LargeLanguageModels.getRecognizedReceiptFileLineItemTransactions(transaction, items, references, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, int64(1), transactions[0].CategoryId)
+	assert.Equal(t, int64(4000), transactions[0].SourceAmount)
+	assert.Equal(t, []models.TransactionSplitResponse{
+		{CategoryId: 1, Amount: 1450, TagIds: []string{"10"}},
+		{CategoryId: 2, Amount: 2550, TagIds: []string{"10"}},
+	}, transactions[0].Splits)
+
+	transaction = &models.RecognizedReceiptImageResponse{Type: models.TRANSACTION_TYPE_EXPENSE, SourceAmount: 4000, Comment: "Invoice", CounterpartyId: 5}
+	transactions, err = LargeLanguageModels.getRecognizedReceiptFileLineItemTransactions(transaction, items, references, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(transactions))
+	assert.Equal(t, int64(1), transactions[0].CategoryId)
+	assert.Equal(t, int64(1000), transactions[0].SourceAmount)
+	assert.Equal(t, "Paper", transactions[0].Comment)
+	assert.Equal(t, int64(5), transactions[0].CounterpartyId)
+	assert.Equal(t, int64(2), transactions[1].CategoryId)
+	assert.Equal(t, int64(2550), transactions[1].SourceAmount)
+	assert.Equal(t, []string{"10"}, transactions[1].TagIds)
+	assert.Equal(t, int64(450), transactions[2].SourceAmount)
+	assert.Nil(t, transactions[0].Splits)
+}
+
+func TestLargeLanguageModelsApiGetRecognizedReceiptFileLineItemTransactions_UnmatchedCategory(t *testing.T) {
+	references := &receiptRecognitionReferences{
+		expenseCategoryMap: map[string]*models.TransactionCategory{
+			"Office": {CategoryId: 1, Name: "Office"},
+		},
+	}
+	items := []*models.RecognizedReceiptFileLineItemResult{
+		{Amount: "10.00", CategoryName: "Office"},
+		{Amount: "5.00", CategoryName: "Unknown"},
+	}
+
+	transaction := &models.RecognizedReceiptImageResponse{Type: models.TRANSACTION_TYPE_EXPENSE, SourceAmount: 1500}
+	transactions, err := LargeLanguageModels.getRecognizedReceiptFileLineItemTransactions(transaction, items, references, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, int64(0), transactions[0].CategoryId)
+	assert.Nil(t, transactions[0].Splits)
+
+	_, err = LargeLanguageModels.getRecognizedReceiptFileLineItemTransactions(transaction, []*models.RecognizedReceiptFileLineItemResult{{Amount: "abc"}}, references, true)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+const defaultMCPAuditLogPageCount = 50
+
+// MCPAuditLogsApi represents api of the changes made by MCP tools
+type MCPAuditLogsApi struct {
+	auditLogs services.MCPAuditLogProvider
+}
+
+// NewMCPAuditLogsApi creates a new MCPAuditLogsApi instance
+func NewMCPAuditLogsApi(l services.MCPAuditLogProvider) *MCPAuditLogsApi {
+	return &MCPAuditLogsApi{auditLogs: l}
+}
+
+// Initialize a MCP audit logs api singleton instance
+var (
+	MCPAuditLogsAPI = NewMCPAuditLogsApi(services.MCPAuditLogs)
+)
+
+// AuditLogListHandler returns the changes made by MCP tools of current user (latest first)
+func (a *MCPAuditLogsApi) AuditLogListHandler(c *core.WebContext) (any, *errs.Error) {
+	var auditLogListReq models.MCPAuditLogListRequest
+	err := c.ShouldBindQuery(&auditLogListReq)
+
+	if err != nil {
+		log.Warnf(c, "[mcp_audit_logs.AuditLogListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if auditLogListReq.Page < 1 {
+		auditLogListReq.Page = 1
+	}
+
+	if auditLogListReq.Count < 1 {
+		auditLogListReq.Count = defaultMCPAuditLogPageCount
+	}
+
+	uid := c.GetCurrentUid()
+	auditLogs, err := a.auditLogs.GetAuditLogsByUid(c, uid, auditLogListReq.TokenId, auditLogListReq.Page, auditLogListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[mcp_audit_logs.AuditLogListHandler] failed to get mcp audit logs for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	auditLogResps := make([]*models.MCPAuditLogInfoResponse, len(auditLogs))
+
+	for i := 0; i < len(auditLogs); i++ {
+		auditLogResps[i] = auditLogs[i].ToMCPAuditLogInfoResponse()
+	}
+
+	return auditLogResps, nil
+}
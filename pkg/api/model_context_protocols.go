@@ -18,12 +18,23 @@ const mcpServerName = core.ApplicationName + "-mcp"
 // ModelContextProtocolAPI represents model context protocol api
 type ModelContextProtocolAPI struct {
 	ApiUsingConfig
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	accounts              *services.AccountService
-	users                 *services.UserService
-	tokens                *services.TokenService
+	transactions             *services.TransactionService
+	transactionCategories    *services.TransactionCategoryService
+	transactionTags          *services.TransactionTagService
+	transactionSearchIndexes *services.TransactionSearchIndexService
+	accounts                 *services.AccountService
+	users                    *services.UserService
+	tokens                   *services.TokenService
+	reports                  *services.ReportService
+	budgets                  *services.BudgetService
+	obligations              *services.ObligationService
+	taxRecords               *services.TaxRecordService
+	investorDeals            *services.InvestorDealService
+	assets                   *services.AssetService
+	cfos                     *services.CFOService
+	counterparties           *services.CounterpartyService
+	investorPayments         *services.InvestorPaymentService
+	mcpAuditLogs             *services.MCPAuditLogService
 }
 
 // Initialize a model context protocol api singleton instance
@@ -32,12 +43,23 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		accounts:              services.Accounts,
-		users:                 services.Users,
-		tokens:                services.Tokens,
+		transactions:             services.Transactions,
+		transactionCategories:    services.TransactionCategories,
+		transactionTags:          services.TransactionTags,
+		transactionSearchIndexes: services.TransactionSearchIndexes,
+		accounts:                 services.Accounts,
+		users:                    services.Users,
+		tokens:                   services.Tokens,
+		reports:                  services.Reports,
+		budgets:                  services.Budgets,
+		obligations:              services.Obligations,
+		taxRecords:               services.TaxRecords,
+		investorDeals:            services.InvestorDeals,
+		assets:                   services.Assets,
+		cfos:                     services.CFOs,
+		counterparties:           services.Counterparties,
+		investorPayments:         services.InvestorPayments,
+		mcpAuditLogs:             services.MCPAuditLogs,
 	}
 )
 
@@ -66,23 +88,27 @@ func (a *ModelContextProtocolAPI) InitializeHandler(c *core.WebContext, jsonRPCR
 	}
 
 	tokenClaims := c.GetTokenClaims()
-	userTokenId, err := utils.StringToInt64(tokenClaims.UserTokenId)
 
-	if err != nil {
-		log.Warnf(c, "[model_context_protocols.InitializeHandler] parse user token id failed, because %s", err.Error())
-	} else {
-		tokenRecord := &models.TokenRecord{
-			Uid:             tokenClaims.Uid,
-			UserTokenId:     userTokenId,
-			CreatedUnixTime: tokenClaims.IssuedAt,
-		}
+	// requests over stdio transport are not authorized by token, so there is no token to update
+	if tokenClaims.UserTokenId != "" {
+		userTokenId, err := utils.StringToInt64(tokenClaims.UserTokenId)
 
-		tokenId := a.tokens.GenerateTokenId(tokenRecord)
+		if err != nil {
+			log.Warnf(c, "[model_context_protocols.InitializeHandler] parse user token id failed, because %s", err.Error())
+		} else {
+			tokenRecord := &models.TokenRecord{
+				Uid:             tokenClaims.Uid,
+				UserTokenId:     userTokenId,
+				CreatedUnixTime: tokenClaims.IssuedAt,
+			}
 
-		err = a.tokens.UpdateTokenLastSeen(c, tokenRecord)
+			tokenId := a.tokens.GenerateTokenId(tokenRecord)
 
-		if err != nil {
-			log.Warnf(c, "[model_context_protocols.InitializeHandler] failed to update last seen of token \"id:%s\" for user \"uid:%d\", because %s", tokenId, uid, err.Error())
+			err = a.tokens.UpdateTokenLastSeen(c, tokenRecord)
+
+			if err != nil {
+				log.Warnf(c, "[model_context_protocols.InitializeHandler] failed to update last seen of token \"id:%s\" for user \"uid:%d\", because %s", tokenId, uid, err.Error())
+			}
 		}
 	}
 
@@ -96,9 +122,16 @@ func (a *ModelContextProtocolAPI) InitializeHandler(c *core.WebContext, jsonRPCR
 	initResp := mcp.MCPInitializeResponse{
 		ProtocolVersion: string(protocolVersion),
 		Capabilities: &mcp.MCPCapabilities{
+			Resources: &mcp.MCPResourceCapabilities{
+				Subscribe:   false,
+				ListChanged: false,
+			},
 			Tools: &mcp.MCPToolCapabilities{
 				ListChanged: false,
 			},
+			Prompts: &mcp.MCPPromptCapabilities{
+				ListChanged: false,
+			},
 		},
 		ServerInfo: &mcp.MCPImplementation{
 			Name:    mcpServerName,
@@ -120,12 +153,18 @@ func (a *ModelContextProtocolAPI) ListResourcesHandler(c *core.WebContext, jsonR
 		return nil, errs.ErrUserNotFound
 	}
 
-	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
-		return nil, errs.ErrNotPermittedToPerformThisAction
+	resources, err := mcp.Container.GetMCPResources(user)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if resources == nil {
+		resources = make([]*mcp.MCPResource, 0)
 	}
 
 	listResourcesResp := mcp.MCPListResourcesResponse{
-		Resources: make([]*mcp.MCPResource, 0),
+		Resources: resources,
 	}
 
 	return listResourcesResp, nil
@@ -151,11 +190,13 @@ func (a *ModelContextProtocolAPI) ReadResourceHandler(c *core.WebContext, jsonRP
 		return nil, errs.ErrUserNotFound
 	}
 
-	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
-		return nil, errs.ErrNotPermittedToPerformThisAction
+	result, err := mcp.Container.ReadResource(c, &readResourceReq, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	return nil, errs.ErrApiNotFound
+	return result, nil
 }
 
 // ListToolsHandler returns the list of tools for model context protocol
@@ -229,6 +270,62 @@ func (a *ModelContextProtocolAPI) CallToolHandler(c *core.WebContext, jsonRPCReq
 	return result, nil
 }
 
+// ListPromptsHandler returns the list of prompts for model context protocol
+func (a *ModelContextProtocolAPI) ListPromptsHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ListPromptsHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	prompts, err := mcp.Container.GetMCPPrompts(user)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if prompts == nil {
+		prompts = make([]*mcp.MCPPrompt, 0)
+	}
+
+	listPromptsResp := mcp.MCPListPromptsResponse{
+		Prompts: prompts,
+	}
+
+	return listPromptsResp, nil
+}
+
+// GetPromptHandler returns the messages of a specific prompt filled with the arguments for model context protocol
+func (a *ModelContextProtocolAPI) GetPromptHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	var getPromptReq mcp.MCPGetPromptRequest
+
+	if jsonRPCRequest.Params != nil {
+		if err := json.Unmarshal(jsonRPCRequest.Params, &getPromptReq); err != nil {
+			return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.GetPromptHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	result, err := mcp.Container.GetPrompt(c, &getPromptReq, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
+
 // PingHandler return the ping response for model context protocol
 func (a *ModelContextProtocolAPI) PingHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
 	return core.O{}, nil
@@ -249,6 +346,11 @@ func (a *ModelContextProtocolAPI) GetTransactionTagService() *services.Transacti
 	return a.transactionTags
 }
 
+// GetTransactionSearchIndexService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetTransactionSearchIndexService() *services.TransactionSearchIndexService {
+	return a.transactionSearchIndexes
+}
+
 // GetAccountService implements the MCPAvailableServices interface
 func (a *ModelContextProtocolAPI) GetAccountService() *services.AccountService {
 	return a.accounts
@@ -263,3 +365,58 @@ func (a *ModelContextProtocolAPI) GetUserService() *services.UserService {
 func (a *ModelContextProtocolAPI) getMCPVersion(c *core.WebContext) string {
 	return c.GetHeader(mcp.MCPProtocolVersionHeaderName)
 }
+
+// GetReportService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetReportService() *services.ReportService {
+	return a.reports
+}
+
+// GetBudgetService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetBudgetService() *services.BudgetService {
+	return a.budgets
+}
+
+// GetObligationService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetObligationService() *services.ObligationService {
+	return a.obligations
+}
+
+// GetTaxRecordService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetTaxRecordService() *services.TaxRecordService {
+	return a.taxRecords
+}
+
+// GetInvestorDealService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetInvestorDealService() *services.InvestorDealService {
+	return a.investorDeals
+}
+
+// GetAssetService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetAssetService() *services.AssetService {
+	return a.assets
+}
+
+// GetCFOService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetCFOService() *services.CFOService {
+	return a.cfos
+}
+
+// GetCounterpartyService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetCounterpartyService() *services.CounterpartyService {
+	return a.counterparties
+}
+
+// GetInvestorPaymentService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetInvestorPaymentService() *services.InvestorPaymentService {
+	return a.investorPayments
+}
+
+// GetTokenService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetTokenService() *services.TokenService {
+	return a.tokens
+}
+
+// GetMCPAuditLogService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetMCPAuditLogService() *services.MCPAuditLogService {
+	return a.mcpAuditLogs
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// NotificationsApi represents notifications api
+type NotificationsApi struct {
+	ApiUsingConfig
+	notifications services.NotificationProvider
+}
+
+// NewNotificationsApi creates a new NotificationsApi instance
+func NewNotificationsApi(n services.NotificationProvider) *NotificationsApi {
+	return &NotificationsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		notifications: n,
+	}
+}
+
+// Initialize a notifications api singleton instance
+var (
+	NotificationsAPI = NewNotificationsApi(services.Notifications)
+)
+
+// NotificationPreferenceGetHandler returns the notification preference of current user
+func (a *NotificationsApi) NotificationPreferenceGetHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	preference, err := a.notifications.GetNotificationPreference(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[notifications.NotificationPreferenceGetHandler] failed to get notification preference for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return preference.ToNotificationPreferenceInfoResponse(), nil
+}
+
+// NotificationPreferenceSaveHandler saves the notification preference by request parameters for current user
+func (a *NotificationsApi) NotificationPreferenceSaveHandler(c *core.WebContext) (any, *errs.Error) {
+	var preferenceSaveReq models.NotificationPreferenceSaveRequest
+	err := c.ShouldBindJSON(&preferenceSaveReq)
+
+	if err != nil {
+		log.Warnf(c, "[notifications.NotificationPreferenceSaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if (preferenceSaveReq.WeeklyDigestEnabled || preferenceSaveReq.DueRemindersEnabled) && !a.CurrentConfig().EnableSMTP {
+		return nil, errs.ErrSMTPServerNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+
+	preference := &models.NotificationPreference{
+		Uid:                   uid,
+		WeeklyDigestEnabled:   preferenceSaveReq.WeeklyDigestEnabled,
+		WeeklyDigestDayOfWeek: preferenceSaveReq.WeeklyDigestDayOfWeek,
+		DueRemindersEnabled:   preferenceSaveReq.DueRemindersEnabled,
+		ReminderDaysBefore:    preferenceSaveReq.ReminderDaysBefore,
+		TimezoneUtcOffset:     preferenceSaveReq.UtcOffset,
+	}
+
+	err = a.notifications.SaveNotificationPreference(c, preference)
+
+	if err != nil {
+		log.Errorf(c, "[notifications.NotificationPreferenceSaveHandler] failed to save notification preference for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[notifications.NotificationPreferenceSaveHandler] user \"uid:%d\" has saved notification preference successfully", uid)
+
+	savedPreference, err := a.notifications.GetNotificationPreference(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[notifications.NotificationPreferenceSaveHandler] failed to get notification preference for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return savedPreference.ToNotificationPreferenceInfoResponse(), nil
+}
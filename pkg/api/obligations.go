@@ -162,3 +162,117 @@ func (a *ObligationsApi) ObligationDeleteHandler(c *core.WebContext) (any, *errs
 	log.Infof(c, "[obligations.ObligationDeleteHandler] user \"uid:%d\" has deleted obligation \"id:%d\"", uid, obligationDeleteReq.Id)
 	return true, nil
 }
+
+// ObligationPaymentListHandler returns payment list of an obligation of current user
+func (a *ObligationsApi) ObligationPaymentListHandler(c *core.WebContext) (any, *errs.Error) {
+	var paymentListReq models.ObligationPaymentListByObligationRequest
+	err := c.ShouldBindQuery(&paymentListReq)
+
+	if err != nil {
+		log.Warnf(c, "[obligations.ObligationPaymentListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	payments, err := a.obligations.GetAllPaymentsByObligationId(c, uid, paymentListReq.ObligationId)
+
+	if err != nil {
+		log.Errorf(c, "[obligations.ObligationPaymentListHandler] failed to get payments for obligation \"id:%d\" user \"uid:%d\", because %s", paymentListReq.ObligationId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	paymentResps := make(models.ObligationPaymentInfoResponseSlice, len(payments))
+
+	for i := 0; i < len(payments); i++ {
+		paymentResps[i] = payments[i].ToObligationPaymentInfoResponse()
+	}
+
+	sort.Sort(paymentResps)
+
+	return paymentResps, nil
+}
+
+// ObligationStatusHistoryListHandler returns status change list of an obligation of current user
+func (a *ObligationsApi) ObligationStatusHistoryListHandler(c *core.WebContext) (any, *errs.Error) {
+	var historyListReq models.StatusHistoryListRequest
+	err := c.ShouldBindQuery(&historyListReq)
+
+	if err != nil {
+		log.Warnf(c, "[obligations.ObligationStatusHistoryListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	histories, err := a.obligations.GetObligationStatusHistories(c, uid, historyListReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[obligations.ObligationStatusHistoryListHandler] failed to get status histories for obligation \"id:%d\" user \"uid:%d\", because %s", historyListReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	historyResps := make([]*models.StatusHistoryInfoResponse, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		historyResps[i] = histories[i].ToStatusHistoryInfoResponse()
+	}
+
+	return historyResps, nil
+}
+
+// ObligationAllocateHandler allocates a transaction across open obligations of its counterparty for current user
+func (a *ObligationsApi) ObligationAllocateHandler(c *core.WebContext) (any, *errs.Error) {
+	var allocateReq models.ObligationAllocateRequest
+	err := c.ShouldBindJSON(&allocateReq)
+
+	if err != nil {
+		log.Warnf(c, "[obligations.ObligationAllocateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	payments, obligations, err := a.obligations.AllocateTransaction(c, uid, allocateReq.TransactionId, allocateReq.Allocations, allocateReq.Comment)
+
+	if err != nil {
+		log.Errorf(c, "[obligations.ObligationAllocateHandler] failed to allocate transaction \"id:%d\" for user \"uid:%d\", because %s", allocateReq.TransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allocateResp := &models.ObligationAllocateResponse{
+		Payments:    make([]*models.ObligationPaymentInfoResponse, len(payments)),
+		Obligations: make([]*models.ObligationInfoResponse, len(obligations)),
+	}
+
+	for i := 0; i < len(payments); i++ {
+		allocateResp.Payments[i] = payments[i].ToObligationPaymentInfoResponse()
+	}
+
+	for i := 0; i < len(obligations); i++ {
+		allocateResp.Obligations[i] = obligations[i].ToObligationInfoResponse()
+	}
+
+	log.Infof(c, "[obligations.ObligationAllocateHandler] user \"uid:%d\" has allocated transaction \"id:%d\" to %d obligations successfully", uid, allocateReq.TransactionId, len(obligations))
+
+	return allocateResp, nil
+}
+
+// ObligationPaymentDeleteHandler deletes an existed obligation payment by request parameters for current user
+func (a *ObligationsApi) ObligationPaymentDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var paymentDeleteReq models.ObligationPaymentDeleteRequest
+	err := c.ShouldBindJSON(&paymentDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[obligations.ObligationPaymentDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.obligations.DeletePayment(c, uid, paymentDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[obligations.ObligationPaymentDeleteHandler] failed to delete obligation payment \"id:%d\" for user \"uid:%d\", because %s", paymentDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[obligations.ObligationPaymentDeleteHandler] user \"uid:%d\" has deleted obligation payment \"id:%d\"", uid, paymentDeleteReq.Id)
+	return true, nil
+}
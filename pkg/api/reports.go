@@ -1,21 +1,36 @@
 package api
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // ReportsApi represents reports api
 type ReportsApi struct {
-	reports services.ReportProvider
+	ApiUsingConfig
+	reports               services.ReportProvider
+	users                 *services.UserService
+	exchangeRateHistories *services.ExchangeRateHistoryService
 }
 
 // NewReportsApi creates a new ReportsApi instance
 func NewReportsApi(r services.ReportProvider) *ReportsApi {
-	return &ReportsApi{reports: r}
+	return &ReportsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		reports:               r,
+		users:                 services.Users,
+		exchangeRateHistories: services.ExchangeRateHistories,
+	}
 }
 
 // Initialize a reports api singleton instance
@@ -34,7 +49,13 @@ func (a *ReportsApi) CashFlowHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	uid := c.GetCurrentUid()
-	result, err := a.reports.GetCashFlow(c, uid, req.CfoId, req.StartTime, req.EndTime)
+	converter, err := a.getReportCurrencyConverter(c, uid, req.Currency, req.EndTime)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result, err := a.reports.GetCashFlow(c, uid, req.CfoId, req.StartTime, req.EndTime, converter)
 
 	if err != nil {
 		log.Errorf(c, "[reports.CashFlowHandler] failed to get cash flow for user \"uid:%d\", because %s", uid, err.Error())
@@ -55,7 +76,13 @@ func (a *ReportsApi) PnLHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	uid := c.GetCurrentUid()
-	result, err := a.reports.GetPnL(c, uid, req.CfoId, req.StartTime, req.EndTime)
+	converter, err := a.getReportCurrencyConverter(c, uid, req.Currency, req.EndTime)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result, err := a.reports.GetPnL(c, uid, req.CfoId, req.StartTime, req.EndTime, converter)
 
 	if err != nil {
 		log.Errorf(c, "[reports.PnLHandler] failed to get P&L for user \"uid:%d\", because %s", uid, err.Error())
@@ -65,7 +92,7 @@ func (a *ReportsApi) PnLHandler(c *core.WebContext) (any, *errs.Error) {
 	return result, nil
 }
 
-// BalanceHandler returns balance sheet report
+// BalanceHandler returns balance sheet report, or the comparative balance sheet report if the compare as-of time is set
 func (a *ReportsApi) BalanceHandler(c *core.WebContext) (any, *errs.Error) {
 	var req models.BalanceReportRequest
 	err := c.ShouldBindQuery(&req)
@@ -76,7 +103,30 @@ func (a *ReportsApi) BalanceHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	uid := c.GetCurrentUid()
-	result, err := a.reports.GetBalance(c, uid, req.CfoId)
+	converter, err := a.getReportCurrencyConverter(c, uid, req.Currency, req.AsOf)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if req.CompareAsOf > 0 {
+		compareConverter, err := a.getReportCurrencyConverter(c, uid, req.Currency, req.CompareAsOf)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		result, err := a.reports.GetComparativeBalance(c, uid, req.CfoId, req.AsOf, req.CompareAsOf, converter, compareConverter)
+
+		if err != nil {
+			log.Errorf(c, "[reports.BalanceHandler] failed to get comparative balance for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		return result, nil
+	}
+
+	result, err := a.reports.GetBalance(c, uid, req.CfoId, req.AsOf, converter)
 
 	if err != nil {
 		log.Errorf(c, "[reports.BalanceHandler] failed to get balance for user \"uid:%d\", because %s", uid, err.Error())
@@ -97,7 +147,13 @@ func (a *ReportsApi) PaymentCalendarHandler(c *core.WebContext) (any, *errs.Erro
 	}
 
 	uid := c.GetCurrentUid()
-	result, err := a.reports.GetPaymentCalendar(c, uid, req.StartTime, req.EndTime)
+	converter, err := a.getReportCurrencyConverter(c, uid, req.Currency, 0)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result, err := a.reports.GetPaymentCalendar(c, uid, req.StartTime, req.EndTime, converter)
 
 	if err != nil {
 		log.Errorf(c, "[reports.PaymentCalendarHandler] failed to get payment calendar for user \"uid:%d\", because %s", uid, err.Error())
@@ -106,3 +162,95 @@ func (a *ReportsApi) PaymentCalendarHandler(c *core.WebContext) (any, *errs.Erro
 
 	return result, nil
 }
+
+// AgingHandler returns accounts receivable and payable aging report
+func (a *ReportsApi) AgingHandler(c *core.WebContext) (any, *errs.Error) {
+	var req models.AgingReportRequest
+	err := c.ShouldBindQuery(&req)
+
+	if err != nil {
+		log.Warnf(c, "[reports.AgingHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	converter, err := a.getReportCurrencyConverter(c, uid, req.Currency, req.AsOf)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result, err := a.reports.GetAging(c, uid, req.CfoId, req.AsOf, converter)
+
+	if err != nil {
+		log.Errorf(c, "[reports.AgingHandler] failed to get aging report for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
+
+// getReportCurrencyConverter returns the converter from all currencies into the specified reporting currency,
+// the default currency of user is used if the reporting currency is not specified.
+// If the rate time is before today, the exchange rates in effect at that time are used when they exist in the exchange rates history.
+// The transactions in reports are converted by the exchange rates in effect at their own transaction dates when they exist in
+// the exchange rates history, and by the exchange rates at the rate time otherwise
+func (a *ReportsApi) getReportCurrencyConverter(c *core.WebContext, uid int64, reportingCurrency string, rateTime int64) (*services.ReportCurrencyConverter, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[reports.getReportCurrencyConverter] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	if reportingCurrency == "" {
+		reportingCurrency = user.DefaultCurrency
+	}
+
+	var exchangeRates *models.LatestExchangeRateResponse
+
+	if rateTime > 0 {
+		exchangeRates = a.getHistoricalExchangeRates(c, models.GetExchangeRateHistoryDate(utils.ToMillisIfSeconds(rateTime)/1000))
+	}
+
+	if exchangeRates == nil {
+		exchangeRates, err = exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
+
+		if err != nil {
+			log.Warnf(c, "[reports.getReportCurrencyConverter] failed to get latest exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			exchangeRates = nil
+		}
+	}
+
+	converter := services.NewReportCurrencyConverter(reportingCurrency, user.DefaultCurrency, exchangeRates)
+
+	if a.exchangeRateHistories != nil {
+		converter.SetHistoricalExchangeRatesLoader(func(rateDate string) *models.LatestExchangeRateResponse {
+			return a.getHistoricalExchangeRates(c, rateDate)
+		})
+	}
+
+	return converter, nil
+}
+
+// getHistoricalExchangeRates returns the exchange rates in effect on the specified date, or nil if the date is not before today or there is no exchange rates history
+func (a *ReportsApi) getHistoricalExchangeRates(c *core.WebContext, rateDate string) *models.LatestExchangeRateResponse {
+	if a.exchangeRateHistories == nil {
+		return nil
+	}
+
+	if rateDate >= models.GetExchangeRateHistoryDate(time.Now().Unix()) {
+		return nil
+	}
+
+	histories, err := a.exchangeRateHistories.GetAllExchangeRatesByDate(c, a.CurrentConfig().ExchangeRatesDataSource, rateDate)
+
+	if err != nil && err != errs.ErrExchangeRateHistoryNotFound {
+		log.Warnf(c, "[reports.getHistoricalExchangeRates] failed to get exchange rates on %s, because %s", rateDate, err.Error())
+		return nil
+	} else if err != nil {
+		return nil
+	}
+
+	return histories.ToLatestExchangeRateResponse()
+}
@@ -62,6 +62,18 @@ func (a *ServerSettingsApi) ServerSettingsJavascriptHandler(c *core.WebContext)
 		}
 	}
 
+	if config.TransactionImportCategorizationLLMConfig != nil && config.TransactionImportCategorizationLLMConfig.LLMProvider != "" {
+		if config.TransactionImportAICategorization {
+			a.appendBooleanSetting(builder, "llmic", config.TransactionImportAICategorization)
+		}
+	}
+
+	if config.TransactionQueryLLMConfig != nil && config.TransactionQueryLLMConfig.LLMProvider != "" {
+		if config.TransactionNaturalLanguageQuery {
+			a.appendBooleanSetting(builder, "llmq", config.TransactionNaturalLanguageQuery)
+		}
+	}
+
 	if config.LoginPageTips.Enabled {
 		a.appendMultiLanguageTipSetting(builder, "lpt", config.LoginPageTips)
 	}
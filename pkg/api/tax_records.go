@@ -2,6 +2,7 @@ package api
 
 import (
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -13,20 +14,29 @@ import (
 // TaxRecordsApi represents tax records api
 type TaxRecordsApi struct {
 	taxRecords services.TaxRecordProvider
+	taxRegimes services.TaxRegimeProvider
 }
 
 // NewTaxRecordsApi creates a new TaxRecordsApi instance
-func NewTaxRecordsApi(t services.TaxRecordProvider) *TaxRecordsApi {
-	return &TaxRecordsApi{taxRecords: t}
+func NewTaxRecordsApi(t services.TaxRecordProvider, r services.TaxRegimeProvider) *TaxRecordsApi {
+	return &TaxRecordsApi{taxRecords: t, taxRegimes: r}
 }
 
 // Initialize a tax records api singleton instance
 var (
-	TaxRecordsAPI = NewTaxRecordsApi(services.TaxRecords)
+	TaxRecordsAPI = NewTaxRecordsApi(services.TaxRecords, services.TaxRegimes)
 )
 
-// TaxRecordListHandler returns tax record list of current user
+// TaxRecordListHandler returns tax record list of current user, the stored tax amounts are compared with the amounts computed by tax regimes if verify is set
 func (a *TaxRecordsApi) TaxRecordListHandler(c *core.WebContext) (any, *errs.Error) {
+	var taxRecordListReq models.TaxRecordListRequest
+	err := c.ShouldBindQuery(&taxRecordListReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_records.TaxRecordListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
 	uid := c.GetCurrentUid()
 	records, err := a.taxRecords.GetAllTaxRecordsByUid(c, uid)
 
@@ -35,10 +45,26 @@ func (a *TaxRecordsApi) TaxRecordListHandler(c *core.WebContext) (any, *errs.Err
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	var calculations map[int64]*models.TaxCalculationResponse
+
+	if taxRecordListReq.Verify {
+		calculations, err = a.taxRegimes.CalculateTaxRecords(c, uid, records, time.FixedZone("Client Timezone", int(taxRecordListReq.UtcOffset)*60))
+
+		if err != nil {
+			log.Errorf(c, "[tax_records.TaxRecordListHandler] failed to calculate taxes of tax records for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	recordResps := make([]*models.TaxRecordInfoResponse, len(records))
 
 	for i := 0; i < len(records); i++ {
 		recordResps[i] = records[i].ToTaxRecordInfoResponse()
+
+		if calculation, exists := calculations[records[i].TaxId]; exists {
+			recordResps[i].ComputedTaxAmount = &calculation.TaxAmount
+			recordResps[i].Diverged = calculation.Diverged
+		}
 	}
 
 	sort.Slice(recordResps, func(i, j int) bool {
@@ -171,3 +197,79 @@ func (a *TaxRecordsApi) TaxRecordDeleteHandler(c *core.WebContext) (any, *errs.E
 	log.Infof(c, "[tax_records.TaxRecordDeleteHandler] user \"uid:%d\" has deleted tax record \"id:%d\"", uid, taxRecordDeleteReq.Id)
 	return true, nil
 }
+
+// TaxRecordStatusHistoryListHandler returns status change list of a tax record of current user
+func (a *TaxRecordsApi) TaxRecordStatusHistoryListHandler(c *core.WebContext) (any, *errs.Error) {
+	var historyListReq models.StatusHistoryListRequest
+	err := c.ShouldBindQuery(&historyListReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_records.TaxRecordStatusHistoryListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	histories, err := a.taxRecords.GetTaxRecordStatusHistories(c, uid, historyListReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[tax_records.TaxRecordStatusHistoryListHandler] failed to get status histories for tax record \"id:%d\" user \"uid:%d\", because %s", historyListReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	historyResps := make([]*models.StatusHistoryInfoResponse, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		historyResps[i] = histories[i].ToStatusHistoryInfoResponse()
+	}
+
+	return historyResps, nil
+}
+
+// TaxRecordCalculateHandler returns the tax of the period computed by the tax regime of user or CFO with the calculation breakdown for current user
+func (a *TaxRecordsApi) TaxRecordCalculateHandler(c *core.WebContext) (any, *errs.Error) {
+	var taxCalculationReq models.TaxCalculationRequest
+	err := c.ShouldBindQuery(&taxCalculationReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_records.TaxRecordCalculateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	timezone := time.FixedZone("Client Timezone", int(taxCalculationReq.UtcOffset)*60)
+	calculation, err := a.taxRegimes.CalculateTax(c, uid, taxCalculationReq.CfoId, taxCalculationReq.TaxType, taxCalculationReq.PeriodYear, taxCalculationReq.PeriodQuarter, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[tax_records.TaxRecordCalculateHandler] failed to calculate tax of \"cfoId:%d\" for user \"uid:%d\", because %s", taxCalculationReq.CfoId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return calculation, nil
+}
+
+// TaxRecordGenerateHandler saves the tax of the period computed by the tax regime of user or CFO to the tax record of the period for current user
+func (a *TaxRecordsApi) TaxRecordGenerateHandler(c *core.WebContext) (any, *errs.Error) {
+	var taxRecordGenerateReq models.TaxRecordGenerateRequest
+	err := c.ShouldBindJSON(&taxRecordGenerateReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_records.TaxRecordGenerateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	timezone := time.FixedZone("Client Timezone", int(taxRecordGenerateReq.UtcOffset)*60)
+	record, calculation, err := a.taxRegimes.GenerateTaxRecord(c, uid, taxRecordGenerateReq.CfoId, taxRecordGenerateReq.TaxType, taxRecordGenerateReq.PeriodYear, taxRecordGenerateReq.PeriodQuarter, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[tax_records.TaxRecordGenerateHandler] failed to generate tax record of \"cfoId:%d\" for user \"uid:%d\", because %s", taxRecordGenerateReq.CfoId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[tax_records.TaxRecordGenerateHandler] user \"uid:%d\" has generated tax record \"id:%d\" successfully", uid, record.TaxId)
+
+	return &models.TaxRecordGenerateResponse{
+		TaxRecord:   record.ToTaxRecordInfoResponse(),
+		Calculation: calculation,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TaxRegimesApi represents tax regimes api
+type TaxRegimesApi struct {
+	taxRegimes services.TaxRegimeProvider
+}
+
+// NewTaxRegimesApi creates a new TaxRegimesApi instance
+func NewTaxRegimesApi(r services.TaxRegimeProvider) *TaxRegimesApi {
+	return &TaxRegimesApi{taxRegimes: r}
+}
+
+// Initialize a tax regimes api singleton instance
+var (
+	TaxRegimesAPI = NewTaxRegimesApi(services.TaxRegimes)
+)
+
+// TaxRegimeListHandler returns tax regime list of current user
+func (a *TaxRegimesApi) TaxRegimeListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	regimes, err := a.taxRegimes.GetAllTaxRegimesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[tax_regimes.TaxRegimeListHandler] failed to get tax regimes for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	regimeResps := make([]*models.TaxRegimeInfoResponse, len(regimes))
+
+	for i := 0; i < len(regimes); i++ {
+		regimeResps[i] = regimes[i].ToTaxRegimeInfoResponse()
+	}
+
+	return regimeResps, nil
+}
+
+// TaxRegimeSaveHandler saves the tax regime of user or CFO by request parameters for current user
+func (a *TaxRegimesApi) TaxRegimeSaveHandler(c *core.WebContext) (any, *errs.Error) {
+	var taxRegimeSaveReq models.TaxRegimeSaveRequest
+	err := c.ShouldBindJSON(&taxRegimeSaveReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_regimes.TaxRegimeSaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	excludedCategoryIds, err := utils.StringArrayToInt64Array(taxRegimeSaveReq.ExcludedCategoryIds)
+
+	if err != nil {
+		log.Warnf(c, "[tax_regimes.TaxRegimeSaveHandler] parse excluded category ids failed, because %s", err.Error())
+		return nil, errs.ErrTaxRegimeExcludedCategoryIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+
+	regime := &models.TaxRegime{
+		Uid:                  uid,
+		CfoId:                taxRegimeSaveReq.CfoId,
+		RegimeType:           taxRegimeSaveReq.RegimeType,
+		PeriodType:           taxRegimeSaveReq.PeriodType,
+		Rate:                 taxRegimeSaveReq.Rate,
+		MinimumTaxRate:       taxRegimeSaveReq.MinimumTaxRate,
+		DueMonthsAfterPeriod: taxRegimeSaveReq.DueMonthsAfterPeriod,
+		DueDayOfMonth:        taxRegimeSaveReq.DueDayOfMonth,
+		ExcludedCategoryIds:  strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(excludedCategoryIds)), ","),
+		Currency:             taxRegimeSaveReq.Currency,
+	}
+
+	err = a.taxRegimes.SaveTaxRegime(c, regime)
+
+	if err != nil {
+		log.Errorf(c, "[tax_regimes.TaxRegimeSaveHandler] failed to save tax regime of \"cfoId:%d\" for user \"uid:%d\", because %s", regime.CfoId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[tax_regimes.TaxRegimeSaveHandler] user \"uid:%d\" has saved tax regime of \"cfoId:%d\" for tax type %d successfully", uid, regime.CfoId, regime.TaxType)
+
+	return regime.ToTaxRegimeInfoResponse(), nil
+}
+
+// TaxRegimeDeleteHandler deletes the tax regime of user or CFO by request parameters for current user
+func (a *TaxRegimesApi) TaxRegimeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var taxRegimeDeleteReq models.TaxRegimeDeleteRequest
+	err := c.ShouldBindJSON(&taxRegimeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[tax_regimes.TaxRegimeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.taxRegimes.DeleteTaxRegime(c, uid, taxRegimeDeleteReq.CfoId, taxRegimeDeleteReq.TaxType)
+
+	if err != nil {
+		log.Errorf(c, "[tax_regimes.TaxRegimeDeleteHandler] failed to delete tax regime of \"cfoId:%d\" for user \"uid:%d\", because %s", taxRegimeDeleteReq.CfoId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[tax_regimes.TaxRegimeDeleteHandler] user \"uid:%d\" has deleted tax regime of \"cfoId:%d\" for tax type %d", uid, taxRegimeDeleteReq.CfoId, taxRegimeDeleteReq.TaxType)
+	return true, nil
+}
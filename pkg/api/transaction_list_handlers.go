@@ -50,7 +50,7 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		}
 	}
 
-	totalCount, err := a.transactions.GetTransactionCount(c, &models.TransactionQueryParams{
+	queryParams := &models.TransactionQueryParams{
 		Uid:                uid,
 		MaxTransactionTime: utils.ToMillisIfSeconds(transactionCountReq.MaxTime),
 		MinTransactionTime: utils.ToMillisIfSeconds(transactionCountReq.MinTime),
@@ -62,7 +62,16 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		AmountFilter:       transactionCountReq.AmountFilter,
 		Keyword:            transactionCountReq.Keyword,
 		CounterpartyId:     transactionCountReq.CounterpartyId,
-	})
+	}
+
+	err = a.setTransactionAdvancedFilterQueryParams(c, uid, &transactionCountReq.TransactionAdvancedFilterRequest, queryParams)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCountHandler] parse transaction advanced filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalCount, err := a.transactions.GetTransactionCount(c, queryParams)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCountHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -130,30 +139,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
-	var totalCount int64
-
-	if transactionListReq.WithCount {
-		totalCount, err = a.transactions.GetTransactionCount(c, &models.TransactionQueryParams{
-			Uid:                uid,
-			MaxTransactionTime: utils.ToMillisIfSeconds(transactionListReq.MaxTime),
-			MinTransactionTime: utils.ToMillisIfSeconds(transactionListReq.MinTime),
-			TransactionType:    transactionListReq.Type,
-			CategoryIds:        allCategoryIds,
-			AccountIds:         allAccountIds,
-			TagFilters:         tagFilters,
-			NoTags:             noTags,
-			AmountFilter:       transactionListReq.AmountFilter,
-			Keyword:            transactionListReq.Keyword,
-			CounterpartyId:     transactionListReq.CounterpartyId,
-		})
-
-		if err != nil {
-			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.Or(err, errs.ErrOperationFailed)
-		}
-	}
-
-	transactions, err := a.transactions.GetTransactionsByMaxTime(c, &models.TransactionQueryParams{
+	queryParams := &models.TransactionQueryParams{
 		Uid:                uid,
 		MaxTransactionTime: utils.ToMillisIfSeconds(transactionListReq.MaxTime),
 		MinTransactionTime: utils.ToMillisIfSeconds(transactionListReq.MinTime),
@@ -169,7 +155,27 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		Count:              transactionListReq.Count,
 		NeedOneMoreItem:    true,
 		NoDuplicated:       true,
-	})
+	}
+
+	err = a.setTransactionAdvancedFilterQueryParams(c, uid, &transactionListReq.TransactionAdvancedFilterRequest, queryParams)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListHandler] parse transaction advanced filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var totalCount int64
+
+	if transactionListReq.WithCount {
+		totalCount, err = a.transactions.GetTransactionCount(c, queryParams)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, queryParams)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transactions earlier than \"%d\" for user \"uid:%d\", because %s", transactionListReq.MaxTime, uid, err.Error())
@@ -261,7 +267,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, &models.TransactionQueryParams{
+	queryParams := &models.TransactionQueryParams{
 		Uid:             uid,
 		TransactionType: transactionListReq.Type,
 		CategoryIds:     allCategoryIds,
@@ -270,8 +276,17 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		NoTags:          noTags,
 		AmountFilter:    transactionListReq.AmountFilter,
 		Keyword:         transactionListReq.Keyword,
-		CounterpartyId:     transactionListReq.CounterpartyId,
-	})
+		CounterpartyId:  transactionListReq.CounterpartyId,
+	}
+
+	err = a.setTransactionAdvancedFilterQueryParams(c, uid, &transactionListReq.TransactionAdvancedFilterRequest, queryParams)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionMonthListHandler] parse transaction advanced filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, queryParams)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get transactions in month \"%d-%d\" for user \"uid:%d\", because %s", transactionListReq.Year, transactionListReq.Month, uid, err.Error())
@@ -358,7 +373,7 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionAllListReq.StartTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, &models.TransactionQueryParams{
+	queryParams := &models.TransactionQueryParams{
 		Uid:                uid,
 		MaxTransactionTime: maxTransactionTime,
 		MinTransactionTime: minTransactionTime,
@@ -371,7 +386,16 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		Keyword:            transactionAllListReq.Keyword,
 		CounterpartyId:     transactionAllListReq.CounterpartyId,
 		NoDuplicated:       true,
-	}, pageCountForDataExport)
+	}
+
+	err = a.setTransactionAdvancedFilterQueryParams(c, uid, &transactionAllListReq.TransactionAdvancedFilterRequest, queryParams)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListAllHandler] parse transaction advanced filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, queryParams, pageCountForDataExport)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListAllHandler] failed to get all transactions for user \"uid:%d\", because %s", uid, err.Error())
@@ -823,3 +847,84 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 
 	return result, nil
 }
+
+// setTransactionAdvancedFilterQueryParams parses the advanced filters in request and sets them to the transaction query params,
+// the category ids and account ids in condition groups are expanded to include their sub categories and sub accounts
+func (a *TransactionsApi) setTransactionAdvancedFilterQueryParams(c core.Context, uid int64, filterReq *models.TransactionAdvancedFilterRequest, queryParams *models.TransactionQueryParams) error {
+	geoBounds, err := models.ParseTransactionGeoBoundsFilter(filterReq.GeoBounds)
+
+	if err != nil {
+		return err
+	}
+
+	commentFilter, err := models.ParseTransactionCommentFilter(filterReq.CommentFilter)
+
+	if err != nil {
+		return err
+	}
+
+	conditionGroupReqs, err := models.ParseTransactionQueryConditionGroupRequests(filterReq.ConditionGroups)
+
+	if err != nil {
+		return err
+	}
+
+	searchTerms, err := a.transactionSearchIndexes.GetTransactionSearchTerms(c, uid, filterReq.Search)
+
+	if err != nil {
+		return err
+	}
+
+	conditionGroups := make([]*models.TransactionQueryConditionGroup, len(conditionGroupReqs))
+
+	for i := 0; i < len(conditionGroupReqs); i++ {
+		conditionGroupReq := conditionGroupReqs[i]
+
+		groupAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, conditionGroupReq.AccountIds, uid)
+
+		if err != nil {
+			return err
+		}
+
+		groupCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, conditionGroupReq.CategoryIds, uid)
+
+		if err != nil {
+			return err
+		}
+
+		groupCommentFilter, err := models.ParseTransactionCommentFilter(conditionGroupReq.CommentFilter)
+
+		if err != nil {
+			return err
+		}
+
+		groupGeoBounds, err := models.ParseTransactionGeoBoundsFilter(conditionGroupReq.GeoBounds)
+
+		if err != nil {
+			return err
+		}
+
+		conditionGroups[i] = &models.TransactionQueryConditionGroup{
+			TransactionType:  conditionGroupReq.Type,
+			CategoryIds:      groupCategoryIds,
+			AccountIds:       groupAccountIds,
+			AmountFilter:     conditionGroupReq.AmountFilter,
+			CommentFilter:    groupCommentFilter,
+			CounterpartyId:   conditionGroupReq.CounterpartyId,
+			CfoId:            conditionGroupReq.CfoId,
+			PlannedFilter:    conditionGroupReq.PlannedFilter,
+			SourceTemplateId: conditionGroupReq.SourceTemplateId,
+			GeoBounds:        groupGeoBounds,
+		}
+	}
+
+	queryParams.CfoId = filterReq.CfoId
+	queryParams.PlannedFilter = filterReq.PlannedFilter
+	queryParams.SourceTemplateId = filterReq.SourceTemplateId
+	queryParams.GeoBounds = geoBounds
+	queryParams.CommentFilter = commentFilter
+	queryParams.ConditionGroups = conditionGroups
+	queryParams.SearchTerms = searchTerms
+
+	return nil
+}
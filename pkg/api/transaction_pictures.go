@@ -127,6 +127,11 @@ func (a *TransactionPicturesApi) TransactionPictureGetHandler(c *core.WebContext
 	fileExtension := utils.GetFileNameExtension(fileName)
 	contentType := utils.GetImageContentType(fileExtension)
 
+	// the receipt documents recognized by ai are also saved as transaction pictures
+	if contentType == "" {
+		contentType = utils.GetDocumentContentType(fileExtension)
+	}
+
 	if contentType == "" {
 		return nil, "", errs.ErrImageTypeNotSupported
 	}
@@ -12,16 +12,17 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionTagGroups  *services.TransactionTagGroupService
-	transactionPictures   *services.TransactionPictureService
-	transactionTemplates  *services.TransactionTemplateService
-	transactionSplits     *services.TransactionSplitService
-	accounts              *services.AccountService
-	counterparties        *services.CounterpartyService
-	users                 *services.UserService
+	transactions             *services.TransactionService
+	transactionCategories    *services.TransactionCategoryService
+	transactionTags          *services.TransactionTagService
+	transactionTagGroups     *services.TransactionTagGroupService
+	transactionPictures      *services.TransactionPictureService
+	transactionTemplates     *services.TransactionTemplateService
+	transactionSplits        *services.TransactionSplitService
+	transactionSearchIndexes *services.TransactionSearchIndexService
+	accounts                 *services.AccountService
+	counterparties           *services.CounterpartyService
+	users                    *services.UserService
 }
 
 // Initialize a transaction api singleton instance
@@ -36,15 +37,16 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionTagGroups:  services.TransactionTagGroups,
-		transactionPictures:   services.TransactionPictures,
-		transactionTemplates:  services.TransactionTemplates,
-		transactionSplits:     services.TransactionSplits,
-		accounts:              services.Accounts,
-		counterparties:        services.Counterparties,
-		users:                 services.Users,
+		transactions:             services.Transactions,
+		transactionCategories:    services.TransactionCategories,
+		transactionTags:          services.TransactionTags,
+		transactionTagGroups:     services.TransactionTagGroups,
+		transactionPictures:      services.TransactionPictures,
+		transactionTemplates:     services.TransactionTemplates,
+		transactionSplits:        services.TransactionSplits,
+		transactionSearchIndexes: services.TransactionSearchIndexes,
+		accounts:                 services.Accounts,
+		counterparties:           services.Counterparties,
+		users:                    services.Users,
 	}
 )
@@ -26,6 +26,7 @@ type UserDataCli struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	searchIndexes           *services.TransactionSearchIndexService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
@@ -42,6 +43,7 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		searchIndexes:           services.TransactionSearchIndexes,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
@@ -749,6 +751,30 @@ func (l *UserDataCli) FixTransactionTagIndexWithTransactionTime(c *core.CliConte
 	return true, nil
 }
 
+// RebuildTransactionSearchIndex rebuilds user transaction search index data, returns the count of indexed transactions
+func (l *UserDataCli) RebuildTransactionSearchIndex(c *core.CliContext, username string) (int64, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RebuildTransactionSearchIndex] user name is empty")
+		return 0, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RebuildTransactionSearchIndex] error occurs when getting user id by user name")
+		return 0, err
+	}
+
+	count, err := l.searchIndexes.RebuildTransactionSearchIndexes(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RebuildTransactionSearchIndex] failed to rebuild transaction search index for user \"%s\", because %s", username, err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // ExportTransaction returns csv file content according user all transactions
 func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fileType string) ([]byte, error) {
 	if username == "" {
@@ -0,0 +1,252 @@
+package beancount
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const beancountExportedCfoMetadataKey = "cfo"
+const beancountExportedUncategorizedName = "Uncategorized"
+
+// beancountTransactionDataExporter defines the structure of beancount exporter for transaction data
+type beancountTransactionDataExporter struct{}
+
+// Initialize a beancount transaction data exporter singleton instance
+var (
+	BeancountTransactionDataExporter = &beancountTransactionDataExporter{}
+)
+
+// beancountExportedPosting represents a posting line of exported beancount transaction
+type beancountExportedPosting struct {
+	account       string
+	amount        int64
+	currency      string
+	totalCost     int64
+	totalCostUnit string
+	hasTotalCost  bool
+}
+
+// ToExportedContent returns the exported beancount transaction data
+func (e *beancountTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	return e.ToExportedContentWithRelatedData(ctx, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+}
+
+// ToExportedContentWithRelatedData returns the exported beancount transaction data, the counterparty is exported as payee, the cfo as metadata and the splits as postings
+func (e *beancountTransactionDataExporter) ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) ([]byte, error) {
+	var entries bytes.Buffer
+	openedAccounts := make([]string, 0)
+	openedAccountDates := make(map[string]string)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		postings := e.getPostings(transaction, accountMap, categoryMap, relatedData)
+
+		if len(postings) < 1 {
+			continue
+		}
+
+		date := converter.GetTransactionLocalTime(transaction).Format("2006-01-02")
+
+		for j := 0; j < len(postings); j++ {
+			if openDate, exists := openedAccountDates[postings[j].account]; !exists || date < openDate {
+				if !exists {
+					openedAccounts = append(openedAccounts, postings[j].account)
+				}
+
+				openedAccountDates[postings[j].account] = date
+			}
+		}
+
+		entries.WriteString(fmt.Sprintf("%s * \"%s\" \"%s\"", date, e.escape(relatedData.GetCounterpartyName(transaction.CounterpartyId)), e.escape(transaction.Comment)))
+
+		for _, tagName := range converter.GetTransactionTagNames(transaction.TransactionId, allTagIndexes, tagMap) {
+			entries.WriteString(" #" + e.normalizeTagName(tagName))
+		}
+
+		entries.WriteString("\n")
+
+		if cfoName := relatedData.GetCfoName(transaction.CfoId); cfoName != "" {
+			entries.WriteString(fmt.Sprintf("  %s: \"%s\"\n", beancountExportedCfoMetadataKey, e.escape(cfoName)))
+		}
+
+		for j := 0; j < len(postings); j++ {
+			posting := postings[j]
+
+			if posting.hasTotalCost {
+				entries.WriteString(fmt.Sprintf("  %s %s %s @@ %s %s\n", posting.account, utils.FormatAmount(posting.amount), posting.currency, utils.FormatAmount(posting.totalCost), posting.totalCostUnit))
+			} else {
+				entries.WriteString(fmt.Sprintf("  %s %s %s\n", posting.account, utils.FormatAmount(posting.amount), posting.currency))
+			}
+		}
+
+		entries.WriteString("\n")
+	}
+
+	var buf bytes.Buffer
+
+	for i := 0; i < len(openedAccounts); i++ {
+		buf.WriteString(fmt.Sprintf("%s %s %s\n", openedAccountDates[openedAccounts[i]], beancountDirectiveOpen, openedAccounts[i]))
+	}
+
+	if len(openedAccounts) > 0 {
+		buf.WriteString("\n")
+	}
+
+	buf.Write(entries.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func (e *beancountTransactionDataExporter) getPostings(transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, relatedData *converter.TransactionDataExportRelatedData) []*beancountExportedPosting {
+	sourceAccount := e.getAccountName(transaction.AccountId, accountMap)
+	sourceCurrency := converter.GetAccountCurrency(transaction.AccountId, accountMap)
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return []*beancountExportedPosting{
+			{account: sourceAccount, amount: transaction.RelatedAccountAmount, currency: sourceCurrency},
+			{account: "Equity:" + beancountEquityAccountNameOpeningBalance, amount: -transaction.RelatedAccountAmount, currency: sourceCurrency},
+		}
+	case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE:
+		categoryPrefix := "Income"
+		sign := int64(-1)
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			categoryPrefix = "Expenses"
+			sign = 1
+		}
+
+		postings := make([]*beancountExportedPosting, 0, 2)
+		splits := relatedData.GetSplits(transaction.TransactionId)
+
+		if len(splits) > 0 {
+			for i := 0; i < len(splits); i++ {
+				postings = append(postings, &beancountExportedPosting{
+					account:  e.getCategoryName(categoryPrefix, splits[i].CategoryId, categoryMap),
+					amount:   sign * splits[i].Amount,
+					currency: sourceCurrency,
+				})
+			}
+		} else {
+			postings = append(postings, &beancountExportedPosting{
+				account:  e.getCategoryName(categoryPrefix, transaction.CategoryId, categoryMap),
+				amount:   sign * transaction.Amount,
+				currency: sourceCurrency,
+			})
+		}
+
+		return append(postings, &beancountExportedPosting{
+			account:  sourceAccount,
+			amount:   -sign * transaction.Amount,
+			currency: sourceCurrency,
+		})
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		destinationCurrency := converter.GetAccountCurrency(transaction.RelatedAccountId, accountMap)
+		destinationPosting := &beancountExportedPosting{
+			account:  e.getAccountName(transaction.RelatedAccountId, accountMap),
+			amount:   transaction.RelatedAccountAmount,
+			currency: destinationCurrency,
+		}
+
+		if destinationCurrency != sourceCurrency {
+			destinationPosting.hasTotalCost = true
+			destinationPosting.totalCost = transaction.Amount
+			destinationPosting.totalCostUnit = sourceCurrency
+		}
+
+		return []*beancountExportedPosting{
+			{account: sourceAccount, amount: -transaction.Amount, currency: sourceCurrency},
+			destinationPosting,
+		}
+	default:
+		return nil
+	}
+}
+
+func (e *beancountTransactionDataExporter) getAccountName(accountId int64, accountMap map[int64]*models.Account) string {
+	prefix := "Assets"
+
+	if converter.IsLiabilityAccount(accountId, accountMap) {
+		prefix = "Liabilities"
+	}
+
+	return prefix + ":" + e.normalizeAccountNameItem(converter.GetAccountName(accountId, accountMap))
+}
+
+func (e *beancountTransactionDataExporter) getCategoryName(prefix string, categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	categoryName := converter.GetCategoryName(categoryId, categoryMap)
+
+	if categoryName == "" {
+		categoryName = beancountExportedUncategorizedName
+	}
+
+	if parentCategoryName := converter.GetParentCategoryName(categoryId, categoryMap); parentCategoryName != "" {
+		return prefix + ":" + e.normalizeAccountNameItem(parentCategoryName) + ":" + e.normalizeAccountNameItem(categoryName)
+	}
+
+	return prefix + ":" + e.normalizeAccountNameItem(categoryName)
+}
+
+// normalizeAccountNameItem returns a valid beancount account name component, which must start with an uppercase letter or digit and cannot contain spaces or colons
+func (e *beancountTransactionDataExporter) normalizeAccountNameItem(name string) string {
+	var builder strings.Builder
+
+	for _, ch := range strings.TrimSpace(name) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '-' {
+			builder.WriteRune(ch)
+		} else {
+			builder.WriteRune('-')
+		}
+	}
+
+	runes := []rune(builder.String())
+
+	if len(runes) < 1 {
+		return beancountExportedUncategorizedName
+	}
+
+	if !unicode.IsDigit(runes[0]) {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+
+	if !unicode.IsUpper(runes[0]) && !unicode.IsDigit(runes[0]) {
+		return "X" + string(runes)
+	}
+
+	return string(runes)
+}
+
+func (e *beancountTransactionDataExporter) normalizeTagName(name string) string {
+	var builder strings.Builder
+
+	for _, ch := range strings.TrimSpace(name) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '-' || ch == '_' || ch == '/' || ch == '.' {
+			builder.WriteRune(ch)
+		} else {
+			builder.WriteRune('-')
+		}
+	}
+
+	return builder.String()
+}
+
+func (e *beancountTransactionDataExporter) escape(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	value = strings.ReplaceAll(value, "\r", "")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	return value
+}
@@ -0,0 +1,131 @@
+package beancount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestBeancountTransactionDataExporter_ToExportedContentWithRelatedData(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD", Category: models.ACCOUNT_CATEGORY_CASH},
+		2: {AccountId: 2, Name: "Credit card", Currency: "EUR", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		11: {CategoryId: 11, Name: "Dining", ParentCategoryId: 10},
+		12: {CategoryId: 12, Name: "Grocery", ParentCategoryId: 10},
+		20: {CategoryId: 20, Name: "Salary"},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "trip"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1002: {100},
+	}
+	relatedData := &converter.TransactionDataExportRelatedData{
+		CounterpartyMap: map[int64]*models.Counterparty{
+			500: {CounterpartyId: 500, Name: "Cafe \"Blue\""},
+		},
+		CfoMap: map[int64]*models.CFO{
+			600: {CfoId: 600, Name: "Shop"},
+		},
+		AllSplits: map[int64][]*models.TransactionSplit{
+			1002: {
+				{TransactionId: 1002, CategoryId: 11, Amount: 700},
+				{TransactionId: 1002, CategoryId: 12, Amount: 300},
+			},
+		},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CategoryId: 20, Amount: 10000, TransactionTime: 1725148800000, Comment: "September"},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 11, Amount: 1000, TransactionTime: 1725235200000, CounterpartyId: 500, CfoId: 600},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 1100, RelatedAccountAmount: 1000, TransactionTime: 1725321600000},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, RelatedAccountId: 1, Amount: 1000, RelatedAccountAmount: 1100, TransactionTime: 1725321600000},
+	}
+
+	content, err := exporter.ToExportedContentWithRelatedData(context, 1234567890, transactions, accountMap, categoryMap, tagMap, allTagIndexes, relatedData)
+	assert.Nil(t, err)
+
+	expectedContent := "2024-09-01 open Income:Salary\n" +
+		"2024-09-01 open Assets:Cash\n" +
+		"2024-09-02 open Expenses:Food:Dining\n" +
+		"2024-09-02 open Expenses:Food:Grocery\n" +
+		"2024-09-03 open Liabilities:Credit-card\n" +
+		"\n" +
+		"2024-09-01 * \"\" \"September\"\n" +
+		"  Income:Salary -100.00 USD\n" +
+		"  Assets:Cash 100.00 USD\n" +
+		"\n" +
+		"2024-09-02 * \"Cafe \\\"Blue\\\"\" \"\" #trip\n" +
+		"  cfo: \"Shop\"\n" +
+		"  Expenses:Food:Dining 7.00 USD\n" +
+		"  Expenses:Food:Grocery 3.00 USD\n" +
+		"  Assets:Cash -10.00 USD\n" +
+		"\n" +
+		"2024-09-03 * \"\" \"\"\n" +
+		"  Assets:Cash -11.00 USD\n" +
+		"  Liabilities:Credit-card 10.00 EUR @@ 11.00 USD\n" +
+		"\n"
+
+	assert.Equal(t, expectedContent, string(content))
+}
+
+func TestBeancountTransactionDataExporter_ExportedContentCanBeImported(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD"},
+		2: {AccountId: 2, Name: "Bank", Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		20: {CategoryId: 20, Name: "Salary"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1000, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, Amount: 5000, RelatedAccountAmount: 5000, TransactionTime: 1725062400000},
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CategoryId: 20, Amount: 10000, TransactionTime: 1725148800000},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 10, Amount: 1000, TransactionTime: 1725235200000},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 500, RelatedAccountAmount: 500, TransactionTime: 1725321600000},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, _, err := BeancountTransactionDataImporter.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(5000), allNewTransactions[0].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Income:Salary", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Expenses:Food", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(500), allNewTransactions[3].Amount)
+	assert.Equal(t, "Assets:Cash", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Assets:Bank", allNewTransactions[3].OriginalDestinationAccountName)
+}
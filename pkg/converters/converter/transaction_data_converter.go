@@ -13,6 +13,21 @@ type TransactionDataExporter interface {
 	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error)
 }
 
+// TransactionDataExportRelatedData represents the data related to exported transactions which is not passed to the basic exporter
+type TransactionDataExportRelatedData struct {
+	CounterpartyMap map[int64]*models.Counterparty
+	CfoMap          map[int64]*models.CFO
+	AllSplits       map[int64][]*models.TransactionSplit
+}
+
+// TransactionDataRelatedDataExporter defines the structure of transaction data exporter which can also export splits, counterparties and cfos
+type TransactionDataRelatedDataExporter interface {
+	TransactionDataExporter
+
+	// ToExportedContentWithRelatedData returns the exported data including the related data
+	ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *TransactionDataExportRelatedData) ([]byte, error)
+}
+
 // TransactionDataImporter defines the structure of transaction data importer
 type TransactionDataImporter interface {
 	// ParseImportedData returns the imported data
@@ -0,0 +1,133 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// GetCounterpartyName returns the name of the specified counterparty, or empty string if it does not exist
+func (d *TransactionDataExportRelatedData) GetCounterpartyName(counterpartyId int64) string {
+	if d == nil || counterpartyId <= 0 {
+		return ""
+	}
+
+	counterparty, exists := d.CounterpartyMap[counterpartyId]
+
+	if !exists {
+		return ""
+	}
+
+	return counterparty.Name
+}
+
+// GetCfoName returns the name of the specified cfo, or empty string if it does not exist
+func (d *TransactionDataExportRelatedData) GetCfoName(cfoId int64) string {
+	if d == nil || cfoId <= 0 {
+		return ""
+	}
+
+	cfo, exists := d.CfoMap[cfoId]
+
+	if !exists {
+		return ""
+	}
+
+	return cfo.Name
+}
+
+// GetSplits returns the split parts of the specified transaction
+func (d *TransactionDataExportRelatedData) GetSplits(transactionId int64) []*models.TransactionSplit {
+	if d == nil {
+		return nil
+	}
+
+	return d.AllSplits[transactionId]
+}
+
+// GetTransactionTagNames returns the names of all tags of the specified transaction
+func GetTransactionTagNames(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIds, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tagNames := make([]string, 0, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		tag, exists := tagMap[tagIds[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	return tagNames
+}
+
+// GetCategoryName returns the name of the specified category, or empty string if it does not exist
+func GetCategoryName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return ""
+	}
+
+	return category.Name
+}
+
+// GetParentCategoryName returns the name of the parent category of the specified category, or empty string if it does not exist
+func GetParentCategoryName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists || category.ParentCategoryId <= 0 {
+		return ""
+	}
+
+	return GetCategoryName(category.ParentCategoryId, categoryMap)
+}
+
+// GetAccountName returns the name of the specified account, or empty string if it does not exist
+func GetAccountName(accountId int64, accountMap map[int64]*models.Account) string {
+	account, exists := accountMap[accountId]
+
+	if !exists {
+		return ""
+	}
+
+	return account.Name
+}
+
+// GetAccountCurrency returns the currency of the specified account, or empty string if it does not exist
+func GetAccountCurrency(accountId int64, accountMap map[int64]*models.Account) string {
+	account, exists := accountMap[accountId]
+
+	if !exists {
+		return ""
+	}
+
+	return account.Currency
+}
+
+// GetTransactionLocalTime returns the transaction time in the timezone of the transaction
+func GetTransactionLocalTime(transaction *models.Transaction) time.Time {
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+
+	return time.Unix(transactionUnixTime, 0).In(transactionTimeZone)
+}
+
+// IsLiabilityAccount returns whether the specified account is a credit card or debt account
+func IsLiabilityAccount(accountId int64, accountMap map[int64]*models.Account) bool {
+	account, exists := accountMap[accountId]
+
+	if !exists {
+		return false
+	}
+
+	return account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD || account.Category == models.ACCOUNT_CATEGORY_DEBT
+}
@@ -0,0 +1,213 @@
+package ledger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerExportedOpeningBalanceAccountName = "Equity:Opening Balances"
+const ledgerExportedUncategorizedName = "Uncategorized"
+
+// ledgerTransactionDataExporter defines the structure of ledger-cli exporter for transaction data
+type ledgerTransactionDataExporter struct{}
+
+// Initialize a ledger-cli transaction data exporter singleton instance
+var (
+	LedgerTransactionDataExporter = &ledgerTransactionDataExporter{}
+)
+
+// ledgerExportedPosting represents a posting line of exported ledger-cli transaction
+type ledgerExportedPosting struct {
+	account       string
+	amount        int64
+	currency      string
+	totalCost     int64
+	totalCostUnit string
+	hasTotalCost  bool
+	elideAmount   bool
+}
+
+// ToExportedContent returns the exported ledger-cli transaction data
+func (e *ledgerTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	return e.ToExportedContentWithRelatedData(ctx, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+}
+
+// ToExportedContentWithRelatedData returns the exported ledger-cli transaction data, the counterparty is exported as payee, the cfo as metadata tag and the splits as postings
+func (e *ledgerTransactionDataExporter) ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		postings := e.getPostings(transaction, accountMap, categoryMap, relatedData)
+
+		if len(postings) < 1 {
+			continue
+		}
+
+		payee := relatedData.GetCounterpartyName(transaction.CounterpartyId)
+
+		if payee == "" {
+			payee = transaction.Comment
+		}
+
+		buf.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", converter.GetTransactionLocalTime(transaction).Format("2006/01/02"), e.escape(payee)), " ") + "\n")
+
+		if transaction.Comment != "" && transaction.Comment != payee {
+			buf.WriteString(fmt.Sprintf("    ; %s\n", e.escape(transaction.Comment)))
+		}
+
+		if tagNames := converter.GetTransactionTagNames(transaction.TransactionId, allTagIndexes, tagMap); len(tagNames) > 0 {
+			for j := 0; j < len(tagNames); j++ {
+				tagNames[j] = e.normalizeTagName(tagNames[j])
+			}
+
+			buf.WriteString(fmt.Sprintf("    ; :%s:\n", strings.Join(tagNames, ":")))
+		}
+
+		if cfoName := relatedData.GetCfoName(transaction.CfoId); cfoName != "" {
+			buf.WriteString(fmt.Sprintf("    ; CFO: %s\n", e.escape(cfoName)))
+		}
+
+		for j := 0; j < len(postings); j++ {
+			posting := postings[j]
+
+			if posting.elideAmount {
+				buf.WriteString(fmt.Sprintf("    %s\n", posting.account))
+			} else if posting.hasTotalCost {
+				buf.WriteString(fmt.Sprintf("    %s  %s %s @@ %s %s\n", posting.account, utils.FormatAmount(posting.amount), posting.currency, utils.FormatAmount(posting.totalCost), posting.totalCostUnit))
+			} else {
+				buf.WriteString(fmt.Sprintf("    %s  %s %s\n", posting.account, utils.FormatAmount(posting.amount), posting.currency))
+			}
+		}
+
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *ledgerTransactionDataExporter) getPostings(transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, relatedData *converter.TransactionDataExportRelatedData) []*ledgerExportedPosting {
+	sourceAccount := e.getAccountName(transaction.AccountId, accountMap)
+	sourceCurrency := converter.GetAccountCurrency(transaction.AccountId, accountMap)
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return []*ledgerExportedPosting{
+			{account: sourceAccount, amount: transaction.RelatedAccountAmount, currency: sourceCurrency},
+			{account: ledgerExportedOpeningBalanceAccountName, elideAmount: true},
+		}
+	case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE:
+		categoryPrefix := "Income"
+		sign := int64(-1)
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			categoryPrefix = "Expenses"
+			sign = 1
+		}
+
+		postings := make([]*ledgerExportedPosting, 0, 2)
+		splits := relatedData.GetSplits(transaction.TransactionId)
+
+		if len(splits) > 0 {
+			for i := 0; i < len(splits); i++ {
+				postings = append(postings, &ledgerExportedPosting{
+					account:  e.getCategoryName(categoryPrefix, splits[i].CategoryId, categoryMap),
+					amount:   sign * splits[i].Amount,
+					currency: sourceCurrency,
+				})
+			}
+		} else {
+			postings = append(postings, &ledgerExportedPosting{
+				account:  e.getCategoryName(categoryPrefix, transaction.CategoryId, categoryMap),
+				amount:   sign * transaction.Amount,
+				currency: sourceCurrency,
+			})
+		}
+
+		return append(postings, &ledgerExportedPosting{
+			account:  sourceAccount,
+			amount:   -sign * transaction.Amount,
+			currency: sourceCurrency,
+		})
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		destinationCurrency := converter.GetAccountCurrency(transaction.RelatedAccountId, accountMap)
+		destinationPosting := &ledgerExportedPosting{
+			account:  e.getAccountName(transaction.RelatedAccountId, accountMap),
+			amount:   transaction.RelatedAccountAmount,
+			currency: destinationCurrency,
+		}
+
+		if destinationCurrency != sourceCurrency {
+			destinationPosting.hasTotalCost = true
+			destinationPosting.totalCost = transaction.Amount
+			destinationPosting.totalCostUnit = sourceCurrency
+		}
+
+		return []*ledgerExportedPosting{
+			{account: sourceAccount, amount: -transaction.Amount, currency: sourceCurrency},
+			destinationPosting,
+		}
+	default:
+		return nil
+	}
+}
+
+func (e *ledgerTransactionDataExporter) getAccountName(accountId int64, accountMap map[int64]*models.Account) string {
+	prefix := "Assets"
+
+	if converter.IsLiabilityAccount(accountId, accountMap) {
+		prefix = "Liabilities"
+	}
+
+	return prefix + ":" + e.normalizeAccountNameItem(converter.GetAccountName(accountId, accountMap))
+}
+
+func (e *ledgerTransactionDataExporter) getCategoryName(prefix string, categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	categoryName := converter.GetCategoryName(categoryId, categoryMap)
+
+	if categoryName == "" {
+		categoryName = ledgerExportedUncategorizedName
+	}
+
+	if parentCategoryName := converter.GetParentCategoryName(categoryId, categoryMap); parentCategoryName != "" {
+		return prefix + ":" + e.normalizeAccountNameItem(parentCategoryName) + ":" + e.normalizeAccountNameItem(categoryName)
+	}
+
+	return prefix + ":" + e.normalizeAccountNameItem(categoryName)
+}
+
+// normalizeAccountNameItem returns a valid ledger-cli account name component, which cannot contain colons, tabs or consecutive spaces
+func (e *ledgerTransactionDataExporter) normalizeAccountNameItem(name string) string {
+	name = strings.ReplaceAll(name, ":", " ")
+	name = strings.Join(strings.Fields(name), " ")
+
+	if name == "" {
+		return ledgerExportedUncategorizedName
+	}
+
+	return name
+}
+
+func (e *ledgerTransactionDataExporter) normalizeTagName(name string) string {
+	name = strings.ReplaceAll(name, ":", "-")
+
+	return strings.Join(strings.Fields(name), "-")
+}
+
+func (e *ledgerTransactionDataExporter) escape(value string) string {
+	value = strings.ReplaceAll(value, "\r", "")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	return value
+}
@@ -0,0 +1,76 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestLedgerTransactionDataExporter_ToExportedContentWithRelatedData(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD", Category: models.ACCOUNT_CATEGORY_CASH},
+		2: {AccountId: 2, Name: "Credit: Card", Currency: "EUR", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		11: {CategoryId: 11, Name: "Dining", ParentCategoryId: 10},
+		12: {CategoryId: 12, Name: "Grocery", ParentCategoryId: 10},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "trip"},
+		101: {TagId: 101, Name: "summer 2024"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1002: {100, 101},
+	}
+	relatedData := &converter.TransactionDataExportRelatedData{
+		CounterpartyMap: map[int64]*models.Counterparty{
+			500: {CounterpartyId: 500, Name: "Cafe"},
+		},
+		CfoMap: map[int64]*models.CFO{
+			600: {CfoId: 600, Name: "Shop"},
+		},
+		AllSplits: map[int64][]*models.TransactionSplit{
+			1002: {
+				{TransactionId: 1002, CategoryId: 11, Amount: 700},
+				{TransactionId: 1002, CategoryId: 12, Amount: 300},
+			},
+		},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1000, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, Amount: 5000, RelatedAccountAmount: 5000, TransactionTime: 1725148800000},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 11, Amount: 1000, TransactionTime: 1725235200000, CounterpartyId: 500, CfoId: 600, Comment: "Lunch"},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 1100, RelatedAccountAmount: 1000, TransactionTime: 1725321600000, Comment: "Repay"},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, RelatedAccountId: 1, Amount: 1000, RelatedAccountAmount: 1100, TransactionTime: 1725321600000, Comment: "Repay"},
+	}
+
+	content, err := exporter.ToExportedContentWithRelatedData(context, 1234567890, transactions, accountMap, categoryMap, tagMap, allTagIndexes, relatedData)
+	assert.Nil(t, err)
+
+	expectedContent := "2024/09/01 *\n" +
+		"    Assets:Cash  50.00 USD\n" +
+		"    Equity:Opening Balances\n" +
+		"\n" +
+		"2024/09/02 * Cafe\n" +
+		"    ; Lunch\n" +
+		"    ; :trip:summer-2024:\n" +
+		"    ; CFO: Shop\n" +
+		"    Expenses:Food:Dining  7.00 USD\n" +
+		"    Expenses:Food:Grocery  3.00 USD\n" +
+		"    Assets:Cash  -10.00 USD\n" +
+		"\n" +
+		"2024/09/03 * Repay\n" +
+		"    Assets:Cash  -11.00 USD\n" +
+		"    Liabilities:Credit Card  10.00 EUR @@ 11.00 USD\n" +
+		"\n"
+
+	assert.Equal(t, expectedContent, string(content))
+}
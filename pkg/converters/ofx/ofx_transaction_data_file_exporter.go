@@ -0,0 +1,151 @@
+package ofx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ofxExportedFileHeader = "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n" +
+	"<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"
+
+// ofxTransactionDataExporter defines the structure of open financial exchange (ofx) exporter for transaction data
+type ofxTransactionDataExporter struct{}
+
+// Initialize an open financial exchange (ofx) transaction data exporter singleton instance
+var (
+	OFXTransactionDataExporter = &ofxTransactionDataExporter{}
+)
+
+// ofxExportedAccountStatement represents all exported transactions of one account
+type ofxExportedAccountStatement struct {
+	accountId    int64
+	transactions []*models.Transaction
+}
+
+// ToExportedContent returns the exported open financial exchange (ofx) transaction data
+func (e *ofxTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	return e.ToExportedContentWithRelatedData(ctx, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+}
+
+// ToExportedContentWithRelatedData returns the exported open financial exchange (ofx) transaction data, the counterparty is exported as payee name
+func (e *ofxTransactionDataExporter) ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) ([]byte, error) {
+	statements := make([]*ofxExportedAccountStatement, 0)
+	statementMap := make(map[int64]*ofxExportedAccountStatement)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		statement, exists := statementMap[transaction.AccountId]
+
+		if !exists {
+			statement = &ofxExportedAccountStatement{
+				accountId: transaction.AccountId,
+			}
+
+			statementMap[transaction.AccountId] = statement
+			statements = append(statements, statement)
+		}
+
+		statement.transactions = append(statement.transactions, transaction)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(ofxExportedFileHeader)
+	buf.WriteString("<OFX>\n")
+	buf.WriteString("<BANKMSGSRSV1>\n")
+
+	for i := 0; i < len(statements); i++ {
+		statement := statements[i]
+
+		buf.WriteString("<STMTTRNRS>\n")
+		buf.WriteString("<STMTRS>\n")
+		buf.WriteString(fmt.Sprintf("<CURDEF>%s</CURDEF>\n", e.escape(converter.GetAccountCurrency(statement.accountId, accountMap))))
+		buf.WriteString("<BANKACCTFROM>\n")
+		buf.WriteString(fmt.Sprintf("<ACCTID>%s</ACCTID>\n", e.escape(converter.GetAccountName(statement.accountId, accountMap))))
+		buf.WriteString("</BANKACCTFROM>\n")
+		buf.WriteString("<BANKTRANLIST>\n")
+
+		for j := 0; j < len(statement.transactions); j++ {
+			e.writeTransaction(&buf, statement.transactions[j], accountMap, relatedData)
+		}
+
+		buf.WriteString("</BANKTRANLIST>\n")
+		buf.WriteString("</STMTRS>\n")
+		buf.WriteString("</STMTTRNRS>\n")
+	}
+
+	buf.WriteString("</BANKMSGSRSV1>\n")
+	buf.WriteString("</OFX>\n")
+
+	return buf.Bytes(), nil
+}
+
+func (e *ofxTransactionDataExporter) writeTransaction(buf *bytes.Buffer, transaction *models.Transaction, accountMap map[int64]*models.Account, relatedData *converter.TransactionDataExportRelatedData) {
+	var transactionType ofxTransactionType
+	amount := transaction.Amount
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		transactionType = ofxOtherTransaction
+		amount = transaction.RelatedAccountAmount
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		transactionType = ofxDepositTransaction
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		transactionType = ofxGenericDebitTransaction
+		amount = -amount
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		transactionType = ofxTransferTransaction
+		amount = -amount
+	case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		transactionType = ofxTransferTransaction
+	default:
+		return
+	}
+
+	buf.WriteString("<STMTTRN>\n")
+	buf.WriteString(fmt.Sprintf("<TRNTYPE>%s</TRNTYPE>\n", transactionType))
+	buf.WriteString(fmt.Sprintf("<DTPOSTED>%s</DTPOSTED>\n", e.formatDateTime(transaction)))
+	buf.WriteString(fmt.Sprintf("<TRNAMT>%s</TRNAMT>\n", utils.FormatAmount(amount)))
+	buf.WriteString(fmt.Sprintf("<FITID>%d</FITID>\n", transaction.TransactionId))
+
+	if counterpartyName := relatedData.GetCounterpartyName(transaction.CounterpartyId); counterpartyName != "" {
+		buf.WriteString(fmt.Sprintf("<NAME>%s</NAME>\n", e.escape(counterpartyName)))
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		buf.WriteString("<BANKACCTTO>\n")
+		buf.WriteString(fmt.Sprintf("<ACCTID>%s</ACCTID>\n", e.escape(converter.GetAccountName(transaction.RelatedAccountId, accountMap))))
+		buf.WriteString("</BANKACCTTO>\n")
+	}
+
+	if transaction.Comment != "" {
+		buf.WriteString(fmt.Sprintf("<MEMO>%s</MEMO>\n", e.escape(transaction.Comment)))
+	}
+
+	buf.WriteString("</STMTTRN>\n")
+}
+
+func (e *ofxTransactionDataExporter) formatDateTime(transaction *models.Transaction) string {
+	localTime := converter.GetTransactionLocalTime(transaction)
+	hoursOffset := strconv.FormatFloat(float64(transaction.TimezoneUtcOffset)/60, 'f', -1, 64)
+
+	if transaction.TimezoneUtcOffset >= 0 {
+		hoursOffset = "+" + hoursOffset
+	}
+
+	return fmt.Sprintf("%s.000[%s:GMT]", localTime.Format("20060102150405"), hoursOffset)
+}
+
+func (e *ofxTransactionDataExporter) escape(value string) string {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(value))
+
+	return builder.String()
+}
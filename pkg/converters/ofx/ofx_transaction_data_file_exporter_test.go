@@ -0,0 +1,85 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestOFXTransactionDataExporter_ToExportedContentWithRelatedData(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash & Card", Currency: "USD"},
+	}
+	relatedData := &converter.TransactionDataExportRelatedData{
+		CounterpartyMap: map[int64]*models.Counterparty{
+			500: {CounterpartyId: 500, Name: "Cafe"},
+		},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Amount: 1000, TransactionTime: 1725235200000, TimezoneUtcOffset: 480, CounterpartyId: 500, Comment: "Lunch <with> team"},
+	}
+
+	content, err := exporter.ToExportedContentWithRelatedData(context, 1234567890, transactions, accountMap, nil, nil, nil, relatedData)
+	assert.Nil(t, err)
+
+	actualContent := string(content)
+	assert.True(t, strings.HasPrefix(actualContent, ofxExportedFileHeader))
+	assert.Contains(t, actualContent, "<CURDEF>USD</CURDEF>\n<BANKACCTFROM>\n<ACCTID>Cash &amp; Card</ACCTID>\n")
+	assert.Contains(t, actualContent, "<STMTTRN>\n"+
+		"<TRNTYPE>DEBIT</TRNTYPE>\n"+
+		"<DTPOSTED>20240902080000.000[+8:GMT]</DTPOSTED>\n"+
+		"<TRNAMT>-10.00</TRNAMT>\n"+
+		"<FITID>1002</FITID>\n"+
+		"<NAME>Cafe</NAME>\n"+
+		"<MEMO>Lunch &lt;with&gt; team</MEMO>\n"+
+		"</STMTTRN>\n")
+}
+
+func TestOFXTransactionDataExporter_ExportedContentCanBeImported(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, Amount: 10000, TransactionTime: 1725148800000},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Amount: 1000, TransactionTime: 1725235200000, Comment: "Lunch"},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := OFXTransactionDataImporter.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+	assert.Equal(t, "Cash", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Lunch", allNewTransactions[1].Comment)
+}
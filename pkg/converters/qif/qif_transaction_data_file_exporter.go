@@ -0,0 +1,138 @@
+package qif
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const qifExportedOpeningBalancePayee = "Opening Balance"
+
+// qifTransactionDataExporter defines the structure of quicken interchange format (qif) exporter for transaction data
+type qifTransactionDataExporter struct{}
+
+// Initialize a quicken interchange format (qif) transaction data exporter singleton instance
+var (
+	QifTransactionDataExporter = &qifTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported quicken interchange format (qif) transaction data
+func (e *qifTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	return e.ToExportedContentWithRelatedData(ctx, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+}
+
+// ToExportedContentWithRelatedData returns the exported quicken interchange format (qif) transaction data, the counterparty is exported as payee, the cfo as class and the splits as split lines
+func (e *qifTransactionDataExporter) ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) ([]byte, error) {
+	accountIds := make([]int64, 0)
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountTransactions[transaction.AccountId]; !exists {
+			accountIds = append(accountIds, transaction.AccountId)
+		}
+
+		accountTransactions[transaction.AccountId] = append(accountTransactions[transaction.AccountId], transaction)
+	}
+
+	var buf bytes.Buffer
+
+	for i := 0; i < len(accountIds); i++ {
+		accountId := accountIds[i]
+
+		buf.WriteString("!Account\n")
+		buf.WriteString("N" + e.escape(converter.GetAccountName(accountId, accountMap)) + "\n")
+		buf.WriteString("TBank\n")
+		buf.WriteString("^\n")
+		buf.WriteString("!Type:Bank\n")
+
+		for _, transaction := range accountTransactions[accountId] {
+			e.writeTransaction(&buf, transaction, accountMap, categoryMap, relatedData)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *qifTransactionDataExporter) writeTransaction(buf *bytes.Buffer, transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, relatedData *converter.TransactionDataExportRelatedData) {
+	amount := transaction.Amount
+	payee := relatedData.GetCounterpartyName(transaction.CounterpartyId)
+	category := ""
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		payee = qifExportedOpeningBalancePayee
+		category = "[" + e.escape(converter.GetAccountName(transaction.AccountId, accountMap)) + "]"
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		category = e.getCategoryWithClass(transaction.CategoryId, transaction.CfoId, categoryMap, relatedData)
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		amount = -amount
+		category = e.getCategoryWithClass(transaction.CategoryId, transaction.CfoId, categoryMap, relatedData)
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		amount = -amount
+		category = "[" + e.escape(converter.GetAccountName(transaction.RelatedAccountId, accountMap)) + "]"
+	default:
+		return
+	}
+
+	buf.WriteString("D" + converter.GetTransactionLocalTime(transaction).Format("2006-01-02") + "\n")
+	buf.WriteString("T" + utils.FormatAmount(amount) + "\n")
+
+	if payee != "" {
+		buf.WriteString("P" + e.escape(payee) + "\n")
+	}
+
+	if transaction.Comment != "" {
+		buf.WriteString("M" + e.escape(transaction.Comment) + "\n")
+	}
+
+	buf.WriteString("L" + category + "\n")
+
+	splits := relatedData.GetSplits(transaction.TransactionId)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		for i := 0; i < len(splits); i++ {
+			splitAmount := splits[i].Amount
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				splitAmount = -splitAmount
+			}
+
+			buf.WriteString("S" + e.getCategoryWithClass(splits[i].CategoryId, transaction.CfoId, categoryMap, relatedData) + "\n")
+			buf.WriteString("$" + utils.FormatAmount(splitAmount) + "\n")
+		}
+	}
+
+	buf.WriteString("^\n")
+}
+
+func (e *qifTransactionDataExporter) getCategoryWithClass(categoryId int64, cfoId int64, categoryMap map[int64]*models.TransactionCategory, relatedData *converter.TransactionDataExportRelatedData) string {
+	category := e.escape(converter.GetCategoryName(categoryId, categoryMap))
+
+	if parentCategory := converter.GetParentCategoryName(categoryId, categoryMap); parentCategory != "" {
+		category = e.escape(parentCategory) + ":" + category
+	}
+
+	if cfoName := relatedData.GetCfoName(cfoId); cfoName != "" {
+		category = category + "/" + e.escape(cfoName)
+	}
+
+	return category
+}
+
+func (e *qifTransactionDataExporter) escape(value string) string {
+	value = strings.ReplaceAll(value, "\r", "")
+	value = strings.ReplaceAll(value, "\n", " ")
+	value = strings.ReplaceAll(value, "/", " ")
+
+	return value
+}
@@ -0,0 +1,119 @@
+package qif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestQifTransactionDataExporter_ToExportedContentWithRelatedData(t *testing.T) {
+	exporter := QifTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD"},
+		2: {AccountId: 2, Name: "Bank", Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		11: {CategoryId: 11, Name: "Dining", ParentCategoryId: 10},
+		12: {CategoryId: 12, Name: "Grocery", ParentCategoryId: 10},
+	}
+	relatedData := &converter.TransactionDataExportRelatedData{
+		CounterpartyMap: map[int64]*models.Counterparty{
+			500: {CounterpartyId: 500, Name: "Cafe"},
+		},
+		CfoMap: map[int64]*models.CFO{
+			600: {CfoId: 600, Name: "Shop"},
+		},
+		AllSplits: map[int64][]*models.TransactionSplit{
+			1002: {
+				{TransactionId: 1002, CategoryId: 11, Amount: 700},
+				{TransactionId: 1002, CategoryId: 12, Amount: 300},
+			},
+		},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 11, Amount: 1000, TransactionTime: 1725235200000, CounterpartyId: 500, CfoId: 600, Comment: "Lunch"},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 500, RelatedAccountAmount: 500, TransactionTime: 1725321600000},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, RelatedAccountId: 1, Amount: 500, RelatedAccountAmount: 500, TransactionTime: 1725321600000},
+	}
+
+	content, err := exporter.ToExportedContentWithRelatedData(context, 1234567890, transactions, accountMap, categoryMap, nil, nil, relatedData)
+	assert.Nil(t, err)
+
+	expectedContent := "!Account\n" +
+		"NCash\n" +
+		"TBank\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D2024-09-02\n" +
+		"T-10.00\n" +
+		"PCafe\n" +
+		"MLunch\n" +
+		"LFood:Dining/Shop\n" +
+		"SFood:Dining/Shop\n" +
+		"$-7.00\n" +
+		"SFood:Grocery/Shop\n" +
+		"$-3.00\n" +
+		"^\n" +
+		"D2024-09-03\n" +
+		"T-5.00\n" +
+		"L[Bank]\n" +
+		"^\n"
+
+	assert.Equal(t, expectedContent, string(content))
+}
+
+func TestQifTransactionDataExporter_ExportedContentCanBeImported(t *testing.T) {
+	exporter := QifTransactionDataExporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD"},
+		2: {AccountId: 2, Name: "Bank", Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		20: {CategoryId: 20, Name: "Salary"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CategoryId: 20, Amount: 10000, TransactionTime: 1725148800000},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 10, Amount: 1000, TransactionTime: 1725235200000, Comment: "Lunch"},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 500, RelatedAccountAmount: 500, TransactionTime: 1725321600000},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, _, err := QifYearMonthDayTransactionDataImporter.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Salary", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Food", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Lunch", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(500), allNewTransactions[2].Amount)
+	assert.Equal(t, "Cash", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Bank", allNewTransactions[2].OriginalDestinationAccountName)
+}
@@ -1,22 +1,105 @@
 package converters
 
 import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/alipay"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/camt"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	_default "github.com/mayswind/ezbookkeeping/pkg/converters/default"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/dengioperacii"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/feidee"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/fireflyIII"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/gnucash"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/iif"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/jdcom"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ledger"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/mt"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/wechat"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/xlsx"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
+// transactionDataImporters maps each supported import file type to its importer
+var transactionDataImporters = map[string]converter.TransactionDataImporter{
+	"custom_csv":                   CustomCSVImporter,
+	"ezbookkeeping_csv":            _default.DefaultTransactionDataCSVFileConverter,
+	"ezbookkeeping_tsv":            _default.DefaultTransactionDataTSVFileConverter,
+	"ezbookkeeping_json":           _default.DefaultTransactionDataJsonFileImporter,
+	"ofx":                          ofx.OFXTransactionDataImporter,
+	"qfx":                          ofx.OFXTransactionDataImporter,
+	"qif_ymd":                      qif.QifYearMonthDayTransactionDataImporter,
+	"qif_mdy":                      qif.QifMonthDayYearTransactionDataImporter,
+	"qif_dmy":                      qif.QifDayMonthYearTransactionDataImporter,
+	"iif":                          iif.IifTransactionDataFileImporter,
+	"camt052":                      camt.Camt052TransactionDataImporter,
+	"camt053":                      camt.Camt053TransactionDataImporter,
+	"mt940":                        mt.MT940TransactionDataFileImporter,
+	"gnucash":                      gnucash.GnuCashTransactionDataImporter,
+	"firefly_iii_csv":              fireflyIII.FireflyIIITransactionDataCsvFileImporter,
+	"beancount":                    beancount.BeancountTransactionDataImporter,
+	"feidee_mymoney_csv":           feidee.FeideeMymoneyAppTransactionDataCsvFileImporter,
+	"feidee_mymoney_xls":           feidee.FeideeMymoneyWebTransactionDataXlsFileImporter,
+	"feidee_mymoney_elecloud_xlsx": feidee.FeideeMymoneyElecloudTransactionDataXlsxFileImporter,
+	"alipay_app_csv":               alipay.AlipayAppTransactionDataCsvFileImporter,
+	"alipay_web_csv":               alipay.AlipayWebTransactionDataCsvFileImporter,
+	"wechat_pay_app_csv":           wechat.WeChatPayTransactionDataCsvFileImporter,
+	"wechat_pay_app_xlsx":          wechat.WeChatPayTransactionDataXlsxFileImporter,
+	"jdcom_finance_app_csv":        jdcom.JDComFinanceTransactionDataCsvFileImporter,
+	"dengioperacii_xlsx":           dengioperacii.DengioperaciiTransactionDataXlsxFileImporter,
+}
+
+// transactionDataExporters maps each supported export file type to its exporter
+var transactionDataExporters = map[string]converter.TransactionDataExporter{
+	"ezbookkeeping_csv": _default.DefaultTransactionDataCSVFileConverter,
+	"ezbookkeeping_tsv": _default.DefaultTransactionDataTSVFileConverter,
+	"ofx":               ofx.OFXTransactionDataExporter,
+	"qif":               qif.QifTransactionDataExporter,
+	"beancount":         beancount.BeancountTransactionDataExporter,
+	"ledger":            ledger.LedgerTransactionDataExporter,
+	"xlsx":              xlsx.TransactionDataXlsxFileExporter,
+}
+
 // GetTransactionDataExporter returns the transaction data exporter according to the file type
 func GetTransactionDataExporter(fileType string) converter.TransactionDataExporter {
-	return nil
+	exporter, exists := transactionDataExporters[fileType]
+
+	if !exists {
+		return nil
+	}
+
+	return exporter
 }
 
 // GetTransactionDataImporter returns the transaction data importer according to the file type
 func GetTransactionDataImporter(fileType string) (converter.TransactionDataImporter, error) {
-	if fileType == "custom_csv" {
-		return CustomCSVImporter, nil
+	if fileType == "" {
+		return nil, errs.ErrImportFileTypeIsEmpty
 	}
 
-	return nil, errs.ErrImportFileTypeNotSupported
+	importer, exists := transactionDataImporters[fileType]
+
+	if !exists {
+		return nil, errs.ErrImportFileTypeNotSupported
+	}
+
+	return importer, nil
+}
+
+// GetAllSupportedImportFileTypes returns all the file types which have a registered importer
+func GetAllSupportedImportFileTypes() []string {
+	fileTypes := make([]string, 0, len(transactionDataImporters))
+
+	for fileType := range transactionDataImporters {
+		fileTypes = append(fileTypes, fileType)
+	}
+
+	sort.Strings(fileTypes)
+
+	return fileTypes
 }
 
 // IsCustomDelimiterSeparatedValuesFileType returns whether the file type is the delimiter-separated values file type
@@ -0,0 +1,62 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
+	_default "github.com/mayswind/ezbookkeeping/pkg/converters/default"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ledger"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/xlsx"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestGetTransactionDataImporter_AllSupportedFileTypes(t *testing.T) {
+	fileTypes := GetAllSupportedImportFileTypes()
+	assert.NotEmpty(t, fileTypes)
+
+	for _, fileType := range fileTypes {
+		importer, err := GetTransactionDataImporter(fileType)
+		assert.Nil(t, err, fileType)
+		assert.NotNil(t, importer, fileType)
+	}
+}
+
+func TestGetTransactionDataImporter_KnownFileType(t *testing.T) {
+	importer, err := GetTransactionDataImporter("ofx")
+	assert.Nil(t, err)
+	assert.Equal(t, ofx.OFXTransactionDataImporter, importer)
+
+	importer, err = GetTransactionDataImporter("custom_csv")
+	assert.Nil(t, err)
+	assert.Equal(t, CustomCSVImporter, importer)
+}
+
+func TestGetTransactionDataImporter_EmptyFileType(t *testing.T) {
+	importer, err := GetTransactionDataImporter("")
+	assert.Nil(t, importer)
+	assert.EqualError(t, err, errs.ErrImportFileTypeIsEmpty.Message)
+}
+
+func TestGetTransactionDataImporter_UnknownFileType(t *testing.T) {
+	importer, err := GetTransactionDataImporter("foo_bar")
+	assert.Nil(t, importer)
+	assert.EqualError(t, err, errs.ErrImportFileTypeNotSupported.Message)
+}
+
+func TestGetTransactionDataExporter_KnownFileType(t *testing.T) {
+	assert.Equal(t, ofx.OFXTransactionDataExporter, GetTransactionDataExporter("ofx"))
+	assert.Equal(t, qif.QifTransactionDataExporter, GetTransactionDataExporter("qif"))
+	assert.Equal(t, beancount.BeancountTransactionDataExporter, GetTransactionDataExporter("beancount"))
+	assert.Equal(t, ledger.LedgerTransactionDataExporter, GetTransactionDataExporter("ledger"))
+	assert.Equal(t, xlsx.TransactionDataXlsxFileExporter, GetTransactionDataExporter("xlsx"))
+	assert.Equal(t, _default.DefaultTransactionDataCSVFileConverter, GetTransactionDataExporter("ezbookkeeping_csv"))
+}
+
+func TestGetTransactionDataExporter_UnknownFileType(t *testing.T) {
+	assert.Nil(t, GetTransactionDataExporter(""))
+	assert.Nil(t, GetTransactionDataExporter("foo_bar"))
+}
@@ -0,0 +1,210 @@
+package xlsx
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const xlsxExportedSheetName = "Transactions"
+const xlsxExportedSplitRowTypeName = "Split"
+
+var xlsxExportedHeaders = []string{
+	"Time",
+	"Type",
+	"Category",
+	"Parent Category",
+	"Account",
+	"Currency",
+	"Amount",
+	"Related Account",
+	"Related Account Currency",
+	"Related Amount",
+	"Counterparty",
+	"CFO",
+	"Tags",
+	"Description",
+}
+
+var xlsxExportedTransactionTypeNames = map[models.TransactionDbType]string{
+	models.TRANSACTION_DB_TYPE_MODIFY_BALANCE: "Balance Modification",
+	models.TRANSACTION_DB_TYPE_INCOME:         "Income",
+	models.TRANSACTION_DB_TYPE_EXPENSE:        "Expense",
+	models.TRANSACTION_DB_TYPE_TRANSFER_OUT:   "Transfer",
+}
+
+// transactionDataXlsxFileExporter defines the structure of xlsx exporter for transaction data
+type transactionDataXlsxFileExporter struct{}
+
+// Initialize a xlsx transaction data exporter singleton instance
+var (
+	TransactionDataXlsxFileExporter = &transactionDataXlsxFileExporter{}
+)
+
+// ToExportedContent returns the exported xlsx transaction data
+func (e *transactionDataXlsxFileExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	return e.ToExportedContentWithRelatedData(ctx, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+}
+
+// ToExportedContentWithRelatedData returns the exported xlsx transaction data, the split parts are exported as separate rows below the transaction
+func (e *transactionDataXlsxFileExporter) ToExportedContentWithRelatedData(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	err := file.SetSheetName(file.GetSheetName(0), xlsxExportedSheetName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	streamWriter, err := file.NewStreamWriter(xlsxExportedSheetName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowIndex := 1
+	err = e.writeRow(streamWriter, rowIndex, e.toCells(xlsxExportedHeaders))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		rowIndex++
+		err = e.writeRow(streamWriter, rowIndex, e.getTransactionRow(transaction, accountMap, categoryMap, tagMap, allTagIndexes, relatedData))
+
+		if err != nil {
+			return nil, err
+		}
+
+		splits := relatedData.GetSplits(transaction.TransactionId)
+
+		for j := 0; j < len(splits); j++ {
+			rowIndex++
+			err = e.writeRow(streamWriter, rowIndex, e.getSplitRow(transaction, splits[j], accountMap, categoryMap, tagMap))
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = streamWriter.Flush()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_, err = file.WriteTo(&buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *transactionDataXlsxFileExporter) getTransactionRow(transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, relatedData *converter.TransactionDataExportRelatedData) []any {
+	amount := transaction.Amount
+	relatedAccountName := ""
+	relatedAccountCurrency := ""
+	var relatedAmount any = ""
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		amount = transaction.RelatedAccountAmount
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		amount = -amount
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		relatedAccountName = converter.GetAccountName(transaction.RelatedAccountId, accountMap)
+		relatedAccountCurrency = converter.GetAccountCurrency(transaction.RelatedAccountId, accountMap)
+		relatedAmount = e.toAmountValue(transaction.RelatedAccountAmount)
+	}
+
+	return []any{
+		converter.GetTransactionLocalTime(transaction).Format("2006-01-02 15:04:05"),
+		xlsxExportedTransactionTypeNames[transaction.Type],
+		converter.GetCategoryName(transaction.CategoryId, categoryMap),
+		converter.GetParentCategoryName(transaction.CategoryId, categoryMap),
+		converter.GetAccountName(transaction.AccountId, accountMap),
+		converter.GetAccountCurrency(transaction.AccountId, accountMap),
+		e.toAmountValue(amount),
+		relatedAccountName,
+		relatedAccountCurrency,
+		relatedAmount,
+		relatedData.GetCounterpartyName(transaction.CounterpartyId),
+		relatedData.GetCfoName(transaction.CfoId),
+		strings.Join(converter.GetTransactionTagNames(transaction.TransactionId, allTagIndexes, tagMap), ";"),
+		transaction.Comment,
+	}
+}
+
+func (e *transactionDataXlsxFileExporter) getSplitRow(transaction *models.Transaction, split *models.TransactionSplit, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag) []any {
+	amount := split.Amount
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		amount = -amount
+	}
+
+	tagNames := make([]string, 0)
+
+	for _, tagId := range split.GetTagIdSlice() {
+		if tag, exists := tagMap[tagId]; exists {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+
+	return []any{
+		"",
+		xlsxExportedSplitRowTypeName,
+		converter.GetCategoryName(split.CategoryId, categoryMap),
+		converter.GetParentCategoryName(split.CategoryId, categoryMap),
+		converter.GetAccountName(transaction.AccountId, accountMap),
+		converter.GetAccountCurrency(transaction.AccountId, accountMap),
+		e.toAmountValue(amount),
+		"",
+		"",
+		"",
+		"",
+		"",
+		strings.Join(tagNames, ";"),
+		"",
+	}
+}
+
+func (e *transactionDataXlsxFileExporter) writeRow(streamWriter *excelize.StreamWriter, rowIndex int, values []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, rowIndex)
+
+	if err != nil {
+		return err
+	}
+
+	return streamWriter.SetRow(cell, values)
+}
+
+func (e *transactionDataXlsxFileExporter) toCells(values []string) []any {
+	cells := make([]any, len(values))
+
+	for i := 0; i < len(values); i++ {
+		cells[i] = values[i]
+	}
+
+	return cells
+}
+
+func (e *transactionDataXlsxFileExporter) toAmountValue(amount int64) float64 {
+	return float64(amount) / 100
+}
@@ -0,0 +1,72 @@
+package xlsx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionDataXlsxFileExporter_ToExportedContentWithRelatedData(t *testing.T) {
+	exporter := TransactionDataXlsxFileExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Currency: "USD"},
+		2: {AccountId: 2, Name: "Bank", Currency: "EUR"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food"},
+		11: {CategoryId: 11, Name: "Dining", ParentCategoryId: 10},
+		12: {CategoryId: 12, Name: "Grocery", ParentCategoryId: 10},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "trip"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1002: {100},
+	}
+	relatedData := &converter.TransactionDataExportRelatedData{
+		CounterpartyMap: map[int64]*models.Counterparty{
+			500: {CounterpartyId: 500, Name: "Cafe"},
+		},
+		CfoMap: map[int64]*models.CFO{
+			600: {CfoId: 600, Name: "Shop"},
+		},
+		AllSplits: map[int64][]*models.TransactionSplit{
+			1002: {
+				{TransactionId: 1002, CategoryId: 11, Amount: 700, TagIds: "100"},
+				{TransactionId: 1002, CategoryId: 12, Amount: 300},
+			},
+		},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CategoryId: 11, Amount: 1000, TransactionTime: 1725235200000, CounterpartyId: 500, CfoId: 600, Comment: "Lunch"},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Amount: 1100, RelatedAccountAmount: 1000, TransactionTime: 1725321600000},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, RelatedAccountId: 1, Amount: 1000, RelatedAccountAmount: 1100, TransactionTime: 1725321600000},
+	}
+
+	content, err := exporter.ToExportedContentWithRelatedData(context, 1234567890, transactions, accountMap, categoryMap, tagMap, allTagIndexes, relatedData)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows(xlsxExportedSheetName)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(rows))
+	assert.Equal(t, xlsxExportedHeaders, rows[0])
+	assert.Equal(t, []string{"2024-09-02 00:00:00", "Expense", "Dining", "Food", "Cash", "USD", "-10", "", "", "", "Cafe", "Shop", "trip", "Lunch"}, rows[1])
+	assert.Equal(t, []string{"", "Split", "Dining", "Food", "Cash", "USD", "-7", "", "", "", "", "", "trip"}, rows[2])
+	assert.Equal(t, []string{"", "Split", "Grocery", "Food", "Cash", "USD", "-3"}, rows[3])
+	assert.Equal(t, []string{"2024-09-03 00:00:00", "Transfer", "", "", "Cash", "USD", "11", "Bank", "EUR", "10"}, rows[4])
+}
@@ -84,6 +84,22 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 	if config.EnableCreateScheduledTransaction {
 		Container.registerIntervalJob(ctx, CreateScheduledTransactionJob)
 	}
+
+	if config.EnableSaveExchangeRatesHistory && config.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource {
+		Container.registerIntervalJob(ctx, SaveExchangeRatesHistoryJob)
+	}
+
+	if config.EnablePostAssetDepreciation {
+		Container.registerIntervalJob(ctx, PostAssetDepreciationJob)
+	}
+
+	if config.EnableUpdateOverdueStatuses {
+		Container.registerIntervalJob(ctx, UpdateOverdueStatusesJob)
+	}
+
+	if config.EnableSendNotifications && config.EnableSMTP {
+		Container.registerIntervalJob(ctx, SendNotificationsJob)
+	}
 }
 
 func (c *CronJobSchedulerContainer) registerIntervalJob(ctx core.Context, job *CronJob) {
@@ -23,6 +23,12 @@ type CronJobFixedHourPeriod struct {
 	Hour uint32
 }
 
+// CronJobFixedDayOfMonthPeriod represents the period of execution at fixed hour of fixed day of every month
+type CronJobFixedDayOfMonthPeriod struct {
+	Day  uint32
+	Hour uint32
+}
+
 // CronJobEvery15MinutesPeriod represents the period of execution at every 15 minutes
 type CronJobEvery15MinutesPeriod struct {
 	Second uint32
@@ -58,6 +64,22 @@ func (p CronJobFixedHourPeriod) ToJobDefinition() gocron.JobDefinition {
 	)
 }
 
+// GetInterval returns the interval time of the period of CronJobFixedDayOfMonthPeriod, which is the length of the shortest month
+func (p CronJobFixedDayOfMonthPeriod) GetInterval() time.Duration {
+	return 28 * 24 * time.Hour
+}
+
+// ToJobDefinition returns the gocron job definition of the period of CronJobFixedDayOfMonthPeriod
+func (p CronJobFixedDayOfMonthPeriod) ToJobDefinition() gocron.JobDefinition {
+	return gocron.MonthlyJob(
+		1,
+		gocron.NewDaysOfTheMonth(int(p.Day)),
+		gocron.NewAtTimes(
+			gocron.NewAtTime(uint(p.Hour), 0, 0),
+		),
+	)
+}
+
 // GetInterval returns the interval time of the period of CronJobEvery15MinutesPeriod
 func (p CronJobEvery15MinutesPeriod) GetInterval() time.Duration {
 	return 15 * time.Minute
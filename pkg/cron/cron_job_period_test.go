@@ -97,6 +97,57 @@ func TestCronJobNextRunTimeWithFixedHourPeriod(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestCronJobNextRunTimeWithFixedDayOfMonthPeriod(t *testing.T) {
+	scheduler, err := gocron.NewScheduler(
+		gocron.WithLocation(time.Local),
+	)
+	assert.Nil(t, err)
+
+	job := CronJob{
+		Name:        "TestCronJobWithFixedDayOfMonthPeriod",
+		Description: "The test cron job",
+		Period: CronJobFixedDayOfMonthPeriod{
+			Day:  1,
+			Hour: 1,
+		},
+		Run: func(c *core.CronContext) error {
+			return nil
+		},
+	}
+
+	assert.Equal(t, 28*24*time.Hour, job.Period.GetInterval())
+
+	gocronJob, err := scheduler.NewJob(
+		job.Period.ToJobDefinition(),
+		gocron.NewTask(job.doRun),
+		gocron.WithName(job.Name),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	assert.Nil(t, err)
+
+	scheduler.Start()
+
+	nextRunTime, err := gocronJob.NextRun()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	expectedRunTime := time.Date(now.Year(), now.Month(), 1, 1, 0, 0, 0, time.Local)
+
+	if !expectedRunTime.After(now) {
+		expectedRunTime = expectedRunTime.AddDate(0, 1, 0)
+	}
+
+	assert.Equal(t, expectedRunTime.Year(), nextRunTime.Year())
+	assert.Equal(t, expectedRunTime.Month(), nextRunTime.Month())
+	assert.Equal(t, 1, nextRunTime.Day())
+	assert.Equal(t, 1, nextRunTime.Hour())
+	assert.Equal(t, 0, nextRunTime.Minute())
+	assert.Equal(t, 0, nextRunTime.Second())
+
+	err = scheduler.Shutdown()
+	assert.Nil(t, err)
+}
+
 func TestCronJobNextRunTimeWithEvery15MinutesPeriod(t *testing.T) {
 	scheduler, err := gocron.NewScheduler(
 		gocron.WithLocation(time.Local),
@@ -4,7 +4,10 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -30,3 +33,73 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// PostAssetDepreciationJob represents the cron job which monthly posts the depreciation of fixed assets as transactions
+var PostAssetDepreciationJob = &CronJob{
+	Name:        "PostAssetDepreciation",
+	Description: "Monthly post the depreciation of fixed assets of the last month as transactions.",
+	Period: CronJobFixedDayOfMonthPeriod{
+		Day:  1,
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.Assets.PostAssetDepreciations(c, time.Now().Unix())
+	},
+}
+
+// UpdateOverdueStatusesJob represents the cron job which daily updates the overdue statuses of tax records and obligations according to their due dates
+var UpdateOverdueStatusesJob = &CronJob{
+	Name:        "UpdateOverdueStatuses",
+	Description: "Daily mark unpaid tax records and obligations past due date as overdue, and mark the paid ones as paid.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 0,
+	},
+	Run: func(c *core.CronContext) error {
+		currentUnixTime := time.Now().Unix()
+		err := services.TaxRecords.UpdateOverdueTaxRecords(c, currentUnixTime)
+
+		if err != nil {
+			return err
+		}
+
+		return services.Obligations.UpdateOverdueObligations(c, currentUnixTime)
+	},
+}
+
+// SendNotificationsJob represents the cron job which daily sends the weekly digest and due reminder emails to users according to their notification preferences
+var SendNotificationsJob = &CronJob{
+	Name:        "SendNotifications",
+	Description: "Daily send weekly digest emails on the day chosen by users, and remind users of the payments falling due.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 8,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.Notifications.SendNotifications(c, time.Now().Unix())
+	},
+}
+
+// SaveExchangeRatesHistoryJob represents the cron job which periodically save the latest exchange rates into the exchange rates history
+var SaveExchangeRatesHistoryJob = &CronJob{
+	Name:        "SaveExchangeRatesHistory",
+	Description: "Periodically save the latest exchange rates into the exchange rates history.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 18,
+	},
+	Run: func(c *core.CronContext) error {
+		config := settings.Container.GetCurrentConfig()
+		exchangeRates, err := exchangerates.Container.GetLatestExchangeRates(c, 0, config)
+
+		if err != nil {
+			return err
+		}
+
+		rateDate, err := services.ExchangeRateHistories.SaveExchangeRates(c, config.ExchangeRatesDataSource, exchangeRates)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof(c, "[cron_jobs.SaveExchangeRatesHistoryJob] %d exchange rates of \"%s\" on %s have been saved", len(exchangeRates.ExchangeRates), config.ExchangeRatesDataSource, rateDate)
+		return nil
+	},
+}
@@ -3,9 +3,23 @@ package errs
 import "net/http"
 
 var (
-	ErrAssetIdInvalid          = NewNormalError(NormalSubcategoryAsset, 0, http.StatusBadRequest, "asset id is invalid")
-	ErrAssetNotFound           = NewNormalError(NormalSubcategoryAsset, 1, http.StatusNotFound, "asset not found")
-	ErrAssetNameIsEmpty        = NewNormalError(NormalSubcategoryAsset, 2, http.StatusBadRequest, "asset name is empty")
-	ErrAssetNameAlreadyExists  = NewNormalError(NormalSubcategoryAsset, 3, http.StatusConflict, "asset name already exists")
-	ErrAssetInUseCannotBeDeleted = NewNormalError(NormalSubcategoryAsset, 4, http.StatusConflict, "asset is in use and cannot be deleted")
+	ErrAssetIdInvalid                       = NewNormalError(NormalSubcategoryAsset, 0, http.StatusBadRequest, "asset id is invalid")
+	ErrAssetNotFound                        = NewNormalError(NormalSubcategoryAsset, 1, http.StatusNotFound, "asset not found")
+	ErrAssetNameIsEmpty                     = NewNormalError(NormalSubcategoryAsset, 2, http.StatusBadRequest, "asset name is empty")
+	ErrAssetNameAlreadyExists               = NewNormalError(NormalSubcategoryAsset, 3, http.StatusConflict, "asset name already exists")
+	ErrAssetInUseCannotBeDeleted            = NewNormalError(NormalSubcategoryAsset, 4, http.StatusConflict, "asset is in use and cannot be deleted")
+	ErrAssetInstalledCapacityRequired       = NewNormalError(NormalSubcategoryAsset, 5, http.StatusBadRequest, "installed capacity is required for units of production depreciation")
+	ErrAssetDepreciationPostingIncomplete   = NewNormalError(NormalSubcategoryAsset, 6, http.StatusBadRequest, "book account and depreciation category are both required for posting depreciation")
+	ErrAssetAlreadyDisposed                 = NewNormalError(NormalSubcategoryAsset, 7, http.StatusBadRequest, "asset has already been disposed")
+	ErrAssetDisposalDateInvalid             = NewNormalError(NormalSubcategoryAsset, 8, http.StatusBadRequest, "asset disposal date is invalid")
+	ErrAssetDisposalProceedsAccountRequired = NewNormalError(NormalSubcategoryAsset, 9, http.StatusBadRequest, "account and category are required for asset disposal proceeds")
+	ErrAssetPurchaseTransactionTypeInvalid  = NewNormalError(NormalSubcategoryAsset, 10, http.StatusBadRequest, "only expense or transfer transaction can be linked as asset purchase")
+	ErrAssetTransactionAlreadyLinked        = NewNormalError(NormalSubcategoryAsset, 11, http.StatusConflict, "transaction has already been linked to asset")
+	ErrAssetTransactionNotLinked            = NewNormalError(NormalSubcategoryAsset, 12, http.StatusBadRequest, "transaction is not linked to asset as purchase")
+	ErrAssetDepreciationNotLatest           = NewNormalError(NormalSubcategoryAsset, 13, http.StatusBadRequest, "only the latest depreciation transaction of active asset can be deleted")
+	ErrAssetDisposalTransactionCannotDelete = NewNormalError(NormalSubcategoryAsset, 14, http.StatusBadRequest, "asset disposal transaction cannot be deleted")
+	ErrAssetPostedTransactionCannotModify   = NewNormalError(NormalSubcategoryAsset, 15, http.StatusBadRequest, "cannot modify amount or time of asset depreciation or disposal transaction")
+	ErrAssetProductionOutputMethodInvalid   = NewNormalError(NormalSubcategoryAsset, 16, http.StatusBadRequest, "production output can only be saved for units of production depreciation")
+	ErrAssetProductionOutputPeriodInvalid   = NewNormalError(NormalSubcategoryAsset, 17, http.StatusBadRequest, "production output month is out of service life of asset")
+	ErrAssetProductionOutputAlreadyPosted   = NewNormalError(NormalSubcategoryAsset, 18, http.StatusBadRequest, "depreciation of production output month has already been posted")
 )
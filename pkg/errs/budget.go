@@ -3,6 +3,10 @@ package errs
 import "net/http"
 
 var (
-	ErrBudgetIdInvalid  = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
-	ErrBudgetNotFound   = NewNormalError(NormalSubcategoryBudget, 1, http.StatusNotFound, "budget not found")
+	ErrBudgetIdInvalid             = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
+	ErrBudgetNotFound              = NewNormalError(NormalSubcategoryBudget, 1, http.StatusNotFound, "budget not found")
+	ErrBudgetMonthRangeInvalid     = NewNormalError(NormalSubcategoryBudget, 2, http.StatusBadRequest, "budget month range is invalid")
+	ErrBudgetSpreadWeightsInvalid  = NewNormalError(NormalSubcategoryBudget, 3, http.StatusBadRequest, "budget spread weights are invalid")
+	ErrBudgetActualAmountsNotFound = NewNormalError(NormalSubcategoryBudget, 4, http.StatusBadRequest, "no actual amounts found to derive budget from")
+	ErrBudgetCopySourceEmpty       = NewNormalError(NormalSubcategoryBudget, 5, http.StatusBadRequest, "source budget is empty")
 )
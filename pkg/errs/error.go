@@ -54,6 +54,8 @@ const (
 	NormalSubcategoryObligation            = 26
 	NormalSubcategoryTaxRecord             = 27
 	NormalSubcategoryReport                = 28
+	NormalSubcategoryExchangeRateHistory   = 29
+	NormalSubcategoryTaxRegime             = 30
 )
 
 // Error represents the specific error returned to user
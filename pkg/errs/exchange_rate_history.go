@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rate history
+var (
+	ErrExchangeRateHistoryNotFound         = NewNormalError(NormalSubcategoryExchangeRateHistory, 0, http.StatusNotFound, "exchange rate history not found")
+	ErrExchangeRateHistoryDateInvalid      = NewNormalError(NormalSubcategoryExchangeRateHistory, 1, http.StatusBadRequest, "exchange rate history date is invalid")
+	ErrHistoricalExchangeRatesNotSupported = NewNormalError(NormalSubcategoryExchangeRateHistory, 2, http.StatusBadRequest, "current exchange rates data source does not support historical exchange rates")
+	ErrExchangeRateHistoryDateRangeInvalid = NewNormalError(NormalSubcategoryExchangeRateHistory, 3, http.StatusBadRequest, "exchange rate history date range is invalid")
+)
@@ -99,3 +99,18 @@ func GetParameterInvalidAmountFilterMessage(field string) string {
 func GetParameterInvalidTagFilterMessage(field string) string {
 	return fmt.Sprintf("parameter \"%s\" is invalid tag filter", field)
 }
+
+// GetParameterInvalidGeoBoundsFilterMessage returns specific error message for invalid geographic bounds filter parameter error
+func GetParameterInvalidGeoBoundsFilterMessage(field string) string {
+	return fmt.Sprintf("parameter \"%s\" is invalid geographic bounds filter", field)
+}
+
+// GetParameterInvalidCommentFilterMessage returns specific error message for invalid comment filter parameter error
+func GetParameterInvalidCommentFilterMessage(field string) string {
+	return fmt.Sprintf("parameter \"%s\" is invalid comment filter", field)
+}
+
+// GetParameterInvalidConditionGroupsMessage returns specific error message for invalid condition groups parameter error
+func GetParameterInvalidConditionGroupsMessage(field string) string {
+	return fmt.Sprintf("parameter \"%s\" is invalid condition groups", field)
+}
@@ -3,9 +3,13 @@ package errs
 import "net/http"
 
 var (
-	ErrInvestorDealIdInvalid            = NewNormalError(NormalSubcategoryInvestorDeal, 0, http.StatusBadRequest, "investor deal id is invalid")
-	ErrInvestorDealNotFound             = NewNormalError(NormalSubcategoryInvestorDeal, 1, http.StatusNotFound, "investor deal not found")
-	ErrInvestorDealInvestorNameIsEmpty  = NewNormalError(NormalSubcategoryInvestorDeal, 2, http.StatusBadRequest, "investor name is empty")
-	ErrInvestorPaymentIdInvalid         = NewNormalError(NormalSubcategoryInvestorDeal, 3, http.StatusBadRequest, "investor payment id is invalid")
-	ErrInvestorPaymentNotFound          = NewNormalError(NormalSubcategoryInvestorDeal, 4, http.StatusNotFound, "investor payment not found")
+	ErrInvestorDealIdInvalid             = NewNormalError(NormalSubcategoryInvestorDeal, 0, http.StatusBadRequest, "investor deal id is invalid")
+	ErrInvestorDealNotFound              = NewNormalError(NormalSubcategoryInvestorDeal, 1, http.StatusNotFound, "investor deal not found")
+	ErrInvestorDealInvestorNameIsEmpty   = NewNormalError(NormalSubcategoryInvestorDeal, 2, http.StatusBadRequest, "investor name is empty")
+	ErrInvestorPaymentIdInvalid          = NewNormalError(NormalSubcategoryInvestorDeal, 3, http.StatusBadRequest, "investor payment id is invalid")
+	ErrInvestorPaymentNotFound           = NewNormalError(NormalSubcategoryInvestorDeal, 4, http.StatusNotFound, "investor payment not found")
+	ErrInvestorDealScheduleNotSupported  = NewNormalError(NormalSubcategoryInvestorDeal, 5, http.StatusBadRequest, "repayment schedule is not supported for this deal type")
+	ErrInvestorDealRepaymentTermsInvalid = NewNormalError(NormalSubcategoryInvestorDeal, 6, http.StatusBadRequest, "repayment terms of investor deal are invalid")
+	ErrInvestorDealNoFutureInstalments   = NewNormalError(NormalSubcategoryInvestorDeal, 7, http.StatusBadRequest, "investor deal has no future instalments")
+	ErrInvestorDealCurrencyMismatch      = NewNormalError(NormalSubcategoryInvestorDeal, 8, http.StatusBadRequest, "account currency does not match investor deal currency")
 )
@@ -9,4 +9,6 @@ var (
 	ErrAIRecognitionImageIsEmpty            = NewNormalError(NormalSubcategoryLargeLanguageModel, 2, http.StatusBadRequest, "image for AI recognition is empty")
 	ErrExceedMaxAIRecognitionImageFileSize  = NewNormalError(NormalSubcategoryLargeLanguageModel, 3, http.StatusBadRequest, "exceed the maximum size of image file for AI recognition")
 	ErrNoTransactionInformationInImage      = NewNormalError(NormalSubcategoryLargeLanguageModel, 4, http.StatusBadRequest, "no transaction information detected")
+	ErrExceedMaxAIRecognitionFileCount      = NewNormalError(NormalSubcategoryLargeLanguageModel, 5, http.StatusBadRequest, "exceed the maximum count of files for AI recognition")
+	ErrLargeLanguageModelNotSupportDocument = NewNormalError(NormalSubcategoryLargeLanguageModel, 6, http.StatusBadRequest, "llm provider does not support document recognition")
 )
@@ -4,5 +4,6 @@ import "net/http"
 
 // Error codes related to model context protocol server
 var (
-	ErrMCPServerNotEnabled = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPServerNotEnabled    = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPAuditLogNotRecorded = NewNormalError(NormalSubcategoryModelContextProtocol, 1, http.StatusInternalServerError, "failed to record audit log, change has not been applied")
 )
@@ -3,8 +3,18 @@ package errs
 import "net/http"
 
 var (
-	ErrObligationIdInvalid  = NewNormalError(NormalSubcategoryObligation, 0, http.StatusBadRequest, "obligation id is invalid")
-	ErrObligationNotFound   = NewNormalError(NormalSubcategoryObligation, 1, http.StatusNotFound, "obligation not found")
-	ErrTaxRecordIdInvalid   = NewNormalError(NormalSubcategoryTaxRecord, 0, http.StatusBadRequest, "tax record id is invalid")
-	ErrTaxRecordNotFound    = NewNormalError(NormalSubcategoryTaxRecord, 1, http.StatusNotFound, "tax record not found")
+	ErrObligationIdInvalid                          = NewNormalError(NormalSubcategoryObligation, 0, http.StatusBadRequest, "obligation id is invalid")
+	ErrObligationNotFound                           = NewNormalError(NormalSubcategoryObligation, 1, http.StatusNotFound, "obligation not found")
+	ErrObligationPaymentIdInvalid                   = NewNormalError(NormalSubcategoryObligation, 2, http.StatusBadRequest, "obligation payment id is invalid")
+	ErrObligationPaymentNotFound                    = NewNormalError(NormalSubcategoryObligation, 3, http.StatusNotFound, "obligation payment not found")
+	ErrObligationAllocationAmountInvalid            = NewNormalError(NormalSubcategoryObligation, 4, http.StatusBadRequest, "allocated amount is invalid")
+	ErrObligationAllocationExceedsRemainingAmount   = NewNormalError(NormalSubcategoryObligation, 5, http.StatusBadRequest, "allocated amount exceeds remaining amount of obligation")
+	ErrObligationAllocationExceedsTransactionAmount = NewNormalError(NormalSubcategoryObligation, 6, http.StatusBadRequest, "allocated amount exceeds unallocated amount of transaction")
+	ErrObligationCounterpartyMismatch               = NewNormalError(NormalSubcategoryObligation, 7, http.StatusBadRequest, "obligations and transaction must belong to the same counterparty")
+	ErrObligationTypeMismatch                       = NewNormalError(NormalSubcategoryObligation, 8, http.StatusBadRequest, "transaction type does not match obligation type")
+	ErrObligationCurrencyMismatch                   = NewNormalError(NormalSubcategoryObligation, 9, http.StatusBadRequest, "transaction currency does not match obligation currency")
+	ErrObligationTransactionInvalid                 = NewNormalError(NormalSubcategoryObligation, 10, http.StatusBadRequest, "only confirmed income or expense transaction can settle obligation")
+	ErrNoOpenObligationsToAllocate                  = NewNormalError(NormalSubcategoryObligation, 11, http.StatusBadRequest, "there are no open obligations of the counterparty to allocate")
+	ErrTaxRecordIdInvalid                           = NewNormalError(NormalSubcategoryTaxRecord, 0, http.StatusBadRequest, "tax record id is invalid")
+	ErrTaxRecordNotFound                            = NewNormalError(NormalSubcategoryTaxRecord, 1, http.StatusNotFound, "tax record not found")
 )
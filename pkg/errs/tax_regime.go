@@ -0,0 +1,10 @@
+package errs
+
+import "net/http"
+
+var (
+	ErrTaxRegimeNotFound                  = NewNormalError(NormalSubcategoryTaxRegime, 0, http.StatusNotFound, "tax regime not found")
+	ErrTaxRegimeMinimumTaxNotSupported    = NewNormalError(NormalSubcategoryTaxRegime, 1, http.StatusBadRequest, "minimum tax is only supported by simplified tax on income minus expenses")
+	ErrTaxRegimeExcludedCategoryIdInvalid = NewNormalError(NormalSubcategoryTaxRegime, 2, http.StatusBadRequest, "excluded category id of tax regime is invalid")
+	ErrTaxPeriodInvalid                   = NewNormalError(NormalSubcategoryTaxRegime, 3, http.StatusBadRequest, "tax period does not match the period type of tax regime")
+)
@@ -46,4 +46,8 @@ var (
 	ErrCannotMoveTransactionFromOrToParentAccount                  = NewNormalError(NormalSubcategoryTransaction, 39, http.StatusBadRequest, "cannot move transaction from or to parent account")
 	ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies = NewNormalError(NormalSubcategoryTransaction, 40, http.StatusBadRequest, "cannot move transaction between accounts with different currencies")
 	ErrTransactionAlreadyRepeatable                                = NewNormalError(NormalSubcategoryTransaction, 41, http.StatusBadRequest, "transaction is already repeatable")
+	ErrTransactionGeoBoundsFilterInvalid                           = NewNormalError(NormalSubcategoryTransaction, 42, http.StatusBadRequest, "transaction geographic bounds filter is invalid")
+	ErrTransactionCommentFilterInvalid                             = NewNormalError(NormalSubcategoryTransaction, 43, http.StatusBadRequest, "transaction comment filter is invalid")
+	ErrTransactionQueryConditionGroupsInvalid                      = NewNormalError(NormalSubcategoryTransaction, 44, http.StatusBadRequest, "transaction query condition groups are invalid")
+	ErrTransactionSearchQueryInvalid                               = NewNormalError(NormalSubcategoryTransaction, 45, http.StatusBadRequest, "transaction search query is invalid")
 )
@@ -19,6 +19,7 @@ import (
 )
 
 const bankOfRussiaExchangeRateUrl = "https://cbr.ru/scripts/XML_daily_eng.asp"
+const bankOfRussiaHistoricalExchangeRateDateFormat = "02/01/2006"
 const bankOfRussiaExchangeRateReferenceUrl = "https://www.cbr.ru/eng/currency_base/daily/"
 const bankOfRussiaDataSource = "Банк России"
 const bankOfRussiaBaseCurrency = "RUB"
@@ -132,6 +133,17 @@ func (e *BankOfRussiaDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildHistoricalRequests returns the bank of Russia exchange rates http requests of the specified date
+func (e *BankOfRussiaDataSource) BuildHistoricalRequests(date time.Time) ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", bankOfRussiaExchangeRateUrl+"?date_req="+date.Format(bankOfRussiaHistoricalExchangeRateDateFormat), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the bank of Russia data source raw response
 func (e *BankOfRussiaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -135,3 +136,12 @@ func TestBankOfRussiaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfRussiaDataSource_BuildHistoricalRequests(t *testing.T) {
+	dataSource := &BankOfRussiaDataSource{}
+
+	requests, err := dataSource.BuildHistoricalRequests(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "https://cbr.ru/scripts/XML_daily_eng.asp?date_req=05/01/2024", requests[0].URL.String())
+}
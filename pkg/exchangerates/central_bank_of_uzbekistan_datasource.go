@@ -15,6 +15,7 @@ import (
 )
 
 const centralBankOfUzbekistanExchangeRateUrl = "https://cbu.uz/ru/arkhiv-kursov-valyut/json/"
+const centralBankOfUzbekistanHistoricalExchangeRateDateFormat = "2006-01-02"
 const centralBankOfUzbekistanExchangeRateReferenceUrl = "https://cbu.uz/en/arkhiv-kursov-valyut/"
 const centralBankOfUzbekistanDataSource = "O‘zbekiston Respublikasi Markaziy banki"
 const centralBankOfUzbekistanBaseCurrency = "UZS"
@@ -142,6 +143,17 @@ func (e *CentralBankOfUzbekistanDataSource) BuildRequests() ([]*http.Request, er
 	return []*http.Request{req}, nil
 }
 
+// BuildHistoricalRequests returns the the central bank of the Republic of Uzbekistan exchange rates http requests of the specified date
+func (e *CentralBankOfUzbekistanDataSource) BuildHistoricalRequests(date time.Time) ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", centralBankOfUzbekistanExchangeRateUrl+"all/"+date.Format(centralBankOfUzbekistanHistoricalExchangeRateDateFormat)+"/", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the the central bank of the Republic of Uzbekistan data source raw response
 func (e *CentralBankOfUzbekistanDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	centralBankOfUzbekistanData := &CentralBankOfUzbekistanExchangeRates{}
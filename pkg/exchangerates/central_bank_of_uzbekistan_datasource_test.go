@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -143,3 +144,12 @@ func TestCentralBankOfUzbekistanDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestCentralBankOfUzbekistanDataSource_BuildHistoricalRequests(t *testing.T) {
+	dataSource := &CentralBankOfUzbekistanDataSource{}
+
+	requests, err := dataSource.BuildHistoricalRequests(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "https://cbu.uz/ru/arkhiv-kursov-valyut/json/all/2024-01-05/", requests[0].URL.String())
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -22,6 +23,12 @@ type HttpExchangeRatesDataSource interface {
 	Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
 
+// HistoricalHttpExchangeRatesDataSource defines the structure of http exchange rates data source which supports requesting historical exchange rates
+type HistoricalHttpExchangeRatesDataSource interface {
+	// BuildHistoricalRequests returns the http requests of the exchange rates published on the specified date
+	BuildHistoricalRequests(date time.Time) ([]*http.Request, error)
+}
+
 // CommonHttpExchangeRatesDataProvider defines the structure of common http exchange rates data provider
 type CommonHttpExchangeRatesDataProvider struct {
 	ExchangeRatesDataProvider
@@ -37,18 +44,46 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 		return nil, errs.ErrFailedToRequestRemoteApi
 	}
 
+	return e.requestExchangeRates(c, uid, requests)
+}
+
+// SupportsHistoricalExchangeRates returns whether the data source supports requesting historical exchange rates
+func (e *CommonHttpExchangeRatesDataProvider) SupportsHistoricalExchangeRates() bool {
+	_, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+	return ok
+}
+
+// GetHistoricalExchangeRates returns the exchange rates published on the specified date
+func (e *CommonHttpExchangeRatesDataProvider) GetHistoricalExchangeRates(c core.Context, uid int64, date time.Time, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	requests, err := historicalDataSource.BuildHistoricalRequests(date)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to build requests of date \"%s\" for user \"uid:%d\", because %s", date.Format("2006-01-02"), uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return e.requestExchangeRates(c, uid, requests)
+}
+
+func (e *CommonHttpExchangeRatesDataProvider) requestExchangeRates(c core.Context, uid int64, requests []*http.Request) (*models.LatestExchangeRateResponse, error) {
 	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
 
 	for i := 0; i < len(requests); i++ {
 		req := requests[i]
 		req = req.WithContext(httpclient.CustomHttpResponseLog(c, func(data []byte) {
-			log.Debugf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] response#%d is %s", i, data)
+			log.Debugf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] response#%d is %s", i, data)
 		}))
 
 		resp, err := e.httpClient.Do(req)
 
 		if err != nil {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] failed to request exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
 			return nil, errs.ErrFailedToRequestRemoteApi
 		}
 
@@ -56,14 +91,14 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 		body, err := io.ReadAll(resp.Body)
 
 		if resp.StatusCode != 200 {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to get latest exchange rate data response for user \"uid:%d\", because response code is %d", uid, resp.StatusCode)
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] failed to get exchange rate data response for user \"uid:%d\", because response code is %d", uid, resp.StatusCode)
 			return nil, errs.ErrFailedToRequestRemoteApi
 		}
 
 		exchangeRateResp, err := e.dataSource.Parse(c, body)
 
 		if err != nil {
-			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetLatestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.requestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
 			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 		}
 
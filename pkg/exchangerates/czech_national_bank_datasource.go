@@ -1,6 +1,7 @@
 package exchangerates
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 
 const czechNationalBankDailyExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/daily.txt"
 const czechNationalBankMonthlyOtherExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/fx-rates-of-other-currencies/fx-rates-of-other-currencies/fx_rates.txt"
+const czechNationalBankHistoricalExchangeRateDateFormat = "02.01.2006"
 const czechNationalBankExchangeRateReferenceUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/"
 const czechNationalBankDataSource = "Česká národní banka"
 const czechNationalBankBaseCurrency = "CZK"
@@ -45,6 +47,23 @@ func (e *CzechNationalBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{monthlyReq, dailyReq}, nil
 }
 
+// BuildHistoricalRequests returns the Czech National Bank exchange rates http requests of the specified date
+func (e *CzechNationalBankDataSource) BuildHistoricalRequests(date time.Time) ([]*http.Request, error) {
+	monthlyReq, err := http.NewRequest("GET", fmt.Sprintf("%s?year=%d&month=%d", czechNationalBankMonthlyOtherExchangeRateUrl, date.Year(), int(date.Month())), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dailyReq, err := http.NewRequest("GET", czechNationalBankDailyExchangeRateUrl+"?date="+date.Format(czechNationalBankHistoricalExchangeRateDateFormat), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{monthlyReq, dailyReq}, nil
+}
+
 // Parse returns the common response entity according to the czech nation bank data source raw response
 func (e *CzechNationalBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	lines := strings.Split(string(content), "\n")
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -182,3 +183,13 @@ func TestCzechNationalBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestCzechNationalBankDataSource_BuildHistoricalRequests(t *testing.T) {
+	dataSource := &CzechNationalBankDataSource{}
+
+	requests, err := dataSource.BuildHistoricalRequests(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, czechNationalBankMonthlyOtherExchangeRateUrl+"?year=2024&month=1", requests[0].URL.String())
+	assert.Equal(t, czechNationalBankDailyExchangeRateUrl+"?date=05.01.2024", requests[1].URL.String())
+}
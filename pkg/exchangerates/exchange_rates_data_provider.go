@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -11,3 +13,12 @@ type ExchangeRatesDataProvider interface {
 	// GetLatestExchangeRates returns the common response entities
 	GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataProvider defines the structure of exchange rates data provider which may support historical exchange rates
+type HistoricalExchangeRatesDataProvider interface {
+	// SupportsHistoricalExchangeRates returns whether the data provider supports requesting historical exchange rates
+	SupportsHistoricalExchangeRates() bool
+
+	// GetHistoricalExchangeRates returns the common response entities of the exchange rates published on the specified date
+	GetHistoricalExchangeRates(c core.Context, uid int64, date time.Time, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error)
+}
@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
@@ -83,3 +85,25 @@ func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRates(c core.Conte
 
 	return e.current.GetLatestExchangeRates(c, uid, currentConfig)
 }
+
+// SupportsHistoricalExchangeRates returns whether the current exchange rates data source supports requesting historical exchange rates
+func (e *ExchangeRatesDataProviderContainer) SupportsHistoricalExchangeRates() bool {
+	historicalProvider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+
+	return ok && historicalProvider.SupportsHistoricalExchangeRates()
+}
+
+// GetHistoricalExchangeRates returns the exchange rates published on the specified date from the current exchange rates data source
+func (e *ExchangeRatesDataProviderContainer) GetHistoricalExchangeRates(c core.Context, uid int64, date time.Time, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	historicalProvider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+
+	if !ok || !historicalProvider.SupportsHistoricalExchangeRates() {
+		return nil, errs.ErrHistoricalExchangeRatesNotSupported
+	}
+
+	return historicalProvider.GetHistoricalExchangeRates(c, uid, date, currentConfig)
+}
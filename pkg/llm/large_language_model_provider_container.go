@@ -10,12 +10,15 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/lmstudio"
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/ollama"
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/openai"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/rules"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // LargeLanguageModelProviderContainer contains the current large language model provider
 type LargeLanguageModelProviderContainer struct {
-	receiptImageRecognitionCurrentProvider provider.LargeLanguageModelProvider
+	receiptImageRecognitionCurrentProvider         provider.LargeLanguageModelProvider
+	transactionImportCategorizationCurrentProvider provider.LargeLanguageModelProvider
+	transactionQueryCurrentProvider                provider.LargeLanguageModelProvider
 }
 
 // Initialize a large language model provider container singleton instance
@@ -35,6 +38,22 @@ func InitializeLargeLanguageModelProvider(config *settings.Config) error {
 		}
 	}
 
+	if config.TransactionImportCategorizationLLMConfig != nil {
+		Container.transactionImportCategorizationCurrentProvider, err = initializeLargeLanguageModelProvider(config.TransactionImportCategorizationLLMConfig, config.EnableDebugLog)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.TransactionQueryLLMConfig != nil {
+		Container.transactionQueryCurrentProvider, err = initializeLargeLanguageModelProvider(config.TransactionQueryLLMConfig, config.EnableDebugLog)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -55,6 +74,8 @@ func initializeLargeLanguageModelProvider(llmConfig *settings.LLMConfig, enableR
 		return lmstudio.NewLMStudioLargeLanguageModelProvider(llmConfig, enableResponseLog), nil
 	} else if llmConfig.LLMProvider == settings.GoogleAILLMProvider {
 		return googleai.NewGoogleAILargeLanguageModelProvider(llmConfig, enableResponseLog), nil
+	} else if llmConfig.LLMProvider == settings.RulesLLMProvider {
+		return rules.NewRulesLargeLanguageModelProvider(llmConfig, enableResponseLog)
 	} else if llmConfig.LLMProvider == "" {
 		return nil, nil
 	}
@@ -70,3 +91,21 @@ func (l *LargeLanguageModelProviderContainer) GetJsonResponseByReceiptImageRecog
 
 	return l.receiptImageRecognitionCurrentProvider.GetJsonResponse(c, uid, currentConfig.ReceiptImageRecognitionLLMConfig, request)
 }
+
+// GetJsonResponseByTransactionImportCategorizationModel returns the json response from the current large language model provider by imported transaction categorization model
+func (l *LargeLanguageModelProviderContainer) GetJsonResponseByTransactionImportCategorizationModel(c core.Context, uid int64, currentConfig *settings.Config, request *data.LargeLanguageModelRequest) (*data.LargeLanguageModelTextualResponse, error) {
+	if currentConfig.TransactionImportCategorizationLLMConfig == nil || Container.transactionImportCategorizationCurrentProvider == nil {
+		return nil, errs.ErrInvalidLLMProvider
+	}
+
+	return l.transactionImportCategorizationCurrentProvider.GetJsonResponse(c, uid, currentConfig.TransactionImportCategorizationLLMConfig, request)
+}
+
+// GetJsonResponseByTransactionQueryModel returns the json response from the current large language model provider by natural language transaction query model
+func (l *LargeLanguageModelProviderContainer) GetJsonResponseByTransactionQueryModel(c core.Context, uid int64, currentConfig *settings.Config, request *data.LargeLanguageModelRequest) (*data.LargeLanguageModelTextualResponse, error) {
+	if currentConfig.TransactionQueryLLMConfig == nil || Container.transactionQueryCurrentProvider == nil {
+		return nil, errs.ErrInvalidLLMProvider
+	}
+
+	return l.transactionQueryCurrentProvider.GetJsonResponse(c, uid, currentConfig.TransactionQueryLLMConfig, request)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/common"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // AnthropicMessagesAPIProvider defines the structure of Anthropic messages API provider
@@ -157,11 +158,17 @@ func (p *CommonAnthropicMessagesAPILargeLanguageModelAdapter) buildJsonRequestBo
 	if len(request.UserPrompt) > 0 {
 		if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL {
 			imageBase64Data := base64.StdEncoding.EncodeToString(request.UserPrompt)
+			blockType := "image"
+
+			if utils.IsDocumentContentType(request.UserPromptContentType) {
+				blockType = "document"
+			}
+
 			messagesRequest.Messages = append(messagesRequest.Messages, &AnthropicMessagesRequestMessage[[]*AnthropicMessagesRequestImageBlockParam]{
 				Role: AnthropicMessageRoleUser,
 				Content: []*AnthropicMessagesRequestImageBlockParam{
 					{
-						Type: "image",
+						Type: blockType,
 						Source: &AnthropicMessagesRequestBase64ImageSource{
 							Data:      imageBase64Data,
 							MediaType: request.UserPromptContentType,
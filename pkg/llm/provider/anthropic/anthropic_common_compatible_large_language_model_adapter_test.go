@@ -58,6 +58,31 @@ func TestCommonAnthropicMessagesAPILargeLanguageModelAdapter_buildJsonRequestBod
 	assert.Equal(t, "{\"model\":\"test\",\"max_tokens\":128,\"stream\":false,\"system\":\"What's in this image?\",\"messages\":[{\"role\":\"user\",\"content\":[{\"source\":{\"data\":\"ZmFrZWRhdGE=\",\"media_type\":\"image/png\",\"type\":\"base64\"},\"type\":\"image\"}]}],\"thinking\":{\"type\":\"disabled\"}}", string(bodyBytes))
 }
 
+func TestCommonAnthropicMessagesAPILargeLanguageModelAdapter_buildJsonRequestBody_DocumentUserPrompt(t *testing.T) {
+	adapter := &CommonAnthropicMessagesAPILargeLanguageModelAdapter{
+		apiProvider: &AnthropicOfficialMessagesAPIProvider{
+			AnthropicModelID:   "test",
+			AnthropicMaxTokens: 128,
+		},
+	}
+
+	request := &data.LargeLanguageModelRequest{
+		SystemPrompt:          "What's in this document?",
+		UserPrompt:            []byte("fakedata"),
+		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+		UserPromptContentType: "application/pdf",
+	}
+
+	bodyBytes, err := adapter.buildJsonRequestBody(core.NewNullContext(), 0, request, data.LARGE_LANGUAGE_MODEL_RESPONSE_FORMAT_JSON)
+	assert.Nil(t, err)
+
+	var body map[string]interface{}
+	err = json.Unmarshal(bodyBytes, &body)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "{\"model\":\"test\",\"max_tokens\":128,\"stream\":false,\"system\":\"What's in this document?\",\"messages\":[{\"role\":\"user\",\"content\":[{\"source\":{\"data\":\"ZmFrZWRhdGE=\",\"media_type\":\"application/pdf\",\"type\":\"base64\"},\"type\":\"document\"}]}],\"thinking\":{\"type\":\"disabled\"}}", string(bodyBytes))
+}
+
 func TestCommonAnthropicMessagesAPILargeLanguageModelAdapter_ParseTextualResponse_ValidJsonResponse(t *testing.T) {
 	adapter := &CommonAnthropicMessagesAPILargeLanguageModelAdapter{
 		apiProvider: &AnthropicOfficialMessagesAPIProvider{},
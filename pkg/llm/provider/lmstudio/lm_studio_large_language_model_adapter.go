@@ -13,6 +13,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/common"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 const lmStudioChatPath = "api/v1/chat"
@@ -110,6 +111,11 @@ func (p *LMStudioLargeLanguageModelAdapter) buildJsonRequestBody(c core.Context,
 		chatRequest.SystemPrompt = request.SystemPrompt
 	}
 
+	if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL && utils.IsDocumentContentType(request.UserPromptContentType) {
+		log.Warnf(c, "[lm_studio_large_language_model_adapter.buildJsonRequestBody] document \"%s\" is not supported for user \"uid:%d\"", request.UserPromptContentType, uid)
+		return nil, errs.ErrLargeLanguageModelNotSupportDocument
+	}
+
 	if len(request.UserPrompt) > 0 {
 		if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL {
 			imageBase64Data := "data:" + request.UserPromptContentType + ";base64," + base64.StdEncoding.EncodeToString(request.UserPrompt)
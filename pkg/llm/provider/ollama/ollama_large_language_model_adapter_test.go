@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
 func TestOllamaLargeLanguageModelAdapter_buildJsonRequestBody_TextualUserPrompt(t *testing.T) {
@@ -51,6 +52,22 @@ func TestOllamaLargeLanguageModelAdapter_buildJsonRequestBody_ImageUserPrompt(t
 	assert.Equal(t, "{\"model\":\"test\",\"stream\":false,\"messages\":[{\"role\":\"system\",\"content\":\"What's in this image?\"},{\"role\":\"user\",\"content\":\"\",\"images\":[\"ZmFrZWRhdGE=\"]}],\"format\":\"json\"}", string(bodyBytes))
 }
 
+func TestOllamaLargeLanguageModelAdapter_buildJsonRequestBody_DocumentUserPrompt(t *testing.T) {
+	adapter := &OllamaLargeLanguageModelAdapter{
+		OllamaModelID: "test",
+	}
+
+	request := &data.LargeLanguageModelRequest{
+		SystemPrompt:          "What's in this document?",
+		UserPrompt:            []byte("fakedata"),
+		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+		UserPromptContentType: "application/pdf",
+	}
+
+	_, err := adapter.buildJsonRequestBody(core.NewNullContext(), 0, request, data.LARGE_LANGUAGE_MODEL_RESPONSE_FORMAT_JSON)
+	assert.Equal(t, errs.ErrLargeLanguageModelNotSupportDocument, err)
+}
+
 func TestOllamaLargeLanguageModelAdapter_ParseTextualResponse_ValidJsonResponse(t *testing.T) {
 	adapter := &OllamaLargeLanguageModelAdapter{}
 
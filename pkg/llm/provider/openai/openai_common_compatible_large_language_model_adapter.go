@@ -15,6 +15,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/llm/provider/common"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // OpenAIChatCompletionsAPIProvider defines the structure of OpenAI chat completions API provider
@@ -59,7 +60,7 @@ type OpenAIChatCompletionsRequest struct {
 }
 
 // OpenAIChatCompletionsRequestMessage defines the structure of OpenAI chat completions request message
-type OpenAIChatCompletionsRequestMessage[T string | []*OpenAIChatCompletionsRequestImageContent] struct {
+type OpenAIChatCompletionsRequestMessage[T string | []*OpenAIChatCompletionsRequestImageContent | []*OpenAIChatCompletionsRequestFileContent] struct {
 	Role    OpenAIMessageRole `json:"role"`
 	Content T                 `json:"content"`
 }
@@ -70,6 +71,12 @@ type OpenAIChatCompletionsRequestImageContent struct {
 	ImageURL *OpenAIChatCompletionsRequestImageUrl `json:"image_url"`
 }
 
+// OpenAIChatCompletionsRequestFileContent defines the structure of OpenAI chat completions request file content
+type OpenAIChatCompletionsRequestFileContent struct {
+	Type string                            `json:"type"`
+	File *OpenAIChatCompletionsRequestFile `json:"file"`
+}
+
 // OpenAIChatCompletionsRequestResponseFormat defines the structure of OpenAI chat completions request response format
 type OpenAIChatCompletionsRequestResponseFormat struct {
 	Type       OpenAIChatCompletionsRequestResponseFormatType `json:"type"`
@@ -81,6 +88,12 @@ type OpenAIChatCompletionsRequestImageUrl struct {
 	Url string `json:"url"`
 }
 
+// OpenAIChatCompletionsRequestFile defines the structure of OpenAI file
+type OpenAIChatCompletionsRequestFile struct {
+	FileName string `json:"filename"`
+	FileData string `json:"file_data"`
+}
+
 // OpenAIChatCompletionsResponse defines the structure of OpenAI chat completions response
 type OpenAIChatCompletionsResponse struct {
 	Choices []*OpenAIChatCompletionsResponseChoice `json:"choices"`
@@ -159,7 +172,21 @@ func (p *CommonOpenAIChatCompletionsAPILargeLanguageModelAdapter) buildJsonReque
 	}
 
 	if len(request.UserPrompt) > 0 {
-		if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL {
+		if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL && utils.IsDocumentContentType(request.UserPromptContentType) {
+			fileBase64Data := "data:" + request.UserPromptContentType + ";base64," + base64.StdEncoding.EncodeToString(request.UserPrompt)
+			chatCompletionsRequest.Messages = append(chatCompletionsRequest.Messages, &OpenAIChatCompletionsRequestMessage[[]*OpenAIChatCompletionsRequestFileContent]{
+				Role: OpenAIMessageRoleUser,
+				Content: []*OpenAIChatCompletionsRequestFileContent{
+					{
+						Type: "file",
+						File: &OpenAIChatCompletionsRequestFile{
+							FileName: "document." + utils.GetDocumentFileExtension(request.UserPromptContentType),
+							FileData: fileBase64Data,
+						},
+					},
+				},
+			})
+		} else if request.UserPromptType == data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL {
 			imageBase64Data := "data:" + request.UserPromptContentType + ";base64," + base64.StdEncoding.EncodeToString(request.UserPrompt)
 			chatCompletionsRequest.Messages = append(chatCompletionsRequest.Messages, &OpenAIChatCompletionsRequestMessage[[]*OpenAIChatCompletionsRequestImageContent]{
 				Role: OpenAIMessageRoleUser,
@@ -0,0 +1,265 @@
+package rules
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/data"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/provider"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const rulesLargeLanguageModelEmptyResponse = "{}"
+
+var rulesLargeLanguageModelResponseVariablePattern = regexp.MustCompile(`\$\{([a-zA-Z0-9_]+)\}`)
+
+// RulesLargeLanguageModelRulesFile defines the structure of rules file of rules large language model provider
+type RulesLargeLanguageModelRulesFile struct {
+	Rules           []*RulesLargeLanguageModelRule `json:"rules"`
+	DefaultResponse json.RawMessage                `json:"default_response,omitempty"`
+}
+
+// RulesLargeLanguageModelRule defines the structure of rule of rules large language model provider,
+// the rule matches the request only when all the specified conditions match, and the "${n}" or "${name}" in response is replaced with the submatch of user prompt pattern
+type RulesLargeLanguageModelRule struct {
+	Name                  string          `json:"name,omitempty"`
+	SystemPromptPattern   string          `json:"system_prompt_pattern,omitempty"`
+	UserPromptPattern     string          `json:"user_prompt_pattern,omitempty"`
+	UserPromptSha256      string          `json:"user_prompt_sha256,omitempty"`
+	UserPromptContentType string          `json:"user_prompt_content_type,omitempty"`
+	Response              json.RawMessage `json:"response"`
+}
+
+// RulesLargeLanguageModelProvider defines the structure of rules large language model provider, which returns the responses from local rules without requesting any model server
+type RulesLargeLanguageModelProvider struct {
+	provider.LargeLanguageModelProvider
+	rules             []*rulesLargeLanguageModelCompiledRule
+	defaultResponse   string
+	enableResponseLog bool
+}
+
+type rulesLargeLanguageModelCompiledRule struct {
+	name                  string
+	systemPromptPattern   *regexp.Regexp
+	userPromptPattern     *regexp.Regexp
+	userPromptSha256      string
+	userPromptContentType string
+	response              string
+}
+
+// GetJsonResponse returns the json response of the first rule which matches the request, or the default response if no rule matches
+func (p *RulesLargeLanguageModelProvider) GetJsonResponse(c core.Context, uid int64, currentLLMConfig *settings.LLMConfig, request *data.LargeLanguageModelRequest) (*data.LargeLanguageModelTextualResponse, error) {
+	if request == nil {
+		return nil, errs.ErrOperationFailed
+	}
+
+	userPromptSha256 := ""
+
+	for i := 0; i < len(p.rules); i++ {
+		rule := p.rules[i]
+
+		if rule.userPromptContentType != "" && !strings.EqualFold(rule.userPromptContentType, request.UserPromptContentType) {
+			continue
+		}
+
+		if rule.userPromptSha256 != "" {
+			if userPromptSha256 == "" {
+				hash := sha256.Sum256(request.UserPrompt)
+				userPromptSha256 = hex.EncodeToString(hash[:])
+			}
+
+			if !strings.EqualFold(rule.userPromptSha256, userPromptSha256) {
+				continue
+			}
+		}
+
+		if rule.systemPromptPattern != nil && !rule.systemPromptPattern.MatchString(request.SystemPrompt) {
+			continue
+		}
+
+		var submatches [][]byte
+
+		if rule.userPromptPattern != nil {
+			submatches = rule.userPromptPattern.FindSubmatch(request.UserPrompt)
+
+			if submatches == nil {
+				continue
+			}
+		}
+
+		content, err := p.getRuleResponse(rule, submatches)
+
+		if err != nil {
+			log.Errorf(c, "[rules_large_language_model_provider.GetJsonResponse] failed to build response of rule \"#%d\" for user \"uid:%d\", because %s", i, uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+
+		if p.enableResponseLog {
+			log.Debugf(c, "[rules_large_language_model_provider.GetJsonResponse] rule \"#%d\" (%s) matches the request, response is %s", i, rule.name, content)
+		}
+
+		return &data.LargeLanguageModelTextualResponse{
+			Content: content,
+		}, nil
+	}
+
+	if p.enableResponseLog {
+		log.Debugf(c, "[rules_large_language_model_provider.GetJsonResponse] no rule matches the request, response is %s", p.defaultResponse)
+	}
+
+	return &data.LargeLanguageModelTextualResponse{
+		Content: p.defaultResponse,
+	}, nil
+}
+
+func (p *RulesLargeLanguageModelProvider) getRuleResponse(rule *rulesLargeLanguageModelCompiledRule, submatches [][]byte) (string, error) {
+	if len(submatches) < 2 {
+		return rule.response, nil
+	}
+
+	var lastErr error
+
+	response := rulesLargeLanguageModelResponseVariablePattern.ReplaceAllStringFunc(rule.response, func(variable string) string {
+		name := variable[2 : len(variable)-1]
+		index, err := strconv.Atoi(name)
+
+		if err != nil {
+			index = rule.userPromptPattern.SubexpIndex(name)
+		}
+
+		if index < 1 || index >= len(submatches) {
+			return variable
+		}
+
+		// the submatch is escaped as json string content, so that the variable can be placed in any json string of the response
+		value, err := json.Marshal(string(submatches[index]))
+
+		if err != nil {
+			lastErr = err
+			return variable
+		}
+
+		return string(value[1 : len(value)-1])
+	})
+
+	if lastErr != nil {
+		return "", lastErr
+	}
+
+	return response, nil
+}
+
+// NewRulesLargeLanguageModelProvider creates a new rules large language model provider instance from the rules file
+func NewRulesLargeLanguageModelProvider(llmConfig *settings.LLMConfig, enableResponseLog bool) (provider.LargeLanguageModelProvider, error) {
+	content, err := os.ReadFile(llmConfig.RulesFilePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rulesProvider, err := newRulesLargeLanguageModelProvider(content, enableResponseLog)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rulesProvider, nil
+}
+
+func newRulesLargeLanguageModelProvider(content []byte, enableResponseLog bool) (*RulesLargeLanguageModelProvider, error) {
+	var rulesFile RulesLargeLanguageModelRulesFile
+
+	if err := json.Unmarshal(content, &rulesFile); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*rulesLargeLanguageModelCompiledRule, 0, len(rulesFile.Rules))
+
+	for i := 0; i < len(rulesFile.Rules); i++ {
+		rule, err := compileRulesLargeLanguageModelRule(rulesFile.Rules[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	defaultResponse := rulesLargeLanguageModelEmptyResponse
+
+	if len(rulesFile.DefaultResponse) > 0 {
+		response, err := compactRulesLargeLanguageModelResponse(rulesFile.DefaultResponse)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defaultResponse = response
+	}
+
+	return &RulesLargeLanguageModelProvider{
+		rules:             rules,
+		defaultResponse:   defaultResponse,
+		enableResponseLog: enableResponseLog,
+	}, nil
+}
+
+func compileRulesLargeLanguageModelRule(rule *RulesLargeLanguageModelRule) (*rulesLargeLanguageModelCompiledRule, error) {
+	if rule == nil || len(rule.Response) < 1 {
+		return nil, errs.ErrInvalidLLMRulesFile
+	}
+
+	response, err := compactRulesLargeLanguageModelResponse(rule.Response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	compiledRule := &rulesLargeLanguageModelCompiledRule{
+		name:                  rule.Name,
+		userPromptSha256:      rule.UserPromptSha256,
+		userPromptContentType: rule.UserPromptContentType,
+		response:              response,
+	}
+
+	if rule.SystemPromptPattern != "" {
+		compiledRule.systemPromptPattern, err = regexp.Compile(rule.SystemPromptPattern)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.UserPromptPattern != "" {
+		compiledRule.userPromptPattern, err = regexp.Compile(rule.UserPromptPattern)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return compiledRule, nil
+}
+
+func compactRulesLargeLanguageModelResponse(response json.RawMessage) (string, error) {
+	var buffer bytes.Buffer
+
+	if err := json.Compact(&buffer, response); err != nil {
+		return "", errs.ErrInvalidLLMRulesFile
+	}
+
+	if !bytes.HasPrefix(buffer.Bytes(), []byte("{")) && !bytes.HasPrefix(buffer.Bytes(), []byte("[")) {
+		return "", errs.ErrInvalidLLMRulesFile
+	}
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/llm/data"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const testRulesFileContent = `{
+  "rules": [
+    {
+      "name": "receipt image",
+      "system_prompt_pattern": "receipt",
+      "user_prompt_sha256": "5994471ABB01112AFCC18159F6CC74B4F511B99806DA59B3CAF5A9C173CACFC5",
+      "user_prompt_content_type": "image/png",
+      "response": {"type": "expense", "amount": "12.34"}
+    },
+    {
+      "name": "query",
+      "user_prompt_pattern": "(?i)spent on (?P<keyword>\\w+) over (\\d+)",
+      "response": {"keyword": "${keyword}", "min_amount": "${2}", "unknown": "${3}"}
+    },
+    {
+      "user_prompt_pattern": "quote",
+      "response": {"comment": "${1}"}
+    }
+  ],
+  "default_response": {"unmatched": true}
+}`
+
+func TestRulesLargeLanguageModelProvider_GetJsonResponse_MatchUserPromptSha256(t *testing.T) {
+	provider, err := newRulesLargeLanguageModelProvider([]byte(testRulesFileContent), false)
+	assert.Nil(t, err)
+
+	response, err := provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		SystemPrompt:          "Recognize the receipt image",
+		UserPrompt:            []byte("12345"),
+		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+		UserPromptContentType: "image/png",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"type\":\"expense\",\"amount\":\"12.34\"}", response.Content)
+
+	response, err = provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		SystemPrompt:          "Recognize the receipt image",
+		UserPrompt:            []byte("12345"),
+		UserPromptType:        data.LARGE_LANGUAGE_MODEL_REQUEST_PROMPT_TYPE_IMAGE_URL,
+		UserPromptContentType: "image/jpeg",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"unmatched\":true}", response.Content)
+}
+
+func TestRulesLargeLanguageModelProvider_GetJsonResponse_ReplaceSubmatches(t *testing.T) {
+	provider, err := newRulesLargeLanguageModelProvider([]byte(testRulesFileContent), false)
+	assert.Nil(t, err)
+
+	response, err := provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		UserPrompt: []byte("How much I spent on coffee over 100"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"keyword\":\"coffee\",\"min_amount\":\"100\",\"unknown\":\"${3}\"}", response.Content)
+
+	response, err = provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		UserPrompt: []byte("a \"quote\""),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"comment\":\"${1}\"}", response.Content)
+}
+
+func TestRulesLargeLanguageModelProvider_GetJsonResponse_EscapeSubmatches(t *testing.T) {
+	provider, err := newRulesLargeLanguageModelProvider([]byte(`{"rules":[{"user_prompt_pattern":"note: (.*)","response":{"comment":"${1}"}}]}`), false)
+	assert.Nil(t, err)
+
+	response, err := provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		UserPrompt: []byte("note: say \"hi\"\\"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"comment\":\"say \\\"hi\\\"\\\\\"}", response.Content)
+}
+
+func TestRulesLargeLanguageModelProvider_GetJsonResponse_EmptyDefaultResponse(t *testing.T) {
+	provider, err := newRulesLargeLanguageModelProvider([]byte(`{"rules":[]}`), false)
+	assert.Nil(t, err)
+
+	response, err := provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		UserPrompt: []byte("anything"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", response.Content)
+}
+
+func TestNewRulesLargeLanguageModelProvider_InvalidRules(t *testing.T) {
+	_, err := newRulesLargeLanguageModelProvider([]byte(`{"rules":[{"user_prompt_pattern":"test"}]}`), false)
+	assert.Equal(t, errs.ErrInvalidLLMRulesFile, err)
+
+	_, err = newRulesLargeLanguageModelProvider([]byte(`{"rules":[{"user_prompt_pattern":"(","response":{}}]}`), false)
+	assert.NotNil(t, err)
+
+	_, err = newRulesLargeLanguageModelProvider([]byte(`{"rules":[],"default_response":"not json"}`), false)
+	assert.Equal(t, errs.ErrInvalidLLMRulesFile, err)
+
+	_, err = newRulesLargeLanguageModelProvider([]byte(`not json`), false)
+	assert.NotNil(t, err)
+}
+
+func TestNewRulesLargeLanguageModelProvider_RulesFile(t *testing.T) {
+	rulesFilePath := filepath.Join(t.TempDir(), "rules.json")
+	err := os.WriteFile(rulesFilePath, []byte(testRulesFileContent), 0600)
+	assert.Nil(t, err)
+
+	provider, err := NewRulesLargeLanguageModelProvider(&settings.LLMConfig{RulesFilePath: rulesFilePath}, false)
+	assert.Nil(t, err)
+
+	response, err := provider.GetJsonResponse(core.NewNullContext(), 0, nil, &data.LargeLanguageModelRequest{
+		UserPrompt: []byte("unknown"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"unmatched\":true}", response.Content)
+
+	_, err = NewRulesLargeLanguageModelProvider(&settings.LLMConfig{RulesFilePath: filepath.Join(t.TempDir(), "not_exists.json")}, false)
+	assert.NotNil(t, err)
+}
@@ -11,6 +11,7 @@ type LocaleTextItems struct {
 	DataConverterTextItems      *DataConverterTextItems
 	VerifyEmailTextItems        *VerifyEmailTextItems
 	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	NotificationMailTextItems   *NotificationMailTextItems
 }
 
 // GlobalTextItems represents global text items need to be translated
@@ -47,3 +48,26 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// NotificationMailTextItems represents text items need to be translated in weekly digest mail and due reminder mail
+type NotificationMailTextItems struct {
+	WeeklyDigestTitle             string
+	DueReminderTitle              string
+	SalutationFormat              string
+	WeeklyDigestDescriptionFormat string
+	CashPosition                  string
+	Total                         string
+	TopExpenseCategories          string
+	BudgetDeviations              string
+	Planned                       string
+	Actual                        string
+	Deviation                     string
+	NoData                        string
+	DueReminderDescriptionFormat  string
+	Receivable                    string
+	Payable                       string
+	Tax                           string
+	EstimatedTax                  string
+	PlannedTransaction            string
+	DescriptionBottomFormat       string
+}
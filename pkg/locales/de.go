@@ -30,4 +30,25 @@ var de = &LocaleTextItems{
 		ResetPassword:             "Passwort zurücksetzen",
 		DescriptionBelowBtnFormat: "Wenn Sie nicht angefordert haben, Ihr Passwort zurückzusetzen, ignorieren Sie bitte diese E-Mail. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Link zum Zurücksetzen des Passworts wird nach %v Minuten ablaufen.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Wöchentliche Übersicht",
+		DueReminderTitle:              "Anstehende Zahlungen",
+		SalutationFormat:              "Hallo %s,",
+		WeeklyDigestDescriptionFormat: "Hier ist die Übersicht Ihrer Finanzen vom %s bis %s.",
+		CashPosition:                  "Kontostände",
+		Total:                         "Gesamt",
+		TopExpenseCategories:          "Top-Ausgabenkategorien",
+		BudgetDeviations:              "Budgetabweichungen",
+		Planned:                       "Geplant",
+		Actual:                        "Ist",
+		Deviation:                     "Abweichung",
+		NoData:                        "Keine Daten",
+		DueReminderDescriptionFormat:  "Die folgenden Zahlungen sind am %s fällig.",
+		Receivable:                    "Forderung",
+		Payable:                       "Verbindlichkeit",
+		Tax:                           "Steuer",
+		EstimatedTax:                  "Geschätzte Steuer",
+		PlannedTransaction:            "Geplante Transaktion",
+		DescriptionBottomFormat:       "Sie erhalten diese E-Mail, weil Sie E-Mail-Benachrichtigungen in %s aktiviert haben. Sie können sie in den Benachrichtigungseinstellungen deaktivieren.",
+	},
 }
@@ -30,4 +30,25 @@ var en = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Weekly Digest",
+		DueReminderTitle:              "Upcoming Payments",
+		SalutationFormat:              "Hi %s,",
+		WeeklyDigestDescriptionFormat: "Here is the summary of your finances from %s to %s.",
+		CashPosition:                  "Cash Position",
+		Total:                         "Total",
+		TopExpenseCategories:          "Top Expense Categories",
+		BudgetDeviations:              "Budget Deviations",
+		Planned:                       "Planned",
+		Actual:                        "Actual",
+		Deviation:                     "Deviation",
+		NoData:                        "No data",
+		DueReminderDescriptionFormat:  "The following payments are due on %s.",
+		Receivable:                    "Receivable",
+		Payable:                       "Payable",
+		Tax:                           "Tax",
+		EstimatedTax:                  "Estimated Tax",
+		PlannedTransaction:            "Planned Transaction",
+		DescriptionBottomFormat:       "You receive this email because you turned on email notifications in %s. You can turn them off in the notification settings.",
+	},
 }
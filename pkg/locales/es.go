@@ -30,4 +30,25 @@ var es = &LocaleTextItems{
 		ResetPassword:             "Restablecer Contraseña",
 		DescriptionBelowBtnFormat: "Si no solicitó un restablecimiento de contraseña, simplemente descarte este correo. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegadror. El enlace de restablecimiento de contraseña expira pasados %v minutos.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Resumen semanal",
+		DueReminderTitle:              "Próximos pagos",
+		SalutationFormat:              "Hola %s,",
+		WeeklyDigestDescriptionFormat: "Este es el resumen de sus finanzas del %s al %s.",
+		CashPosition:                  "Saldos de cuentas",
+		Total:                         "Total",
+		TopExpenseCategories:          "Principales categorías de gastos",
+		BudgetDeviations:              "Desviaciones del presupuesto",
+		Planned:                       "Planificado",
+		Actual:                        "Real",
+		Deviation:                     "Desviación",
+		NoData:                        "Sin datos",
+		DueReminderDescriptionFormat:  "Los siguientes pagos vencen el %s.",
+		Receivable:                    "Por cobrar",
+		Payable:                       "Por pagar",
+		Tax:                           "Impuesto",
+		EstimatedTax:                  "Impuesto estimado",
+		PlannedTransaction:            "Transacción planificada",
+		DescriptionBottomFormat:       "Recibe este correo electrónico porque activó las notificaciones por correo electrónico en %s. Puede desactivarlas en la configuración de notificaciones.",
+	},
 }
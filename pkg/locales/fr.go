@@ -30,4 +30,25 @@ var fr = &LocaleTextItems{
 		ResetPassword:             "Réinitialiser le mot de passe",
 		DescriptionBelowBtnFormat: "Si vous n'avez pas demandé la réinitialisation de votre mot de passe, vous pouvez ignorer cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien de réinitialisation du mot de passe expire après %v minutes.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Résumé hebdomadaire",
+		DueReminderTitle:              "Paiements à venir",
+		SalutationFormat:              "Bonjour %s,",
+		WeeklyDigestDescriptionFormat: "Voici le résumé de vos finances du %s au %s.",
+		CashPosition:                  "Soldes des comptes",
+		Total:                         "Total",
+		TopExpenseCategories:          "Principales catégories de dépenses",
+		BudgetDeviations:              "Écarts budgétaires",
+		Planned:                       "Prévu",
+		Actual:                        "Réel",
+		Deviation:                     "Écart",
+		NoData:                        "Aucune donnée",
+		DueReminderDescriptionFormat:  "Les paiements suivants arrivent à échéance le %s.",
+		Receivable:                    "Créance",
+		Payable:                       "Dette",
+		Tax:                           "Impôt",
+		EstimatedTax:                  "Impôt estimé",
+		PlannedTransaction:            "Transaction planifiée",
+		DescriptionBottomFormat:       "Vous recevez cet e-mail car vous avez activé les notifications par e-mail dans %s. Vous pouvez les désactiver dans les paramètres de notification.",
+	},
 }
@@ -30,4 +30,25 @@ var it = &LocaleTextItems{
 		ResetPassword:             "Reimposta password",
 		DescriptionBelowBtnFormat: "Se non hai chiesto alcun cambio della password, puoi ignorare questa mail. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di verifica scadrà tra %v minuti.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Riepilogo settimanale",
+		DueReminderTitle:              "Pagamenti in arrivo",
+		SalutationFormat:              "Ciao %s,",
+		WeeklyDigestDescriptionFormat: "Ecco il riepilogo delle tue finanze dal %s al %s.",
+		CashPosition:                  "Saldi dei conti",
+		Total:                         "Totale",
+		TopExpenseCategories:          "Principali categorie di spesa",
+		BudgetDeviations:              "Scostamenti dal budget",
+		Planned:                       "Pianificato",
+		Actual:                        "Effettivo",
+		Deviation:                     "Scostamento",
+		NoData:                        "Nessun dato",
+		DueReminderDescriptionFormat:  "I seguenti pagamenti scadono il %s.",
+		Receivable:                    "Credito",
+		Payable:                       "Debito",
+		Tax:                           "Imposta",
+		EstimatedTax:                  "Imposta stimata",
+		PlannedTransaction:            "Transazione pianificata",
+		DescriptionBottomFormat:       "Ricevi questa email perché hai attivato le notifiche email in %s. Puoi disattivarle nelle impostazioni delle notifiche.",
+	},
 }
@@ -30,4 +30,25 @@ var ja = &LocaleTextItems{
 		ResetPassword:             "パスワードをリセット",
 		DescriptionBelowBtnFormat: "パスワードのリセットをリクエストしていない場合はこのメールを無視してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。パスワードリセットのリンクは%v分後に期限切れになります。",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "週次ダイジェスト",
+		DueReminderTitle:              "今後の支払い",
+		SalutationFormat:              "こんにちは%s,",
+		WeeklyDigestDescriptionFormat: "%sから%sまでの家計の概要です。",
+		CashPosition:                  "口座残高",
+		Total:                         "合計",
+		TopExpenseCategories:          "支出の多いカテゴリ",
+		BudgetDeviations:              "予算との差異",
+		Planned:                       "予算",
+		Actual:                        "実績",
+		Deviation:                     "差異",
+		NoData:                        "データがありません",
+		DueReminderDescriptionFormat:  "以下の支払いは%sが期日です。",
+		Receivable:                    "売掛金",
+		Payable:                       "買掛金",
+		Tax:                           "税金",
+		EstimatedTax:                  "見積税額",
+		PlannedTransaction:            "予定取引",
+		DescriptionBottomFormat:       "%sでメール通知を有効にしているため、このメールが送信されました。通知設定で無効にできます。",
+	},
 }
@@ -30,4 +30,25 @@ var kn = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "ಸಾಪ್ತಾಹಿಕ ಸಾರಾಂಶ",
+		DueReminderTitle:              "ಮುಂಬರುವ ಪಾವತಿಗಳು",
+		SalutationFormat:              "ಹಲೋ %s,",
+		WeeklyDigestDescriptionFormat: "%s ರಿಂದ %s ವರೆಗಿನ ನಿಮ್ಮ ಹಣಕಾಸಿನ ಸಾರಾಂಶ ಇಲ್ಲಿದೆ.",
+		CashPosition:                  "ಖಾತೆ ಬಾಕಿಗಳು",
+		Total:                         "ಒಟ್ಟು",
+		TopExpenseCategories:          "ಪ್ರಮುಖ ವೆಚ್ಚದ ವರ್ಗಗಳು",
+		BudgetDeviations:              "ಬಜೆಟ್ ವ್ಯತ್ಯಾಸಗಳು",
+		Planned:                       "ಯೋಜಿತ",
+		Actual:                        "ವಾಸ್ತವಿಕ",
+		Deviation:                     "ವ್ಯತ್ಯಾಸ",
+		NoData:                        "ಯಾವುದೇ ಡೇಟಾ ಇಲ್ಲ",
+		DueReminderDescriptionFormat:  "ಈ ಕೆಳಗಿನ ಪಾವತಿಗಳು %s ರಂದು ಬಾಕಿ ಇವೆ.",
+		Receivable:                    "ಸ್ವೀಕರಿಸಬೇಕಾದದ್ದು",
+		Payable:                       "ಪಾವತಿಸಬೇಕಾದದ್ದು",
+		Tax:                           "ತೆರಿಗೆ",
+		EstimatedTax:                  "ಅಂದಾಜು ತೆರಿಗೆ",
+		PlannedTransaction:            "ಯೋಜಿತ ವಹಿವಾಟು",
+		DescriptionBottomFormat:       "ನೀವು %s ನಲ್ಲಿ ಇಮೇಲ್ ಅಧಿಸೂಚನೆಗಳನ್ನು ಆನ್ ಮಾಡಿರುವುದರಿಂದ ಈ ಇಮೇಲ್ ಅನ್ನು ಸ್ವೀಕರಿಸುತ್ತಿದ್ದೀರಿ. ಅಧಿಸೂಚನೆ ಸೆಟ್ಟಿಂಗ್‌ಗಳಲ್ಲಿ ನೀವು ಅವುಗಳನ್ನು ಆಫ್ ಮಾಡಬಹುದು.",
+	},
 }
@@ -30,4 +30,25 @@ var ko = &LocaleTextItems{
 		ResetPassword:             "비밀번호 재설정",
 		DescriptionBelowBtnFormat: "비밀번호 재설정을 요청하지 않으셨다면 이 이메일을 무시해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 비밀번호 재설정 링크는 %v분 후에 만료됩니다.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "주간 요약",
+		DueReminderTitle:              "예정된 결제",
+		SalutationFormat:              "안녕하세요 %s님,",
+		WeeklyDigestDescriptionFormat: "%s부터 %s까지의 재정 요약입니다.",
+		CashPosition:                  "계좌 잔액",
+		Total:                         "합계",
+		TopExpenseCategories:          "주요 지출 카테고리",
+		BudgetDeviations:              "예산 편차",
+		Planned:                       "계획",
+		Actual:                        "실적",
+		Deviation:                     "편차",
+		NoData:                        "데이터 없음",
+		DueReminderDescriptionFormat:  "다음 결제의 기한은 %s입니다.",
+		Receivable:                    "미수금",
+		Payable:                       "미지급금",
+		Tax:                           "세금",
+		EstimatedTax:                  "예상 세금",
+		PlannedTransaction:            "예정된 거래",
+		DescriptionBottomFormat:       "%s에서 이메일 알림을 켰기 때문에 이 이메일을 받았습니다. 알림 설정에서 끌 수 있습니다.",
+	},
 }
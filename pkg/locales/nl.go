@@ -30,4 +30,25 @@ var nl = &LocaleTextItems{
 		ResetPassword:             "Wachtwoord opnieuw instellen",
 		DescriptionBelowBtnFormat: "Als je geen verzoek hebt gedaan om je wachtwoord te resetten, kun je deze e-mail negeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De link voor het opnieuw instellen van het wachtwoord verloopt na  %v minuten.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Wekelijks overzicht",
+		DueReminderTitle:              "Aankomende betalingen",
+		SalutationFormat:              "Hallo %s,",
+		WeeklyDigestDescriptionFormat: "Hier is het overzicht van uw financiën van %s tot %s.",
+		CashPosition:                  "Rekeningsaldi",
+		Total:                         "Totaal",
+		TopExpenseCategories:          "Grootste uitgavencategorieën",
+		BudgetDeviations:              "Budgetafwijkingen",
+		Planned:                       "Gepland",
+		Actual:                        "Werkelijk",
+		Deviation:                     "Afwijking",
+		NoData:                        "Geen gegevens",
+		DueReminderDescriptionFormat:  "De volgende betalingen vervallen op %s.",
+		Receivable:                    "Vordering",
+		Payable:                       "Schuld",
+		Tax:                           "Belasting",
+		EstimatedTax:                  "Geschatte belasting",
+		PlannedTransaction:            "Geplande transactie",
+		DescriptionBottomFormat:       "U ontvangt deze e-mail omdat u e-mailmeldingen hebt ingeschakeld in %s. U kunt ze uitschakelen in de meldingsinstellingen.",
+	},
 }
@@ -30,4 +30,25 @@ var ptBR = &LocaleTextItems{
 		ResetPassword:             "Redefinir Senha",
 		DescriptionBelowBtnFormat: "Se você não solicitou a redefinição de senha, basta ignorar este e-mail. Se não conseguir clicar no link acima, copie a URL acima e cole no seu navegador. O link de redefinição de senha expirará após %v minutos.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Resumo semanal",
+		DueReminderTitle:              "Próximos pagamentos",
+		SalutationFormat:              "Olá %s,",
+		WeeklyDigestDescriptionFormat: "Aqui está o resumo das suas finanças de %s a %s.",
+		CashPosition:                  "Saldos das contas",
+		Total:                         "Total",
+		TopExpenseCategories:          "Principais categorias de despesas",
+		BudgetDeviations:              "Desvios do orçamento",
+		Planned:                       "Planejado",
+		Actual:                        "Realizado",
+		Deviation:                     "Desvio",
+		NoData:                        "Sem dados",
+		DueReminderDescriptionFormat:  "Os seguintes pagamentos vencem em %s.",
+		Receivable:                    "A receber",
+		Payable:                       "A pagar",
+		Tax:                           "Imposto",
+		EstimatedTax:                  "Imposto estimado",
+		PlannedTransaction:            "Transação planejada",
+		DescriptionBottomFormat:       "Você está recebendo este e-mail porque ativou as notificações por e-mail no %s. Você pode desativá-las nas configurações de notificação.",
+	},
 }
@@ -30,4 +30,25 @@ var ru = &LocaleTextItems{
 		ResetPassword:             "Сбросить пароль",
 		DescriptionBelowBtnFormat: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка для сброса пароля истечет через %v минут.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Еженедельная сводка",
+		DueReminderTitle:              "Предстоящие платежи",
+		SalutationFormat:              "Здравствуйте %s,",
+		WeeklyDigestDescriptionFormat: "Сводка ваших финансов с %s по %s.",
+		CashPosition:                  "Остатки на счетах",
+		Total:                         "Итого",
+		TopExpenseCategories:          "Основные категории расходов",
+		BudgetDeviations:              "Отклонения от бюджета",
+		Planned:                       "План",
+		Actual:                        "Факт",
+		Deviation:                     "Отклонение",
+		NoData:                        "Нет данных",
+		DueReminderDescriptionFormat:  "Срок оплаты следующих платежей — %s.",
+		Receivable:                    "Дебиторская задолженность",
+		Payable:                       "Кредиторская задолженность",
+		Tax:                           "Налог",
+		EstimatedTax:                  "Расчётный налог",
+		PlannedTransaction:            "Запланированная транзакция",
+		DescriptionBottomFormat:       "Вы получили это письмо, потому что включили уведомления по электронной почте в %s. Отключить их можно в настройках уведомлений.",
+	},
 }
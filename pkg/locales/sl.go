@@ -30,4 +30,25 @@ var sl = &LocaleTextItems{
 		ResetPassword:             "Ponastavi geslo",
 		DescriptionBelowBtnFormat: "Če niste zahtevali ponastavitve gesla, prosimo, da to e-poštno sporočilo preprosto prezrete. Če ne morete klikniti zgornje povezave, kopirajte zgornji URL in ga prilepite v brskalnik. Povezava za ponastavitev gesla bo potekla po %v minutah.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Tedenski povzetek",
+		DueReminderTitle:              "Prihajajoča plačila",
+		SalutationFormat:              "Zdravo %s,",
+		WeeklyDigestDescriptionFormat: "Tukaj je povzetek vaših financ od %s do %s.",
+		CashPosition:                  "Stanja računov",
+		Total:                         "Skupaj",
+		TopExpenseCategories:          "Največje kategorije odhodkov",
+		BudgetDeviations:              "Odstopanja od proračuna",
+		Planned:                       "Načrtovano",
+		Actual:                        "Dejansko",
+		Deviation:                     "Odstopanje",
+		NoData:                        "Ni podatkov",
+		DueReminderDescriptionFormat:  "Naslednja plačila zapadejo %s.",
+		Receivable:                    "Terjatev",
+		Payable:                       "Obveznost",
+		Tax:                           "Davek",
+		EstimatedTax:                  "Ocenjeni davek",
+		PlannedTransaction:            "Načrtovana transakcija",
+		DescriptionBottomFormat:       "To e-poštno sporočilo prejemate, ker ste v %s vklopili e-poštna obvestila. Izklopite jih lahko v nastavitvah obvestil.",
+	},
 }
@@ -30,4 +30,25 @@ var ta = &LocaleTextItems{
 		ResetPassword:             "கடவுச்சொல்லை மீட்டமை",
 		DescriptionBelowBtnFormat: "உங்கள் கடவுச்சொல்லை மீட்டமைக்க நீங்கள் கோரவில்லை என்றால், இந்த மின்னஞ்சலை புறக்கணிக்கவும். மேலே உள்ள இணைப்பைக் கிளிக் செய்ய முடியவில்லை என்றால், மேலே உள்ள URL ஐ நகலெடுத்து உங்கள் உலாவியில் ஒட்டவும். கடவுச்சொல் மீட்டமைப்பு இணைப்பு %v நிமிடங்களுக்குப் பிறகு காலாவதியாகும்.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "வாராந்திர சுருக்கம்",
+		DueReminderTitle:              "வரவிருக்கும் கொடுப்பனவுகள்",
+		SalutationFormat:              "வணக்கம் %s,",
+		WeeklyDigestDescriptionFormat: "%s முதல் %s வரையிலான உங்கள் நிதிகளின் சுருக்கம் இங்கே.",
+		CashPosition:                  "கணக்கு இருப்புகள்",
+		Total:                         "மொத்தம்",
+		TopExpenseCategories:          "முக்கிய செலவு வகைகள்",
+		BudgetDeviations:              "பட்ஜெட் விலகல்கள்",
+		Planned:                       "திட்டமிடப்பட்டது",
+		Actual:                        "உண்மையானது",
+		Deviation:                     "விலகல்",
+		NoData:                        "தரவு இல்லை",
+		DueReminderDescriptionFormat:  "பின்வரும் கொடுப்பனவுகள் %s அன்று செலுத்தப்பட வேண்டும்.",
+		Receivable:                    "பெற வேண்டியது",
+		Payable:                       "செலுத்த வேண்டியது",
+		Tax:                           "வரி",
+		EstimatedTax:                  "மதிப்பிடப்பட்ட வரி",
+		PlannedTransaction:            "திட்டமிடப்பட்ட பரிவர்த்தனை",
+		DescriptionBottomFormat:       "%s இல் மின்னஞ்சல் அறிவிப்புகளை இயக்கியதால் இந்த மின்னஞ்சலைப் பெறுகிறீர்கள். அறிவிப்பு அமைப்புகளில் அவற்றை அணைக்கலாம்.",
+	},
 }
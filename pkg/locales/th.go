@@ -30,4 +30,25 @@ var th = &LocaleTextItems{
 		ResetPassword:             "ตั้งรหัสผ่านใหม่",
 		DescriptionBelowBtnFormat: "หากคุณไม่ได้ร้องขอให้รีเซ็ตรหัสผ่าน โปรดละเว้นอีเมลนี้ หากคุณไม่สามารถคลิกลิงก์ด้านบน โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ ลิงก์รีเซ็ตรหัสผ่านจะหมดอายุหลังจาก %v นาที",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "สรุปรายสัปดาห์",
+		DueReminderTitle:              "การชำระเงินที่กำลังจะมาถึง",
+		SalutationFormat:              "สวัสดี %s,",
+		WeeklyDigestDescriptionFormat: "นี่คือสรุปการเงินของคุณตั้งแต่ %s ถึง %s",
+		CashPosition:                  "ยอดคงเหลือในบัญชี",
+		Total:                         "รวม",
+		TopExpenseCategories:          "หมวดหมู่ค่าใช้จ่ายสูงสุด",
+		BudgetDeviations:              "ส่วนต่างจากงบประมาณ",
+		Planned:                       "ตามแผน",
+		Actual:                        "ตามจริง",
+		Deviation:                     "ส่วนต่าง",
+		NoData:                        "ไม่มีข้อมูล",
+		DueReminderDescriptionFormat:  "การชำระเงินต่อไปนี้ครบกำหนดในวันที่ %s",
+		Receivable:                    "ลูกหนี้",
+		Payable:                       "เจ้าหนี้",
+		Tax:                           "ภาษี",
+		EstimatedTax:                  "ภาษีโดยประมาณ",
+		PlannedTransaction:            "รายการที่วางแผนไว้",
+		DescriptionBottomFormat:       "คุณได้รับอีเมลนี้เนื่องจากคุณเปิดการแจ้งเตือนทางอีเมลใน %s คุณสามารถปิดได้ในการตั้งค่าการแจ้งเตือน",
+	},
 }
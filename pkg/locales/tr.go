@@ -30,4 +30,25 @@ var tr = &LocaleTextItems{
 		ResetPassword:             "Şifreyi Sıfırla",
 		DescriptionBelowBtnFormat: "Eğer şifre sıfırlama talebinde bulunmadıysanız, lütfen bu e-postayı dikkate almayın. Eğer yukarıdaki bağlantıya tıklayamıyorsanız, lütfen adresi kopyalayıp tarayıcınıza yapıştırın. Şifre sıfırlama bağlantısının süresi %v dakika sonra dolacaktır.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Haftalık Özet",
+		DueReminderTitle:              "Yaklaşan Ödemeler",
+		SalutationFormat:              "Merhaba %s,",
+		WeeklyDigestDescriptionFormat: "%s ile %s arasındaki finansal özetiniz aşağıdadır.",
+		CashPosition:                  "Hesap Bakiyeleri",
+		Total:                         "Toplam",
+		TopExpenseCategories:          "En Çok Harcanan Kategoriler",
+		BudgetDeviations:              "Bütçe Sapmaları",
+		Planned:                       "Planlanan",
+		Actual:                        "Gerçekleşen",
+		Deviation:                     "Sapma",
+		NoData:                        "Veri yok",
+		DueReminderDescriptionFormat:  "Aşağıdaki ödemelerin son tarihi %s.",
+		Receivable:                    "Alacak",
+		Payable:                       "Borç",
+		Tax:                           "Vergi",
+		EstimatedTax:                  "Tahmini Vergi",
+		PlannedTransaction:            "Planlanan İşlem",
+		DescriptionBottomFormat:       "Bu e-postayı %s içinde e-posta bildirimlerini açtığınız için alıyorsunuz. Bildirim ayarlarından kapatabilirsiniz.",
+	},
 }
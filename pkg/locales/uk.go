@@ -30,4 +30,25 @@ var uk = &LocaleTextItems{
 		ResetPassword:             "Скинути пароль",
 		DescriptionBelowBtnFormat: "Якщо ви не надсилали запит на скидання пароля, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання для скидання пароля буде дійсне протягом %v хвилин.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Щотижневий звіт",
+		DueReminderTitle:              "Майбутні платежі",
+		SalutationFormat:              "Вітаємо, %s!",
+		WeeklyDigestDescriptionFormat: "Підсумок ваших фінансів з %s по %s.",
+		CashPosition:                  "Залишки на рахунках",
+		Total:                         "Разом",
+		TopExpenseCategories:          "Основні категорії витрат",
+		BudgetDeviations:              "Відхилення від бюджету",
+		Planned:                       "План",
+		Actual:                        "Факт",
+		Deviation:                     "Відхилення",
+		NoData:                        "Немає даних",
+		DueReminderDescriptionFormat:  "Термін сплати наступних платежів — %s.",
+		Receivable:                    "Дебіторська заборгованість",
+		Payable:                       "Кредиторська заборгованість",
+		Tax:                           "Податок",
+		EstimatedTax:                  "Розрахунковий податок",
+		PlannedTransaction:            "Запланована транзакція",
+		DescriptionBottomFormat:       "Ви отримали цей лист, тому що увімкнули сповіщення електронною поштою в %s. Вимкнути їх можна в налаштуваннях сповіщень.",
+	},
 }
@@ -30,4 +30,25 @@ var vi = &LocaleTextItems{
 		ResetPassword:             "Đặt lại Mật khẩu",
 		DescriptionBelowBtnFormat: "Nếu bạn không yêu cầu đặt lại mật khẩu, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết đặt lại mật khẩu sẽ hết hạn sau %v phút.",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "Tóm tắt hàng tuần",
+		DueReminderTitle:              "Các khoản thanh toán sắp tới",
+		SalutationFormat:              "Chào %s,",
+		WeeklyDigestDescriptionFormat: "Đây là tóm tắt tài chính của bạn từ %s đến %s.",
+		CashPosition:                  "Số dư tài khoản",
+		Total:                         "Tổng cộng",
+		TopExpenseCategories:          "Danh mục chi tiêu hàng đầu",
+		BudgetDeviations:              "Chênh lệch ngân sách",
+		Planned:                       "Kế hoạch",
+		Actual:                        "Thực tế",
+		Deviation:                     "Chênh lệch",
+		NoData:                        "Không có dữ liệu",
+		DueReminderDescriptionFormat:  "Các khoản thanh toán sau đến hạn vào %s.",
+		Receivable:                    "Khoản phải thu",
+		Payable:                       "Khoản phải trả",
+		Tax:                           "Thuế",
+		EstimatedTax:                  "Thuế ước tính",
+		PlannedTransaction:            "Giao dịch dự kiến",
+		DescriptionBottomFormat:       "Bạn nhận được email này vì bạn đã bật thông báo qua email trong %s. Bạn có thể tắt chúng trong cài đặt thông báo.",
+	},
 }
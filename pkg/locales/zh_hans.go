@@ -30,4 +30,25 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "每周摘要",
+		DueReminderTitle:              "即将到期的付款",
+		SalutationFormat:              "%s 您好，",
+		WeeklyDigestDescriptionFormat: "以下是您从 %s 到 %s 的财务摘要。",
+		CashPosition:                  "账户余额",
+		Total:                         "合计",
+		TopExpenseCategories:          "主要支出分类",
+		BudgetDeviations:              "预算偏差",
+		Planned:                       "计划",
+		Actual:                        "实际",
+		Deviation:                     "偏差",
+		NoData:                        "暂无数据",
+		DueReminderDescriptionFormat:  "以下付款将于 %s 到期。",
+		Receivable:                    "应收款",
+		Payable:                       "应付款",
+		Tax:                           "税款",
+		EstimatedTax:                  "预估税款",
+		PlannedTransaction:            "计划交易",
+		DescriptionBottomFormat:       "您收到此邮件是因为您在 %s 中开启了邮件通知。您可以在通知设置中关闭它们。",
+	},
 }
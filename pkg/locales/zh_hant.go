@@ -30,4 +30,25 @@ var zhHant = &LocaleTextItems{
 		ResetPassword:             "重設密碼",
 		DescriptionBelowBtnFormat: "如果您沒有請求重設密碼，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。重設密碼連結將在 %v 分鐘後過期。",
 	},
+	NotificationMailTextItems: &NotificationMailTextItems{
+		WeeklyDigestTitle:             "每週摘要",
+		DueReminderTitle:              "即將到期的付款",
+		SalutationFormat:              "%s 您好，",
+		WeeklyDigestDescriptionFormat: "以下是您從 %s 到 %s 的財務摘要。",
+		CashPosition:                  "帳戶餘額",
+		Total:                         "合計",
+		TopExpenseCategories:          "主要支出分類",
+		BudgetDeviations:              "預算偏差",
+		Planned:                       "計劃",
+		Actual:                        "實際",
+		Deviation:                     "偏差",
+		NoData:                        "暫無資料",
+		DueReminderDescriptionFormat:  "以下付款將於 %s 到期。",
+		Receivable:                    "應收款",
+		Payable:                       "應付款",
+		Tax:                           "稅款",
+		EstimatedTax:                  "預估稅款",
+		PlannedTransaction:            "計劃交易",
+		DescriptionBottomFormat:       "您收到此郵件是因為您在 %s 中開啟了郵件通知。您可以在通知設定中關閉它們。",
+	},
 }
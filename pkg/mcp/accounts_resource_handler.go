@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPAccountsResource represents the contents of the chart of accounts resource
+type MCPAccountsResource struct {
+	Accounts []*MCPAccountResourceInfo `json:"accounts"`
+}
+
+// MCPAccountResourceInfo defines the structure of account information in the chart of accounts resource
+type MCPAccountResourceInfo struct {
+	Name        string                    `json:"name"`
+	Category    string                    `json:"category"`
+	Type        string                    `json:"type"`
+	Currency    string                    `json:"currency,omitempty"`
+	Balance     string                    `json:"balance,omitempty"`
+	Comment     string                    `json:"comment,omitempty"`
+	SubAccounts []*MCPAccountResourceInfo `json:"sub_accounts,omitempty"`
+}
+
+var mcpAccountCategoryNames = map[models.AccountCategory]string{
+	models.ACCOUNT_CATEGORY_CASH:                   "cash",
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       "checking",
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        "savings",
+	models.ACCOUNT_CATEGORY_CREDIT_CARD:            "credit_card",
+	models.ACCOUNT_CATEGORY_VIRTUAL:                "virtual",
+	models.ACCOUNT_CATEGORY_DEBT:                   "debt",
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            "receivables",
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: "certificate_of_deposit",
+	models.ACCOUNT_CATEGORY_INVESTMENT:             "investment",
+}
+
+type mcpAccountsResourceHandler struct{}
+
+var MCPAccountsResourceHandler = &mcpAccountsResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpAccountsResourceHandler) URI() string {
+	return "ezbookkeeping://accounts"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpAccountsResourceHandler) Name() string {
+	return "Chart of accounts"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpAccountsResourceHandler) Description() string {
+	return "All visible accounts with their categories, currencies, current balances and sub-accounts."
+}
+
+// MimeType returns the MIME type of the MCP resource contents
+func (h *mcpAccountsResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpAccountsResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	uid := user.Uid
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[accounts_resource.Read] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	resource := MCPAccountsResource{
+		Accounts: make([]*MCPAccountResourceInfo, 0, len(accounts)),
+	}
+
+	accountInfos := make(map[int64]*MCPAccountResourceInfo, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Hidden || account.ParentAccountId != models.LevelOneAccountParentId {
+			continue
+		}
+
+		accountInfo := h.createNewMCPAccountResourceInfo(account)
+		accountInfos[account.AccountId] = accountInfo
+		resource.Accounts = append(resource.Accounts, accountInfo)
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Hidden || account.ParentAccountId == models.LevelOneAccountParentId {
+			continue
+		}
+
+		if parentAccountInfo, exists := accountInfos[account.ParentAccountId]; exists {
+			parentAccountInfo.SubAccounts = append(parentAccountInfo.SubAccounts, h.createNewMCPAccountResourceInfo(account))
+		}
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(h.URI(), string(content), h.MimeType()), nil
+}
+
+func (h *mcpAccountsResourceHandler) createNewMCPAccountResourceInfo(account *models.Account) *MCPAccountResourceInfo {
+	accountInfo := &MCPAccountResourceInfo{
+		Name:     account.Name,
+		Category: mcpAccountCategoryNames[account.Category],
+		Comment:  account.Comment,
+	}
+
+	if account.Category.IsLiability() {
+		accountInfo.Type = "liability"
+	} else {
+		accountInfo.Type = "asset"
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		accountInfo.Currency = account.Currency
+		accountInfo.Balance = utils.FormatAmount(account.Balance)
+	}
+
+	return accountInfo
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPAddObligationRequest represents all parameters of the add obligation request
+type MCPAddObligationRequest struct {
+	Type             string `json:"type" jsonschema:"enum=receivable,enum=payable" jsonschema_description:"Obligation type (receivable, payable)"`
+	CounterpartyName string `json:"counterparty_name,omitempty" jsonschema_description:"Counterparty name of the obligation (optional)"`
+	CfoName          string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the obligation (optional)"`
+	Amount           string `json:"amount" jsonschema_description:"Total amount of the obligation"`
+	PaidAmount       string `json:"paid_amount,omitempty" jsonschema_description:"Already paid amount of the obligation (optional, default 0)"`
+	Currency         string `json:"currency,omitempty" jsonschema_description:"Currency code of the obligation (e.g. USD, EUR) (optional, default currency of user if empty)"`
+	DueDate          string `json:"due_date,omitempty" jsonschema:"format=date-time" jsonschema_description:"Due date of the obligation in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional)"`
+	Comment          string `json:"comment,omitempty" jsonschema_description:"Description of the obligation (optional)"`
+	DryRun           bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the obligation will not be created, only the changes to be applied are returned (optional)"`
+}
+
+type mcpAddObligationToolHandler struct{}
+
+var MCPAddObligationToolHandler = &mcpAddObligationToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddObligationToolHandler) Name() string {
+	return "add_obligation"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddObligationToolHandler) Description() string {
+	return "Add a new receivable or payable (obligation to or from a counterparty) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddObligationToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddObligationRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddObligationToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddObligationToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addObligationRequest MCPAddObligationRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addObligationRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	obligation, err := h.createNewObligationModel(c, uid, user.DefaultCurrency, &addObligationRequest, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, counterpartyNames, err := getMCPCounterpartyNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obligationInfo := MCPQueryObligationsToolHandler.createNewMCPObligationInfo(obligation, counterpartyNames, cfoNames)
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_CREATE,
+		entityType: mcpEntityTypeObligation,
+		changes: getMCPFieldChanges(nil, []*mcpEntityField{
+			{name: "type", value: obligationInfo.Type},
+			{name: "status", value: obligationInfo.Status},
+			{name: "counterparty_name", value: obligationInfo.CounterpartyName},
+			{name: "cfo_name", value: obligationInfo.CfoName},
+			{name: "amount", value: obligationInfo.Amount},
+			{name: "paid_amount", value: obligationInfo.PaidAmount},
+			{name: "currency", value: obligationInfo.Currency},
+			{name: "due_date", value: obligationInfo.DueDate},
+			{name: "comment", value: obligationInfo.Comment},
+		}),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, addObligationRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetObligationService().CreateObligationInSession(c, sess, obligation)
+
+		if err != nil {
+			log.Errorf(c, "[add_obligation.Handle] failed to create obligation for user \"uid:%d\", because %s", uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[add_obligation.Handle] user \"uid:%d\" has created a new obligation \"id:%d\" successfully", uid, obligation.ObligationId)
+
+		return obligation.ObligationId, nil
+	})
+}
+
+func (h *mcpAddObligationToolHandler) createNewObligationModel(c *core.WebContext, uid int64, defaultCurrency string, addObligationRequest *MCPAddObligationRequest, services MCPAvailableServices) (*models.Obligation, error) {
+	var obligationType models.ObligationType
+
+	if addObligationRequest.Type == obligationTypeReceivable {
+		obligationType = models.OBLIGATION_TYPE_RECEIVABLE
+	} else if addObligationRequest.Type == obligationTypePayable {
+		obligationType = models.OBLIGATION_TYPE_PAYABLE
+	} else {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	amount, err := utils.ParseAmount(addObligationRequest.Amount)
+
+	if err != nil || amount <= 0 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	paidAmount := int64(0)
+
+	if addObligationRequest.PaidAmount != "" {
+		paidAmount, err = utils.ParseAmount(addObligationRequest.PaidAmount)
+
+		if err != nil || paidAmount < 0 {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(addObligationRequest.Currency))
+
+	if currency == "" {
+		currency = defaultCurrency
+	} else if len(currency) != 3 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	dueDate, err := parseMCPOptionalTime(addObligationRequest.DueDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, addObligationRequest.CfoName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	counterparties, _, err := getMCPCounterpartyNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	counterpartyId, err := getMCPCounterpartyIdByName(counterparties, addObligationRequest.CounterpartyName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Obligation{
+		Uid:            uid,
+		ObligationType: obligationType,
+		CounterpartyId: counterpartyId,
+		CfoId:          cfoId,
+		Amount:         amount,
+		Currency:       currency,
+		DueDate:        dueDate,
+		Status:         models.GetObligationStatus(amount, paidAmount),
+		PaidAmount:     paidAmount,
+		Comment:        addObligationRequest.Comment,
+	}, nil
+}
@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPAddTaxRecordRequest represents all parameters of the add tax record request
+type MCPAddTaxRecordRequest struct {
+	TaxType       string `json:"tax_type" jsonschema:"enum=income,enum=vat,enum=property,enum=other" jsonschema_description:"Tax type (income, vat, property, other)"`
+	PeriodYear    int32  `json:"period_year" jsonschema_description:"Year of the tax period"`
+	PeriodQuarter int32  `json:"period_quarter,omitempty" jsonschema:"minimum=0,maximum=4" jsonschema_description:"Quarter of the tax period (1-4) (optional, 0 or empty for annual taxes)"`
+	TaxableIncome string `json:"taxable_income,omitempty" jsonschema_description:"Taxable base of the tax period (optional, default 0)"`
+	TaxAmount     string `json:"tax_amount" jsonschema_description:"Amount of tax to be paid"`
+	PaidAmount    string `json:"paid_amount,omitempty" jsonschema_description:"Paid amount of tax (optional, default 0)"`
+	Currency      string `json:"currency,omitempty" jsonschema_description:"Currency code of the tax record (e.g. USD, EUR) (optional, default currency of user if empty)"`
+	DueDate       string `json:"due_date,omitempty" jsonschema:"format=date-time" jsonschema_description:"Due date of the tax payment in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional)"`
+	CfoName       string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the tax record (optional)"`
+	Comment       string `json:"comment,omitempty" jsonschema_description:"Description of the tax record (optional)"`
+	DryRun        bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the tax record will not be created, only the changes to be applied are returned (optional)"`
+}
+
+type mcpAddTaxRecordToolHandler struct{}
+
+var MCPAddTaxRecordToolHandler = &mcpAddTaxRecordToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddTaxRecordToolHandler) Name() string {
+	return "add_tax_record"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddTaxRecordToolHandler) Description() string {
+	return "Add a new tax record (tax calculated, paid and due for a tax period) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddTaxRecordToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddTaxRecordRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddTaxRecordToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddTaxRecordToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addTaxRecordRequest MCPAddTaxRecordRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addTaxRecordRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	cfos, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByName(cfos, addTaxRecordRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, err := h.createNewTaxRecordModel(uid, cfoId, user.DefaultCurrency, &addTaxRecordRequest)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordInfo := MCPQueryTaxRecordsToolHandler.createNewMCPTaxRecordInfo(record, cfoNames)
+	periodQuarter := ""
+
+	if recordInfo.PeriodQuarter > 0 {
+		periodQuarter = strconv.Itoa(int(recordInfo.PeriodQuarter))
+	}
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_CREATE,
+		entityType: mcpEntityTypeTaxRecord,
+		changes: getMCPFieldChanges(nil, []*mcpEntityField{
+			{name: "tax_type", value: recordInfo.TaxType},
+			{name: "status", value: recordInfo.Status},
+			{name: "period_year", value: strconv.Itoa(int(recordInfo.PeriodYear))},
+			{name: "period_quarter", value: periodQuarter},
+			{name: "cfo_name", value: recordInfo.CfoName},
+			{name: "taxable_income", value: recordInfo.TaxableIncome},
+			{name: "tax_amount", value: recordInfo.TaxAmount},
+			{name: "paid_amount", value: recordInfo.PaidAmount},
+			{name: "currency", value: recordInfo.Currency},
+			{name: "due_date", value: recordInfo.DueDate},
+			{name: "comment", value: recordInfo.Comment},
+		}),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, addTaxRecordRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetTaxRecordService().CreateTaxRecordInSession(c, sess, record)
+
+		if err != nil {
+			log.Errorf(c, "[add_tax_record.Handle] failed to create tax record for user \"uid:%d\", because %s", uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[add_tax_record.Handle] user \"uid:%d\" has created a new tax record \"id:%d\" successfully", uid, record.TaxId)
+
+		return record.TaxId, nil
+	})
+}
+
+func (h *mcpAddTaxRecordToolHandler) createNewTaxRecordModel(uid int64, cfoId int64, defaultCurrency string, addTaxRecordRequest *MCPAddTaxRecordRequest) (*models.TaxRecord, error) {
+	var taxType models.TaxType
+
+	switch addTaxRecordRequest.TaxType {
+	case taxTypeIncome:
+		taxType = models.TAX_TYPE_INCOME
+	case taxTypeVat:
+		taxType = models.TAX_TYPE_VAT
+	case taxTypeProperty:
+		taxType = models.TAX_TYPE_PROPERTY
+	case taxTypeOther:
+		taxType = models.TAX_TYPE_OTHER
+	default:
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if addTaxRecordRequest.PeriodYear <= 0 || addTaxRecordRequest.PeriodQuarter < 0 || addTaxRecordRequest.PeriodQuarter > 4 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	taxAmount, err := utils.ParseAmount(addTaxRecordRequest.TaxAmount)
+
+	if err != nil || taxAmount < 0 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	taxableIncome, err := h.parseOptionalAmount(addTaxRecordRequest.TaxableIncome)
+
+	if err != nil {
+		return nil, err
+	}
+
+	paidAmount, err := h.parseOptionalAmount(addTaxRecordRequest.PaidAmount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(addTaxRecordRequest.Currency))
+
+	if currency == "" {
+		currency = defaultCurrency
+	} else if len(currency) != 3 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	dueDate, err := parseMCPOptionalTime(addTaxRecordRequest.DueDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.TAX_STATUS_PENDING
+
+	if paidAmount >= taxAmount {
+		status = models.TAX_STATUS_PAID
+	}
+
+	return &models.TaxRecord{
+		Uid:           uid,
+		CfoId:         cfoId,
+		TaxType:       taxType,
+		PeriodYear:    addTaxRecordRequest.PeriodYear,
+		PeriodQuarter: addTaxRecordRequest.PeriodQuarter,
+		TaxableIncome: taxableIncome,
+		TaxAmount:     taxAmount,
+		PaidAmount:    paidAmount,
+		DueDate:       dueDate,
+		Status:        status,
+		Comment:       addTaxRecordRequest.Comment,
+		Currency:      currency,
+	}, nil
+}
+
+func (h *mcpAddTaxRecordToolHandler) parseOptionalAmount(amount string) (int64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+
+	value, err := utils.ParseAmount(amount)
+
+	if err != nil || value < 0 {
+		return 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	return value, nil
+}
@@ -5,7 +5,10 @@ import (
 	"reflect"
 	"time"
 
+	"xorm.io/xorm"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
@@ -33,8 +36,7 @@ type MCPAddTransactionRequest struct {
 
 // MCPAddTransactionResponse represents the response structure for add transaction
 type MCPAddTransactionResponse struct {
-	Success                   bool   `json:"success" jsonschema_description:"Indicates whether this operation is successful"`
-	DryRun                    bool   `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this operation is a dry run (transaction not saved actually)"`
+	MCPMutationResponse
 	AccountBalance            string `json:"account_balance,omitempty" jsonschema_description:"Account balance (or outstanding balance for debt accounts) after the transaction"`
 	DestinationAccountBalance string `json:"destination_account_balance,omitempty" jsonschema_description:"Destination account balance (or outstanding balance for debt accounts) after the transaction (only for transfer transactions)"`
 }
@@ -86,14 +88,13 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	uid := user.Uid
-	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	references, err := getMCPTransactionReferences(c, services, uid)
 
 	if err != nil {
-		log.Warnf(c, "[add_transaction.Handle] get account error, because %s", err.Error())
 		return nil, nil, err
 	}
 
-	accountsMap := services.GetAccountService().GetVisibleAccountNameMapByList(allAccounts)
+	accountsMap := services.GetAccountService().GetVisibleAccountNameMapByList(references.allAccounts)
 	sourceAccount, exists := accountsMap[addTransactionRequest.AccountName]
 
 	if !exists {
@@ -115,13 +116,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		destinationAccountId = destinationAccount.AccountId
 	}
 
-	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0)
-
-	if err != nil {
-		log.Warnf(c, "[add_transaction.Handle] get transaction category error, because %s", err.Error())
-		return nil, nil, err
-	}
-
+	allCategories := references.allCategories
 	var transactionCategory *models.TransactionCategory = nil
 
 	for i := 0; i < len(allCategories); i++ {
@@ -153,14 +148,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	var tagIds []int64
 
 	if len(addTransactionRequest.Tags) > 0 {
-		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
-
-		if err != nil {
-			log.Warnf(c, "[add_transaction.Handle] get transaction tag ids error, because %s", err.Error())
-			return nil, nil, err
-		}
-
-		tagMaps := services.GetTransactionTagService().GetVisibleTagNameMapByList(allTags)
+		tagMaps := services.GetTransactionTagService().GetVisibleTagNameMapByList(references.allTags)
 		tagIds = make([]int64, 0, len(addTransactionRequest.Tags))
 
 		for _, tagName := range addTransactionRequest.Tags {
@@ -184,36 +172,41 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		return nil, nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
 
-	if !addTransactionRequest.DryRun {
-		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil)
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_CREATE,
+		entityType: mcpEntityTypeTransaction,
+		changes:    getMCPFieldChanges(nil, references.getTransactionFields(transaction, tagIds)),
+	}
+
+	return applyMCPMutationWithResponse(c, user, services, mutation, addTransactionRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetTransactionService().CreateTransactionWithTagsInSession(c, database, sess, transaction, tagIds, nil)
 
 		if err != nil {
 			log.Errorf(c, "[add_transaction.Handle] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
-			return nil, nil, err
+			return 0, err
 		}
 
 		log.Infof(c, "[add_transaction.Handle] user \"uid:%d\" has created a new transaction \"id:%d\" successfully", uid, transaction.TransactionId)
 
-		accountIds := []int64{sourceAccount.AccountId}
+		return transaction.TransactionId, nil
+	}, func(mutationResponse *MCPMutationResponse) (any, error) {
+		if !addTransactionRequest.DryRun {
+			accountIds := []int64{sourceAccount.AccountId}
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			accountIds = append(accountIds, destinationAccountId)
-		}
-
-		newAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, accountIds)
+			if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				accountIds = append(accountIds, destinationAccountId)
+			}
 
-		if err != nil {
-			log.Warnf(c, "[add_transaction.Handle] failed to get latest accounts info after transaction created, because %s", err.Error())
-		}
+			newAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, accountIds)
 
-		structuredResponse, response, err := h.createNewMCPAddTransactionResponse(c, transaction, newAccounts, false)
+			if err != nil {
+				log.Warnf(c, "[add_transaction.Handle] failed to get latest accounts info after transaction created, because %s", err.Error())
+			}
 
-		if err != nil {
-			return nil, nil, err
+			return h.createNewMCPAddTransactionResponse(transaction, newAccounts, mutationResponse), nil
 		}
 
-		return structuredResponse, response, nil
-	} else {
 		newAccounts := make(map[int64]*models.Account)
 		newAccounts[sourceAccount.AccountId] = sourceAccount
 
@@ -228,14 +221,8 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 			destinationAccount.Balance += transaction.RelatedAccountAmount
 		}
 
-		structuredResponse, response, err := h.createNewMCPAddTransactionResponse(c, transaction, newAccounts, true)
-
-		if err != nil {
-			return nil, nil, err
-		}
-
-		return structuredResponse, response, nil
-	}
+		return h.createNewMCPAddTransactionResponse(transaction, newAccounts, mutationResponse), nil
+	})
 }
 
 func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addTransactionRequest *MCPAddTransactionRequest, categoryId int64, sourceAccountId int64, destinationAccountId int64, clientIp string) (*models.Transaction, error) {
@@ -289,7 +276,7 @@ func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addT
 	return transaction, nil
 }
 
-func (h *mcpAddTransactionToolHandler) createNewMCPAddTransactionResponse(c *core.WebContext, transaction *models.Transaction, accountsMap map[int64]*models.Account, dryRun bool) (any, []*MCPTextContent, error) {
+func (h *mcpAddTransactionToolHandler) createNewMCPAddTransactionResponse(transaction *models.Transaction, accountsMap map[int64]*models.Account, mutationResponse *MCPMutationResponse) *MCPAddTransactionResponse {
 	var sourceAccountInfo *models.AccountInfoResponse
 	var destinationAccountInfo *models.AccountInfoResponse
 
@@ -303,9 +290,8 @@ func (h *mcpAddTransactionToolHandler) createNewMCPAddTransactionResponse(c *cor
 		}
 	}
 
-	response := MCPAddTransactionResponse{
-		Success: true,
-		DryRun:  dryRun,
+	response := &MCPAddTransactionResponse{
+		MCPMutationResponse: *mutationResponse,
 	}
 
 	if sourceAccountInfo != nil {
@@ -324,13 +310,5 @@ func (h *mcpAddTransactionToolHandler) createNewMCPAddTransactionResponse(c *cor
 		}
 	}
 
-	content, err := json.Marshal(response)
-
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return response, []*MCPTextContent{
-		NewMCPTextContent(string(content)),
-	}, nil
+	return response
 }
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpBudgetVarianceCommentaryPromptHandler struct{}
+
+var MCPBudgetVarianceCommentaryPromptHandler = &mcpBudgetVarianceCommentaryPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpBudgetVarianceCommentaryPromptHandler) Name() string {
+	return "budget_variance_commentary"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpBudgetVarianceCommentaryPromptHandler) Title() string {
+	return "Budget variance commentary"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpBudgetVarianceCommentaryPromptHandler) Description() string {
+	return "Write a commentary on the deviations between the budget and the actual income and expenses of a month."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpBudgetVarianceCommentaryPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Description: "Month to comment in YYYY-MM format (optional, default is the last complete month)",
+		},
+		{
+			Name:        "version",
+			Description: "Budget version (optional, default is the base version)",
+		},
+		{
+			Name:        "cfo_name",
+			Description: "Only compare the budget of this CFO (center of financial responsibility) (optional)",
+		},
+	}
+}
+
+// Get returns the messages of the MCP prompt filled with the arguments
+func (h *mcpBudgetVarianceCommentaryPromptHandler) Get(c *core.WebContext, arguments map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPGetPromptResponse, error) {
+	uid := user.Uid
+	location, err := getMCPRequestTimezone(c, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthStartTime, err := getMCPMonthStartTime(arguments["month"], location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, arguments["cfo_name"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	planFactRangeReq := &models.PlanFactRangeRequest{
+		PeriodType: models.PLAN_FACT_PERIOD_MONTH,
+		Year:       int32(monthStartTime.Year()),
+		Month:      int32(monthStartTime.Month()),
+		CfoId:      cfoId,
+		Version:    arguments["version"],
+	}
+
+	planFact, err := services.GetBudgetService().GetPlanFactByMonths(c, uid, planFactRangeReq, user.FiscalYearStart, location)
+
+	if err != nil {
+		log.Errorf(c, "[budget_variance_commentary.Get] failed to get plan-fact analysis for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	content, err := json.Marshal(newMCPQueryBudgetPlanFactResponse(planFact))
+
+	if err != nil {
+		return nil, err
+	}
+
+	month := monthStartTime.Format("2006-01")
+	text := fmt.Sprintf("Please write a budget variance commentary for %s based on the plan-fact analysis below.\n"+
+		"Each line contains the planned amount, the actual amount, the deviation and the deviation in percent of a category.\n"+
+		"1. Start with a short summary of total income, total expenses and the net result against the budget.\n"+
+		"2. Explain the most significant favorable and unfavorable deviations, keeping in mind that spending above plan is unfavorable for expenses but earning above plan is favorable for income.\n"+
+		"3. Point out categories with actual amounts but no budget, and budgeted categories without any actual amounts.\n"+
+		"4. Suggest adjustments for the budget of the following months.\n"+
+		"Use only the figures below.\n\n"+
+		"Plan-fact analysis (JSON):\n%s", month, string(content))
+
+	return &MCPGetPromptResponse{
+		Description: fmt.Sprintf("Budget variance commentary of %s", month),
+		Messages: []*MCPPromptMessage{
+			NewMCPUserPromptMessage(NewMCPTextContent(text)),
+		},
+	}, nil
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPCFOsResource represents the contents of the CFO list resource
+type MCPCFOsResource struct {
+	Cfos []*MCPCFOResourceInfo `json:"cfos"`
+}
+
+// MCPCFOResourceInfo defines the structure of CFO information in the CFO list resource
+type MCPCFOResourceInfo struct {
+	Name    string `json:"name"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type mcpCFOsResourceHandler struct{}
+
+var MCPCFOsResourceHandler = &mcpCFOsResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpCFOsResourceHandler) URI() string {
+	return "ezbookkeeping://cfos"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpCFOsResourceHandler) Name() string {
+	return "Financial responsibility centers"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpCFOsResourceHandler) Description() string {
+	return "All visible financial responsibility centers (CFOs) which budgets, reports and transactions can be filtered by."
+}
+
+// MimeType returns the MIME type of the MCP resource contents
+func (h *mcpCFOsResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpCFOsResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	uid := user.Uid
+	cfos, err := services.GetCFOService().GetAllCFOsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[cfos_resource.Read] failed to get cfos for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	resource := MCPCFOsResource{
+		Cfos: make([]*MCPCFOResourceInfo, 0, len(cfos)),
+	}
+
+	for i := 0; i < len(cfos); i++ {
+		if cfos[i].Hidden {
+			continue
+		}
+
+		resource.Cfos = append(resource.Cfos, &MCPCFOResourceInfo{
+			Name:    cfos[i].Name,
+			Comment: cfos[i].Comment,
+		})
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(h.URI(), string(content), h.MimeType()), nil
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPConfirmPlannedTransactionRequest represents all parameters of the confirm planned transaction request
+type MCPConfirmPlannedTransactionRequest struct {
+	Id     string `json:"id" jsonschema_description:"Planned transaction id returned by query_transactions"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be confirmed, only the changes to be applied are returned (optional)"`
+}
+
+type mcpConfirmPlannedTransactionToolHandler struct{}
+
+var MCPConfirmPlannedTransactionToolHandler = &mcpConfirmPlannedTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpConfirmPlannedTransactionToolHandler) Name() string {
+	return "confirm_planned_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpConfirmPlannedTransactionToolHandler) Description() string {
+	return "Confirm a planned transaction in ezBookkeeping, the transaction time will be changed to now and the account balances will be updated."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpConfirmPlannedTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPConfirmPlannedTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpConfirmPlannedTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpConfirmPlannedTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var confirmPlannedTransactionRequest MCPConfirmPlannedTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &confirmPlannedTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionId, err := parseMCPEntityId(confirmPlannedTransactionRequest.Id)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uid := user.Uid
+	transaction, err := services.GetTransactionService().GetTransactionByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.Errorf(c, "[confirm_planned_transaction.Handle] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	if !transaction.Planned {
+		return nil, nil, errs.ErrNothingWillBeUpdated
+	}
+
+	transactionTimezone := getMCPTransactionTimezone(transaction)
+	oldTransactionTime := utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transactionTimezone)
+	newTransactionTime := utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(time.Now().Unix(), transactionTimezone)
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_CONFIRM,
+		entityType: mcpEntityTypeTransaction,
+		entityId:   transactionId,
+		changes: getMCPFieldChanges([]*mcpEntityField{
+			{name: "planned", value: strconv.FormatBool(true)},
+			{name: "time", value: oldTransactionTime},
+		}, []*mcpEntityField{
+			{name: "planned", value: strconv.FormatBool(false)},
+			{name: "time", value: newTransactionTime},
+		}),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, confirmPlannedTransactionRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		clientTimezone, err := getMCPRequestTimezone(c, "")
+
+		if err != nil {
+			return 0, err
+		}
+
+		confirmedTransaction, err := services.GetTransactionService().ConfirmPlannedTransactionInSession(c, sess, uid, transactionId, clientTimezone)
+
+		if err != nil {
+			log.Errorf(c, "[confirm_planned_transaction.Handle] failed to confirm planned transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[confirm_planned_transaction.Handle] user \"uid:%d\" has confirmed planned transaction \"id:%d\" successfully", uid, transactionId)
+
+		return confirmedTransaction.TransactionId, nil
+	})
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPCounterpartiesResource represents the contents of the counterparty list resource
+type MCPCounterpartiesResource struct {
+	Counterparties []*MCPCounterpartyResourceInfo `json:"counterparties"`
+}
+
+// MCPCounterpartyResourceInfo defines the structure of counterparty information in the counterparty list resource
+type MCPCounterpartyResourceInfo struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type mcpCounterpartiesResourceHandler struct{}
+
+var MCPCounterpartiesResourceHandler = &mcpCounterpartiesResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpCounterpartiesResourceHandler) URI() string {
+	return "ezbookkeeping://counterparties"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpCounterpartiesResourceHandler) Name() string {
+	return "Counterparties"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpCounterpartiesResourceHandler) Description() string {
+	return "All visible counterparties (persons and companies) which receivables and payables are tracked for."
+}
+
+// MimeType returns the MIME type of the MCP resource contents
+func (h *mcpCounterpartiesResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpCounterpartiesResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	uid := user.Uid
+	counterparties, err := services.GetCounterpartyService().GetAllCounterpartiesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[counterparties_resource.Read] failed to get counterparties for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	resource := MCPCounterpartiesResource{
+		Counterparties: make([]*MCPCounterpartyResourceInfo, 0, len(counterparties)),
+	}
+
+	for i := 0; i < len(counterparties); i++ {
+		counterparty := counterparties[i]
+
+		if counterparty.Hidden {
+			continue
+		}
+
+		counterpartyInfo := &MCPCounterpartyResourceInfo{
+			Name:    counterparty.Name,
+			Comment: counterparty.Comment,
+		}
+
+		if counterparty.Type == models.COUNTERPARTY_TYPE_COMPANY {
+			counterpartyInfo.Type = "company"
+		} else {
+			counterpartyInfo.Type = "person"
+		}
+
+		resource.Counterparties = append(resource.Counterparties, counterpartyInfo)
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(h.URI(), string(content), h.MimeType()), nil
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPDeleteTransactionRequest represents all parameters of the delete transaction request
+type MCPDeleteTransactionRequest struct {
+	Id     string `json:"id" jsonschema_description:"Transaction id returned by query_transactions"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be deleted, only the changes to be applied are returned (optional)"`
+}
+
+type mcpDeleteTransactionToolHandler struct{}
+
+var MCPDeleteTransactionToolHandler = &mcpDeleteTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpDeleteTransactionToolHandler) Name() string {
+	return "delete_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpDeleteTransactionToolHandler) Description() string {
+	return "Delete an existing transaction in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpDeleteTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPDeleteTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpDeleteTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpDeleteTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var deleteTransactionRequest MCPDeleteTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &deleteTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionId, err := parseMCPEntityId(deleteTransactionRequest.Id)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uid := user.Uid
+	transaction, err := services.GetTransactionService().GetTransactionByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.Errorf(c, "[delete_transaction.Handle] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return nil, nil, errs.ErrTransactionTypeInvalid
+	}
+
+	if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, getMCPTransactionTimezone(transaction)) {
+		return nil, nil, errs.ErrCannotDeleteTransactionWithThisTransactionTime
+	}
+
+	references, err := getMCPTransactionReferences(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagIds, err := getMCPTransactionTagIds(c, services, uid, transactionId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_DELETE,
+		entityType: mcpEntityTypeTransaction,
+		entityId:   transactionId,
+		changes:    getMCPFieldChanges(references.getTransactionFields(transaction, tagIds), nil),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, deleteTransactionRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetTransactionService().DeleteTransactionInSession(c, sess, uid, transactionId)
+
+		if err != nil {
+			log.Errorf(c, "[delete_transaction.Handle] failed to delete transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[delete_transaction.Handle] user \"uid:%d\" has deleted transaction \"id:%d\"", uid, transactionId)
+
+		return transactionId, nil
+	})
+}
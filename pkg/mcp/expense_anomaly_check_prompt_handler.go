@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const expenseAnomalyComparisonMonths = 3
+const expenseAnomalyDefaultThresholdPct = 30
+
+// MCPExpenseAnomalyContext represents the expenses by category of a month compared with the average of the previous months
+type MCPExpenseAnomalyContext struct {
+	Month            string                           `json:"month"`
+	Currency         string                           `json:"currency"`
+	ComparisonMonths int                              `json:"comparison_months"`
+	ThresholdPct     int                              `json:"threshold_pct"`
+	Categories       []*MCPExpenseAnomalyCategoryInfo `json:"categories"`
+	Warnings         []string                         `json:"warnings,omitempty"`
+}
+
+// MCPExpenseAnomalyCategoryInfo defines the structure of expenses of a category in the expense anomaly context
+type MCPExpenseAnomalyCategoryInfo struct {
+	CategoryName  string `json:"category_name"`
+	Amount        string `json:"amount"`
+	AverageAmount string `json:"average_amount"`
+	ChangePct     *int32 `json:"change_pct,omitempty"`
+}
+
+type mcpExpenseAnomalyCheckPromptHandler struct{}
+
+var MCPExpenseAnomalyCheckPromptHandler = &mcpExpenseAnomalyCheckPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpExpenseAnomalyCheckPromptHandler) Name() string {
+	return "expense_anomaly_check"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpExpenseAnomalyCheckPromptHandler) Title() string {
+	return "Expense anomaly check"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpExpenseAnomalyCheckPromptHandler) Description() string {
+	return "Compare the expenses of each category in a month with the average of the previous three months and explain unusual changes."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpExpenseAnomalyCheckPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Description: "Month to check in YYYY-MM format (optional, default is the last complete month)",
+		},
+		{
+			Name:        "threshold_pct",
+			Description: "Change in percent from the average regarded as an anomaly (optional, default is 30)",
+		},
+		{
+			Name:        "currency",
+			Description: "Reporting currency code (e.g. USD, EUR) (optional, default is the default currency of user)",
+		},
+	}
+}
+
+// Get returns the messages of the MCP prompt filled with the arguments
+func (h *mcpExpenseAnomalyCheckPromptHandler) Get(c *core.WebContext, arguments map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPGetPromptResponse, error) {
+	thresholdPct := expenseAnomalyDefaultThresholdPct
+
+	if arguments["threshold_pct"] != "" {
+		value, err := strconv.Atoi(arguments["threshold_pct"])
+
+		if err != nil || value <= 0 {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		thresholdPct = value
+	}
+
+	location, err := getMCPRequestTimezone(c, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthStartTime, err := getMCPMonthStartTime(arguments["month"], location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	converter, err := getMCPReportCurrencyConverter(c, user, arguments["currency"], currentConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	uid := user.Uid
+	categoryNames := make(map[int64]string)
+	currentAmounts := make(map[int64]int64)
+	previousAmounts := make(map[int64]int64)
+	warnings := make([]string, 0)
+
+	for i := 0; i <= expenseAnomalyComparisonMonths; i++ {
+		startTime := monthStartTime.AddDate(0, -i, 0)
+		endTime := startTime.AddDate(0, 1, 0)
+		cashFlow, err := services.GetReportService().GetCashFlow(c, uid, 0, startTime.UnixMilli(), endTime.UnixMilli(), converter)
+
+		if err != nil {
+			log.Errorf(c, "[expense_anomaly_check.Get] failed to get cash flow for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, err
+		}
+
+		for _, activity := range cashFlow.Activities {
+			for _, line := range activity.Lines {
+				if line.Expense == 0 {
+					continue
+				}
+
+				categoryNames[line.CategoryId] = line.CategoryName
+
+				if i == 0 {
+					currentAmounts[line.CategoryId] += line.Expense
+				} else {
+					previousAmounts[line.CategoryId] += line.Expense
+				}
+			}
+		}
+
+		warnings = append(warnings, cashFlow.Warnings...)
+	}
+
+	anomalyContext := &MCPExpenseAnomalyContext{
+		Month:            monthStartTime.Format("2006-01"),
+		Currency:         converter.ReportingCurrency(),
+		ComparisonMonths: expenseAnomalyComparisonMonths,
+		ThresholdPct:     thresholdPct,
+		Categories:       h.getExpenseAnomalyCategories(categoryNames, currentAmounts, previousAmounts),
+	}
+
+	if len(warnings) > 0 {
+		anomalyContext.Warnings = warnings
+	}
+
+	content, err := json.Marshal(anomalyContext)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Please check my expenses of %s for anomalies based on the data below.\n"+
+		"For every expense category the data contains the amount of the month, the average monthly amount of the previous %d months and the change in percent.\n"+
+		"1. List the categories whose amount changed by more than %d%% from the average, and new categories without previous expenses, ordered by the absolute change.\n"+
+		"2. For each of them suggest plausible reasons (one-off purchases, seasonality, price changes, duplicated or miscategorized transactions) and what I should verify.\n"+
+		"3. Mention categories with unusually steady or missing expenses if recurring payments may have been skipped.\n"+
+		"Use only the figures below and state the currency of every amount.\n\n"+
+		"Expenses by category (JSON):\n%s", anomalyContext.Month, expenseAnomalyComparisonMonths, thresholdPct, string(content))
+
+	return &MCPGetPromptResponse{
+		Description: fmt.Sprintf("Expense anomaly check of %s", anomalyContext.Month),
+		Messages: []*MCPPromptMessage{
+			NewMCPUserPromptMessage(NewMCPTextContent(text)),
+		},
+	}, nil
+}
+
+func (h *mcpExpenseAnomalyCheckPromptHandler) getExpenseAnomalyCategories(categoryNames map[int64]string, currentAmounts map[int64]int64, previousAmounts map[int64]int64) []*MCPExpenseAnomalyCategoryInfo {
+	categoryIds := make([]int64, 0, len(categoryNames))
+
+	for categoryId := range categoryNames {
+		categoryIds = append(categoryIds, categoryId)
+	}
+
+	sort.Slice(categoryIds, func(i, j int) bool {
+		if currentAmounts[categoryIds[i]] != currentAmounts[categoryIds[j]] {
+			return currentAmounts[categoryIds[i]] > currentAmounts[categoryIds[j]]
+		}
+
+		return categoryIds[i] < categoryIds[j]
+	})
+
+	categories := make([]*MCPExpenseAnomalyCategoryInfo, 0, len(categoryIds))
+
+	for _, categoryId := range categoryIds {
+		amount := currentAmounts[categoryId]
+		averageAmount := int64(math.Round(float64(previousAmounts[categoryId]) / float64(expenseAnomalyComparisonMonths)))
+		categoryInfo := &MCPExpenseAnomalyCategoryInfo{
+			CategoryName:  categoryNames[categoryId],
+			Amount:        utils.FormatAmount(amount),
+			AverageAmount: utils.FormatAmount(averageAmount),
+		}
+
+		if averageAmount != 0 {
+			changePct := int32(math.Round(float64(amount-averageAmount) * 100 / math.Abs(float64(averageAmount))))
+			categoryInfo.ChangePct = &changePct
+		}
+
+		categories = append(categories, categoryInfo)
+	}
+
+	return categories
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// getMCPCfoNameMap returns all CFOs of the user and the map of CFO names by CFO id
+func getMCPCfoNameMap(c *core.WebContext, availableServices MCPAvailableServices, uid int64) ([]*models.CFO, map[int64]string, error) {
+	cfos, err := availableServices.GetCFOService().GetAllCFOsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[financial_tool_helpers.getMCPCfoNameMap] get cfo error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	cfoNames := make(map[int64]string, len(cfos))
+
+	for i := 0; i < len(cfos); i++ {
+		cfoNames[cfos[i].CfoId] = cfos[i].Name
+	}
+
+	return cfos, cfoNames, nil
+}
+
+// getMCPCfoIdByName returns the id of the CFO with the specified name (case-insensitive), or 0 if the name is empty
+func getMCPCfoIdByName(cfos []*models.CFO, cfoName string) (int64, error) {
+	if cfoName == "" {
+		return 0, nil
+	}
+
+	for i := 0; i < len(cfos); i++ {
+		if strings.EqualFold(cfos[i].Name, cfoName) {
+			return cfos[i].CfoId, nil
+		}
+	}
+
+	return 0, errs.ErrCFONotFound
+}
+
+// getMCPCfoIdByNameFromServices returns the id of the CFO with the specified name (case-insensitive), or 0 if the name is empty
+func getMCPCfoIdByNameFromServices(c *core.WebContext, availableServices MCPAvailableServices, uid int64, cfoName string) (int64, error) {
+	if cfoName == "" {
+		return 0, nil
+	}
+
+	cfos, _, err := getMCPCfoNameMap(c, availableServices, uid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return getMCPCfoIdByName(cfos, cfoName)
+}
+
+// getMCPCounterpartyNameMap returns all counterparties of the user and the map of counterparty names by counterparty id
+func getMCPCounterpartyNameMap(c *core.WebContext, availableServices MCPAvailableServices, uid int64) ([]*models.Counterparty, map[int64]string, error) {
+	counterparties, err := availableServices.GetCounterpartyService().GetAllCounterpartiesByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[financial_tool_helpers.getMCPCounterpartyNameMap] get counterparty error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	counterpartyNames := make(map[int64]string, len(counterparties))
+
+	for i := 0; i < len(counterparties); i++ {
+		counterpartyNames[counterparties[i].CounterpartyId] = counterparties[i].Name
+	}
+
+	return counterparties, counterpartyNames, nil
+}
+
+// getMCPCounterpartyIdByName returns the id of the counterparty with the specified name (case-insensitive), or 0 if the name is empty
+func getMCPCounterpartyIdByName(counterparties []*models.Counterparty, counterpartyName string) (int64, error) {
+	if counterpartyName == "" {
+		return 0, nil
+	}
+
+	for i := 0; i < len(counterparties); i++ {
+		if strings.EqualFold(counterparties[i].Name, counterpartyName) {
+			return counterparties[i].CounterpartyId, nil
+		}
+	}
+
+	return 0, errs.ErrCounterpartyNotFound
+}
+
+// getMCPReportCurrencyConverter returns the converter from all currencies into the reporting currency based on the latest exchange rates,
+// the default currency of user is used if the reporting currency is not specified.
+// The transactions in reports are converted by the exchange rates in effect at their own transaction dates when they exist in the exchange rates history
+func getMCPReportCurrencyConverter(c *core.WebContext, user *models.User, reportingCurrency string, currentConfig *settings.Config) (*services.ReportCurrencyConverter, error) {
+	reportingCurrency = strings.ToUpper(strings.TrimSpace(reportingCurrency))
+
+	if reportingCurrency == "" {
+		reportingCurrency = user.DefaultCurrency
+	} else if len(reportingCurrency) != 3 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	exchangeRates, err := exchangerates.Container.GetLatestExchangeRates(c, user.Uid, currentConfig)
+
+	if err != nil {
+		log.Warnf(c, "[financial_tool_helpers.getMCPReportCurrencyConverter] failed to get latest exchange rates for user \"uid:%d\", because %s", user.Uid, err.Error())
+		exchangeRates = nil
+	}
+
+	converter := services.NewReportCurrencyConverter(reportingCurrency, user.DefaultCurrency, exchangeRates)
+	converter.SetHistoricalExchangeRatesLoader(func(rateDate string) *models.LatestExchangeRateResponse {
+		if rateDate >= models.GetExchangeRateHistoryDate(time.Now().Unix()) {
+			return nil
+		}
+
+		histories, err := services.ExchangeRateHistories.GetAllExchangeRatesByDate(c, currentConfig.ExchangeRatesDataSource, rateDate)
+
+		if err != nil {
+			return nil
+		}
+
+		return histories.ToLatestExchangeRateResponse()
+	})
+
+	return converter, nil
+}
+
+// parseMCPTimeRange parses the start and end time in RFC 3339 format and returns them in unix time (seconds)
+func parseMCPTimeRange(startTime string, endTime string) (int64, int64, error) {
+	minTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(startTime)
+
+	if err != nil {
+		return 0, 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	maxTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(endTime)
+
+	if err != nil {
+		return 0, 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if maxTime.Unix() <= minTime.Unix() {
+		return 0, 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	return minTime.Unix(), maxTime.Unix(), nil
+}
+
+// parseMCPOptionalTime parses the time in RFC 3339 format and returns it in unix time (milliseconds), or 0 if the time is empty
+func parseMCPOptionalTime(t string) (int64, error) {
+	if t == "" {
+		return 0, nil
+	}
+
+	parsedTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(t)
+
+	if err != nil {
+		return 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	return parsedTime.UnixMilli(), nil
+}
+
+// getMCPRequestTimezone returns the timezone of the specified name, the client timezone, or UTC if neither is available
+func getMCPRequestTimezone(c *core.WebContext, timezoneName string) (*time.Location, error) {
+	if timezoneName != "" {
+		location, err := time.LoadLocation(timezoneName)
+
+		if err != nil {
+			return nil, errs.ErrClientTimezoneOffsetInvalid
+		}
+
+		return location, nil
+	}
+
+	location, err := c.GetClientTimezone()
+
+	if err != nil || location == nil {
+		return time.UTC, nil
+	}
+
+	return location, nil
+}
+
+// formatMCPDate returns the date time in RFC 3339 format in UTC, or empty string if the time is not set.
+// Times stored in seconds are also accepted
+func formatMCPDate(unixTime int64) string {
+	if unixTime <= 0 {
+		return ""
+	}
+
+	return utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(utils.ToMillisIfSeconds(unixTime)/1000, time.UTC)
+}
+
+// formatMCPPercent returns the percent value stored in hundredths of a percent as a decimal string
+func formatMCPPercent(value int32) string {
+	return utils.FormatAmount(int64(value))
+}
+
+// matchMCPKeyword returns whether the value contains the keyword (case-insensitive), an empty keyword matches all values
+func matchMCPKeyword(value string, keyword string) bool {
+	if keyword == "" {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(value), strings.ToLower(keyword))
+}
+
+// getMCPMonthStartTime returns the start time of the month in YYYY-MM format in the location, or of the last complete month if the month is empty
+func getMCPMonthStartTime(month string, location *time.Location) (time.Time, error) {
+	if month == "" {
+		now := time.Now().In(location)
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location).AddDate(0, -1, 0), nil
+	}
+
+	monthStartTime, err := time.ParseInLocation("2006-01", month, location)
+
+	if err != nil {
+		return time.Time{}, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	return monthStartTime, nil
+}
@@ -14,8 +14,20 @@ type MCPAvailableServices interface {
 	GetTransactionService() *services.TransactionService
 	GetTransactionCategoryService() *services.TransactionCategoryService
 	GetTransactionTagService() *services.TransactionTagService
+	GetTransactionSearchIndexService() *services.TransactionSearchIndexService
 	GetAccountService() *services.AccountService
 	GetUserService() *services.UserService
+	GetReportService() *services.ReportService
+	GetBudgetService() *services.BudgetService
+	GetObligationService() *services.ObligationService
+	GetTaxRecordService() *services.TaxRecordService
+	GetInvestorDealService() *services.InvestorDealService
+	GetAssetService() *services.AssetService
+	GetCFOService() *services.CFOService
+	GetCounterpartyService() *services.CounterpartyService
+	GetInvestorPaymentService() *services.InvestorPaymentService
+	GetTokenService() *services.TokenService
+	GetMCPAuditLogService() *services.MCPAuditLogService
 }
 
 // MCPToolHandler defines the MCP tool handler
@@ -35,3 +47,39 @@ type MCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPRe
 	// Handle processes the MCP call tool request and returns the response
 	Handle(*core.WebContext, *MCPCallToolRequest, *models.User, *settings.Config, MCPAvailableServices) (any, []*T, error)
 }
+
+// MCPResourceHandler defines the MCP resource handler
+type MCPResourceHandler interface {
+	// URI returns the uri of the MCP resource
+	URI() string
+
+	// Name returns the name of the MCP resource
+	Name() string
+
+	// Description returns the description of the MCP resource
+	Description() string
+
+	// MimeType returns the MIME type of the MCP resource contents
+	MimeType() string
+
+	// Read returns the contents of the MCP resource
+	Read(*core.WebContext, *models.User, *settings.Config, MCPAvailableServices) (*MCPTextResourceContents, error)
+}
+
+// MCPPromptHandler defines the MCP prompt handler
+type MCPPromptHandler interface {
+	// Name returns the name of the MCP prompt
+	Name() string
+
+	// Title returns the title of the MCP prompt
+	Title() string
+
+	// Description returns the description of the MCP prompt
+	Description() string
+
+	// Arguments returns the arguments of the MCP prompt
+	Arguments() []*MCPPromptArgument
+
+	// Get returns the messages of the MCP prompt filled with the arguments
+	Get(*core.WebContext, map[string]string, *models.User, *settings.Config, MCPAvailableServices) (*MCPGetPromptResponse, error)
+}
@@ -18,6 +18,8 @@ type MCPContainer struct {
 	mcpResourceLinkTools     *orderedmap.OrderedMap[string, MCPToolHandler[MCPResourceLink]]
 	mcpEmbeddedResourceTools *orderedmap.OrderedMap[string, MCPToolHandler[MCPEmbeddedResource]]
 	mcpTools                 []*MCPTool
+	mcpResources             *orderedmap.OrderedMap[string, MCPResourceHandler]
+	mcpPrompts               *orderedmap.OrderedMap[string, MCPPromptHandler]
 }
 
 // Initialize a mcp handler container singleton instance
@@ -34,6 +36,112 @@ func (c *MCPContainer) GetMCPTools() []*MCPTool {
 	return c.mcpTools
 }
 
+// GetMCPResources returns the registered MCP resources available to the user
+func (c *MCPContainer) GetMCPResources(user *models.User) ([]*MCPResource, error) {
+	if err := checkMCPAccess(user); err != nil {
+		return nil, err
+	}
+
+	if c.mcpResources == nil {
+		return nil, nil
+	}
+
+	resources := make([]*MCPResource, 0, c.mcpResources.Len())
+
+	for pair := c.mcpResources.Oldest(); pair != nil; pair = pair.Next() {
+		resources = append(resources, &MCPResource{
+			URI:         pair.Value.URI(),
+			Name:        pair.Value.Name(),
+			MimeType:    pair.Value.MimeType(),
+			Description: pair.Value.Description(),
+		})
+	}
+
+	return resources, nil
+}
+
+// ReadResource returns the contents of the MCP resource based on the resource uri
+func (c *MCPContainer) ReadResource(ctx *core.WebContext, readResourceReq *MCPReadResourceRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	if err := checkMCPAccess(user); err != nil {
+		return nil, err
+	}
+
+	if c.mcpResources == nil {
+		return nil, errs.ErrApiNotFound
+	}
+
+	handler, exists := c.mcpResources.Get(readResourceReq.URI)
+
+	if !exists {
+		return nil, errs.ErrApiNotFound
+	}
+
+	contents, err := handler.Read(ctx, user, currentConfig, services)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return MCPReadResourceResponse[MCPTextResourceContents]{
+		Contents: []*MCPTextResourceContents{contents},
+	}, nil
+}
+
+// GetMCPPrompts returns the registered MCP prompts available to the user
+func (c *MCPContainer) GetMCPPrompts(user *models.User) ([]*MCPPrompt, error) {
+	if err := checkMCPAccess(user); err != nil {
+		return nil, err
+	}
+
+	if c.mcpPrompts == nil {
+		return nil, nil
+	}
+
+	prompts := make([]*MCPPrompt, 0, c.mcpPrompts.Len())
+
+	for pair := c.mcpPrompts.Oldest(); pair != nil; pair = pair.Next() {
+		prompts = append(prompts, &MCPPrompt{
+			Name:        pair.Value.Name(),
+			Title:       pair.Value.Title(),
+			Description: pair.Value.Description(),
+			Arguments:   pair.Value.Arguments(),
+		})
+	}
+
+	return prompts, nil
+}
+
+// GetPrompt returns the messages of the MCP prompt based on the prompt name
+func (c *MCPContainer) GetPrompt(ctx *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	if err := checkMCPAccess(user); err != nil {
+		return nil, err
+	}
+
+	if c.mcpPrompts == nil {
+		return nil, errs.ErrApiNotFound
+	}
+
+	handler, exists := c.mcpPrompts.Get(getPromptReq.Name)
+
+	if !exists {
+		return nil, errs.ErrApiNotFound
+	}
+
+	for _, argument := range handler.Arguments() {
+		if argument.Required && getPromptReq.Arguments[argument.Name] == "" {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	}
+
+	result, err := handler.Get(ctx, getPromptReq.Arguments, user, currentConfig, services)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
+
 // HandleTool returns the result of the MCP tool handler based on the tool name
 func (c *MCPContainer) HandleTool(ctx *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
 	if handler, exists := c.mcpTextContentTools.Get(callToolReq.Name); exists {
@@ -68,20 +176,77 @@ func InitializeMCPHandlers(config *settings.Config) error {
 		mcpResourceLinkTools:     orderedmap.New[string, MCPToolHandler[MCPResourceLink]](),
 		mcpEmbeddedResourceTools: orderedmap.New[string, MCPToolHandler[MCPEmbeddedResource]](),
 		mcpTools:                 make([]*MCPTool, 0),
+		mcpResources:             orderedmap.New[string, MCPResourceHandler](),
+		mcpPrompts:               orderedmap.New[string, MCPPromptHandler](),
 	}
 
 	registerMCPTextContentToolHandler(container, MCPAddTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPModifyTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPDeleteTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPConfirmPlannedTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddObligationToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddTaxRecordToolHandler)
+	registerMCPTextContentToolHandler(container, MCPRecordInvestorPaymentToolHandler)
+	registerMCPTextContentToolHandler(container, MCPSetBudgetToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryTransactionsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsBalanceToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionCategoriesToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionTagsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryLatestExchangeRatesToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryCashFlowToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryProfitAndLossToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryBalanceSheetToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryPaymentCalendarToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryBudgetPlanFactToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryObligationsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryTaxRecordsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryInvestorDealsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryAssetsToolHandler)
+
+	registerMCPResourceHandler(container, MCPAccountsResourceHandler)
+	registerMCPResourceHandler(container, MCPTransactionCategoriesResourceHandler)
+	registerMCPResourceHandler(container, MCPCFOsResourceHandler)
+	registerMCPResourceHandler(container, MCPCounterpartiesResourceHandler)
+	registerMCPResourceHandler(container, MCPLatestMonthlyStatementsResourceHandler)
+
+	registerMCPPromptHandler(container, MCPMonthEndReviewPromptHandler)
+	registerMCPPromptHandler(container, MCPExpenseAnomalyCheckPromptHandler)
+	registerMCPPromptHandler(container, MCPBudgetVarianceCommentaryPromptHandler)
 
 	Container = container
 	return nil
 }
 
+// checkMCPAccess returns an error if the user is not permitted to access mcp server
+func checkMCPAccess(user *models.User) error {
+	if user == nil {
+		return errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return errs.ErrNotPermittedToPerformThisAction
+	}
+
+	return nil
+}
+
+func registerMCPResourceHandler(c *MCPContainer, handler MCPResourceHandler) {
+	if _, exists := c.mcpResources.Get(handler.URI()); exists {
+		return
+	}
+
+	c.mcpResources.Set(handler.URI(), handler)
+}
+
+func registerMCPPromptHandler(c *MCPContainer, handler MCPPromptHandler) {
+	if _, exists := c.mcpPrompts.Get(handler.Name()); exists {
+		return
+	}
+
+	c.mcpPrompts.Set(handler.Name(), handler)
+}
+
 func registerMCPTextContentToolHandler(c *MCPContainer, handler MCPToolHandler[MCPTextContent]) {
 	registerMCPToolHandler(c, c.mcpTextContentTools, handler)
 }
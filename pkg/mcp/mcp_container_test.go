@@ -0,0 +1,312 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type testMCPResourceHandler struct {
+	uri string
+}
+
+func (h *testMCPResourceHandler) URI() string {
+	return h.uri
+}
+
+func (h *testMCPResourceHandler) Name() string {
+	return "Test resource " + h.uri
+}
+
+func (h *testMCPResourceHandler) Description() string {
+	return "Test resource"
+}
+
+func (h *testMCPResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+func (h *testMCPResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	return NewMCPTextResourceContents(h.uri, `{"username":"`+user.Username+`"}`, h.MimeType()), nil
+}
+
+type testMCPPromptHandler struct {
+	name      string
+	arguments []*MCPPromptArgument
+}
+
+func (h *testMCPPromptHandler) Name() string {
+	return h.name
+}
+
+func (h *testMCPPromptHandler) Title() string {
+	return "Test prompt " + h.name
+}
+
+func (h *testMCPPromptHandler) Description() string {
+	return "Test prompt"
+}
+
+func (h *testMCPPromptHandler) Arguments() []*MCPPromptArgument {
+	return h.arguments
+}
+
+func (h *testMCPPromptHandler) Get(c *core.WebContext, arguments map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPGetPromptResponse, error) {
+	return &MCPGetPromptResponse{
+		Description: h.Description(),
+		Messages: []*MCPPromptMessage{
+			NewMCPUserPromptMessage(NewMCPTextContent("Review " + arguments["month"])),
+		},
+	}, nil
+}
+
+func newTestMCPContainer() *MCPContainer {
+	container := &MCPContainer{
+		mcpResources: orderedmap.New[string, MCPResourceHandler](),
+		mcpPrompts:   orderedmap.New[string, MCPPromptHandler](),
+	}
+
+	registerMCPResourceHandler(container, &testMCPResourceHandler{uri: "ezbookkeeping://test/first"})
+	registerMCPResourceHandler(container, &testMCPResourceHandler{uri: "ezbookkeeping://test/second"})
+	registerMCPResourceHandler(container, &testMCPResourceHandler{uri: "ezbookkeeping://test/first"})
+	registerMCPPromptHandler(container, &testMCPPromptHandler{
+		name: "test_review",
+		arguments: []*MCPPromptArgument{
+			{Name: "month", Required: true},
+			{Name: "cfo_name"},
+		},
+	})
+
+	return container
+}
+
+var (
+	testMCPPermittedUser  = &models.User{Uid: 1, Username: "permitted"}
+	testMCPRestrictedUser = &models.User{Uid: 2, Username: "restricted", FeatureRestriction: core.UserFeatureRestrictions(0).Add(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS)}
+)
+
+func TestMCPContainerGetMCPResources(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         *models.User
+		expectedURIs []string
+		expectedErr  error
+	}{
+		{
+			name:         "list all resources",
+			user:         testMCPPermittedUser,
+			expectedURIs: []string{"ezbookkeeping://test/first", "ezbookkeeping://test/second"},
+		},
+		{
+			name:        "user restricted from mcp access",
+			user:        testMCPRestrictedUser,
+			expectedErr: errs.ErrNotPermittedToPerformThisAction,
+		},
+	}
+
+	container := newTestMCPContainer()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resources, err := container.GetMCPResources(test.user)
+			assert.Equal(t, test.expectedErr, err)
+
+			var uris []string
+
+			for _, resource := range resources {
+				uris = append(uris, resource.URI)
+				assert.Equal(t, "application/json", resource.MimeType)
+			}
+
+			assert.Equal(t, test.expectedURIs, uris)
+		})
+	}
+}
+
+func TestMCPContainerReadResource(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         *models.User
+		uri          string
+		expectedText string
+		expectedErr  error
+	}{
+		{
+			name:         "read resource",
+			user:         testMCPPermittedUser,
+			uri:          "ezbookkeeping://test/second",
+			expectedText: `{"username":"permitted"}`,
+		},
+		{
+			name:        "unknown uri",
+			user:        testMCPPermittedUser,
+			uri:         "ezbookkeeping://test/unknown",
+			expectedErr: errs.ErrApiNotFound,
+		},
+		{
+			name:        "user restricted from mcp access",
+			user:        testMCPRestrictedUser,
+			uri:         "ezbookkeeping://test/second",
+			expectedErr: errs.ErrNotPermittedToPerformThisAction,
+		},
+	}
+
+	container := newTestMCPContainer()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := container.ReadResource(nil, &MCPReadResourceRequest{URI: test.uri}, test.user, nil, nil)
+			assert.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr != nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			response := result.(MCPReadResourceResponse[MCPTextResourceContents])
+			assert.Equal(t, 1, len(response.Contents))
+			assert.Equal(t, test.uri, response.Contents[0].URI)
+			assert.Equal(t, test.expectedText, response.Contents[0].Text)
+		})
+	}
+}
+
+func TestMCPContainerGetMCPPrompts(t *testing.T) {
+	tests := []struct {
+		name          string
+		user          *models.User
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name:          "list all prompts",
+			user:          testMCPPermittedUser,
+			expectedNames: []string{"test_review"},
+		},
+		{
+			name:        "user restricted from mcp access",
+			user:        testMCPRestrictedUser,
+			expectedErr: errs.ErrNotPermittedToPerformThisAction,
+		},
+	}
+
+	container := newTestMCPContainer()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prompts, err := container.GetMCPPrompts(test.user)
+			assert.Equal(t, test.expectedErr, err)
+
+			var names []string
+
+			for _, prompt := range prompts {
+				names = append(names, prompt.Name)
+				assert.Equal(t, 2, len(prompt.Arguments))
+			}
+
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
+
+func TestMCPContainerGetPrompt(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         *models.User
+		promptName   string
+		arguments    map[string]string
+		expectedText string
+		expectedErr  error
+	}{
+		{
+			name:         "get prompt",
+			user:         testMCPPermittedUser,
+			promptName:   "test_review",
+			arguments:    map[string]string{"month": "2025-03"},
+			expectedText: "Review 2025-03",
+		},
+		{
+			name:        "unknown prompt name",
+			user:        testMCPPermittedUser,
+			promptName:  "test_unknown",
+			arguments:   map[string]string{"month": "2025-03"},
+			expectedErr: errs.ErrApiNotFound,
+		},
+		{
+			name:        "missing required argument",
+			user:        testMCPPermittedUser,
+			promptName:  "test_review",
+			arguments:   map[string]string{"cfo_name": "Sales"},
+			expectedErr: errs.ErrIncompleteOrIncorrectSubmission,
+		},
+		{
+			name:        "user restricted from mcp access",
+			user:        testMCPRestrictedUser,
+			promptName:  "test_review",
+			arguments:   map[string]string{"month": "2025-03"},
+			expectedErr: errs.ErrNotPermittedToPerformThisAction,
+		},
+	}
+
+	container := newTestMCPContainer()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := container.GetPrompt(nil, &MCPGetPromptRequest{Name: test.promptName, Arguments: test.arguments}, test.user, nil, nil)
+			assert.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr != nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			response := result.(*MCPGetPromptResponse)
+			assert.Equal(t, 1, len(response.Messages))
+			assert.Equal(t, "user", response.Messages[0].Role)
+			assert.Equal(t, test.expectedText, response.Messages[0].Content.(*MCPTextContent).Text)
+		})
+	}
+}
+
+func TestInitializeMCPHandlersRegistersResourcesAndPrompts(t *testing.T) {
+	originalContainer := Container
+	defer func() {
+		Container = originalContainer
+	}()
+
+	assert.Nil(t, InitializeMCPHandlers(nil))
+
+	resources, err := Container.GetMCPResources(testMCPPermittedUser)
+	assert.Nil(t, err)
+
+	var uris []string
+
+	for _, resource := range resources {
+		uris = append(uris, resource.URI)
+	}
+
+	assert.Equal(t, []string{
+		MCPAccountsResourceHandler.URI(),
+		MCPTransactionCategoriesResourceHandler.URI(),
+		MCPCFOsResourceHandler.URI(),
+		MCPCounterpartiesResourceHandler.URI(),
+		MCPLatestMonthlyStatementsResourceHandler.URI(),
+	}, uris)
+
+	prompts, err := Container.GetMCPPrompts(testMCPPermittedUser)
+	assert.Nil(t, err)
+
+	var names []string
+
+	for _, prompt := range prompts {
+		names = append(names, prompt.Name)
+	}
+
+	assert.Equal(t, []string{"month_end_review", "expense_anomaly_check", "budget_variance_commentary"}, names)
+}
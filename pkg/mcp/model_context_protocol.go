@@ -116,6 +116,45 @@ type MCPBlobResourceContents struct {
 	MimeType string `json:"mimeType,omitempty"`
 }
 
+// MCPListPromptsResponse defines the response structure for listing prompts in the MCP
+type MCPListPromptsResponse struct {
+	Prompts    []*MCPPrompt `json:"prompts"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// MCPPrompt defines the structure of a prompt template in the MCP
+type MCPPrompt struct {
+	Name        string               `json:"name"`
+	Title       string               `json:"title,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Arguments   []*MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument defines the structure of an argument of a prompt template in the MCP
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPGetPromptRequest defines the request structure for getting a prompt in the MCP
+type MCPGetPromptRequest struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// MCPGetPromptResponse defines the response structure for getting a prompt in the MCP
+type MCPGetPromptResponse struct {
+	Description string              `json:"description,omitempty"`
+	Messages    []*MCPPromptMessage `json:"messages"`
+}
+
+// MCPPromptMessage defines the structure of a message in the prompt in the MCP
+type MCPPromptMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
 // MCPListToolsResponse defines the response structure for listing tools in the MCP
 type MCPListToolsResponse struct {
 	Tools      []*MCPTool `json:"tools"`
@@ -223,3 +262,20 @@ func NewMCPEmbeddedResource[T MCPTextResourceContents | MCPBlobResourceContents]
 		Resource: resource,
 	}
 }
+
+// NewMCPTextResourceContents creates a new instance of MCPTextResourceContents with the given uri, text and MIME type
+func NewMCPTextResourceContents(uri string, text string, mimeType string) *MCPTextResourceContents {
+	return &MCPTextResourceContents{
+		URI:      uri,
+		Text:     text,
+		MimeType: mimeType,
+	}
+}
+
+// NewMCPUserPromptMessage creates a new instance of MCPPromptMessage sent by user with the given content
+func NewMCPUserPromptMessage(content any) *MCPPromptMessage {
+	return &MCPPromptMessage{
+		Role:    "user",
+		Content: content,
+	}
+}
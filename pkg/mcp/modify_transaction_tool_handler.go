@@ -0,0 +1,289 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPModifyTransactionRequest represents all parameters of the modify transaction request
+type MCPModifyTransactionRequest struct {
+	Id                     string    `json:"id" jsonschema_description:"Transaction id returned by query_transactions"`
+	Time                   string    `json:"time,omitempty" jsonschema:"format=date-time" jsonschema_description:"New transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z) (optional, unchanged if empty)"`
+	CategoryName           string    `json:"category_name,omitempty" jsonschema_description:"New category name for the transaction (optional, unchanged if empty)"`
+	AccountName            string    `json:"account_name,omitempty" jsonschema_description:"New account name for the transaction (optional, unchanged if empty)"`
+	Amount                 string    `json:"amount,omitempty" jsonschema_description:"New transaction amount (optional, unchanged if empty)"`
+	DestinationAccountName string    `json:"destination_account_name,omitempty" jsonschema_description:"New destination account name for transfer transactions (optional, unchanged if empty)"`
+	DestinationAmount      string    `json:"destination_amount,omitempty" jsonschema_description:"New destination amount for transfer transactions (optional, unchanged if empty)"`
+	Tags                   *[]string `json:"tags,omitempty" jsonschema_description:"New list of tags of the transaction (optional, unchanged if not set, an empty list removes all tags, maximum 10 tags allowed)"`
+	Comment                *string   `json:"comment,omitempty" jsonschema_description:"New transaction description (optional, unchanged if not set)"`
+	DryRun                 bool      `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be modified, only the changes to be applied are returned (optional)"`
+}
+
+type mcpModifyTransactionToolHandler struct{}
+
+var MCPModifyTransactionToolHandler = &mcpModifyTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Name() string {
+	return "modify_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Description() string {
+	return "Modify an existing income, expense or transfer transaction in ezBookkeeping, only the specified fields are changed."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpModifyTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPModifyTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpModifyTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpModifyTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var modifyTransactionRequest MCPModifyTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &modifyTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionId, err := parseMCPEntityId(modifyTransactionRequest.Id)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if modifyTransactionRequest.Tags != nil && len(*modifyTransactionRequest.Tags) > models.MaximumTagsCountOfTransaction {
+		return nil, nil, errs.ErrTransactionHasTooManyTags
+	}
+
+	uid := user.Uid
+	transaction, err := services.GetTransactionService().GetTransactionByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction.Handle] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		log.Warnf(c, "[modify_transaction.Handle] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is %d", transactionId, uid, transaction.Type)
+		return nil, nil, errs.ErrTransactionTypeInvalid
+	}
+
+	references, err := getMCPTransactionReferences(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagIds, err := getMCPTransactionTagIds(c, services, uid, transactionId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newTransaction, newTagIds, err := h.createNewTransactionModel(c, &modifyTransactionRequest, transaction, tagIds, references, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, getMCPTransactionTimezone(transaction))
+	newTransactionEditable := user.CanEditTransactionByTransactionTime(newTransaction.TransactionTime, getMCPTransactionTimezone(newTransaction))
+
+	if !transactionEditable || !newTransactionEditable {
+		return nil, nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+	}
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_MODIFY,
+		entityType: mcpEntityTypeTransaction,
+		entityId:   transactionId,
+		changes:    getMCPFieldChanges(references.getTransactionFields(transaction, tagIds), references.getTransactionFields(newTransaction, newTagIds)),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, modifyTransactionRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		var addTagIds []int64
+		var removeTagIds []int64
+
+		if !utils.Int64SliceEquals(newTagIds, tagIds) {
+			removeTagIds = tagIds
+			addTagIds = newTagIds
+		}
+
+		err := services.GetTransactionService().ModifyTransactionInSession(c, sess, newTransaction, len(tagIds), addTagIds, removeTagIds, nil, nil)
+
+		if err != nil {
+			log.Errorf(c, "[modify_transaction.Handle] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[modify_transaction.Handle] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionId)
+
+		return transactionId, nil
+	})
+}
+
+func (h *mcpModifyTransactionToolHandler) createNewTransactionModel(c *core.WebContext, modifyTransactionRequest *MCPModifyTransactionRequest, transaction *models.Transaction, tagIds []int64, references *mcpTransactionReferences, services MCPAvailableServices) (*models.Transaction, []int64, error) {
+	uid := transaction.Uid
+	newTransaction := &models.Transaction{
+		TransactionId:        transaction.TransactionId,
+		Uid:                  uid,
+		Type:                 transaction.Type,
+		CategoryId:           transaction.CategoryId,
+		TransactionTime:      transaction.TransactionTime,
+		TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+		AccountId:            transaction.AccountId,
+		Amount:               transaction.Amount,
+		RelatedAccountId:     transaction.RelatedAccountId,
+		RelatedAccountAmount: transaction.RelatedAccountAmount,
+		HideAmount:           transaction.HideAmount,
+		CounterpartyId:       transaction.CounterpartyId,
+		Comment:              transaction.Comment,
+		GeoLongitude:         transaction.GeoLongitude,
+		GeoLatitude:          transaction.GeoLatitude,
+		Planned:              transaction.Planned,
+	}
+
+	if modifyTransactionRequest.Time != "" {
+		transactionTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(modifyTransactionRequest.Time)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		newTransaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix())
+		newTransaction.TimezoneUtcOffset = utils.GetTimezoneOffsetMinutes(transactionTime.Unix(), transactionTime.Location())
+	}
+
+	if modifyTransactionRequest.CategoryName != "" {
+		category := h.getCategoryByName(references.allCategories, modifyTransactionRequest.CategoryName, transaction.Type)
+
+		if category == nil {
+			log.Warnf(c, "[modify_transaction.Handle] category \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.CategoryName, uid)
+			return nil, nil, errs.ErrTransactionCategoryNotFound
+		}
+
+		newTransaction.CategoryId = category.CategoryId
+	}
+
+	accountsMap := services.GetAccountService().GetVisibleAccountNameMapByList(references.allAccounts)
+
+	if modifyTransactionRequest.AccountName != "" {
+		sourceAccount, exists := accountsMap[modifyTransactionRequest.AccountName]
+
+		if !exists {
+			log.Warnf(c, "[modify_transaction.Handle] source account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.AccountName, uid)
+			return nil, nil, errs.ErrSourceAccountNotFound
+		}
+
+		newTransaction.AccountId = sourceAccount.AccountId
+	}
+
+	if modifyTransactionRequest.Amount != "" {
+		amount, err := utils.ParseAmount(modifyTransactionRequest.Amount)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		newTransaction.Amount = amount
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		if modifyTransactionRequest.DestinationAccountName != "" {
+			destinationAccount, exists := accountsMap[modifyTransactionRequest.DestinationAccountName]
+
+			if !exists {
+				log.Warnf(c, "[modify_transaction.Handle] destination account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.DestinationAccountName, uid)
+				return nil, nil, errs.ErrDestinationAccountNotFound
+			}
+
+			newTransaction.RelatedAccountId = destinationAccount.AccountId
+		}
+
+		if modifyTransactionRequest.DestinationAmount != "" {
+			destinationAmount, err := utils.ParseAmount(modifyTransactionRequest.DestinationAmount)
+
+			if err != nil {
+				return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+			}
+
+			newTransaction.RelatedAccountAmount = destinationAmount
+		}
+
+		sourceAccount := references.accountsMap[newTransaction.AccountId]
+		destinationAccount := references.accountsMap[newTransaction.RelatedAccountId]
+
+		if sourceAccount != nil && destinationAccount != nil && sourceAccount.Currency == destinationAccount.Currency && newTransaction.Amount != newTransaction.RelatedAccountAmount {
+			return nil, nil, errs.ErrTransactionSourceAndDestinationAmountNotEqual
+		}
+	} else if modifyTransactionRequest.DestinationAccountName != "" || modifyTransactionRequest.DestinationAmount != "" {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if modifyTransactionRequest.Comment != nil {
+		newTransaction.Comment = *modifyTransactionRequest.Comment
+	}
+
+	newTagIds := tagIds
+
+	if modifyTransactionRequest.Tags != nil {
+		tagsMap := services.GetTransactionTagService().GetVisibleTagNameMapByList(references.allTags)
+		newTagIds = make([]int64, 0, len(*modifyTransactionRequest.Tags))
+
+		for _, tagName := range *modifyTransactionRequest.Tags {
+			tag, exists := tagsMap[tagName]
+
+			if !exists {
+				log.Warnf(c, "[modify_transaction.Handle] transaction tag \"%s\" not found for user \"uid:%d\"", tagName, uid)
+				return nil, nil, errs.ErrTransactionTagNotFound
+			}
+
+			newTagIds = append(newTagIds, tag.TagId)
+		}
+
+		newTagIds = utils.ToUniqueInt64Slice(newTagIds)
+	}
+
+	return newTransaction, newTagIds, nil
+}
+
+func (h *mcpModifyTransactionToolHandler) getCategoryByName(allCategories []*models.TransactionCategory, categoryName string, transactionType models.TransactionDbType) *models.TransactionCategory {
+	categoryType := models.CATEGORY_TYPE_EXPENSE
+
+	if transactionType == models.TRANSACTION_DB_TYPE_INCOME {
+		categoryType = models.CATEGORY_TYPE_INCOME
+	} else if transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		categoryType = models.CATEGORY_TYPE_TRANSFER
+	}
+
+	for i := 0; i < len(allCategories); i++ {
+		category := allCategories[i]
+
+		if !category.Hidden && category.Type == categoryType && category.Name == categoryName {
+			return category
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpMonthEndReviewPromptHandler struct{}
+
+var MCPMonthEndReviewPromptHandler = &mcpMonthEndReviewPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpMonthEndReviewPromptHandler) Name() string {
+	return "month_end_review"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpMonthEndReviewPromptHandler) Title() string {
+	return "Month-end review"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpMonthEndReviewPromptHandler) Description() string {
+	return "Review the profit and loss, cash flow and balance sheet of a month and summarize the key findings."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpMonthEndReviewPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Description: "Month to review in YYYY-MM format (optional, default is the last complete month)",
+		},
+		{
+			Name:        "currency",
+			Description: "Reporting currency code (e.g. USD, EUR) (optional, default is the default currency of user)",
+		},
+	}
+}
+
+// Get returns the messages of the MCP prompt filled with the arguments
+func (h *mcpMonthEndReviewPromptHandler) Get(c *core.WebContext, arguments map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPGetPromptResponse, error) {
+	location, err := getMCPRequestTimezone(c, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthStartTime, err := getMCPMonthStartTime(arguments["month"], location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := getMCPMonthlyStatements(c, user, currentConfig, services, monthStartTime, arguments["currency"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(statements)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Please prepare a month-end review of my finances for %s based on the financial statements below.\n"+
+		"1. Summarize revenue, expenses, gross profit and net profit, and explain what drove the result.\n"+
+		"2. Summarize the net cash flow of operating, investing and financing activities and point out where cash profit and accounting profit differ.\n"+
+		"3. Comment on the balance sheet: liquidity, receivables, payables, tax liabilities and debt.\n"+
+		"4. List any warnings of the statements (e.g. missing exchange rates) and the questions I should check before closing the month.\n"+
+		"Use only the figures below and state the currency of every amount.\n\n"+
+		"Financial statements (JSON):\n%s", statements.Month, string(content))
+
+	return &MCPGetPromptResponse{
+		Description: fmt.Sprintf("Month-end review of %s", statements.Month),
+		Messages: []*MCPPromptMessage{
+			NewMCPUserPromptMessage(NewMCPTextContent(text)),
+		},
+	}, nil
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPMonthlyStatements represents the financial statements of a month
+type MCPMonthlyStatements struct {
+	Month         string                         `json:"month"`
+	StartTime     string                         `json:"start_time"`
+	EndTime       string                         `json:"end_time"`
+	ProfitAndLoss *MCPQueryProfitAndLossResponse `json:"profit_and_loss"`
+	CashFlow      *MCPQueryCashFlowResponse      `json:"cash_flow"`
+	BalanceSheet  *MCPQueryBalanceSheetResponse  `json:"balance_sheet"`
+}
+
+type mcpLatestMonthlyStatementsResourceHandler struct{}
+
+var MCPLatestMonthlyStatementsResourceHandler = &mcpLatestMonthlyStatementsResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpLatestMonthlyStatementsResourceHandler) URI() string {
+	return "ezbookkeeping://statements/monthly/latest"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpLatestMonthlyStatementsResourceHandler) Name() string {
+	return "Latest monthly statements"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpLatestMonthlyStatementsResourceHandler) Description() string {
+	return "Profit and loss, cash flow and balance sheet of the last complete month in the default currency of user."
+}
+
+// MimeType returns the MIME type of the MCP resource contents
+func (h *mcpLatestMonthlyStatementsResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpLatestMonthlyStatementsResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	location, err := getMCPRequestTimezone(c, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthStartTime, err := getMCPMonthStartTime("", location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := getMCPMonthlyStatements(c, user, currentConfig, services, monthStartTime, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(statements)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(h.URI(), string(content), h.MimeType()), nil
+}
+
+// getMCPMonthlyStatements returns the profit and loss, cash flow and balance sheet at the end of the month which starts at the specified time
+func getMCPMonthlyStatements(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices, monthStartTime time.Time, currency string) (*MCPMonthlyStatements, error) {
+	uid := user.Uid
+	monthEndTime := monthStartTime.AddDate(0, 1, 0)
+	converter, err := getMCPReportCurrencyConverter(c, user, currency, currentConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pnl, err := services.GetReportService().GetPnL(c, uid, 0, monthStartTime.UnixMilli(), monthEndTime.UnixMilli(), converter)
+
+	if err != nil {
+		log.Errorf(c, "[monthly_statements.getMCPMonthlyStatements] failed to get profit and loss for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	cashFlow, err := services.GetReportService().GetCashFlow(c, uid, 0, monthStartTime.UnixMilli(), monthEndTime.UnixMilli(), converter)
+
+	if err != nil {
+		log.Errorf(c, "[monthly_statements.getMCPMonthlyStatements] failed to get cash flow for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	balance, err := services.GetReportService().GetBalance(c, uid, 0, monthEndTime.UnixMilli()-1, converter)
+
+	if err != nil {
+		log.Errorf(c, "[monthly_statements.getMCPMonthlyStatements] failed to get balance sheet for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	reportingCurrency := converter.ReportingCurrency()
+
+	return &MCPMonthlyStatements{
+		Month:         monthStartTime.Format("2006-01"),
+		StartTime:     monthStartTime.Format(time.RFC3339),
+		EndTime:       monthEndTime.Format(time.RFC3339),
+		ProfitAndLoss: newMCPQueryProfitAndLossResponse(pnl, reportingCurrency),
+		CashFlow:      newMCPQueryCashFlowResponse(cashFlow, reportingCurrency),
+		BalanceSheet:  newMCPQueryBalanceSheetResponse(balance, reportingCurrency),
+	}, nil
+}
@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpEntityTypeTransaction = "transaction"
+const mcpEntityTypeObligation = "obligation"
+const mcpEntityTypeTaxRecord = "tax_record"
+const mcpEntityTypeInvestorPayment = "investor_payment"
+const mcpEntityTypeBudget = "budget"
+
+// mcpStdioTokenIdPrefix is the prefix of the token id recorded for the changes made over stdio transport, which is followed by the user name
+const mcpStdioTokenIdPrefix = "stdio:"
+
+// MCPMutationResponse represents the response structure of the tools which change data
+type MCPMutationResponse struct {
+	Success    bool              `json:"success" jsonschema_description:"Indicates whether this operation is successful"`
+	DryRun     bool              `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this operation is a dry run (changes not applied actually)"`
+	Action     string            `json:"action" jsonschema:"enum=create,enum=modify,enum=delete,enum=confirm" jsonschema_description:"Action of the change (create, modify, delete, confirm)"`
+	EntityType string            `json:"entity_type" jsonschema:"enum=transaction,enum=obligation,enum=tax_record,enum=investor_payment,enum=budget" jsonschema_description:"Type of the changed data (transaction, obligation, tax_record, investor_payment, budget)"`
+	EntityId   string            `json:"entity_id,omitempty" jsonschema_description:"Id of the changed data (empty for a dry run of creation)"`
+	Changes    []*MCPFieldChange `json:"changes" jsonschema_description:"Exact list of fields changed (or to be changed for a dry run) with their old and new values"`
+}
+
+// MCPFieldChange defines the structure of a changed field
+type MCPFieldChange struct {
+	Field    string `json:"field" jsonschema_description:"Field name"`
+	OldValue string `json:"old_value,omitempty" jsonschema_description:"Value before the change (empty for creation)"`
+	NewValue string `json:"new_value,omitempty" jsonschema_description:"Value after the change (empty for deletion)"`
+}
+
+// mcpEntityField represents a field name and its formatted value of the data to be changed
+type mcpEntityField struct {
+	name  string
+	value string
+}
+
+// mcpMutation represents a change of data made by MCP tool
+type mcpMutation struct {
+	toolName   string
+	action     models.MCPAuditAction
+	entityType string
+	entityId   int64
+	changes    []*MCPFieldChange
+}
+
+// getMCPFieldChanges returns the fields whose values differ between the old and new fields, the old fields are nil for creation and the new fields are nil for deletion
+func getMCPFieldChanges(oldFields []*mcpEntityField, newFields []*mcpEntityField) []*MCPFieldChange {
+	oldValues := make(map[string]string, len(oldFields))
+	changes := make([]*MCPFieldChange, 0, len(newFields))
+
+	for i := 0; i < len(oldFields); i++ {
+		oldValues[oldFields[i].name] = oldFields[i].value
+	}
+
+	for i := 0; i < len(newFields); i++ {
+		field := newFields[i]
+		oldValue := oldValues[field.name]
+		delete(oldValues, field.name)
+
+		if oldValue != field.value {
+			changes = append(changes, &MCPFieldChange{
+				Field:    field.name,
+				OldValue: oldValue,
+				NewValue: field.value,
+			})
+		}
+	}
+
+	for i := 0; i < len(oldFields); i++ {
+		field := oldFields[i]
+
+		if oldValue, exists := oldValues[field.name]; exists && oldValue != "" {
+			changes = append(changes, &MCPFieldChange{
+				Field:    field.name,
+				OldValue: oldValue,
+			})
+		}
+	}
+
+	return changes
+}
+
+// applyMCPMutation applies the change unless it is a dry run, records the applied change with the MCP token id and returns the tool response
+func applyMCPMutation(c *core.WebContext, user *models.User, services MCPAvailableServices, mutation *mcpMutation, dryRun bool, apply func(database *datastore.Database, sess *xorm.Session) (int64, error)) (any, []*MCPTextContent, error) {
+	return applyMCPMutationWithResponse(c, user, services, mutation, dryRun, apply, func(response *MCPMutationResponse) (any, error) {
+		return *response, nil
+	})
+}
+
+// applyMCPMutationWithResponse applies the change unless it is a dry run, records the applied change with the MCP token id and returns the tool response
+// built from the mutation response by the specified function. The change and its audit log are saved in one database transaction,
+// so the change is rolled back if it cannot be recorded
+func applyMCPMutationWithResponse(c *core.WebContext, user *models.User, services MCPAvailableServices, mutation *mcpMutation, dryRun bool, apply func(database *datastore.Database, sess *xorm.Session) (int64, error), buildResponse func(response *MCPMutationResponse) (any, error)) (any, []*MCPTextContent, error) {
+	if len(mutation.changes) < 1 {
+		return nil, nil, errs.ErrNothingWillBeUpdated
+	}
+
+	if !dryRun {
+		database := services.GetMCPAuditLogService().UserDataDB(user.Uid)
+
+		err := database.DoTransaction(c, func(sess *xorm.Session) error {
+			entityId, err := apply(database, sess)
+
+			if err != nil {
+				return err
+			}
+
+			mutation.entityId = entityId
+
+			return recordMCPAuditLogInSession(c, sess, user, services, mutation)
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	mutationResponse := &MCPMutationResponse{
+		Success:    true,
+		DryRun:     dryRun,
+		Action:     mutation.action.String(),
+		EntityType: mutation.entityType,
+		Changes:    mutation.changes,
+	}
+
+	if mutation.entityId > 0 {
+		mutationResponse.EntityId = utils.Int64ToString(mutation.entityId)
+	}
+
+	response, err := buildResponse(mutationResponse)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+// recordMCPAuditLogInSession saves the applied change into the audit log in the session of the change
+func recordMCPAuditLogInSession(c *core.WebContext, sess *xorm.Session, user *models.User, services MCPAvailableServices, mutation *mcpMutation) error {
+	changes := make([]*models.MCPAuditFieldChange, len(mutation.changes))
+
+	for i := 0; i < len(mutation.changes); i++ {
+		changes[i] = &models.MCPAuditFieldChange{
+			Field:    mutation.changes[i].Field,
+			OldValue: mutation.changes[i].OldValue,
+			NewValue: mutation.changes[i].NewValue,
+		}
+	}
+
+	changesContent, err := json.Marshal(changes)
+
+	if err != nil {
+		log.Errorf(c, "[mutation_tool_helpers.recordMCPAuditLogInSession] failed to serialize changes of %s \"id:%d\" for user \"uid:%d\", because %s", mutation.entityType, mutation.entityId, user.Uid, err.Error())
+		return errs.ErrMCPAuditLogNotRecorded
+	}
+
+	auditLog := &models.MCPAuditLog{
+		Uid:        user.Uid,
+		TokenId:    getMCPTokenId(c, services),
+		ToolName:   mutation.toolName,
+		Action:     mutation.action,
+		EntityType: mutation.entityType,
+		EntityId:   mutation.entityId,
+		Changes:    string(changesContent),
+		ClientIp:   c.ClientIP(),
+	}
+
+	err = services.GetMCPAuditLogService().CreateAuditLogInSession(c, sess, auditLog)
+
+	if err != nil {
+		log.Errorf(c, "[mutation_tool_helpers.recordMCPAuditLogInSession] failed to record change of %s \"id:%d\" made by tool \"%s\" for user \"uid:%d\", the change will be rolled back, because %s", mutation.entityType, mutation.entityId, mutation.toolName, user.Uid, err.Error())
+		return errs.ErrMCPAuditLogNotRecorded
+	}
+
+	log.Infof(c, "[mutation_tool_helpers.recordMCPAuditLogInSession] change of %s \"id:%d\" made by tool \"%s\" for user \"uid:%d\" has been recorded as \"id:%d\"", mutation.entityType, mutation.entityId, mutation.toolName, user.Uid, auditLog.AuditId)
+
+	return nil
+}
+
+// getMCPTokenId returns the id of the MCP token used by current request, or "stdio:<username>" if the request is made over stdio transport which is not authorized by token
+func getMCPTokenId(c *core.WebContext, services MCPAvailableServices) string {
+	tokenClaims := c.GetTokenClaims()
+
+	if tokenClaims == nil {
+		return ""
+	}
+
+	if tokenClaims.UserTokenId == "" {
+		return mcpStdioTokenIdPrefix + tokenClaims.Username
+	}
+
+	userTokenId, err := utils.StringToInt64(tokenClaims.UserTokenId)
+
+	if err != nil {
+		log.Warnf(c, "[mutation_tool_helpers.getMCPTokenId] parse user token id failed, because %s", err.Error())
+		return ""
+	}
+
+	return services.GetTokenService().GenerateTokenId(&models.TokenRecord{
+		Uid:             tokenClaims.Uid,
+		UserTokenId:     userTokenId,
+		CreatedUnixTime: tokenClaims.IssuedAt,
+	})
+}
+
+// parseMCPEntityId parses the id string returned by the query tools
+func parseMCPEntityId(id string) (int64, error) {
+	entityId, err := utils.StringToInt64(id)
+
+	if err != nil || entityId <= 0 {
+		return 0, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	return entityId, nil
+}
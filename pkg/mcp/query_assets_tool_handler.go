@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	assetTypeEquipment   = "equipment"
+	assetTypeFurniture   = "furniture"
+	assetTypeVehicle     = "vehicle"
+	assetTypeElectronics = "electronics"
+	assetTypeRealEstate  = "real_estate"
+	assetTypeOther       = "other"
+)
+
+const (
+	assetStatusActive         = "active"
+	assetStatusDecommissioned = "decommissioned"
+	assetStatusSold           = "sold"
+)
+
+const (
+	assetDepreciationMethodStraightLine      = "straight_line"
+	assetDepreciationMethodDecliningBalance  = "declining_balance"
+	assetDepreciationMethodSumOfYearsDigits  = "sum_of_years_digits"
+	assetDepreciationMethodUnitsOfProduction = "units_of_production"
+)
+
+// MCPQueryAssetsRequest represents all parameters of the query assets request
+type MCPQueryAssetsRequest struct {
+	Name          string `json:"name,omitempty" jsonschema_description:"Keyword to search in asset name (optional)"`
+	AssetType     string `json:"asset_type,omitempty" jsonschema:"enum=equipment,enum=furniture,enum=vehicle,enum=electronics,enum=real_estate,enum=other" jsonschema_description:"Asset type to filter by (equipment, furniture, vehicle, electronics, real_estate, other) (optional)"`
+	Status        string `json:"status,omitempty" jsonschema:"enum=active,enum=decommissioned,enum=sold" jsonschema_description:"Asset status to filter by (active, decommissioned, sold) (optional)"`
+	CfoName       string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional)"`
+	IncludeHidden bool   `json:"include_hidden,omitempty" jsonschema_description:"Whether to include hidden assets (optional, default: false)"`
+}
+
+// MCPQueryAssetsResponse represents the response structure for querying assets
+type MCPQueryAssetsResponse struct {
+	Assets []*MCPAssetInfo `json:"assets" jsonschema_description:"List of assets matching the query"`
+}
+
+// MCPAssetInfo defines the structure of asset information
+type MCPAssetInfo struct {
+	Name               string `json:"name" jsonschema_description:"Asset name"`
+	AssetType          string `json:"asset_type" jsonschema:"enum=equipment,enum=furniture,enum=vehicle,enum=electronics,enum=real_estate,enum=other" jsonschema_description:"Asset type (equipment, furniture, vehicle, electronics, real_estate, other)"`
+	Status             string `json:"status" jsonschema:"enum=active,enum=decommissioned,enum=sold" jsonschema_description:"Asset status (active, decommissioned, sold)"`
+	CfoName            string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the asset"`
+	PurchaseDate       string `json:"purchase_date,omitempty" jsonschema_description:"Purchase date in RFC 3339 format"`
+	PurchaseCost       string `json:"purchase_cost" jsonschema_description:"Purchase cost of the asset"`
+	UsefulLifeMonths   int32  `json:"useful_life_months,omitempty" jsonschema_description:"Useful life of the asset in months"`
+	SalvageValue       string `json:"salvage_value" jsonschema_description:"Salvage value of the asset at the end of its useful life"`
+	DepreciationMethod string `json:"depreciation_method" jsonschema:"enum=straight_line,enum=declining_balance,enum=sum_of_years_digits,enum=units_of_production" jsonschema_description:"Depreciation method of the asset (straight_line, declining_balance, sum_of_years_digits, units_of_production)"`
+	CommissionDate     string `json:"commission_date,omitempty" jsonschema_description:"Commissioning date in RFC 3339 format"`
+	DecommissionDate   string `json:"decommission_date,omitempty" jsonschema_description:"Decommissioning date in RFC 3339 format"`
+	DisposalProceeds   string `json:"disposal_proceeds,omitempty" jsonschema_description:"Proceeds from disposal of the sold or decommissioned asset"`
+	DisposalBookValue  string `json:"disposal_book_value,omitempty" jsonschema_description:"Book value of the sold or decommissioned asset at the disposal date"`
+	Comment            string `json:"comment,omitempty" jsonschema_description:"Description of the asset"`
+}
+
+type mcpQueryAssetsToolHandler struct{}
+
+var MCPQueryAssetsToolHandler = &mcpQueryAssetsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryAssetsToolHandler) Name() string {
+	return "query_assets"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryAssetsToolHandler) Description() string {
+	return "Query fixed assets (equipment, vehicles, real estate, etc.) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryAssetsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryAssetsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryAssetsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryAssetsResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryAssetsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryAssetsRequest MCPQueryAssetsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryAssetsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	cfos, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByName(cfos, queryAssetsRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assets, err := services.GetAssetService().GetAllAssetsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_assets.Handle] failed to get assets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryAssetsResponse{
+		Assets: make([]*MCPAssetInfo, 0, len(assets)),
+	}
+
+	for i := 0; i < len(assets); i++ {
+		asset := assets[i]
+		assetInfo := h.createNewMCPAssetInfo(asset, cfoNames)
+
+		if asset.Hidden && !queryAssetsRequest.IncludeHidden ||
+			!matchMCPKeyword(asset.Name, queryAssetsRequest.Name) ||
+			queryAssetsRequest.AssetType != "" && assetInfo.AssetType != queryAssetsRequest.AssetType ||
+			queryAssetsRequest.Status != "" && assetInfo.Status != queryAssetsRequest.Status ||
+			cfoId > 0 && asset.CfoId != cfoId {
+			continue
+		}
+
+		response.Assets = append(response.Assets, assetInfo)
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQueryAssetsToolHandler) createNewMCPAssetInfo(asset *models.Asset, cfoNames map[int64]string) *MCPAssetInfo {
+	assetInfo := &MCPAssetInfo{
+		Name:             asset.Name,
+		CfoName:          cfoNames[asset.CfoId],
+		PurchaseDate:     formatMCPDate(asset.PurchaseDate),
+		PurchaseCost:     utils.FormatAmount(asset.PurchaseCost),
+		UsefulLifeMonths: asset.UsefulLifeMonths,
+		SalvageValue:     utils.FormatAmount(asset.SalvageValue),
+		CommissionDate:   formatMCPDate(asset.CommissionDate),
+		DecommissionDate: formatMCPDate(asset.DecommissionDate),
+		Comment:          asset.Comment,
+	}
+
+	switch asset.AssetType {
+	case models.ASSET_TYPE_EQUIPMENT:
+		assetInfo.AssetType = assetTypeEquipment
+	case models.ASSET_TYPE_FURNITURE:
+		assetInfo.AssetType = assetTypeFurniture
+	case models.ASSET_TYPE_VEHICLE:
+		assetInfo.AssetType = assetTypeVehicle
+	case models.ASSET_TYPE_ELECTRONICS:
+		assetInfo.AssetType = assetTypeElectronics
+	case models.ASSET_TYPE_REAL_ESTATE:
+		assetInfo.AssetType = assetTypeRealEstate
+	case models.ASSET_TYPE_OTHER:
+		assetInfo.AssetType = assetTypeOther
+	}
+
+	switch asset.DepreciationMethod {
+	case models.ASSET_DEPRECIATION_METHOD_DECLINING_BALANCE:
+		assetInfo.DepreciationMethod = assetDepreciationMethodDecliningBalance
+	case models.ASSET_DEPRECIATION_METHOD_SUM_OF_YEARS_DIGITS:
+		assetInfo.DepreciationMethod = assetDepreciationMethodSumOfYearsDigits
+	case models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION:
+		assetInfo.DepreciationMethod = assetDepreciationMethodUnitsOfProduction
+	default:
+		assetInfo.DepreciationMethod = assetDepreciationMethodStraightLine
+	}
+
+	if asset.Status != models.ASSET_STATUS_ACTIVE && (asset.DisposalProceeds != 0 || asset.DisposalBookValue != 0) {
+		assetInfo.DisposalProceeds = utils.FormatAmount(asset.DisposalProceeds)
+		assetInfo.DisposalBookValue = utils.FormatAmount(asset.DisposalBookValue)
+	}
+
+	switch asset.Status {
+	case models.ASSET_STATUS_ACTIVE:
+		assetInfo.Status = assetStatusActive
+	case models.ASSET_STATUS_DECOMMISSIONED:
+		assetInfo.Status = assetStatusDecommissioned
+	case models.ASSET_STATUS_SOLD:
+		assetInfo.Status = assetStatusSold
+	}
+
+	return assetInfo
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryBalanceSheetRequest represents all parameters of the query balance sheet request
+type MCPQueryBalanceSheetRequest struct {
+	AsOf     string `json:"as_of,omitempty" jsonschema:"format=date-time" jsonschema_description:"Date of the balance sheet in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional, default is now)"`
+	CfoName  string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional, leave empty for all CFOs)"`
+	Currency string `json:"currency,omitempty" jsonschema_description:"Reporting currency code (e.g. USD, EUR) all amounts are converted into (optional, default is the default currency of user)"`
+}
+
+// MCPQueryBalanceSheetResponse represents the response structure for querying balance sheet
+type MCPQueryBalanceSheetResponse struct {
+	AsOf           string           `json:"as_of,omitempty" jsonschema_description:"Date of the balance sheet in RFC 3339 format"`
+	Currency       string           `json:"currency" jsonschema_description:"Reporting currency code of all amounts"`
+	Assets         []*MCPReportLine `json:"assets" jsonschema_description:"Asset lines of the balance sheet"`
+	TotalAssets    string           `json:"total_assets" jsonschema_description:"Total assets"`
+	Liabilities    []*MCPReportLine `json:"liabilities" jsonschema_description:"Liability lines of the balance sheet"`
+	TotalLiability string           `json:"total_liability" jsonschema_description:"Total liabilities"`
+	Equity         string           `json:"equity" jsonschema_description:"Equity (total assets minus total liabilities)"`
+	Warnings       []string         `json:"warnings,omitempty" jsonschema_description:"Warnings of the report, e.g. missing exchange rates"`
+}
+
+type mcpQueryBalanceSheetToolHandler struct{}
+
+var MCPQueryBalanceSheetToolHandler = &mcpQueryBalanceSheetToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryBalanceSheetToolHandler) Name() string {
+	return "query_balance_sheet"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryBalanceSheetToolHandler) Description() string {
+	return "Query balance sheet (assets, liabilities and equity) as of a date in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryBalanceSheetToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryBalanceSheetRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryBalanceSheetToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryBalanceSheetResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryBalanceSheetToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryBalanceSheetRequest MCPQueryBalanceSheetRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryBalanceSheetRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	asOf, err := parseMCPOptionalTime(queryBalanceSheetRequest.AsOf)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, queryBalanceSheetRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := getMCPReportCurrencyConverter(c, user, queryBalanceSheetRequest.Currency, currentConfig)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balance, err := services.GetReportService().GetBalance(c, uid, cfoId, asOf, converter)
+
+	if err != nil {
+		log.Errorf(c, "[query_balance_sheet.Handle] failed to get balance sheet for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	return h.createNewMCPQueryBalanceSheetResponse(c, balance, converter.ReportingCurrency())
+}
+
+func (h *mcpQueryBalanceSheetToolHandler) createNewMCPQueryBalanceSheetResponse(c *core.WebContext, balance *models.BalanceResponse, currency string) (any, []*MCPTextContent, error) {
+	response := newMCPQueryBalanceSheetResponse(balance, currency)
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func newMCPQueryBalanceSheetResponse(balance *models.BalanceResponse, currency string) *MCPQueryBalanceSheetResponse {
+	response := &MCPQueryBalanceSheetResponse{
+		AsOf:           formatMCPDate(balance.AsOf),
+		Currency:       currency,
+		Assets:         make([]*MCPReportLine, 0, len(balance.AssetLines)),
+		TotalAssets:    utils.FormatAmount(balance.TotalAssets),
+		Liabilities:    make([]*MCPReportLine, 0, len(balance.LiabilityLines)),
+		TotalLiability: utils.FormatAmount(balance.TotalLiability),
+		Equity:         utils.FormatAmount(balance.Equity),
+		Warnings:       balance.Warnings,
+	}
+
+	for i := 0; i < len(balance.AssetLines); i++ {
+		response.Assets = append(response.Assets, &MCPReportLine{
+			Label:  balance.AssetLines[i].Label,
+			Amount: utils.FormatAmount(balance.AssetLines[i].Amount),
+		})
+	}
+
+	for i := 0; i < len(balance.LiabilityLines); i++ {
+		response.Liabilities = append(response.Liabilities, &MCPReportLine{
+			Label:  balance.LiabilityLines[i].Label,
+			Amount: utils.FormatAmount(balance.LiabilityLines[i].Amount),
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	planFactPeriodMonth      = "month"
+	planFactPeriodQuarter    = "quarter"
+	planFactPeriodFiscalYear = "fiscal_year"
+	planFactPeriodYearToDate = "year_to_date"
+	planFactPeriodCustom     = "custom"
+)
+
+const (
+	planFactGroupByMonth = "month"
+	planFactGroupByCfo   = "cfo"
+)
+
+// MCPQueryBudgetPlanFactRequest represents all parameters of the query budget plan-fact request
+type MCPQueryBudgetPlanFactRequest struct {
+	PeriodType string `json:"period_type" jsonschema:"enum=month,enum=quarter,enum=fiscal_year,enum=year_to_date,enum=custom" jsonschema_description:"Period type of the analysis (month, quarter, fiscal_year, year_to_date, custom)"`
+	Year       int32  `json:"year" jsonschema_description:"Year of the period, for fiscal_year and quarter it is the fiscal year named by its end year"`
+	Month      int32  `json:"month,omitempty" jsonschema_description:"Month (1-12), required for month, year_to_date (the last month) and custom (the first month) period types"`
+	Quarter    int32  `json:"quarter,omitempty" jsonschema_description:"Quarter of the fiscal year (1-4), required for quarter period type"`
+	EndYear    int32  `json:"end_year,omitempty" jsonschema_description:"Year of the last month, required for custom period type"`
+	EndMonth   int32  `json:"end_month,omitempty" jsonschema_description:"Last month (1-12), required for custom period type"`
+	GroupBy    string `json:"group_by,omitempty" jsonschema:"enum=month,enum=cfo" jsonschema_description:"Columns of the analysis table (month, cfo) (optional, default: month)"`
+	CfoName    string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional, leave empty for all CFOs)"`
+	Version    string `json:"version,omitempty" jsonschema_description:"Budget version name (optional, leave empty for the working budget)"`
+	Timezone   string `json:"timezone,omitempty" jsonschema_description:"IANA timezone name for month boundaries (e.g. Europe/Moscow) (optional, default is the client timezone or UTC)"`
+}
+
+// MCPQueryBudgetPlanFactResponse represents the response structure for querying budget plan-fact analysis
+type MCPQueryBudgetPlanFactResponse struct {
+	StartYear  int32                  `json:"start_year" jsonschema_description:"Year of the first month of the period"`
+	StartMonth int32                  `json:"start_month" jsonschema_description:"First month of the period"`
+	EndYear    int32                  `json:"end_year" jsonschema_description:"Year of the last month of the period"`
+	EndMonth   int32                  `json:"end_month" jsonschema_description:"Last month of the period"`
+	Version    string                 `json:"version,omitempty" jsonschema_description:"Budget version name"`
+	Columns    []string               `json:"columns" jsonschema_description:"Column names of the analysis table, months (YYYY-MM) or CFO names"`
+	Lines      []*MCPPlanFactLineInfo `json:"lines" jsonschema_description:"Plan-fact lines of each category"`
+}
+
+// MCPPlanFactLineInfo defines the structure of plan-fact line information
+type MCPPlanFactLineInfo struct {
+	CategoryName string                    `json:"category_name" jsonschema_description:"Transaction category name"`
+	CategoryType string                    `json:"category_type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction category type (income, expense, transfer)"`
+	Rollup       bool                      `json:"rollup,omitempty" jsonschema_description:"Whether the amounts include the amounts of sub-categories"`
+	Columns      []*MCPPlanFactAmountsInfo `json:"columns" jsonschema_description:"Amounts of each column"`
+	Total        *MCPPlanFactAmountsInfo   `json:"total" jsonschema_description:"Total amounts of the period"`
+}
+
+// MCPPlanFactAmountsInfo defines the structure of planned and fact amounts
+type MCPPlanFactAmountsInfo struct {
+	Planned      string `json:"planned" jsonschema_description:"Planned amount"`
+	Fact         string `json:"fact" jsonschema_description:"Fact amount"`
+	Deviation    string `json:"deviation" jsonschema_description:"Deviation of fact amount from planned amount"`
+	DeviationPct *int32 `json:"deviation_pct,omitempty" jsonschema_description:"Deviation in percent of planned amount, absent if nothing is planned"`
+}
+
+type mcpQueryBudgetPlanFactToolHandler struct{}
+
+var MCPQueryBudgetPlanFactToolHandler = &mcpQueryBudgetPlanFactToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryBudgetPlanFactToolHandler) Name() string {
+	return "query_budget_plan_fact"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryBudgetPlanFactToolHandler) Description() string {
+	return "Query budget plan-fact analysis (planned and actual amounts by category) of a period in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryBudgetPlanFactToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryBudgetPlanFactRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryBudgetPlanFactToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryBudgetPlanFactResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryBudgetPlanFactToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryPlanFactRequest MCPQueryBudgetPlanFactRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryPlanFactRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	periodType := h.getPlanFactPeriodType(queryPlanFactRequest.PeriodType)
+
+	if periodType == 0 ||
+		queryPlanFactRequest.Year < 2000 || queryPlanFactRequest.Year > 2100 ||
+		queryPlanFactRequest.Month < 0 || queryPlanFactRequest.Month > 12 ||
+		queryPlanFactRequest.Quarter < 0 || queryPlanFactRequest.Quarter > 4 ||
+		queryPlanFactRequest.EndMonth < 0 || queryPlanFactRequest.EndMonth > 12 ||
+		(queryPlanFactRequest.GroupBy != "" && queryPlanFactRequest.GroupBy != planFactGroupByMonth && queryPlanFactRequest.GroupBy != planFactGroupByCfo) {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	timezone, err := getMCPRequestTimezone(c, queryPlanFactRequest.Timezone)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, queryPlanFactRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	planFactRangeReq := &models.PlanFactRangeRequest{
+		PeriodType: periodType,
+		Year:       queryPlanFactRequest.Year,
+		Month:      queryPlanFactRequest.Month,
+		Quarter:    queryPlanFactRequest.Quarter,
+		EndYear:    queryPlanFactRequest.EndYear,
+		EndMonth:   queryPlanFactRequest.EndMonth,
+		CfoId:      cfoId,
+		Version:    queryPlanFactRequest.Version,
+	}
+
+	var planFact *models.PlanFactTableResponse
+
+	if queryPlanFactRequest.GroupBy == planFactGroupByCfo {
+		planFact, err = services.GetBudgetService().GetPlanFactByCfos(c, uid, planFactRangeReq, user.FiscalYearStart, timezone)
+	} else {
+		planFact, err = services.GetBudgetService().GetPlanFactByMonths(c, uid, planFactRangeReq, user.FiscalYearStart, timezone)
+	}
+
+	if err != nil {
+		log.Errorf(c, "[query_budget_plan_fact.Handle] failed to get plan-fact analysis for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	return h.createNewMCPQueryBudgetPlanFactResponse(c, planFact)
+}
+
+func (h *mcpQueryBudgetPlanFactToolHandler) getPlanFactPeriodType(periodType string) models.PlanFactPeriodType {
+	switch periodType {
+	case planFactPeriodMonth:
+		return models.PLAN_FACT_PERIOD_MONTH
+	case planFactPeriodQuarter:
+		return models.PLAN_FACT_PERIOD_QUARTER
+	case planFactPeriodFiscalYear:
+		return models.PLAN_FACT_PERIOD_FISCAL_YEAR
+	case planFactPeriodYearToDate:
+		return models.PLAN_FACT_PERIOD_YEAR_TO_DATE
+	case planFactPeriodCustom:
+		return models.PLAN_FACT_PERIOD_CUSTOM
+	default:
+		return 0
+	}
+}
+
+func (h *mcpQueryBudgetPlanFactToolHandler) createNewMCPQueryBudgetPlanFactResponse(c *core.WebContext, planFact *models.PlanFactTableResponse) (any, []*MCPTextContent, error) {
+	response := newMCPQueryBudgetPlanFactResponse(planFact)
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func newMCPQueryBudgetPlanFactResponse(planFact *models.PlanFactTableResponse) *MCPQueryBudgetPlanFactResponse {
+	response := &MCPQueryBudgetPlanFactResponse{
+		StartYear:  planFact.StartYear,
+		StartMonth: planFact.StartMonth,
+		EndYear:    planFact.EndYear,
+		EndMonth:   planFact.EndMonth,
+		Version:    planFact.Version,
+		Columns:    make([]string, 0, len(planFact.Columns)),
+		Lines:      make([]*MCPPlanFactLineInfo, 0, len(planFact.Lines)),
+	}
+
+	for i := 0; i < len(planFact.Columns); i++ {
+		column := planFact.Columns[i]
+
+		if column.Year > 0 {
+			response.Columns = append(response.Columns, fmt.Sprintf("%04d-%02d", column.Year, column.Month))
+		} else {
+			response.Columns = append(response.Columns, column.CfoName)
+		}
+	}
+
+	for i := 0; i < len(planFact.Lines); i++ {
+		line := planFact.Lines[i]
+		lineInfo := &MCPPlanFactLineInfo{
+			CategoryName: line.CategoryName,
+			Rollup:       line.Rollup,
+			Columns:      make([]*MCPPlanFactAmountsInfo, 0, len(line.Columns)),
+			Total:        newMCPPlanFactAmountsInfo(line.Total),
+		}
+
+		if models.TransactionCategoryType(line.CategoryType) == models.CATEGORY_TYPE_INCOME {
+			lineInfo.CategoryType = transactionTypeIncome
+		} else if models.TransactionCategoryType(line.CategoryType) == models.CATEGORY_TYPE_EXPENSE {
+			lineInfo.CategoryType = transactionTypeExpense
+		} else if models.TransactionCategoryType(line.CategoryType) == models.CATEGORY_TYPE_TRANSFER {
+			lineInfo.CategoryType = transactionTypeTransfer
+		}
+
+		for j := 0; j < len(line.Columns); j++ {
+			lineInfo.Columns = append(lineInfo.Columns, newMCPPlanFactAmountsInfo(line.Columns[j]))
+		}
+
+		response.Lines = append(response.Lines, lineInfo)
+	}
+
+	return response
+}
+
+func newMCPPlanFactAmountsInfo(amounts *models.PlanFactAmountsResponse) *MCPPlanFactAmountsInfo {
+	if amounts == nil {
+		return nil
+	}
+
+	return &MCPPlanFactAmountsInfo{
+		Planned:      utils.FormatAmount(amounts.PlannedAmount),
+		Fact:         utils.FormatAmount(amounts.FactAmount),
+		Deviation:    utils.FormatAmount(amounts.Deviation),
+		DeviationPct: amounts.DeviationPct,
+	}
+}
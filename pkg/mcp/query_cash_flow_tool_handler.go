@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryReportRequest represents all parameters of the query period report request
+type MCPQueryReportRequest struct {
+	StartTime string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time of the report period in RFC 3339 format (e.g. 2023-01-01T00:00:00Z)"`
+	EndTime   string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time of the report period in RFC 3339 format (e.g. 2023-02-01T00:00:00Z)"`
+	CfoName   string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional, leave empty for all CFOs)"`
+	Currency  string `json:"currency,omitempty" jsonschema_description:"Reporting currency code (e.g. USD, EUR) all amounts are converted into (optional, default is the default currency of user)"`
+}
+
+// MCPQueryCashFlowResponse represents the response structure for querying cash flow report
+type MCPQueryCashFlowResponse struct {
+	Currency   string                     `json:"currency" jsonschema_description:"Reporting currency code of all amounts"`
+	Activities []*MCPCashFlowActivityInfo `json:"activities" jsonschema_description:"List of cash flow activities (operating, investing, financing)"`
+	TotalNet   string                     `json:"total_net" jsonschema_description:"Net cash flow of all activities"`
+	Warnings   []string                   `json:"warnings,omitempty" jsonschema_description:"Warnings of the report, e.g. missing exchange rates"`
+}
+
+// MCPCashFlowActivityInfo defines the structure of cash flow activity information
+type MCPCashFlowActivityInfo struct {
+	Name         string                 `json:"name" jsonschema_description:"Name of the activity"`
+	TotalIncome  string                 `json:"total_income" jsonschema_description:"Total cash inflow of the activity"`
+	TotalExpense string                 `json:"total_expense" jsonschema_description:"Total cash outflow of the activity"`
+	TotalNet     string                 `json:"total_net" jsonschema_description:"Net cash flow of the activity"`
+	Lines        []*MCPCashFlowLineInfo `json:"lines" jsonschema_description:"Cash flow of each category in the activity"`
+}
+
+// MCPCashFlowLineInfo defines the structure of cash flow line information
+type MCPCashFlowLineInfo struct {
+	CategoryName string `json:"category_name" jsonschema_description:"Transaction category name"`
+	Income       string `json:"income" jsonschema_description:"Cash inflow of the category"`
+	Expense      string `json:"expense" jsonschema_description:"Cash outflow of the category"`
+	Net          string `json:"net" jsonschema_description:"Net cash flow of the category"`
+}
+
+type mcpQueryCashFlowToolHandler struct{}
+
+var MCPQueryCashFlowToolHandler = &mcpQueryCashFlowToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryCashFlowToolHandler) Name() string {
+	return "query_cash_flow"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryCashFlowToolHandler) Description() string {
+	return "Query cash flow statement (operating, investing and financing activities) of a period in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryCashFlowToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryReportRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryCashFlowToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryCashFlowResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryCashFlowToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryReportRequest MCPQueryReportRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryReportRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	startTime, endTime, err := parseMCPTimeRange(queryReportRequest.StartTime, queryReportRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, queryReportRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := getMCPReportCurrencyConverter(c, user, queryReportRequest.Currency, currentConfig)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cashFlow, err := services.GetReportService().GetCashFlow(c, uid, cfoId, startTime, endTime, converter)
+
+	if err != nil {
+		log.Errorf(c, "[query_cash_flow.Handle] failed to get cash flow for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	return h.createNewMCPQueryCashFlowResponse(c, cashFlow, converter.ReportingCurrency())
+}
+
+func (h *mcpQueryCashFlowToolHandler) createNewMCPQueryCashFlowResponse(c *core.WebContext, cashFlow *models.CashFlowResponse, currency string) (any, []*MCPTextContent, error) {
+	response := newMCPQueryCashFlowResponse(cashFlow, currency)
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func newMCPQueryCashFlowResponse(cashFlow *models.CashFlowResponse, currency string) *MCPQueryCashFlowResponse {
+	response := &MCPQueryCashFlowResponse{
+		Currency:   currency,
+		Activities: make([]*MCPCashFlowActivityInfo, 0, len(cashFlow.Activities)),
+		TotalNet:   utils.FormatAmount(cashFlow.TotalNet),
+		Warnings:   cashFlow.Warnings,
+	}
+
+	for i := 0; i < len(cashFlow.Activities); i++ {
+		activity := cashFlow.Activities[i]
+		activityInfo := &MCPCashFlowActivityInfo{
+			Name:         activity.ActivityName,
+			TotalIncome:  utils.FormatAmount(activity.TotalIncome),
+			TotalExpense: utils.FormatAmount(activity.TotalExpense),
+			TotalNet:     utils.FormatAmount(activity.TotalNet),
+			Lines:        make([]*MCPCashFlowLineInfo, 0, len(activity.Lines)),
+		}
+
+		for j := 0; j < len(activity.Lines); j++ {
+			line := activity.Lines[j]
+			activityInfo.Lines = append(activityInfo.Lines, &MCPCashFlowLineInfo{
+				CategoryName: line.CategoryName,
+				Income:       utils.FormatAmount(line.Income),
+				Expense:      utils.FormatAmount(line.Expense),
+				Net:          utils.FormatAmount(line.Net),
+			})
+		}
+
+		response.Activities = append(response.Activities, activityInfo)
+	}
+
+	return response
+}
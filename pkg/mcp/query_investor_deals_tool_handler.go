@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	investorDealTypeLoan         = "loan"
+	investorDealTypeEquity       = "equity"
+	investorDealTypeRevenueShare = "revenue_share"
+	investorDealTypeOther        = "other"
+)
+
+const (
+	investorRepaymentMethodAnnuity = "annuity"
+	investorRepaymentMethodLinear  = "linear"
+)
+
+// MCPQueryInvestorDealsRequest represents all parameters of the query investor deals request
+type MCPQueryInvestorDealsRequest struct {
+	InvestorName string `json:"investor_name,omitempty" jsonschema_description:"Keyword to search in investor name (optional)"`
+	DealType     string `json:"deal_type,omitempty" jsonschema:"enum=loan,enum=equity,enum=revenue_share,enum=other" jsonschema_description:"Deal type to filter by (loan, equity, revenue_share, other) (optional)"`
+	CfoName      string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional)"`
+}
+
+// MCPQueryInvestorDealsResponse represents the response structure for querying investor deals
+type MCPQueryInvestorDealsResponse struct {
+	Deals []*MCPInvestorDealInfo `json:"deals" jsonschema_description:"List of investor deals matching the query ordered by investment date (latest first)"`
+}
+
+// MCPInvestorDealInfo defines the structure of investor deal information
+type MCPInvestorDealInfo struct {
+	Id                 string `json:"id" jsonschema_description:"Deal id, which can be used to record payments to the investor"`
+	InvestorName       string `json:"investor_name" jsonschema_description:"Investor name"`
+	DealType           string `json:"deal_type" jsonschema:"enum=loan,enum=equity,enum=revenue_share,enum=other" jsonschema_description:"Deal type (loan, equity, revenue_share, other)"`
+	CfoName            string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the deal"`
+	InvestmentDate     string `json:"investment_date,omitempty" jsonschema_description:"Investment date in RFC 3339 format"`
+	InvestmentAmount   string `json:"investment_amount" jsonschema_description:"Invested amount"`
+	Currency           string `json:"currency" jsonschema_description:"Currency code of the deal (e.g. USD, EUR)"`
+	RepaymentMethod    string `json:"repayment_method,omitempty" jsonschema:"enum=annuity,enum=linear" jsonschema_description:"Repayment method of loan (annuity, linear)"`
+	AnnualRate         string `json:"annual_rate,omitempty" jsonschema_description:"Annual interest rate in percent"`
+	ProfitSharePct     int32  `json:"profit_share_pct,omitempty" jsonschema_description:"Profit or revenue share of the investor in percent"`
+	FixedPayment       string `json:"fixed_payment,omitempty" jsonschema_description:"Fixed periodic payment amount"`
+	RepaymentStartDate string `json:"repayment_start_date,omitempty" jsonschema_description:"Repayment start date in RFC 3339 format"`
+	RepaymentEndDate   string `json:"repayment_end_date,omitempty" jsonschema_description:"Repayment end date in RFC 3339 format"`
+	TotalToRepay       string `json:"total_to_repay" jsonschema_description:"Total amount to be repaid to the investor"`
+	Comment            string `json:"comment,omitempty" jsonschema_description:"Description of the deal"`
+}
+
+type mcpQueryInvestorDealsToolHandler struct{}
+
+var MCPQueryInvestorDealsToolHandler = &mcpQueryInvestorDealsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryInvestorDealsToolHandler) Name() string {
+	return "query_investor_deals"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryInvestorDealsToolHandler) Description() string {
+	return "Query investor deals (loans, equity and revenue share investments) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryInvestorDealsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryInvestorDealsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryInvestorDealsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryInvestorDealsResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryInvestorDealsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryInvestorDealsRequest MCPQueryInvestorDealsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryInvestorDealsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	cfos, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByName(cfos, queryInvestorDealsRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deals, err := services.GetInvestorDealService().GetAllDealsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_investor_deals.Handle] failed to get investor deals for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryInvestorDealsResponse{
+		Deals: make([]*MCPInvestorDealInfo, 0, len(deals)),
+	}
+
+	for i := 0; i < len(deals); i++ {
+		deal := deals[i]
+		dealInfo := h.createNewMCPInvestorDealInfo(deal, cfoNames)
+
+		if !matchMCPKeyword(deal.InvestorName, queryInvestorDealsRequest.InvestorName) ||
+			queryInvestorDealsRequest.DealType != "" && dealInfo.DealType != queryInvestorDealsRequest.DealType ||
+			cfoId > 0 && deal.CfoId != cfoId {
+			continue
+		}
+
+		response.Deals = append(response.Deals, dealInfo)
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQueryInvestorDealsToolHandler) createNewMCPInvestorDealInfo(deal *models.InvestorDeal, cfoNames map[int64]string) *MCPInvestorDealInfo {
+	dealInfo := &MCPInvestorDealInfo{
+		Id:                 utils.Int64ToString(deal.DealId),
+		InvestorName:       deal.InvestorName,
+		CfoName:            cfoNames[deal.CfoId],
+		InvestmentDate:     formatMCPDate(deal.InvestmentDate),
+		InvestmentAmount:   utils.FormatAmount(deal.InvestmentAmount),
+		Currency:           deal.Currency,
+		ProfitSharePct:     deal.ProfitSharePct,
+		RepaymentStartDate: formatMCPDate(deal.RepaymentStartDate),
+		RepaymentEndDate:   formatMCPDate(deal.RepaymentEndDate),
+		TotalToRepay:       utils.FormatAmount(deal.TotalToRepay),
+		Comment:            deal.Comment,
+	}
+
+	switch deal.DealType {
+	case models.INVESTOR_DEAL_TYPE_LOAN:
+		dealInfo.DealType = investorDealTypeLoan
+	case models.INVESTOR_DEAL_TYPE_EQUITY:
+		dealInfo.DealType = investorDealTypeEquity
+	case models.INVESTOR_DEAL_TYPE_REVENUE_SHARE:
+		dealInfo.DealType = investorDealTypeRevenueShare
+	case models.INVESTOR_DEAL_TYPE_OTHER:
+		dealInfo.DealType = investorDealTypeOther
+	}
+
+	if deal.DealType == models.INVESTOR_DEAL_TYPE_LOAN {
+		if deal.RepaymentMethod == models.INVESTOR_REPAYMENT_METHOD_ANNUITY {
+			dealInfo.RepaymentMethod = investorRepaymentMethodAnnuity
+		} else if deal.RepaymentMethod == models.INVESTOR_REPAYMENT_METHOD_LINEAR {
+			dealInfo.RepaymentMethod = investorRepaymentMethodLinear
+		}
+	}
+
+	if deal.AnnualRate > 0 {
+		dealInfo.AnnualRate = formatMCPPercent(deal.AnnualRate)
+	}
+
+	if deal.FixedPayment > 0 {
+		dealInfo.FixedPayment = utils.FormatAmount(deal.FixedPayment)
+	}
+
+	return dealInfo
+}
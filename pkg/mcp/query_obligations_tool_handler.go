@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	obligationTypeReceivable = "receivable"
+	obligationTypePayable    = "payable"
+)
+
+const (
+	obligationStatusOpen    = "open"
+	obligationStatusActive  = "active"
+	obligationStatusPartial = "partial"
+	obligationStatusPaid    = "paid"
+	obligationStatusOverdue = "overdue"
+)
+
+// MCPQueryObligationsRequest represents all parameters of the query obligations request
+type MCPQueryObligationsRequest struct {
+	Type             string `json:"type,omitempty" jsonschema:"enum=receivable,enum=payable" jsonschema_description:"Obligation type to filter by (receivable, payable) (optional)"`
+	Status           string `json:"status,omitempty" jsonschema:"enum=open,enum=active,enum=partial,enum=paid,enum=overdue" jsonschema_description:"Obligation status to filter by (open means active, partially paid or overdue) (optional)"`
+	CounterpartyName string `json:"counterparty_name,omitempty" jsonschema_description:"Counterparty name to filter obligations by (optional)"`
+	CfoName          string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional)"`
+	DueStartTime     string `json:"due_start_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"Earliest due date in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional)"`
+	DueEndTime       string `json:"due_end_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"Latest due date in RFC 3339 format (e.g. 2023-02-01T00:00:00Z) (optional)"`
+}
+
+// MCPQueryObligationsResponse represents the response structure for querying obligations
+type MCPQueryObligationsResponse struct {
+	Obligations []*MCPObligationInfo `json:"obligations" jsonschema_description:"List of obligations matching the query ordered by due date (latest first)"`
+}
+
+// MCPObligationInfo defines the structure of obligation information
+type MCPObligationInfo struct {
+	Type             string `json:"type" jsonschema:"enum=receivable,enum=payable" jsonschema_description:"Obligation type (receivable, payable)"`
+	Status           string `json:"status" jsonschema:"enum=active,enum=partial,enum=paid,enum=overdue" jsonschema_description:"Obligation status (active, partial, paid, overdue)"`
+	CounterpartyName string `json:"counterparty_name,omitempty" jsonschema_description:"Counterparty name of the obligation"`
+	CfoName          string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the obligation"`
+	Amount           string `json:"amount" jsonschema_description:"Total amount of the obligation"`
+	PaidAmount       string `json:"paid_amount" jsonschema_description:"Paid amount of the obligation"`
+	RemainingAmount  string `json:"remaining_amount" jsonschema_description:"Remaining amount to be paid"`
+	Currency         string `json:"currency" jsonschema_description:"Currency code of the obligation (e.g. USD, EUR)"`
+	DueDate          string `json:"due_date,omitempty" jsonschema_description:"Due date of the obligation in RFC 3339 format"`
+	Comment          string `json:"comment,omitempty" jsonschema_description:"Description of the obligation"`
+}
+
+type mcpQueryObligationsToolHandler struct{}
+
+var MCPQueryObligationsToolHandler = &mcpQueryObligationsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryObligationsToolHandler) Name() string {
+	return "query_obligations"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryObligationsToolHandler) Description() string {
+	return "Query receivables and payables (obligations to and from counterparties) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryObligationsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryObligationsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryObligationsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryObligationsResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryObligationsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryObligationsRequest MCPQueryObligationsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryObligationsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	dueStartTime, err := parseMCPOptionalTime(queryObligationsRequest.DueStartTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dueEndTime, err := parseMCPOptionalTime(queryObligationsRequest.DueEndTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfos, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByName(cfos, queryObligationsRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counterparties, counterpartyNames, err := getMCPCounterpartyNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counterpartyId, err := getMCPCounterpartyIdByName(counterparties, queryObligationsRequest.CounterpartyName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obligations, err := services.GetObligationService().GetAllObligationsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_obligations.Handle] failed to get obligations for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryObligationsResponse{
+		Obligations: make([]*MCPObligationInfo, 0, len(obligations)),
+	}
+
+	for i := 0; i < len(obligations); i++ {
+		obligation := obligations[i]
+		dueDate := utils.ToMillisIfSeconds(obligation.DueDate)
+
+		if queryObligationsRequest.Type == obligationTypeReceivable && obligation.ObligationType != models.OBLIGATION_TYPE_RECEIVABLE ||
+			queryObligationsRequest.Type == obligationTypePayable && obligation.ObligationType != models.OBLIGATION_TYPE_PAYABLE ||
+			!h.matchObligationStatus(obligation.Status, queryObligationsRequest.Status) ||
+			counterpartyId > 0 && obligation.CounterpartyId != counterpartyId ||
+			cfoId > 0 && obligation.CfoId != cfoId ||
+			dueStartTime > 0 && dueDate < dueStartTime ||
+			dueEndTime > 0 && dueDate > dueEndTime {
+			continue
+		}
+
+		response.Obligations = append(response.Obligations, h.createNewMCPObligationInfo(obligation, counterpartyNames, cfoNames))
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQueryObligationsToolHandler) matchObligationStatus(status models.ObligationStatus, filterStatus string) bool {
+	switch filterStatus {
+	case obligationStatusOpen:
+		return status != models.OBLIGATION_STATUS_PAID
+	case obligationStatusActive:
+		return status == models.OBLIGATION_STATUS_ACTIVE
+	case obligationStatusPartial:
+		return status == models.OBLIGATION_STATUS_PARTIAL
+	case obligationStatusPaid:
+		return status == models.OBLIGATION_STATUS_PAID
+	case obligationStatusOverdue:
+		return status == models.OBLIGATION_STATUS_OVERDUE
+	default:
+		return true
+	}
+}
+
+func (h *mcpQueryObligationsToolHandler) createNewMCPObligationInfo(obligation *models.Obligation, counterpartyNames map[int64]string, cfoNames map[int64]string) *MCPObligationInfo {
+	obligationInfo := &MCPObligationInfo{
+		CounterpartyName: counterpartyNames[obligation.CounterpartyId],
+		CfoName:          cfoNames[obligation.CfoId],
+		Amount:           utils.FormatAmount(obligation.Amount),
+		PaidAmount:       utils.FormatAmount(obligation.PaidAmount),
+		RemainingAmount:  utils.FormatAmount(obligation.Amount - obligation.PaidAmount),
+		Currency:         obligation.Currency,
+		DueDate:          formatMCPDate(obligation.DueDate),
+		Comment:          obligation.Comment,
+	}
+
+	if obligation.ObligationType == models.OBLIGATION_TYPE_RECEIVABLE {
+		obligationInfo.Type = obligationTypeReceivable
+	} else if obligation.ObligationType == models.OBLIGATION_TYPE_PAYABLE {
+		obligationInfo.Type = obligationTypePayable
+	}
+
+	if obligation.Status == models.OBLIGATION_STATUS_ACTIVE {
+		obligationInfo.Status = obligationStatusActive
+	} else if obligation.Status == models.OBLIGATION_STATUS_PARTIAL {
+		obligationInfo.Status = obligationStatusPartial
+	} else if obligation.Status == models.OBLIGATION_STATUS_PAID {
+		obligationInfo.Status = obligationStatusPaid
+	} else if obligation.Status == models.OBLIGATION_STATUS_OVERDUE {
+		obligationInfo.Status = obligationStatusOverdue
+	}
+
+	return obligationInfo
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryPaymentCalendarRequest represents all parameters of the query payment calendar request
+type MCPQueryPaymentCalendarRequest struct {
+	StartTime string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time of the period in RFC 3339 format (e.g. 2023-01-01T00:00:00Z)"`
+	EndTime   string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time of the period in RFC 3339 format (e.g. 2023-02-01T00:00:00Z)"`
+	Currency  string `json:"currency,omitempty" jsonschema_description:"Reporting currency code (e.g. USD, EUR) all amounts are converted into (optional, default is the default currency of user)"`
+}
+
+// MCPQueryPaymentCalendarResponse represents the response structure for querying payment calendar
+type MCPQueryPaymentCalendarResponse struct {
+	Currency string                    `json:"currency" jsonschema_description:"Reporting currency code of all converted amounts"`
+	Items    []*MCPPaymentCalendarItem `json:"items" jsonschema_description:"List of upcoming payments ordered by date"`
+	Warnings []string                  `json:"warnings,omitempty" jsonschema_description:"Warnings of the report, e.g. missing exchange rates"`
+}
+
+// MCPPaymentCalendarItem defines the structure of payment calendar item
+type MCPPaymentCalendarItem struct {
+	Date            string `json:"date" jsonschema_description:"Due date of the payment in RFC 3339 format"`
+	Type            string `json:"type" jsonschema_description:"Type of the payment (Receivable, Payable, Tax, Planned)"`
+	Amount          string `json:"amount" jsonschema_description:"Amount of the payment in its original currency"`
+	Currency        string `json:"currency" jsonschema_description:"Original currency code of the payment"`
+	ConvertedAmount string `json:"converted_amount" jsonschema_description:"Amount of the payment in the reporting currency"`
+	Description     string `json:"description,omitempty" jsonschema_description:"Description of the payment"`
+}
+
+type mcpQueryPaymentCalendarToolHandler struct{}
+
+var MCPQueryPaymentCalendarToolHandler = &mcpQueryPaymentCalendarToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryPaymentCalendarToolHandler) Name() string {
+	return "query_payment_calendar"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryPaymentCalendarToolHandler) Description() string {
+	return "Query payment calendar (receivables, payables, taxes and planned transactions due) of a period in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryPaymentCalendarToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryPaymentCalendarRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryPaymentCalendarToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryPaymentCalendarResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryPaymentCalendarToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryPaymentCalendarRequest MCPQueryPaymentCalendarRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryPaymentCalendarRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	startTime, endTime, err := parseMCPTimeRange(queryPaymentCalendarRequest.StartTime, queryPaymentCalendarRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := getMCPReportCurrencyConverter(c, user, queryPaymentCalendarRequest.Currency, currentConfig)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calendar, err := services.GetReportService().GetPaymentCalendar(c, uid, startTime, endTime, converter)
+
+	if err != nil {
+		log.Errorf(c, "[query_payment_calendar.Handle] failed to get payment calendar for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	return h.createNewMCPQueryPaymentCalendarResponse(c, calendar, converter.ReportingCurrency())
+}
+
+func (h *mcpQueryPaymentCalendarToolHandler) createNewMCPQueryPaymentCalendarResponse(c *core.WebContext, calendar *models.PaymentCalendarResponse, currency string) (any, []*MCPTextContent, error) {
+	response := MCPQueryPaymentCalendarResponse{
+		Currency: currency,
+		Items:    make([]*MCPPaymentCalendarItem, 0, len(calendar.Items)),
+		Warnings: calendar.Warnings,
+	}
+
+	for i := 0; i < len(calendar.Items); i++ {
+		item := calendar.Items[i]
+		response.Items = append(response.Items, &MCPPaymentCalendarItem{
+			Date:            formatMCPDate(item.Date),
+			Type:            item.Type,
+			Amount:          utils.FormatAmount(item.Amount),
+			Currency:        item.Currency,
+			ConvertedAmount: utils.FormatAmount(item.ConvertedAmount),
+			Description:     item.Description,
+		})
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
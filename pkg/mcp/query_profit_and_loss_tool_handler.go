@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryProfitAndLossResponse represents the response structure for querying profit and loss report
+type MCPQueryProfitAndLossResponse struct {
+	Currency         string           `json:"currency" jsonschema_description:"Reporting currency code of all amounts"`
+	Revenue          string           `json:"revenue" jsonschema_description:"Revenue"`
+	CostOfGoods      string           `json:"cost_of_goods" jsonschema_description:"Cost of goods sold"`
+	GrossProfit      string           `json:"gross_profit" jsonschema_description:"Gross profit (revenue minus cost of goods sold)"`
+	OperatingExpense string           `json:"operating_expense" jsonschema_description:"Operating expenses"`
+	Depreciation     string           `json:"depreciation" jsonschema_description:"Depreciation of fixed assets"`
+	OperatingProfit  string           `json:"operating_profit" jsonschema_description:"Operating profit"`
+	DisposalGainLoss string           `json:"disposal_gain_loss" jsonschema_description:"Gain or loss (negative) on disposal of fixed assets"`
+	FinancialExpense string           `json:"financial_expense" jsonschema_description:"Financial expenses"`
+	TaxExpense       string           `json:"tax_expense" jsonschema_description:"Tax expenses"`
+	NetProfit        string           `json:"net_profit" jsonschema_description:"Net profit"`
+	Details          []*MCPReportLine `json:"details,omitempty" jsonschema_description:"Detail lines of the report"`
+	Warnings         []string         `json:"warnings,omitempty" jsonschema_description:"Warnings of the report, e.g. missing exchange rates"`
+}
+
+// MCPReportLine defines the structure of a labeled report amount
+type MCPReportLine struct {
+	Label  string `json:"label" jsonschema_description:"Label of the line"`
+	Amount string `json:"amount" jsonschema_description:"Amount of the line"`
+}
+
+type mcpQueryProfitAndLossToolHandler struct{}
+
+var MCPQueryProfitAndLossToolHandler = &mcpQueryProfitAndLossToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryProfitAndLossToolHandler) Name() string {
+	return "query_profit_and_loss"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryProfitAndLossToolHandler) Description() string {
+	return "Query profit and loss statement (revenue, expenses and net profit) of a period in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryProfitAndLossToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryReportRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryProfitAndLossToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryProfitAndLossResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryProfitAndLossToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryReportRequest MCPQueryReportRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryReportRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	startTime, endTime, err := parseMCPTimeRange(queryReportRequest.StartTime, queryReportRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, queryReportRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := getMCPReportCurrencyConverter(c, user, queryReportRequest.Currency, currentConfig)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pnl, err := services.GetReportService().GetPnL(c, uid, cfoId, startTime, endTime, converter)
+
+	if err != nil {
+		log.Errorf(c, "[query_profit_and_loss.Handle] failed to get profit and loss for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	return h.createNewMCPQueryProfitAndLossResponse(c, pnl, converter.ReportingCurrency())
+}
+
+func (h *mcpQueryProfitAndLossToolHandler) createNewMCPQueryProfitAndLossResponse(c *core.WebContext, pnl *models.PnLResponse, currency string) (any, []*MCPTextContent, error) {
+	response := newMCPQueryProfitAndLossResponse(pnl, currency)
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func newMCPQueryProfitAndLossResponse(pnl *models.PnLResponse, currency string) *MCPQueryProfitAndLossResponse {
+	response := &MCPQueryProfitAndLossResponse{
+		Currency:         currency,
+		Revenue:          utils.FormatAmount(pnl.Revenue),
+		CostOfGoods:      utils.FormatAmount(pnl.CostOfGoods),
+		GrossProfit:      utils.FormatAmount(pnl.GrossProfit),
+		OperatingExpense: utils.FormatAmount(pnl.OperatingExpense),
+		Depreciation:     utils.FormatAmount(pnl.Depreciation),
+		OperatingProfit:  utils.FormatAmount(pnl.OperatingProfit),
+		DisposalGainLoss: utils.FormatAmount(pnl.DisposalGainLoss),
+		FinancialExpense: utils.FormatAmount(pnl.FinancialExpense),
+		TaxExpense:       utils.FormatAmount(pnl.TaxExpense),
+		NetProfit:        utils.FormatAmount(pnl.NetProfit),
+		Details:          make([]*MCPReportLine, 0, len(pnl.Details)),
+		Warnings:         pnl.Warnings,
+	}
+
+	for i := 0; i < len(pnl.Details); i++ {
+		response.Details = append(response.Details, &MCPReportLine{
+			Label:  pnl.Details[i].Label,
+			Amount: utils.FormatAmount(pnl.Details[i].Amount),
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	taxTypeIncome   = "income"
+	taxTypeVat      = "vat"
+	taxTypeProperty = "property"
+	taxTypeOther    = "other"
+)
+
+const (
+	taxStatusPending = "pending"
+	taxStatusPaid    = "paid"
+	taxStatusOverdue = "overdue"
+)
+
+// MCPQueryTaxRecordsRequest represents all parameters of the query tax records request
+type MCPQueryTaxRecordsRequest struct {
+	TaxType string `json:"tax_type,omitempty" jsonschema:"enum=income,enum=vat,enum=property,enum=other" jsonschema_description:"Tax type to filter by (income, vat, property, other) (optional)"`
+	Status  string `json:"status,omitempty" jsonschema:"enum=pending,enum=paid,enum=overdue" jsonschema_description:"Tax status to filter by (pending, paid, overdue) (optional)"`
+	Year    int32  `json:"year,omitempty" jsonschema_description:"Tax period year to filter by (optional)"`
+	CfoName string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) to filter by (optional)"`
+}
+
+// MCPQueryTaxRecordsResponse represents the response structure for querying tax records
+type MCPQueryTaxRecordsResponse struct {
+	TaxRecords []*MCPTaxRecordInfo `json:"tax_records" jsonschema_description:"List of tax records matching the query ordered by tax period (latest first)"`
+}
+
+// MCPTaxRecordInfo defines the structure of tax record information
+type MCPTaxRecordInfo struct {
+	TaxType       string `json:"tax_type" jsonschema:"enum=income,enum=vat,enum=property,enum=other" jsonschema_description:"Tax type (income, vat, property, other)"`
+	Status        string `json:"status" jsonschema:"enum=pending,enum=paid,enum=overdue" jsonschema_description:"Tax status (pending, paid, overdue)"`
+	PeriodYear    int32  `json:"period_year" jsonschema_description:"Year of the tax period"`
+	PeriodQuarter int32  `json:"period_quarter,omitempty" jsonschema_description:"Quarter of the tax period, absent for annual taxes"`
+	CfoName       string `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the tax record"`
+	TaxableIncome string `json:"taxable_income" jsonschema_description:"Taxable base of the tax period"`
+	TaxAmount     string `json:"tax_amount" jsonschema_description:"Amount of tax to be paid"`
+	PaidAmount    string `json:"paid_amount" jsonschema_description:"Paid amount of tax"`
+	Currency      string `json:"currency" jsonschema_description:"Currency code of the tax record (e.g. USD, EUR)"`
+	DueDate       string `json:"due_date,omitempty" jsonschema_description:"Due date of the tax payment in RFC 3339 format"`
+	Comment       string `json:"comment,omitempty" jsonschema_description:"Description of the tax record"`
+}
+
+type mcpQueryTaxRecordsToolHandler struct{}
+
+var MCPQueryTaxRecordsToolHandler = &mcpQueryTaxRecordsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryTaxRecordsToolHandler) Name() string {
+	return "query_tax_records"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryTaxRecordsToolHandler) Description() string {
+	return "Query tax records (taxes calculated, paid and due per tax period) in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryTaxRecordsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTaxRecordsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryTaxRecordsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTaxRecordsResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryTaxRecordsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryTaxRecordsRequest MCPQueryTaxRecordsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryTaxRecordsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	cfos, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfoId, err := getMCPCfoIdByName(cfos, queryTaxRecordsRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err := services.GetTaxRecordService().GetAllTaxRecordsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_tax_records.Handle] failed to get tax records for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryTaxRecordsResponse{
+		TaxRecords: make([]*MCPTaxRecordInfo, 0, len(records)),
+	}
+
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		recordInfo := h.createNewMCPTaxRecordInfo(record, cfoNames)
+
+		if queryTaxRecordsRequest.TaxType != "" && recordInfo.TaxType != queryTaxRecordsRequest.TaxType ||
+			queryTaxRecordsRequest.Status != "" && recordInfo.Status != queryTaxRecordsRequest.Status ||
+			queryTaxRecordsRequest.Year > 0 && record.PeriodYear != queryTaxRecordsRequest.Year ||
+			cfoId > 0 && record.CfoId != cfoId {
+			continue
+		}
+
+		response.TaxRecords = append(response.TaxRecords, recordInfo)
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQueryTaxRecordsToolHandler) createNewMCPTaxRecordInfo(record *models.TaxRecord, cfoNames map[int64]string) *MCPTaxRecordInfo {
+	recordInfo := &MCPTaxRecordInfo{
+		PeriodYear:    record.PeriodYear,
+		PeriodQuarter: record.PeriodQuarter,
+		CfoName:       cfoNames[record.CfoId],
+		TaxableIncome: utils.FormatAmount(record.TaxableIncome),
+		TaxAmount:     utils.FormatAmount(record.TaxAmount),
+		PaidAmount:    utils.FormatAmount(record.PaidAmount),
+		Currency:      record.Currency,
+		DueDate:       formatMCPDate(record.DueDate),
+		Comment:       record.Comment,
+	}
+
+	switch record.TaxType {
+	case models.TAX_TYPE_INCOME:
+		recordInfo.TaxType = taxTypeIncome
+	case models.TAX_TYPE_VAT:
+		recordInfo.TaxType = taxTypeVat
+	case models.TAX_TYPE_PROPERTY:
+		recordInfo.TaxType = taxTypeProperty
+	case models.TAX_TYPE_OTHER:
+		recordInfo.TaxType = taxTypeOther
+	}
+
+	switch record.Status {
+	case models.TAX_STATUS_PENDING:
+		recordInfo.Status = taxStatusPending
+	case models.TAX_STATUS_PAID:
+		recordInfo.Status = taxStatusPaid
+	case models.TAX_STATUS_OVERDUE:
+		recordInfo.Status = taxStatusOverdue
+	}
+
+	return recordInfo
+}
@@ -22,6 +22,7 @@ type MCPQueryTransactionsRequest struct {
 	CategoryName string `json:"category_name,omitempty" jsonschema_description:"Category name to filter transactions by (optional)"`
 	AccountName           string `json:"account_name,omitempty" jsonschema_description:"Account name to filter transactions by (optional)"`
 	Keyword               string `json:"keyword,omitempty" jsonschema_description:"Keyword to search in transaction description (optional)"`
+	Search                string `json:"search,omitempty" jsonschema_description:"Full-text search query matched against words of transaction description, counterparty name and tag names, all the words are required to match, append * to a word for prefix match and wrap words in double quotes for phrase match (e.g. coffee sta* \"monthly rent\") (optional)"`
 	Count                 int32  `json:"count,omitempty" jsonschema:"default=100" jsonschema_description:"Maximum number of results to return (default: 100)"`
 	Page                  int32  `json:"page,omitempty" jsonschema:"default=1" jsonschema_description:"Page number for pagination (default: 1)"`
 	ResponseFields        string `json:"response_fields,omitempty" jsonschema_description:"Comma-separated list of optional fields to include in the response (optional, leave empty for all fields, available fields: time, currency, category_name, account_name, comment)"`
@@ -37,6 +38,7 @@ type MCPQueryTransactionsResponse struct {
 
 // MCPTransactionInfo defines the structure of transaction information
 type MCPTransactionInfo struct {
+	Id                     string `json:"id" jsonschema_description:"Transaction id, which can be used to modify, delete or confirm the transaction"`
 	Time                   string `json:"time,omitempty" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
 	Type                   string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
 	Amount                 string `json:"amount" jsonschema_description:"Amount of the transaction in the specified currency"`
@@ -47,6 +49,7 @@ type MCPTransactionInfo struct {
 	DestinationCurrency    string `json:"destination_currency,omitempty" jsonschema_description:"Currency code of the destination amount for transfer transactions (optional)"`
 	DestinationAccountName string `json:"destination_account_name,omitempty" jsonschema_description:"Destination account name for transfer transactions (optional)"`
 	Comment                string `json:"comment,omitempty" jsonschema_description:"Description of the transaction"`
+	Planned                bool   `json:"planned,omitempty" jsonschema_description:"Whether the transaction is planned and not confirmed yet"`
 }
 
 type mcpQueryTransactionsToolHandler struct{}
@@ -153,6 +156,13 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		}
 	}
 
+	searchTerms, err := services.GetTransactionSearchIndexService().GetTransactionSearchTerms(c, uid, queryTransactionsRequest.Search)
+
+	if err != nil {
+		log.Warnf(c, "[query_transactions_tool_handler.Handle] failed to parse search query for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
 	queryParams := &models.TransactionQueryParams{
 		Uid:                uid,
 		MaxTransactionTime: maxTransactionTime,
@@ -161,6 +171,7 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		CategoryIds:        filterCategoryIds,
 		AccountIds:         filterAccountIds,
 		Keyword:            queryTransactionsRequest.Keyword,
+		SearchTerms:        searchTerms,
 	}
 
 	totalCount, err := services.GetTransactionService().GetTransactionCount(c, queryParams)
@@ -178,6 +189,7 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		CategoryIds:        filterCategoryIds,
 		AccountIds:         filterAccountIds,
 		Keyword:            queryTransactionsRequest.Keyword,
+		SearchTerms:        searchTerms,
 		Page:               queryTransactionsRequest.Page,
 		Count:              queryTransactionsRequest.Count,
 		NoDuplicated:       true,
@@ -210,7 +222,9 @@ func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 		transactionInfo := MCPTransactionInfo{
-			Amount: utils.FormatAmount(transaction.Amount),
+			Id:      utils.Int64ToString(transaction.TransactionId),
+			Amount:  utils.FormatAmount(transaction.Amount),
+			Planned: transaction.Planned,
 		}
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
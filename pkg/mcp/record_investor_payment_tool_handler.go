@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	investorPaymentTypePrincipal = "principal"
+	investorPaymentTypeInterest  = "interest"
+	investorPaymentTypeMixed     = "mixed"
+)
+
+// MCPRecordInvestorPaymentRequest represents all parameters of the record investor payment request
+type MCPRecordInvestorPaymentRequest struct {
+	DealId      string `json:"deal_id" jsonschema_description:"Investor deal id returned by query_investor_deals"`
+	PaymentDate string `json:"payment_date,omitempty" jsonschema:"format=date-time" jsonschema_description:"Payment date in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional, now if empty)"`
+	Amount      string `json:"amount" jsonschema_description:"Paid amount in the currency of the deal"`
+	PaymentType string `json:"payment_type" jsonschema:"enum=principal,enum=interest,enum=mixed" jsonschema_description:"Payment type (principal, interest, mixed)"`
+	Comment     string `json:"comment,omitempty" jsonschema_description:"Description of the payment (optional)"`
+	DryRun      bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the payment will not be recorded, only the changes to be applied are returned (optional)"`
+}
+
+type mcpRecordInvestorPaymentToolHandler struct{}
+
+var MCPRecordInvestorPaymentToolHandler = &mcpRecordInvestorPaymentToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpRecordInvestorPaymentToolHandler) Name() string {
+	return "record_investor_payment"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpRecordInvestorPaymentToolHandler) Description() string {
+	return "Record a payment (principal or interest repayment) to the investor of an investor deal in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpRecordInvestorPaymentToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPRecordInvestorPaymentRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpRecordInvestorPaymentToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpRecordInvestorPaymentToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var recordInvestorPaymentRequest MCPRecordInvestorPaymentRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &recordInvestorPaymentRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	dealId, err := parseMCPEntityId(recordInvestorPaymentRequest.DealId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payment, err := h.createNewInvestorPaymentModel(user.Uid, dealId, &recordInvestorPaymentRequest)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uid := user.Uid
+	deal, err := services.GetInvestorDealService().GetDealByDealId(c, uid, dealId)
+
+	if err != nil {
+		log.Errorf(c, "[record_investor_payment.Handle] failed to get investor deal \"id:%d\" for user \"uid:%d\", because %s", dealId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_CREATE,
+		entityType: mcpEntityTypeInvestorPayment,
+		changes: getMCPFieldChanges(nil, []*mcpEntityField{
+			{name: "deal_id", value: utils.Int64ToString(deal.DealId)},
+			{name: "investor_name", value: deal.InvestorName},
+			{name: "payment_date", value: formatMCPDate(payment.PaymentDate)},
+			{name: "amount", value: utils.FormatAmount(payment.Amount)},
+			{name: "currency", value: deal.Currency},
+			{name: "payment_type", value: recordInvestorPaymentRequest.PaymentType},
+			{name: "comment", value: payment.Comment},
+		}),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, recordInvestorPaymentRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetInvestorPaymentService().CreatePaymentInSession(c, sess, payment)
+
+		if err != nil {
+			log.Errorf(c, "[record_investor_payment.Handle] failed to create payment of investor deal \"id:%d\" for user \"uid:%d\", because %s", dealId, uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[record_investor_payment.Handle] user \"uid:%d\" has created a new payment \"id:%d\" of investor deal \"id:%d\" successfully", uid, payment.PaymentId, dealId)
+
+		return payment.PaymentId, nil
+	})
+}
+
+func (h *mcpRecordInvestorPaymentToolHandler) createNewInvestorPaymentModel(uid int64, dealId int64, recordInvestorPaymentRequest *MCPRecordInvestorPaymentRequest) (*models.InvestorPayment, error) {
+	var paymentType models.InvestorPaymentType
+
+	switch recordInvestorPaymentRequest.PaymentType {
+	case investorPaymentTypePrincipal:
+		paymentType = models.INVESTOR_PAYMENT_TYPE_PRINCIPAL
+	case investorPaymentTypeInterest:
+		paymentType = models.INVESTOR_PAYMENT_TYPE_INTEREST
+	case investorPaymentTypeMixed:
+		paymentType = models.INVESTOR_PAYMENT_TYPE_MIXED
+	default:
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	amount, err := utils.ParseAmount(recordInvestorPaymentRequest.Amount)
+
+	if err != nil || amount <= 0 {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	paymentDate, err := parseMCPOptionalTime(recordInvestorPaymentRequest.PaymentDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if paymentDate == 0 {
+		paymentDate = time.Now().UnixMilli()
+	}
+
+	return &models.InvestorPayment{
+		Uid:         uid,
+		DealId:      dealId,
+		PaymentDate: paymentDate,
+		Amount:      amount,
+		PaymentType: paymentType,
+		Comment:     recordInvestorPaymentRequest.Comment,
+	}, nil
+}
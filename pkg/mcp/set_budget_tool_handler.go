@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPSetBudgetRequest represents all parameters of the set budget request
+type MCPSetBudgetRequest struct {
+	Year    int32                   `json:"year" jsonschema:"minimum=2000,maximum=2100" jsonschema_description:"Year of the budget"`
+	Month   int32                   `json:"month" jsonschema:"minimum=1,maximum=12" jsonschema_description:"Month of the budget (1-12)"`
+	Version string                  `json:"version,omitempty" jsonschema_description:"Budget version name (optional, leave empty for the working budget)"`
+	CfoName string                  `json:"cfo_name,omitempty" jsonschema_description:"Name of the financial responsibility center (CFO) of the budget (optional, leave empty for the budget without CFO)"`
+	Items   []*MCPSetBudgetItemInfo `json:"items" jsonschema_description:"Budget lines to set, other lines of the budget are kept unchanged"`
+	DryRun  bool                    `json:"dry_run,omitempty" jsonschema_description:"If true, the budget will not be saved, only the changes to be applied are returned (optional)"`
+}
+
+// MCPSetBudgetItemInfo defines the structure of a budget line to set
+type MCPSetBudgetItemInfo struct {
+	CategoryName  string  `json:"category_name" jsonschema_description:"Income or expense category name of the budget line"`
+	PlannedAmount string  `json:"planned_amount" jsonschema_description:"Planned amount of the category for the month"`
+	Comment       *string `json:"comment,omitempty" jsonschema_description:"Description of the budget line (optional, unchanged if not set)"`
+}
+
+type mcpSetBudgetToolHandler struct{}
+
+var MCPSetBudgetToolHandler = &mcpSetBudgetToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpSetBudgetToolHandler) Name() string {
+	return "set_budget"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpSetBudgetToolHandler) Description() string {
+	return "Set the planned amounts of income and expense categories in the monthly budget in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpSetBudgetToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPSetBudgetRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpSetBudgetToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPMutationResponse{})
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpSetBudgetToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var setBudgetRequest MCPSetBudgetRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &setBudgetRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if setBudgetRequest.Year < 2000 || setBudgetRequest.Year > 2100 || setBudgetRequest.Month < 1 || setBudgetRequest.Month > 12 ||
+		len(setBudgetRequest.Version) > 32 || len(setBudgetRequest.Items) < 1 {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	cfoId, err := getMCPCfoIdByNameFromServices(c, services, uid, setBudgetRequest.CfoName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Errorf(c, "[set_budget.Handle] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	budgets, err := services.GetBudgetService().GetBudgetsByYearMonth(c, uid, setBudgetRequest.Year, setBudgetRequest.Month, cfoId, setBudgetRequest.Version)
+
+	if err != nil {
+		log.Errorf(c, "[set_budget.Handle] failed to get budgets of %04d-%02d for user \"uid:%d\", because %s", setBudgetRequest.Year, setBudgetRequest.Month, uid, err.Error())
+		return nil, nil, err
+	}
+
+	existingBudgets := make(map[int64]*models.Budget, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		if budgets[i].CfoId == cfoId {
+			existingBudgets[budgets[i].CategoryId] = budgets[i]
+		}
+	}
+
+	items := make([]*models.BudgetItemRequest, 0, len(setBudgetRequest.Items))
+	oldFields := make([]*mcpEntityField, 0, len(setBudgetRequest.Items)*2)
+	newFields := make([]*mcpEntityField, 0, len(setBudgetRequest.Items)*2)
+	categoryIds := make(map[int64]bool, len(setBudgetRequest.Items))
+
+	for i := 0; i < len(setBudgetRequest.Items); i++ {
+		itemInfo := setBudgetRequest.Items[i]
+		category := h.getCategoryByName(allCategories, itemInfo.CategoryName)
+
+		if category == nil {
+			log.Warnf(c, "[set_budget.Handle] category \"%s\" not found for user \"uid:%d\"", itemInfo.CategoryName, uid)
+			return nil, nil, errs.ErrTransactionCategoryNotFound
+		}
+
+		if categoryIds[category.CategoryId] {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		categoryIds[category.CategoryId] = true
+		plannedAmount, err := utils.ParseAmount(itemInfo.PlannedAmount)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		item := &models.BudgetItemRequest{
+			CategoryId:    category.CategoryId,
+			PlannedAmount: plannedAmount,
+		}
+
+		if existingBudget, exists := existingBudgets[category.CategoryId]; exists {
+			item.Comment = existingBudget.Comment
+			oldFields = append(oldFields, &mcpEntityField{name: category.Name + ".planned_amount", value: utils.FormatAmount(existingBudget.PlannedAmount)})
+			oldFields = append(oldFields, &mcpEntityField{name: category.Name + ".comment", value: existingBudget.Comment})
+		}
+
+		if itemInfo.Comment != nil {
+			item.Comment = *itemInfo.Comment
+		}
+
+		newFields = append(newFields, &mcpEntityField{name: category.Name + ".planned_amount", value: utils.FormatAmount(item.PlannedAmount)})
+		newFields = append(newFields, &mcpEntityField{name: category.Name + ".comment", value: item.Comment})
+		items = append(items, item)
+	}
+
+	mutation := &mcpMutation{
+		toolName:   h.Name(),
+		action:     models.MCP_AUDIT_ACTION_MODIFY,
+		entityType: mcpEntityTypeBudget,
+		changes:    getMCPFieldChanges(oldFields, newFields),
+	}
+
+	return applyMCPMutation(c, user, services, mutation, setBudgetRequest.DryRun, func(database *datastore.Database, sess *xorm.Session) (int64, error) {
+		err := services.GetBudgetService().SaveBudgetsInSession(c, sess, uid, setBudgetRequest.Year, setBudgetRequest.Month, cfoId, setBudgetRequest.Version, items)
+
+		if err != nil {
+			log.Errorf(c, "[set_budget.Handle] failed to save budgets of %04d-%02d for user \"uid:%d\", because %s", setBudgetRequest.Year, setBudgetRequest.Month, uid, err.Error())
+			return 0, err
+		}
+
+		log.Infof(c, "[set_budget.Handle] user \"uid:%d\" has saved %d budget lines of %04d-%02d successfully", uid, len(items), setBudgetRequest.Year, setBudgetRequest.Month)
+
+		return 0, nil
+	})
+}
+
+func (h *mcpSetBudgetToolHandler) getCategoryByName(allCategories []*models.TransactionCategory, categoryName string) *models.TransactionCategory {
+	for i := 0; i < len(allCategories); i++ {
+		category := allCategories[i]
+
+		if !category.Hidden && (category.Type == models.CATEGORY_TYPE_INCOME || category.Type == models.CATEGORY_TYPE_EXPENSE) && category.Name == categoryName {
+			return category
+		}
+	}
+
+	return nil
+}
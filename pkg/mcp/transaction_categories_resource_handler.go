@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPTransactionCategoriesResource represents the contents of the transaction category tree resource
+type MCPTransactionCategoriesResource struct {
+	Categories []*MCPTransactionCategoryResourceInfo `json:"categories"`
+}
+
+// MCPTransactionCategoryResourceInfo defines the structure of transaction category information in the category tree resource
+type MCPTransactionCategoryResourceInfo struct {
+	Name          string                                `json:"name"`
+	Type          string                                `json:"type"`
+	ActivityType  string                                `json:"activity_type,omitempty"`
+	CostType      string                                `json:"cost_type,omitempty"`
+	CfoName       string                                `json:"cfo_name,omitempty"`
+	Comment       string                                `json:"comment,omitempty"`
+	SubCategories []*MCPTransactionCategoryResourceInfo `json:"sub_categories,omitempty"`
+}
+
+var mcpActivityTypeNames = map[models.ActivityType]string{
+	models.ACTIVITY_TYPE_OPERATING: "operating",
+	models.ACTIVITY_TYPE_INVESTING: "investing",
+	models.ACTIVITY_TYPE_FINANCING: "financing",
+}
+
+var mcpCostTypeNames = map[models.CostType]string{
+	models.COST_TYPE_COGS:        "cost_of_goods_sold",
+	models.COST_TYPE_OPERATIONAL: "operational",
+	models.COST_TYPE_FINANCIAL:   "financial",
+}
+
+type mcpTransactionCategoriesResourceHandler struct{}
+
+var MCPTransactionCategoriesResourceHandler = &mcpTransactionCategoriesResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) URI() string {
+	return "ezbookkeeping://transaction-categories"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Name() string {
+	return "Transaction category tree"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Description() string {
+	return "All visible income, expense and transfer categories with sub-categories, cash flow activity types, cost types and CFOs."
+}
+
+// MimeType returns the MIME type of the MCP resource contents
+func (h *mcpTransactionCategoriesResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Read(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	uid := user.Uid
+	categories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories_resource.Read] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	_, cfoNames, err := getMCPCfoNameMap(c, services, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resource := MCPTransactionCategoriesResource{
+		Categories: make([]*MCPTransactionCategoryResourceInfo, 0, len(categories)),
+	}
+
+	categoryInfos := make(map[int64]*MCPTransactionCategoryResourceInfo, len(categories))
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden || category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		categoryInfo := h.createNewMCPTransactionCategoryResourceInfo(category, cfoNames)
+		categoryInfos[category.CategoryId] = categoryInfo
+		resource.Categories = append(resource.Categories, categoryInfo)
+	}
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		if parentCategoryInfo, exists := categoryInfos[category.ParentCategoryId]; exists {
+			parentCategoryInfo.SubCategories = append(parentCategoryInfo.SubCategories, h.createNewMCPTransactionCategoryResourceInfo(category, cfoNames))
+		}
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(h.URI(), string(content), h.MimeType()), nil
+}
+
+func (h *mcpTransactionCategoriesResourceHandler) createNewMCPTransactionCategoryResourceInfo(category *models.TransactionCategory, cfoNames map[int64]string) *MCPTransactionCategoryResourceInfo {
+	categoryInfo := &MCPTransactionCategoryResourceInfo{
+		Name:         category.Name,
+		ActivityType: mcpActivityTypeNames[models.ActivityType(category.ActivityType)],
+		CfoName:      cfoNames[category.CfoId],
+		Comment:      category.Comment,
+	}
+
+	if category.Type == models.CATEGORY_TYPE_INCOME {
+		categoryInfo.Type = transactionTypeIncome
+	} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+		categoryInfo.Type = transactionTypeExpense
+		categoryInfo.CostType = mcpCostTypeNames[models.CostType(category.CostType)]
+	} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
+		categoryInfo.Type = transactionTypeTransfer
+	}
+
+	return categoryInfo
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// mcpTransactionReferences represents the accounts, categories and tags of user which transactions refer to
+type mcpTransactionReferences struct {
+	allAccounts   []*models.Account
+	accountsMap   map[int64]*models.Account
+	allCategories []*models.TransactionCategory
+	categoriesMap map[int64]*models.TransactionCategory
+	allTags       []*models.TransactionTag
+	tagsMap       map[int64]*models.TransactionTag
+}
+
+// getMCPTransactionReferences returns all accounts, categories and tags of user
+func getMCPTransactionReferences(c *core.WebContext, services MCPAvailableServices, uid int64) (*mcpTransactionReferences, error) {
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_mutation_tool_helpers.getMCPTransactionReferences] get account error, because %s", err.Error())
+		return nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_mutation_tool_helpers.getMCPTransactionReferences] get transaction category error, because %s", err.Error())
+		return nil, err
+	}
+
+	allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_mutation_tool_helpers.getMCPTransactionReferences] get transaction tag error, because %s", err.Error())
+		return nil, err
+	}
+
+	return &mcpTransactionReferences{
+		allAccounts:   allAccounts,
+		accountsMap:   services.GetAccountService().GetAccountMapByList(allAccounts),
+		allCategories: allCategories,
+		categoriesMap: services.GetTransactionCategoryService().GetCategoryMapByList(allCategories),
+		allTags:       allTags,
+		tagsMap:       services.GetTransactionTagService().GetTagMapByList(allTags),
+	}, nil
+}
+
+// getMCPTransactionTagIds returns the tag ids of the transaction
+func getMCPTransactionTagIds(c *core.WebContext, services MCPAvailableServices, uid int64, transactionId int64) ([]int64, error) {
+	allTransactionTagIds, err := services.GetTransactionTagService().GetAllTagIdsOfTransactions(c, uid, []int64{transactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_mutation_tool_helpers.getMCPTransactionTagIds] failed to get tag ids of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, err
+	}
+
+	tagIds := allTransactionTagIds[transactionId]
+
+	if tagIds == nil {
+		tagIds = make([]int64, 0)
+	}
+
+	return tagIds, nil
+}
+
+// getMCPTransactionTimezone returns the timezone which the transaction time is recorded in
+func getMCPTransactionTimezone(transaction *models.Transaction) *time.Location {
+	return time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+}
+
+// getTransactionFields returns the fields of the transaction used to calculate the changes
+func (r *mcpTransactionReferences) getTransactionFields(transaction *models.Transaction, tagIds []int64) []*mcpEntityField {
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	fields := []*mcpEntityField{
+		{name: "type", value: getMCPTransactionTypeName(transaction.Type)},
+		{name: "time", value: utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(transactionUnixTime, getMCPTransactionTimezone(transaction))},
+		{name: "planned", value: strconv.FormatBool(transaction.Planned)},
+	}
+
+	if category, exists := r.categoriesMap[transaction.CategoryId]; exists {
+		fields = append(fields, &mcpEntityField{name: "category_name", value: category.Name})
+	}
+
+	if account, exists := r.accountsMap[transaction.AccountId]; exists {
+		fields = append(fields, &mcpEntityField{name: "account_name", value: account.Name})
+	}
+
+	fields = append(fields, &mcpEntityField{name: "amount", value: utils.FormatAmount(transaction.Amount)})
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		if destinationAccount, exists := r.accountsMap[transaction.RelatedAccountId]; exists {
+			fields = append(fields, &mcpEntityField{name: "destination_account_name", value: destinationAccount.Name})
+		}
+
+		fields = append(fields, &mcpEntityField{name: "destination_amount", value: utils.FormatAmount(transaction.RelatedAccountAmount)})
+	}
+
+	tagNames := make([]string, 0, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		if tag, exists := r.tagsMap[tagIds[i]]; exists {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+
+	sort.Strings(tagNames)
+
+	fields = append(fields, &mcpEntityField{name: "tags", value: strings.Join(tagNames, ", ")})
+	fields = append(fields, &mcpEntityField{name: "comment", value: transaction.Comment})
+
+	return fields
+}
+
+// getMCPTransactionTypeName returns the transaction type name used in MCP tools
+func getMCPTransactionTypeName(transactionType models.TransactionDbType) string {
+	switch transactionType {
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		return transactionTypeIncome
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		return transactionTypeExpense
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		return transactionTypeTransfer
+	default:
+		return "balance_modification"
+	}
+}
@@ -1,16 +1,20 @@
 package models
 
+import (
+	"encoding/json"
+)
+
 // AssetType represents asset type
 type AssetType byte
 
 // Asset types
 const (
-	ASSET_TYPE_EQUIPMENT  AssetType = 1
-	ASSET_TYPE_FURNITURE  AssetType = 2
-	ASSET_TYPE_VEHICLE    AssetType = 3
+	ASSET_TYPE_EQUIPMENT   AssetType = 1
+	ASSET_TYPE_FURNITURE   AssetType = 2
+	ASSET_TYPE_VEHICLE     AssetType = 3
 	ASSET_TYPE_ELECTRONICS AssetType = 4
 	ASSET_TYPE_REAL_ESTATE AssetType = 5
-	ASSET_TYPE_OTHER      AssetType = 6
+	ASSET_TYPE_OTHER       AssetType = 6
 )
 
 // AssetStatus represents asset status
@@ -18,31 +22,53 @@ type AssetStatus byte
 
 // Asset statuses
 const (
-	ASSET_STATUS_ACTIVE       AssetStatus = 1
+	ASSET_STATUS_ACTIVE         AssetStatus = 1
 	ASSET_STATUS_DECOMMISSIONED AssetStatus = 2
-	ASSET_STATUS_SOLD         AssetStatus = 3
+	ASSET_STATUS_SOLD           AssetStatus = 3
+)
+
+// AssetDepreciationMethod represents the depreciation method of asset
+type AssetDepreciationMethod byte
+
+// Asset depreciation methods
+const (
+	ASSET_DEPRECIATION_METHOD_STRAIGHT_LINE       AssetDepreciationMethod = 1
+	ASSET_DEPRECIATION_METHOD_DECLINING_BALANCE   AssetDepreciationMethod = 2
+	ASSET_DEPRECIATION_METHOD_SUM_OF_YEARS_DIGITS AssetDepreciationMethod = 3
+	ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION AssetDepreciationMethod = 4
 )
 
+// AssetMaxUsefulLifeMonths is the maximum useful life months of asset
+const AssetMaxUsefulLifeMonths = 1200
+
 // Asset represents asset data stored in database
 type Asset struct {
-	AssetId                int64       `xorm:"PK"`
-	Uid                    int64       `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
-	Deleted                bool        `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
-	CfoId                  int64       `xorm:"NOT NULL DEFAULT 0"`
-	LocationId             int64       `xorm:"NOT NULL DEFAULT 0"`
-	Name                   string      `xorm:"VARCHAR(64) NOT NULL"`
-	AssetType              AssetType   `xorm:"NOT NULL DEFAULT 1"`
-	PurchaseDate           int64       `xorm:"NOT NULL DEFAULT 0"`
-	PurchaseCost           int64       `xorm:"NOT NULL DEFAULT 0"`
-	UsefulLifeMonths       int32       `xorm:"NOT NULL DEFAULT 0"`
-	SalvageValue           int64       `xorm:"NOT NULL DEFAULT 0"`
-	Status                 AssetStatus `xorm:"NOT NULL DEFAULT 1"`
-	CommissionDate         int64       `xorm:"NOT NULL DEFAULT 0"`
-	DecommissionDate       int64       `xorm:"NOT NULL DEFAULT 0"`
-	InstalledCapacityWatts int64       `xorm:"NOT NULL DEFAULT 0"`
-	Comment                string      `xorm:"VARCHAR(255) NOT NULL"`
-	DisplayOrder           int32       `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
-	Hidden                 bool        `xorm:"NOT NULL"`
+	AssetId                int64                      `xorm:"PK"`
+	Uid                    int64                      `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
+	Deleted                bool                       `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
+	CfoId                  int64                      `xorm:"NOT NULL DEFAULT 0"`
+	LocationId             int64                      `xorm:"NOT NULL DEFAULT 0"`
+	Name                   string                     `xorm:"VARCHAR(64) NOT NULL"`
+	AssetType              AssetType                  `xorm:"NOT NULL DEFAULT 1"`
+	PurchaseDate           int64                      `xorm:"NOT NULL DEFAULT 0"`
+	PurchaseCost           int64                      `xorm:"NOT NULL DEFAULT 0"`
+	UsefulLifeMonths       int32                      `xorm:"NOT NULL DEFAULT 0"`
+	SalvageValue           int64                      `xorm:"NOT NULL DEFAULT 0"`
+	DepreciationMethod     AssetDepreciationMethod    `xorm:"NOT NULL DEFAULT 1"`
+	Status                 AssetStatus                `xorm:"NOT NULL DEFAULT 1"`
+	CommissionDate         int64                      `xorm:"NOT NULL DEFAULT 0"`
+	DecommissionDate       int64                      `xorm:"NOT NULL DEFAULT 0"`
+	InstalledCapacityWatts int64                      `xorm:"NOT NULL DEFAULT 0"`
+	AccountId              int64                      `xorm:"NOT NULL DEFAULT 0"`
+	DepreciationCategoryId int64                      `xorm:"NOT NULL DEFAULT 0"`
+	DepreciationPostedTime int64                      `xorm:"NOT NULL DEFAULT 0"`
+	TimezoneUtcOffset      int16                      `xorm:"NOT NULL DEFAULT 0"`
+	ProductionOutputs      AssetProductionOutputSlice `xorm:"BLOB"`
+	DisposalProceeds       int64                      `xorm:"NOT NULL DEFAULT 0"`
+	DisposalBookValue      int64                      `xorm:"NOT NULL DEFAULT 0"`
+	Comment                string                     `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder           int32                      `xorm:"INDEX(IDX_asset_uid_deleted_order) NOT NULL"`
+	Hidden                 bool                       `xorm:"NOT NULL"`
 	CreatedUnixTime        int64
 	UpdatedUnixTime        int64
 	DeletedUnixTime        int64
@@ -55,39 +81,47 @@ type AssetGetRequest struct {
 
 // AssetCreateRequest represents all parameters of asset creation request
 type AssetCreateRequest struct {
-	Name                   string      `json:"name" binding:"required,notBlank,max=64"`
-	CfoId                  int64       `json:"cfoId,string"`
-	LocationId             int64       `json:"locationId,string"`
-	AssetType              AssetType   `json:"assetType" binding:"min=0"`
-	PurchaseDate           int64       `json:"purchaseDate"`
-	PurchaseCost           int64       `json:"purchaseCost" binding:"min=0"`
-	UsefulLifeMonths       int32       `json:"usefulLifeMonths" binding:"min=0"`
-	SalvageValue           int64       `json:"salvageValue" binding:"min=0"`
-	Status                 AssetStatus `json:"status"`
-	CommissionDate         int64       `json:"commissionDate"`
-	DecommissionDate       int64       `json:"decommissionDate"`
-	InstalledCapacityWatts int64       `json:"installedCapacityWatts" binding:"min=0"`
-	Comment                string      `json:"comment" binding:"max=255"`
-	ClientSessionId        string      `json:"clientSessionId"`
+	Name                   string                  `json:"name" binding:"required,notBlank,max=64"`
+	CfoId                  int64                   `json:"cfoId,string"`
+	LocationId             int64                   `json:"locationId,string"`
+	AssetType              AssetType               `json:"assetType" binding:"min=0"`
+	PurchaseDate           int64                   `json:"purchaseDate"`
+	PurchaseCost           int64                   `json:"purchaseCost" binding:"min=0"`
+	UsefulLifeMonths       int32                   `json:"usefulLifeMonths" binding:"min=0,max=1200"`
+	SalvageValue           int64                   `json:"salvageValue" binding:"min=0"`
+	DepreciationMethod     AssetDepreciationMethod `json:"depreciationMethod" binding:"min=0,max=4"`
+	Status                 AssetStatus             `json:"status"`
+	CommissionDate         int64                   `json:"commissionDate"`
+	DecommissionDate       int64                   `json:"decommissionDate"`
+	InstalledCapacityWatts int64                   `json:"installedCapacityWatts" binding:"min=0"`
+	AccountId              int64                   `json:"accountId,string"`
+	DepreciationCategoryId int64                   `json:"depreciationCategoryId,string"`
+	UtcOffset              int16                   `json:"utcOffset" binding:"min=-720,max=840"`
+	Comment                string                  `json:"comment" binding:"max=255"`
+	ClientSessionId        string                  `json:"clientSessionId"`
 }
 
 // AssetModifyRequest represents all parameters of asset modification request
 type AssetModifyRequest struct {
-	Id                     int64       `json:"id,string" binding:"required,min=1"`
-	Name                   string      `json:"name" binding:"required,notBlank,max=64"`
-	CfoId                  int64       `json:"cfoId,string"`
-	LocationId             int64       `json:"locationId,string"`
-	AssetType              AssetType   `json:"assetType"`
-	PurchaseDate           int64       `json:"purchaseDate"`
-	PurchaseCost           int64       `json:"purchaseCost"`
-	UsefulLifeMonths       int32       `json:"usefulLifeMonths"`
-	SalvageValue           int64       `json:"salvageValue"`
-	Status                 AssetStatus `json:"status"`
-	CommissionDate         int64       `json:"commissionDate"`
-	DecommissionDate       int64       `json:"decommissionDate"`
-	InstalledCapacityWatts int64       `json:"installedCapacityWatts"`
-	Comment                string      `json:"comment" binding:"max=255"`
-	Hidden                 bool        `json:"hidden"`
+	Id                     int64                   `json:"id,string" binding:"required,min=1"`
+	Name                   string                  `json:"name" binding:"required,notBlank,max=64"`
+	CfoId                  int64                   `json:"cfoId,string"`
+	LocationId             int64                   `json:"locationId,string"`
+	AssetType              AssetType               `json:"assetType"`
+	PurchaseDate           int64                   `json:"purchaseDate"`
+	PurchaseCost           int64                   `json:"purchaseCost"`
+	UsefulLifeMonths       int32                   `json:"usefulLifeMonths" binding:"min=0,max=1200"`
+	SalvageValue           int64                   `json:"salvageValue"`
+	DepreciationMethod     AssetDepreciationMethod `json:"depreciationMethod" binding:"min=0,max=4"`
+	Status                 AssetStatus             `json:"status"`
+	CommissionDate         int64                   `json:"commissionDate"`
+	DecommissionDate       int64                   `json:"decommissionDate"`
+	InstalledCapacityWatts int64                   `json:"installedCapacityWatts"`
+	AccountId              int64                   `json:"accountId,string"`
+	DepreciationCategoryId int64                   `json:"depreciationCategoryId,string"`
+	UtcOffset              int16                   `json:"utcOffset" binding:"min=-720,max=840"`
+	Comment                string                  `json:"comment" binding:"max=255"`
+	Hidden                 bool                    `json:"hidden"`
 }
 
 // AssetHideRequest represents all parameters of asset hiding request
@@ -112,24 +146,56 @@ type AssetDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// AssetDepreciationScheduleRequest represents all parameters of asset depreciation schedule getting request
+type AssetDepreciationScheduleRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AssetProductionOutputSaveRequest represents all parameters of asset production output saving request
+type AssetProductionOutputSaveRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Year   int32 `json:"year" binding:"required,min=1"`
+	Month  int32 `json:"month" binding:"required,min=1,max=12"`
+	Output int64 `json:"output" binding:"min=0"`
+}
+
+// AssetDisposeRequest represents all parameters of asset disposing request
+type AssetDisposeRequest struct {
+	Id                 int64       `json:"id,string" binding:"required,min=1"`
+	Status             AssetStatus `json:"status" binding:"required,min=2,max=3"`
+	DisposalDate       int64       `json:"disposalDate" binding:"required,min=1"`
+	Proceeds           int64       `json:"proceeds" binding:"min=0"`
+	ProceedsAccountId  int64       `json:"proceedsAccountId,string"`
+	ProceedsCategoryId int64       `json:"proceedsCategoryId,string"`
+	UtcOffset          int16       `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
 // AssetInfoResponse represents a view-object of asset
 type AssetInfoResponse struct {
-	Id                     int64       `json:"id,string"`
-	Name                   string      `json:"name"`
-	CfoId                  int64       `json:"cfoId,string"`
-	LocationId             int64       `json:"locationId,string"`
-	AssetType              AssetType   `json:"assetType"`
-	PurchaseDate           int64       `json:"purchaseDate"`
-	PurchaseCost           int64       `json:"purchaseCost"`
-	UsefulLifeMonths       int32       `json:"usefulLifeMonths"`
-	SalvageValue           int64       `json:"salvageValue"`
-	Status                 AssetStatus `json:"status"`
-	CommissionDate         int64       `json:"commissionDate"`
-	DecommissionDate       int64       `json:"decommissionDate"`
-	InstalledCapacityWatts int64       `json:"installedCapacityWatts"`
-	Comment                string      `json:"comment"`
-	DisplayOrder           int32       `json:"displayOrder"`
-	Hidden                 bool        `json:"hidden"`
+	Id                     int64                      `json:"id,string"`
+	Name                   string                     `json:"name"`
+	CfoId                  int64                      `json:"cfoId,string"`
+	LocationId             int64                      `json:"locationId,string"`
+	AssetType              AssetType                  `json:"assetType"`
+	PurchaseDate           int64                      `json:"purchaseDate"`
+	PurchaseCost           int64                      `json:"purchaseCost"`
+	UsefulLifeMonths       int32                      `json:"usefulLifeMonths"`
+	SalvageValue           int64                      `json:"salvageValue"`
+	DepreciationMethod     AssetDepreciationMethod    `json:"depreciationMethod"`
+	Status                 AssetStatus                `json:"status"`
+	CommissionDate         int64                      `json:"commissionDate"`
+	DecommissionDate       int64                      `json:"decommissionDate"`
+	InstalledCapacityWatts int64                      `json:"installedCapacityWatts"`
+	AccountId              int64                      `json:"accountId,string"`
+	DepreciationCategoryId int64                      `json:"depreciationCategoryId,string"`
+	DepreciationPostedTime int64                      `json:"depreciationPostedTime"`
+	UtcOffset              int16                      `json:"utcOffset"`
+	ProductionOutputs      AssetProductionOutputSlice `json:"productionOutputs"`
+	DisposalProceeds       int64                      `json:"disposalProceeds"`
+	DisposalBookValue      int64                      `json:"disposalBookValue"`
+	Comment                string                     `json:"comment"`
+	DisplayOrder           int32                      `json:"displayOrder"`
+	Hidden                 bool                       `json:"hidden"`
 }
 
 // ToAssetInfoResponse returns a view-object according to database model
@@ -144,16 +210,95 @@ func (a *Asset) ToAssetInfoResponse() *AssetInfoResponse {
 		PurchaseCost:           a.PurchaseCost,
 		UsefulLifeMonths:       a.UsefulLifeMonths,
 		SalvageValue:           a.SalvageValue,
+		DepreciationMethod:     a.DepreciationMethod,
 		Status:                 a.Status,
 		CommissionDate:         a.CommissionDate,
 		DecommissionDate:       a.DecommissionDate,
 		InstalledCapacityWatts: a.InstalledCapacityWatts,
+		AccountId:              a.AccountId,
+		DepreciationCategoryId: a.DepreciationCategoryId,
+		DepreciationPostedTime: a.DepreciationPostedTime,
+		UtcOffset:              a.TimezoneUtcOffset,
+		ProductionOutputs:      a.ProductionOutputs,
+		DisposalProceeds:       a.DisposalProceeds,
+		DisposalBookValue:      a.DisposalBookValue,
 		Comment:                a.Comment,
 		DisplayOrder:           a.DisplayOrder,
 		Hidden:                 a.Hidden,
 	}
 }
 
+// AssetDepreciationScheduleResponse represents a view-object of asset depreciation schedule
+type AssetDepreciationScheduleResponse struct {
+	Id                 int64                                    `json:"id,string"`
+	DepreciationMethod AssetDepreciationMethod                  `json:"depreciationMethod"`
+	PurchaseCost       int64                                    `json:"purchaseCost"`
+	SalvageValue       int64                                    `json:"salvageValue"`
+	Items              []*AssetDepreciationScheduleItemResponse `json:"items"`
+}
+
+// AssetDepreciationScheduleItemResponse represents the depreciation of asset in a calendar month,
+// the start time and end time are the part of the month in which the asset is depreciated
+type AssetDepreciationScheduleItemResponse struct {
+	Year                    int32 `json:"year"`
+	Month                   int32 `json:"month"`
+	StartTime               int64 `json:"startTime"`
+	EndTime                 int64 `json:"endTime"`
+	Depreciation            int64 `json:"depreciation"`
+	AccumulatedDepreciation int64 `json:"accumulatedDepreciation"`
+	BookValue               int64 `json:"bookValue"`
+}
+
+// AssetProductionOutput represents the actual output of asset in a calendar month, which is in the same unit as the installed capacity
+type AssetProductionOutput struct {
+	Year   int32 `json:"year"`
+	Month  int32 `json:"month"`
+	Output int64 `json:"output"`
+}
+
+// AssetProductionOutputSlice represents the slice data structure of AssetProductionOutput
+type AssetProductionOutputSlice []*AssetProductionOutput
+
+// FromDB fills the fields from the data stored in database
+func (s *AssetProductionOutputSlice) FromDB(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+// ToDB returns the actual stored data in database
+func (s *AssetProductionOutputSlice) ToDB() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Get returns the production output of the specified calendar month, or nil if the output of that month has not been entered
+func (s AssetProductionOutputSlice) Get(year int32, month int32) *AssetProductionOutput {
+	for i := 0; i < len(s); i++ {
+		if s[i].Year == year && s[i].Month == month {
+			return s[i]
+		}
+	}
+
+	return nil
+}
+
+// Len returns the count of items
+func (s AssetProductionOutputSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s AssetProductionOutputSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s AssetProductionOutputSlice) Less(i, j int) bool {
+	if s[i].Year != s[j].Year {
+		return s[i].Year < s[j].Year
+	}
+
+	return s[i].Month < s[j].Month
+}
+
 // AssetInfoResponseSlice represents the slice data structure of AssetInfoResponse
 type AssetInfoResponseSlice []*AssetInfoResponse
 
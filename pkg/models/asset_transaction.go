@@ -0,0 +1,58 @@
+package models
+
+// AssetTransactionType represents the type of the transaction linked to asset
+type AssetTransactionType byte
+
+// Asset transaction types
+const (
+	ASSET_TRANSACTION_TYPE_PURCHASE           AssetTransactionType = 1
+	ASSET_TRANSACTION_TYPE_DEPRECIATION       AssetTransactionType = 2
+	ASSET_TRANSACTION_TYPE_DISPOSAL_PROCEEDS  AssetTransactionType = 3
+	ASSET_TRANSACTION_TYPE_DISPOSAL_WRITE_OFF AssetTransactionType = 4
+)
+
+// AssetTransaction represents the link between asset and transaction stored in database,
+// purchase transactions are capitalized into the asset, and depreciation and disposal transactions are posted for the asset
+type AssetTransaction struct {
+	Uid             int64                `xorm:"PK INDEX(IDX_asset_transaction_uid_asset_id)"`
+	TransactionId   int64                `xorm:"PK"`
+	AssetId         int64                `xorm:"INDEX(IDX_asset_transaction_uid_asset_id) NOT NULL"`
+	TransactionType AssetTransactionType `xorm:"NOT NULL"`
+	Amount          int64                `xorm:"NOT NULL DEFAULT 0"`
+	PeriodStartTime int64                `xorm:"NOT NULL DEFAULT 0"`
+	PeriodEndTime   int64                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnixTime int64
+}
+
+// AssetTransactionListRequest represents all parameters of asset transaction listing request
+type AssetTransactionListRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AssetPurchaseTransactionLinkRequest represents all parameters of asset purchase transaction linking or unlinking request
+type AssetPurchaseTransactionLinkRequest struct {
+	Id             int64    `json:"id,string" binding:"required,min=1"`
+	TransactionIds []string `json:"transactionIds" binding:"required,min=1,max=100"`
+}
+
+// AssetTransactionInfoResponse represents a view-object of the transaction linked to asset
+type AssetTransactionInfoResponse struct {
+	TransactionId   int64                `json:"transactionId,string"`
+	AssetId         int64                `json:"assetId,string"`
+	TransactionType AssetTransactionType `json:"transactionType"`
+	Amount          int64                `json:"amount"`
+	PeriodStartTime int64                `json:"periodStartTime,omitempty"`
+	PeriodEndTime   int64                `json:"periodEndTime,omitempty"`
+}
+
+// ToAssetTransactionInfoResponse returns a view-object according to database model
+func (t *AssetTransaction) ToAssetTransactionInfoResponse() *AssetTransactionInfoResponse {
+	return &AssetTransactionInfoResponse{
+		TransactionId:   t.TransactionId,
+		AssetId:         t.AssetId,
+		TransactionType: t.TransactionType,
+		Amount:          t.Amount,
+		PeriodStartTime: t.PeriodStartTime,
+		PeriodEndTime:   t.PeriodEndTime,
+	}
+}
@@ -9,6 +9,7 @@ type Budget struct {
 	CategoryId      int64  `xorm:"NOT NULL DEFAULT 0"`
 	Year            int32  `xorm:"INDEX(IDX_budget_uid_deleted_year_month) NOT NULL"`
 	Month           int32  `xorm:"INDEX(IDX_budget_uid_deleted_year_month) NOT NULL"`
+	Version         string `xorm:"VARCHAR(32) NOT NULL DEFAULT ''"`
 	PlannedAmount   int64  `xorm:"NOT NULL DEFAULT 0"`
 	Comment         string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
 	CreatedUnixTime int64
@@ -18,9 +19,10 @@ type Budget struct {
 
 // BudgetListRequest represents parameters to list budgets
 type BudgetListRequest struct {
-	Year  int32 `form:"year" binding:"required,min=2000,max=2100"`
-	Month int32 `form:"month" binding:"required,min=1,max=12"`
-	CfoId int64 `form:"cfoId,string"`
+	Year    int32  `form:"year" binding:"required,min=2000,max=2100"`
+	Month   int32  `form:"month" binding:"required,min=1,max=12"`
+	CfoId   int64  `form:"cfoId,string"`
+	Version string `form:"version" binding:"max=32"`
 }
 
 // BudgetSaveRequest represents parameters to save budgets (bulk)
@@ -28,6 +30,7 @@ type BudgetSaveRequest struct {
 	Year    int32               `json:"year" binding:"required,min=2000,max=2100"`
 	Month   int32               `json:"month" binding:"required,min=1,max=12"`
 	CfoId   int64               `json:"cfoId,string"`
+	Version string              `json:"version" binding:"max=32"`
 	Budgets []*BudgetItemRequest `json:"budgets" binding:"required"`
 }
 
@@ -38,11 +41,57 @@ type BudgetItemRequest struct {
 	Comment       string `json:"comment" binding:"max=255"`
 }
 
+// BudgetCopyRequest represents parameters to copy the budget of one month to a range of months
+type BudgetCopyRequest struct {
+	SourceYear       int32  `json:"sourceYear" binding:"required,min=2000,max=2100"`
+	SourceMonth      int32  `json:"sourceMonth" binding:"required,min=1,max=12"`
+	SourceVersion    string `json:"sourceVersion" binding:"max=32"`
+	CfoId            int64  `json:"cfoId,string"`
+	TargetStartYear  int32  `json:"targetStartYear" binding:"required,min=2000,max=2100"`
+	TargetStartMonth int32  `json:"targetStartMonth" binding:"required,min=1,max=12"`
+	TargetEndYear    int32  `json:"targetEndYear" binding:"required,min=2000,max=2100"`
+	TargetEndMonth   int32  `json:"targetEndMonth" binding:"required,min=1,max=12"`
+	TargetVersion    string `json:"targetVersion" binding:"max=32"`
+}
+
+// BudgetSpreadRequest represents parameters to spread annual amounts over the months of a year
+type BudgetSpreadRequest struct {
+	Year    int32                      `json:"year" binding:"required,min=2000,max=2100"`
+	CfoId   int64                      `json:"cfoId,string"`
+	Version string                     `json:"version" binding:"max=32"`
+	Weights []int64                    `json:"weights" binding:"omitempty,len=12,dive,min=0"`
+	Budgets []*BudgetAnnualItemRequest `json:"budgets" binding:"required"`
+}
+
+// BudgetAnnualItemRequest represents the annual amount of a single budget line
+type BudgetAnnualItemRequest struct {
+	CategoryId   int64  `json:"categoryId,string" binding:"required,min=1"`
+	AnnualAmount int64  `json:"annualAmount"`
+	Comment      string `json:"comment" binding:"max=255"`
+}
+
+// BudgetFromActualsRequest represents parameters to derive the budget of a month from actual amounts of previous months
+type BudgetFromActualsRequest struct {
+	Year      int32  `json:"year" binding:"required,min=2000,max=2100"`
+	Month     int32  `json:"month" binding:"required,min=1,max=12"`
+	CfoId     int64  `json:"cfoId,string"`
+	Version   string `json:"version" binding:"max=32"`
+	Months    int32  `json:"months" binding:"required,min=1,max=24"`
+	UtcOffset int16  `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// BudgetVersionListRequest represents parameters to list budget versions
+type BudgetVersionListRequest struct {
+	Year  int32 `form:"year" binding:"omitempty,min=2000,max=2100"`
+	CfoId int64 `form:"cfoId,string"`
+}
+
 // PlanFactRequest represents parameters for plan-fact analysis
 type PlanFactRequest struct {
-	Year  int32 `form:"year" binding:"required,min=2000,max=2100"`
-	Month int32 `form:"month" binding:"required,min=1,max=12"`
-	CfoId int64 `form:"cfoId,string"`
+	Year    int32  `form:"year" binding:"required,min=2000,max=2100"`
+	Month   int32  `form:"month" binding:"required,min=1,max=12"`
+	CfoId   int64  `form:"cfoId,string"`
+	Version string `form:"version" binding:"max=32"`
 }
 
 // BudgetInfoResponse represents a view-object of budget
@@ -52,6 +101,7 @@ type BudgetInfoResponse struct {
 	CategoryId    int64  `json:"categoryId,string"`
 	Year          int32  `json:"year"`
 	Month         int32  `json:"month"`
+	Version       string `json:"version"`
 	PlannedAmount int64  `json:"plannedAmount"`
 	Comment       string `json:"comment"`
 }
@@ -64,6 +114,7 @@ func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
 		CategoryId:    b.CategoryId,
 		Year:          b.Year,
 		Month:         b.Month,
+		Version:       b.Version,
 		PlannedAmount: b.PlannedAmount,
 		Comment:       b.Comment,
 	}
@@ -82,8 +133,86 @@ type PlanFactLineResponse struct {
 
 // PlanFactResponse represents plan-fact analysis result
 type PlanFactResponse struct {
-	Year  int32                   `json:"year"`
-	Month int32                   `json:"month"`
-	CfoId int64                   `json:"cfoId,string"`
-	Lines []*PlanFactLineResponse `json:"lines"`
+	Year    int32                   `json:"year"`
+	Month   int32                   `json:"month"`
+	CfoId   int64                   `json:"cfoId,string"`
+	Version string                  `json:"version"`
+	Lines   []*PlanFactLineResponse `json:"lines"`
+}
+
+// BudgetVersionInfoResponse represents a view-object of budget version
+type BudgetVersionInfoResponse struct {
+	Version     string `json:"version"`
+	BudgetCount int64  `json:"budgetCount"`
+}
+
+// PlanFactPeriodType represents the period type of plan-fact analysis
+type PlanFactPeriodType byte
+
+// Plan-fact period types
+const (
+	PLAN_FACT_PERIOD_MONTH        PlanFactPeriodType = 1
+	PLAN_FACT_PERIOD_QUARTER      PlanFactPeriodType = 2
+	PLAN_FACT_PERIOD_FISCAL_YEAR  PlanFactPeriodType = 3
+	PLAN_FACT_PERIOD_YEAR_TO_DATE PlanFactPeriodType = 4
+	PLAN_FACT_PERIOD_CUSTOM       PlanFactPeriodType = 5
+)
+
+// PlanFactRangeRequest represents parameters for plan-fact analysis of a period.
+// Year means the fiscal year for quarter and fiscal year periods, Year and Month mean the last month for year-to-date period
+// and the first month for custom period which ends at EndYear and EndMonth.
+type PlanFactRangeRequest struct {
+	PeriodType PlanFactPeriodType `form:"periodType" binding:"required,min=1,max=5"`
+	Year       int32              `form:"year" binding:"required,min=2000,max=2100"`
+	Month      int32              `form:"month" binding:"omitempty,min=1,max=12"`
+	Quarter    int32              `form:"quarter" binding:"omitempty,min=1,max=4"`
+	EndYear    int32              `form:"endYear" binding:"omitempty,min=2000,max=2100"`
+	EndMonth   int32              `form:"endMonth" binding:"omitempty,min=1,max=12"`
+	CfoId      int64              `form:"cfoId,string"`
+	Version    string             `form:"version" binding:"max=32"`
+}
+
+// PlanFactAmountsResponse represents planned and fact amounts and their deviation
+type PlanFactAmountsResponse struct {
+	PlannedAmount int64  `json:"plannedAmount"`
+	FactAmount    int64  `json:"factAmount"`
+	Deviation     int64  `json:"deviation"`
+	DeviationPct  *int32 `json:"deviationPct"`
+}
+
+// PlanFactTableColumnResponse represents a column of plan-fact table, which is a month or a CFO
+type PlanFactTableColumnResponse struct {
+	Year      int32  `json:"year,omitempty"`
+	Month     int32  `json:"month,omitempty"`
+	StartTime int64  `json:"startTime,omitempty"`
+	EndTime   int64  `json:"endTime,omitempty"`
+	CfoId     int64  `json:"cfoId,string,omitempty"`
+	CfoName   string `json:"cfoName,omitempty"`
+}
+
+// PlanFactTableLineResponse represents a plan-fact table line for one category.
+// Amounts of a rollup line include amounts of its sub-categories.
+type PlanFactTableLineResponse struct {
+	CategoryId       int64                      `json:"categoryId,string"`
+	CategoryName     string                     `json:"categoryName"`
+	CategoryType     int32                      `json:"categoryType"`
+	ParentCategoryId int64                      `json:"parentCategoryId,string"`
+	Rollup           bool                       `json:"rollup"`
+	Columns          []*PlanFactAmountsResponse `json:"columns"`
+	Cumulative       []*PlanFactAmountsResponse `json:"cumulative,omitempty"`
+	Total            *PlanFactAmountsResponse   `json:"total"`
+}
+
+// PlanFactTableResponse represents plan-fact analysis result of a period with month or CFO columns
+type PlanFactTableResponse struct {
+	StartYear  int32                          `json:"startYear"`
+	StartMonth int32                          `json:"startMonth"`
+	EndYear    int32                          `json:"endYear"`
+	EndMonth   int32                          `json:"endMonth"`
+	StartTime  int64                          `json:"startTime"`
+	EndTime    int64                          `json:"endTime"`
+	CfoId      int64                          `json:"cfoId,string"`
+	Version    string                         `json:"version"`
+	Columns    []*PlanFactTableColumnResponse `json:"columns"`
+	Lines      []*PlanFactTableLineResponse   `json:"lines"`
 }
@@ -31,6 +31,7 @@ type ExportTransactionDataRequest struct {
 	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
 	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword      string          `form:"keyword"`
-	MaxTime      int64           `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
-	MinTime      int64           `form:"min_time" binding:"min=0"` // Unix timestamp in seconds
+	TransactionAdvancedFilterRequest
+	MaxTime int64 `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
+	MinTime int64 `form:"min_time" binding:"min=0"` // Unix timestamp in seconds
 }
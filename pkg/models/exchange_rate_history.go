@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+)
+
+// ExchangeRateHistoryDateFormat represents the date format of exchange rate history
+const ExchangeRateHistoryDateFormat = "2006-01-02"
+
+// ExchangeRateHistory represents the exchange rate of a currency published by exchange rates data source on a specified date
+type ExchangeRateHistory struct {
+	DataSource      string `xorm:"PK VARCHAR(32) NOT NULL"`
+	RateDate        string `xorm:"PK VARCHAR(10) NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(3) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// HistoricalExchangeRateRequest represents all parameters of historical exchange rate getting request
+type HistoricalExchangeRateRequest struct {
+	Date     string `form:"date" binding:"required,len=10"`
+	Currency string `form:"currency" binding:"omitempty,len=3,validCurrency"`
+}
+
+// HistoricalExchangeRateResponse represents a view-object of exchange rates in effect on a specified date
+type HistoricalExchangeRateResponse struct {
+	DataSource    string                    `json:"dataSource"`
+	Date          string                    `json:"date"`
+	BaseCurrency  string                    `json:"baseCurrency"`
+	ExchangeRates []*HistoricalExchangeRate `json:"exchangeRates"`
+}
+
+// HistoricalExchangeRate represents the exchange rate of a currency and the date on which it was published
+type HistoricalExchangeRate struct {
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+	RateDate string `json:"rateDate"`
+}
+
+// ToHistoricalExchangeRate returns a view-object according to database model
+func (r *ExchangeRateHistory) ToHistoricalExchangeRate() *HistoricalExchangeRate {
+	return &HistoricalExchangeRate{
+		Currency: r.Currency,
+		Rate:     r.Rate,
+		RateDate: r.RateDate,
+	}
+}
+
+// GetExchangeRateHistoryDate returns the exchange rate history date of the specified unix time
+func GetExchangeRateHistoryDate(unixTime int64) string {
+	return time.Unix(unixTime, 0).UTC().Format(ExchangeRateHistoryDateFormat)
+}
+
+// ParseExchangeRateHistoryDate returns the time of the specified exchange rate history date
+func ParseExchangeRateHistoryDate(date string) (time.Time, error) {
+	return time.ParseInLocation(ExchangeRateHistoryDateFormat, date, time.UTC)
+}
+
+// ToLatestExchangeRateResponse returns a view-object which contains all exchange rates in the specified histories
+func (s ExchangeRateHistorySlice) ToLatestExchangeRateResponse() *LatestExchangeRateResponse {
+	if len(s) < 1 {
+		return nil
+	}
+
+	exchangeRates := make(LatestExchangeRateSlice, 0, len(s))
+	latestRateDate := ""
+
+	for i := 0; i < len(s); i++ {
+		exchangeRates = append(exchangeRates, &LatestExchangeRate{
+			Currency: s[i].Currency,
+			Rate:     s[i].Rate,
+		})
+
+		if s[i].RateDate > latestRateDate {
+			latestRateDate = s[i].RateDate
+		}
+	}
+
+	updateTime := int64(0)
+
+	if rateDate, err := ParseExchangeRateHistoryDate(latestRateDate); err == nil {
+		updateTime = rateDate.Unix()
+	}
+
+	return &LatestExchangeRateResponse{
+		DataSource:    s[0].DataSource,
+		UpdateTime:    updateTime,
+		BaseCurrency:  s[0].BaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+// ExchangeRateHistorySlice represents the slice data structure of ExchangeRateHistory
+type ExchangeRateHistorySlice []*ExchangeRateHistory
+
+// Len returns the count of items
+func (s ExchangeRateHistorySlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ExchangeRateHistorySlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ExchangeRateHistorySlice) Less(i, j int) bool {
+	return s[i].Currency < s[j].Currency
+}
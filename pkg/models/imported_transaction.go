@@ -56,6 +56,7 @@ type ImportTransactionResponse struct {
 	Comment                            string                          `json:"comment"`
 	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
 	CounterpartyId                     int64                           `json:"counterpartyId,string,omitempty"`
+	OriginalCounterpartyName           string                          `json:"originalCounterpartyName,omitempty"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -100,6 +101,7 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		Comment:                            t.Comment,
 		GeoLocation:                        geoLocation,
 		CounterpartyId:                     t.CounterpartyId,
+		OriginalCounterpartyName:           t.OriginalCounterpartyName,
 	}
 }
 
@@ -11,24 +11,34 @@ const (
 	INVESTOR_DEAL_TYPE_OTHER         InvestorDealType = 4
 )
 
+// InvestorRepaymentMethod represents the repayment method of loan deal
+type InvestorRepaymentMethod byte
+
+// Investor repayment methods
+const (
+	INVESTOR_REPAYMENT_METHOD_ANNUITY InvestorRepaymentMethod = 1
+	INVESTOR_REPAYMENT_METHOD_LINEAR  InvestorRepaymentMethod = 2
+)
+
 // InvestorDeal represents investor deal data stored in database
 type InvestorDeal struct {
-	DealId             int64            `xorm:"PK"`
-	Uid                int64            `xorm:"INDEX(IDX_investor_deal_uid_deleted) NOT NULL"`
-	Deleted            bool             `xorm:"INDEX(IDX_investor_deal_uid_deleted) NOT NULL"`
-	InvestorName       string           `xorm:"VARCHAR(64) NOT NULL"`
-	CfoId              int64            `xorm:"NOT NULL DEFAULT 0"`
-	InvestmentDate     int64            `xorm:"NOT NULL DEFAULT 0"`
-	InvestmentAmount   int64            `xorm:"NOT NULL DEFAULT 0"`
-	Currency           string           `xorm:"VARCHAR(3) NOT NULL DEFAULT 'RUB'"`
-	DealType           InvestorDealType `xorm:"NOT NULL DEFAULT 1"`
-	AnnualRate         int32            `xorm:"NOT NULL DEFAULT 0"`
-	ProfitSharePct     int32            `xorm:"NOT NULL DEFAULT 0"`
-	FixedPayment       int64            `xorm:"NOT NULL DEFAULT 0"`
-	RepaymentStartDate int64            `xorm:"NOT NULL DEFAULT 0"`
-	RepaymentEndDate   int64            `xorm:"NOT NULL DEFAULT 0"`
-	TotalToRepay       int64            `xorm:"NOT NULL DEFAULT 0"`
-	Comment            string           `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	DealId             int64                   `xorm:"PK"`
+	Uid                int64                   `xorm:"INDEX(IDX_investor_deal_uid_deleted) NOT NULL"`
+	Deleted            bool                    `xorm:"INDEX(IDX_investor_deal_uid_deleted) NOT NULL"`
+	InvestorName       string                  `xorm:"VARCHAR(64) NOT NULL"`
+	CfoId              int64                   `xorm:"NOT NULL DEFAULT 0"`
+	InvestmentDate     int64                   `xorm:"NOT NULL DEFAULT 0"`
+	InvestmentAmount   int64                   `xorm:"NOT NULL DEFAULT 0"`
+	Currency           string                  `xorm:"VARCHAR(3) NOT NULL DEFAULT 'RUB'"`
+	DealType           InvestorDealType        `xorm:"NOT NULL DEFAULT 1"`
+	RepaymentMethod    InvestorRepaymentMethod `xorm:"NOT NULL DEFAULT 1"`
+	AnnualRate         int32                   `xorm:"NOT NULL DEFAULT 0"`
+	ProfitSharePct     int32                   `xorm:"NOT NULL DEFAULT 0"`
+	FixedPayment       int64                   `xorm:"NOT NULL DEFAULT 0"`
+	RepaymentStartDate int64                   `xorm:"NOT NULL DEFAULT 0"`
+	RepaymentEndDate   int64                   `xorm:"NOT NULL DEFAULT 0"`
+	TotalToRepay       int64                   `xorm:"NOT NULL DEFAULT 0"`
+	Comment            string                  `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
 	CreatedUnixTime    int64
 	UpdatedUnixTime    int64
 	DeletedUnixTime    int64
@@ -41,37 +51,39 @@ type InvestorDealGetRequest struct {
 
 // InvestorDealCreateRequest represents all parameters of deal creation request
 type InvestorDealCreateRequest struct {
-	InvestorName       string           `json:"investorName" binding:"required,notBlank,max=64"`
-	CfoId              int64            `json:"cfoId,string"`
-	InvestmentDate     int64            `json:"investmentDate"`
-	InvestmentAmount   int64            `json:"investmentAmount" binding:"min=0"`
-	Currency           string           `json:"currency" binding:"required,max=3"`
-	DealType           InvestorDealType `json:"dealType"`
-	AnnualRate         int32            `json:"annualRate" binding:"min=0,max=10000"`
-	ProfitSharePct     int32            `json:"profitSharePct" binding:"min=0,max=100"`
-	FixedPayment       int64            `json:"fixedPayment" binding:"min=0"`
-	RepaymentStartDate int64            `json:"repaymentStartDate"`
-	RepaymentEndDate   int64            `json:"repaymentEndDate"`
-	TotalToRepay       int64            `json:"totalToRepay" binding:"min=0"`
-	Comment            string           `json:"comment" binding:"max=255"`
+	InvestorName       string                  `json:"investorName" binding:"required,notBlank,max=64"`
+	CfoId              int64                   `json:"cfoId,string"`
+	InvestmentDate     int64                   `json:"investmentDate"`
+	InvestmentAmount   int64                   `json:"investmentAmount" binding:"min=0"`
+	Currency           string                  `json:"currency" binding:"required,max=3"`
+	DealType           InvestorDealType        `json:"dealType"`
+	RepaymentMethod    InvestorRepaymentMethod `json:"repaymentMethod" binding:"omitempty,min=1,max=2"`
+	AnnualRate         int32                   `json:"annualRate" binding:"min=0,max=10000"`
+	ProfitSharePct     int32                   `json:"profitSharePct" binding:"min=0,max=100"`
+	FixedPayment       int64                   `json:"fixedPayment" binding:"min=0"`
+	RepaymentStartDate int64                   `json:"repaymentStartDate"`
+	RepaymentEndDate   int64                   `json:"repaymentEndDate"`
+	TotalToRepay       int64                   `json:"totalToRepay" binding:"min=0"`
+	Comment            string                  `json:"comment" binding:"max=255"`
 }
 
 // InvestorDealModifyRequest represents all parameters of deal modification request
 type InvestorDealModifyRequest struct {
-	Id                 int64            `json:"id,string" binding:"required,min=1"`
-	InvestorName       string           `json:"investorName" binding:"required,notBlank,max=64"`
-	CfoId              int64            `json:"cfoId,string"`
-	InvestmentDate     int64            `json:"investmentDate"`
-	InvestmentAmount   int64            `json:"investmentAmount"`
-	Currency           string           `json:"currency" binding:"required,max=3"`
-	DealType           InvestorDealType `json:"dealType"`
-	AnnualRate         int32            `json:"annualRate"`
-	ProfitSharePct     int32            `json:"profitSharePct"`
-	FixedPayment       int64            `json:"fixedPayment"`
-	RepaymentStartDate int64            `json:"repaymentStartDate"`
-	RepaymentEndDate   int64            `json:"repaymentEndDate"`
-	TotalToRepay       int64            `json:"totalToRepay"`
-	Comment            string           `json:"comment" binding:"max=255"`
+	Id                 int64                   `json:"id,string" binding:"required,min=1"`
+	InvestorName       string                  `json:"investorName" binding:"required,notBlank,max=64"`
+	CfoId              int64                   `json:"cfoId,string"`
+	InvestmentDate     int64                   `json:"investmentDate"`
+	InvestmentAmount   int64                   `json:"investmentAmount"`
+	Currency           string                  `json:"currency" binding:"required,max=3"`
+	DealType           InvestorDealType        `json:"dealType"`
+	RepaymentMethod    InvestorRepaymentMethod `json:"repaymentMethod" binding:"omitempty,min=1,max=2"`
+	AnnualRate         int32                   `json:"annualRate"`
+	ProfitSharePct     int32                   `json:"profitSharePct"`
+	FixedPayment       int64                   `json:"fixedPayment"`
+	RepaymentStartDate int64                   `json:"repaymentStartDate"`
+	RepaymentEndDate   int64                   `json:"repaymentEndDate"`
+	TotalToRepay       int64                   `json:"totalToRepay"`
+	Comment            string                  `json:"comment" binding:"max=255"`
 }
 
 // InvestorDealDeleteRequest represents all parameters of deal deleting request
@@ -81,20 +93,21 @@ type InvestorDealDeleteRequest struct {
 
 // InvestorDealInfoResponse represents a view-object of investor deal
 type InvestorDealInfoResponse struct {
-	Id                 int64            `json:"id,string"`
-	InvestorName       string           `json:"investorName"`
-	CfoId              int64            `json:"cfoId,string"`
-	InvestmentDate     int64            `json:"investmentDate"`
-	InvestmentAmount   int64            `json:"investmentAmount"`
-	Currency           string           `json:"currency"`
-	DealType           InvestorDealType `json:"dealType"`
-	AnnualRate         int32            `json:"annualRate"`
-	ProfitSharePct     int32            `json:"profitSharePct"`
-	FixedPayment       int64            `json:"fixedPayment"`
-	RepaymentStartDate int64            `json:"repaymentStartDate"`
-	RepaymentEndDate   int64            `json:"repaymentEndDate"`
-	TotalToRepay       int64            `json:"totalToRepay"`
-	Comment            string           `json:"comment"`
+	Id                 int64                   `json:"id,string"`
+	InvestorName       string                  `json:"investorName"`
+	CfoId              int64                   `json:"cfoId,string"`
+	InvestmentDate     int64                   `json:"investmentDate"`
+	InvestmentAmount   int64                   `json:"investmentAmount"`
+	Currency           string                  `json:"currency"`
+	DealType           InvestorDealType        `json:"dealType"`
+	RepaymentMethod    InvestorRepaymentMethod `json:"repaymentMethod"`
+	AnnualRate         int32                   `json:"annualRate"`
+	ProfitSharePct     int32                   `json:"profitSharePct"`
+	FixedPayment       int64                   `json:"fixedPayment"`
+	RepaymentStartDate int64                   `json:"repaymentStartDate"`
+	RepaymentEndDate   int64                   `json:"repaymentEndDate"`
+	TotalToRepay       int64                   `json:"totalToRepay"`
+	Comment            string                  `json:"comment"`
 }
 
 // ToInvestorDealInfoResponse returns a view-object according to database model
@@ -107,6 +120,7 @@ func (d *InvestorDeal) ToInvestorDealInfoResponse() *InvestorDealInfoResponse {
 		InvestmentAmount:   d.InvestmentAmount,
 		Currency:           d.Currency,
 		DealType:           d.DealType,
+		RepaymentMethod:    d.RepaymentMethod,
 		AnnualRate:         d.AnnualRate,
 		ProfitSharePct:     d.ProfitSharePct,
 		FixedPayment:       d.FixedPayment,
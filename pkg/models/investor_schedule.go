@@ -0,0 +1,85 @@
+package models
+
+// InvestorScheduleInstalmentStatus represents the status of a scheduled instalment compared with the recorded payments
+type InvestorScheduleInstalmentStatus string
+
+// Investor schedule instalment statuses
+const (
+	INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID       InvestorScheduleInstalmentStatus = "paid"
+	INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID_AHEAD InvestorScheduleInstalmentStatus = "paidAhead"
+	INVESTOR_SCHEDULE_INSTALMENT_STATUS_PARTIAL    InvestorScheduleInstalmentStatus = "partial"
+	INVESTOR_SCHEDULE_INSTALMENT_STATUS_OVERDUE    InvestorScheduleInstalmentStatus = "overdue"
+	INVESTOR_SCHEDULE_INSTALMENT_STATUS_UPCOMING   InvestorScheduleInstalmentStatus = "upcoming"
+)
+
+// InvestorScheduleTransactionPart represents the part of instalment paid by the planned transaction created for it
+type InvestorScheduleTransactionPart byte
+
+// Investor schedule transaction parts
+const (
+	INVESTOR_SCHEDULE_TRANSACTION_PART_PAYMENT   InvestorScheduleTransactionPart = 1
+	INVESTOR_SCHEDULE_TRANSACTION_PART_PRINCIPAL InvestorScheduleTransactionPart = 2
+	INVESTOR_SCHEDULE_TRANSACTION_PART_INTEREST  InvestorScheduleTransactionPart = 3
+)
+
+// InvestorScheduleTransaction represents the link between the instalment of investor deal repayment schedule
+// and the planned transaction created for it stored in database
+type InvestorScheduleTransaction struct {
+	Uid              int64                           `xorm:"PK INDEX(IDX_investor_schedule_transaction_uid_deal_id_number)"`
+	TransactionId    int64                           `xorm:"PK"`
+	DealId           int64                           `xorm:"INDEX(IDX_investor_schedule_transaction_uid_deal_id_number) NOT NULL"`
+	InstalmentNumber int                             `xorm:"INDEX(IDX_investor_schedule_transaction_uid_deal_id_number) NOT NULL"`
+	Part             InvestorScheduleTransactionPart `xorm:"NOT NULL"`
+	CreatedUnixTime  int64
+}
+
+// InvestorScheduleGetRequest represents all parameters of repayment schedule getting request
+type InvestorScheduleGetRequest struct {
+	DealId int64 `form:"dealId,string" binding:"required,min=1"`
+}
+
+// InvestorSchedulePlannedTransactionsCreateRequest represents all parameters of creating planned transactions for future instalments,
+// the interest is included in the principal transaction if the interest category is not set
+type InvestorSchedulePlannedTransactionsCreateRequest struct {
+	DealId              int64 `json:"dealId,string" binding:"required,min=1"`
+	AccountId           int64 `json:"accountId,string" binding:"required,min=1"`
+	PrincipalCategoryId int64 `json:"principalCategoryId,string" binding:"required,min=1"`
+	InterestCategoryId  int64 `json:"interestCategoryId,string" binding:"min=0"`
+	CounterpartyId      int64 `json:"counterpartyId,string" binding:"min=0"`
+	UtcOffset           int16 `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// InvestorScheduleInstalment represents an instalment of investor deal repayment schedule
+type InvestorScheduleInstalment struct {
+	Number             int                              `json:"number"`
+	Date               int64                            `json:"date"`
+	Principal          int64                            `json:"principal"`
+	Interest           int64                            `json:"interest"`
+	Payment            int64                            `json:"payment"`
+	RemainingPrincipal int64                            `json:"remainingPrincipal"`
+	Revenue            int64                            `json:"revenue,omitempty"`
+	Projected          bool                             `json:"projected,omitempty"`
+	PaidAmount         int64                            `json:"paidAmount"`
+	Status             InvestorScheduleInstalmentStatus `json:"status"`
+}
+
+// InvestorScheduleResponse represents a view-object of investor deal repayment schedule compared with the recorded payments
+type InvestorScheduleResponse struct {
+	DealId          int64                         `json:"dealId,string"`
+	DealType        InvestorDealType              `json:"dealType"`
+	RepaymentMethod InvestorRepaymentMethod       `json:"repaymentMethod,omitempty"`
+	Currency        string                        `json:"currency"`
+	Instalments     []*InvestorScheduleInstalment `json:"instalments"`
+	TotalPrincipal  int64                         `json:"totalPrincipal"`
+	TotalInterest   int64                         `json:"totalInterest"`
+	TotalPayment    int64                         `json:"totalPayment"`
+	TotalPaid       int64                         `json:"totalPaid"`
+	ScheduledToDate int64                         `json:"scheduledToDate"`
+	OverdueAmount   int64                         `json:"overdueAmount"`
+	AheadAmount     int64                         `json:"aheadAmount"`
+}
+
+// InvestorSchedulePlannedTransactionsCreateResponse represents a view-object of planned transactions creating result
+type InvestorSchedulePlannedTransactionsCreateResponse struct {
+	Count int `json:"count"`
+}
@@ -2,15 +2,24 @@ package models
 
 // RecognizedReceiptImageResponse represents a view-object of recognized receipt image response
 type RecognizedReceiptImageResponse struct {
-	Type                 TransactionType `json:"type"`
-	Time                 int64           `json:"time,omitempty"`
-	CategoryId           int64           `json:"categoryId,string,omitempty"`
-	SourceAccountId      int64           `json:"sourceAccountId,string,omitempty"`
-	DestinationAccountId int64           `json:"destinationAccountId,string,omitempty"`
-	SourceAmount         int64           `json:"sourceAmount,omitempty"`
-	DestinationAmount    int64           `json:"destinationAmount,omitempty"`
-	TagIds               []string        `json:"tagIds,omitempty"`
-	Comment              string          `json:"comment,omitempty"`
+	Type                 TransactionType            `json:"type"`
+	Time                 int64                      `json:"time,omitempty"`
+	CategoryId           int64                      `json:"categoryId,string,omitempty"`
+	SourceAccountId      int64                      `json:"sourceAccountId,string,omitempty"`
+	DestinationAccountId int64                      `json:"destinationAccountId,string,omitempty"`
+	SourceAmount         int64                      `json:"sourceAmount,omitempty"`
+	DestinationAmount    int64                      `json:"destinationAmount,omitempty"`
+	TagIds               []string                   `json:"tagIds,omitempty"`
+	Comment              string                     `json:"comment,omitempty"`
+	CounterpartyId       int64                      `json:"counterpartyId,string,omitempty"`
+	Splits               []TransactionSplitResponse `json:"splits,omitempty"`
+	PictureIds           []string                   `json:"pictureIds,omitempty"`
+}
+
+// RecognizedReceiptFilesResponse represents a view-object of recognized receipt files response
+type RecognizedReceiptFilesResponse struct {
+	Transactions []*RecognizedReceiptImageResponse        `json:"transactions"`
+	Pictures     TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
 }
 
 // RecognizedReceiptImageResult represents the result of recognized receipt image
@@ -25,3 +34,113 @@ type RecognizedReceiptImageResult struct {
 	DestinationAmount      string   `json:"destination_amount,omitempty" jsonschema_description:"Destination amount for transfer transactions"`
 	DestinationAccountName string   `json:"destination_account,omitempty" jsonschema_description:"Destination account name for transfer transactions"`
 }
+
+// RecognizedReceiptFileResult represents the result of recognized receipt file which contains multiple transactions
+type RecognizedReceiptFileResult struct {
+	Transactions []*RecognizedReceiptFileTransactionResult `json:"transactions" jsonschema_description:"List of transactions in the file"`
+}
+
+// RecognizedReceiptFileTransactionResult represents a transaction in the result of recognized receipt file
+type RecognizedReceiptFileTransactionResult struct {
+	RecognizedReceiptImageResult
+	CounterpartyName string                                 `json:"counterparty,omitempty" jsonschema_description:"Counterparty (merchant or supplier) name for the transaction"`
+	Items            []*RecognizedReceiptFileLineItemResult `json:"items,omitempty" jsonschema_description:"List of line items of the transaction"`
+}
+
+// RecognizedReceiptFileLineItemResult represents a line item of transaction in the result of recognized receipt file
+type RecognizedReceiptFileLineItemResult struct {
+	Amount       string   `json:"amount" jsonschema_description:"Line item amount"`
+	CategoryName string   `json:"category,omitempty" jsonschema_description:"Category name for the line item"`
+	TagNames     []string `json:"tags,omitempty" jsonschema_description:"List of tags associated with the line item"`
+	Description  string   `json:"description,omitempty" jsonschema_description:"Line item description"`
+}
+
+// ImportTransactionCategorizationSuggestRequest represents all parameters of imported transaction categorization suggestion request
+type ImportTransactionCategorizationSuggestRequest struct {
+	Transactions []*ImportTransactionCategorizationItem `json:"transactions" binding:"required,min=1,max=100,dive"`
+}
+
+// ImportTransactionCategorizationItem represents an imported transaction which needs categorization suggestion
+type ImportTransactionCategorizationItem struct {
+	Index                         int             `json:"index" binding:"min=0"`
+	Type                          TransactionType `json:"type" binding:"required"`
+	Time                          int64           `json:"time"`
+	UtcOffset                     int16           `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAmount                  int64           `json:"sourceAmount"`
+	OriginalSourceAccountCurrency string          `json:"originalSourceAccountCurrency" binding:"max=3"`
+	OriginalCategoryName          string          `json:"originalCategoryName" binding:"max=256"`
+	CounterpartyId                int64           `json:"counterpartyId,string,omitempty"`
+	OriginalCounterpartyName      string          `json:"originalCounterpartyName" binding:"max=256"`
+	Comment                       string          `json:"comment" binding:"max=255"`
+}
+
+// ImportTransactionCategorizationSuggestion represents a view-object of categorization suggestion of an imported transaction
+type ImportTransactionCategorizationSuggestion struct {
+	Index          int      `json:"index"`
+	CategoryId     int64    `json:"categoryId,string,omitempty"`
+	CounterpartyId int64    `json:"counterpartyId,string,omitempty"`
+	TagIds         []string `json:"tagIds,omitempty"`
+	Confidence     float64  `json:"confidence"`
+}
+
+// ImportTransactionCategorizationResult represents the result of imported transaction categorization
+type ImportTransactionCategorizationResult struct {
+	Suggestions []*ImportTransactionCategorizationResultItem `json:"suggestions" jsonschema_description:"List of categorization suggestions of the transactions"`
+}
+
+// ImportTransactionCategorizationResultItem represents the categorization result of an imported transaction
+type ImportTransactionCategorizationResultItem struct {
+	Index            int      `json:"index" jsonschema_description:"Index of the transaction in user input"`
+	CategoryName     string   `json:"category,omitempty" jsonschema_description:"Category name for the transaction"`
+	CounterpartyName string   `json:"counterparty,omitempty" jsonschema_description:"Counterparty name for the transaction"`
+	TagNames         []string `json:"tags,omitempty" jsonschema_description:"List of tags associated with the transaction (maximum 10 tags allowed)"`
+	Confidence       float64  `json:"confidence" jsonschema:"minimum=0,maximum=1" jsonschema_description:"Confidence of the suggestion (from 0 to 1)"`
+}
+
+// TransactionNaturalLanguageQueryRequest represents all parameters of natural language transaction query request
+type TransactionNaturalLanguageQueryRequest struct {
+	Query string `json:"query" binding:"required,notBlank,max=500"`
+	Count int32  `json:"count" binding:"required,min=1,max=50"`
+}
+
+// TransactionNaturalLanguageQueryFilter represents a view-object of transaction filter parsed from natural language query, the fields are the same as the parameters of transaction list request
+type TransactionNaturalLanguageQueryFilter struct {
+	Type           TransactionType `json:"type"`
+	MinTime        int64           `json:"minTime,omitempty"`
+	MaxTime        int64           `json:"maxTime,omitempty"`
+	CategoryIds    []string        `json:"categoryIds,omitempty"`
+	AccountIds     []string        `json:"accountIds,omitempty"`
+	TagFilter      string          `json:"tagFilter,omitempty"`
+	AmountFilter   string          `json:"amountFilter,omitempty"`
+	Keyword        string          `json:"keyword,omitempty"`
+	CounterpartyId int64           `json:"counterpartyId,string,omitempty"`
+	UnmatchedNames []string        `json:"unmatchedNames,omitempty"`
+}
+
+// TransactionNaturalLanguageQueryResponse represents a response of natural language transaction query which contains the parsed filter and the transactions
+type TransactionNaturalLanguageQueryResponse struct {
+	Filter             *TransactionNaturalLanguageQueryFilter `json:"filter"`
+	Items              TransactionInfoResponseSlice           `json:"items"`
+	NextTimeSequenceId *int64                                 `json:"nextTimeSequenceId,string"`
+}
+
+// TransactionNaturalLanguageQueryResult represents the result of converting natural language query to transaction filter
+type TransactionNaturalLanguageQueryResult struct {
+	Type             string                                       `json:"type,omitempty" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
+	StartTime        string                                       `json:"start_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"Start time of the time range in long date time format (YYYY-MM-DD HH:mm:ss, e.g. 2023-01-01 00:00:00)"`
+	EndTime          string                                       `json:"end_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"End time of the time range in long date time format (YYYY-MM-DD HH:mm:ss, e.g. 2023-01-31 23:59:59)"`
+	CategoryNames    []string                                     `json:"categories,omitempty" jsonschema_description:"List of category names"`
+	AccountNames     []string                                     `json:"accounts,omitempty" jsonschema_description:"List of account names"`
+	TagNames         []string                                     `json:"tags,omitempty" jsonschema_description:"List of tag names"`
+	TagFilterType    string                                       `json:"tag_filter_type,omitempty" jsonschema:"enum=has_any,enum=has_all,enum=not_has_any,enum=not_has_all" jsonschema_description:"How the tags are matched (has_any, has_all, not_has_any, not_has_all)"`
+	AmountFilter     *TransactionNaturalLanguageQueryAmountFilter `json:"amount_filter,omitempty" jsonschema_description:"Amount filter"`
+	Keyword          string                                       `json:"keyword,omitempty" jsonschema_description:"Keyword in transaction description"`
+	CounterpartyName string                                       `json:"counterparty,omitempty" jsonschema_description:"Counterparty name"`
+}
+
+// TransactionNaturalLanguageQueryAmountFilter represents the amount filter of converting natural language query
+type TransactionNaturalLanguageQueryAmountFilter struct {
+	Operator string `json:"operator" jsonschema:"enum=gt,enum=lt,enum=eq,enum=ne,enum=bt,enum=nb" jsonschema_description:"Operator (gt: greater than, lt: less than, eq: equal to, ne: not equal to, bt: between, nb: not between)"`
+	Amount   string `json:"amount" jsonschema_description:"Amount"`
+	Amount2  string `json:"amount2,omitempty" jsonschema_description:"Upper bound amount for between and not between operators"`
+}
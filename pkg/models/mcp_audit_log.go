@@ -0,0 +1,94 @@
+package models
+
+import "encoding/json"
+
+// MCPAuditAction represents the action of a change made by MCP tool
+type MCPAuditAction byte
+
+// MCP audit actions
+const (
+	MCP_AUDIT_ACTION_CREATE  MCPAuditAction = 1
+	MCP_AUDIT_ACTION_MODIFY  MCPAuditAction = 2
+	MCP_AUDIT_ACTION_DELETE  MCPAuditAction = 3
+	MCP_AUDIT_ACTION_CONFIRM MCPAuditAction = 4
+)
+
+// String returns a textual representation of the MCP audit action
+func (a MCPAuditAction) String() string {
+	switch a {
+	case MCP_AUDIT_ACTION_CREATE:
+		return "create"
+	case MCP_AUDIT_ACTION_MODIFY:
+		return "modify"
+	case MCP_AUDIT_ACTION_DELETE:
+		return "delete"
+	case MCP_AUDIT_ACTION_CONFIRM:
+		return "confirm"
+	default:
+		return ""
+	}
+}
+
+// MCPAuditLog represents a change made by MCP tool stored in database
+type MCPAuditLog struct {
+	AuditId         int64          `xorm:"PK"`
+	Uid             int64          `xorm:"INDEX(IDX_mcp_audit_log_uid_created_time) NOT NULL"`
+	TokenId         string         `xorm:"VARCHAR(64) NOT NULL DEFAULT ''"`
+	ToolName        string         `xorm:"VARCHAR(64) NOT NULL"`
+	Action          MCPAuditAction `xorm:"TINYINT NOT NULL"`
+	EntityType      string         `xorm:"VARCHAR(32) NOT NULL"`
+	EntityId        int64          `xorm:"NOT NULL DEFAULT 0"`
+	Changes         string         `xorm:"BLOB"`
+	ClientIp        string         `xorm:"VARCHAR(39)"`
+	CreatedUnixTime int64          `xorm:"INDEX(IDX_mcp_audit_log_uid_created_time)"`
+}
+
+// MCPAuditFieldChange represents the old and new value of a field changed by MCP tool
+type MCPAuditFieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+}
+
+// MCPAuditLogListRequest represents all parameters of MCP audit log listing request
+type MCPAuditLogListRequest struct {
+	TokenId string `form:"tokenId"`
+	Page    int32  `form:"page" binding:"omitempty,min=1"`
+	Count   int32  `form:"count" binding:"omitempty,min=1,max=100"`
+}
+
+// MCPAuditLogInfoResponse represents a view-object of MCP audit log
+type MCPAuditLogInfoResponse struct {
+	Id          int64                  `json:"id,string"`
+	TokenId     string                 `json:"tokenId"`
+	ToolName    string                 `json:"toolName"`
+	Action      string                 `json:"action"`
+	EntityType  string                 `json:"entityType"`
+	EntityId    int64                  `json:"entityId,string"`
+	Changes     []*MCPAuditFieldChange `json:"changes"`
+	ClientIp    string                 `json:"clientIp"`
+	CreatedTime int64                  `json:"createdTime"`
+}
+
+// ToMCPAuditLogInfoResponse returns a view-object according to database model
+func (l *MCPAuditLog) ToMCPAuditLogInfoResponse() *MCPAuditLogInfoResponse {
+	changes := make([]*MCPAuditFieldChange, 0)
+
+	if l.Changes != "" {
+		if err := json.Unmarshal([]byte(l.Changes), &changes); err != nil {
+			changes = make([]*MCPAuditFieldChange, 0)
+		}
+	}
+
+	return &MCPAuditLogInfoResponse{
+		Id:          l.AuditId,
+		TokenId:     l.TokenId,
+		ToolName:    l.ToolName,
+		Action:      l.Action.String(),
+		EntityType:  l.EntityType,
+		EntityId:    l.EntityId,
+		Changes:     changes,
+		ClientIp:    l.ClientIp,
+		CreatedTime: l.CreatedUnixTime,
+	}
+}
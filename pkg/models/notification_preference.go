@@ -0,0 +1,65 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/core"
+
+// Default notification preference values
+const (
+	DefaultWeeklyDigestDayOfWeek = core.WEEKDAY_MONDAY
+	DefaultReminderDaysBefore    = 3
+)
+
+// NotificationPreference represents the email notification preference of user stored in database
+type NotificationPreference struct {
+	Uid                      int64        `xorm:"PK"`
+	WeeklyDigestEnabled      bool         `xorm:"NOT NULL"`
+	WeeklyDigestDayOfWeek    core.WeekDay `xorm:"TINYINT NOT NULL DEFAULT 1"`
+	DueRemindersEnabled      bool         `xorm:"NOT NULL"`
+	ReminderDaysBefore       int32        `xorm:"NOT NULL DEFAULT 3"`
+	TimezoneUtcOffset        int16        `xorm:"NOT NULL DEFAULT 0"`
+	LastWeeklyDigestUnixTime int64        `xorm:"NOT NULL DEFAULT 0"`
+	LastDueReminderUnixTime  int64        `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnixTime          int64
+	UpdatedUnixTime          int64
+}
+
+// NotificationPreferenceSaveRequest represents all parameters of notification preference saving request
+type NotificationPreferenceSaveRequest struct {
+	WeeklyDigestEnabled   bool         `json:"weeklyDigestEnabled"`
+	WeeklyDigestDayOfWeek core.WeekDay `json:"weeklyDigestDayOfWeek" binding:"min=0,max=6"`
+	DueRemindersEnabled   bool         `json:"dueRemindersEnabled"`
+	ReminderDaysBefore    int32        `json:"reminderDaysBefore" binding:"min=0,max=30"`
+	UtcOffset             int16        `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// NotificationPreferenceInfoResponse represents a view-object of notification preference
+type NotificationPreferenceInfoResponse struct {
+	WeeklyDigestEnabled   bool         `json:"weeklyDigestEnabled"`
+	WeeklyDigestDayOfWeek core.WeekDay `json:"weeklyDigestDayOfWeek"`
+	DueRemindersEnabled   bool         `json:"dueRemindersEnabled"`
+	ReminderDaysBefore    int32        `json:"reminderDaysBefore"`
+	UtcOffset             int16        `json:"utcOffset"`
+	LastWeeklyDigestTime  int64        `json:"lastWeeklyDigestTime,omitempty"`
+	LastDueReminderTime   int64        `json:"lastDueReminderTime,omitempty"`
+}
+
+// NewDefaultNotificationPreference returns the notification preference of user who has not saved it, all notifications are turned off
+func NewDefaultNotificationPreference(uid int64) *NotificationPreference {
+	return &NotificationPreference{
+		Uid:                   uid,
+		WeeklyDigestDayOfWeek: DefaultWeeklyDigestDayOfWeek,
+		ReminderDaysBefore:    DefaultReminderDaysBefore,
+	}
+}
+
+// ToNotificationPreferenceInfoResponse returns a view-object according to database model
+func (p *NotificationPreference) ToNotificationPreferenceInfoResponse() *NotificationPreferenceInfoResponse {
+	return &NotificationPreferenceInfoResponse{
+		WeeklyDigestEnabled:   p.WeeklyDigestEnabled,
+		WeeklyDigestDayOfWeek: p.WeeklyDigestDayOfWeek,
+		DueRemindersEnabled:   p.DueRemindersEnabled,
+		ReminderDaysBefore:    p.ReminderDaysBefore,
+		UtcOffset:             p.TimezoneUtcOffset,
+		LastWeeklyDigestTime:  p.LastWeeklyDigestUnixTime,
+		LastDueReminderTime:   p.LastDueReminderUnixTime,
+	}
+}
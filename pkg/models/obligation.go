@@ -15,6 +15,7 @@ const (
 	OBLIGATION_STATUS_ACTIVE   ObligationStatus = 1
 	OBLIGATION_STATUS_PARTIAL  ObligationStatus = 2
 	OBLIGATION_STATUS_PAID     ObligationStatus = 3
+	OBLIGATION_STATUS_OVERDUE  ObligationStatus = 4
 )
 
 // Obligation represents obligation data stored in database
@@ -36,6 +37,17 @@ type Obligation struct {
 	DeletedUnixTime int64
 }
 
+// GetObligationStatus returns the status of obligation according to its amount and paid amount
+func GetObligationStatus(amount int64, paidAmount int64) ObligationStatus {
+	if paidAmount <= 0 {
+		return OBLIGATION_STATUS_ACTIVE
+	} else if paidAmount < amount {
+		return OBLIGATION_STATUS_PARTIAL
+	}
+
+	return OBLIGATION_STATUS_PAID
+}
+
 type ObligationGetRequest struct {
 	Id int64 `form:"id,string" binding:"required,min=1"`
 }
@@ -0,0 +1,86 @@
+package models
+
+// ObligationPayment represents a part of transaction which settles an obligation, stored in database
+type ObligationPayment struct {
+	PaymentId       int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_obligation_payment_uid_deleted) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_obligation_payment_uid_deleted) NOT NULL"`
+	ObligationId    int64  `xorm:"INDEX NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX NOT NULL"`
+	PaymentDate     int64  `xorm:"NOT NULL DEFAULT 0"`
+	Amount          int64  `xorm:"NOT NULL DEFAULT 0"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// ObligationPaymentListByObligationRequest represents all parameters to list payments by obligation
+type ObligationPaymentListByObligationRequest struct {
+	ObligationId int64 `form:"obligationId,string" binding:"required,min=1"`
+}
+
+// ObligationPaymentDeleteRequest represents all parameters of obligation payment deleting request
+type ObligationPaymentDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// ObligationAllocation represents the amount of transaction allocated to an obligation
+type ObligationAllocation struct {
+	ObligationId int64 `json:"obligationId,string" binding:"required,min=1"`
+	Amount       int64 `json:"amount" binding:"required,min=1"`
+}
+
+// ObligationAllocateRequest represents all parameters of transaction allocating request,
+// the transaction is allocated to the open obligations of its counterparty by due date if no allocation is specified
+type ObligationAllocateRequest struct {
+	TransactionId int64                   `json:"transactionId,string" binding:"required,min=1"`
+	Allocations   []*ObligationAllocation `json:"allocations" binding:"omitempty,dive"`
+	Comment       string                  `json:"comment" binding:"max=255"`
+}
+
+// ObligationAllocateResponse represents a view-object of transaction allocating result
+type ObligationAllocateResponse struct {
+	Payments    []*ObligationPaymentInfoResponse `json:"payments"`
+	Obligations []*ObligationInfoResponse        `json:"obligations"`
+}
+
+// ObligationPaymentInfoResponse represents a view-object of obligation payment
+type ObligationPaymentInfoResponse struct {
+	Id            int64  `json:"id,string"`
+	ObligationId  int64  `json:"obligationId,string"`
+	TransactionId int64  `json:"transactionId,string"`
+	PaymentDate   int64  `json:"paymentDate"`
+	Amount        int64  `json:"amount"`
+	Comment       string `json:"comment"`
+}
+
+// ToObligationPaymentInfoResponse returns a view-object according to database model
+func (p *ObligationPayment) ToObligationPaymentInfoResponse() *ObligationPaymentInfoResponse {
+	return &ObligationPaymentInfoResponse{
+		Id:            p.PaymentId,
+		ObligationId:  p.ObligationId,
+		TransactionId: p.TransactionId,
+		PaymentDate:   p.PaymentDate,
+		Amount:        p.Amount,
+		Comment:       p.Comment,
+	}
+}
+
+// ObligationPaymentInfoResponseSlice represents the slice data structure of ObligationPaymentInfoResponse
+type ObligationPaymentInfoResponseSlice []*ObligationPaymentInfoResponse
+
+// Len returns the count of items
+func (s ObligationPaymentInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ObligationPaymentInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one (by payment date desc)
+func (s ObligationPaymentInfoResponseSlice) Less(i, j int) bool {
+	return s[i].PaymentDate > s[j].PaymentDate
+}
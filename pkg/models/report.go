@@ -11,6 +11,17 @@ const (
 	BalanceLabelInvestorDebt   = "Investor Debt"
 )
 
+// Profit & loss detail line labels
+const (
+	PnLLabelRevenue          = "Revenue"
+	PnLLabelCostOfGoods      = "Cost of Goods Sold"
+	PnLLabelOperatingExpense = "Operating Expenses"
+	PnLLabelDepreciation     = "Depreciation"
+	PnLLabelDisposalGainLoss = "Gain (Loss) on Disposal of Assets"
+	PnLLabelFinancialExpense = "Financial Expenses"
+	PnLLabelTaxExpense       = "Tax Expenses"
+)
+
 // Cash flow activity names
 const (
 	ActivityNameOperating = "Operating"
@@ -20,26 +31,38 @@ const (
 
 // Payment calendar item types
 const (
-	PaymentTypeReceivable = "Receivable"
-	PaymentTypePayable    = "Payable"
-	PaymentTypeTax        = "Tax"
-	PaymentTypePlanned    = "Planned"
+	PaymentTypeReceivable   = "Receivable"
+	PaymentTypePayable      = "Payable"
+	PaymentTypeTax          = "Tax"
+	PaymentTypeEstimatedTax = "Estimated Tax"
+	PaymentTypePlanned      = "Planned"
 )
 
 // ReportRequest represents a report request
 type ReportRequest struct {
-	CfoId     int64 `form:"cfoId,string"`
-	StartTime int64 `form:"startTime" binding:"required,min=1"`
-	EndTime   int64 `form:"endTime" binding:"required,min=1,gtfield=StartTime"`
+	CfoId     int64  `form:"cfoId,string"`
+	StartTime int64  `form:"startTime" binding:"required,min=1"`
+	EndTime   int64  `form:"endTime" binding:"required,min=1,gtfield=StartTime"`
+	Currency  string `form:"currency" binding:"omitempty,len=3"`
+}
+
+// ReportCurrencyAmount represents the part of a report amount in one original currency and its value in the reporting currency
+type ReportCurrencyAmount struct {
+	Currency       string `json:"currency"`
+	OriginalAmount int64  `json:"originalAmount"`
+	Rate           string `json:"rate,omitempty"`
+	Amount         int64  `json:"amount"`
+	RateMissing    bool   `json:"rateMissing,omitempty"`
 }
 
 // CashFlowActivityLine represents a line in cash flow report
 type CashFlowActivityLine struct {
-	CategoryId   int64  `json:"categoryId,string"`
-	CategoryName string `json:"categoryName"`
-	Income       int64  `json:"income"`
-	Expense      int64  `json:"expense"`
-	Net          int64  `json:"net"`
+	CategoryId   int64                   `json:"categoryId,string"`
+	CategoryName string                  `json:"categoryName"`
+	Income       int64                   `json:"income"`
+	Expense      int64                   `json:"expense"`
+	Net          int64                   `json:"net"`
+	Currencies   []*ReportCurrencyAmount `json:"currencies,omitempty"`
 }
 
 // CashFlowActivity represents an activity section in cash flow report
@@ -54,14 +77,18 @@ type CashFlowActivity struct {
 
 // CashFlowResponse represents the cash flow report response
 type CashFlowResponse struct {
-	Activities []*CashFlowActivity `json:"activities"`
-	TotalNet   int64               `json:"totalNet"`
+	Activities        []*CashFlowActivity     `json:"activities"`
+	TotalNet          int64                   `json:"totalNet"`
+	Currency          string                  `json:"currency,omitempty"`
+	CurrencySubtotals []*ReportCurrencyAmount `json:"currencySubtotals,omitempty"`
+	Warnings          []string                `json:"warnings,omitempty"`
 }
 
 // PnLLine represents a line in P&L report
 type PnLLine struct {
-	Label  string `json:"label"`
-	Amount int64  `json:"amount"`
+	Label      string                  `json:"label"`
+	Amount     int64                   `json:"amount"`
+	Currencies []*ReportCurrencyAmount `json:"currencies,omitempty"`
 }
 
 // PnLResponse represents the P&L report response
@@ -72,45 +99,124 @@ type PnLResponse struct {
 	OperatingExpense int64      `json:"operatingExpense"`
 	Depreciation     int64      `json:"depreciation"`
 	OperatingProfit  int64      `json:"operatingProfit"`
+	DisposalGainLoss int64      `json:"disposalGainLoss"`
 	FinancialExpense int64      `json:"financialExpense"`
 	TaxExpense       int64      `json:"taxExpense"`
 	NetProfit        int64      `json:"netProfit"`
 	Details          []*PnLLine `json:"details"`
+	Currency         string     `json:"currency,omitempty"`
 	Warnings         []string   `json:"warnings,omitempty"`
 }
 
 // BalanceSection represents a section in balance sheet
 type BalanceLine struct {
-	Label  string `json:"label"`
-	Amount int64  `json:"amount"`
+	Label      string                  `json:"label"`
+	Amount     int64                   `json:"amount"`
+	Currencies []*ReportCurrencyAmount `json:"currencies,omitempty"`
 }
 
 // BalanceResponse represents the balance sheet report response
 type BalanceResponse struct {
-	AssetLines      []*BalanceLine `json:"assetLines"`
-	TotalAssets     int64          `json:"totalAssets"`
-	LiabilityLines  []*BalanceLine `json:"liabilityLines"`
-	TotalLiability  int64          `json:"totalLiability"`
-	Equity          int64          `json:"equity"`
-	Warnings        []string       `json:"warnings,omitempty"`
+	AssetLines        []*BalanceLine          `json:"assetLines"`
+	TotalAssets       int64                   `json:"totalAssets"`
+	LiabilityLines    []*BalanceLine          `json:"liabilityLines"`
+	TotalLiability    int64                   `json:"totalLiability"`
+	Equity            int64                   `json:"equity"`
+	AsOf              int64                   `json:"asOf,omitempty"`
+	Currency          string                  `json:"currency,omitempty"`
+	CurrencySubtotals []*ReportCurrencyAmount `json:"currencySubtotals,omitempty"`
+	Warnings          []string                `json:"warnings,omitempty"`
+}
+
+// ComparativeBalanceLine represents a line of comparative balance sheet with the amounts on two dates
+type ComparativeBalanceLine struct {
+	Label         string `json:"label"`
+	Amount        int64  `json:"amount"`
+	CompareAmount int64  `json:"compareAmount"`
+	Delta         int64  `json:"delta"`
+}
+
+// ComparativeBalanceResponse represents the balance sheets on two dates side by side with deltas
+type ComparativeBalanceResponse struct {
+	Current             *BalanceResponse          `json:"current"`
+	Compare             *BalanceResponse          `json:"compare"`
+	AssetLines          []*ComparativeBalanceLine `json:"assetLines"`
+	LiabilityLines      []*ComparativeBalanceLine `json:"liabilityLines"`
+	TotalAssetsDelta    int64                     `json:"totalAssetsDelta"`
+	TotalLiabilityDelta int64                     `json:"totalLiabilityDelta"`
+	EquityDelta         int64                     `json:"equityDelta"`
 }
 
 // PaymentCalendarItem represents a payment calendar entry
 type PaymentCalendarItem struct {
-	Date        int64  `json:"date"`
-	Type        string `json:"type"`
-	Amount      int64  `json:"amount"`
-	Description string `json:"description"`
-	Currency    string `json:"currency"`
+	Date            int64  `json:"date"`
+	Type            string `json:"type"`
+	Amount          int64  `json:"amount"`
+	Description     string `json:"description"`
+	Currency        string `json:"currency"`
+	ConvertedAmount int64  `json:"convertedAmount"`
+	Rate            string `json:"rate,omitempty"`
+	RateMissing     bool   `json:"rateMissing,omitempty"`
+	Overdue         bool   `json:"overdue,omitempty"`
+	PaidLate        bool   `json:"paidLate,omitempty"`
 }
 
-// PaymentCalendarResponse represents the payment calendar response
+// PaymentCalendarResponse represents the payment calendar response, the settled items are the paid obligations and tax records due in range
 type PaymentCalendarResponse struct {
-	Items    []*PaymentCalendarItem `json:"items"`
-	Warnings []string               `json:"warnings,omitempty"`
+	Items             []*PaymentCalendarItem  `json:"items"`
+	SettledItems      []*PaymentCalendarItem  `json:"settledItems,omitempty"`
+	Currency          string                  `json:"currency,omitempty"`
+	CurrencySubtotals []*ReportCurrencyAmount `json:"currencySubtotals,omitempty"`
+	Warnings          []string                `json:"warnings,omitempty"`
 }
 
-// BalanceReportRequest represents a balance sheet report request (no time range needed)
+// BalanceReportRequest represents a balance sheet report request, the balance sheet is as of now if as-of time is not set,
+// and the comparative balance sheet is returned if compare as-of time is set
 type BalanceReportRequest struct {
-	CfoId int64 `form:"cfoId,string"`
+	CfoId       int64  `form:"cfoId,string"`
+	AsOf        int64  `form:"asOf" binding:"min=0"`
+	CompareAsOf int64  `form:"compareAsOf" binding:"min=0"`
+	Currency    string `form:"currency" binding:"omitempty,len=3"`
+}
+
+// AgingReportRequest represents an aging report request, days overdue are counted as of now if as-of time is not set
+type AgingReportRequest struct {
+	CfoId    int64  `form:"cfoId,string"`
+	AsOf     int64  `form:"asOf" binding:"min=0"`
+	Currency string `form:"currency" binding:"omitempty,len=3"`
+}
+
+// AgingBuckets represents the open amounts split by days overdue
+type AgingBuckets struct {
+	Current    int64 `json:"current"`
+	Days1To30  int64 `json:"days1To30"`
+	Days31To60 int64 `json:"days31To60"`
+	Days61To90 int64 `json:"days61To90"`
+	Over90Days int64 `json:"over90Days"`
+	Total      int64 `json:"total"`
+}
+
+// AgingLine represents the open amounts of a counterparty in aging report, and how many paid obligations of the counterparty were paid on time or late
+type AgingLine struct {
+	CounterpartyId   int64         `json:"counterpartyId,string"`
+	CounterpartyName string        `json:"counterpartyName"`
+	ObligationCount  int           `json:"obligationCount"`
+	Buckets          *AgingBuckets `json:"buckets"`
+	PaidOnTimeCount  int           `json:"paidOnTimeCount"`
+	PaidLateCount    int           `json:"paidLateCount"`
+}
+
+// AgingSection represents the receivables or payables part of aging report
+type AgingSection struct {
+	Lines  []*AgingLine  `json:"lines"`
+	Totals *AgingBuckets `json:"totals"`
+}
+
+// AgingReportResponse represents the accounts receivable and payable aging report response
+type AgingReportResponse struct {
+	AsOf        int64         `json:"asOf"`
+	Receivables *AgingSection `json:"receivables"`
+	Payables    *AgingSection `json:"payables"`
+	Currency    string        `json:"currency,omitempty"`
+	Warnings    []string      `json:"warnings,omitempty"`
 }
@@ -0,0 +1,52 @@
+package models
+
+// StatusHistoryEntityType represents the type of the entity whose status changes are recorded
+type StatusHistoryEntityType byte
+
+// Status history entity types
+const (
+	STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD StatusHistoryEntityType = 1
+	STATUS_HISTORY_ENTITY_TYPE_OBLIGATION StatusHistoryEntityType = 2
+)
+
+// StatusHistory represents a status change of tax record or obligation stored in database,
+// the status changes of each entity are numbered by sequence in the order they happened
+type StatusHistory struct {
+	Uid             int64                   `xorm:"PK"`
+	EntityType      StatusHistoryEntityType `xorm:"PK"`
+	EntityId        int64                   `xorm:"PK"`
+	Sequence        int32                   `xorm:"PK"`
+	OldStatus       byte                    `xorm:"NOT NULL"`
+	NewStatus       byte                    `xorm:"NOT NULL"`
+	DueDate         int64                   `xorm:"NOT NULL DEFAULT 0"`
+	PaidAmount      int64                   `xorm:"NOT NULL DEFAULT 0"`
+	Automatic       bool                    `xorm:"NOT NULL"`
+	ChangedUnixTime int64                   `xorm:"NOT NULL"`
+}
+
+// StatusHistoryListRequest represents all parameters of the status history listing request of tax record or obligation
+type StatusHistoryListRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// StatusHistoryInfoResponse represents a view-object of status change
+type StatusHistoryInfoResponse struct {
+	OldStatus   byte  `json:"oldStatus"`
+	NewStatus   byte  `json:"newStatus"`
+	DueDate     int64 `json:"dueDate"`
+	PaidAmount  int64 `json:"paidAmount"`
+	Automatic   bool  `json:"automatic"`
+	ChangedTime int64 `json:"changedTime"`
+}
+
+// ToStatusHistoryInfoResponse returns a view-object according to database model
+func (h *StatusHistory) ToStatusHistoryInfoResponse() *StatusHistoryInfoResponse {
+	return &StatusHistoryInfoResponse{
+		OldStatus:   h.OldStatus,
+		NewStatus:   h.NewStatus,
+		DueDate:     h.DueDate,
+		PaidAmount:  h.PaidAmount,
+		Automatic:   h.Automatic,
+		ChangedTime: h.ChangedUnixTime,
+	}
+}
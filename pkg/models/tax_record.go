@@ -40,6 +40,13 @@ type TaxRecord struct {
 	DeletedUnixTime int64
 }
 
+// TaxRecordListRequest represents all parameters of tax record listing request,
+// the stored tax amounts are compared with the amounts computed by tax regimes if verify is set
+type TaxRecordListRequest struct {
+	Verify    bool  `form:"verify"`
+	UtcOffset int16 `form:"utcOffset" binding:"min=-720,max=840"`
+}
+
 type TaxRecordGetRequest struct {
 	Id int64 `form:"id,string" binding:"required,min=1"`
 }
@@ -78,18 +85,20 @@ type TaxRecordDeleteRequest struct {
 }
 
 type TaxRecordInfoResponse struct {
-	Id            int64     `json:"id,string"`
-	CfoId         int64     `json:"cfoId,string"`
-	TaxType       TaxType   `json:"taxType"`
-	PeriodYear    int32     `json:"periodYear"`
-	PeriodQuarter int32     `json:"periodQuarter"`
-	TaxableIncome int64     `json:"taxableIncome"`
-	TaxAmount     int64     `json:"taxAmount"`
-	PaidAmount    int64     `json:"paidAmount"`
-	DueDate       int64     `json:"dueDate"`
-	Status        TaxStatus `json:"status"`
-	Comment       string    `json:"comment"`
-	Currency      string    `json:"currency"`
+	Id                int64     `json:"id,string"`
+	CfoId             int64     `json:"cfoId,string"`
+	TaxType           TaxType   `json:"taxType"`
+	PeriodYear        int32     `json:"periodYear"`
+	PeriodQuarter     int32     `json:"periodQuarter"`
+	TaxableIncome     int64     `json:"taxableIncome"`
+	TaxAmount         int64     `json:"taxAmount"`
+	PaidAmount        int64     `json:"paidAmount"`
+	DueDate           int64     `json:"dueDate"`
+	Status            TaxStatus `json:"status"`
+	Comment           string    `json:"comment"`
+	Currency          string    `json:"currency"`
+	ComputedTaxAmount *int64    `json:"computedTaxAmount,omitempty"`
+	Diverged          bool      `json:"diverged,omitempty"`
 }
 
 func (t *TaxRecord) ToTaxRecordInfoResponse() *TaxRecordInfoResponse {
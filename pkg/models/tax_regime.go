@@ -0,0 +1,215 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TaxRegimeType represents the type of tax regime
+type TaxRegimeType byte
+
+// Tax regime types
+const (
+	TAX_REGIME_TYPE_SIMPLIFIED_INCOME                TaxRegimeType = 1
+	TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES TaxRegimeType = 2
+	TAX_REGIME_TYPE_VAT                              TaxRegimeType = 3
+	TAX_REGIME_TYPE_PROPERTY                         TaxRegimeType = 4
+)
+
+// TaxType returns the type of the tax records computed by the tax regime
+func (t TaxRegimeType) TaxType() TaxType {
+	switch t {
+	case TAX_REGIME_TYPE_SIMPLIFIED_INCOME, TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES:
+		return TAX_TYPE_INCOME
+	case TAX_REGIME_TYPE_VAT:
+		return TAX_TYPE_VAT
+	case TAX_REGIME_TYPE_PROPERTY:
+		return TAX_TYPE_PROPERTY
+	default:
+		return TAX_TYPE_OTHER
+	}
+}
+
+// String returns a textual representation of the tax regime type
+func (t TaxRegimeType) String() string {
+	switch t {
+	case TAX_REGIME_TYPE_SIMPLIFIED_INCOME:
+		return "Simplified tax on income"
+	case TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES:
+		return "Simplified tax on income minus expenses"
+	case TAX_REGIME_TYPE_VAT:
+		return "VAT"
+	case TAX_REGIME_TYPE_PROPERTY:
+		return "Property tax"
+	default:
+		return "Unknown"
+	}
+}
+
+// TaxRegimePeriodType represents the tax period of tax regime
+type TaxRegimePeriodType byte
+
+// Tax regime period types
+const (
+	TAX_REGIME_PERIOD_TYPE_QUARTERLY TaxRegimePeriodType = 1
+	TAX_REGIME_PERIOD_TYPE_ANNUAL    TaxRegimePeriodType = 2
+)
+
+// TaxRateBasisPointsPerUnit is the number of basis points of the 100% tax rate, all tax rates are stored in basis points
+const TaxRateBasisPointsPerUnit = 10000
+
+// Tax calculation breakdown line labels
+const (
+	TaxCalculationLabelIncome                = "Income"
+	TaxCalculationLabelExpenses              = "Expenses"
+	TaxCalculationLabelTaxBase               = "Tax Base"
+	TaxCalculationLabelTaxAtRate             = "Tax at Rate"
+	TaxCalculationLabelMinimumTax            = "Minimum Tax"
+	TaxCalculationLabelAdvancePayments       = "Advance Payments"
+	TaxCalculationLabelOutputVat             = "Output VAT"
+	TaxCalculationLabelInputVat              = "Input VAT"
+	TaxCalculationLabelVatRefundable         = "VAT Refundable"
+	TaxCalculationLabelAverageAssetBookValue = "Average Book Value of Assets"
+	TaxCalculationLabelTaxAmount             = "Tax Amount"
+)
+
+// TaxRegime represents the tax regime of user or CFO stored in database, each user or CFO has at most one regime of each tax type.
+// The tax regime of CFO computes the taxes of the transactions and assets of that CFO, and the tax regime without CFO computes
+// the taxes of all transactions and assets of user.
+type TaxRegime struct {
+	Uid                  int64               `xorm:"PK"`
+	CfoId                int64               `xorm:"PK"`
+	TaxType              TaxType             `xorm:"PK"`
+	RegimeType           TaxRegimeType       `xorm:"NOT NULL"`
+	PeriodType           TaxRegimePeriodType `xorm:"NOT NULL DEFAULT 1"`
+	Rate                 int32               `xorm:"NOT NULL DEFAULT 0"`
+	MinimumTaxRate       int32               `xorm:"NOT NULL DEFAULT 0"`
+	DueMonthsAfterPeriod int32               `xorm:"NOT NULL DEFAULT 1"`
+	DueDayOfMonth        int32               `xorm:"NOT NULL DEFAULT 28"`
+	ExcludedCategoryIds  string              `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	Currency             string              `xorm:"VARCHAR(3) NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+}
+
+// TaxRegimeSaveRequest represents all parameters of tax regime creation or modification request
+type TaxRegimeSaveRequest struct {
+	CfoId                int64               `json:"cfoId,string"`
+	RegimeType           TaxRegimeType       `json:"regimeType" binding:"required,min=1,max=4"`
+	PeriodType           TaxRegimePeriodType `json:"periodType" binding:"required,min=1,max=2"`
+	Rate                 int32               `json:"rate" binding:"required,min=1,max=10000"`
+	MinimumTaxRate       int32               `json:"minimumTaxRate" binding:"min=0,max=10000"`
+	DueMonthsAfterPeriod int32               `json:"dueMonthsAfterPeriod" binding:"min=0,max=12"`
+	DueDayOfMonth        int32               `json:"dueDayOfMonth" binding:"required,min=1,max=31"`
+	ExcludedCategoryIds  []string            `json:"excludedCategoryIds" binding:"max=10"`
+	Currency             string              `json:"currency" binding:"required,len=3,validCurrency"`
+}
+
+// TaxRegimeDeleteRequest represents all parameters of tax regime deleting request
+type TaxRegimeDeleteRequest struct {
+	CfoId   int64   `json:"cfoId,string"`
+	TaxType TaxType `json:"taxType" binding:"required,min=1,max=3"`
+}
+
+// TaxRegimeInfoResponse represents a view-object of tax regime
+type TaxRegimeInfoResponse struct {
+	CfoId                int64               `json:"cfoId,string"`
+	TaxType              TaxType             `json:"taxType"`
+	RegimeType           TaxRegimeType       `json:"regimeType"`
+	PeriodType           TaxRegimePeriodType `json:"periodType"`
+	Rate                 int32               `json:"rate"`
+	MinimumTaxRate       int32               `json:"minimumTaxRate"`
+	DueMonthsAfterPeriod int32               `json:"dueMonthsAfterPeriod"`
+	DueDayOfMonth        int32               `json:"dueDayOfMonth"`
+	ExcludedCategoryIds  []string            `json:"excludedCategoryIds"`
+	Currency             string              `json:"currency"`
+}
+
+// TaxCalculationRequest represents all parameters of tax calculation request,
+// the quarter must be 1 to 4 for quarterly tax regime and 0 for annual tax regime
+type TaxCalculationRequest struct {
+	CfoId         int64   `form:"cfoId,string"`
+	TaxType       TaxType `form:"taxType" binding:"required,min=1,max=3"`
+	PeriodYear    int32   `form:"periodYear" binding:"required,min=1970,max=9999"`
+	PeriodQuarter int32   `form:"periodQuarter" binding:"min=0,max=4"`
+	UtcOffset     int16   `form:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// TaxRecordGenerateRequest represents all parameters of the request which generates tax record from tax regime,
+// the quarter must be 1 to 4 for quarterly tax regime and 0 for annual tax regime
+type TaxRecordGenerateRequest struct {
+	CfoId         int64   `json:"cfoId,string"`
+	TaxType       TaxType `json:"taxType" binding:"required,min=1,max=3"`
+	PeriodYear    int32   `json:"periodYear" binding:"required,min=1970,max=9999"`
+	PeriodQuarter int32   `json:"periodQuarter" binding:"min=0,max=4"`
+	UtcOffset     int16   `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// TaxCalculationLine represents a line of tax calculation breakdown
+type TaxCalculationLine struct {
+	Label  string `json:"label"`
+	Amount int64  `json:"amount"`
+}
+
+// TaxCalculationResponse represents the tax of a period computed by tax regime from transactions and assets
+type TaxCalculationResponse struct {
+	CfoId           int64                 `json:"cfoId,string"`
+	TaxType         TaxType               `json:"taxType"`
+	RegimeType      TaxRegimeType         `json:"regimeType"`
+	PeriodYear      int32                 `json:"periodYear"`
+	PeriodQuarter   int32                 `json:"periodQuarter"`
+	StartTime       int64                 `json:"startTime"`
+	EndTime         int64                 `json:"endTime"`
+	DueDate         int64                 `json:"dueDate"`
+	Rate            int32                 `json:"rate"`
+	TaxableIncome   int64                 `json:"taxableIncome"`
+	TaxAmount       int64                 `json:"taxAmount"`
+	Currency        string                `json:"currency"`
+	Lines           []*TaxCalculationLine `json:"lines"`
+	TaxRecordId     int64                 `json:"taxRecordId,string,omitempty"`
+	StoredTaxAmount int64                 `json:"storedTaxAmount,omitempty"`
+	Diverged        bool                  `json:"diverged,omitempty"`
+	Warnings        []string              `json:"warnings,omitempty"`
+}
+
+// TaxRecordGenerateResponse represents the tax record generated from tax regime and its calculation
+type TaxRecordGenerateResponse struct {
+	TaxRecord   *TaxRecordInfoResponse  `json:"taxRecord"`
+	Calculation *TaxCalculationResponse `json:"calculation"`
+}
+
+// GetExcludedCategoryIds returns the ids of the categories whose transactions are excluded from tax calculation
+func (t *TaxRegime) GetExcludedCategoryIds() []int64 {
+	categoryIds := make([]string, 0)
+
+	if t.ExcludedCategoryIds != "" {
+		categoryIds = strings.Split(t.ExcludedCategoryIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(categoryIds)
+
+	return result
+}
+
+// ToTaxRegimeInfoResponse returns a view-object according to database model
+func (t *TaxRegime) ToTaxRegimeInfoResponse() *TaxRegimeInfoResponse {
+	excludedCategoryIds := make([]string, 0)
+
+	if t.ExcludedCategoryIds != "" {
+		excludedCategoryIds = strings.Split(t.ExcludedCategoryIds, ",")
+	}
+
+	return &TaxRegimeInfoResponse{
+		CfoId:                t.CfoId,
+		TaxType:              t.TaxType,
+		RegimeType:           t.RegimeType,
+		PeriodType:           t.PeriodType,
+		Rate:                 t.Rate,
+		MinimumTaxRate:       t.MinimumTaxRate,
+		DueMonthsAfterPeriod: t.DueMonthsAfterPeriod,
+		DueDayOfMonth:        t.DueDayOfMonth,
+		ExcludedCategoryIds:  excludedCategoryIds,
+		Currency:             t.Currency,
+	}
+}
@@ -236,6 +236,7 @@ type TransactionCountRequest struct {
 	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword      string          `form:"keyword"`
 	CounterpartyId int64           `form:"counterparty_id"`
+	TransactionAdvancedFilterRequest
 	MaxTime      int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime      int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
 }
@@ -249,6 +250,7 @@ type TransactionListByMaxTimeRequest struct {
 	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword      string          `form:"keyword"`
 	CounterpartyId int64           `form:"counterparty_id"`
+	TransactionAdvancedFilterRequest
 	MaxTime      int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime      int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
 	Page         int32           `form:"page" binding:"min=0"`
@@ -271,6 +273,7 @@ type TransactionListInMonthByPageRequest struct {
 	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword      string          `form:"keyword"`
 	CounterpartyId int64           `form:"counterparty_id"`
+	TransactionAdvancedFilterRequest
 	WithPictures bool            `form:"with_pictures"`
 	TrimAccount  bool            `form:"trim_account"`
 	TrimCategory bool            `form:"trim_category"`
@@ -286,6 +289,7 @@ type TransactionAllListRequest struct {
 	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword      string          `form:"keyword"`
 	CounterpartyId int64           `form:"counterparty_id"`
+	TransactionAdvancedFilterRequest
 	StartTime    int64           `form:"start_time" binding:"min=0"`
 	EndTime      int64           `form:"end_time" binding:"min=0"`
 	WithPictures bool            `form:"with_pictures"`
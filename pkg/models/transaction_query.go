@@ -1,5 +1,41 @@
 package models
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// TransactionQueryConditionGroupMaxCount is the maximum count of condition groups in a transaction query
+const TransactionQueryConditionGroupMaxCount = 10
+
+// TransactionPlannedFilterType represents the filter type of transaction planned state
+type TransactionPlannedFilterType byte
+
+// Transaction planned filter types
+const (
+	TRANSACTION_PLANNED_FILTER_TYPE_ALL       TransactionPlannedFilterType = 0
+	TRANSACTION_PLANNED_FILTER_TYPE_PLANNED   TransactionPlannedFilterType = 1
+	TRANSACTION_PLANNED_FILTER_TYPE_CONFIRMED TransactionPlannedFilterType = 2
+)
+
+// TransactionCommentFilterType represents the match type of transaction comment filter
+type TransactionCommentFilterType string
+
+// Transaction comment filter types
+const (
+	TRANSACTION_COMMENT_FILTER_TYPE_CONTAINS     TransactionCommentFilterType = "ct"
+	TRANSACTION_COMMENT_FILTER_TYPE_NOT_CONTAINS TransactionCommentFilterType = "nc"
+	TRANSACTION_COMMENT_FILTER_TYPE_EQUALS       TransactionCommentFilterType = "eq"
+	TRANSACTION_COMMENT_FILTER_TYPE_NOT_EQUALS   TransactionCommentFilterType = "ne"
+	TRANSACTION_COMMENT_FILTER_TYPE_STARTS_WITH  TransactionCommentFilterType = "sw"
+	TRANSACTION_COMMENT_FILTER_TYPE_ENDS_WITH    TransactionCommentFilterType = "ew"
+	TRANSACTION_COMMENT_FILTER_TYPE_EMPTY        TransactionCommentFilterType = "em"
+	TRANSACTION_COMMENT_FILTER_TYPE_NOT_EMPTY    TransactionCommentFilterType = "nm"
+)
+
 // TransactionQueryParams represents common query parameters for transaction listing and counting
 type TransactionQueryParams struct {
 	Uid                int64
@@ -13,8 +49,180 @@ type TransactionQueryParams struct {
 	AmountFilter       string
 	Keyword            string
 	CounterpartyId     int64
+	CfoId              int64
+	PlannedFilter      TransactionPlannedFilterType
+	SourceTemplateId   int64
+	GeoBounds          *TransactionGeoBoundsFilter
+	CommentFilter      *TransactionCommentFilter
+	ConditionGroups    []*TransactionQueryConditionGroup
+	SearchTerms        []*TransactionSearchTerm
 	Page               int32
 	Count              int32
 	NeedOneMoreItem    bool
 	NoDuplicated       bool
 }
+
+// TransactionQueryConditionGroup represents a group of conditions which are all required to match,
+// the transaction matches the condition groups of query when it matches any group
+type TransactionQueryConditionGroup struct {
+	TransactionType  TransactionType
+	CategoryIds      []int64
+	AccountIds       []int64
+	AmountFilter     string
+	CommentFilter    *TransactionCommentFilter
+	CounterpartyId   int64
+	CfoId            int64
+	PlannedFilter    TransactionPlannedFilterType
+	SourceTemplateId int64
+	GeoBounds        *TransactionGeoBoundsFilter
+}
+
+// TransactionGeoBoundsFilter represents the geographic bounding box of transaction location,
+// the bounding box crosses the 180th meridian when the minimum longitude is greater than the maximum longitude
+type TransactionGeoBoundsFilter struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// TransactionCommentFilter represents the filter of transaction comment
+type TransactionCommentFilter struct {
+	Type  TransactionCommentFilterType
+	Value string
+}
+
+// TransactionAdvancedFilterRequest represents the advanced filter parameters of transaction listing, counting and exporting request
+type TransactionAdvancedFilterRequest struct {
+	CfoId            int64                        `form:"cfo_id" binding:"min=0"`
+	PlannedFilter    TransactionPlannedFilterType `form:"planned_filter" binding:"min=0,max=2"`
+	SourceTemplateId int64                        `form:"source_template_id" binding:"min=0"`
+	GeoBounds        string                       `form:"geo_bounds" binding:"validGeoBoundsFilter"`
+	CommentFilter    string                       `form:"comment_filter" binding:"validCommentFilter"`
+	ConditionGroups  string                       `form:"condition_groups" binding:"validConditionGroups"`
+	Search           string                       `form:"search" binding:"max=255"`
+}
+
+// TransactionQueryConditionGroupRequest represents a condition group in the json of condition groups parameter
+type TransactionQueryConditionGroupRequest struct {
+	Type             TransactionType              `json:"type"`
+	CategoryIds      string                       `json:"categoryIds"`
+	AccountIds       string                       `json:"accountIds"`
+	AmountFilter     string                       `json:"amountFilter"`
+	CommentFilter    string                       `json:"commentFilter"`
+	CounterpartyId   int64                        `json:"counterpartyId,string"`
+	CfoId            int64                        `json:"cfoId,string"`
+	PlannedFilter    TransactionPlannedFilterType `json:"plannedFilter"`
+	SourceTemplateId int64                        `json:"sourceTemplateId,string"`
+	GeoBounds        string                       `json:"geoBounds"`
+}
+
+// ParseTransactionGeoBoundsFilter parses transaction geographic bounding box filter from string with the format "minLongitude,minLatitude,maxLongitude,maxLatitude"
+func ParseTransactionGeoBoundsFilter(geoBoundsStr string) (*TransactionGeoBoundsFilter, error) {
+	if geoBoundsStr == "" {
+		return nil, nil
+	}
+
+	items := strings.Split(geoBoundsStr, ",")
+
+	if len(items) != 4 {
+		return nil, errs.ErrTransactionGeoBoundsFilterInvalid
+	}
+
+	values := make([]float64, len(items))
+
+	for i := 0; i < len(items); i++ {
+		value, err := strconv.ParseFloat(strings.TrimSpace(items[i]), 64)
+
+		if err != nil {
+			return nil, errs.ErrTransactionGeoBoundsFilterInvalid
+		}
+
+		values[i] = value
+	}
+
+	geoBounds := &TransactionGeoBoundsFilter{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}
+
+	if geoBounds.MinLongitude < -180 || geoBounds.MinLongitude > 180 || geoBounds.MaxLongitude < -180 || geoBounds.MaxLongitude > 180 ||
+		geoBounds.MinLatitude < -90 || geoBounds.MinLatitude > 90 || geoBounds.MaxLatitude < -90 || geoBounds.MaxLatitude > 90 ||
+		geoBounds.MinLatitude > geoBounds.MaxLatitude {
+		return nil, errs.ErrTransactionGeoBoundsFilterInvalid
+	}
+
+	return geoBounds, nil
+}
+
+// ParseTransactionCommentFilter parses transaction comment filter from string with the format "type:value", the value is omitted for "em" and "nm" types
+func ParseTransactionCommentFilter(commentFilterStr string) (*TransactionCommentFilter, error) {
+	if commentFilterStr == "" {
+		return nil, nil
+	}
+
+	items := strings.SplitN(commentFilterStr, ":", 2)
+	filterType := TransactionCommentFilterType(items[0])
+
+	switch filterType {
+	case TRANSACTION_COMMENT_FILTER_TYPE_EMPTY, TRANSACTION_COMMENT_FILTER_TYPE_NOT_EMPTY:
+		if len(items) != 1 {
+			return nil, errs.ErrTransactionCommentFilterInvalid
+		}
+
+		return &TransactionCommentFilter{
+			Type: filterType,
+		}, nil
+	case TRANSACTION_COMMENT_FILTER_TYPE_CONTAINS, TRANSACTION_COMMENT_FILTER_TYPE_NOT_CONTAINS,
+		TRANSACTION_COMMENT_FILTER_TYPE_EQUALS, TRANSACTION_COMMENT_FILTER_TYPE_NOT_EQUALS,
+		TRANSACTION_COMMENT_FILTER_TYPE_STARTS_WITH, TRANSACTION_COMMENT_FILTER_TYPE_ENDS_WITH:
+		if len(items) != 2 || items[1] == "" {
+			return nil, errs.ErrTransactionCommentFilterInvalid
+		}
+
+		return &TransactionCommentFilter{
+			Type:  filterType,
+			Value: items[1],
+		}, nil
+	default:
+		return nil, errs.ErrTransactionCommentFilterInvalid
+	}
+}
+
+// ParseTransactionQueryConditionGroupRequests parses the condition groups from json string and validates the filters in each group
+func ParseTransactionQueryConditionGroupRequests(conditionGroupsStr string) ([]*TransactionQueryConditionGroupRequest, error) {
+	if conditionGroupsStr == "" {
+		return nil, nil
+	}
+
+	var conditionGroups []*TransactionQueryConditionGroupRequest
+
+	if err := json.Unmarshal([]byte(conditionGroupsStr), &conditionGroups); err != nil {
+		return nil, errs.ErrTransactionQueryConditionGroupsInvalid
+	}
+
+	if len(conditionGroups) > TransactionQueryConditionGroupMaxCount {
+		return nil, errs.ErrTransactionQueryConditionGroupsInvalid
+	}
+
+	for i := 0; i < len(conditionGroups); i++ {
+		conditionGroup := conditionGroups[i]
+
+		if conditionGroup == nil || conditionGroup.Type > TRANSACTION_TYPE_TRANSFER || conditionGroup.PlannedFilter > TRANSACTION_PLANNED_FILTER_TYPE_CONFIRMED ||
+			conditionGroup.CounterpartyId < 0 || conditionGroup.CfoId < 0 || conditionGroup.SourceTemplateId < 0 {
+			return nil, errs.ErrTransactionQueryConditionGroupsInvalid
+		}
+
+		if _, err := ParseTransactionCommentFilter(conditionGroup.CommentFilter); err != nil {
+			return nil, err
+		}
+
+		if _, err := ParseTransactionGeoBoundsFilter(conditionGroup.GeoBounds); err != nil {
+			return nil, err
+		}
+	}
+
+	return conditionGroups, nil
+}
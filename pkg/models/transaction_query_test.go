@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestParseTransactionGeoBoundsFilter_EmptyFilter(t *testing.T) {
+	actualValue, err := ParseTransactionGeoBoundsFilter("")
+	assert.Nil(t, err)
+	assert.Nil(t, actualValue)
+}
+
+func TestParseTransactionGeoBoundsFilter_ValidFilter(t *testing.T) {
+	actualValue, err := ParseTransactionGeoBoundsFilter("116.1, 39.7, 116.7, 40.2")
+	assert.Nil(t, err)
+	assert.Equal(t, 116.1, actualValue.MinLongitude)
+	assert.Equal(t, 39.7, actualValue.MinLatitude)
+	assert.Equal(t, 116.7, actualValue.MaxLongitude)
+	assert.Equal(t, 40.2, actualValue.MaxLatitude)
+
+	actualValue, err = ParseTransactionGeoBoundsFilter("170,-10,-170,10")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(170), actualValue.MinLongitude)
+	assert.Equal(t, float64(-170), actualValue.MaxLongitude)
+}
+
+func TestParseTransactionGeoBoundsFilter_InvalidFilter(t *testing.T) {
+	_, err := ParseTransactionGeoBoundsFilter("116.1,39.7,116.7")
+	assert.EqualError(t, err, errs.ErrTransactionGeoBoundsFilterInvalid.Message)
+
+	_, err = ParseTransactionGeoBoundsFilter("116.1,39.7,116.7,a")
+	assert.EqualError(t, err, errs.ErrTransactionGeoBoundsFilterInvalid.Message)
+
+	_, err = ParseTransactionGeoBoundsFilter("116.1,40.2,116.7,39.7")
+	assert.EqualError(t, err, errs.ErrTransactionGeoBoundsFilterInvalid.Message)
+
+	_, err = ParseTransactionGeoBoundsFilter("0,0,181,10")
+	assert.EqualError(t, err, errs.ErrTransactionGeoBoundsFilterInvalid.Message)
+}
+
+func TestParseTransactionCommentFilter_ValidFilter(t *testing.T) {
+	actualValue, err := ParseTransactionCommentFilter("")
+	assert.Nil(t, err)
+	assert.Nil(t, actualValue)
+
+	actualValue, err = ParseTransactionCommentFilter("ct:a:b")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_COMMENT_FILTER_TYPE_CONTAINS, actualValue.Type)
+	assert.Equal(t, "a:b", actualValue.Value)
+
+	actualValue, err = ParseTransactionCommentFilter("em")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_COMMENT_FILTER_TYPE_EMPTY, actualValue.Type)
+	assert.Equal(t, "", actualValue.Value)
+}
+
+func TestParseTransactionCommentFilter_InvalidFilter(t *testing.T) {
+	_, err := ParseTransactionCommentFilter("ct:")
+	assert.EqualError(t, err, errs.ErrTransactionCommentFilterInvalid.Message)
+
+	_, err = ParseTransactionCommentFilter("nm:test")
+	assert.EqualError(t, err, errs.ErrTransactionCommentFilterInvalid.Message)
+
+	_, err = ParseTransactionCommentFilter("xx:test")
+	assert.EqualError(t, err, errs.ErrTransactionCommentFilterInvalid.Message)
+}
+
+func TestParseTransactionQueryConditionGroupRequests_ValidGroups(t *testing.T) {
+	actualValue, err := ParseTransactionQueryConditionGroupRequests("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualValue))
+
+	actualValue, err = ParseTransactionQueryConditionGroupRequests(`[{"type":3,"cfoId":"1001","plannedFilter":1},{"categoryIds":"1,2","sourceTemplateId":"2001"}]`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualValue))
+	assert.Equal(t, TRANSACTION_TYPE_EXPENSE, actualValue[0].Type)
+	assert.Equal(t, int64(1001), actualValue[0].CfoId)
+	assert.Equal(t, TRANSACTION_PLANNED_FILTER_TYPE_PLANNED, actualValue[0].PlannedFilter)
+	assert.Equal(t, "1,2", actualValue[1].CategoryIds)
+	assert.Equal(t, int64(2001), actualValue[1].SourceTemplateId)
+}
+
+func TestParseTransactionQueryConditionGroupRequests_InvalidGroups(t *testing.T) {
+	_, err := ParseTransactionQueryConditionGroupRequests(`{}`)
+	assert.EqualError(t, err, errs.ErrTransactionQueryConditionGroupsInvalid.Message)
+
+	_, err = ParseTransactionQueryConditionGroupRequests(`[{"plannedFilter":3}]`)
+	assert.EqualError(t, err, errs.ErrTransactionQueryConditionGroupsInvalid.Message)
+
+	_, err = ParseTransactionQueryConditionGroupRequests(`[{"commentFilter":"xx"}]`)
+	assert.EqualError(t, err, errs.ErrTransactionCommentFilterInvalid.Message)
+
+	_, err = ParseTransactionQueryConditionGroupRequests(`[{},{},{},{},{},{},{},{},{},{},{}]`)
+	assert.EqualError(t, err, errs.ErrTransactionQueryConditionGroupsInvalid.Message)
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// TransactionSearchTokenMaxLength is the maximum rune count of the token in transaction search index, the longer token is truncated
+const TransactionSearchTokenMaxLength = 32
+
+// TransactionSearchTermMaxCount is the maximum count of terms in transaction search query
+const TransactionSearchTermMaxCount = 10
+
+// TransactionSearchIndex represents the token of transaction comment stored in the full-text search index,
+// only the transaction id of the transaction created by user is indexed, the transfer-in transaction shares the index of its related transaction
+type TransactionSearchIndex struct {
+	Uid           int64  `xorm:"PK INDEX(IDX_transaction_search_index_uid_token_transaction_id)"`
+	Token         string `xorm:"VARCHAR(128) INDEX(IDX_transaction_search_index_uid_token_transaction_id) NOT NULL"`
+	TransactionId int64  `xorm:"PK INDEX(IDX_transaction_search_index_uid_token_transaction_id)"`
+	Position      int32  `xorm:"PK"`
+}
+
+// TransactionSearchTerm represents a term of transaction search query,
+// the term with multiple tokens is a phrase which requires the tokens to be adjacent, and the last token matches by prefix if prefix is true
+type TransactionSearchTerm struct {
+	Tokens          []string
+	Prefix          bool
+	CounterpartyIds []int64
+	TagIds          []int64
+}
+
+// IsMatchTokens returns whether the term matches the tokens of the text
+func (t *TransactionSearchTerm) IsMatchTokens(tokens []string) bool {
+	if len(t.Tokens) < 1 {
+		return false
+	}
+
+	for i := 0; i+len(t.Tokens) <= len(tokens); i++ {
+		matched := true
+
+		for j := 0; j < len(t.Tokens); j++ {
+			token := tokens[i+j]
+
+			if j == len(t.Tokens)-1 && t.Prefix {
+				matched = strings.HasPrefix(token, t.Tokens[j])
+			} else {
+				matched = token == t.Tokens[j]
+			}
+
+			if !matched {
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetTransactionSearchTokens returns the lower case tokens of the text in order,
+// the letters and numbers are split by other characters, and each ideographic character is a separate token
+func GetTransactionSearchTokens(text string) []string {
+	tokens := make([]string, 0)
+	currentToken := make([]rune, 0, TransactionSearchTokenMaxLength)
+
+	appendCurrentToken := func() {
+		if len(currentToken) < 1 {
+			return
+		}
+
+		if len(currentToken) > TransactionSearchTokenMaxLength {
+			currentToken = currentToken[:TransactionSearchTokenMaxLength]
+		}
+
+		tokens = append(tokens, string(currentToken))
+		currentToken = currentToken[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			appendCurrentToken()
+			tokens = append(tokens, string(r))
+		} else if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) {
+			currentToken = append(currentToken, r)
+		} else {
+			appendCurrentToken()
+		}
+	}
+
+	appendCurrentToken()
+
+	return tokens
+}
+
+// ParseTransactionSearchQuery parses transaction search query to terms which are all required to match,
+// the words in double quotes are a phrase, and the word or phrase ending with asterisk matches by prefix
+func ParseTransactionSearchQuery(query string) ([]*TransactionSearchTerm, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	terms := make([]*TransactionSearchTerm, 0)
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); i++ {
+		if unicode.IsSpace(runes[i]) {
+			continue
+		}
+
+		start := i
+		end := i
+
+		if runes[i] == '"' {
+			start = i + 1
+			end = start
+
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+
+			i = end
+		} else {
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+
+			i = end
+		}
+
+		termText := strings.TrimSpace(string(runes[start:end]))
+		tokens := GetTransactionSearchTokens(termText)
+
+		if len(tokens) < 1 {
+			continue
+		}
+
+		terms = append(terms, &TransactionSearchTerm{
+			Tokens: tokens,
+			Prefix: strings.HasSuffix(termText, "*"),
+		})
+	}
+
+	if len(terms) < 1 || len(terms) > TransactionSearchTermMaxCount {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	return terms, nil
+}
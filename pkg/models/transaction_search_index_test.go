@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestGetTransactionSearchTokens(t *testing.T) {
+	assert.Equal(t, []string{"coffee", "at", "starbucks", "2024"}, GetTransactionSearchTokens("Coffee at Starbucks, 2024!"))
+	assert.Equal(t, []string{"café", "au", "lait"}, GetTransactionSearchTokens("Café-au-lait"))
+	assert.Equal(t, []string{"lunch", "午", "饭"}, GetTransactionSearchTokens("lunch午饭"))
+	assert.Equal(t, []string{}, GetTransactionSearchTokens(" ,.;"))
+}
+
+func TestGetTransactionSearchTokens_TruncateLongToken(t *testing.T) {
+	actualValue := GetTransactionSearchTokens(strings.Repeat("a", 40))
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, strings.Repeat("a", TransactionSearchTokenMaxLength), actualValue[0])
+}
+
+func TestParseTransactionSearchQuery_EmptyQuery(t *testing.T) {
+	actualValue, err := ParseTransactionSearchQuery("  ")
+	assert.Nil(t, err)
+	assert.Nil(t, actualValue)
+}
+
+func TestParseTransactionSearchQuery_WordsPrefixAndPhrase(t *testing.T) {
+	actualValue, err := ParseTransactionSearchQuery("Coffee sta* \"Monthly Rent\" \"new yo*\"")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(actualValue))
+
+	assert.Equal(t, []string{"coffee"}, actualValue[0].Tokens)
+	assert.False(t, actualValue[0].Prefix)
+
+	assert.Equal(t, []string{"sta"}, actualValue[1].Tokens)
+	assert.True(t, actualValue[1].Prefix)
+
+	assert.Equal(t, []string{"monthly", "rent"}, actualValue[2].Tokens)
+	assert.False(t, actualValue[2].Prefix)
+
+	assert.Equal(t, []string{"new", "yo"}, actualValue[3].Tokens)
+	assert.True(t, actualValue[3].Prefix)
+}
+
+func TestParseTransactionSearchQuery_UnclosedQuote(t *testing.T) {
+	actualValue, err := ParseTransactionSearchQuery("\"monthly rent")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, []string{"monthly", "rent"}, actualValue[0].Tokens)
+}
+
+func TestParseTransactionSearchQuery_InvalidQuery(t *testing.T) {
+	_, err := ParseTransactionSearchQuery("\"\" * ,")
+	assert.EqualError(t, err, errs.ErrTransactionSearchQueryInvalid.Message)
+
+	_, err = ParseTransactionSearchQuery("a b c d e f g h i j k")
+	assert.EqualError(t, err, errs.ErrTransactionSearchQueryInvalid.Message)
+}
+
+func TestTransactionSearchTermIsMatchTokens(t *testing.T) {
+	tokens := GetTransactionSearchTokens("Pay monthly rent to landlord")
+
+	assert.True(t, (&TransactionSearchTerm{Tokens: []string{"rent"}}).IsMatchTokens(tokens))
+	assert.False(t, (&TransactionSearchTerm{Tokens: []string{"ren"}}).IsMatchTokens(tokens))
+	assert.True(t, (&TransactionSearchTerm{Tokens: []string{"land"}, Prefix: true}).IsMatchTokens(tokens))
+	assert.True(t, (&TransactionSearchTerm{Tokens: []string{"monthly", "rent"}}).IsMatchTokens(tokens))
+	assert.True(t, (&TransactionSearchTerm{Tokens: []string{"monthly", "re"}, Prefix: true}).IsMatchTokens(tokens))
+	assert.False(t, (&TransactionSearchTerm{Tokens: []string{"rent", "monthly"}}).IsMatchTokens(tokens))
+	assert.False(t, (&TransactionSearchTerm{Tokens: []string{"pay", "rent"}}).IsMatchTokens(tokens))
+	assert.False(t, (&TransactionSearchTerm{}).IsMatchTokens(tokens))
+}
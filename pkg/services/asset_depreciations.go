@@ -0,0 +1,387 @@
+// asset_depreciations.go calculates asset depreciation by straight-line, declining-balance, sum-of-years-digits and units-of-production methods.
+package services
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// assetDecliningBalanceRateFactor is the multiple of straight-line rate used by declining-balance method (double declining balance)
+const assetDecliningBalanceRateFactor = 2
+
+// assetDepreciationPlan represents the depreciation of each period of asset, the period is the service month which starts at the same day of month as the commission date,
+// or the calendar month for units-of-production method, and the depreciation of partial period is prorated by time
+type assetDepreciationPlan struct {
+	boundaries  []int64
+	amounts     []int64
+	accumulated []int64
+	endTime     int64
+}
+
+// GetAssetDepreciationSchedule returns the month-by-month depreciation schedule of asset in the calendar months of the specified timezone,
+// the schedule starts at the commission date and ends at the end of useful life or the decommission date, whichever comes first
+func (s *AssetService) GetAssetDepreciationSchedule(asset *models.Asset, timezone *time.Location) []*models.AssetDepreciationScheduleItemResponse {
+	items := make([]*models.AssetDepreciationScheduleItemResponse, 0)
+	plan := getAssetDepreciationPlan(asset, timezone)
+
+	if plan == nil {
+		return items
+	}
+
+	startTime := plan.boundaries[0]
+	commissionTime := time.Unix(startTime, 0).In(timezone)
+	monthStartTime := time.Date(commissionTime.Year(), commissionTime.Month(), 1, 0, 0, 0, 0, timezone)
+
+	for startTime < plan.endTime {
+		nextMonthStartTime := monthStartTime.AddDate(0, 1, 0)
+		endTime := nextMonthStartTime.Unix()
+
+		if endTime > plan.endTime {
+			endTime = plan.endTime
+		}
+
+		accumulated := plan.getAccumulatedDepreciation(endTime)
+
+		items = append(items, &models.AssetDepreciationScheduleItemResponse{
+			Year:                    int32(monthStartTime.Year()),
+			Month:                   int32(monthStartTime.Month()),
+			StartTime:               startTime,
+			EndTime:                 endTime,
+			Depreciation:            accumulated - plan.getAccumulatedDepreciation(startTime),
+			AccumulatedDepreciation: accumulated,
+			BookValue:               asset.PurchaseCost - accumulated,
+		})
+
+		startTime = endTime
+		monthStartTime = nextMonthStartTime
+	}
+
+	return items
+}
+
+// SaveAssetProductionOutput saves the actual output of units-of-production asset in the specified calendar month of the asset timezone and returns the asset,
+// the output of the month whose depreciation has been posted cannot be changed
+func (s *AssetService) SaveAssetProductionOutput(c core.Context, uid int64, saveReq *models.AssetProductionOutputSaveRequest) (*models.Asset, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if saveReq.Id <= 0 {
+		return nil, errs.ErrAssetIdInvalid
+	}
+
+	asset := &models.Asset{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(saveReq.Id).Where("uid=? AND deleted=?", uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAssetNotFound
+		}
+
+		if asset.DepreciationMethod != models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION {
+			return errs.ErrAssetProductionOutputMethodInvalid
+		}
+
+		if asset.Status != models.ASSET_STATUS_ACTIVE {
+			return errs.ErrAssetAlreadyDisposed
+		}
+
+		timezone := time.FixedZone("Asset Timezone", int(asset.TimezoneUtcOffset)*60)
+		plan := getAssetDepreciationPlan(asset, timezone)
+		monthStartTime := time.Date(int(saveReq.Year), time.Month(saveReq.Month), 1, 0, 0, 0, 0, timezone)
+
+		if plan == nil || monthStartTime.AddDate(0, 1, 0).Unix() <= plan.boundaries[0] || monthStartTime.Unix() >= plan.endTime {
+			return errs.ErrAssetProductionOutputPeriodInvalid
+		}
+
+		if asset.DepreciationPostedTime > 0 && monthStartTime.Unix() < asset.DepreciationPostedTime {
+			return errs.ErrAssetProductionOutputAlreadyPosted
+		}
+
+		output := asset.ProductionOutputs.Get(saveReq.Year, saveReq.Month)
+
+		if output != nil {
+			output.Output = saveReq.Output
+		} else {
+			asset.ProductionOutputs = append(asset.ProductionOutputs, &models.AssetProductionOutput{
+				Year:   saveReq.Year,
+				Month:  saveReq.Month,
+				Output: saveReq.Output,
+			})
+			sort.Sort(asset.ProductionOutputs)
+		}
+
+		asset.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(asset.AssetId).Cols("production_outputs", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(asset)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAssetNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// getAssetAccumulatedDepreciation returns the accumulated depreciation of asset at the specified time
+func getAssetAccumulatedDepreciation(asset *models.Asset, asOf time.Time) int64 {
+	plan := getAssetDepreciationPlan(asset, asOf.Location())
+
+	if plan == nil {
+		return 0
+	}
+
+	return plan.getAccumulatedDepreciation(asOf.Unix())
+}
+
+// getAssetDepreciationPostableTime returns the time until which the depreciation of asset can be posted, the depreciation of units-of-production asset
+// can only be posted until the start of the first calendar month whose production output has not been entered
+func getAssetDepreciationPostableTime(asset *models.Asset, untilUnixTime int64, timezone *time.Location) int64 {
+	if asset.DepreciationMethod != models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION || asset.InstalledCapacityWatts <= 0 {
+		return untilUnixTime
+	}
+
+	plan := getAssetDepreciationPlan(asset, timezone)
+
+	if plan == nil {
+		return untilUnixTime
+	}
+
+	for i := 0; i < len(plan.amounts) && plan.boundaries[i] < untilUnixTime; i++ {
+		periodStartTime := time.Unix(plan.boundaries[i], 0).In(timezone)
+
+		if asset.ProductionOutputs.Get(int32(periodStartTime.Year()), int32(periodStartTime.Month())) == nil {
+			return plan.boundaries[i]
+		}
+	}
+
+	return untilUnixTime
+}
+
+// getAssetDepreciationPlan returns the depreciation plan of asset in the calendar months of the specified timezone, or nil if the asset is not depreciated
+func getAssetDepreciationPlan(asset *models.Asset, timezone *time.Location) *assetDepreciationPlan {
+	if asset.CommissionDate <= 0 || asset.UsefulLifeMonths <= 0 {
+		return nil
+	}
+
+	commissionTime := time.Unix(asset.CommissionDate, 0).In(timezone)
+	endTime := addMonthsKeepingEndOfMonth(commissionTime, int(asset.UsefulLifeMonths)).Unix()
+
+	if asset.DecommissionDate > 0 && asset.DecommissionDate < endTime {
+		endTime = asset.DecommissionDate
+	}
+
+	if endTime < commissionTime.Unix() {
+		endTime = commissionTime.Unix()
+	}
+
+	var plan *assetDepreciationPlan
+	depreciableAmount := asset.PurchaseCost - asset.SalvageValue
+
+	if asset.DepreciationMethod == models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION && asset.InstalledCapacityWatts > 0 {
+		plan = newCalendarMonthDepreciationPlan(commissionTime, endTime)
+
+		if depreciableAmount > 0 {
+			plan.setUnitsOfProductionAmounts(depreciableAmount, asset.InstalledCapacityWatts, asset.ProductionOutputs, timezone)
+		}
+	} else {
+		plan = newServiceMonthDepreciationPlan(commissionTime, int(asset.UsefulLifeMonths))
+
+		if depreciableAmount > 0 {
+			switch asset.DepreciationMethod {
+			case models.ASSET_DEPRECIATION_METHOD_DECLINING_BALANCE:
+				plan.setDecliningBalanceAmounts(asset.PurchaseCost, asset.SalvageValue)
+			case models.ASSET_DEPRECIATION_METHOD_SUM_OF_YEARS_DIGITS:
+				plan.setSumOfYearsDigitsAmounts(depreciableAmount)
+			default:
+				plan.setStraightLineAmounts(depreciableAmount)
+			}
+		}
+	}
+
+	plan.endTime = endTime
+
+	for i := 0; i < len(plan.amounts); i++ {
+		plan.accumulated[i+1] = plan.accumulated[i] + plan.amounts[i]
+	}
+
+	return plan
+}
+
+// newServiceMonthDepreciationPlan returns the plan of which each period is a service month of useful life
+func newServiceMonthDepreciationPlan(commissionTime time.Time, months int) *assetDepreciationPlan {
+	plan := &assetDepreciationPlan{
+		boundaries:  make([]int64, months+1),
+		amounts:     make([]int64, months),
+		accumulated: make([]int64, months+1),
+	}
+
+	for i := 0; i <= months; i++ {
+		plan.boundaries[i] = addMonthsKeepingEndOfMonth(commissionTime, i).Unix()
+	}
+
+	return plan
+}
+
+// newCalendarMonthDepreciationPlan returns the plan of which each period is the part of a calendar month between the commission time and the end time,
+// so the production output entered for a calendar month belongs to exactly one period
+func newCalendarMonthDepreciationPlan(commissionTime time.Time, endTime int64) *assetDepreciationPlan {
+	boundaries := []int64{commissionTime.Unix()}
+	monthStartTime := time.Date(commissionTime.Year(), commissionTime.Month(), 1, 0, 0, 0, 0, commissionTime.Location())
+
+	for boundaries[len(boundaries)-1] < endTime {
+		monthStartTime = monthStartTime.AddDate(0, 1, 0)
+		boundary := monthStartTime.Unix()
+
+		if boundary > endTime {
+			boundary = endTime
+		}
+
+		boundaries = append(boundaries, boundary)
+	}
+
+	return &assetDepreciationPlan{
+		boundaries:  boundaries,
+		amounts:     make([]int64, len(boundaries)-1),
+		accumulated: make([]int64, len(boundaries)),
+	}
+}
+
+// getAccumulatedDepreciation returns the accumulated depreciation at the specified unix time, the depreciation of the service month is prorated by the elapsed time
+func (p *assetDepreciationPlan) getAccumulatedDepreciation(unixTime int64) int64 {
+	if unixTime > p.endTime {
+		unixTime = p.endTime
+	}
+
+	if unixTime <= p.boundaries[0] {
+		return 0
+	}
+
+	months := len(p.amounts)
+
+	if unixTime >= p.boundaries[months] {
+		return p.accumulated[months]
+	}
+
+	index := sort.Search(months, func(i int) bool {
+		return p.boundaries[i+1] > unixTime
+	})
+
+	return p.accumulated[index] + mulDivInt64(p.amounts[index], unixTime-p.boundaries[index], p.boundaries[index+1]-p.boundaries[index])
+}
+
+// setStraightLineAmounts sets the same depreciation to every month, the remainder of depreciable amount divided by useful life months is not depreciated
+func (p *assetDepreciationPlan) setStraightLineAmounts(depreciableAmount int64) {
+	monthlyAmount := depreciableAmount / int64(len(p.amounts))
+
+	for i := 0; i < len(p.amounts); i++ {
+		p.amounts[i] = monthlyAmount
+	}
+}
+
+// setDecliningBalanceAmounts sets the depreciation of double declining balance with the monthly rate of 2 / useful life months,
+// it switches to straight-line method over the remaining months once that gives the larger depreciation, so the book value reaches the salvage value at the end of useful life
+func (p *assetDepreciationPlan) setDecliningBalanceAmounts(purchaseCost int64, salvageValue int64) {
+	months := int64(len(p.amounts))
+	bookValue := purchaseCost
+
+	for i := int64(0); i < months; i++ {
+		amount := mulDivInt64(bookValue, assetDecliningBalanceRateFactor, months)
+		straightLineAmount := (bookValue - salvageValue) / (months - i)
+
+		if straightLineAmount > amount || i == months-1 {
+			amount = straightLineAmount
+		}
+
+		if bookValue-amount < salvageValue || i == months-1 {
+			amount = bookValue - salvageValue
+		}
+
+		p.amounts[i] = amount
+		bookValue -= amount
+	}
+}
+
+// setSumOfYearsDigitsAmounts sets the depreciation of sum-of-years-digits method with the digits counted in months of useful life,
+// the depreciation of each month is proportional to the remaining months of useful life
+func (p *assetDepreciationPlan) setSumOfYearsDigitsAmounts(depreciableAmount int64) {
+	months := int64(len(p.amounts))
+	digitsSum := months * (months + 1) / 2
+	accumulatedDigits := int64(0)
+	accumulatedAmount := int64(0)
+
+	for i := int64(0); i < months; i++ {
+		accumulatedDigits += months - i
+		amount := mulDivInt64(depreciableAmount, accumulatedDigits, digitsSum) - accumulatedAmount
+		p.amounts[i] = amount
+		accumulatedAmount += amount
+	}
+}
+
+// setUnitsOfProductionAmounts sets the depreciation of units-of-production method, the installed capacity is the total output of the asset over its useful life,
+// so the depreciation of each calendar month is proportional to the output entered for that month, and the months without output are not depreciated
+func (p *assetDepreciationPlan) setUnitsOfProductionAmounts(depreciableAmount int64, totalCapacity int64, outputs models.AssetProductionOutputSlice, timezone *time.Location) {
+	accumulatedOutput := int64(0)
+	accumulatedAmount := int64(0)
+
+	for i := 0; i < len(p.amounts); i++ {
+		periodStartTime := time.Unix(p.boundaries[i], 0).In(timezone)
+		output := outputs.Get(int32(periodStartTime.Year()), int32(periodStartTime.Month()))
+
+		if output != nil {
+			accumulatedOutput += output.Output
+		}
+
+		amount := mulDivInt64(depreciableAmount, accumulatedOutput, totalCapacity)
+
+		// the output exceeding the installed capacity is not depreciated
+		if amount > depreciableAmount {
+			amount = depreciableAmount
+		}
+
+		p.amounts[i] = amount - accumulatedAmount
+		accumulatedAmount = amount
+	}
+}
+
+// addMonthsKeepingEndOfMonth returns the time after the specified months, the day of month is clamped to the last day of the target month instead of overflowing into the next month
+func addMonthsKeepingEndOfMonth(t time.Time, months int) time.Time {
+	firstDayOfTargetMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDayOfTargetMonth := firstDayOfTargetMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+
+	if day > lastDayOfTargetMonth {
+		day = lastDayOfTargetMonth
+	}
+
+	return firstDayOfTargetMonth.AddDate(0, 0, day-1)
+}
+
+// mulDivInt64 returns a * b / c without overflow of the intermediate product
+func mulDivInt64(a int64, b int64, c int64) int64 {
+	if c == 0 {
+		return 0
+	}
+
+	result := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	result.Quo(result, big.NewInt(c))
+
+	return result.Int64()
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetAssetDepreciationSchedule_NoCommissionDate(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:     120000,
+		UsefulLifeMonths: 12,
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 0, len(items))
+}
+
+func TestGetAssetDepreciationSchedule_StraightLineWithPartialMonths(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:       120000,
+		SalvageValue:       0,
+		UsefulLifeMonths:   12,
+		DepreciationMethod: models.ASSET_DEPRECIATION_METHOD_STRAIGHT_LINE,
+		CommissionDate:     time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 13, len(items))
+
+	// January: 16 of 31 days of the first service month (Jan 16 - Feb 16)
+	assert.Equal(t, int32(2025), items[0].Year)
+	assert.Equal(t, int32(1), items[0].Month)
+	assert.Equal(t, asset.CommissionDate, items[0].StartTime)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix(), items[0].EndTime)
+	assert.Equal(t, int64(10000*16/31), items[0].Depreciation)
+
+	// February: the rest of the first service month and 13 of 28 days of the second service month (Feb 16 - Mar 16)
+	assert.Equal(t, int64(10000+10000*13/28), items[1].AccumulatedDepreciation)
+	assert.Equal(t, items[1].AccumulatedDepreciation-items[0].AccumulatedDepreciation, items[1].Depreciation)
+
+	// January of next year: the last partial month ends at the end of useful life
+	assert.Equal(t, int32(2026), items[12].Year)
+	assert.Equal(t, int32(1), items[12].Month)
+	assert.Equal(t, time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC).Unix(), items[12].EndTime)
+	assert.Equal(t, int64(120000), items[12].AccumulatedDepreciation)
+	assert.Equal(t, int64(0), items[12].BookValue)
+
+	totalDepreciation := int64(0)
+
+	for i := 0; i < len(items); i++ {
+		totalDepreciation += items[i].Depreciation
+		assert.Equal(t, asset.PurchaseCost-items[i].AccumulatedDepreciation, items[i].BookValue)
+	}
+
+	assert.Equal(t, int64(120000), totalDepreciation)
+}
+
+func TestGetAssetDepreciationSchedule_StopAtDecommissionDate(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:     120000,
+		SalvageValue:     0,
+		UsefulLifeMonths: 12,
+		CommissionDate:   time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC).Unix(),
+		DecommissionDate: time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, int32(3), items[2].Month)
+	assert.Equal(t, asset.DecommissionDate, items[2].EndTime)
+	assert.Equal(t, int64(20000), items[2].AccumulatedDepreciation)
+	assert.Equal(t, int64(100000), items[2].BookValue)
+}
+
+func TestGetAssetDepreciationSchedule_DecliningBalance(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:       120000,
+		SalvageValue:       12000,
+		UsefulLifeMonths:   12,
+		DepreciationMethod: models.ASSET_DEPRECIATION_METHOD_DECLINING_BALANCE,
+		CommissionDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 12, len(items))
+
+	// the first month is depreciated by the double rate of 2 / 12 on the purchase cost
+	assert.Equal(t, int64(20000), items[0].Depreciation)
+	// the second month is depreciated by the double rate on the book value
+	assert.Equal(t, int64(100000*2/12), items[1].Depreciation)
+
+	// the depreciation switches to straight-line method over the remaining months, so it never drops to zero before the end of useful life
+	for i := 0; i < len(items); i++ {
+		assert.True(t, items[i].Depreciation > 0)
+		assert.True(t, items[i].BookValue >= asset.SalvageValue)
+	}
+
+	assert.Equal(t, int64(12000), items[11].BookValue)
+}
+
+func TestGetAssetDepreciationSchedule_SumOfYearsDigits(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:       70000,
+		SalvageValue:       10000,
+		UsefulLifeMonths:   3,
+		DepreciationMethod: models.ASSET_DEPRECIATION_METHOD_SUM_OF_YEARS_DIGITS,
+		CommissionDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, int64(30000), items[0].Depreciation)
+	assert.Equal(t, int64(20000), items[1].Depreciation)
+	assert.Equal(t, int64(10000), items[2].Depreciation)
+	assert.Equal(t, int64(10000), items[2].BookValue)
+}
+
+func TestGetAssetDepreciationSchedule_UnitsOfProduction(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:           60000,
+		SalvageValue:           0,
+		UsefulLifeMonths:       3,
+		DepreciationMethod:     models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION,
+		InstalledCapacityWatts: 1000,
+		CommissionDate:         time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		ProductionOutputs: models.AssetProductionOutputSlice{
+			{Year: 2025, Month: 2, Output: 250},
+			{Year: 2025, Month: 4, Output: 500},
+		},
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 3, len(items))
+
+	// the depreciation of each month is proportional to the output of that month, and the month without output is not depreciated
+	assert.Equal(t, int64(15000), items[0].Depreciation)
+	assert.Equal(t, int64(0), items[1].Depreciation)
+	assert.Equal(t, int64(30000), items[2].Depreciation)
+	assert.Equal(t, int64(45000), items[2].AccumulatedDepreciation)
+	assert.Equal(t, int64(15000), items[2].BookValue)
+}
+
+func TestGetAssetDepreciationSchedule_UnitsOfProductionWithPartialMonths(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:           60000,
+		SalvageValue:           10000,
+		UsefulLifeMonths:       2,
+		DepreciationMethod:     models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION,
+		InstalledCapacityWatts: 1000,
+		CommissionDate:         time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC).Unix(),
+		ProductionOutputs: models.AssetProductionOutputSlice{
+			{Year: 2025, Month: 2, Output: 400},
+			{Year: 2025, Month: 3, Output: 400},
+			{Year: 2025, Month: 4, Output: 400},
+		},
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 3, len(items))
+
+	// the output of the partial first and last months belongs to the days in service of those months
+	assert.Equal(t, asset.CommissionDate, items[0].StartTime)
+	assert.Equal(t, int64(20000), items[0].Depreciation)
+	assert.Equal(t, int64(20000), items[1].Depreciation)
+	assert.Equal(t, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC).Unix(), items[2].EndTime)
+
+	// the output exceeding the installed capacity is not depreciated below the salvage value
+	assert.Equal(t, int64(10000), items[2].Depreciation)
+	assert.Equal(t, int64(10000), items[2].BookValue)
+}
+
+func TestGetAssetDepreciationSchedule_UnitsOfProductionWithDecommissionDate(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:           60000,
+		SalvageValue:           0,
+		UsefulLifeMonths:       12,
+		DepreciationMethod:     models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION,
+		InstalledCapacityWatts: 1000,
+		CommissionDate:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		DecommissionDate:       time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC).Unix(),
+		ProductionOutputs: models.AssetProductionOutputSlice{
+			{Year: 2025, Month: 1, Output: 100},
+			{Year: 2025, Month: 2, Output: 50},
+		},
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 2, len(items))
+
+	// the whole output of the decommission month is depreciated by the decommission date
+	assert.Equal(t, asset.DecommissionDate, items[1].EndTime)
+	assert.Equal(t, int64(3000), items[1].Depreciation)
+	assert.Equal(t, int64(51000), calculateResidualValue(asset, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestGetAssetDepreciationSchedule_UnitsOfProductionWithoutInstalledCapacity(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:       60000,
+		SalvageValue:       0,
+		UsefulLifeMonths:   2,
+		DepreciationMethod: models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION,
+		CommissionDate:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	items := Assets.GetAssetDepreciationSchedule(asset, time.UTC)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, int64(30000), items[0].Depreciation)
+	assert.Equal(t, int64(30000), items[1].Depreciation)
+}
+
+func TestCalculateResidualValue_ProratePartialMonth(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:     120000,
+		SalvageValue:     0,
+		UsefulLifeMonths: 12,
+		CommissionDate:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	asOf := time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC) // 15 of 30 days
+	assert.Equal(t, int64(115000), calculateResidualValue(asset, asOf))
+}
+
+func TestCalculateResidualValue_AfterDecommissionDate(t *testing.T) {
+	asset := &models.Asset{
+		PurchaseCost:     120000,
+		SalvageValue:     0,
+		UsefulLifeMonths: 12,
+		CommissionDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		DecommissionDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	asOf := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(90000), calculateResidualValue(asset, asOf))
+}
+
+func TestAddMonthsKeepingEndOfMonth(t *testing.T) {
+	from := time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 28, 8, 0, 0, 0, time.UTC), addMonthsKeepingEndOfMonth(from, 1))
+	assert.Equal(t, time.Date(2025, 3, 31, 8, 0, 0, 0, time.UTC), addMonthsKeepingEndOfMonth(from, 2))
+	assert.Equal(t, time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC), addMonthsKeepingEndOfMonth(from, 12))
+}
@@ -0,0 +1,543 @@
+// asset_transactions.go links fixed assets to the transaction ledger: purchase transactions are capitalized into the asset,
+// and the depreciation and disposal of the asset are posted as transactions of its book account.
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// GetAssetTransactionsByAssetId returns all transactions linked to asset
+func (s *AssetService) GetAssetTransactionsByAssetId(c core.Context, uid int64, assetId int64) ([]*models.AssetTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if assetId <= 0 {
+		return nil, errs.ErrAssetIdInvalid
+	}
+
+	var assetTransactions []*models.AssetTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND asset_id=?", uid, assetId).OrderBy("created_unix_time asc, transaction_id asc").Find(&assetTransactions)
+
+	return assetTransactions, err
+}
+
+// LinkPurchaseTransactions links the expense or transfer transactions to asset as its purchase,
+// and updates the purchase cost of asset to the total amount of all linked purchase transactions
+func (s *AssetService) LinkPurchaseTransactions(c core.Context, uid int64, assetId int64, transactionIds []int64) (*models.Asset, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if assetId <= 0 {
+		return nil, errs.ErrAssetIdInvalid
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	if len(transactionIds) < 1 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	asset := &models.Asset{}
+	now := time.Now().Unix()
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(assetId).Where("uid=? AND deleted=?", uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAssetNotFound
+		}
+
+		var transactions []*models.Transaction
+		err = sess.Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&transactions)
+
+		if err != nil {
+			return err
+		} else if len(transactions) < len(transactionIds) {
+			return errs.ErrTransactionNotFound
+		}
+
+		linked, err := sess.Where("uid=?", uid).In("transaction_id", transactionIds).Exist(&models.AssetTransaction{})
+
+		if err != nil {
+			return err
+		} else if linked {
+			return errs.ErrAssetTransactionAlreadyLinked
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+
+			if transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				return errs.ErrAssetPurchaseTransactionTypeInvalid
+			}
+
+			_, err = sess.Insert(&models.AssetTransaction{
+				Uid:             uid,
+				TransactionId:   transaction.TransactionId,
+				AssetId:         asset.AssetId,
+				TransactionType: models.ASSET_TRANSACTION_TYPE_PURCHASE,
+				Amount:          transaction.Amount,
+				CreatedUnixTime: now,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.updateAssetPurchaseCostInSession(sess, asset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// UnlinkPurchaseTransactions removes the purchase transactions from asset,
+// and updates the purchase cost of asset to the total amount of the remaining linked purchase transactions
+func (s *AssetService) UnlinkPurchaseTransactions(c core.Context, uid int64, assetId int64, transactionIds []int64) (*models.Asset, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if assetId <= 0 {
+		return nil, errs.ErrAssetIdInvalid
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	if len(transactionIds) < 1 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	asset := &models.Asset{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(assetId).Where("uid=? AND deleted=?", uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAssetNotFound
+		}
+
+		deletedRows, err := sess.Where("uid=? AND asset_id=? AND transaction_type=?", uid, assetId, models.ASSET_TRANSACTION_TYPE_PURCHASE).In("transaction_id", transactionIds).Delete(&models.AssetTransaction{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < int64(len(transactionIds)) {
+			return errs.ErrAssetTransactionNotLinked
+		}
+
+		return s.updateAssetPurchaseCostInSession(sess, asset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// PostAssetDepreciations posts the depreciation of all calendar months ended by the specified time as expense transactions
+// of the book account, for all active assets of all users which have book account and depreciation category,
+// the calendar months are in the timezone saved with each asset, and the depreciation of units-of-production asset is posted
+// only for the months whose production output has been entered
+func (s *AssetService) PostAssetDepreciations(c core.Context, currentUnixTime int64) error {
+	var allAssets []*models.Asset
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var assets []*models.Asset
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND status=? AND account_id>? AND depreciation_category_id>? AND commission_date>? AND commission_date<? AND useful_life_months>? AND depreciation_posted_time<?", false, models.ASSET_STATUS_ACTIVE, 0, 0, 0, currentUnixTime, 0, currentUnixTime).Find(&assets)
+
+		if err != nil {
+			return err
+		}
+
+		allAssets = append(allAssets, assets...)
+	}
+
+	if len(allAssets) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[asset_transactions.PostAssetDepreciations] should post depreciation of %d assets now", len(allAssets))
+
+	postedCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allAssets); i++ {
+		asset := allAssets[i]
+		timezone := time.FixedZone("Asset Timezone", int(asset.TimezoneUtcOffset)*60)
+		count, err := s.postAssetDepreciations(c, asset, getAssetDepreciationPostableTime(asset, currentUnixTime, timezone), timezone)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[asset_transactions.PostAssetDepreciations] failed to post depreciation of asset \"id:%d\" for user \"uid:%d\", because %s", asset.AssetId, asset.Uid, err.Error())
+			continue
+		}
+
+		postedCount += count
+	}
+
+	log.Infof(c, "[asset_transactions.PostAssetDepreciations] %d depreciation transactions have been posted, %d assets failed", postedCount, failedCount)
+
+	return nil
+}
+
+// DisposeAsset sells or decommissions asset at the disposal date and returns the disposed asset.
+// If the asset has book account and depreciation category, the depreciation until the disposal date and the write-off
+// of the remaining book value are posted to the book account. The proceeds are posted as income to the proceeds account.
+// The difference between the proceeds and the book value is the gain or loss on disposal.
+func (s *AssetService) DisposeAsset(c core.Context, uid int64, disposeReq *models.AssetDisposeRequest) (*models.Asset, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if disposeReq.Id <= 0 {
+		return nil, errs.ErrAssetIdInvalid
+	}
+
+	if disposeReq.Status != models.ASSET_STATUS_DECOMMISSIONED && disposeReq.Status != models.ASSET_STATUS_SOLD {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if disposeReq.Proceeds > 0 && (disposeReq.ProceedsAccountId <= 0 || disposeReq.ProceedsCategoryId <= 0) {
+		return nil, errs.ErrAssetDisposalProceedsAccountRequired
+	}
+
+	timezone := time.FixedZone("Client Timezone", int(disposeReq.UtcOffset)*60)
+	asset := &models.Asset{}
+	database := s.UserDataDB(uid)
+
+	err := database.DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(disposeReq.Id).Where("uid=? AND deleted=?", uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAssetNotFound
+		}
+
+		if asset.Status != models.ASSET_STATUS_ACTIVE {
+			return errs.ErrAssetAlreadyDisposed
+		}
+
+		if utils.ToMillisIfSeconds(disposeReq.DisposalDate) < utils.ToMillisIfSeconds(asset.PurchaseDate) || disposeReq.DisposalDate < asset.CommissionDate || disposeReq.DisposalDate < asset.DepreciationPostedTime {
+			return errs.ErrAssetDisposalDateInvalid
+		}
+
+		asset.Status = disposeReq.Status
+		asset.DecommissionDate = disposeReq.DisposalDate
+		postsToBookAccount := asset.AccountId > 0 && asset.DepreciationCategoryId > 0
+
+		if postsToBookAccount {
+			_, err = s.postAssetDepreciationsInSession(c, database, sess, asset, disposeReq.DisposalDate, timezone)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		asset.DisposalProceeds = disposeReq.Proceeds
+		asset.DisposalBookValue = calculateResidualValue(asset, time.Unix(disposeReq.DisposalDate, 0).In(timezone))
+
+		if postsToBookAccount && asset.DisposalBookValue > 0 {
+			err = s.createAssetTransactionInSession(c, database, sess, asset, &models.Transaction{
+				Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+				CategoryId:      asset.DepreciationCategoryId,
+				AccountId:       asset.AccountId,
+				TransactionTime: utils.GetMinTransactionTimeFromUnixTime(disposeReq.DisposalDate),
+				Amount:          asset.DisposalBookValue,
+				Comment:         fmt.Sprintf("Write-off of %s on disposal", asset.Name),
+			}, models.ASSET_TRANSACTION_TYPE_DISPOSAL_WRITE_OFF, disposeReq.DisposalDate, disposeReq.DisposalDate, timezone)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if disposeReq.Proceeds > 0 {
+			err = s.createAssetTransactionInSession(c, database, sess, asset, &models.Transaction{
+				Type:            models.TRANSACTION_DB_TYPE_INCOME,
+				CategoryId:      disposeReq.ProceedsCategoryId,
+				AccountId:       disposeReq.ProceedsAccountId,
+				TransactionTime: utils.GetMinTransactionTimeFromUnixTime(disposeReq.DisposalDate),
+				Amount:          disposeReq.Proceeds,
+				Comment:         fmt.Sprintf("Proceeds from disposal of %s", asset.Name),
+			}, models.ASSET_TRANSACTION_TYPE_DISPOSAL_PROCEEDS, disposeReq.DisposalDate, disposeReq.DisposalDate, timezone)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		asset.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(asset.AssetId).Cols("status", "decommission_date", "disposal_proceeds", "disposal_book_value", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(asset)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAssetNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// updateAssetPurchaseCostInSession updates the purchase cost of asset to the total amount of linked purchase transactions
+func (s *AssetService) updateAssetPurchaseCostInSession(sess *xorm.Session, asset *models.Asset) error {
+	purchaseCost, err := sess.Where("uid=? AND asset_id=? AND transaction_type=?", asset.Uid, asset.AssetId, models.ASSET_TRANSACTION_TYPE_PURCHASE).SumInt(&models.AssetTransaction{}, "amount")
+
+	if err != nil {
+		return err
+	}
+
+	asset.PurchaseCost = purchaseCost
+	asset.UpdatedUnixTime = time.Now().Unix()
+
+	_, err = sess.ID(asset.AssetId).Cols("purchase_cost", "updated_unix_time").Where("uid=? AND deleted=?", asset.Uid, false).Update(asset)
+
+	return err
+}
+
+func (s *AssetService) postAssetDepreciations(c core.Context, asset *models.Asset, untilUnixTime int64, timezone *time.Location) (int, error) {
+	database := s.UserDataDB(asset.Uid)
+	postedCount := 0
+
+	err := database.DoTransaction(c, func(sess *xorm.Session) error {
+		var err error
+		postedCount, err = s.postAssetDepreciationsInSession(c, database, sess, asset, untilUnixTime, timezone)
+		return err
+	})
+
+	return postedCount, err
+}
+
+// postAssetDepreciationsInSession posts one expense transaction for the depreciation of each calendar month which has not been posted
+// and ends by the specified time, the depreciation of each month is the accumulated depreciation since the last posted time
+func (s *AssetService) postAssetDepreciationsInSession(c core.Context, database *datastore.Database, sess *xorm.Session, asset *models.Asset, untilUnixTime int64, timezone *time.Location) (int, error) {
+	items := s.GetAssetDepreciationSchedule(asset, timezone)
+	lastPostedTime := asset.DepreciationPostedTime
+	postedTime := lastPostedTime
+	postedCount := 0
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if item.EndTime <= postedTime || item.EndTime > untilUnixTime {
+			continue
+		}
+
+		startTime := item.StartTime
+
+		if startTime < postedTime {
+			startTime = postedTime
+		}
+
+		amount := getAssetAccumulatedDepreciation(asset, time.Unix(item.EndTime, 0).In(timezone)) - getAssetAccumulatedDepreciation(asset, time.Unix(startTime, 0).In(timezone))
+
+		if amount > 0 {
+			err := s.createAssetTransactionInSession(c, database, sess, asset, &models.Transaction{
+				Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+				CategoryId:      asset.DepreciationCategoryId,
+				AccountId:       asset.AccountId,
+				TransactionTime: utils.GetMinTransactionTimeFromUnixTime(item.EndTime - 1),
+				Amount:          amount,
+				Comment:         fmt.Sprintf("Depreciation of %s for %04d-%02d", asset.Name, item.Year, item.Month),
+			}, models.ASSET_TRANSACTION_TYPE_DEPRECIATION, startTime, item.EndTime, timezone)
+
+			if err != nil {
+				return 0, err
+			}
+
+			postedCount++
+		}
+
+		postedTime = item.EndTime
+	}
+
+	if postedTime == lastPostedTime {
+		return 0, nil
+	}
+
+	asset.DepreciationPostedTime = postedTime
+	asset.UpdatedUnixTime = time.Now().Unix()
+
+	// the last posted time in the condition prevents the same months from being posted twice by concurrent runs
+	updatedRows, err := sess.ID(asset.AssetId).Cols("depreciation_posted_time", "updated_unix_time").Where("uid=? AND deleted=? AND depreciation_posted_time=?", asset.Uid, false, lastPostedTime).Update(asset)
+
+	if err != nil {
+		return 0, err
+	} else if updatedRows < 1 {
+		return 0, errs.ErrDatabaseOperationFailed
+	}
+
+	return postedCount, nil
+}
+
+// createAssetTransactionInSession creates the transaction posted for asset and links it to asset
+func (s *AssetService) createAssetTransactionInSession(c core.Context, database *datastore.Database, sess *xorm.Session, asset *models.Asset, transaction *models.Transaction, transactionType models.AssetTransactionType, periodStartTime int64, periodEndTime int64, timezone *time.Location) error {
+	_, utcOffset := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(timezone).Zone()
+
+	transaction.Uid = asset.Uid
+	transaction.CfoId = asset.CfoId
+	transaction.TimezoneUtcOffset = int16(utcOffset / 60)
+
+	err := Transactions.CreateTransactionInSession(c, database, sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Insert(&models.AssetTransaction{
+		Uid:             asset.Uid,
+		TransactionId:   transaction.TransactionId,
+		AssetId:         asset.AssetId,
+		TransactionType: transactionType,
+		Amount:          transaction.Amount,
+		PeriodStartTime: periodStartTime,
+		PeriodEndTime:   periodEndTime,
+		CreatedUnixTime: time.Now().Unix(),
+	})
+
+	return err
+}
+
+// deleteAssetTransactionsOfTransaction removes the links between asset and the deleted transaction,
+// the purchase cost of asset is updated to the total amount of the remaining purchase transactions,
+// and the posted time of depreciation is rolled back to the start of the deleted depreciation period.
+// Only the latest depreciation of active asset can be deleted so that no month is posted twice, and the disposal cannot be deleted.
+func deleteAssetTransactionsOfTransaction(sess *xorm.Session, uid int64, transactionId int64, now int64) error {
+	var assetTransactions []*models.AssetTransaction
+	err := sess.Where("uid=? AND transaction_id=?", uid, transactionId).Find(&assetTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(assetTransactions); i++ {
+		assetTransaction := assetTransactions[i]
+		asset := &models.Asset{}
+		has, err := sess.ID(assetTransaction.AssetId).Where("uid=? AND deleted=?", uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		}
+
+		if has && assetTransaction.TransactionType == models.ASSET_TRANSACTION_TYPE_DEPRECIATION {
+			if asset.Status != models.ASSET_STATUS_ACTIVE {
+				return errs.ErrAssetDepreciationNotLatest
+			}
+
+			laterPosted, err := sess.Where("uid=? AND asset_id=? AND transaction_type=? AND period_start_time>=? AND transaction_id<>?", uid, asset.AssetId, models.ASSET_TRANSACTION_TYPE_DEPRECIATION, assetTransaction.PeriodEndTime, transactionId).Exist(&models.AssetTransaction{})
+
+			if err != nil {
+				return err
+			} else if laterPosted {
+				return errs.ErrAssetDepreciationNotLatest
+			}
+		} else if has && assetTransaction.TransactionType != models.ASSET_TRANSACTION_TYPE_PURCHASE {
+			return errs.ErrAssetDisposalTransactionCannotDelete
+		}
+
+		_, err = sess.Where("uid=? AND asset_id=? AND transaction_id=?", uid, assetTransaction.AssetId, transactionId).Delete(&models.AssetTransaction{})
+
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			continue
+		}
+
+		if assetTransaction.TransactionType == models.ASSET_TRANSACTION_TYPE_PURCHASE {
+			err = Assets.updateAssetPurchaseCostInSession(sess, asset)
+		} else if asset.DepreciationPostedTime > assetTransaction.PeriodStartTime {
+			lastPostedTime := asset.DepreciationPostedTime
+			asset.DepreciationPostedTime = assetTransaction.PeriodStartTime
+			asset.UpdatedUnixTime = now
+
+			_, err = sess.ID(asset.AssetId).Cols("depreciation_posted_time", "updated_unix_time").Where("uid=? AND deleted=? AND depreciation_posted_time=?", uid, false, lastPostedTime).Update(asset)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateAssetTransactionsOfTransaction updates the links between asset and the modified transaction, the purchase cost of asset
+// is updated to the total amount of linked purchase transactions, and the amount and time of depreciation or disposal cannot be changed
+func updateAssetTransactionsOfTransaction(sess *xorm.Session, transaction *models.Transaction, oldTransaction *models.Transaction) error {
+	if transaction.Amount == oldTransaction.Amount && utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
+		return nil
+	}
+
+	var assetTransactions []*models.AssetTransaction
+	err := sess.Where("uid=? AND transaction_id=?", transaction.Uid, transaction.TransactionId).Find(&assetTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(assetTransactions); i++ {
+		assetTransaction := assetTransactions[i]
+
+		if assetTransaction.TransactionType != models.ASSET_TRANSACTION_TYPE_PURCHASE {
+			return errs.ErrAssetPostedTransactionCannotModify
+		}
+
+		if transaction.Amount == assetTransaction.Amount {
+			continue
+		}
+
+		assetTransaction.Amount = transaction.Amount
+		_, err = sess.Cols("amount").Where("uid=? AND asset_id=? AND transaction_id=?", transaction.Uid, assetTransaction.AssetId, transaction.TransactionId).Update(assetTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		asset := &models.Asset{}
+		has, err := sess.ID(assetTransaction.AssetId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(asset)
+
+		if err != nil {
+			return err
+		} else if !has {
+			continue
+		}
+
+		err = Assets.updateAssetPurchaseCostInSession(sess, asset)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
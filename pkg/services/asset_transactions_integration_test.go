@@ -0,0 +1,439 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	testAssetCashAccountId          = int64(11)
+	testAssetBookAccountId          = int64(12)
+	testAssetPurchaseCategoryId     = int64(21)
+	testAssetDepreciationCategoryId = int64(22)
+	testAssetSaleCategoryId         = int64(23)
+)
+
+func seedTestAssetLedger(t *testing.T, tdb *testDB) {
+	t.Helper()
+
+	accounts := []*models.Account{
+		{AccountId: testAssetCashAccountId, Uid: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		{AccountId: testAssetBookAccountId, Uid: 1, Name: "Fixed Assets", Category: models.ACCOUNT_CATEGORY_VIRTUAL, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	}
+
+	for _, account := range accounts {
+		_, err := tdb.engine.Insert(account)
+		assert.Nil(t, err)
+	}
+
+	categories := []*models.TransactionCategory{
+		{CategoryId: testAssetPurchaseCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Equipment", ActivityType: int32(models.ACTIVITY_TYPE_INVESTING)},
+		{CategoryId: testAssetDepreciationCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Depreciation", CostType: int32(models.COST_TYPE_OPERATIONAL)},
+		{CategoryId: testAssetSaleCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Asset Sale", ActivityType: int32(models.ACTIVITY_TYPE_INVESTING)},
+	}
+
+	for _, category := range categories {
+		_, err := tdb.engine.Insert(category)
+		assert.Nil(t, err)
+	}
+}
+
+func insertTestAssetPurchaseTransaction(t *testing.T, tdb *testDB, transactionId int64, transactionType models.TransactionDbType, amount int64, unixTime int64) {
+	t.Helper()
+	_, err := tdb.engine.Insert(&models.Transaction{
+		TransactionId:   transactionId,
+		Uid:             1,
+		Type:            transactionType,
+		CategoryId:      testAssetPurchaseCategoryId,
+		AccountId:       testAssetCashAccountId,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		Amount:          amount,
+	})
+	assert.Nil(t, err)
+}
+
+func createTestLedgerAsset(t *testing.T, svc *AssetService) *models.Asset {
+	t.Helper()
+	asset := &models.Asset{
+		Uid:                    1,
+		Name:                   "Lathe",
+		AssetType:              models.ASSET_TYPE_EQUIPMENT,
+		Status:                 models.ASSET_STATUS_ACTIVE,
+		PurchaseDate:           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		PurchaseCost:           120000,
+		UsefulLifeMonths:       12,
+		DepreciationMethod:     models.ASSET_DEPRECIATION_METHOD_STRAIGHT_LINE,
+		CommissionDate:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		AccountId:              testAssetBookAccountId,
+		DepreciationCategoryId: testAssetDepreciationCategoryId,
+	}
+	assert.Nil(t, svc.CreateAsset(nil, asset))
+	return asset
+}
+
+func getTestAccountBalance(t *testing.T, tdb *testDB, accountId int64) int64 {
+	t.Helper()
+	account := &models.Account{}
+	has, err := tdb.engine.ID(accountId).Get(account)
+	assert.Nil(t, err)
+	assert.True(t, has)
+	return account.Balance
+}
+
+func TestAssetServiceLinkAndUnlinkPurchaseTransactions(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, svc)
+	purchaseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+
+	insertTestAssetPurchaseTransaction(t, tdb, 3001, models.TRANSACTION_DB_TYPE_EXPENSE, 100000, purchaseTime)
+	insertTestAssetPurchaseTransaction(t, tdb, 3002, models.TRANSACTION_DB_TYPE_EXPENSE, 25000, purchaseTime+1)
+	insertTestAssetPurchaseTransaction(t, tdb, 3003, models.TRANSACTION_DB_TYPE_INCOME, 5000, purchaseTime+2)
+
+	linkedAsset, err := svc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3001, 3002})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(125000), linkedAsset.PurchaseCost)
+
+	_, err = svc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3002})
+	assert.Equal(t, errs.ErrAssetTransactionAlreadyLinked, err)
+
+	_, err = svc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3003})
+	assert.Equal(t, errs.ErrAssetPurchaseTransactionTypeInvalid, err)
+
+	_, err = svc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3999})
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	unlinkedAsset, err := svc.UnlinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3002})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100000), unlinkedAsset.PurchaseCost)
+
+	_, err = svc.UnlinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3002})
+	assert.Equal(t, errs.ErrAssetTransactionNotLinked, err)
+
+	assetTransactions, err := svc.GetAssetTransactionsByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(assetTransactions))
+	assert.Equal(t, int64(3001), assetTransactions[0].TransactionId)
+	assert.Equal(t, models.ASSET_TRANSACTION_TYPE_PURCHASE, assetTransactions[0].TransactionType)
+}
+
+func TestAssetServicePostAssetDepreciations(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, svc)
+	untilTime := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC).Unix()
+
+	postedCount, err := svc.postAssetDepreciations(nil, asset, untilTime, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, postedCount)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(), asset.DepreciationPostedTime)
+	assert.Equal(t, int64(-30000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+
+	assetTransactions, err := svc.GetAssetTransactionsByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(assetTransactions))
+
+	for i := 0; i < len(assetTransactions); i++ {
+		assert.Equal(t, models.ASSET_TRANSACTION_TYPE_DEPRECIATION, assetTransactions[i].TransactionType)
+		assert.Equal(t, int64(10000), assetTransactions[i].Amount)
+
+		transaction := &models.Transaction{}
+		has, err := tdb.engine.ID(assetTransactions[i].TransactionId).Get(transaction)
+		assert.Nil(t, err)
+		assert.True(t, has)
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+		assert.Equal(t, testAssetDepreciationCategoryId, transaction.CategoryId)
+		assert.True(t, utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) < assetTransactions[i].PeriodEndTime)
+	}
+
+	// the months already posted are not posted again
+	postedCount, err = svc.postAssetDepreciations(nil, asset, untilTime, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, postedCount)
+	assert.Equal(t, int64(-30000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+}
+
+func TestAssetServiceDisposeAsset(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, svc)
+	disposalTime := time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC).Unix()
+
+	_, err := svc.DisposeAsset(nil, 1, &models.AssetDisposeRequest{
+		Id:           asset.AssetId,
+		Status:       models.ASSET_STATUS_SOLD,
+		DisposalDate: disposalTime,
+		Proceeds:     100000,
+	})
+	assert.Equal(t, errs.ErrAssetDisposalProceedsAccountRequired, err)
+
+	disposedAsset, err := svc.DisposeAsset(nil, 1, &models.AssetDisposeRequest{
+		Id:                 asset.AssetId,
+		Status:             models.ASSET_STATUS_SOLD,
+		DisposalDate:       disposalTime,
+		Proceeds:           100000,
+		ProceedsAccountId:  testAssetCashAccountId,
+		ProceedsCategoryId: testAssetSaleCategoryId,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, models.ASSET_STATUS_SOLD, disposedAsset.Status)
+	assert.Equal(t, disposalTime, disposedAsset.DecommissionDate)
+	assert.Equal(t, disposalTime, disposedAsset.DepreciationPostedTime)
+	assert.Equal(t, int64(100000), disposedAsset.DisposalProceeds)
+	// 3 months and 15 of 30 days of April are depreciated
+	assert.Equal(t, int64(85000), disposedAsset.DisposalBookValue)
+
+	// the depreciation and the write-off of the remaining book value bring the book account to minus purchase cost
+	assert.Equal(t, int64(-120000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+	assert.Equal(t, int64(100000), getTestAccountBalance(t, tdb, testAssetCashAccountId))
+
+	assetTransactions, err := svc.GetAssetTransactionsByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+
+	transactionTypeCounts := make(map[models.AssetTransactionType]int)
+
+	for i := 0; i < len(assetTransactions); i++ {
+		transactionTypeCounts[assetTransactions[i].TransactionType]++
+	}
+
+	assert.Equal(t, 4, transactionTypeCounts[models.ASSET_TRANSACTION_TYPE_DEPRECIATION])
+	assert.Equal(t, 1, transactionTypeCounts[models.ASSET_TRANSACTION_TYPE_DISPOSAL_WRITE_OFF])
+	assert.Equal(t, 1, transactionTypeCounts[models.ASSET_TRANSACTION_TYPE_DISPOSAL_PROCEEDS])
+
+	_, err = svc.DisposeAsset(nil, 1, &models.AssetDisposeRequest{
+		Id:           asset.AssetId,
+		Status:       models.ASSET_STATUS_DECOMMISSIONED,
+		DisposalDate: disposalTime,
+	})
+	assert.Equal(t, errs.ErrAssetAlreadyDisposed, err)
+}
+
+func TestReportServiceGetPnL_WithAssetTransactions(t *testing.T) {
+	assetSvc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, assetSvc)
+
+	insertTestAssetPurchaseTransaction(t, tdb, 3001, models.TRANSACTION_DB_TYPE_EXPENSE, 120000, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).Unix())
+
+	_, err := assetSvc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3001})
+	assert.Nil(t, err)
+
+	_, err = assetSvc.DisposeAsset(nil, 1, &models.AssetDisposeRequest{
+		Id:                 asset.AssetId,
+		Status:             models.ASSET_STATUS_SOLD,
+		DisposalDate:       time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Proceeds:           95000,
+		ProceedsAccountId:  testAssetCashAccountId,
+		ProceedsCategoryId: testAssetSaleCategoryId,
+	})
+	assert.Nil(t, err)
+
+	reportSvc := &ReportService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+		assets:         assetSvc,
+		taxes:          &mockTaxRecordProvider{},
+		deals:          &mockInvestorDealProvider{},
+		payments:       &mockInvestorPaymentProvider{},
+	}
+
+	startTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	endTime := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	pnl, err := reportSvc.GetPnL(nil, 1, 0, startTime, endTime, nil)
+	assert.Nil(t, err)
+
+	// the purchase is capitalized, and the proceeds and the write-off are reported as disposal gain
+	assert.Equal(t, int64(0), pnl.Revenue)
+	assert.Equal(t, int64(0), pnl.OperatingExpense)
+	assert.Equal(t, int64(30000), pnl.Depreciation)
+	assert.Equal(t, int64(5000), pnl.DisposalGainLoss)
+	assert.Equal(t, int64(-25000), pnl.NetProfit)
+
+	cashFlow, err := reportSvc.GetCashFlow(nil, 1, 0, startTime, endTime, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(95000-120000), cashFlow.TotalNet)
+}
+
+func TestAssetServiceDeleteAndModifyLinkedPurchaseTransactions(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, svc)
+	purchaseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+
+	insertTestAssetPurchaseTransaction(t, tdb, 3001, models.TRANSACTION_DB_TYPE_EXPENSE, 100000, purchaseTime)
+	insertTestAssetPurchaseTransaction(t, tdb, 3002, models.TRANSACTION_DB_TYPE_EXPENSE, 25000, purchaseTime+1)
+
+	_, err := svc.LinkPurchaseTransactions(nil, 1, asset.AssetId, []int64{3001, 3002})
+	assert.Nil(t, err)
+
+	transactionSvc := &TransactionService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: svc.ServiceUsingUuid,
+	}
+
+	// Changing the amount of purchase transaction updates the purchase cost
+	transaction := &models.Transaction{}
+	_, err = tdb.engine.ID(3002).Get(transaction)
+	assert.Nil(t, err)
+
+	transaction.Amount = 20000
+	err = transactionSvc.ModifyTransaction(nil, transaction, 0, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	got, err := svc.GetAssetByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(120000), got.PurchaseCost)
+
+	// Deleting purchase transaction unlinks it and updates the purchase cost
+	err = transactionSvc.DeleteTransaction(nil, 1, 3001)
+	assert.Nil(t, err)
+
+	got, err = svc.GetAssetByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20000), got.PurchaseCost)
+
+	assetTransactions, err := svc.GetAssetTransactionsByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(assetTransactions))
+	assert.Equal(t, int64(3002), assetTransactions[0].TransactionId)
+	assert.Equal(t, int64(20000), assetTransactions[0].Amount)
+}
+
+func TestAssetServiceDeleteAndModifyPostedDepreciationTransactions(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := createTestLedgerAsset(t, svc)
+	untilTime := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC).Unix()
+
+	_, err := svc.postAssetDepreciations(nil, asset, untilTime, time.UTC)
+	assert.Nil(t, err)
+
+	assetTransactions, err := svc.GetAssetTransactionsByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(assetTransactions))
+
+	transactionSvc := &TransactionService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: svc.ServiceUsingUuid,
+	}
+
+	var firstMonth, lastMonth *models.AssetTransaction
+
+	for i := 0; i < len(assetTransactions); i++ {
+		if firstMonth == nil || assetTransactions[i].PeriodStartTime < firstMonth.PeriodStartTime {
+			firstMonth = assetTransactions[i]
+		}
+
+		if lastMonth == nil || assetTransactions[i].PeriodStartTime > lastMonth.PeriodStartTime {
+			lastMonth = assetTransactions[i]
+		}
+	}
+
+	// The amount of posted depreciation cannot be changed
+	transaction := &models.Transaction{}
+	_, err = tdb.engine.ID(lastMonth.TransactionId).Get(transaction)
+	assert.Nil(t, err)
+
+	transaction.Amount = 5000
+	err = transactionSvc.ModifyTransaction(nil, transaction, 0, nil, nil, nil, nil)
+	assert.Equal(t, errs.ErrAssetPostedTransactionCannotModify, err)
+
+	// Only the latest depreciation can be deleted
+	err = transactionSvc.DeleteTransaction(nil, 1, firstMonth.TransactionId)
+	assert.Equal(t, errs.ErrAssetDepreciationNotLatest, err)
+
+	err = transactionSvc.DeleteTransaction(nil, 1, lastMonth.TransactionId)
+	assert.Nil(t, err)
+
+	got, err := svc.GetAssetByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).Unix(), got.DepreciationPostedTime)
+	assert.Equal(t, int64(-20000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+
+	// The month of the deleted depreciation is posted again
+	postedCount, err := svc.postAssetDepreciations(nil, got, untilTime, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, postedCount)
+	assert.Equal(t, int64(-30000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+}
+
+func TestAssetServicePostUnitsOfProductionDepreciations(t *testing.T) {
+	svc, tdb := newTestAssetService(t)
+	defer tdb.close()
+
+	seedTestAssetLedger(t, tdb)
+	asset := &models.Asset{
+		Uid:                    1,
+		Name:                   "Solar Panels",
+		AssetType:              models.ASSET_TYPE_EQUIPMENT,
+		Status:                 models.ASSET_STATUS_ACTIVE,
+		PurchaseCost:           120000,
+		UsefulLifeMonths:       12,
+		DepreciationMethod:     models.ASSET_DEPRECIATION_METHOD_UNITS_OF_PRODUCTION,
+		InstalledCapacityWatts: 1200,
+		CommissionDate:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		AccountId:              testAssetBookAccountId,
+		DepreciationCategoryId: testAssetDepreciationCategoryId,
+	}
+	assert.Nil(t, svc.CreateAsset(nil, asset))
+
+	_, err := svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2025, Month: 1, Output: 100})
+	assert.Nil(t, err)
+	_, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2025, Month: 3, Output: 300})
+	assert.Nil(t, err)
+
+	// the output of the same month is replaced
+	asset, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2025, Month: 1, Output: 200})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(asset.ProductionOutputs))
+	assert.Equal(t, int64(200), asset.ProductionOutputs[0].Output)
+
+	// the month out of useful life is rejected
+	_, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2026, Month: 1, Output: 100})
+	assert.Equal(t, errs.ErrAssetProductionOutputPeriodInvalid, err)
+
+	// the depreciation is posted only until the first month without output
+	err = svc.PostAssetDepreciations(nil, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	asset, err = svc.GetAssetByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix(), asset.DepreciationPostedTime)
+	assert.Equal(t, int64(-20000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+
+	// the output of posted month cannot be changed
+	_, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2025, Month: 1, Output: 100})
+	assert.Equal(t, errs.ErrAssetProductionOutputAlreadyPosted, err)
+
+	_, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: asset.AssetId, Year: 2025, Month: 2, Output: 0})
+	assert.Nil(t, err)
+
+	err = svc.PostAssetDepreciations(nil, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	asset, err = svc.GetAssetByAssetId(nil, 1, asset.AssetId)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC).Unix(), asset.DepreciationPostedTime)
+	assert.Equal(t, int64(-50000), getTestAccountBalance(t, tdb, testAssetBookAccountId))
+
+	// the output can only be saved for units-of-production asset
+	straightLineAsset := createTestLedgerAsset(t, svc)
+	_, err = svc.SaveAssetProductionOutput(nil, 1, &models.AssetProductionOutputSaveRequest{Id: straightLineAsset.AssetId, Year: 2025, Month: 1, Output: 100})
+	assert.Equal(t, errs.ErrAssetProductionOutputMethodInvalid, err)
+}
@@ -1,5 +1,6 @@
 // assets.go provides CRUD operations for fixed assets with depreciation tracking.
-// Residual values use straight-line depreciation.
+// Residual values use the depreciation method of each asset, see asset_depreciations.go,
+// and the purchase, depreciation and disposal transactions of assets are linked in asset_transactions.go.
 package services
 
 import (
@@ -135,7 +136,7 @@ func (s *AssetService) ModifyAsset(c core.Context, asset *models.Asset, nameChan
 	asset.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(asset.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(asset.AssetId).Cols("name", "cfo_id", "location_id", "asset_type", "purchase_date", "purchase_cost", "useful_life_months", "salvage_value", "status", "commission_date", "decommission_date", "installed_capacity_watts", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", asset.Uid, false).Update(asset)
+		updatedRows, err := sess.ID(asset.AssetId).Cols("name", "cfo_id", "location_id", "asset_type", "purchase_date", "purchase_cost", "useful_life_months", "salvage_value", "depreciation_method", "status", "commission_date", "decommission_date", "installed_capacity_watts", "account_id", "depreciation_category_id", "timezone_utc_offset", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", asset.Uid, false).Update(asset)
 
 		if err != nil {
 			return err
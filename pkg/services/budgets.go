@@ -31,14 +31,14 @@ var (
 	}
 )
 
-// GetBudgetsByYearMonth returns budget models for given year+month+cfo
-func (s *BudgetService) GetBudgetsByYearMonth(c core.Context, uid int64, year int32, month int32, cfoId int64) ([]*models.Budget, error) {
+// GetBudgetsByYearMonth returns budget models for given year+month+cfo of the given version
+func (s *BudgetService) GetBudgetsByYearMonth(c core.Context, uid int64, year int32, month int32, cfoId int64, version string) ([]*models.Budget, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var budgets []*models.Budget
-	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND year=? AND month=?", uid, false, year, month)
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND year=? AND month=? AND version=?", uid, false, year, month, version)
 
 	if cfoId > 0 {
 		sess = sess.And("cfo_id=?", cfoId)
@@ -49,88 +49,121 @@ func (s *BudgetService) GetBudgetsByYearMonth(c core.Context, uid int64, year in
 	return budgets, err
 }
 
-// SaveBudgets saves budgets in bulk (upsert for year+month+cfoId+categoryId)
-func (s *BudgetService) SaveBudgets(c core.Context, uid int64, year int32, month int32, cfoId int64, items []*models.BudgetItemRequest) error {
+// SaveBudgets saves budgets of the given version in bulk (upsert for year+month+cfoId+categoryId)
+func (s *BudgetService) SaveBudgets(c core.Context, uid int64, year int32, month int32, cfoId int64, version string, items []*models.BudgetItemRequest) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get existing budgets for this period
-		var existing []*models.Budget
-		existQuery := sess.Where("uid=? AND deleted=? AND year=? AND month=? AND cfo_id=?", uid, false, year, month, cfoId)
-		err := existQuery.Find(&existing)
+		return s.SaveBudgetsInSession(c, sess, uid, year, month, cfoId, version, items)
+	})
+}
+
+// SaveBudgetsInSession saves budgets of the given version in bulk in the given session
+func (s *BudgetService) SaveBudgetsInSession(c core.Context, sess *xorm.Session, uid int64, year int32, month int32, cfoId int64, version string, items []*models.BudgetItemRequest) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	budgets := make([]*models.Budget, len(items))
 
-		if err != nil {
-			return err
+	for i, item := range items {
+		budgets[i] = &models.Budget{
+			CfoId:         cfoId,
+			CategoryId:    item.CategoryId,
+			PlannedAmount: item.PlannedAmount,
+			Comment:       item.Comment,
 		}
+	}
+
+	return s.upsertBudgets(sess, uid, year, month, version, budgets, time.Now().Unix())
+}
+
+// upsertBudgets updates the existing budgets matching cfoId+categoryId of given year+month+version and creates the others
+func (s *BudgetService) upsertBudgets(sess *xorm.Session, uid int64, year int32, month int32, version string, budgets []*models.Budget, now int64) error {
+	// Get existing budgets for this period
+	var existing []*models.Budget
+	err := sess.Where("uid=? AND deleted=? AND year=? AND month=? AND version=?", uid, false, year, month, version).Find(&existing)
+
+	if err != nil {
+		return err
+	}
 
-		// Map existing by categoryId
-		existingMap := make(map[int64]*models.Budget)
-		for _, b := range existing {
-			existingMap[b.CategoryId] = b
+	// Map existing by cfoId and categoryId
+	existingMap := make(map[int64]map[int64]*models.Budget)
+	for _, b := range existing {
+		if _, exists := existingMap[b.CfoId]; !exists {
+			existingMap[b.CfoId] = make(map[int64]*models.Budget)
 		}
 
-		now := time.Now().Unix()
-
-		for _, item := range items {
-			if existBudget, ok := existingMap[item.CategoryId]; ok {
-				// Update existing
-				existBudget.PlannedAmount = item.PlannedAmount
-				existBudget.Comment = item.Comment
-				existBudget.UpdatedUnixTime = now
-
-				_, err := sess.ID(existBudget.BudgetId).Cols("planned_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(existBudget)
-				if err != nil {
-					return err
-				}
-			} else {
-				// Create new
-				newBudget := &models.Budget{
-					BudgetId:        s.GenerateUuid(uuid.UUID_TYPE_DEFAULT),
-					Uid:             uid,
-					Deleted:         false,
-					CfoId:           cfoId,
-					CategoryId:      item.CategoryId,
-					Year:            year,
-					Month:           month,
-					PlannedAmount:   item.PlannedAmount,
-					Comment:         item.Comment,
-					CreatedUnixTime: now,
-					UpdatedUnixTime: now,
-				}
-
-				if newBudget.BudgetId < 1 {
-					return errs.ErrSystemIsBusy
-				}
-
-				_, err := sess.Insert(newBudget)
-				if err != nil {
-					return err
-				}
+		existingMap[b.CfoId][b.CategoryId] = b
+	}
+
+	for _, budget := range budgets {
+		if existBudget, ok := existingMap[budget.CfoId][budget.CategoryId]; ok {
+			// Update existing
+			existBudget.PlannedAmount = budget.PlannedAmount
+			existBudget.Comment = budget.Comment
+			existBudget.UpdatedUnixTime = now
+
+			_, err := sess.ID(existBudget.BudgetId).Cols("planned_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(existBudget)
+			if err != nil {
+				return err
+			}
+		} else {
+			// Create new
+			newBudget := &models.Budget{
+				BudgetId:        s.GenerateUuid(uuid.UUID_TYPE_DEFAULT),
+				Uid:             uid,
+				Deleted:         false,
+				CfoId:           budget.CfoId,
+				CategoryId:      budget.CategoryId,
+				Year:            year,
+				Month:           month,
+				Version:         version,
+				PlannedAmount:   budget.PlannedAmount,
+				Comment:         budget.Comment,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
 			}
+
+			if newBudget.BudgetId < 1 {
+				return errs.ErrSystemIsBusy
+			}
+
+			_, err := sess.Insert(newBudget)
+			if err != nil {
+				return err
+			}
+
+			if _, exists := existingMap[newBudget.CfoId]; !exists {
+				existingMap[newBudget.CfoId] = make(map[int64]*models.Budget)
+			}
+
+			existingMap[newBudget.CfoId][newBudget.CategoryId] = newBudget
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
-// GetFactAmountsByYearMonth returns fact (actual) amounts grouped by categoryId for given period
+// GetFactAmountsByYearMonth returns fact (actual) amounts grouped by categoryId for given period, planned transactions are excluded
 func (s *BudgetService) GetFactAmountsByYearMonth(c core.Context, uid int64, startTime int64, endTime int64, cfoId int64) (map[int64]int64, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	type FactResult struct {
-		CategoryId int64 `xorm:"category_id"`
+		CategoryId  int64 `xorm:"category_id"`
 		TotalAmount int64 `xorm:"total_amount"`
 	}
 
 	var results []FactResult
 	sess := s.UserDataDB(uid).NewSession(c).
-		Table("\"transaction\"").
+		Table(new(models.Transaction)).
 		Select("category_id, SUM(amount) as total_amount").
-		Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<?", uid, false, startTime, endTime)
+		Where("uid=? AND deleted=? AND planned=? AND transaction_time>=? AND transaction_time<?", uid, false, false, startTime, endTime)
 
 	if cfoId > 0 {
 		sess = sess.And("cfo_id=?", cfoId)
@@ -0,0 +1,284 @@
+// budgets_bulk.go provides bulk budget planning operations: copying, spreading annual amounts, deriving from actuals and versions.
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const maxBudgetCopyMonths = 120
+
+// CopyBudgets copies the budget of the source month into every month of the target range and returns the count of saved budget lines.
+// Budget lines of all CFOs are copied if cfoId is not set. Existing lines of the target months are overwritten, other lines are kept.
+// The source month itself is skipped unless the target version differs, which allows saving a snapshot of a budget as a named version.
+func (s *BudgetService) CopyBudgets(c core.Context, uid int64, req *models.BudgetCopyRequest) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	sourceMonthIndex := getBudgetMonthIndex(req.SourceYear, req.SourceMonth)
+	startMonthIndex := getBudgetMonthIndex(req.TargetStartYear, req.TargetStartMonth)
+	endMonthIndex := getBudgetMonthIndex(req.TargetEndYear, req.TargetEndMonth)
+
+	if endMonthIndex < startMonthIndex || endMonthIndex-startMonthIndex >= maxBudgetCopyMonths {
+		return 0, errs.ErrBudgetMonthRangeInvalid
+	}
+
+	sourceBudgets, err := s.GetBudgetsByYearMonth(c, uid, req.SourceYear, req.SourceMonth, req.CfoId, req.SourceVersion)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(sourceBudgets) < 1 {
+		return 0, errs.ErrBudgetCopySourceEmpty
+	}
+
+	count := 0
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		now := time.Now().Unix()
+
+		for monthIndex := startMonthIndex; monthIndex <= endMonthIndex; monthIndex++ {
+			if monthIndex == sourceMonthIndex && req.TargetVersion == req.SourceVersion {
+				continue
+			}
+
+			year, month := getBudgetYearMonth(monthIndex)
+			err := s.upsertBudgets(sess, uid, year, month, req.TargetVersion, sourceBudgets, now)
+
+			if err != nil {
+				return err
+			}
+
+			count += len(sourceBudgets)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SpreadAnnualBudgets spreads the annual amount of every budget line over the months of the year and returns the count of saved budget lines.
+// The amount is spread evenly if no weights are set, otherwise in proportion to the seasonality weights of each month.
+func (s *BudgetService) SpreadAnnualBudgets(c core.Context, uid int64, req *models.BudgetSpreadRequest) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	weights := req.Weights
+
+	if len(weights) == 0 {
+		weights = []int64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	}
+
+	monthlyBudgets := make([][]*models.Budget, 12)
+
+	for _, item := range req.Budgets {
+		amounts, err := spreadBudgetAmount(item.AnnualAmount, weights)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for i := 0; i < len(monthlyBudgets); i++ {
+			monthlyBudgets[i] = append(monthlyBudgets[i], &models.Budget{
+				CfoId:         req.CfoId,
+				CategoryId:    item.CategoryId,
+				PlannedAmount: amounts[i],
+				Comment:       item.Comment,
+			})
+		}
+	}
+
+	count := 0
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		now := time.Now().Unix()
+
+		for i, budgets := range monthlyBudgets {
+			err := s.upsertBudgets(sess, uid, req.Year, int32(i+1), req.Version, budgets, now)
+
+			if err != nil {
+				return err
+			}
+
+			count += len(budgets)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CreateBudgetsFromActuals saves the budget of the given month as the average actual amount of every category over the previous months
+// and returns the budgets of the month. Month boundaries are calculated in the timezone of the given utc offset (in minutes).
+func (s *BudgetService) CreateBudgetsFromActuals(c core.Context, uid int64, req *models.BudgetFromActualsRequest) ([]*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if req.Months < 1 {
+		return nil, errs.ErrBudgetMonthRangeInvalid
+	}
+
+	location := time.FixedZone("", int(req.UtcOffset)*60)
+	targetMonthIndex := getBudgetMonthIndex(req.Year, req.Month)
+	totalAmounts := make(map[int64]int64)
+
+	for monthIndex := targetMonthIndex - req.Months; monthIndex < targetMonthIndex; monthIndex++ {
+		year, month := getBudgetYearMonth(monthIndex)
+		startTime, endTime := getBudgetMonthTimeRange(year, month, location)
+		factAmounts, err := s.GetFactAmountsByYearMonth(c, uid, startTime, endTime, req.CfoId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for categoryId, amount := range factAmounts {
+			if categoryId > 0 {
+				totalAmounts[categoryId] += amount
+			}
+		}
+	}
+
+	if len(totalAmounts) < 1 {
+		return nil, errs.ErrBudgetActualAmountsNotFound
+	}
+
+	budgets := make([]*models.Budget, 0, len(totalAmounts))
+	comment := fmt.Sprintf("Average of %d months", req.Months)
+
+	for categoryId, totalAmount := range totalAmounts {
+		budgets = append(budgets, &models.Budget{
+			CfoId:         req.CfoId,
+			CategoryId:    categoryId,
+			PlannedAmount: int64(math.Round(float64(totalAmount) / float64(req.Months))),
+			Comment:       comment,
+		})
+	}
+
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].CategoryId < budgets[j].CategoryId
+	})
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.upsertBudgets(sess, uid, req.Year, req.Month, req.Version, budgets, time.Now().Unix())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBudgetsByYearMonth(c, uid, req.Year, req.Month, req.CfoId, req.Version)
+}
+
+// GetBudgetVersions returns all budget versions with their budget line counts, optionally filtered by year and CFO.
+// The working version has an empty name.
+func (s *BudgetService) GetBudgetVersions(c core.Context, uid int64, year int32, cfoId int64) ([]*models.BudgetVersionInfoResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	type VersionResult struct {
+		Version     string `xorm:"version"`
+		BudgetCount int64  `xorm:"budget_count"`
+	}
+
+	var results []VersionResult
+	sess := s.UserDataDB(uid).NewSession(c).
+		Table(new(models.Budget)).
+		Select("version, COUNT(*) as budget_count").
+		Where("uid=? AND deleted=?", uid, false)
+
+	if year > 0 {
+		sess = sess.And("year=?", year)
+	}
+
+	if cfoId > 0 {
+		sess = sess.And("cfo_id=?", cfoId)
+	}
+
+	err := sess.GroupBy("version").OrderBy("version asc").Find(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*models.BudgetVersionInfoResponse, len(results))
+
+	for i, result := range results {
+		versions[i] = &models.BudgetVersionInfoResponse{
+			Version:     result.Version,
+			BudgetCount: result.BudgetCount,
+		}
+	}
+
+	return versions, nil
+}
+
+// spreadBudgetAmount splits the amount in proportion to the weights, the rounded parts always add up to the amount
+func spreadBudgetAmount(amount int64, weights []int64) ([]int64, error) {
+	totalWeight := int64(0)
+
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, errs.ErrBudgetSpreadWeightsInvalid
+		}
+
+		totalWeight += weight
+	}
+
+	if totalWeight <= 0 {
+		return nil, errs.ErrBudgetSpreadWeightsInvalid
+	}
+
+	amounts := make([]int64, len(weights))
+	cumulativeWeight := int64(0)
+	allocatedAmount := int64(0)
+
+	for i, weight := range weights {
+		cumulativeWeight += weight
+		cumulativeAmount := int64(math.Round(float64(amount) * float64(cumulativeWeight) / float64(totalWeight)))
+		amounts[i] = cumulativeAmount - allocatedAmount
+		allocatedAmount = cumulativeAmount
+	}
+
+	return amounts, nil
+}
+
+// getBudgetMonthIndex returns the sequential number of the month which makes month arithmetic simple
+func getBudgetMonthIndex(year int32, month int32) int32 {
+	return year*12 + month - 1
+}
+
+// getBudgetYearMonth returns the year and month of the sequential month number
+func getBudgetYearMonth(monthIndex int32) (int32, int32) {
+	return monthIndex / 12, monthIndex%12 + 1
+}
+
+// getBudgetMonthTimeRange returns the transaction time range [start, end) of the month in the location
+func getBudgetMonthTimeRange(year int32, month int32, location *time.Location) (int64, int64) {
+	startTime := time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, location)
+	endTime := startTime.AddDate(0, 1, 0)
+
+	return startTime.UnixMilli(), endTime.UnixMilli()
+}
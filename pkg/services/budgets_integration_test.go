@@ -29,10 +29,10 @@ func TestBudgetServiceSaveAndGet(t *testing.T) {
 		{CategoryId: 200, PlannedAmount: 30000, Comment: "Rent"},
 	}
 
-	err := svc.SaveBudgets(nil, 1, 2025, 6, 0, items)
+	err := svc.SaveBudgets(nil, 1, 2025, 6, 0, "", items)
 	assert.Nil(t, err)
 
-	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 6, 0)
+	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 6, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(budgets))
 
@@ -56,16 +56,16 @@ func TestBudgetServiceSaveUpsert(t *testing.T) {
 	items := []*models.BudgetItemRequest{
 		{CategoryId: 100, PlannedAmount: 50000, Comment: "Initial"},
 	}
-	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 7, 0, items))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 7, 0, "", items))
 
 	// Update with upsert
 	updatedItems := []*models.BudgetItemRequest{
 		{CategoryId: 100, PlannedAmount: 75000, Comment: "Updated"},
 		{CategoryId: 200, PlannedAmount: 20000, Comment: "New line"},
 	}
-	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 7, 0, updatedItems))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 7, 0, "", updatedItems))
 
-	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 7, 0)
+	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 7, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(budgets))
 
@@ -84,7 +84,7 @@ func TestBudgetServiceGetByYearMonthEmpty(t *testing.T) {
 	svc, tdb := newTestBudgetService(t)
 	defer tdb.close()
 
-	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 12, 0)
+	budgets, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 12, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(budgets))
 }
@@ -93,10 +93,10 @@ func TestBudgetServiceInvalidUid(t *testing.T) {
 	svc, tdb := newTestBudgetService(t)
 	defer tdb.close()
 
-	_, err := svc.GetBudgetsByYearMonth(nil, 0, 2025, 6, 0)
+	_, err := svc.GetBudgetsByYearMonth(nil, 0, 2025, 6, 0, "")
 	assert.Equal(t, errs.ErrUserIdInvalid, err)
 
-	err = svc.SaveBudgets(nil, 0, 2025, 6, 0, []*models.BudgetItemRequest{})
+	err = svc.SaveBudgets(nil, 0, 2025, 6, 0, "", []*models.BudgetItemRequest{})
 	assert.Equal(t, errs.ErrUserIdInvalid, err)
 }
 
@@ -111,15 +111,15 @@ func TestBudgetServiceUserIsolation(t *testing.T) {
 		{CategoryId: 100, PlannedAmount: 60000, Comment: "user2budget"},
 	}
 
-	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 8, 0, items1))
-	assert.Nil(t, svc.SaveBudgets(nil, 2, 2025, 8, 0, items2))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 8, 0, "", items1))
+	assert.Nil(t, svc.SaveBudgets(nil, 2, 2025, 8, 0, "", items2))
 
-	list1, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 8, 0)
+	list1, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 8, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(list1))
 	assert.Equal(t, "user1budget", list1[0].Comment)
 
-	list2, err := svc.GetBudgetsByYearMonth(nil, 2, 2025, 8, 0)
+	list2, err := svc.GetBudgetsByYearMonth(nil, 2, 2025, 8, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(list2))
 	assert.Equal(t, "user2budget", list2[0].Comment)
@@ -136,17 +136,249 @@ func TestBudgetServiceFilterByCfoId(t *testing.T) {
 		{CategoryId: 100, PlannedAmount: 20000, Comment: "cfo2"},
 	}
 
-	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 9, 10, items1))
-	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 9, 20, items2))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 9, 10, "", items1))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 9, 20, "", items2))
 
 	// Filter by cfoId=10
-	list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 9, 10)
+	list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 9, 10, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(list))
 	assert.Equal(t, "cfo1", list[0].Comment)
 
 	// Get all (cfoId=0 means no filter)
-	all, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 9, 0)
+	all, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 9, 0, "")
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(all))
 }
+
+func TestBudgetServiceVersions(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 10, 0, "", []*models.BudgetItemRequest{{CategoryId: 100, PlannedAmount: 10000}}))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 10, 0, "original", []*models.BudgetItemRequest{{CategoryId: 100, PlannedAmount: 8000}, {CategoryId: 200, PlannedAmount: 500}}))
+
+	list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 10, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, int64(10000), list[0].PlannedAmount)
+
+	list, err = svc.GetBudgetsByYearMonth(nil, 1, 2025, 10, 0, "original")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+
+	versions, err := svc.GetBudgetVersions(nil, 1, 2025, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(versions))
+	assert.Equal(t, "", versions[0].Version)
+	assert.Equal(t, int64(1), versions[0].BudgetCount)
+	assert.Equal(t, "original", versions[1].Version)
+	assert.Equal(t, int64(2), versions[1].BudgetCount)
+
+	versions, err = svc.GetBudgetVersions(nil, 1, 2024, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(versions))
+}
+
+func TestBudgetServiceCopyBudgets(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 1, 10, "", []*models.BudgetItemRequest{{CategoryId: 100, PlannedAmount: 10000, Comment: "Rent"}}))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 1, 20, "", []*models.BudgetItemRequest{{CategoryId: 100, PlannedAmount: 20000}}))
+	assert.Nil(t, svc.SaveBudgets(nil, 1, 2025, 3, 10, "", []*models.BudgetItemRequest{{CategoryId: 100, PlannedAmount: 1}, {CategoryId: 300, PlannedAmount: 300}}))
+
+	// Copy January to January..April, January itself is skipped
+	count, err := svc.CopyBudgets(nil, 1, &models.BudgetCopyRequest{
+		SourceYear:       2025,
+		SourceMonth:      1,
+		TargetStartYear:  2025,
+		TargetStartMonth: 1,
+		TargetEndYear:    2025,
+		TargetEndMonth:   4,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 6, count)
+
+	list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 1, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+
+	// Existing lines are overwritten and other lines are kept
+	list, err = svc.GetBudgetsByYearMonth(nil, 1, 2025, 3, 10, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+
+	budgetMap := make(map[int64]*models.Budget)
+	for _, b := range list {
+		budgetMap[b.CategoryId] = b
+	}
+
+	assert.Equal(t, int64(10000), budgetMap[100].PlannedAmount)
+	assert.Equal(t, "Rent", budgetMap[100].Comment)
+	assert.Equal(t, int64(300), budgetMap[300].PlannedAmount)
+
+	list, err = svc.GetBudgetsByYearMonth(nil, 1, 2025, 4, 20, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, int64(20000), list[0].PlannedAmount)
+
+	// Copy a snapshot of March of one CFO into a named version
+	count, err = svc.CopyBudgets(nil, 1, &models.BudgetCopyRequest{
+		SourceYear:       2025,
+		SourceMonth:      3,
+		CfoId:            10,
+		TargetStartYear:  2025,
+		TargetStartMonth: 3,
+		TargetEndYear:    2025,
+		TargetEndMonth:   3,
+		TargetVersion:    "original",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	list, err = svc.GetBudgetsByYearMonth(nil, 1, 2025, 3, 0, "original")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+}
+
+func TestBudgetServiceCopyBudgets_Invalid(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+
+	req := &models.BudgetCopyRequest{
+		SourceYear:       2025,
+		SourceMonth:      1,
+		TargetStartYear:  2025,
+		TargetStartMonth: 5,
+		TargetEndYear:    2025,
+		TargetEndMonth:   4,
+	}
+
+	_, err := svc.CopyBudgets(nil, 1, req)
+	assert.Equal(t, errs.ErrBudgetMonthRangeInvalid, err)
+
+	req.TargetEndYear = 2036
+	_, err = svc.CopyBudgets(nil, 1, req)
+	assert.Equal(t, errs.ErrBudgetMonthRangeInvalid, err)
+
+	req.TargetEndYear = 2026
+	_, err = svc.CopyBudgets(nil, 1, req)
+	assert.Equal(t, errs.ErrBudgetCopySourceEmpty, err)
+}
+
+func TestBudgetServiceSpreadAnnualBudgets(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+
+	count, err := svc.SpreadAnnualBudgets(nil, 1, &models.BudgetSpreadRequest{
+		Year:  2025,
+		CfoId: 10,
+		Budgets: []*models.BudgetAnnualItemRequest{
+			{CategoryId: 100, AnnualAmount: 100000, Comment: "Annual"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 12, count)
+
+	total := int64(0)
+	for month := int32(1); month <= 12; month++ {
+		list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, month, 10, "")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+		assert.Equal(t, "Annual", list[0].Comment)
+		assert.InDelta(t, 8333, list[0].PlannedAmount, 1)
+		total += list[0].PlannedAmount
+	}
+	assert.Equal(t, int64(100000), total)
+
+	// Seasonality weights make December the main month
+	_, err = svc.SpreadAnnualBudgets(nil, 1, &models.BudgetSpreadRequest{
+		Year:    2025,
+		CfoId:   10,
+		Weights: []int64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 9},
+		Budgets: []*models.BudgetAnnualItemRequest{
+			{CategoryId: 100, AnnualAmount: 100000},
+		},
+	})
+	assert.Nil(t, err)
+
+	list, err := svc.GetBudgetsByYearMonth(nil, 1, 2025, 12, 10, "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(45000), list[0].PlannedAmount)
+
+	_, err = svc.SpreadAnnualBudgets(nil, 1, &models.BudgetSpreadRequest{
+		Year:    2025,
+		Weights: []int64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		Budgets: []*models.BudgetAnnualItemRequest{
+			{CategoryId: 100, AnnualAmount: 100000},
+		},
+	})
+	assert.Equal(t, errs.ErrBudgetSpreadWeightsInvalid, err)
+}
+
+func TestSpreadBudgetAmount(t *testing.T) {
+	amounts, err := spreadBudgetAmount(100, []int64{1, 1, 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{33, 34, 33}, amounts)
+
+	amounts, err = spreadBudgetAmount(-100, []int64{1, 0, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{-25, 0, -75}, amounts)
+
+	_, err = spreadBudgetAmount(100, []int64{1, -1})
+	assert.Equal(t, errs.ErrBudgetSpreadWeightsInvalid, err)
+}
+
+func TestGetBudgetYearMonth(t *testing.T) {
+	year, month := getBudgetYearMonth(getBudgetMonthIndex(2025, 1) - 1)
+	assert.Equal(t, int32(2024), year)
+	assert.Equal(t, int32(12), month)
+
+	year, month = getBudgetYearMonth(getBudgetMonthIndex(2025, 11) + 3)
+	assert.Equal(t, int32(2026), year)
+	assert.Equal(t, int32(2), month)
+}
+
+func TestBudgetServiceCreateBudgetsFromActuals(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+
+	// 2025-03-01 00:30 at UTC+3 is still February in UTC
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, CfoId: 10, TransactionTime: 1738368000000, Amount: 30000},
+		{TransactionId: 2, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, CfoId: 10, TransactionTime: 1740778200000, Amount: 10000},
+		{TransactionId: 3, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 200, CfoId: 10, TransactionTime: 1740787200000, Amount: 5000},
+		// transactions of another CFO and before the range are excluded
+		{TransactionId: 4, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, CfoId: 20, TransactionTime: 1740787201000, Amount: 70000},
+		{TransactionId: 5, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, CfoId: 10, TransactionTime: 1735689600000, Amount: 70000},
+	}
+	for _, transaction := range transactions {
+		_, err := tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	budgets, err := svc.CreateBudgetsFromActuals(nil, 1, &models.BudgetFromActualsRequest{
+		Year:      2025,
+		Month:     4,
+		CfoId:     10,
+		Version:   "forecast",
+		Months:    2,
+		UtcOffset: 180,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(budgets))
+
+	budgetMap := make(map[int64]*models.Budget)
+	for _, b := range budgets {
+		budgetMap[b.CategoryId] = b
+	}
+
+	assert.Equal(t, int64(20000), budgetMap[100].PlannedAmount)
+	assert.Equal(t, int64(2500), budgetMap[200].PlannedAmount)
+	assert.Equal(t, int64(10), budgetMap[100].CfoId)
+	assert.Equal(t, "forecast", budgetMap[100].Version)
+
+	_, err = svc.CreateBudgetsFromActuals(nil, 1, &models.BudgetFromActualsRequest{Year: 2025, Month: 1, Months: 1})
+	assert.Equal(t, errs.ErrBudgetActualAmountsNotFound, err)
+}
@@ -0,0 +1,493 @@
+// budgets_plan_fact.go provides plan-fact analysis of multi-month periods by month and by CFO.
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const maxPlanFactMonths = 36
+
+// planFactTableLine represents planned and fact amounts of a category in every column of plan-fact table
+type planFactTableLine struct {
+	categoryId int64
+	planned    []int64
+	fact       []int64
+	rollup     bool
+}
+
+// GetPlanFactByMonths returns plan-fact analysis of the period with a column for every month, cumulative amounts and totals.
+// Months of fiscal periods begin at the month of the fiscal year start, every month begins on the day of the fiscal year start,
+// and month boundaries are calculated in the location.
+// Amounts of sub-categories are rolled up to their parent categories.
+func (s *BudgetService) GetPlanFactByMonths(c core.Context, uid int64, req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart, location *time.Location) (*models.PlanFactTableResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	startMonthIndex, endMonthIndex, err := getPlanFactMonthRange(req, fiscalYearStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.getBudgetsByMonthRange(c, uid, startMonthIndex, endMonthIndex, req.CfoId, req.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	columnCount := int(endMonthIndex-startMonthIndex) + 1
+	columns := make([]*models.PlanFactTableColumnResponse, columnCount)
+	lines := make(map[int64]*planFactTableLine)
+
+	for i := 0; i < columnCount; i++ {
+		year, month := getBudgetYearMonth(startMonthIndex + int32(i))
+		startTime, endTime := getPlanFactMonthTimeRange(year, month, fiscalYearStart, location)
+		factAmounts, err := s.GetFactAmountsByYearMonth(c, uid, startTime, endTime, req.CfoId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		columns[i] = &models.PlanFactTableColumnResponse{
+			Year:      year,
+			Month:     month,
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+
+		for categoryId, amount := range factAmounts {
+			getPlanFactTableLine(lines, categoryId, columnCount).fact[i] += amount
+		}
+	}
+
+	for _, budget := range budgets {
+		column := getBudgetMonthIndex(budget.Year, budget.Month) - startMonthIndex
+		getPlanFactTableLine(lines, budget.CategoryId, columnCount).planned[column] += budget.PlannedAmount
+	}
+
+	categories, err := s.getCategoryMap(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PlanFactTableResponse{
+		StartYear:  columns[0].Year,
+		StartMonth: columns[0].Month,
+		EndYear:    columns[columnCount-1].Year,
+		EndMonth:   columns[columnCount-1].Month,
+		StartTime:  columns[0].StartTime,
+		EndTime:    columns[columnCount-1].EndTime,
+		CfoId:      req.CfoId,
+		Version:    req.Version,
+		Columns:    columns,
+		Lines:      buildPlanFactTableLines(lines, categories, true),
+	}, nil
+}
+
+// GetPlanFactByCfos returns plan-fact analysis of the period as a CFO × category matrix with a column for every CFO which has budgets or fact amounts.
+// The CFO columns are ordered by display order, the column of amounts without CFO goes last. Amounts of sub-categories are rolled up to their parent categories.
+func (s *BudgetService) GetPlanFactByCfos(c core.Context, uid int64, req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart, location *time.Location) (*models.PlanFactTableResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	startMonthIndex, endMonthIndex, err := getPlanFactMonthRange(req, fiscalYearStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.getBudgetsByMonthRange(c, uid, startMonthIndex, endMonthIndex, req.CfoId, req.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	startYear, startMonth := getBudgetYearMonth(startMonthIndex)
+	endYear, endMonth := getBudgetYearMonth(endMonthIndex)
+	startTime, _ := getPlanFactMonthTimeRange(startYear, startMonth, fiscalYearStart, location)
+	_, endTime := getPlanFactMonthTimeRange(endYear, endMonth, fiscalYearStart, location)
+
+	factAmounts, err := s.getFactAmountsByCfoAndCategory(c, uid, startTime, endTime, req.CfoId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cfos []*models.CFO
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc, cfo_id asc").Find(&cfos)
+
+	if err != nil {
+		return nil, err
+	}
+
+	usedCfoIds := make(map[int64]bool)
+
+	for _, budget := range budgets {
+		usedCfoIds[budget.CfoId] = true
+	}
+
+	for cfoId := range factAmounts {
+		usedCfoIds[cfoId] = true
+	}
+
+	columns := make([]*models.PlanFactTableColumnResponse, 0, len(usedCfoIds))
+
+	for _, cfo := range cfos {
+		if usedCfoIds[cfo.CfoId] {
+			columns = append(columns, &models.PlanFactTableColumnResponse{
+				CfoId:   cfo.CfoId,
+				CfoName: cfo.Name,
+			})
+			delete(usedCfoIds, cfo.CfoId)
+		}
+	}
+
+	// CFOs which are deleted and amounts without CFO
+	otherCfoIds := make([]int64, 0, len(usedCfoIds))
+
+	for cfoId := range usedCfoIds {
+		otherCfoIds = append(otherCfoIds, cfoId)
+	}
+
+	sort.Slice(otherCfoIds, func(i, j int) bool {
+		if (otherCfoIds[i] == 0) != (otherCfoIds[j] == 0) {
+			return otherCfoIds[j] == 0
+		}
+
+		return otherCfoIds[i] < otherCfoIds[j]
+	})
+
+	for _, cfoId := range otherCfoIds {
+		columns = append(columns, &models.PlanFactTableColumnResponse{
+			CfoId: cfoId,
+		})
+	}
+
+	columnIndexes := make(map[int64]int, len(columns))
+
+	for i, column := range columns {
+		columnIndexes[column.CfoId] = i
+	}
+
+	lines := make(map[int64]*planFactTableLine)
+
+	for cfoId, categoryAmounts := range factAmounts {
+		for categoryId, amount := range categoryAmounts {
+			getPlanFactTableLine(lines, categoryId, len(columns)).fact[columnIndexes[cfoId]] += amount
+		}
+	}
+
+	for _, budget := range budgets {
+		getPlanFactTableLine(lines, budget.CategoryId, len(columns)).planned[columnIndexes[budget.CfoId]] += budget.PlannedAmount
+	}
+
+	categories, err := s.getCategoryMap(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PlanFactTableResponse{
+		StartYear:  startYear,
+		StartMonth: startMonth,
+		EndYear:    endYear,
+		EndMonth:   endMonth,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		CfoId:      req.CfoId,
+		Version:    req.Version,
+		Columns:    columns,
+		Lines:      buildPlanFactTableLines(lines, categories, false),
+	}, nil
+}
+
+// getBudgetsByMonthRange returns budget models of all months in the sequential month number range of the given version
+func (s *BudgetService) getBudgetsByMonthRange(c core.Context, uid int64, startMonthIndex int32, endMonthIndex int32, cfoId int64, version string) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND version=? AND year*12+month-1>=? AND year*12+month-1<=?", uid, false, version, startMonthIndex, endMonthIndex)
+
+	if cfoId > 0 {
+		sess = sess.And("cfo_id=?", cfoId)
+	}
+
+	err := sess.Find(&budgets)
+
+	return budgets, err
+}
+
+// getFactAmountsByCfoAndCategory returns fact (actual) amounts grouped by cfoId and categoryId for given period, planned transactions are excluded
+func (s *BudgetService) getFactAmountsByCfoAndCategory(c core.Context, uid int64, startTime int64, endTime int64, cfoId int64) (map[int64]map[int64]int64, error) {
+	type FactResult struct {
+		CfoId       int64 `xorm:"cfo_id"`
+		CategoryId  int64 `xorm:"category_id"`
+		TotalAmount int64 `xorm:"total_amount"`
+	}
+
+	var results []FactResult
+	sess := s.UserDataDB(uid).NewSession(c).
+		Table(new(models.Transaction)).
+		Select("cfo_id, category_id, SUM(amount) as total_amount").
+		Where("uid=? AND deleted=? AND planned=? AND transaction_time>=? AND transaction_time<?", uid, false, false, startTime, endTime)
+
+	if cfoId > 0 {
+		sess = sess.And("cfo_id=?", cfoId)
+	}
+
+	err := sess.GroupBy("cfo_id, category_id").Find(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	factMap := make(map[int64]map[int64]int64)
+
+	for _, r := range results {
+		if _, exists := factMap[r.CfoId]; !exists {
+			factMap[r.CfoId] = make(map[int64]int64)
+		}
+
+		factMap[r.CfoId][r.CategoryId] = r.TotalAmount
+	}
+
+	return factMap, nil
+}
+
+// getCategoryMap returns all transaction categories of user mapped by category id
+func (s *BudgetService) getCategoryMap(c core.Context, uid int64) (map[int64]*models.TransactionCategory, error) {
+	var categories []*models.TransactionCategory
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&categories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, len(categories))
+
+	for _, category := range categories {
+		categoryMap[category.CategoryId] = category
+	}
+
+	return categoryMap, nil
+}
+
+// getPlanFactMonthTimeRange returns the transaction time range [start, end) of the month in the location, the month begins on the day
+// of the fiscal year start and ends before that day of the next month, the day is clamped to the last day of shorter months
+func getPlanFactMonthTimeRange(year int32, month int32, fiscalYearStart core.FiscalYearStart, location *time.Location) (int64, int64) {
+	_, fiscalYearStartDay, err := fiscalYearStart.GetMonthDay()
+
+	if err != nil || fiscalYearStartDay <= 1 {
+		return getBudgetMonthTimeRange(year, month, location)
+	}
+
+	// every day of fiscal year start is valid in January, so the months are counted from it
+	januaryStartTime := time.Date(int(year), time.January, int(fiscalYearStartDay), 0, 0, 0, 0, location)
+	startTime := addMonthsClamped(januaryStartTime, int(month)-1)
+	endTime := addMonthsClamped(januaryStartTime, int(month))
+
+	return startTime.UnixMilli(), endTime.UnixMilli()
+}
+
+// getPlanFactMonthRange returns the first and the last sequential month numbers of the plan-fact period.
+// Fiscal years begin at the month of the fiscal year start and are named by the calendar year they end in,
+// unless the fiscal year starts in January
+func getPlanFactMonthRange(req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart) (int32, int32, error) {
+	fiscalYearStartMonth, _, err := fiscalYearStart.GetMonthDay()
+
+	if err != nil {
+		fiscalYearStartMonth = 1
+	}
+
+	getFiscalYearStartMonthIndex := func(fiscalYear int32) int32 {
+		if fiscalYearStartMonth == 1 {
+			return getBudgetMonthIndex(fiscalYear, 1)
+		}
+
+		return getBudgetMonthIndex(fiscalYear-1, int32(fiscalYearStartMonth))
+	}
+
+	var startMonthIndex, endMonthIndex int32
+
+	switch req.PeriodType {
+	case models.PLAN_FACT_PERIOD_MONTH:
+		if req.Month < 1 {
+			return 0, 0, errs.ErrBudgetMonthRangeInvalid
+		}
+
+		startMonthIndex = getBudgetMonthIndex(req.Year, req.Month)
+		endMonthIndex = startMonthIndex
+	case models.PLAN_FACT_PERIOD_QUARTER:
+		if req.Quarter < 1 {
+			return 0, 0, errs.ErrBudgetMonthRangeInvalid
+		}
+
+		startMonthIndex = getFiscalYearStartMonthIndex(req.Year) + (req.Quarter-1)*3
+		endMonthIndex = startMonthIndex + 2
+	case models.PLAN_FACT_PERIOD_FISCAL_YEAR:
+		startMonthIndex = getFiscalYearStartMonthIndex(req.Year)
+		endMonthIndex = startMonthIndex + 11
+	case models.PLAN_FACT_PERIOD_YEAR_TO_DATE:
+		if req.Month < 1 {
+			return 0, 0, errs.ErrBudgetMonthRangeInvalid
+		}
+
+		endMonthIndex = getBudgetMonthIndex(req.Year, req.Month)
+		startMonthIndex = getFiscalYearStartMonthIndex(req.Year)
+
+		if startMonthIndex > endMonthIndex {
+			startMonthIndex -= 12
+		} else if endMonthIndex-startMonthIndex >= 12 {
+			startMonthIndex += 12
+		}
+	case models.PLAN_FACT_PERIOD_CUSTOM:
+		if req.Month < 1 || req.EndYear < 1 || req.EndMonth < 1 {
+			return 0, 0, errs.ErrBudgetMonthRangeInvalid
+		}
+
+		startMonthIndex = getBudgetMonthIndex(req.Year, req.Month)
+		endMonthIndex = getBudgetMonthIndex(req.EndYear, req.EndMonth)
+
+		if endMonthIndex < startMonthIndex || endMonthIndex-startMonthIndex >= maxPlanFactMonths {
+			return 0, 0, errs.ErrBudgetMonthRangeInvalid
+		}
+	default:
+		return 0, 0, errs.ErrBudgetMonthRangeInvalid
+	}
+
+	return startMonthIndex, endMonthIndex, nil
+}
+
+// getPlanFactTableLine returns the line of the category, and creates it if it does not exist
+func getPlanFactTableLine(lines map[int64]*planFactTableLine, categoryId int64, columnCount int) *planFactTableLine {
+	line, exists := lines[categoryId]
+
+	if !exists {
+		line = &planFactTableLine{
+			categoryId: categoryId,
+			planned:    make([]int64, columnCount),
+			fact:       make([]int64, columnCount),
+		}
+		lines[categoryId] = line
+	}
+
+	return line
+}
+
+// buildPlanFactTableLines rolls up amounts of sub-categories to their parent categories and returns the lines
+// ordered by category type and category tree, every parent category goes before its sub-categories
+func buildPlanFactTableLines(lines map[int64]*planFactTableLine, categories map[int64]*models.TransactionCategory, withCumulative bool) []*models.PlanFactTableLineResponse {
+	subCategoryLines := make([]*planFactTableLine, 0, len(lines))
+
+	for categoryId, line := range lines {
+		if category, exists := categories[categoryId]; exists && category.ParentCategoryId > 0 {
+			subCategoryLines = append(subCategoryLines, line)
+		}
+	}
+
+	for _, line := range subCategoryLines {
+		parentLine := getPlanFactTableLine(lines, categories[line.categoryId].ParentCategoryId, len(line.planned))
+		parentLine.rollup = true
+
+		for i := 0; i < len(line.planned); i++ {
+			parentLine.planned[i] += line.planned[i]
+			parentLine.fact[i] += line.fact[i]
+		}
+	}
+
+	sortedLines := make([]*planFactTableLine, 0, len(lines))
+
+	for _, line := range lines {
+		sortedLines = append(sortedLines, line)
+	}
+
+	sort.Slice(sortedLines, func(i, j int) bool {
+		keyI := getPlanFactTableLineSortKey(sortedLines[i].categoryId, categories)
+		keyJ := getPlanFactTableLineSortKey(sortedLines[j].categoryId, categories)
+
+		for k := 0; k < len(keyI); k++ {
+			if keyI[k] != keyJ[k] {
+				return keyI[k] < keyJ[k]
+			}
+		}
+
+		return false
+	})
+
+	result := make([]*models.PlanFactTableLineResponse, len(sortedLines))
+
+	for i, line := range sortedLines {
+		resultLine := &models.PlanFactTableLineResponse{
+			CategoryId: line.categoryId,
+			Rollup:     line.rollup,
+			Columns:    make([]*models.PlanFactAmountsResponse, len(line.planned)),
+		}
+
+		if category, exists := categories[line.categoryId]; exists {
+			resultLine.CategoryName = category.Name
+			resultLine.CategoryType = int32(category.Type)
+			resultLine.ParentCategoryId = category.ParentCategoryId
+		}
+
+		if withCumulative {
+			resultLine.Cumulative = make([]*models.PlanFactAmountsResponse, len(line.planned))
+		}
+
+		totalPlanned := int64(0)
+		totalFact := int64(0)
+
+		for j := 0; j < len(line.planned); j++ {
+			resultLine.Columns[j] = newPlanFactAmounts(line.planned[j], line.fact[j])
+			totalPlanned += line.planned[j]
+			totalFact += line.fact[j]
+
+			if withCumulative {
+				resultLine.Cumulative[j] = newPlanFactAmounts(totalPlanned, totalFact)
+			}
+		}
+
+		resultLine.Total = newPlanFactAmounts(totalPlanned, totalFact)
+		result[i] = resultLine
+	}
+
+	return result
+}
+
+// getPlanFactTableLineSortKey returns the sort key of the category line, lines of unknown categories go last
+func getPlanFactTableLineSortKey(categoryId int64, categories map[int64]*models.TransactionCategory) [6]int64 {
+	category, exists := categories[categoryId]
+
+	if !exists {
+		return [6]int64{1 << 8, 0, categoryId, 0, 0, categoryId}
+	}
+
+	if parentCategory, exists := categories[category.ParentCategoryId]; exists && category.ParentCategoryId > 0 {
+		return [6]int64{int64(category.Type), int64(parentCategory.DisplayOrder), parentCategory.CategoryId, 1, int64(category.DisplayOrder), category.CategoryId}
+	}
+
+	return [6]int64{int64(category.Type), int64(category.DisplayOrder), category.CategoryId, 0, 0, category.CategoryId}
+}
+
+// newPlanFactAmounts returns the planned and fact amounts with their deviation, the deviation percentage is not set if nothing is planned
+func newPlanFactAmounts(planned int64, fact int64) *models.PlanFactAmountsResponse {
+	deviation := fact - planned
+
+	var deviationPct *int32
+	if planned != 0 {
+		pct := int32(float64(deviation) / float64(planned) * 100)
+		deviationPct = &pct
+	}
+
+	return &models.PlanFactAmountsResponse{
+		PlannedAmount: planned,
+		FactAmount:    fact,
+		Deviation:     deviation,
+		DeviationPct:  deviationPct,
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func assertPlanFactMonthRange(t *testing.T, req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart, startYear int32, startMonth int32, endYear int32, endMonth int32) {
+	t.Helper()
+
+	startMonthIndex, endMonthIndex, err := getPlanFactMonthRange(req, fiscalYearStart)
+	assert.Nil(t, err)
+	assert.Equal(t, getBudgetMonthIndex(startYear, startMonth), startMonthIndex)
+	assert.Equal(t, getBudgetMonthIndex(endYear, endMonth), endMonthIndex)
+}
+
+func TestGetPlanFactMonthRange(t *testing.T) {
+	aprilFirst, _ := core.NewFiscalYearStart(4, 1)
+
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_MONTH, Year: 2025, Month: 3}, aprilFirst, 2025, 3, 2025, 3)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_QUARTER, Year: 2025, Quarter: 2}, core.FISCAL_YEAR_START_DEFAULT, 2025, 4, 2025, 6)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_QUARTER, Year: 2025, Quarter: 4}, aprilFirst, 2025, 1, 2025, 3)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_FISCAL_YEAR, Year: 2025}, core.FISCAL_YEAR_START_DEFAULT, 2025, 1, 2025, 12)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_FISCAL_YEAR, Year: 2025}, aprilFirst, 2024, 4, 2025, 3)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_YEAR_TO_DATE, Year: 2025, Month: 2}, aprilFirst, 2024, 4, 2025, 2)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_YEAR_TO_DATE, Year: 2025, Month: 5}, aprilFirst, 2025, 4, 2025, 5)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_YEAR_TO_DATE, Year: 2025, Month: 5}, core.FISCAL_YEAR_START_DEFAULT, 2025, 1, 2025, 5)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_CUSTOM, Year: 2024, Month: 11, EndYear: 2025, EndMonth: 2}, aprilFirst, 2024, 11, 2025, 2)
+
+	// Invalid fiscal year start is treated as January 1
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_FISCAL_YEAR, Year: 2025}, core.FISCAL_YEAR_START_INVALID, 2025, 1, 2025, 12)
+
+	// Fiscal year which starts in January but not on January 1 is named by the calendar year it starts in
+	januaryFifteenth, _ := core.NewFiscalYearStart(1, 15)
+	assertPlanFactMonthRange(t, &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_FISCAL_YEAR, Year: 2025}, januaryFifteenth, 2025, 1, 2025, 12)
+}
+
+func TestGetPlanFactMonthTimeRange(t *testing.T) {
+	aprilFirst, _ := core.NewFiscalYearStart(4, 1)
+	startTime, endTime := getPlanFactMonthTimeRange(2025, 2, aprilFirst, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), startTime)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), endTime)
+
+	// Months begin on the day of fiscal year start
+	julyFifteenth, _ := core.NewFiscalYearStart(7, 15)
+	startTime, endTime = getPlanFactMonthTimeRange(2025, 7, julyFifteenth, time.UTC)
+	assert.Equal(t, time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC).UnixMilli(), startTime)
+	assert.Equal(t, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC).UnixMilli(), endTime)
+
+	// The day is clamped to the last day of shorter months
+	januaryThirtyFirst, _ := core.NewFiscalYearStart(1, 31)
+	startTime, endTime = getPlanFactMonthTimeRange(2025, 1, januaryThirtyFirst, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC).UnixMilli(), startTime)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC).UnixMilli(), endTime)
+
+	startTime, endTime = getPlanFactMonthTimeRange(2025, 12, januaryThirtyFirst, time.UTC)
+	assert.Equal(t, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC).UnixMilli(), startTime)
+	assert.Equal(t, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC).UnixMilli(), endTime)
+}
+
+func TestGetPlanFactMonthRange_Invalid(t *testing.T) {
+	invalidRequests := []*models.PlanFactRangeRequest{
+		{PeriodType: models.PLAN_FACT_PERIOD_MONTH, Year: 2025},
+		{PeriodType: models.PLAN_FACT_PERIOD_QUARTER, Year: 2025},
+		{PeriodType: models.PLAN_FACT_PERIOD_YEAR_TO_DATE, Year: 2025},
+		{PeriodType: models.PLAN_FACT_PERIOD_CUSTOM, Year: 2025, Month: 1},
+		{PeriodType: models.PLAN_FACT_PERIOD_CUSTOM, Year: 2025, Month: 2, EndYear: 2025, EndMonth: 1},
+		{PeriodType: models.PLAN_FACT_PERIOD_CUSTOM, Year: 2025, Month: 1, EndYear: 2028, EndMonth: 1},
+		{PeriodType: 6, Year: 2025, Month: 1},
+	}
+
+	for _, req := range invalidRequests {
+		_, _, err := getPlanFactMonthRange(req, core.FISCAL_YEAR_START_DEFAULT)
+		assert.Equal(t, errs.ErrBudgetMonthRangeInvalid, err)
+	}
+}
+
+func insertTestPlanFactData(t *testing.T, tdb *testDB) {
+	t.Helper()
+
+	categories := []*models.TransactionCategory{
+		{CategoryId: 10, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Office", DisplayOrder: 2},
+		{CategoryId: 11, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10, Name: "Rent", DisplayOrder: 2},
+		{CategoryId: 12, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10, Name: "Utilities", DisplayOrder: 1},
+		{CategoryId: 20, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Travel", DisplayOrder: 1},
+		{CategoryId: 30, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Sales", DisplayOrder: 1},
+	}
+	for _, category := range categories {
+		_, err := tdb.engine.Insert(category)
+		assert.Nil(t, err)
+	}
+
+	cfos := []*models.CFO{
+		{CfoId: 1, Uid: 1, Name: "Sales dept", DisplayOrder: 2},
+		{CfoId: 2, Uid: 1, Name: "Back office", DisplayOrder: 1},
+	}
+	for _, cfo := range cfos {
+		_, err := tdb.engine.Insert(cfo)
+		assert.Nil(t, err)
+	}
+
+	budgets := []*models.Budget{
+		{BudgetId: 1, Uid: 1, CfoId: 2, CategoryId: 11, Year: 2025, Month: 1, PlannedAmount: 10000},
+		{BudgetId: 2, Uid: 1, CfoId: 2, CategoryId: 11, Year: 2025, Month: 2, PlannedAmount: 10000},
+		{BudgetId: 3, Uid: 1, CfoId: 2, CategoryId: 12, Year: 2025, Month: 2, PlannedAmount: 2000},
+		{BudgetId: 4, Uid: 1, CfoId: 1, CategoryId: 30, Year: 2025, Month: 1, PlannedAmount: 50000},
+		{BudgetId: 5, Uid: 1, CfoId: 1, CategoryId: 30, Year: 2025, Month: 2, PlannedAmount: 50000},
+		// other version and month out of range
+		{BudgetId: 6, Uid: 1, CfoId: 2, CategoryId: 11, Year: 2025, Month: 1, Version: "original", PlannedAmount: 9000},
+		{BudgetId: 7, Uid: 1, CfoId: 2, CategoryId: 11, Year: 2025, Month: 3, PlannedAmount: 10000},
+	}
+	for _, budget := range budgets {
+		_, err := tdb.engine.Insert(budget)
+		assert.Nil(t, err)
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CfoId: 2, CategoryId: 11, TransactionTime: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC).UnixMilli(), Amount: 12000},
+		// 2025-02-01 01:00 at UTC+3 is still January in UTC
+		{TransactionId: 2, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CfoId: 2, CategoryId: 12, TransactionTime: time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC).UnixMilli(), Amount: 1500},
+		{TransactionId: 3, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CfoId: 0, CategoryId: 20, TransactionTime: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC).UnixMilli(), Amount: 3000},
+		{TransactionId: 4, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CfoId: 1, CategoryId: 30, TransactionTime: time.Date(2025, 2, 11, 0, 0, 0, 0, time.UTC).UnixMilli(), Amount: 40000},
+		// planned transaction is excluded
+		{TransactionId: 5, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CfoId: 2, CategoryId: 11, TransactionTime: time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC).UnixMilli(), Amount: 10000, Planned: true},
+	}
+	for _, transaction := range transactions {
+		_, err := tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+}
+
+func TestBudgetServiceGetPlanFactByMonths(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+	insertTestPlanFactData(t, tdb)
+
+	req := &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_CUSTOM, Year: 2025, Month: 1, EndYear: 2025, EndMonth: 2}
+	result, err := svc.GetPlanFactByMonths(nil, 1, req, core.FISCAL_YEAR_START_DEFAULT, time.FixedZone("", 3*60*60))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(result.Columns))
+	assert.Equal(t, int32(2025), result.Columns[1].Year)
+	assert.Equal(t, int32(2), result.Columns[1].Month)
+	assert.Equal(t, time.Date(2025, 1, 31, 21, 0, 0, 0, time.UTC).UnixMilli(), result.Columns[1].StartTime)
+
+	// Income goes first, then parent category before its sub-categories
+	assert.Equal(t, 5, len(result.Lines))
+	assert.Equal(t, int64(30), result.Lines[0].CategoryId)
+	assert.Equal(t, int64(20), result.Lines[1].CategoryId)
+	assert.Equal(t, int64(10), result.Lines[2].CategoryId)
+	assert.Equal(t, int64(12), result.Lines[3].CategoryId)
+	assert.Equal(t, int64(11), result.Lines[4].CategoryId)
+
+	sales := result.Lines[0]
+	assert.Equal(t, "Sales", sales.CategoryName)
+	assert.Equal(t, int64(0), sales.Columns[0].FactAmount)
+	assert.Equal(t, int64(40000), sales.Columns[1].FactAmount)
+	assert.Equal(t, int64(100000), sales.Total.PlannedAmount)
+	assert.Equal(t, int64(-60000), sales.Total.Deviation)
+	assert.Equal(t, int32(-60), *sales.Total.DeviationPct)
+
+	travel := result.Lines[1]
+	assert.Equal(t, int64(3000), travel.Columns[1].FactAmount)
+	assert.Nil(t, travel.Total.DeviationPct)
+
+	office := result.Lines[2]
+	assert.True(t, office.Rollup)
+	assert.Equal(t, "Office", office.CategoryName)
+	assert.Equal(t, int64(10000), office.Columns[0].PlannedAmount)
+	assert.Equal(t, int64(12000), office.Columns[0].FactAmount)
+	assert.Equal(t, int64(12000), office.Columns[1].PlannedAmount)
+	assert.Equal(t, int64(1500), office.Columns[1].FactAmount)
+	assert.Equal(t, int64(22000), office.Cumulative[1].PlannedAmount)
+	assert.Equal(t, int64(13500), office.Cumulative[1].FactAmount)
+	assert.Equal(t, int64(22000), office.Total.PlannedAmount)
+
+	utilities := result.Lines[3]
+	assert.False(t, utilities.Rollup)
+	assert.Equal(t, int64(10), utilities.ParentCategoryId)
+	assert.Equal(t, int64(1500), utilities.Columns[1].FactAmount)
+
+	// Another budget version and CFO filter
+	req.Version = "original"
+	req.CfoId = 2
+	result, err = svc.GetPlanFactByMonths(nil, 1, req, core.FISCAL_YEAR_START_DEFAULT, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result.Lines))
+	assert.Equal(t, int64(10), result.Lines[0].CategoryId)
+	assert.Equal(t, int64(9000), result.Lines[0].Total.PlannedAmount)
+	assert.Equal(t, int64(13500), result.Lines[0].Total.FactAmount)
+}
+
+func TestBudgetServiceGetPlanFactByCfos(t *testing.T) {
+	svc, tdb := newTestBudgetService(t)
+	defer tdb.close()
+	insertTestPlanFactData(t, tdb)
+
+	req := &models.PlanFactRangeRequest{PeriodType: models.PLAN_FACT_PERIOD_QUARTER, Year: 2025, Quarter: 1}
+	result, err := svc.GetPlanFactByCfos(nil, 1, req, core.FISCAL_YEAR_START_DEFAULT, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), result.StartMonth)
+	assert.Equal(t, int32(3), result.EndMonth)
+
+	// CFOs are ordered by display order, amounts without CFO go last
+	assert.Equal(t, 3, len(result.Columns))
+	assert.Equal(t, int64(2), result.Columns[0].CfoId)
+	assert.Equal(t, "Back office", result.Columns[0].CfoName)
+	assert.Equal(t, int64(1), result.Columns[1].CfoId)
+	assert.Equal(t, int64(0), result.Columns[2].CfoId)
+
+	assert.Equal(t, 5, len(result.Lines))
+
+	sales := result.Lines[0]
+	assert.Equal(t, int64(0), sales.Columns[0].PlannedAmount)
+	assert.Equal(t, int64(100000), sales.Columns[1].PlannedAmount)
+	assert.Equal(t, int64(40000), sales.Columns[1].FactAmount)
+	assert.Nil(t, sales.Cumulative)
+
+	travel := result.Lines[1]
+	assert.Equal(t, int64(3000), travel.Columns[2].FactAmount)
+
+	office := result.Lines[2]
+	assert.True(t, office.Rollup)
+	assert.Equal(t, int64(32000), office.Columns[0].PlannedAmount)
+	assert.Equal(t, int64(13500), office.Columns[0].FactAmount)
+	assert.Equal(t, int64(32000), office.Total.PlannedAmount)
+}
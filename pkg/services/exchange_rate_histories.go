@@ -0,0 +1,150 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ExchangeRateHistoryService represents exchange rate history service
+type ExchangeRateHistoryService struct {
+	ServiceUsingDB
+}
+
+// Initialize an exchange rate history service singleton instance
+var (
+	ExchangeRateHistories = &ExchangeRateHistoryService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// SaveExchangeRates saves all exchange rates of the specified response as the rates published on the date of its update time, and returns the rate date
+func (s *ExchangeRateHistoryService) SaveExchangeRates(c core.Context, dataSource string, exchangeRates *models.LatestExchangeRateResponse) (string, error) {
+	if dataSource == "" || exchangeRates == nil || len(exchangeRates.ExchangeRates) < 1 {
+		return "", errs.ErrExchangeRateHistoryNotFound
+	}
+
+	if exchangeRates.UpdateTime <= 0 {
+		return "", errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	now := time.Now().Unix()
+	rateDate := models.GetExchangeRateHistoryDate(exchangeRates.UpdateTime)
+	histories := make([]*models.ExchangeRateHistory, 0, len(exchangeRates.ExchangeRates)+1)
+	baseCurrencyExists := false
+
+	for i := 0; i < len(exchangeRates.ExchangeRates); i++ {
+		exchangeRate := exchangeRates.ExchangeRates[i]
+
+		if exchangeRate.Currency == exchangeRates.BaseCurrency {
+			baseCurrencyExists = true
+		}
+
+		histories = append(histories, &models.ExchangeRateHistory{
+			DataSource:      dataSource,
+			RateDate:        rateDate,
+			Currency:        exchangeRate.Currency,
+			BaseCurrency:    exchangeRates.BaseCurrency,
+			Rate:            exchangeRate.Rate,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		})
+	}
+
+	if !baseCurrencyExists {
+		histories = append(histories, &models.ExchangeRateHistory{
+			DataSource:      dataSource,
+			RateDate:        rateDate,
+			Currency:        exchangeRates.BaseCurrency,
+			BaseCurrency:    exchangeRates.BaseCurrency,
+			Rate:            "1",
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		})
+	}
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("data_source=? AND rate_date=?", dataSource, rateDate).Delete(&models.ExchangeRateHistory{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(histories)
+
+		return err
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return rateDate, nil
+}
+
+// GetExchangeRate returns the exchange rate of the specified currency in effect on the specified date, which is the nearest rate published on or before that date
+func (s *ExchangeRateHistoryService) GetExchangeRate(c core.Context, dataSource string, currency string, rateDate string) (*models.ExchangeRateHistory, error) {
+	if _, err := models.ParseExchangeRateHistoryDate(rateDate); err != nil {
+		return nil, errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	history := &models.ExchangeRateHistory{}
+	has, err := s.UserDB().NewSession(c).Where("data_source=? AND currency=? AND rate_date<=?", dataSource, currency, rateDate).OrderBy("rate_date desc").Limit(1).Get(history)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExchangeRateHistoryNotFound
+	}
+
+	return history, nil
+}
+
+// GetAllExchangeRatesByDate returns the exchange rates of all currencies in effect on the specified date, each currency falls back to its nearest rate published on or before that date
+func (s *ExchangeRateHistoryService) GetAllExchangeRatesByDate(c core.Context, dataSource string, rateDate string) (models.ExchangeRateHistorySlice, error) {
+	if _, err := models.ParseExchangeRateHistoryDate(rateDate); err != nil {
+		return nil, errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	var latestRateDates []*models.ExchangeRateHistory
+	err := s.UserDB().NewSession(c).Select("currency, MAX(rate_date) AS rate_date").Where("data_source=? AND rate_date<=?", dataSource, rateDate).GroupBy("currency").Find(&latestRateDates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(latestRateDates) < 1 {
+		return nil, errs.ErrExchangeRateHistoryNotFound
+	}
+
+	currenciesByRateDate := make(map[string][]string)
+
+	for i := 0; i < len(latestRateDates); i++ {
+		currenciesByRateDate[latestRateDates[i].RateDate] = append(currenciesByRateDate[latestRateDates[i].RateDate], latestRateDates[i].Currency)
+	}
+
+	histories := make(models.ExchangeRateHistorySlice, 0, len(latestRateDates))
+
+	for date, currencies := range currenciesByRateDate {
+		var dateHistories []*models.ExchangeRateHistory
+		err = s.UserDB().NewSession(c).Where("data_source=? AND rate_date=?", dataSource, date).In("currency", currencies).Find(&dateHistories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		histories = append(histories, dateHistories...)
+	}
+
+	sort.Sort(histories)
+
+	return histories, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func newTestExchangeRateHistoryService(t *testing.T) (*ExchangeRateHistoryService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	svc := &ExchangeRateHistoryService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+	}
+	return svc, tdb
+}
+
+func newTestLatestExchangeRateResponse(updateTime int64, usdRate string, eurRate string) *models.LatestExchangeRateResponse {
+	exchangeRates := models.LatestExchangeRateSlice{
+		{Currency: "USD", Rate: usdRate},
+	}
+
+	if eurRate != "" {
+		exchangeRates = append(exchangeRates, &models.LatestExchangeRate{Currency: "EUR", Rate: eurRate})
+	}
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    "Test Bank",
+		UpdateTime:    updateTime,
+		BaseCurrency:  "RUB",
+		ExchangeRates: exchangeRates,
+	}
+}
+
+func TestExchangeRateHistoryServiceSaveExchangeRates(t *testing.T) {
+	svc, tdb := newTestExchangeRateHistoryService(t)
+	defer tdb.close()
+
+	rateDate, err := svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705320000, "0.0113", "0.0103")) // 2024-01-15 12:00:00 UTC
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-15", rateDate)
+
+	count, err := tdb.engine.Count(&models.ExchangeRateHistory{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	baseCurrencyRate, err := svc.GetExchangeRate(nil, "bank_of_russia", "RUB", "2024-01-15")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", baseCurrencyRate.Rate)
+}
+
+func TestExchangeRateHistoryServiceSaveExchangeRatesOverwritesSameDate(t *testing.T) {
+	svc, tdb := newTestExchangeRateHistoryService(t)
+	defer tdb.close()
+
+	_, err := svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705320000, "0.0113", "0.0103"))
+	assert.Nil(t, err)
+
+	_, err = svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705330000, "0.0114", ""))
+	assert.Nil(t, err)
+
+	history, err := svc.GetExchangeRate(nil, "bank_of_russia", "USD", "2024-01-15")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.0114", history.Rate)
+
+	_, err = svc.GetExchangeRate(nil, "bank_of_russia", "EUR", "2024-01-15")
+	assert.Equal(t, errs.ErrExchangeRateHistoryNotFound, err)
+}
+
+func TestExchangeRateHistoryServiceGetExchangeRateFallsBackToNearestEarlierDate(t *testing.T) {
+	svc, tdb := newTestExchangeRateHistoryService(t)
+	defer tdb.close()
+
+	_, err := svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705320000, "0.0113", "0.0103")) // 2024-01-15
+	assert.Nil(t, err)
+
+	_, err = svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705579200, "0.0115", "0.0105")) // 2024-01-18
+	assert.Nil(t, err)
+
+	history, err := svc.GetExchangeRate(nil, "bank_of_russia", "USD", "2024-01-17")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-15", history.RateDate)
+	assert.Equal(t, "0.0113", history.Rate)
+
+	history, err = svc.GetExchangeRate(nil, "bank_of_russia", "USD", "2024-02-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-18", history.RateDate)
+	assert.Equal(t, "0.0115", history.Rate)
+
+	_, err = svc.GetExchangeRate(nil, "bank_of_russia", "USD", "2024-01-14")
+	assert.Equal(t, errs.ErrExchangeRateHistoryNotFound, err)
+
+	_, err = svc.GetExchangeRate(nil, "euro_central_bank", "USD", "2024-01-17")
+	assert.Equal(t, errs.ErrExchangeRateHistoryNotFound, err)
+}
+
+func TestExchangeRateHistoryServiceGetAllExchangeRatesByDate(t *testing.T) {
+	svc, tdb := newTestExchangeRateHistoryService(t)
+	defer tdb.close()
+
+	_, err := svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705320000, "0.0113", "0.0103")) // 2024-01-15
+	assert.Nil(t, err)
+
+	_, err = svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(1705579200, "0.0115", "")) // 2024-01-18, without EUR
+	assert.Nil(t, err)
+
+	histories, err := svc.GetAllExchangeRatesByDate(nil, "bank_of_russia", "2024-01-20")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(histories))
+
+	assert.Equal(t, "EUR", histories[0].Currency)
+	assert.Equal(t, "2024-01-15", histories[0].RateDate)
+	assert.Equal(t, "0.0103", histories[0].Rate)
+	assert.Equal(t, "RUB", histories[1].Currency)
+	assert.Equal(t, "2024-01-18", histories[1].RateDate)
+	assert.Equal(t, "USD", histories[2].Currency)
+	assert.Equal(t, "2024-01-18", histories[2].RateDate)
+	assert.Equal(t, "0.0115", histories[2].Rate)
+
+	exchangeRates := histories.ToLatestExchangeRateResponse()
+	assert.Equal(t, "RUB", exchangeRates.BaseCurrency)
+	assert.Equal(t, 3, len(exchangeRates.ExchangeRates))
+	assert.Equal(t, int64(1705536000), exchangeRates.UpdateTime)
+}
+
+func TestExchangeRateHistoryServiceInvalidDate(t *testing.T) {
+	svc, tdb := newTestExchangeRateHistoryService(t)
+	defer tdb.close()
+
+	_, err := svc.GetExchangeRate(nil, "bank_of_russia", "USD", "2024/01/15")
+	assert.Equal(t, errs.ErrExchangeRateHistoryDateInvalid, err)
+
+	_, err = svc.GetAllExchangeRatesByDate(nil, "bank_of_russia", "")
+	assert.Equal(t, errs.ErrExchangeRateHistoryDateInvalid, err)
+
+	_, err = svc.SaveExchangeRates(nil, "bank_of_russia", newTestLatestExchangeRateResponse(0, "0.0113", ""))
+	assert.Equal(t, errs.ErrExchangeRateHistoryDateInvalid, err)
+}
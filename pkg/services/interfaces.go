@@ -94,6 +94,12 @@ type AssetProvider interface {
 	ModifyAssetDisplayOrders(c core.Context, uid int64, assets []*models.Asset) error
 	DeleteAsset(c core.Context, uid int64, assetId int64) error
 	ExistsAssetName(c core.Context, uid int64, name string) (bool, error)
+	GetAssetDepreciationSchedule(asset *models.Asset, timezone *time.Location) []*models.AssetDepreciationScheduleItemResponse
+	SaveAssetProductionOutput(c core.Context, uid int64, saveReq *models.AssetProductionOutputSaveRequest) (*models.Asset, error)
+	GetAssetTransactionsByAssetId(c core.Context, uid int64, assetId int64) ([]*models.AssetTransaction, error)
+	LinkPurchaseTransactions(c core.Context, uid int64, assetId int64, transactionIds []int64) (*models.Asset, error)
+	UnlinkPurchaseTransactions(c core.Context, uid int64, assetId int64, transactionIds []int64) (*models.Asset, error)
+	DisposeAsset(c core.Context, uid int64, disposeReq *models.AssetDisposeRequest) (*models.Asset, error)
 }
 
 // TaxRecordProvider provides access to tax records
@@ -103,6 +109,18 @@ type TaxRecordProvider interface {
 	CreateTaxRecord(c core.Context, record *models.TaxRecord) error
 	ModifyTaxRecord(c core.Context, record *models.TaxRecord) error
 	DeleteTaxRecord(c core.Context, uid int64, taxId int64) error
+	GetTaxRecordStatusHistories(c core.Context, uid int64, taxId int64) ([]*models.StatusHistory, error)
+}
+
+// TaxRegimeProvider provides access to tax regimes and the taxes computed by them
+type TaxRegimeProvider interface {
+	GetAllTaxRegimesByUid(c core.Context, uid int64) ([]*models.TaxRegime, error)
+	GetTaxRegime(c core.Context, uid int64, cfoId int64, taxType models.TaxType) (*models.TaxRegime, error)
+	SaveTaxRegime(c core.Context, regime *models.TaxRegime) error
+	DeleteTaxRegime(c core.Context, uid int64, cfoId int64, taxType models.TaxType) error
+	CalculateTax(c core.Context, uid int64, cfoId int64, taxType models.TaxType, periodYear int32, periodQuarter int32, timezone *time.Location) (*models.TaxCalculationResponse, error)
+	GenerateTaxRecord(c core.Context, uid int64, cfoId int64, taxType models.TaxType, periodYear int32, periodQuarter int32, timezone *time.Location) (*models.TaxRecord, *models.TaxCalculationResponse, error)
+	CalculateTaxRecords(c core.Context, uid int64, records []*models.TaxRecord, timezone *time.Location) (map[int64]*models.TaxCalculationResponse, error)
 }
 
 // InvestorDealProvider provides access to investor deals
@@ -125,6 +143,12 @@ type InvestorPaymentProvider interface {
 	DeletePayment(c core.Context, uid int64, paymentId int64) error
 }
 
+// InvestorScheduleProvider provides access to repayment schedules of investor deals
+type InvestorScheduleProvider interface {
+	GetSchedule(c core.Context, uid int64, dealId int64) (*models.InvestorScheduleResponse, error)
+	CreatePlannedTransactions(c core.Context, uid int64, req *models.InvestorSchedulePlannedTransactionsCreateRequest) (int, error)
+}
+
 // ObligationProvider provides access to obligations
 type ObligationProvider interface {
 	GetAllObligationsByUid(c core.Context, uid int64) ([]*models.Obligation, error)
@@ -132,6 +156,10 @@ type ObligationProvider interface {
 	CreateObligation(c core.Context, obligation *models.Obligation) error
 	ModifyObligation(c core.Context, obligation *models.Obligation) error
 	DeleteObligation(c core.Context, uid int64, obligationId int64) error
+	GetAllPaymentsByObligationId(c core.Context, uid int64, obligationId int64) ([]*models.ObligationPayment, error)
+	AllocateTransaction(c core.Context, uid int64, transactionId int64, allocations []*models.ObligationAllocation, comment string) ([]*models.ObligationPayment, []*models.Obligation, error)
+	DeletePayment(c core.Context, uid int64, paymentId int64) error
+	GetObligationStatusHistories(c core.Context, uid int64, obligationId int64) ([]*models.StatusHistory, error)
 }
 
 // CFOProvider provides access to CFO entities
@@ -149,17 +177,32 @@ type CFOProvider interface {
 
 // BudgetProvider provides access to budgets
 type BudgetProvider interface {
-	GetBudgetsByYearMonth(c core.Context, uid int64, year int32, month int32, cfoId int64) ([]*models.Budget, error)
-	SaveBudgets(c core.Context, uid int64, year int32, month int32, cfoId int64, items []*models.BudgetItemRequest) error
+	GetBudgetsByYearMonth(c core.Context, uid int64, year int32, month int32, cfoId int64, version string) ([]*models.Budget, error)
+	SaveBudgets(c core.Context, uid int64, year int32, month int32, cfoId int64, version string, items []*models.BudgetItemRequest) error
 	GetFactAmountsByYearMonth(c core.Context, uid int64, startTime int64, endTime int64, cfoId int64) (map[int64]int64, error)
+	CopyBudgets(c core.Context, uid int64, req *models.BudgetCopyRequest) (int, error)
+	SpreadAnnualBudgets(c core.Context, uid int64, req *models.BudgetSpreadRequest) (int, error)
+	CreateBudgetsFromActuals(c core.Context, uid int64, req *models.BudgetFromActualsRequest) ([]*models.Budget, error)
+	GetBudgetVersions(c core.Context, uid int64, year int32, cfoId int64) ([]*models.BudgetVersionInfoResponse, error)
+	GetPlanFactByMonths(c core.Context, uid int64, req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart, location *time.Location) (*models.PlanFactTableResponse, error)
+	GetPlanFactByCfos(c core.Context, uid int64, req *models.PlanFactRangeRequest, fiscalYearStart core.FiscalYearStart, location *time.Location) (*models.PlanFactTableResponse, error)
 }
 
 // ReportProvider provides access to financial reports
 type ReportProvider interface {
-	GetCashFlow(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64) (*models.CashFlowResponse, error)
-	GetPnL(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64) (*models.PnLResponse, error)
-	GetBalance(c core.Context, uid int64, cfoId int64) (*models.BalanceResponse, error)
-	GetPaymentCalendar(c core.Context, uid int64, startTime int64, endTime int64) (*models.PaymentCalendarResponse, error)
+	GetCashFlow(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.CashFlowResponse, error)
+	GetPnL(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.PnLResponse, error)
+	GetBalance(c core.Context, uid int64, cfoId int64, asOf int64, converter *ReportCurrencyConverter) (*models.BalanceResponse, error)
+	GetComparativeBalance(c core.Context, uid int64, cfoId int64, asOf int64, compareAsOf int64, converter *ReportCurrencyConverter, compareConverter *ReportCurrencyConverter) (*models.ComparativeBalanceResponse, error)
+	GetPaymentCalendar(c core.Context, uid int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.PaymentCalendarResponse, error)
+	GetAging(c core.Context, uid int64, cfoId int64, asOf int64, converter *ReportCurrencyConverter) (*models.AgingReportResponse, error)
+}
+
+// NotificationProvider provides access to notification preferences and sends notification emails
+type NotificationProvider interface {
+	GetNotificationPreference(c core.Context, uid int64) (*models.NotificationPreference, error)
+	SaveNotificationPreference(c core.Context, preference *models.NotificationPreference) error
+	SendNotifications(c core.Context, currentUnixTime int64) error
 }
 
 // LocationProvider provides access to locations
@@ -175,6 +218,12 @@ type LocationProvider interface {
 	ExistsLocationName(c core.Context, uid int64, name string) (bool, error)
 }
 
+// MCPAuditLogProvider provides access to the changes made by MCP tools
+type MCPAuditLogProvider interface {
+	GetAuditLogsByUid(c core.Context, uid int64, tokenId string, page int32, count int32) ([]*models.MCPAuditLog, error)
+	CreateAuditLog(c core.Context, auditLog *models.MCPAuditLog) error
+}
+
 // Compile-time interface compliance checks
 var (
 	_ TransactionReader             = (*TransactionService)(nil)
@@ -185,11 +234,15 @@ var (
 	_ AccountWriter                 = (*AccountService)(nil)
 	_ AssetProvider                 = (*AssetService)(nil)
 	_ TaxRecordProvider             = (*TaxRecordService)(nil)
+	_ TaxRegimeProvider             = (*TaxRegimeService)(nil)
 	_ InvestorDealProvider          = (*InvestorDealService)(nil)
 	_ InvestorPaymentProvider       = (*InvestorPaymentService)(nil)
+	_ InvestorScheduleProvider      = (*InvestorScheduleService)(nil)
 	_ ObligationProvider            = (*ObligationService)(nil)
 	_ CFOProvider                   = (*CFOService)(nil)
 	_ BudgetProvider                = (*BudgetService)(nil)
 	_ ReportProvider                = (*ReportService)(nil)
+	_ NotificationProvider          = (*NotificationService)(nil)
 	_ LocationProvider              = (*LocationService)(nil)
+	_ MCPAuditLogProvider           = (*MCPAuditLogService)(nil)
 )
@@ -104,7 +104,7 @@ func (s *InvestorDealService) ModifyDeal(c core.Context, deal *models.InvestorDe
 	deal.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(deal.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(deal.DealId).Cols("investor_name", "cfo_id", "investment_date", "investment_amount", "currency", "deal_type", "annual_rate", "profit_share_pct", "fixed_payment", "repayment_start_date", "repayment_end_date", "total_to_repay", "comment", "updated_unix_time").Where("uid=? AND deleted=?", deal.Uid, false).Update(deal)
+		updatedRows, err := sess.ID(deal.DealId).Cols("investor_name", "cfo_id", "investment_date", "investment_amount", "currency", "deal_type", "repayment_method", "annual_rate", "profit_share_pct", "fixed_payment", "repayment_start_date", "repayment_end_date", "total_to_repay", "comment", "updated_unix_time").Where("uid=? AND deleted=?", deal.Uid, false).Update(deal)
 
 		if err != nil {
 			return err
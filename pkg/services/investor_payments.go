@@ -112,6 +112,17 @@ func (s *InvestorPaymentService) CreatePayment(c core.Context, payment *models.I
 		return errs.ErrUserIdInvalid
 	}
 
+	return s.UserDataDB(payment.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.CreatePaymentInSession(c, sess, payment)
+	})
+}
+
+// CreatePaymentInSession saves a new investor payment model to database in the given session
+func (s *InvestorPaymentService) CreatePaymentInSession(c core.Context, sess *xorm.Session, payment *models.InvestorPayment) error {
+	if payment.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	if payment.DealId <= 0 {
 		return errs.ErrInvestorDealIdInvalid
 	}
@@ -126,10 +137,8 @@ func (s *InvestorPaymentService) CreatePayment(c core.Context, payment *models.I
 	payment.CreatedUnixTime = time.Now().Unix()
 	payment.UpdatedUnixTime = time.Now().Unix()
 
-	return s.UserDataDB(payment.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Insert(payment)
-		return err
-	})
+	_, err := sess.Insert(payment)
+	return err
 }
 
 // ModifyPayment saves an existed investor payment model to database
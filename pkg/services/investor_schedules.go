@@ -0,0 +1,575 @@
+// investor_schedules.go generates repayment schedules of investor deals and compares them with the recorded payments.
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	// maxInvestorSchedulePeriods limits open-ended schedules to 50 years of monthly instalments
+	maxInvestorSchedulePeriods = 600
+
+	// investorScheduleRevenueProjectionPeriods is the number of latest actual periods averaged to project future revenue
+	investorScheduleRevenueProjectionPeriods = 3
+)
+
+// InvestorScheduleService represents investor deal repayment schedule service
+type InvestorScheduleService struct {
+	ServiceUsingDB
+	deals        InvestorDealProvider
+	payments     InvestorPaymentProvider
+	transactions *TransactionService
+}
+
+// Initialize an investor schedule service singleton instance
+var (
+	InvestorSchedules = &InvestorScheduleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		deals:        InvestorDeals,
+		payments:     InvestorPayments,
+		transactions: Transactions,
+	}
+)
+
+// investorScheduleRevenueFunc returns the actual revenue in the period (fromMs, toMs]
+type investorScheduleRevenueFunc func(fromMs int64, toMs int64) int64
+
+// GetSchedule returns the monthly repayment schedule of the specified deal compared with its recorded payments.
+//
+//   - Loan deals are repaid by annuity (equal instalments, or the fixed payment if set) or linear (equal principal parts)
+//     instalments, the interest of each period is accrued on the remaining principal at annual rate / 12.
+//   - Revenue share deals pay the profit share percentage of the actual income of the deal CFO in each period,
+//     future periods are projected by the average revenue of the latest periods, until the total to repay is reached.
+//
+// Recorded payments are allocated to instalments in date order, so instalments are paid, paid ahead of schedule,
+// partially paid, overdue or upcoming. All dates are in milliseconds.
+func (s *InvestorScheduleService) GetSchedule(c core.Context, uid int64, dealId int64) (*models.InvestorScheduleResponse, error) {
+	return s.getScheduleAsOf(c, uid, dealId, time.Now().UnixMilli())
+}
+
+// CreatePlannedTransactions creates planned expense transactions for the unpaid parts of all future instalments of the specified deal,
+// and returns the count of created transactions. Every planned transaction is linked to its instalment by the deal id, instalment number
+// and the part of instalment it pays, and the instalment parts which already have a planned transaction are skipped.
+func (s *InvestorScheduleService) CreatePlannedTransactions(c core.Context, uid int64, req *models.InvestorSchedulePlannedTransactionsCreateRequest) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	deal, err := s.deals.GetDealByDealId(c, uid, req.DealId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	account := &models.Account{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(req.AccountId).Where("uid=? AND deleted=?", uid, false).Get(account)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrAccountNotFound
+	}
+
+	if account.Currency != deal.Currency {
+		return 0, errs.ErrInvestorDealCurrencyMismatch
+	}
+
+	nowMs := time.Now().UnixMilli()
+	schedule, err := s.getScheduleAsOf(c, uid, deal.DealId, nowMs)
+
+	if err != nil {
+		return 0, err
+	}
+
+	plannedTransactions := make([]*models.Transaction, 0, len(schedule.Instalments))
+	plannedTransactionLinks := make([]*models.InvestorScheduleTransaction, 0, len(schedule.Instalments))
+	addPlannedTransaction := func(instalment *models.InvestorScheduleInstalment, part models.InvestorScheduleTransactionPart, categoryId int64, amount int64, comment string) {
+		plannedTransactions = append(plannedTransactions, s.newPlannedTransaction(uid, deal, req, categoryId, instalment.Date, amount, comment))
+		plannedTransactionLinks = append(plannedTransactionLinks, &models.InvestorScheduleTransaction{
+			Uid:              uid,
+			DealId:           deal.DealId,
+			InstalmentNumber: instalment.Number,
+			Part:             part,
+		})
+	}
+
+	for _, instalment := range schedule.Instalments {
+		if instalment.Date <= nowMs || instalment.PaidAmount >= instalment.Payment {
+			continue
+		}
+
+		// recorded payments settle the interest of instalment first
+		remainingInterest := instalment.Interest - instalment.PaidAmount
+
+		if remainingInterest < 0 {
+			remainingInterest = 0
+		}
+
+		remainingPrincipal := instalment.Payment - instalment.PaidAmount - remainingInterest
+		comment := fmt.Sprintf("%s: instalment %d/%d", deal.InvestorName, instalment.Number, len(schedule.Instalments))
+
+		if req.InterestCategoryId <= 0 {
+			addPlannedTransaction(instalment, models.INVESTOR_SCHEDULE_TRANSACTION_PART_PAYMENT, req.PrincipalCategoryId, remainingPrincipal+remainingInterest, comment)
+			continue
+		}
+
+		if remainingPrincipal > 0 {
+			addPlannedTransaction(instalment, models.INVESTOR_SCHEDULE_TRANSACTION_PART_PRINCIPAL, req.PrincipalCategoryId, remainingPrincipal, comment+" (principal)")
+		}
+
+		if remainingInterest > 0 {
+			addPlannedTransaction(instalment, models.INVESTOR_SCHEDULE_TRANSACTION_PART_INTEREST, req.InterestCategoryId, remainingInterest, comment+" (interest)")
+		}
+	}
+
+	if len(plannedTransactions) < 1 {
+		return 0, errs.ErrInvestorDealNoFutureInstalments
+	}
+
+	count := 0
+
+	for i, transaction := range plannedTransactions {
+		link := plannedTransactionLinks[i]
+		exists, err := s.hasPlannedTransaction(c, link)
+
+		if err != nil {
+			return count, err
+		} else if exists {
+			continue
+		}
+
+		database := s.UserDataDB(uid)
+		err = database.DoTransaction(c, func(sess *xorm.Session) error {
+			err := s.transactions.CreateTransactionInSession(c, database, sess, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			link.TransactionId = transaction.TransactionId
+			link.CreatedUnixTime = time.Now().Unix()
+
+			_, err = sess.Insert(link)
+
+			return err
+		})
+
+		if err != nil {
+			log.Warnf(c, "[investor_schedules.CreatePlannedTransactions] failed to create planned transaction for deal \"id:%d\" of user \"uid:%d\", because %s", deal.DealId, uid, err.Error())
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// hasPlannedTransaction returns whether the part of instalment already has a planned transaction which is not deleted
+func (s *InvestorScheduleService) hasPlannedTransaction(c core.Context, link *models.InvestorScheduleTransaction) (bool, error) {
+	query := `SELECT l.transaction_id
+		FROM investor_schedule_transaction l
+		INNER JOIN "transaction" t ON t.transaction_id = l.transaction_id AND t.uid = l.uid
+		WHERE l.uid = ? AND l.deal_id = ? AND l.instalment_number = ? AND l.part = ? AND t.deleted = 0 AND t.planned = 1`
+
+	var rows []struct {
+		TransactionId int64 `xorm:"transaction_id"`
+	}
+
+	err := s.UserDataDB(link.Uid).NewSession(c).SQL(query, link.Uid, link.DealId, link.InstalmentNumber, link.Part).Find(&rows)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(rows) > 0, nil
+}
+
+func (s *InvestorScheduleService) newPlannedTransaction(uid int64, deal *models.InvestorDeal, req *models.InvestorSchedulePlannedTransactionsCreateRequest, categoryId int64, dateMs int64, amount int64, comment string) *models.Transaction {
+	return &models.Transaction{
+		Uid:               uid,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:        categoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(dateMs / 1000),
+		TimezoneUtcOffset: req.UtcOffset,
+		AccountId:         req.AccountId,
+		Amount:            amount,
+		Comment:           comment,
+		CounterpartyId:    req.CounterpartyId,
+		CfoId:             deal.CfoId,
+		Planned:           true,
+	}
+}
+
+func (s *InvestorScheduleService) getScheduleAsOf(c core.Context, uid int64, dealId int64, nowMs int64) (*models.InvestorScheduleResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	deal, err := s.deals.GetDealByDealId(c, uid, dealId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.payments.GetAllPaymentsByDealId(c, uid, deal.DealId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var revenueOf investorScheduleRevenueFunc
+
+	if deal.DealType == models.INVESTOR_DEAL_TYPE_REVENUE_SHARE {
+		revenueOf, err = s.getRevenueFunc(c, uid, deal)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buildInvestorSchedule(deal, payments, revenueOf, nowMs)
+}
+
+// getRevenueFunc returns the function which sums the actual income in the deal currency of the deal CFO (or all CFOs if not set)
+func (s *InvestorScheduleService) getRevenueFunc(c core.Context, uid int64, deal *models.InvestorDeal) (investorScheduleRevenueFunc, error) {
+	query := fmt.Sprintf(`SELECT t.transaction_time, t.amount
+		FROM "transaction" t
+		INNER JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
+		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0 AND t.type = %d AND a.currency = ?`, models.TRANSACTION_DB_TYPE_INCOME)
+	args := []any{uid, deal.Currency}
+
+	if deal.CfoId > 0 {
+		query += cfoFilterClause
+		args = append(args, deal.CfoId)
+	}
+
+	var rows []struct {
+		TransactionTime int64 `xorm:"transaction_time"`
+		Amount          int64 `xorm:"amount"`
+	}
+
+	err := s.UserDataDB(uid).NewSession(c).SQL(query, args...).Find(&rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(fromMs int64, toMs int64) int64 {
+		revenue := int64(0)
+
+		for _, row := range rows {
+			if row.TransactionTime > fromMs && row.TransactionTime <= toMs {
+				revenue += row.Amount
+			}
+		}
+
+		return revenue
+	}, nil
+}
+
+// buildInvestorSchedule returns the repayment schedule of the deal compared with the payments as of the specified time
+func buildInvestorSchedule(deal *models.InvestorDeal, payments []*models.InvestorPayment, revenueOf investorScheduleRevenueFunc, nowMs int64) (*models.InvestorScheduleResponse, error) {
+	dates, err := getInvestorScheduleDates(deal)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var instalments []*models.InvestorScheduleInstalment
+
+	switch deal.DealType {
+	case models.INVESTOR_DEAL_TYPE_LOAN:
+		instalments, err = buildLoanInstalments(deal, dates)
+	case models.INVESTOR_DEAL_TYPE_REVENUE_SHARE:
+		instalments, err = buildRevenueShareInstalments(deal, dates, revenueOf, nowMs)
+	default:
+		return nil, errs.ErrInvestorDealScheduleNotSupported
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &models.InvestorScheduleResponse{
+		DealId:      deal.DealId,
+		DealType:    deal.DealType,
+		Currency:    deal.Currency,
+		Instalments: instalments,
+	}
+
+	if deal.DealType == models.INVESTOR_DEAL_TYPE_LOAN {
+		schedule.RepaymentMethod = getInvestorRepaymentMethod(deal)
+	}
+
+	for _, payment := range payments {
+		schedule.TotalPaid += payment.Amount
+	}
+
+	unallocatedPaid := schedule.TotalPaid
+
+	for _, instalment := range instalments {
+		schedule.TotalPrincipal += instalment.Principal
+		schedule.TotalInterest += instalment.Interest
+		schedule.TotalPayment += instalment.Payment
+
+		isDue := instalment.Date <= nowMs
+
+		if isDue {
+			schedule.ScheduledToDate += instalment.Payment
+		}
+
+		instalment.PaidAmount = instalment.Payment
+
+		if unallocatedPaid < instalment.PaidAmount {
+			instalment.PaidAmount = unallocatedPaid
+		}
+
+		unallocatedPaid -= instalment.PaidAmount
+
+		switch {
+		case instalment.PaidAmount >= instalment.Payment && isDue:
+			instalment.Status = models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID
+		case instalment.PaidAmount >= instalment.Payment && instalment.Payment > 0:
+			instalment.Status = models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID_AHEAD
+		case isDue:
+			instalment.Status = models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_OVERDUE
+		case instalment.PaidAmount > 0:
+			instalment.Status = models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PARTIAL
+		default:
+			instalment.Status = models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_UPCOMING
+		}
+	}
+
+	if schedule.ScheduledToDate > schedule.TotalPaid {
+		schedule.OverdueAmount = schedule.ScheduledToDate - schedule.TotalPaid
+	} else {
+		schedule.AheadAmount = schedule.TotalPaid - schedule.ScheduledToDate
+	}
+
+	return schedule, nil
+}
+
+// getInvestorScheduleDates returns the monthly instalment dates (in milliseconds) from the repayment start date to the repayment end date,
+// the first instalment is one month after the investment date if the repayment start date is not set,
+// and the dates are open-ended (limited by maxInvestorSchedulePeriods) if the repayment end date is not set
+func getInvestorScheduleDates(deal *models.InvestorDeal) ([]int64, error) {
+	startMs := utils.ToMillisIfSeconds(deal.RepaymentStartDate)
+
+	if startMs <= 0 {
+		investmentMs := utils.ToMillisIfSeconds(deal.InvestmentDate)
+
+		if investmentMs <= 0 {
+			return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+		}
+
+		startMs = addMonthsClamped(time.UnixMilli(investmentMs).UTC(), 1).UnixMilli()
+	}
+
+	endMs := utils.ToMillisIfSeconds(deal.RepaymentEndDate)
+
+	if endMs > 0 && endMs < startMs {
+		return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+	}
+
+	start := time.UnixMilli(startMs).UTC()
+	dates := make([]int64, 0)
+
+	for i := 0; i < maxInvestorSchedulePeriods; i++ {
+		date := addMonthsClamped(start, i).UnixMilli()
+
+		if endMs > 0 && date > endMs {
+			break
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+// addMonthsClamped returns the time the specified months later, the day is clamped to the last day of the target month
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstDayOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, months, 0)
+	lastDay := firstDayOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstDayOfMonth.AddDate(0, 0, day-1)
+}
+
+func getInvestorRepaymentMethod(deal *models.InvestorDeal) models.InvestorRepaymentMethod {
+	if deal.RepaymentMethod == models.INVESTOR_REPAYMENT_METHOD_LINEAR {
+		return models.INVESTOR_REPAYMENT_METHOD_LINEAR
+	}
+
+	return models.INVESTOR_REPAYMENT_METHOD_ANNUITY
+}
+
+// buildLoanInstalments returns the annuity or linear instalments of loan deal, the last instalment repays all remaining principal
+func buildLoanInstalments(deal *models.InvestorDeal, dates []int64) ([]*models.InvestorScheduleInstalment, error) {
+	principal := deal.InvestmentAmount
+	isOpenEnded := utils.ToMillisIfSeconds(deal.RepaymentEndDate) <= 0
+	method := getInvestorRepaymentMethod(deal)
+
+	if principal <= 0 || len(dates) < 1 || (isOpenEnded && (method != models.INVESTOR_REPAYMENT_METHOD_ANNUITY || deal.FixedPayment <= 0)) {
+		return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+	}
+
+	monthlyRate := float64(deal.AnnualRate) / 100 / 12
+	periods := len(dates)
+	annuityPayment := deal.FixedPayment
+
+	if method == models.INVESTOR_REPAYMENT_METHOD_ANNUITY && annuityPayment <= 0 {
+		if monthlyRate > 0 {
+			annuityPayment = int64(math.Round(float64(principal) * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(periods)))))
+		} else {
+			annuityPayment = int64(math.Round(float64(principal) / float64(periods)))
+		}
+	}
+
+	instalments := make([]*models.InvestorScheduleInstalment, 0, periods)
+	balance := principal
+
+	for i := 0; i < periods && balance > 0; i++ {
+		interest := int64(math.Round(float64(balance) * monthlyRate))
+		var principalPart int64
+
+		if method == models.INVESTOR_REPAYMENT_METHOD_LINEAR {
+			principalPart = principal / int64(periods)
+		} else {
+			principalPart = annuityPayment - interest
+		}
+
+		if principalPart <= 0 && i < periods-1 {
+			// the fixed payment does not even cover the interest, so the loan would never be repaid
+			return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+		}
+
+		if i == periods-1 || principalPart > balance {
+			principalPart = balance
+		}
+
+		balance -= principalPart
+
+		instalments = append(instalments, &models.InvestorScheduleInstalment{
+			Number:             i + 1,
+			Date:               dates[i],
+			Principal:          principalPart,
+			Interest:           interest,
+			Payment:            principalPart + interest,
+			RemainingPrincipal: balance,
+		})
+	}
+
+	if balance > 0 {
+		return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+	}
+
+	return instalments, nil
+}
+
+// buildRevenueShareInstalments returns the instalments of revenue share deal, each instalment is the profit share of the revenue
+// in its period, the part exceeding the investment amount is regarded as interest
+func buildRevenueShareInstalments(deal *models.InvestorDeal, dates []int64, revenueOf investorScheduleRevenueFunc, nowMs int64) ([]*models.InvestorScheduleInstalment, error) {
+	isOpenEnded := utils.ToMillisIfSeconds(deal.RepaymentEndDate) <= 0
+
+	if deal.ProfitSharePct <= 0 || len(dates) < 1 || (isOpenEnded && deal.TotalToRepay <= 0) {
+		return nil, errs.ErrInvestorDealRepaymentTermsInvalid
+	}
+
+	instalments := make([]*models.InvestorScheduleInstalment, 0, len(dates))
+	actualRevenues := make([]int64, 0, len(dates))
+	totalPayment := int64(0)
+	balance := deal.InvestmentAmount
+
+	for i := 0; i < len(dates); i++ {
+		periodStart := addMonthsClamped(time.UnixMilli(dates[i]).UTC(), -1).UnixMilli()
+
+		if i > 0 {
+			periodStart = dates[i-1]
+		}
+
+		instalment := &models.InvestorScheduleInstalment{
+			Number: i + 1,
+			Date:   dates[i],
+		}
+
+		if dates[i] <= nowMs && revenueOf != nil {
+			instalment.Revenue = revenueOf(periodStart, dates[i])
+			actualRevenues = append(actualRevenues, instalment.Revenue)
+		} else {
+			instalment.Revenue = getProjectedRevenue(actualRevenues)
+			instalment.Projected = true
+
+			if instalment.Revenue <= 0 && isOpenEnded {
+				// nothing can be projected, so the rest of an open-ended schedule is unknown
+				break
+			}
+		}
+
+		payment := int64(math.Round(float64(instalment.Revenue) * float64(deal.ProfitSharePct) / 100))
+
+		if deal.TotalToRepay > 0 && totalPayment+payment > deal.TotalToRepay {
+			payment = deal.TotalToRepay - totalPayment
+		}
+
+		principalPart := payment
+
+		if principalPart > balance {
+			principalPart = balance
+		}
+
+		balance -= principalPart
+		totalPayment += payment
+
+		instalment.Principal = principalPart
+		instalment.Interest = payment - principalPart
+		instalment.Payment = payment
+		instalment.RemainingPrincipal = balance
+		instalments = append(instalments, instalment)
+
+		if deal.TotalToRepay > 0 && totalPayment >= deal.TotalToRepay {
+			break
+		}
+	}
+
+	return instalments, nil
+}
+
+// getProjectedRevenue returns the average revenue of the latest actual periods
+func getProjectedRevenue(actualRevenues []int64) int64 {
+	count := len(actualRevenues)
+
+	if count > investorScheduleRevenueProjectionPeriods {
+		count = investorScheduleRevenueProjectionPeriods
+	}
+
+	if count < 1 {
+		return 0
+	}
+
+	total := int64(0)
+
+	for _, revenue := range actualRevenues[len(actualRevenues)-count:] {
+		total += revenue
+	}
+
+	return total / int64(count)
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func newTestInvestorScheduleService(t *testing.T) (*InvestorScheduleService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	uuidContainer := initUuidContainer(t)
+	dbService := ServiceUsingDB{container: tdb.container}
+	uuidService := ServiceUsingUuid{container: uuidContainer}
+	svc := &InvestorScheduleService{
+		ServiceUsingDB: dbService,
+		deals:          &InvestorDealService{ServiceUsingDB: dbService, ServiceUsingUuid: uuidService},
+		payments:       &InvestorPaymentService{ServiceUsingDB: dbService, ServiceUsingUuid: uuidService},
+		transactions:   &TransactionService{ServiceUsingDB: dbService, ServiceUsingUuid: uuidService},
+	}
+	return svc, tdb
+}
+
+func getTestScheduleDate(year int, month time.Month, day int) int64 {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	jan31 := time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, time.February, 29, 10, 0, 0, 0, time.UTC), addMonthsClamped(jan31, 1))
+	assert.Equal(t, time.Date(2024, time.March, 31, 10, 0, 0, 0, time.UTC), addMonthsClamped(jan31, 2))
+	assert.Equal(t, time.Date(2023, time.December, 31, 10, 0, 0, 0, time.UTC), addMonthsClamped(jan31, -1))
+}
+
+func TestBuildInvestorSchedule_AnnuityLoan(t *testing.T) {
+	deal := &models.InvestorDeal{
+		DealId:             1,
+		DealType:           models.INVESTOR_DEAL_TYPE_LOAN,
+		RepaymentMethod:    models.INVESTOR_REPAYMENT_METHOD_ANNUITY,
+		InvestmentAmount:   1200000,
+		AnnualRate:         12,
+		Currency:           "RUB",
+		RepaymentStartDate: getTestScheduleDate(2024, time.January, 15),
+		RepaymentEndDate:   getTestScheduleDate(2024, time.December, 15),
+	}
+
+	schedule, err := buildInvestorSchedule(deal, nil, nil, getTestScheduleDate(2024, time.January, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(schedule.Instalments))
+	assert.Equal(t, models.INVESTOR_REPAYMENT_METHOD_ANNUITY, schedule.RepaymentMethod)
+
+	// 1% monthly on 12000.00 for 12 months makes the annuity payment 1066.19
+	first := schedule.Instalments[0]
+	assert.Equal(t, getTestScheduleDate(2024, time.January, 15), first.Date)
+	assert.Equal(t, int64(12000), first.Interest)
+	assert.Equal(t, int64(94619), first.Principal)
+	assert.Equal(t, int64(106619), first.Payment)
+	assert.Equal(t, int64(1105381), first.RemainingPrincipal)
+
+	last := schedule.Instalments[11]
+	assert.Equal(t, getTestScheduleDate(2024, time.December, 15), last.Date)
+	assert.Equal(t, int64(0), last.RemainingPrincipal)
+	assert.InDelta(t, 106619, last.Payment, 5)
+
+	assert.Equal(t, int64(1200000), schedule.TotalPrincipal)
+	assert.Equal(t, schedule.TotalPrincipal+schedule.TotalInterest, schedule.TotalPayment)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_UPCOMING, first.Status)
+}
+
+func TestBuildInvestorSchedule_LinearLoan(t *testing.T) {
+	deal := &models.InvestorDeal{
+		DealType:           models.INVESTOR_DEAL_TYPE_LOAN,
+		RepaymentMethod:    models.INVESTOR_REPAYMENT_METHOD_LINEAR,
+		InvestmentAmount:   100000,
+		AnnualRate:         12,
+		RepaymentStartDate: getTestScheduleDate(2024, time.January, 1),
+		RepaymentEndDate:   getTestScheduleDate(2024, time.March, 1),
+	}
+
+	schedule, err := buildInvestorSchedule(deal, nil, nil, getTestScheduleDate(2023, time.December, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(schedule.Instalments))
+
+	assert.Equal(t, int64(33333), schedule.Instalments[0].Principal)
+	assert.Equal(t, int64(1000), schedule.Instalments[0].Interest)
+	assert.Equal(t, int64(33333), schedule.Instalments[1].Principal)
+	assert.Equal(t, int64(667), schedule.Instalments[1].Interest)
+	assert.Equal(t, int64(33334), schedule.Instalments[2].Principal)
+	assert.Equal(t, int64(333), schedule.Instalments[2].Interest)
+	assert.Equal(t, int64(2000), schedule.TotalInterest)
+}
+
+func TestBuildInvestorSchedule_FixedPaymentWithoutEndDate(t *testing.T) {
+	deal := &models.InvestorDeal{
+		DealType:         models.INVESTOR_DEAL_TYPE_LOAN,
+		InvestmentAmount: 100000,
+		InvestmentDate:   getTestScheduleDate(2024, time.January, 31),
+		FixedPayment:     30000,
+	}
+
+	schedule, err := buildInvestorSchedule(deal, nil, nil, getTestScheduleDate(2024, time.January, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(schedule.Instalments))
+	assert.Equal(t, getTestScheduleDate(2024, time.February, 29), schedule.Instalments[0].Date)
+	assert.Equal(t, int64(30000), schedule.Instalments[0].Payment)
+	assert.Equal(t, int64(10000), schedule.Instalments[3].Payment)
+
+	// The fixed payment does not cover the interest
+	deal.AnnualRate = 600
+	_, err = buildInvestorSchedule(deal, nil, nil, 0)
+	assert.Equal(t, errs.ErrInvestorDealRepaymentTermsInvalid, err)
+}
+
+func TestBuildInvestorSchedule_InvalidTerms(t *testing.T) {
+	_, err := buildInvestorSchedule(&models.InvestorDeal{DealType: models.INVESTOR_DEAL_TYPE_EQUITY, InvestmentDate: getTestScheduleDate(2024, time.January, 1)}, nil, nil, 0)
+	assert.Equal(t, errs.ErrInvestorDealScheduleNotSupported, err)
+
+	_, err = buildInvestorSchedule(&models.InvestorDeal{DealType: models.INVESTOR_DEAL_TYPE_LOAN, InvestmentAmount: 1000}, nil, nil, 0)
+	assert.Equal(t, errs.ErrInvestorDealRepaymentTermsInvalid, err)
+
+	// Linear loan without end date
+	_, err = buildInvestorSchedule(&models.InvestorDeal{DealType: models.INVESTOR_DEAL_TYPE_LOAN, RepaymentMethod: models.INVESTOR_REPAYMENT_METHOD_LINEAR, InvestmentAmount: 1000, RepaymentStartDate: getTestScheduleDate(2024, time.January, 1)}, nil, nil, 0)
+	assert.Equal(t, errs.ErrInvestorDealRepaymentTermsInvalid, err)
+
+	// End date before start date
+	_, err = buildInvestorSchedule(&models.InvestorDeal{DealType: models.INVESTOR_DEAL_TYPE_LOAN, InvestmentAmount: 1000, RepaymentStartDate: getTestScheduleDate(2024, time.February, 1), RepaymentEndDate: getTestScheduleDate(2024, time.January, 1)}, nil, nil, 0)
+	assert.Equal(t, errs.ErrInvestorDealRepaymentTermsInvalid, err)
+}
+
+func TestBuildInvestorSchedule_ComparesWithPayments(t *testing.T) {
+	deal := &models.InvestorDeal{
+		DealType:           models.INVESTOR_DEAL_TYPE_LOAN,
+		RepaymentMethod:    models.INVESTOR_REPAYMENT_METHOD_LINEAR,
+		InvestmentAmount:   40000,
+		RepaymentStartDate: getTestScheduleDate(2024, time.January, 1),
+		RepaymentEndDate:   getTestScheduleDate(2024, time.April, 1),
+	}
+	now := getTestScheduleDate(2024, time.February, 15)
+
+	// One and a half instalments are paid while two are due
+	payments := []*models.InvestorPayment{
+		{PaymentDate: getTestScheduleDate(2024, time.January, 1), Amount: 10000},
+		{PaymentDate: getTestScheduleDate(2024, time.February, 1), Amount: 5000},
+	}
+
+	schedule, err := buildInvestorSchedule(deal, payments, nil, now)
+	assert.Nil(t, err)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID, schedule.Instalments[0].Status)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_OVERDUE, schedule.Instalments[1].Status)
+	assert.Equal(t, int64(5000), schedule.Instalments[1].PaidAmount)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_UPCOMING, schedule.Instalments[2].Status)
+	assert.Equal(t, int64(20000), schedule.ScheduledToDate)
+	assert.Equal(t, int64(15000), schedule.TotalPaid)
+	assert.Equal(t, int64(5000), schedule.OverdueAmount)
+	assert.Equal(t, int64(0), schedule.AheadAmount)
+
+	// Two and a half instalments are paid while two are due
+	payments = append(payments, &models.InvestorPayment{PaymentDate: getTestScheduleDate(2024, time.February, 10), Amount: 20000})
+
+	schedule, err = buildInvestorSchedule(deal, payments, nil, now)
+	assert.Nil(t, err)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID, schedule.Instalments[1].Status)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PAID_AHEAD, schedule.Instalments[2].Status)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_INSTALMENT_STATUS_PARTIAL, schedule.Instalments[3].Status)
+	assert.Equal(t, int64(0), schedule.OverdueAmount)
+	assert.Equal(t, int64(15000), schedule.AheadAmount)
+}
+
+func TestBuildInvestorSchedule_RevenueShare(t *testing.T) {
+	deal := &models.InvestorDeal{
+		DealType:           models.INVESTOR_DEAL_TYPE_REVENUE_SHARE,
+		InvestmentAmount:   50000,
+		ProfitSharePct:     10,
+		TotalToRepay:       70000,
+		RepaymentStartDate: getTestScheduleDate(2024, time.February, 1),
+	}
+	revenues := map[int64]int64{
+		getTestScheduleDate(2024, time.February, 1): 100000,
+		getTestScheduleDate(2024, time.March, 1):    200000,
+	}
+	revenueOf := func(fromMs int64, toMs int64) int64 {
+		assert.Equal(t, addMonthsClamped(time.UnixMilli(toMs).UTC(), -1).UnixMilli(), fromMs)
+		return revenues[toMs]
+	}
+
+	schedule, err := buildInvestorSchedule(deal, nil, revenueOf, getTestScheduleDate(2024, time.March, 15))
+	assert.Nil(t, err)
+
+	// 10000 and 20000 actually, then 15000 per month projected until 70000 is repaid
+	assert.Equal(t, 5, len(schedule.Instalments))
+	assert.Equal(t, int64(10000), schedule.Instalments[0].Payment)
+	assert.False(t, schedule.Instalments[0].Projected)
+	assert.Equal(t, int64(20000), schedule.Instalments[1].Payment)
+	assert.Equal(t, int64(150000), schedule.Instalments[2].Revenue)
+	assert.True(t, schedule.Instalments[2].Projected)
+	assert.Equal(t, int64(15000), schedule.Instalments[2].Payment)
+	assert.Equal(t, int64(15000), schedule.Instalments[3].Payment)
+	assert.Equal(t, int64(10000), schedule.Instalments[4].Payment)
+
+	// The investment amount is repaid first, the rest is the investor return
+	assert.Equal(t, int64(5000), schedule.Instalments[3].Principal)
+	assert.Equal(t, int64(10000), schedule.Instalments[3].Interest)
+	assert.Equal(t, int64(50000), schedule.TotalPrincipal)
+	assert.Equal(t, int64(20000), schedule.TotalInterest)
+	assert.Equal(t, int64(70000), schedule.TotalPayment)
+}
+
+func TestInvestorScheduleService_GetSchedule_RevenueShareWithDB(t *testing.T) {
+	svc, tdb := newTestInvestorScheduleService(t)
+	defer tdb.close()
+
+	deal := &models.InvestorDeal{
+		Uid:                1,
+		InvestorName:       "Angel",
+		CfoId:              5,
+		DealType:           models.INVESTOR_DEAL_TYPE_REVENUE_SHARE,
+		InvestmentAmount:   50000,
+		Currency:           "RUB",
+		ProfitSharePct:     20,
+		RepaymentStartDate: getTestScheduleDate(2024, time.February, 1),
+		RepaymentEndDate:   getTestScheduleDate(2024, time.March, 1),
+	}
+	assert.Nil(t, svc.deals.CreateDeal(nil, deal))
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 1, Uid: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Checking", Currency: "RUB"})
+	assert.Nil(t, err)
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CfoId: 5, TransactionTime: getTestScheduleDate(2024, time.January, 10), Amount: 30000},
+		{TransactionId: 2, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CfoId: 5, TransactionTime: getTestScheduleDate(2024, time.February, 10), Amount: 50000},
+		// income of another CFO is excluded
+		{TransactionId: 3, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CfoId: 6, TransactionTime: getTestScheduleDate(2024, time.February, 11), Amount: 70000},
+		// planned income is excluded
+		{TransactionId: 4, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CfoId: 5, TransactionTime: getTestScheduleDate(2024, time.February, 12), Amount: 90000, Planned: true},
+	}
+	for _, transaction := range transactions {
+		_, err = tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	schedule, err := svc.getScheduleAsOf(nil, 1, deal.DealId, getTestScheduleDate(2024, time.March, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(schedule.Instalments))
+	assert.Equal(t, int64(30000), schedule.Instalments[0].Revenue)
+	assert.Equal(t, int64(6000), schedule.Instalments[0].Payment)
+	assert.Equal(t, int64(50000), schedule.Instalments[1].Revenue)
+	assert.Equal(t, int64(10000), schedule.Instalments[1].Payment)
+	assert.Equal(t, int64(16000), schedule.OverdueAmount)
+}
+
+func TestInvestorScheduleService_CreatePlannedTransactions(t *testing.T) {
+	svc, tdb := newTestInvestorScheduleService(t)
+	defer tdb.close()
+
+	now := time.Now().UTC()
+	deal := &models.InvestorDeal{
+		Uid:                1,
+		InvestorName:       "Bank",
+		DealType:           models.INVESTOR_DEAL_TYPE_LOAN,
+		RepaymentMethod:    models.INVESTOR_REPAYMENT_METHOD_LINEAR,
+		InvestmentAmount:   300000,
+		AnnualRate:         12,
+		Currency:           "RUB",
+		RepaymentStartDate: addMonthsClamped(now, -1).UnixMilli(),
+		RepaymentEndDate:   addMonthsClamped(now, 2).UnixMilli(),
+	}
+	assert.Nil(t, svc.deals.CreateDeal(nil, deal))
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 1, Uid: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Checking", Currency: "RUB"})
+	assert.Nil(t, err)
+	_, err = tdb.engine.Insert(&models.Account{AccountId: 2, Uid: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Dollars", Currency: "USD"})
+	assert.Nil(t, err)
+
+	categories := []*models.TransactionCategory{
+		{CategoryId: 11, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Loan repayment"},
+		{CategoryId: 12, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Interest"},
+	}
+	for _, category := range categories {
+		_, err = tdb.engine.Insert(category)
+		assert.Nil(t, err)
+	}
+
+	req := &models.InvestorSchedulePlannedTransactionsCreateRequest{
+		DealId:              deal.DealId,
+		AccountId:           2,
+		PrincipalCategoryId: 11,
+		InterestCategoryId:  12,
+	}
+
+	_, err = svc.CreatePlannedTransactions(nil, 1, req)
+	assert.Equal(t, errs.ErrInvestorDealCurrencyMismatch, err)
+
+	// The first two instalments are already due, each of the other two has a principal and an interest transaction
+	req.AccountId = 1
+	count, err := svc.CreatePlannedTransactions(nil, 1, req)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, count)
+
+	var plannedTransactions []*models.Transaction
+	err = tdb.engine.Where("uid=? AND planned=?", 1, true).OrderBy("transaction_time asc, category_id asc").Find(&plannedTransactions)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(plannedTransactions))
+	assert.Equal(t, int64(75000), plannedTransactions[0].Amount)
+	assert.Equal(t, int64(11), plannedTransactions[0].CategoryId)
+	assert.Equal(t, "Bank: instalment 3/4 (principal)", plannedTransactions[0].Comment)
+	assert.Equal(t, int64(1500), plannedTransactions[1].Amount)
+	assert.Equal(t, int64(12), plannedTransactions[1].CategoryId)
+	assert.Equal(t, int64(750), plannedTransactions[3].Amount)
+
+	// Transactions created before are not duplicated
+	count, err = svc.CreatePlannedTransactions(nil, 1, req)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	// Transactions created before are not duplicated after the instalment count changes, only the new instalment is planned
+	_, err = tdb.engine.ID(deal.DealId).Cols("repayment_end_date").Update(&models.InvestorDeal{RepaymentEndDate: addMonthsClamped(now, 3).UnixMilli()})
+	assert.Nil(t, err)
+
+	count, err = svc.CreatePlannedTransactions(nil, 1, req)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	var links []*models.InvestorScheduleTransaction
+	err = tdb.engine.Where("uid=? AND deal_id=?", 1, deal.DealId).OrderBy("instalment_number asc, part asc").Find(&links)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(links))
+	assert.Equal(t, 3, links[0].InstalmentNumber)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_TRANSACTION_PART_PRINCIPAL, links[0].Part)
+	assert.Equal(t, 5, links[5].InstalmentNumber)
+	assert.Equal(t, models.INVESTOR_SCHEDULE_TRANSACTION_PART_INTEREST, links[5].Part)
+}
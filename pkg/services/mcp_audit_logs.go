@@ -0,0 +1,88 @@
+// mcp_audit_logs.go records the changes made by MCP tools so that they can be reviewed by the user.
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// MCPAuditLogService represents MCP audit log service
+type MCPAuditLogService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a MCP audit log service singleton instance
+var (
+	MCPAuditLogs = &MCPAuditLogService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAuditLogsByUid returns the MCP audit log models of user (latest first), only the logs of the specified token are returned if token id is not empty
+func (s *MCPAuditLogService) GetAuditLogsByUid(c core.Context, uid int64, tokenId string, page int32, count int32) ([]*models.MCPAuditLog, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if page < 1 {
+		return nil, errs.ErrPageIndexInvalid
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	var auditLogs []*models.MCPAuditLog
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid)
+
+	if tokenId != "" {
+		sess = sess.And("token_id=?", tokenId)
+	}
+
+	err := sess.OrderBy("created_unix_time desc, audit_id desc").Limit(int(count), int(count*(page-1))).Find(&auditLogs)
+
+	return auditLogs, err
+}
+
+// CreateAuditLog saves a new MCP audit log model to database
+func (s *MCPAuditLogService) CreateAuditLog(c core.Context, auditLog *models.MCPAuditLog) error {
+	if auditLog.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(auditLog.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.CreateAuditLogInSession(c, sess, auditLog)
+	})
+}
+
+// CreateAuditLogInSession saves a new MCP audit log model to database in the given session,
+// so the audit log is committed or rolled back together with the change it records
+func (s *MCPAuditLogService) CreateAuditLogInSession(c core.Context, sess *xorm.Session, auditLog *models.MCPAuditLog) error {
+	if auditLog.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	auditLog.AuditId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+	if auditLog.AuditId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	auditLog.CreatedUnixTime = time.Now().Unix()
+
+	_, err := sess.Insert(auditLog)
+	return err
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func newTestMCPAuditLogService(t *testing.T) (*MCPAuditLogService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	uuidContainer := initUuidContainer(t)
+	svc := &MCPAuditLogService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: uuidContainer},
+	}
+	return svc, tdb
+}
+
+func TestMCPAuditLogServiceCreateAndList(t *testing.T) {
+	svc, tdb := newTestMCPAuditLogService(t)
+	defer tdb.close()
+
+	tokens := []string{"1:100:1", "1:200:2", "1:100:1"}
+
+	for i := 0; i < len(tokens); i++ {
+		auditLog := &models.MCPAuditLog{
+			Uid:        1,
+			TokenId:    tokens[i],
+			ToolName:   "delete_transaction",
+			Action:     models.MCP_AUDIT_ACTION_DELETE,
+			EntityType: "transaction",
+			EntityId:   int64(i + 1),
+			Changes:    `[{"field":"amount","oldValue":"1.00"}]`,
+		}
+		assert.Nil(t, svc.CreateAuditLog(nil, auditLog))
+		assert.True(t, auditLog.AuditId > 0)
+		assert.True(t, auditLog.CreatedUnixTime > 0)
+	}
+
+	other := &models.MCPAuditLog{
+		Uid:        2,
+		TokenId:    "2:100:1",
+		ToolName:   "add_obligation",
+		Action:     models.MCP_AUDIT_ACTION_CREATE,
+		EntityType: "obligation",
+	}
+	assert.Nil(t, svc.CreateAuditLog(nil, other))
+
+	all, err := svc.GetAuditLogsByUid(nil, 1, "", 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(all))
+
+	byToken, err := svc.GetAuditLogsByUid(nil, 1, "1:100:1", 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(byToken))
+
+	secondPage, err := svc.GetAuditLogsByUid(nil, 1, "", 2, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(secondPage))
+
+	resp := byToken[0].ToMCPAuditLogInfoResponse()
+	assert.Equal(t, "delete", resp.Action)
+	assert.Equal(t, 1, len(resp.Changes))
+	assert.Equal(t, "amount", resp.Changes[0].Field)
+	assert.Equal(t, "1.00", resp.Changes[0].OldValue)
+}
+
+func TestMCPAuditLogServiceCreateAuditLogInSession_SameTransactionAsChange(t *testing.T) {
+	svc, tdb := newTestMCPAuditLogService(t)
+	defer tdb.close()
+
+	obligationSvc := &ObligationService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: svc.ServiceUsingUuid,
+	}
+	database := svc.UserDataDB(1)
+
+	// the change is rolled back if its audit log cannot be recorded
+	err := database.DoTransaction(nil, func(sess *xorm.Session) error {
+		err := obligationSvc.CreateObligationInSession(nil, sess, &models.Obligation{Uid: 1, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 1000, Currency: "USD", Status: models.OBLIGATION_STATUS_ACTIVE})
+
+		if err != nil {
+			return err
+		}
+
+		return svc.CreateAuditLogInSession(nil, sess, &models.MCPAuditLog{ToolName: "add_obligation", Action: models.MCP_AUDIT_ACTION_CREATE, EntityType: "obligation"})
+	})
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	count, err := tdb.engine.Count(&models.Obligation{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// the change and its audit log are committed together
+	obligation := &models.Obligation{Uid: 1, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 1000, Currency: "USD", Status: models.OBLIGATION_STATUS_ACTIVE}
+	err = database.DoTransaction(nil, func(sess *xorm.Session) error {
+		err := obligationSvc.CreateObligationInSession(nil, sess, obligation)
+
+		if err != nil {
+			return err
+		}
+
+		return svc.CreateAuditLogInSession(nil, sess, &models.MCPAuditLog{Uid: 1, TokenId: "stdio:test", ToolName: "add_obligation", Action: models.MCP_AUDIT_ACTION_CREATE, EntityType: "obligation", EntityId: obligation.ObligationId})
+	})
+	assert.Nil(t, err)
+
+	count, err = tdb.engine.Count(&models.Obligation{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	auditLogs, err := svc.GetAuditLogsByUid(nil, 1, "stdio:test", 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(auditLogs))
+	assert.Equal(t, obligation.ObligationId, auditLogs[0].EntityId)
+}
+
+func TestMCPAuditLogServiceInvalidParameters(t *testing.T) {
+	svc, tdb := newTestMCPAuditLogService(t)
+	defer tdb.close()
+
+	assert.Equal(t, errs.ErrUserIdInvalid, svc.CreateAuditLog(nil, &models.MCPAuditLog{}))
+
+	_, err := svc.GetAuditLogsByUid(nil, 0, "", 1, 10)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, err = svc.GetAuditLogsByUid(nil, 1, "", 0, 10)
+	assert.Equal(t, errs.ErrPageIndexInvalid, err)
+
+	_, err = svc.GetAuditLogsByUid(nil, 1, "", 1, 0)
+	assert.Equal(t, errs.ErrPageCountInvalid, err)
+}
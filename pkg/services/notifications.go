@@ -0,0 +1,499 @@
+// notifications.go sends the weekly digest and due reminder emails according to the notification preferences of users.
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	weeklyDigestDays                    = 7
+	weeklyDigestTopExpenseCategoryCount = 5
+	weeklyDigestBudgetDeviationCount    = 5
+	notificationMailDateFormat          = "2006-01-02"
+)
+
+// notificationMailLine represents a line with name and amount in notification mail
+type notificationMailLine struct {
+	Name   string
+	Amount string
+}
+
+// notificationMailBudgetLine represents a budget deviation line of category in weekly digest mail
+type notificationMailBudgetLine struct {
+	Name      string
+	Planned   string
+	Actual    string
+	Deviation string
+}
+
+// notificationMailDueItem represents a payment which falls due in due reminder mail
+type notificationMailDueItem struct {
+	Type        string
+	Description string
+	Amount      string
+}
+
+// NotificationService represents notification service
+type NotificationService struct {
+	ServiceUsingDB
+	budgets BudgetProvider
+	reports ReportProvider
+	mailer  mail.Mailer
+}
+
+// Initialize a notification service singleton instance
+var (
+	Notifications = &NotificationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		budgets: Budgets,
+		reports: Reports,
+		mailer:  mail.Container,
+	}
+)
+
+// GetNotificationPreference returns the notification preference model of user, all notifications are turned off if user has not saved it
+func (s *NotificationService) GetNotificationPreference(c core.Context, uid int64) (*models.NotificationPreference, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	preference := &models.NotificationPreference{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Get(preference)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return models.NewDefaultNotificationPreference(uid), nil
+	}
+
+	return preference, nil
+}
+
+// SaveNotificationPreference saves the notification preference model of user to database
+func (s *NotificationService) SaveNotificationPreference(c core.Context, preference *models.NotificationPreference) error {
+	if preference.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	preference.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(preference.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=?", preference.Uid).Exist(&models.NotificationPreference{})
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = sess.Cols("weekly_digest_enabled", "weekly_digest_day_of_week", "due_reminders_enabled", "reminder_days_before", "timezone_utc_offset", "updated_unix_time").Where("uid=?", preference.Uid).Update(preference)
+			return err
+		}
+
+		preference.CreatedUnixTime = preference.UpdatedUnixTime
+		_, err = sess.Insert(preference)
+
+		return err
+	})
+}
+
+// SendNotifications sends the weekly digest to the users whose digest day is today, and the due reminders of the payments
+// which fall due in the days before set by users, every notification is sent at most once a day, and the days are in the timezone
+// saved with the notification preference of each user
+func (s *NotificationService) SendNotifications(c core.Context, currentUnixTime int64) error {
+	var allPreferences []*models.NotificationPreference
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var preferences []*models.NotificationPreference
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("weekly_digest_enabled=? OR due_reminders_enabled=?", true, true).Find(&preferences)
+
+		if err != nil {
+			return err
+		}
+
+		allPreferences = append(allPreferences, preferences...)
+	}
+
+	if len(allPreferences) < 1 {
+		return nil
+	}
+
+	sentCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allPreferences); i++ {
+		preference := allPreferences[i]
+		user := &models.User{}
+		has, err := s.UserDB().NewSession(c).ID(preference.Uid).Where("deleted=?", false).Get(user)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[notifications.SendNotifications] failed to get user \"uid:%d\", because %s", preference.Uid, err.Error())
+			continue
+		} else if !has || user.Disabled || user.Email == "" {
+			continue
+		}
+
+		now := time.Unix(currentUnixTime, 0).In(time.FixedZone("User Timezone", int(preference.TimezoneUtcOffset)*60))
+
+		if preference.WeeklyDigestEnabled && core.WeekDay(now.Weekday()) == preference.WeeklyDigestDayOfWeek && !isSameNotificationDay(preference.LastWeeklyDigestUnixTime, now) {
+			err = s.sendWeeklyDigest(c, user, now)
+
+			if err != nil {
+				failedCount++
+				log.Errorf(c, "[notifications.SendNotifications] failed to send weekly digest to user \"uid:%d\", because %s", user.Uid, err.Error())
+			} else {
+				sentCount++
+				preference.LastWeeklyDigestUnixTime = currentUnixTime
+				err = s.saveLastNotificationTime(c, preference, "last_weekly_digest_unix_time")
+			}
+
+			if err != nil {
+				log.Errorf(c, "[notifications.SendNotifications] failed to save weekly digest time of user \"uid:%d\", because %s", user.Uid, err.Error())
+			}
+		}
+
+		if preference.DueRemindersEnabled && !isSameNotificationDay(preference.LastDueReminderUnixTime, now) {
+			sent, err := s.sendDueReminder(c, user, preference.ReminderDaysBefore, now)
+
+			if err != nil {
+				failedCount++
+				log.Errorf(c, "[notifications.SendNotifications] failed to send due reminder to user \"uid:%d\", because %s", user.Uid, err.Error())
+			} else {
+				if sent {
+					sentCount++
+				}
+
+				preference.LastDueReminderUnixTime = currentUnixTime
+				err = s.saveLastNotificationTime(c, preference, "last_due_reminder_unix_time")
+			}
+
+			if err != nil {
+				log.Errorf(c, "[notifications.SendNotifications] failed to save due reminder time of user \"uid:%d\", because %s", user.Uid, err.Error())
+			}
+		}
+	}
+
+	log.Infof(c, "[notifications.SendNotifications] %d notification emails have been sent, %d notification emails failed", sentCount, failedCount)
+
+	return nil
+}
+
+// sendWeeklyDigest sends the digest of the last week to user, which contains the cash position of every account,
+// the top expense categories of the last week and the budget deviations of the month of the last day of the week
+func (s *NotificationService) sendWeeklyDigest(c core.Context, user *models.User, now time.Time) error {
+	textItems := locales.GetLocaleTextItems(user.Language)
+	notificationTextItems := textItems.NotificationMailTextItems
+
+	endTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startTime := endTime.AddDate(0, 0, -weeklyDigestDays)
+	lastDay := endTime.AddDate(0, 0, -1)
+
+	accountLines, accountTotalLines, err := s.getCashPositionLines(c, user.Uid, notificationTextItems.Total)
+
+	if err != nil {
+		return err
+	}
+
+	expenseCategoryLines, err := s.getTopExpenseCategoryLines(c, user.Uid, startTime.UnixMilli(), endTime.UnixMilli())
+
+	if err != nil {
+		return err
+	}
+
+	budgetLines, err := s.getBudgetDeviationLines(c, user, lastDay)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": textItems.GlobalTextItems.AppName,
+		"WeeklyDigestMail": map[string]any{
+			"Title":                notificationTextItems.WeeklyDigestTitle,
+			"Salutation":           fmt.Sprintf(notificationTextItems.SalutationFormat, user.Nickname),
+			"Description":          fmt.Sprintf(notificationTextItems.WeeklyDigestDescriptionFormat, startTime.Format(notificationMailDateFormat), lastDay.Format(notificationMailDateFormat)),
+			"CashPosition":         notificationTextItems.CashPosition,
+			"Accounts":             accountLines,
+			"AccountTotals":        accountTotalLines,
+			"TopExpenseCategories": notificationTextItems.TopExpenseCategories,
+			"ExpenseCategories":    expenseCategoryLines,
+			"BudgetDeviations":     notificationTextItems.BudgetDeviations,
+			"Planned":              notificationTextItems.Planned,
+			"Actual":               notificationTextItems.Actual,
+			"Deviation":            notificationTextItems.Deviation,
+			"BudgetLines":          budgetLines,
+			"NoData":               notificationTextItems.NoData,
+			"DescriptionBottom":    fmt.Sprintf(notificationTextItems.DescriptionBottomFormat, textItems.GlobalTextItems.AppName),
+		},
+	}
+
+	return s.sendNotificationMail(user, templates.TEMPLATE_WEEKLY_DIGEST, notificationTextItems.WeeklyDigestTitle, templateParams)
+}
+
+// sendDueReminder sends the reminder of the obligations, tax records and planned transactions which fall due in the specified days
+// to user, and returns whether the reminder is sent, nothing is sent if no payment falls due on that day
+func (s *NotificationService) sendDueReminder(c core.Context, user *models.User, daysBefore int32, now time.Time) (bool, error) {
+	textItems := locales.GetLocaleTextItems(user.Language)
+	notificationTextItems := textItems.NotificationMailTextItems
+
+	dueDate := time.Date(now.Year(), now.Month(), now.Day()+int(daysBefore), 0, 0, 0, 0, now.Location())
+	calendar, err := s.reports.GetPaymentCalendar(c, user.Uid, dueDate.UnixMilli(), dueDate.AddDate(0, 0, 1).UnixMilli(), nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(calendar.Items) < 1 {
+		return false, nil
+	}
+
+	dueItems := make([]*notificationMailDueItem, len(calendar.Items))
+
+	for i, item := range calendar.Items {
+		dueItems[i] = &notificationMailDueItem{
+			Type:        getNotificationPaymentTypeName(item.Type, notificationTextItems),
+			Description: item.Description,
+			Amount:      formatNotificationAmount(item.Amount, item.Currency),
+		}
+	}
+
+	templateParams := map[string]any{
+		"AppName": textItems.GlobalTextItems.AppName,
+		"DueReminderMail": map[string]any{
+			"Title":             notificationTextItems.DueReminderTitle,
+			"Salutation":        fmt.Sprintf(notificationTextItems.SalutationFormat, user.Nickname),
+			"Description":       fmt.Sprintf(notificationTextItems.DueReminderDescriptionFormat, dueDate.Format(notificationMailDateFormat)),
+			"Items":             dueItems,
+			"DescriptionBottom": fmt.Sprintf(notificationTextItems.DescriptionBottomFormat, textItems.GlobalTextItems.AppName),
+		},
+	}
+
+	err = s.sendNotificationMail(user, templates.TEMPLATE_DUE_REMINDER, notificationTextItems.DueReminderTitle, templateParams)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sendNotificationMail renders the notification mail template and sends the mail to user
+func (s *NotificationService) sendNotificationMail(user *models.User, templateName templates.KnownTemplate, subject string, templateParams map[string]any) error {
+	tmpl, err := templates.GetTemplate(templateName)
+
+	if err != nil {
+		return err
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: subject,
+		Body:    bodyBuffer.String(),
+	}
+
+	return s.mailer.SendMail(message)
+}
+
+// getCashPositionLines returns the balance of every visible account of user and the total balances of every currency
+func (s *NotificationService) getCashPositionLines(c core.Context, uid int64, totalName string) ([]*notificationMailLine, []*notificationMailLine, error) {
+	var accounts []*models.Account
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND hidden=? AND type=?", uid, false, false, models.ACCOUNT_TYPE_SINGLE_ACCOUNT).OrderBy("display_order asc, account_id asc").Find(&accounts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountLines := make([]*notificationMailLine, len(accounts))
+	totalBalances := make(map[string]int64)
+
+	for i, account := range accounts {
+		accountLines[i] = &notificationMailLine{
+			Name:   account.Name,
+			Amount: formatNotificationAmount(account.Balance, account.Currency),
+		}
+		totalBalances[account.Currency] += account.Balance
+	}
+
+	currencies := make([]string, 0, len(totalBalances))
+
+	for currency := range totalBalances {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+	totalLines := make([]*notificationMailLine, len(currencies))
+
+	for i, currency := range currencies {
+		totalLines[i] = &notificationMailLine{
+			Name:   totalName,
+			Amount: formatNotificationAmount(totalBalances[currency], currency),
+		}
+	}
+
+	return accountLines, totalLines, nil
+}
+
+// getTopExpenseCategoryLines returns the expense categories of user with the largest expense amounts in the period,
+// the expense amounts of every category are grouped by account currency
+func (s *NotificationService) getTopExpenseCategoryLines(c core.Context, uid int64, startTimeMs int64, endTimeMs int64) ([]*notificationMailLine, error) {
+	type expenseRow struct {
+		CategoryName string `xorm:"category_name"`
+		Currency     string `xorm:"currency"`
+		TotalAmount  int64  `xorm:"total_amount"`
+	}
+
+	var rows []*expenseRow
+	err := s.UserDataDB(uid).NewSession(c).SQL(`SELECT COALESCE(tc.name, '') as category_name, COALESCE(a.currency, '') as currency, SUM(t.amount) as total_amount
+		FROM "transaction" t
+		LEFT JOIN transaction_category tc ON t.category_id = tc.category_id AND tc.uid = t.uid
+		LEFT JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
+		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0 AND t.type = ?
+		AND t.transaction_time >= ? AND t.transaction_time < ?
+		GROUP BY t.category_id, COALESCE(tc.name, ''), COALESCE(a.currency, '')`,
+		uid, models.TRANSACTION_DB_TYPE_EXPENSE, startTimeMs, endTimeMs).Find(&rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].TotalAmount > rows[j].TotalAmount
+	})
+
+	if len(rows) > weeklyDigestTopExpenseCategoryCount {
+		rows = rows[:weeklyDigestTopExpenseCategoryCount]
+	}
+
+	lines := make([]*notificationMailLine, len(rows))
+
+	for i, row := range rows {
+		lines[i] = &notificationMailLine{
+			Name:   row.CategoryName,
+			Amount: formatNotificationAmount(row.TotalAmount, row.Currency),
+		}
+	}
+
+	return lines, nil
+}
+
+// getBudgetDeviationLines returns the primary categories of user with the largest deviations between the budgets and the fact amounts
+// of the month of the specified day, the categories without budget or deviation are omitted
+func (s *NotificationService) getBudgetDeviationLines(c core.Context, user *models.User, day time.Time) ([]*notificationMailBudgetLine, error) {
+	planFact, err := s.budgets.GetPlanFactByMonths(c, user.Uid, &models.PlanFactRangeRequest{
+		PeriodType: models.PLAN_FACT_PERIOD_MONTH,
+		Year:       int32(day.Year()),
+		Month:      int32(day.Month()),
+	}, user.FiscalYearStart, day.Location())
+
+	if err != nil {
+		return nil, err
+	}
+
+	deviationLines := make([]*models.PlanFactTableLineResponse, 0, len(planFact.Lines))
+
+	for _, line := range planFact.Lines {
+		if line.ParentCategoryId > 0 || line.Total.PlannedAmount == 0 || line.Total.Deviation == 0 {
+			continue
+		}
+
+		deviationLines = append(deviationLines, line)
+	}
+
+	sort.SliceStable(deviationLines, func(i, j int) bool {
+		return getAbsoluteAmount(deviationLines[i].Total.Deviation) > getAbsoluteAmount(deviationLines[j].Total.Deviation)
+	})
+
+	if len(deviationLines) > weeklyDigestBudgetDeviationCount {
+		deviationLines = deviationLines[:weeklyDigestBudgetDeviationCount]
+	}
+
+	lines := make([]*notificationMailBudgetLine, len(deviationLines))
+
+	for i, line := range deviationLines {
+		lines[i] = &notificationMailBudgetLine{
+			Name:      line.CategoryName,
+			Planned:   formatNotificationAmount(line.Total.PlannedAmount, user.DefaultCurrency),
+			Actual:    formatNotificationAmount(line.Total.FactAmount, user.DefaultCurrency),
+			Deviation: formatNotificationAmount(line.Total.Deviation, user.DefaultCurrency),
+		}
+	}
+
+	return lines, nil
+}
+
+// saveLastNotificationTime saves the time when the specified notification was sent to user
+func (s *NotificationService) saveLastNotificationTime(c core.Context, preference *models.NotificationPreference, column string) error {
+	_, err := s.UserDataDB(preference.Uid).NewSession(c).Cols(column).Where("uid=?", preference.Uid).Update(preference)
+	return err
+}
+
+// isSameNotificationDay returns whether the specified unix time is on the same day as now
+func isSameNotificationDay(unixTime int64, now time.Time) bool {
+	if unixTime <= 0 {
+		return false
+	}
+
+	lastTime := time.Unix(unixTime, 0).In(now.Location())
+
+	return lastTime.Year() == now.Year() && lastTime.YearDay() == now.YearDay()
+}
+
+// getNotificationPaymentTypeName returns the localized name of the payment type of payment calendar
+func getNotificationPaymentTypeName(paymentType string, textItems *locales.NotificationMailTextItems) string {
+	switch paymentType {
+	case models.PaymentTypeReceivable:
+		return textItems.Receivable
+	case models.PaymentTypePayable:
+		return textItems.Payable
+	case models.PaymentTypeTax:
+		return textItems.Tax
+	case models.PaymentTypeEstimatedTax:
+		return textItems.EstimatedTax
+	case models.PaymentTypePlanned:
+		return textItems.PlannedTransaction
+	default:
+		return paymentType
+	}
+}
+
+// getAbsoluteAmount returns the absolute value of amount
+func getAbsoluteAmount(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+
+	return amount
+}
+
+// formatNotificationAmount returns a textual representation of amount with currency
+func formatNotificationAmount(amount int64, currency string) string {
+	if currency == "" {
+		return utils.FormatAmount(amount)
+	}
+
+	return utils.FormatAmount(amount) + " " + currency
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// fakeMailer collects the mails sent by services instead of sending them
+type fakeMailer struct {
+	messages []*mail.MailMessage
+}
+
+func (m *fakeMailer) SendMail(message *mail.MailMessage) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func newTestNotificationService(t *testing.T) (*NotificationService, *fakeMailer, *testDB) {
+	t.Helper()
+
+	// mail templates are loaded relative to the repository root
+	t.Chdir("../..")
+
+	reportSvc, tdb := newTestReportServiceWithDB(t)
+	mailer := &fakeMailer{}
+
+	svc := &NotificationService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+		budgets: &BudgetService{
+			ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+			ServiceUsingUuid: ServiceUsingUuid{container: initUuidContainer(t)},
+		},
+		reports: reportSvc,
+		mailer:  mailer,
+	}
+
+	_, err := tdb.engine.Insert(&models.User{Uid: 1, Username: "test", Email: "test@example.com", Nickname: "Tester", Language: "en", DefaultCurrency: "RUB"})
+	assert.Nil(t, err)
+
+	return svc, mailer, tdb
+}
+
+func TestNotificationServiceSaveAndGetPreference(t *testing.T) {
+	svc, _, tdb := newTestNotificationService(t)
+	defer tdb.close()
+
+	preference, err := svc.GetNotificationPreference(nil, 1)
+	assert.Nil(t, err)
+	assert.False(t, preference.WeeklyDigestEnabled)
+	assert.False(t, preference.DueRemindersEnabled)
+	assert.Equal(t, models.DefaultWeeklyDigestDayOfWeek, preference.WeeklyDigestDayOfWeek)
+	assert.Equal(t, int32(models.DefaultReminderDaysBefore), preference.ReminderDaysBefore)
+
+	err = svc.SaveNotificationPreference(nil, &models.NotificationPreference{Uid: 1, WeeklyDigestEnabled: true, WeeklyDigestDayOfWeek: 5, ReminderDaysBefore: 3})
+	assert.Nil(t, err)
+
+	err = svc.SaveNotificationPreference(nil, &models.NotificationPreference{Uid: 1, WeeklyDigestEnabled: true, WeeklyDigestDayOfWeek: 5, DueRemindersEnabled: true, ReminderDaysBefore: 7, TimezoneUtcOffset: 180})
+	assert.Nil(t, err)
+
+	preference, err = svc.GetNotificationPreference(nil, 1)
+	assert.Nil(t, err)
+	assert.True(t, preference.WeeklyDigestEnabled)
+	assert.True(t, preference.DueRemindersEnabled)
+	assert.Equal(t, int(5), int(preference.WeeklyDigestDayOfWeek))
+	assert.Equal(t, int32(7), preference.ReminderDaysBefore)
+	assert.Equal(t, int16(180), preference.TimezoneUtcOffset)
+}
+
+func TestNotificationServiceSendWeeklyDigest(t *testing.T) {
+	svc, mailer, tdb := newTestNotificationService(t)
+	defer tdb.close()
+	insertTestPlanFactData(t, tdb)
+
+	accounts := []*models.Account{
+		{AccountId: 1, Uid: 1, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Bank", Currency: "RUB", Balance: 150000, DisplayOrder: 1},
+		{AccountId: 2, Uid: 1, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Hidden wallet", Currency: "RUB", Balance: 500, Hidden: true, DisplayOrder: 2},
+	}
+
+	for _, account := range accounts {
+		_, err := tdb.engine.Insert(account)
+		assert.Nil(t, err)
+	}
+
+	err := svc.SaveNotificationPreference(nil, &models.NotificationPreference{Uid: 1, WeeklyDigestEnabled: true, WeeklyDigestDayOfWeek: models.DefaultWeeklyDigestDayOfWeek, ReminderDaysBefore: 3})
+	assert.Nil(t, err)
+
+	// 2025-02-16 is Sunday, and the digest is not sent on the day which is not chosen by user
+	err = svc.SendNotifications(nil, time.Date(2025, 2, 16, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(mailer.messages))
+
+	// 2025-02-17 is Monday, and the digest covers the week from 2025-02-10 to 2025-02-16
+	err = svc.SendNotifications(nil, time.Date(2025, 2, 17, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+	assert.Equal(t, "test@example.com", mailer.messages[0].To)
+
+	body := mailer.messages[0].Body
+	assert.Contains(t, body, "2025-02-10")
+	assert.Contains(t, body, "2025-02-16")
+	assert.Contains(t, body, "Bank")
+	assert.Contains(t, body, "1500.00 RUB")
+	assert.NotContains(t, body, "Hidden wallet")
+	assert.Contains(t, body, "Travel")
+	assert.Contains(t, body, "30.00")
+	assert.Contains(t, body, "Office")
+	assert.Contains(t, body, "Sales")
+	assert.Contains(t, body, "-100.00 RUB")
+
+	// the digest is sent only once a day
+	err = svc.SendNotifications(nil, time.Date(2025, 2, 17, 9, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+
+	preference, err := svc.GetNotificationPreference(nil, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 2, 17, 8, 0, 0, 0, time.UTC).Unix(), preference.LastWeeklyDigestUnixTime)
+}
+
+func TestNotificationServiceSendDueReminder(t *testing.T) {
+	svc, mailer, tdb := newTestNotificationService(t)
+	defer tdb.close()
+
+	dueDate := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC).UnixMilli()
+	obligations := []*models.Obligation{
+		{ObligationId: 1, Uid: 1, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 20000, PaidAmount: 5000, Currency: "RUB", DueDate: dueDate, Comment: "Office rent", Status: models.OBLIGATION_STATUS_PARTIAL},
+		{ObligationId: 2, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, Amount: 7000, Currency: "RUB", DueDate: dueDate + 24*60*60*1000, Comment: "Invoice", Status: models.OBLIGATION_STATUS_ACTIVE},
+	}
+
+	for _, obligation := range obligations {
+		_, err := tdb.engine.Insert(obligation)
+		assert.Nil(t, err)
+	}
+
+	err := svc.SaveNotificationPreference(nil, &models.NotificationPreference{Uid: 1, DueRemindersEnabled: true, ReminderDaysBefore: 3})
+	assert.Nil(t, err)
+
+	// nothing falls due on 2025-03-12
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 9, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(mailer.messages))
+
+	// the payable obligation falls due on 2025-03-13
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+
+	body := mailer.messages[0].Body
+	assert.Contains(t, body, "2025-03-13")
+	assert.Contains(t, body, "Office rent")
+	assert.Contains(t, body, "150.00 RUB")
+	assert.NotContains(t, body, "Invoice")
+
+	// the reminder is sent only once a day
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+
+	// the disabled user receives no reminder
+	_, err = tdb.engine.ID(int64(1)).Cols("disabled").Update(&models.User{Disabled: true})
+	assert.Nil(t, err)
+
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+}
+
+func TestNotificationServiceSendDueReminder_InUserTimezone(t *testing.T) {
+	svc, mailer, tdb := newTestNotificationService(t)
+	defer tdb.close()
+
+	userTimezone := time.FixedZone("User Timezone", 10*60*60)
+	obligation := &models.Obligation{ObligationId: 1, Uid: 1, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 20000, Currency: "RUB", DueDate: time.Date(2025, 3, 13, 0, 0, 0, 0, userTimezone).UnixMilli(), Comment: "Office rent", Status: models.OBLIGATION_STATUS_ACTIVE}
+	_, err := tdb.engine.Insert(obligation)
+	assert.Nil(t, err)
+
+	err = svc.SaveNotificationPreference(nil, &models.NotificationPreference{Uid: 1, DueRemindersEnabled: true, ReminderDaysBefore: 3, TimezoneUtcOffset: 600})
+	assert.Nil(t, err)
+
+	// it is still 2025-03-09 in UTC, but already 2025-03-10 in the timezone of user
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+	assert.Contains(t, mailer.messages[0].Body, "2025-03-13")
+	assert.Contains(t, mailer.messages[0].Body, "Office rent")
+
+	// it is 2025-03-10 in UTC too, but the reminder of that day in the timezone of user has been sent
+	err = svc.SendNotifications(nil, time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC).Unix())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mailer.messages))
+}
@@ -0,0 +1,404 @@
+// obligation_payments.go provides the settlement ledger of obligations, which links obligations to the transactions paying them.
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// GetAllPaymentsByObligationId returns all payment models which settle the specified obligation
+func (s *ObligationService) GetAllPaymentsByObligationId(c core.Context, uid int64, obligationId int64) ([]*models.ObligationPayment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if obligationId <= 0 {
+		return nil, errs.ErrObligationIdInvalid
+	}
+
+	var payments []*models.ObligationPayment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND obligation_id=?", uid, false, obligationId).OrderBy("payment_date desc").Find(&payments)
+
+	return payments, err
+}
+
+// GetAllPaymentsByObligationIds returns all payment models of the specified obligations grouped by obligation id
+func (s *ObligationService) GetAllPaymentsByObligationIds(c core.Context, uid int64, obligationIds []int64) (map[int64][]*models.ObligationPayment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(obligationIds) == 0 {
+		return make(map[int64][]*models.ObligationPayment), nil
+	}
+
+	var payments []*models.ObligationPayment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("obligation_id", obligationIds).Find(&payments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64][]*models.ObligationPayment)
+
+	for _, p := range payments {
+		result[p.ObligationId] = append(result[p.ObligationId], p)
+	}
+
+	return result, nil
+}
+
+// AllocateTransaction settles obligations of the counterparty of the specified transaction by the transaction amount.
+// Income transactions settle receivables and expense transactions settle payables. If no allocation is specified,
+// the unallocated amount of transaction is allocated to the open obligations in order of due date.
+// The paid amount and status of every affected obligation are derived from all of its payments.
+func (s *ObligationService) AllocateTransaction(c core.Context, uid int64, transactionId int64, allocations []*models.ObligationAllocation, comment string) ([]*models.ObligationPayment, []*models.Obligation, error) {
+	if uid <= 0 {
+		return nil, nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, nil, errs.ErrTransactionIdInvalid
+	}
+
+	var payments []*models.ObligationPayment
+	var obligations []*models.Obligation
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		transaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		var obligationType models.ObligationType
+
+		if transaction.Planned {
+			return errs.ErrObligationTransactionInvalid
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			obligationType = models.OBLIGATION_TYPE_RECEIVABLE
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			obligationType = models.OBLIGATION_TYPE_PAYABLE
+		} else {
+			return errs.ErrObligationTransactionInvalid
+		}
+
+		account := &models.Account{}
+		has, err = sess.ID(transaction.AccountId).Where("uid=?", uid).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		allocatedAmount, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).SumInt(&models.ObligationPayment{}, "amount")
+
+		if err != nil {
+			return err
+		}
+
+		unallocatedAmount := transaction.Amount - allocatedAmount
+
+		if unallocatedAmount <= 0 {
+			return errs.ErrObligationAllocationExceedsTransactionAmount
+		}
+
+		if len(allocations) < 1 {
+			allocations, err = s.getAutoAllocations(sess, uid, transaction, obligationType, account.Currency, unallocatedAmount)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		obligationIds := make([]int64, 0, len(allocations))
+		amounts := make(map[int64]int64, len(allocations))
+		totalAmount := int64(0)
+
+		for i := 0; i < len(allocations); i++ {
+			if allocations[i].Amount <= 0 {
+				return errs.ErrObligationAllocationAmountInvalid
+			}
+
+			if _, exists := amounts[allocations[i].ObligationId]; !exists {
+				obligationIds = append(obligationIds, allocations[i].ObligationId)
+			}
+
+			amounts[allocations[i].ObligationId] += allocations[i].Amount
+			totalAmount += allocations[i].Amount
+		}
+
+		if totalAmount > unallocatedAmount {
+			return errs.ErrObligationAllocationExceedsTransactionAmount
+		}
+
+		counterpartyId := transaction.CounterpartyId
+		now := time.Now().Unix()
+
+		for i := 0; i < len(obligationIds); i++ {
+			obligation := &models.Obligation{}
+			has, err := sess.ID(obligationIds[i]).Where("uid=? AND deleted=?", uid, false).Get(obligation)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrObligationNotFound
+			}
+
+			if obligation.ObligationType != obligationType {
+				return errs.ErrObligationTypeMismatch
+			}
+
+			if counterpartyId <= 0 {
+				counterpartyId = obligation.CounterpartyId
+			}
+
+			if obligation.CounterpartyId != counterpartyId {
+				return errs.ErrObligationCounterpartyMismatch
+			}
+
+			if obligation.Currency != account.Currency {
+				return errs.ErrObligationCurrencyMismatch
+			}
+
+			if amounts[obligation.ObligationId] > obligation.Amount-obligation.PaidAmount {
+				return errs.ErrObligationAllocationExceedsRemainingAmount
+			}
+
+			payment := &models.ObligationPayment{
+				PaymentId:       s.GenerateUuid(uuid.UUID_TYPE_DEFAULT),
+				Uid:             uid,
+				Deleted:         false,
+				ObligationId:    obligation.ObligationId,
+				TransactionId:   transaction.TransactionId,
+				PaymentDate:     transaction.TransactionTime,
+				Amount:          amounts[obligation.ObligationId],
+				Comment:         comment,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
+			}
+
+			if payment.PaymentId < 1 {
+				return errs.ErrSystemIsBusy
+			}
+
+			if _, err := sess.Insert(payment); err != nil {
+				return err
+			}
+
+			payments = append(payments, payment)
+
+			obligation, err = recalculateObligationPaidAmount(sess, uid, obligation.ObligationId, now)
+
+			if err != nil {
+				return err
+			}
+
+			obligations = append(obligations, obligation)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payments, obligations, nil
+}
+
+// DeletePayment deletes an existed obligation payment from database and recalculates the paid amount of its obligation
+func (s *ObligationService) DeletePayment(c core.Context, uid int64, paymentId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if paymentId <= 0 {
+		return errs.ErrObligationPaymentIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.ObligationPayment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		payment := &models.ObligationPayment{}
+		has, err := sess.ID(paymentId).Where("uid=? AND deleted=?", uid, false).Get(payment)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrObligationPaymentNotFound
+		}
+
+		deletedRows, err := sess.ID(paymentId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrObligationPaymentNotFound
+		}
+
+		_, err = recalculateObligationPaidAmount(sess, uid, payment.ObligationId, now)
+
+		if err == errs.ErrObligationNotFound {
+			return nil
+		}
+
+		return err
+	})
+}
+
+// getAutoAllocations returns the allocations of the specified amount to the open obligations of the transaction counterparty, earliest due date first
+func (s *ObligationService) getAutoAllocations(sess *xorm.Session, uid int64, transaction *models.Transaction, obligationType models.ObligationType, currency string, amount int64) ([]*models.ObligationAllocation, error) {
+	if transaction.CounterpartyId <= 0 {
+		return nil, errs.ErrNoOpenObligationsToAllocate
+	}
+
+	var openObligations []*models.Obligation
+	err := sess.Where("uid=? AND deleted=? AND obligation_type=? AND counterparty_id=? AND currency=? AND status<>?", uid, false, obligationType, transaction.CounterpartyId, currency, models.OBLIGATION_STATUS_PAID).Find(&openObligations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(openObligations, func(i, j int) bool {
+		if openObligations[i].DueDate != openObligations[j].DueDate {
+			return openObligations[i].DueDate < openObligations[j].DueDate
+		}
+
+		return openObligations[i].CreatedUnixTime < openObligations[j].CreatedUnixTime
+	})
+
+	allocations := make([]*models.ObligationAllocation, 0, len(openObligations))
+
+	for i := 0; i < len(openObligations) && amount > 0; i++ {
+		remainingAmount := openObligations[i].Amount - openObligations[i].PaidAmount
+
+		if remainingAmount <= 0 {
+			continue
+		}
+
+		if remainingAmount > amount {
+			remainingAmount = amount
+		}
+
+		allocations = append(allocations, &models.ObligationAllocation{
+			ObligationId: openObligations[i].ObligationId,
+			Amount:       remainingAmount,
+		})
+
+		amount -= remainingAmount
+	}
+
+	if len(allocations) < 1 {
+		return nil, errs.ErrNoOpenObligationsToAllocate
+	}
+
+	return allocations, nil
+}
+
+// recalculateObligationPaidAmount derives the paid amount and status of the specified obligation from all of its payments, the overdue obligation
+// stays overdue until it is fully paid or its due date is not passed, saves the obligation and its status change, and returns the obligation
+func recalculateObligationPaidAmount(sess *xorm.Session, uid int64, obligationId int64, now int64) (*models.Obligation, error) {
+	obligation := &models.Obligation{}
+	has, err := sess.ID(obligationId).Where("uid=? AND deleted=?", uid, false).Get(obligation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrObligationNotFound
+	}
+
+	paidAmount, err := sess.Where("uid=? AND deleted=? AND obligation_id=?", uid, false, obligationId).SumInt(&models.ObligationPayment{}, "amount")
+
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := obligation.Status
+	oldPaidAmount := obligation.PaidAmount
+	obligation.PaidAmount = paidAmount
+	obligation.Status = models.GetObligationStatus(obligation.Amount, paidAmount)
+
+	if oldStatus == models.OBLIGATION_STATUS_OVERDUE && getObligationStatusAsOf(obligation.Amount, paidAmount, obligation.DueDate, now*1000) == models.OBLIGATION_STATUS_OVERDUE {
+		obligation.Status = models.OBLIGATION_STATUS_OVERDUE
+	}
+
+	obligation.UpdatedUnixTime = now
+
+	_, err = sess.ID(obligationId).Cols("paid_amount", "status", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(obligation)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveStatusChangeInSession(sess, uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, obligationId, byte(oldStatus), byte(obligation.Status), obligation.DueDate, oldPaidAmount, obligation.PaidAmount, true, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return obligation, nil
+}
+
+// deleteObligationPaymentsOfTransaction deletes all obligation payments of the specified transaction and recalculates the paid amount of their obligations
+func deleteObligationPaymentsOfTransaction(sess *xorm.Session, uid int64, transactionId int64, now int64) error {
+	var payments []*models.ObligationPayment
+	err := sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).Find(&payments)
+
+	if err != nil {
+		return err
+	}
+
+	if len(payments) < 1 {
+		return nil
+	}
+
+	updateModel := &models.ObligationPayment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).Update(updateModel)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(payments); i++ {
+		_, err = recalculateObligationPaidAmount(sess, uid, payments[i].ObligationId, now)
+
+		if err != nil && err != errs.ErrObligationNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateObligationPaymentDatesOfTransaction updates the payment dates of all obligation payments of the specified transaction to the transaction time
+func updateObligationPaymentDatesOfTransaction(sess *xorm.Session, uid int64, transactionId int64, transactionTime int64, now int64) error {
+	updateModel := &models.ObligationPayment{
+		PaymentDate:     transactionTime,
+		UpdatedUnixTime: now,
+	}
+
+	_, err := sess.Cols("payment_date", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).Update(updateModel)
+
+	return err
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func insertTestObligationSettlementData(t *testing.T, tdb *testDB) {
+	t.Helper()
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 1, Uid: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Checking", Currency: "RUB"})
+	assert.Nil(t, err)
+
+	transactions := []*models.Transaction{
+		{TransactionId: 101, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CounterpartyId: 7, TransactionTime: 1700000000000, Amount: 50000},
+		{TransactionId: 102, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CounterpartyId: 7, TransactionTime: 1700000001000, Amount: 10000},
+		{TransactionId: 103, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, CounterpartyId: 7, TransactionTime: 1700000002000, Amount: 10000, Planned: true},
+	}
+
+	for _, transaction := range transactions {
+		_, err = tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	obligations := []*models.Obligation{
+		{ObligationId: 201, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 7, Amount: 30000, Currency: "RUB", DueDate: 1700100000000, Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 202, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 7, Amount: 40000, Currency: "RUB", DueDate: 1700000000000, Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 203, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 8, Amount: 10000, Currency: "RUB", Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 204, Uid: 1, ObligationType: models.OBLIGATION_TYPE_PAYABLE, CounterpartyId: 7, Amount: 10000, Currency: "RUB", Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 205, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 7, Amount: 10000, Currency: "USD", Status: models.OBLIGATION_STATUS_ACTIVE},
+	}
+
+	for _, obligation := range obligations {
+		_, err = tdb.engine.Insert(obligation)
+		assert.Nil(t, err)
+	}
+}
+
+func TestObligationServiceAllocateTransaction_SpecifiedAllocations(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	payments, obligations, err := svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{
+		{ObligationId: 201, Amount: 30000},
+		{ObligationId: 202, Amount: 15000},
+	}, "March invoices")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(payments))
+	assert.Equal(t, 2, len(obligations))
+
+	assert.Equal(t, int64(1700000000000), payments[0].PaymentDate)
+	assert.Equal(t, "March invoices", payments[0].Comment)
+
+	got, err := svc.GetObligationByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30000), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, got.Status)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(15000), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PARTIAL, got.Status)
+
+	list, err := svc.GetAllPaymentsByObligationId(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, int64(101), list[0].TransactionId)
+
+	// Only 5000 of the transaction is left unallocated
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 202, Amount: 6000}}, "")
+	assert.Equal(t, errs.ErrObligationAllocationExceedsTransactionAmount, err)
+}
+
+func TestObligationServiceAllocateTransaction_AutoAllocation(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	payments, obligations, err := svc.AllocateTransaction(nil, 1, 101, nil, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(payments))
+
+	// Obligation 202 is due first and is paid in full, the rest goes to obligation 201
+	assert.Equal(t, int64(202), obligations[0].ObligationId)
+	assert.Equal(t, int64(40000), obligations[0].PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, obligations[0].Status)
+	assert.Equal(t, int64(201), obligations[1].ObligationId)
+	assert.Equal(t, int64(10000), obligations[1].PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PARTIAL, obligations[1].Status)
+
+	// The transaction is fully allocated
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, nil, "")
+	assert.Equal(t, errs.ErrObligationAllocationExceedsTransactionAmount, err)
+}
+
+func TestObligationServiceAllocateTransaction_InvalidAllocations(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	_, _, err := svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 201, Amount: 30001}}, "")
+	assert.Equal(t, errs.ErrObligationAllocationExceedsRemainingAmount, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 203, Amount: 1000}}, "")
+	assert.Equal(t, errs.ErrObligationCounterpartyMismatch, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 204, Amount: 1000}}, "")
+	assert.Equal(t, errs.ErrObligationTypeMismatch, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 205, Amount: 1000}}, "")
+	assert.Equal(t, errs.ErrObligationCurrencyMismatch, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 103, nil, "")
+	assert.Equal(t, errs.ErrObligationTransactionInvalid, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 999, nil, "")
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	// Nothing is saved when any allocation is invalid
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 201, Amount: 1000}, {ObligationId: 203, Amount: 1000}}, "")
+	assert.Equal(t, errs.ErrObligationCounterpartyMismatch, err)
+
+	list, err := svc.GetAllPaymentsByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(list))
+}
+
+func TestObligationServiceDeletePayment(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	payments, _, err := svc.AllocateTransaction(nil, 1, 102, []*models.ObligationAllocation{{ObligationId: 204, Amount: 10000}}, "")
+	assert.Nil(t, err)
+
+	got, err := svc.GetObligationByObligationId(nil, 1, 204)
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, got.Status)
+
+	err = svc.DeletePayment(nil, 1, payments[0].PaymentId)
+	assert.Nil(t, err)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 204)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, got.Status)
+
+	err = svc.DeletePayment(nil, 1, payments[0].PaymentId)
+	assert.Equal(t, errs.ErrObligationPaymentNotFound, err)
+}
+
+func TestObligationServiceModifyObligation_DerivesPaidAmountFromPayments(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	_, _, err := svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 201, Amount: 30000}}, "")
+	assert.Nil(t, err)
+
+	got, err := svc.GetObligationByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+
+	// Raising the amount makes the obligation partially paid, and the manual paid amount is ignored
+	got.Amount = 60000
+	got.PaidAmount = 0
+	got.Status = models.OBLIGATION_STATUS_ACTIVE
+	err = svc.ModifyObligation(nil, got)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30000), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PARTIAL, got.Status)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30000), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_PARTIAL, got.Status)
+
+	err = svc.DeleteObligation(nil, 1, 201)
+	assert.Nil(t, err)
+
+	list, err := svc.GetAllPaymentsByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(list))
+}
+
+func TestObligationServiceUnconfirmTransaction_DeletesPayments(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	transactionSvc := &TransactionService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+	}
+
+	_, _, err := svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 201, Amount: 30000}}, "")
+	assert.Nil(t, err)
+
+	err = transactionSvc.UnconfirmTransaction(nil, 1, 101)
+	assert.Nil(t, err)
+
+	got, err := svc.GetObligationByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, got.Status)
+
+	list, err := svc.GetAllPaymentsByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(list))
+}
+
+func TestObligationServiceModifyTransaction_UpdatesPayments(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	transactionSvc := &TransactionService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: svc.ServiceUsingUuid,
+	}
+
+	_, _, err := svc.AllocateTransaction(nil, 1, 102, []*models.ObligationAllocation{{ObligationId: 204, Amount: 10000}}, "")
+	assert.Nil(t, err)
+
+	transaction := &models.Transaction{}
+	_, err = tdb.engine.ID(102).Get(transaction)
+	assert.Nil(t, err)
+
+	// Changing the transaction time moves the payment date
+	transaction.TransactionTime = 1700000005000
+	err = transactionSvc.ModifyTransaction(nil, transaction, 0, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	list, err := svc.GetAllPaymentsByObligationId(nil, 1, 204)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, transaction.TransactionTime, list[0].PaymentDate)
+
+	// Changing the amount deletes the payments and recalculates the obligation
+	transaction.Amount = 8000
+	err = transactionSvc.ModifyTransaction(nil, transaction, 0, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	list, err = svc.GetAllPaymentsByObligationId(nil, 1, 204)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(list))
+
+	got, err := svc.GetObligationByObligationId(nil, 1, 204)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), got.PaidAmount)
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, got.Status)
+}
+
+func TestGetObligationStatus(t *testing.T) {
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, models.GetObligationStatus(10000, 0))
+	assert.Equal(t, models.OBLIGATION_STATUS_PARTIAL, models.GetObligationStatus(10000, 1))
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, models.GetObligationStatus(10000, 10000))
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, models.GetObligationStatus(10000, 12000))
+}
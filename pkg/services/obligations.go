@@ -9,6 +9,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/uuid"
 )
@@ -65,12 +66,23 @@ func (s *ObligationService) GetObligationByObligationId(c core.Context, uid int6
 	return obligation, nil
 }
 
-// CreateObligation saves a new obligation model to database
+// CreateObligation saves a new obligation model to database, the initial paid amount is recorded in its status history
 func (s *ObligationService) CreateObligation(c core.Context, obligation *models.Obligation) error {
 	if obligation.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	return s.UserDataDB(obligation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.CreateObligationInSession(c, sess, obligation)
+	})
+}
+
+// CreateObligationInSession saves a new obligation model and its initial status history to database in the given session
+func (s *ObligationService) CreateObligationInSession(c core.Context, sess *xorm.Session, obligation *models.Obligation) error {
+	if obligation.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	obligation.ObligationId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
 
 	if obligation.ObligationId < 1 {
@@ -81,13 +93,16 @@ func (s *ObligationService) CreateObligation(c core.Context, obligation *models.
 	obligation.CreatedUnixTime = time.Now().Unix()
 	obligation.UpdatedUnixTime = time.Now().Unix()
 
-	return s.UserDataDB(obligation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Insert(obligation)
+	_, err := sess.Insert(obligation)
+
+	if err != nil {
 		return err
-	})
+	}
+
+	return saveStatusChangeInSession(sess, obligation.Uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, obligation.ObligationId, byte(obligation.Status), byte(obligation.Status), obligation.DueDate, 0, obligation.PaidAmount, false, obligation.CreatedUnixTime)
 }
 
-// ModifyObligation saves an existed obligation model to database
+// ModifyObligation saves an existed obligation model and its status or paid amount change to database, the paid amount and status of obligation which has payments are derived from its payments
 func (s *ObligationService) ModifyObligation(c core.Context, obligation *models.Obligation) error {
 	if obligation.Uid <= 0 {
 		return errs.ErrUserIdInvalid
@@ -96,6 +111,15 @@ func (s *ObligationService) ModifyObligation(c core.Context, obligation *models.
 	obligation.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(obligation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldObligation := &models.Obligation{}
+		has, err := sess.ID(obligation.ObligationId).Cols("status", "paid_amount").Where("uid=? AND deleted=?", obligation.Uid, false).Get(oldObligation)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrObligationNotFound
+		}
+
 		updatedRows, err := sess.ID(obligation.ObligationId).Cols("obligation_type", "counterparty_id", "cfo_id", "amount", "currency", "due_date", "status", "paid_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", obligation.Uid, false).Update(obligation)
 
 		if err != nil {
@@ -104,7 +128,30 @@ func (s *ObligationService) ModifyObligation(c core.Context, obligation *models.
 			return errs.ErrObligationNotFound
 		}
 
-		return err
+		err = saveStatusChangeInSession(sess, obligation.Uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, obligation.ObligationId, byte(oldObligation.Status), byte(obligation.Status), obligation.DueDate, oldObligation.PaidAmount, obligation.PaidAmount, false, obligation.UpdatedUnixTime)
+
+		if err != nil {
+			return err
+		}
+
+		paymentCount, err := sess.Where("uid=? AND deleted=? AND obligation_id=?", obligation.Uid, false, obligation.ObligationId).Count(&models.ObligationPayment{})
+
+		if err != nil {
+			return err
+		} else if paymentCount < 1 {
+			return nil
+		}
+
+		recalculatedObligation, err := recalculateObligationPaidAmount(sess, obligation.Uid, obligation.ObligationId, obligation.UpdatedUnixTime)
+
+		if err != nil {
+			return err
+		}
+
+		obligation.PaidAmount = recalculatedObligation.PaidAmount
+		obligation.Status = recalculatedObligation.Status
+
+		return nil
 	})
 }
 
@@ -121,6 +168,11 @@ func (s *ObligationService) DeleteObligation(c core.Context, uid int64, obligati
 		DeletedUnixTime: now,
 	}
 
+	paymentUpdateModel := &models.ObligationPayment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		deletedRows, err := sess.ID(obligationId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
@@ -130,6 +182,83 @@ func (s *ObligationService) DeleteObligation(c core.Context, uid int64, obligati
 			return errs.ErrObligationNotFound
 		}
 
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND obligation_id=?", uid, false, obligationId).Update(paymentUpdateModel)
+
 		return err
 	})
 }
+
+// GetObligationStatusHistories returns the status changes of the obligation in the order they happened
+func (s *ObligationService) GetObligationStatusHistories(c core.Context, uid int64, obligationId int64) ([]*models.StatusHistory, error) {
+	if _, err := s.GetObligationByObligationId(c, uid, obligationId); err != nil {
+		return nil, err
+	}
+
+	return getStatusHistories(s.UserDataDB(uid).NewSession(c), uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, obligationId)
+}
+
+// UpdateOverdueObligations updates the statuses of all unpaid obligations of all users according to their due dates and paid amounts,
+// the obligations which are not fully paid after the due dates become overdue, and the ones which are fully paid become paid.
+// The paid obligations whose paid amounts are less than the amounts are checked again.
+func (s *ObligationService) UpdateOverdueObligations(c core.Context, currentUnixTime int64) error {
+	var allObligations []*models.Obligation
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var obligations []*models.Obligation
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND (status<>? OR paid_amount<amount)", false, models.OBLIGATION_STATUS_PAID).Find(&obligations)
+
+		if err != nil {
+			return err
+		}
+
+		allObligations = append(allObligations, obligations...)
+	}
+
+	updatedCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allObligations); i++ {
+		obligation := allObligations[i]
+		newStatus := getObligationStatusAsOf(obligation.Amount, obligation.PaidAmount, obligation.DueDate, currentUnixTime*1000)
+
+		if newStatus == obligation.Status {
+			continue
+		}
+
+		err := s.updateObligationStatus(c, obligation, newStatus, currentUnixTime)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[obligations.UpdateOverdueObligations] failed to update status of obligation \"id:%d\" for user \"uid:%d\", because %s", obligation.ObligationId, obligation.Uid, err.Error())
+			continue
+		}
+
+		updatedCount++
+	}
+
+	if updatedCount > 0 || failedCount > 0 {
+		log.Infof(c, "[obligations.UpdateOverdueObligations] %d obligations have been updated, %d obligations failed", updatedCount, failedCount)
+	}
+
+	return nil
+}
+
+// updateObligationStatus saves the status of obligation which is changed by system and its status change, the obligation modified after being loaded is not updated,
+// and the updated time of obligation is not changed since the change is recorded in the status history only
+func (s *ObligationService) updateObligationStatus(c core.Context, obligation *models.Obligation, newStatus models.ObligationStatus, now int64) error {
+	updateModel := &models.Obligation{
+		Status: newStatus,
+	}
+
+	return s.UserDataDB(obligation.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(obligation.ObligationId).Cols("status").Where("uid=? AND deleted=? AND status=?", obligation.Uid, false, obligation.Status).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrObligationNotFound
+		}
+
+		return saveStatusChangeInSession(sess, obligation.Uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, obligation.ObligationId, byte(obligation.Status), byte(newStatus), obligation.DueDate, obligation.PaidAmount, obligation.PaidAmount, true, now)
+	})
+}
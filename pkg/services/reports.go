@@ -47,26 +47,43 @@ const (
 
 // buildCashFlowQuery returns the SQL query for cash flow report
 func buildCashFlowQuery() string {
-	return fmt.Sprintf(`SELECT t.category_id, COALESCE(tc.name, 'Uncategorized') as category_name, COALESCE(NULLIF(tc.activity_type, 0), 1) as activity_type, t.type, SUM(t.amount) as total_amount
+	return fmt.Sprintf(`SELECT t.category_id, COALESCE(tc.name, 'Uncategorized') as category_name, COALESCE(NULLIF(tc.activity_type, 0), 1) as activity_type, t.type, COALESCE(a.currency, '') as currency, t.transaction_time, SUM(t.amount) as total_amount
 		FROM "transaction" t
 		LEFT JOIN transaction_category tc ON t.category_id = tc.category_id AND tc.uid = t.uid
+		LEFT JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
 		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0
 		AND t.transaction_time >= ? AND t.transaction_time < ?
-		AND t.type IN (%d, %d)`,
-		models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE)
+		AND t.type IN (%d, %d)
+		AND NOT EXISTS (SELECT 1 FROM asset_transaction ast WHERE ast.uid = t.uid AND ast.transaction_id = t.transaction_id AND ast.transaction_type IN (%d, %d))`,
+		models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE,
+		models.ASSET_TRANSACTION_TYPE_DEPRECIATION, models.ASSET_TRANSACTION_TYPE_DISPOSAL_WRITE_OFF)
 }
 
 // buildPnlQuery returns the SQL query for P&L report
 func buildPnlQuery() string {
-	return fmt.Sprintf(`SELECT COALESCE(NULLIF(tc.cost_type, 0), 2) as cost_type, t.type, SUM(t.amount) as total_amount
+	return fmt.Sprintf(`SELECT COALESCE(NULLIF(tc.cost_type, 0), 2) as cost_type, t.type, COALESCE(a.currency, '') as currency, t.transaction_time, SUM(t.amount) as total_amount
 		FROM "transaction" t
 		LEFT JOIN transaction_category tc ON t.category_id = tc.category_id AND tc.uid = t.uid
+		LEFT JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
 		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0
 		AND t.transaction_time >= ? AND t.transaction_time < ?
-		AND t.type IN (%d, %d)`,
+		AND t.type IN (%d, %d)
+		AND NOT EXISTS (SELECT 1 FROM asset_transaction ast WHERE ast.uid = t.uid AND ast.transaction_id = t.transaction_id)`,
 		models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE)
 }
 
+// buildPostedDepreciationQuery returns the SQL query for the depreciation transactions posted for assets
+func buildPostedDepreciationQuery() string {
+	return fmt.Sprintf(`SELECT COALESCE(a.currency, '') as currency, t.transaction_time, SUM(t.amount) as total_amount
+		FROM "transaction" t
+		INNER JOIN asset_transaction ast ON ast.uid = t.uid AND ast.transaction_id = t.transaction_id
+		LEFT JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
+		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0
+		AND t.transaction_time >= ? AND t.transaction_time < ?
+		AND ast.transaction_type = %d`,
+		models.ASSET_TRANSACTION_TYPE_DEPRECIATION)
+}
+
 // matchesCfo returns true if cfoId filter is not set or entity belongs to the specified CFO
 func matchesCfo(filterCfoId int64, entityCfoId int64) bool {
 	return filterCfoId <= 0 || entityCfoId == filterCfoId
@@ -85,12 +102,14 @@ func validateTimeRange(startTime int64, endTime int64) error {
 
 // transactionRow is a helper struct for SQL query results
 type transactionRow struct {
-	CategoryId   int64  `xorm:"category_id"`
-	CategoryName string `xorm:"category_name"`
-	ActivityType int32  `xorm:"activity_type"`
-	CostType     int32  `xorm:"cost_type"`
-	Type         int32  `xorm:"type"`
-	Amount       int64  `xorm:"total_amount"`
+	CategoryId      int64  `xorm:"category_id"`
+	CategoryName    string `xorm:"category_name"`
+	ActivityType    int32  `xorm:"activity_type"`
+	CostType        int32  `xorm:"cost_type"`
+	Type            int32  `xorm:"type"`
+	Currency        string `xorm:"currency"`
+	TransactionTime int64  `xorm:"transaction_time"`
+	Amount          int64  `xorm:"total_amount"`
 }
 
 // GetCashFlow returns a Cash Flow Statement (ОДДС / Statement of Cash Flows).
@@ -100,9 +119,11 @@ type transactionRow struct {
 //   - Financing (activity_type=3): loans, investor contributions, debt payments
 //
 // Only confirmed (planned=false) income and expense transactions are included.
-// Transfers between accounts are excluded.
+// Transfers between accounts and non-cash depreciation and write-off transactions posted for assets are excluded.
 // Optionally filtered by CFO (Center of Financial Responsibility).
-func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64) (*models.CashFlowResponse, error) {
+// Amounts are converted into the reporting currency of converter (unchanged if converter is nil),
+// every transaction is converted by the exchange rates in effect at its transaction time if the converter has the exchange rates history.
+func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.CashFlowResponse, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -123,7 +144,7 @@ func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, star
 		args = append(args, cfoId)
 	}
 
-	query += " GROUP BY t.category_id, COALESCE(tc.name, 'Uncategorized'), COALESCE(NULLIF(tc.activity_type, 0), 1), t.type"
+	query += " GROUP BY t.category_id, COALESCE(tc.name, 'Uncategorized'), COALESCE(NULLIF(tc.activity_type, 0), 1), t.type, COALESCE(a.currency, ''), t.transaction_time"
 
 	err := s.UserDataDB(uid).NewSession(c).SQL(query, args...).Find(&rows)
 
@@ -144,6 +165,8 @@ func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, star
 		categoryId   int64
 	}
 	catAgg := map[catKey]*models.CashFlowActivityLine{}
+	catBreakdowns := map[catKey]*reportCurrencyBreakdown{}
+	totalBreakdown := newReportCurrencyBreakdown(converter)
 
 	for _, row := range rows {
 		at := row.ActivityType
@@ -159,17 +182,20 @@ func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, star
 				CategoryName: row.CategoryName,
 			}
 			catAgg[key] = line
+			catBreakdowns[key] = newReportCurrencyBreakdown(converter)
 		}
 
 		if row.Type == int32(models.TRANSACTION_DB_TYPE_INCOME) {
-			line.Income += row.Amount
+			line.Income += catBreakdowns[key].addAt(row.Amount, row.Currency, row.TransactionTime)
 		} else if row.Type == int32(models.TRANSACTION_DB_TYPE_EXPENSE) {
-			line.Expense += row.Amount
+			line.Expense += -catBreakdowns[key].addAt(-row.Amount, row.Currency, row.TransactionTime)
 		}
 	}
 
 	for key, line := range catAgg {
 		line.Net = line.Income - line.Expense
+		line.Currencies = catBreakdowns[key].toList()
+		totalBreakdown.merge(catBreakdowns[key], 1)
 		activity := activityMap[key.activityType]
 		activity.Lines = append(activity.Lines, line)
 		activity.TotalIncome += line.Income
@@ -185,8 +211,11 @@ func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, star
 	}
 
 	return &models.CashFlowResponse{
-		Activities: activities,
-		TotalNet:   totalNet,
+		Activities:        activities,
+		TotalNet:          totalNet,
+		Currency:          converter.ReportingCurrency(),
+		CurrencySubtotals: totalBreakdown.toList(),
+		Warnings:          converter.Warnings(),
 	}, nil
 }
 
@@ -197,12 +226,20 @@ func (s *ReportService) GetCashFlow(c core.Context, uid int64, cfoId int64, star
 //	- Cost of Goods Sold (expenses with cost_type=COGS)
 //	= Gross Profit
 //	- Operating Expenses (expenses with cost_type=operational)
-//	- Depreciation (straight-line, calculated from assets)
+//	- Depreciation (posted depreciation transactions, and the depreciation not posted yet calculated from assets)
 //	= Operating Profit (EBIT)
+//	+ Gain (Loss) on Disposal of Assets (proceeds minus book value of assets disposed in the period)
 //	- Financial Expenses (expenses with cost_type=financial)
 //	- Tax Expenses (from tax_record table, matched by period)
 //	= Net Profit
-func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64) (*models.PnLResponse, error) {
+//
+// Transactions linked to assets are not counted as revenue or expenses: purchase transactions are capitalized
+// into the assets, and depreciation and disposal transactions are reported in their own lines.
+// Amounts are converted into the reporting currency of converter (unchanged if converter is nil),
+// every transaction is converted by the exchange rates in effect at its transaction time if the converter has the exchange rates history,
+// and the details contain every line above split by original currency. The amounts of assets are in the currency of their book accounts,
+// or in the default currency if assets have no book account.
+func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.PnLResponse, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -224,7 +261,7 @@ func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime
 		args = append(args, cfoId)
 	}
 
-	query += " GROUP BY tc.cost_type, t.type"
+	query += " GROUP BY tc.cost_type, t.type, COALESCE(a.currency, ''), t.transaction_time"
 
 	err := s.UserDataDB(uid).NewSession(c).SQL(query, args...).Find(&rows)
 
@@ -232,72 +269,100 @@ func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime
 		return nil, err
 	}
 
-	response := &models.PnLResponse{}
+	response := &models.PnLResponse{
+		Currency: converter.ReportingCurrency(),
+	}
+
+	revenue := newReportCurrencyBreakdown(converter)
+	costOfGoods := newReportCurrencyBreakdown(converter)
+	operatingExpense := newReportCurrencyBreakdown(converter)
+	financialExpense := newReportCurrencyBreakdown(converter)
+	depreciation := newReportCurrencyBreakdown(converter)
+	disposalGainLoss := newReportCurrencyBreakdown(converter)
+	taxExpense := newReportCurrencyBreakdown(converter)
 
 	for _, row := range rows {
 		if row.Type == int32(models.TRANSACTION_DB_TYPE_INCOME) {
-			response.Revenue += row.Amount
+			revenue.addAt(row.Amount, row.Currency, row.TransactionTime)
 		} else if row.Type == int32(models.TRANSACTION_DB_TYPE_EXPENSE) {
 			switch models.CostType(row.CostType) {
 			case models.COST_TYPE_COGS:
-				response.CostOfGoods += row.Amount
+				costOfGoods.addAt(row.Amount, row.Currency, row.TransactionTime)
 			case models.COST_TYPE_OPERATIONAL:
-				response.OperatingExpense += row.Amount
+				operatingExpense.addAt(row.Amount, row.Currency, row.TransactionTime)
 			case models.COST_TYPE_FINANCIAL:
-				response.FinancialExpense += row.Amount
+				financialExpense.addAt(row.Amount, row.Currency, row.TransactionTime)
 			default:
-				response.OperatingExpense += row.Amount
+				operatingExpense.addAt(row.Amount, row.Currency, row.TransactionTime)
 			}
 		}
 	}
 
-	// Calculate depreciation from assets
+	// Depreciation posted as transactions
+	var postedDepreciationRows []*transactionRow
+
+	postedDepreciationQuery := buildPostedDepreciationQuery()
+	postedDepreciationArgs := []interface{}{uid, startTimeMs, endTimeMs}
+
+	if cfoId > 0 {
+		postedDepreciationQuery += cfoFilterClause
+		postedDepreciationArgs = append(postedDepreciationArgs, cfoId)
+	}
+
+	postedDepreciationQuery += " GROUP BY COALESCE(a.currency, ''), t.transaction_time"
+
+	err = s.UserDataDB(uid).NewSession(c).SQL(postedDepreciationQuery, postedDepreciationArgs...).Find(&postedDepreciationRows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range postedDepreciationRows {
+		depreciation.addAt(row.Amount, row.Currency, row.TransactionTime)
+	}
+
+	// Calculate depreciation not posted yet and disposal gain or loss from assets
 	assets, err := s.assets.GetAllAssetsByUid(c, uid)
 	if err != nil {
 		log.Warnf(c, "[reports.GetPnL] failed to load assets for uid:%d: %s", uid, err.Error())
 		response.Warnings = append(response.Warnings, "Failed to load asset data for depreciation calculation")
 	} else {
-		now := time.Now()
+		assetCurrencies, err := getAssetBookAccountCurrencies(c, s.UserDataDB(uid), uid, assets)
+		if err != nil {
+			return nil, err
+		}
+
+		asOfDate := time.Now()
+		if endTimeMs > 0 {
+			asOfDate = time.Unix(endTimeMs/1000, 0)
+		}
+		startDate := time.Unix(startTimeMs/1000, 0)
+
 		for _, asset := range assets {
-			if asset.CommissionDate <= 0 || asset.UsefulLifeMonths <= 0 {
-				continue
-			}
 			if !matchesCfo(cfoId, asset.CfoId) {
 				continue
 			}
 
-			commDate := time.Unix(asset.CommissionDate, 0)
-			asOfDate := now
-			if endTimeMs > 0 {
-				asOfDate = time.Unix(endTimeMs/1000, 0)
+			if decommissionTimeMs := utils.ToMillisIfSeconds(asset.DecommissionDate); asset.Status != models.ASSET_STATUS_ACTIVE && decommissionTimeMs >= startTimeMs && decommissionTimeMs < endTimeMs {
+				disposalGainLoss.add(asset.DisposalProceeds-asset.DisposalBookValue, assetCurrencies[asset.AccountId])
 			}
 
-			// Only count depreciation within the period
-			startDate := time.Unix(startTimeMs/1000, 0)
-			monthlyDepr := (asset.PurchaseCost - asset.SalvageValue) / int64(asset.UsefulLifeMonths)
-
-			// Months from commission to end of period
-			monthsToEnd := monthsBetween(commDate, asOfDate)
-			// Months from commission to start of period
-			monthsToStart := monthsBetween(commDate, startDate)
-
-			maxMonths := int64(asset.UsefulLifeMonths)
-			if monthsToEnd > maxMonths {
-				monthsToEnd = maxMonths
-			}
-			if monthsToStart > maxMonths {
-				monthsToStart = maxMonths
+			if asset.CommissionDate <= 0 || asset.UsefulLifeMonths <= 0 {
+				continue
 			}
-			if monthsToStart < 0 {
-				monthsToStart = 0
+
+			// Only count depreciation within the period and after the posted time, partial months are prorated by time
+			unpostedStartDate := startDate
+			if asset.DepreciationPostedTime > unpostedStartDate.Unix() {
+				unpostedStartDate = time.Unix(asset.DepreciationPostedTime, 0)
 			}
-			if monthsToEnd < 0 {
-				monthsToEnd = 0
+			if !unpostedStartDate.Before(asOfDate) {
+				continue
 			}
 
-			periodDepr := (monthsToEnd - monthsToStart) * monthlyDepr
+			periodDepr := getAssetAccumulatedDepreciation(asset, asOfDate) - getAssetAccumulatedDepreciation(asset, unpostedStartDate)
 			if periodDepr > 0 {
-				response.Depreciation += periodDepr
+				depreciation.add(periodDepr, assetCurrencies[asset.AccountId])
 			}
 		}
 	}
@@ -313,14 +378,36 @@ func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime
 				continue
 			}
 			if tr.DueDate >= startTimeMs && tr.DueDate < endTimeMs {
-				response.TaxExpense += tr.TaxAmount
+				taxExpense.add(tr.TaxAmount, tr.Currency)
 			}
 		}
 	}
 
+	response.Revenue = revenue.total
+	response.CostOfGoods = costOfGoods.total
+	response.OperatingExpense = operatingExpense.total
+	response.FinancialExpense = financialExpense.total
+	response.Depreciation = depreciation.total
+	response.DisposalGainLoss = disposalGainLoss.total
+	response.TaxExpense = taxExpense.total
+
+	if converter != nil {
+		response.Details = []*models.PnLLine{
+			{Label: models.PnLLabelRevenue, Amount: revenue.total, Currencies: revenue.toList()},
+			{Label: models.PnLLabelCostOfGoods, Amount: costOfGoods.total, Currencies: costOfGoods.toList()},
+			{Label: models.PnLLabelOperatingExpense, Amount: operatingExpense.total, Currencies: operatingExpense.toList()},
+			{Label: models.PnLLabelDepreciation, Amount: depreciation.total, Currencies: depreciation.toList()},
+			{Label: models.PnLLabelDisposalGainLoss, Amount: disposalGainLoss.total, Currencies: disposalGainLoss.toList()},
+			{Label: models.PnLLabelFinancialExpense, Amount: financialExpense.total, Currencies: financialExpense.toList()},
+			{Label: models.PnLLabelTaxExpense, Amount: taxExpense.total, Currencies: taxExpense.toList()},
+		}
+	}
+
+	response.Warnings = append(response.Warnings, converter.Warnings()...)
+
 	response.GrossProfit = response.Revenue - response.CostOfGoods
 	response.OperatingProfit = response.GrossProfit - response.OperatingExpense - response.Depreciation
-	response.NetProfit = response.OperatingProfit - response.FinancialExpense - response.TaxExpense
+	response.NetProfit = response.OperatingProfit + response.DisposalGainLoss - response.FinancialExpense - response.TaxExpense
 
 	return response, nil
 }
@@ -332,18 +419,68 @@ func (s *ReportService) GetPnL(c core.Context, uid int64, cfoId int64, startTime
 //	LIABILITIES = Payables + Credit Debts + Tax Liabilities + Investor Debt
 //	EQUITY = Total Assets - Total Liabilities
 //
-// Fixed asset residual values use straight-line depreciation:
+// Fixed asset residual values use the depreciation method of each asset, e.g. straight-line depreciation:
 //
 //	monthly_depreciation = (purchase_cost - salvage_value) / useful_life_months
 //	residual = purchase_cost - (months_elapsed * monthly_depreciation)
-func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*models.BalanceResponse, error) {
+//
+// The book accounts of assets, on which depreciation and disposal are posted, are not counted in cash and bank accounts,
+// because fixed assets are reported by their residual values instead. Disposed assets are excluded from fixed assets.
+//
+// If asOf is set, the balance sheet is rebuilt as of that time: account balances are accumulated
+// from the transaction ledger, assets purchased later or decommissioned earlier are excluded,
+// and obligations, tax records and investor deals created later are excluded. Paid amounts of
+// obligations settled by payments are accumulated from the payments made by that time, other paid
+// amounts and statuses of obligations and tax records are taken from their status histories.
+//
+// Every line is converted into the reporting currency of converter (unchanged if converter is nil),
+// and the currency subtotals contain the equity split by original currency. The residual values of assets are in the currency
+// of their book accounts, or in the default currency if assets have no book account.
+func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64, asOf int64, converter *ReportCurrencyConverter) (*models.BalanceResponse, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	asOfMs := utils.ToMillisIfSeconds(asOf)
+	isHistorical := asOfMs > 0
+	asOfTime := time.Now()
+
+	if isHistorical {
+		asOfTime = time.UnixMilli(asOfMs)
+	}
+
+	// existsAsOf returns whether the entity created or dated at the specified time exists at the as-of time
+	existsAsOf := func(entityTime int64) bool {
+		return !isHistorical || utils.ToMillisIfSeconds(entityTime) <= asOfMs
+	}
+
 	response := &models.BalanceResponse{
 		AssetLines:     []*models.BalanceLine{},
 		LiabilityLines: []*models.BalanceLine{},
+		AsOf:           asOfMs,
+		Currency:       converter.ReportingCurrency(),
+	}
+
+	equity := newReportCurrencyBreakdown(converter)
+	appendAssetLine := func(label string, breakdown *reportCurrencyBreakdown) {
+		if breakdown.hasNonZeroAmount() {
+			response.AssetLines = append(response.AssetLines, &models.BalanceLine{Label: label, Amount: breakdown.total, Currencies: breakdown.toList()})
+			equity.merge(breakdown, 1)
+		}
+	}
+	appendLiabilityLine := func(label string, breakdown *reportCurrencyBreakdown) {
+		if breakdown.hasNonZeroAmount() {
+			response.LiabilityLines = append(response.LiabilityLines, &models.BalanceLine{Label: label, Amount: breakdown.total, Currencies: breakdown.toList()})
+			equity.merge(breakdown, -1)
+		}
+	}
+
+	assets, assetsErr := s.assets.GetAllAssetsByUid(c, uid)
+	assetBookAccountIds := make(map[int64]bool)
+	for _, asset := range assets {
+		if asset.AccountId > 0 {
+			assetBookAccountIds[asset.AccountId] = true
+		}
 	}
 
 	// 1. Cash in accounts (assets)
@@ -353,19 +490,32 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 		return nil, err
 	}
 
-	cashAssets := int64(0)
-	cashLiabilities := int64(0)
+	var balancesAsOf map[int64]int64
+	if isHistorical {
+		balancesAsOf, err = s.getAccountBalancesAsOf(c, uid, asOfMs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cashAssets := newReportCurrencyBreakdown(converter)
+	cashLiabilities := newReportCurrencyBreakdown(converter)
 	for _, acc := range accounts {
+		if assetBookAccountIds[acc.AccountId] {
+			continue
+		}
+		balance := acc.Balance
+		if isHistorical {
+			balance = balancesAsOf[acc.AccountId]
+		}
 		if acc.Category.IsAsset() {
-			cashAssets += acc.Balance
+			cashAssets.add(balance, acc.Currency)
 		}
 		if acc.Category.IsLiability() {
-			cashLiabilities += acc.Balance
+			cashLiabilities.add(balance, acc.Currency)
 		}
 	}
-	if cashAssets != 0 {
-		response.AssetLines = append(response.AssetLines, &models.BalanceLine{Label: models.BalanceLabelCashAndBank, Amount: cashAssets})
-	}
+	appendAssetLine(models.BalanceLabelCashAndBank, cashAssets)
 
 	// 2. Receivables (obligation type 1, not fully paid)
 	var obligations []*models.Obligation
@@ -374,44 +524,58 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 		return nil, err
 	}
 
-	receivables := int64(0)
-	payables := int64(0)
+	var obligationPaidAmountsAsOf map[int64]int64
+	if isHistorical {
+		obligationPaidAmountsAsOf, err = s.getObligationPaidAmountsAsOf(c, uid, obligations, asOfMs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receivables := newReportCurrencyBreakdown(converter)
+	payables := newReportCurrencyBreakdown(converter)
 	for _, o := range obligations {
-		if !matchesCfo(cfoId, o.CfoId) {
+		if !matchesCfo(cfoId, o.CfoId) || !existsAsOf(o.CreatedUnixTime) {
 			continue
 		}
-		remaining := o.Amount - o.PaidAmount
+		paidAmount := o.PaidAmount
+		if isHistorical {
+			paidAmount = obligationPaidAmountsAsOf[o.ObligationId]
+		}
+		remaining := o.Amount - paidAmount
 		if remaining <= 0 {
 			continue
 		}
 		if o.ObligationType == models.OBLIGATION_TYPE_RECEIVABLE {
-			receivables += remaining
+			receivables.add(remaining, o.Currency)
 		} else if o.ObligationType == models.OBLIGATION_TYPE_PAYABLE {
-			payables += remaining
+			payables.add(remaining, o.Currency)
 		}
 	}
-	if receivables != 0 {
-		response.AssetLines = append(response.AssetLines, &models.BalanceLine{Label: models.BalanceLabelReceivables, Amount: receivables})
-	}
+	appendAssetLine(models.BalanceLabelReceivables, receivables)
 
 	// 3. Fixed assets (residual values)
-	assets, err := s.assets.GetAllAssetsByUid(c, uid)
-	if err != nil {
-		log.Warnf(c, "[reports.GetBalance] failed to load assets for uid:%d: %s", uid, err.Error())
+	if assetsErr != nil {
+		log.Warnf(c, "[reports.GetBalance] failed to load assets for uid:%d: %s", uid, assetsErr.Error())
 		response.Warnings = append(response.Warnings, "Failed to load asset data for fixed assets calculation")
 	} else {
-		now := time.Now()
-		totalResidual := int64(0)
+		assetCurrencies, err := getAssetBookAccountCurrencies(c, s.UserDataDB(uid), uid, assets)
+		if err != nil {
+			return nil, err
+		}
+
+		totalResidual := newReportCurrencyBreakdown(converter)
 		for _, asset := range assets {
-			if !matchesCfo(cfoId, asset.CfoId) {
+			if !matchesCfo(cfoId, asset.CfoId) || !existsAsOf(asset.PurchaseDate) {
 				continue
 			}
-			residual := calculateResidualValue(asset, now)
-			totalResidual += residual
-		}
-		if totalResidual != 0 {
-			response.AssetLines = append(response.AssetLines, &models.BalanceLine{Label: models.BalanceLabelFixedAssets, Amount: totalResidual})
+			if (isHistorical || asset.Status != models.ASSET_STATUS_ACTIVE) && asset.DecommissionDate > 0 && existsAsOf(asset.DecommissionDate) {
+				continue
+			}
+			residual := calculateResidualValue(asset, asOfTime)
+			totalResidual.add(residual, assetCurrencies[asset.AccountId])
 		}
+		appendAssetLine(models.BalanceLabelFixedAssets, totalResidual)
 	}
 
 	// Calculate total assets
@@ -420,34 +584,38 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 	}
 
 	// LIABILITIES
-	if payables != 0 {
-		response.LiabilityLines = append(response.LiabilityLines, &models.BalanceLine{Label: models.BalanceLabelPayables, Amount: payables})
-	}
-	if cashLiabilities != 0 {
-		response.LiabilityLines = append(response.LiabilityLines, &models.BalanceLine{Label: models.BalanceLabelCreditCards, Amount: cashLiabilities})
-	}
+	appendLiabilityLine(models.BalanceLabelPayables, payables)
+	appendLiabilityLine(models.BalanceLabelCreditCards, cashLiabilities)
 
 	// Tax liabilities (unpaid)
 	taxRecords, err := s.taxes.GetAllTaxRecordsByUid(c, uid)
+	var taxRecordStatesAsOf map[int64]*entityStateAsOf
+	if err == nil && isHistorical {
+		taxRecordStatesAsOf, err = getEntityStatesAsOf(s.UserDataDB(uid).NewSession(c), uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, asOfMs)
+	}
 	if err != nil {
 		log.Warnf(c, "[reports.GetBalance] failed to load tax records for uid:%d: %s", uid, err.Error())
 		response.Warnings = append(response.Warnings, "Failed to load tax records for tax liabilities calculation")
 	} else {
-		taxLiability := int64(0)
+		taxLiability := newReportCurrencyBreakdown(converter)
 		for _, tr := range taxRecords {
-			if !matchesCfo(cfoId, tr.CfoId) {
+			if !matchesCfo(cfoId, tr.CfoId) || !existsAsOf(tr.CreatedUnixTime) {
 				continue
 			}
-			if tr.Status != models.TAX_STATUS_PAID {
-				remaining := tr.TaxAmount - tr.PaidAmount
+			status := tr.Status
+			paidAmount := tr.PaidAmount
+			if state, exists := taxRecordStatesAsOf[tr.TaxId]; exists {
+				status = models.TaxStatus(state.Status)
+				paidAmount = state.PaidAmount
+			}
+			if status != models.TAX_STATUS_PAID {
+				remaining := tr.TaxAmount - paidAmount
 				if remaining > 0 {
-					taxLiability += remaining
+					taxLiability.add(remaining, tr.Currency)
 				}
 			}
 		}
-		if taxLiability != 0 {
-			response.LiabilityLines = append(response.LiabilityLines, &models.BalanceLine{Label: models.BalanceLabelTaxLiabilities, Amount: taxLiability})
-		}
+		appendLiabilityLine(models.BalanceLabelTaxLiabilities, taxLiability)
 	}
 
 	// Investor debt
@@ -460,7 +628,7 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 		var dealIds []int64
 		filteredDeals := make([]*models.InvestorDeal, 0, len(deals))
 		for _, deal := range deals {
-			if !matchesCfo(cfoId, deal.CfoId) {
+			if !matchesCfo(cfoId, deal.CfoId) || !existsAsOf(deal.InvestmentDate) {
 				continue
 			}
 			dealIds = append(dealIds, deal.DealId)
@@ -472,20 +640,20 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 			log.Warnf(c, "[reports.GetBalance] failed to load investor payments for uid:%d: %s", uid, pErr.Error())
 			response.Warnings = append(response.Warnings, "Failed to load investor payments for investor debt calculation")
 		} else {
-			investorDebt := int64(0)
+			investorDebt := newReportCurrencyBreakdown(converter)
 			for _, deal := range filteredDeals {
 				totalPaid := int64(0)
 				for _, p := range paymentsByDeal[deal.DealId] {
-					totalPaid += p.Amount
+					if existsAsOf(p.PaymentDate) {
+						totalPaid += p.Amount
+					}
 				}
 				remaining := deal.TotalToRepay - totalPaid
 				if remaining > 0 {
-					investorDebt += remaining
+					investorDebt.add(remaining, deal.Currency)
 				}
 			}
-			if investorDebt != 0 {
-				response.LiabilityLines = append(response.LiabilityLines, &models.BalanceLine{Label: models.BalanceLabelInvestorDebt, Amount: investorDebt})
-			}
+			appendLiabilityLine(models.BalanceLabelInvestorDebt, investorDebt)
 		}
 	}
 
@@ -495,17 +663,162 @@ func (s *ReportService) GetBalance(c core.Context, uid int64, cfoId int64) (*mod
 	}
 
 	response.Equity = response.TotalAssets - response.TotalLiability
+	response.CurrencySubtotals = equity.toList()
+	response.Warnings = append(response.Warnings, converter.Warnings()...)
 
 	return response, nil
 }
 
-// GetPaymentCalendar returns upcoming payments from three sources:
+// GetComparativeBalance returns the balance sheets as of two times side by side, and the delta of every line
+// which is the amount as of asOf minus the amount as of compareAsOf
+func (s *ReportService) GetComparativeBalance(c core.Context, uid int64, cfoId int64, asOf int64, compareAsOf int64, converter *ReportCurrencyConverter, compareConverter *ReportCurrencyConverter) (*models.ComparativeBalanceResponse, error) {
+	current, err := s.GetBalance(c, uid, cfoId, asOf, converter)
+	if err != nil {
+		return nil, err
+	}
+
+	compare, err := s.GetBalance(c, uid, cfoId, compareAsOf, compareConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ComparativeBalanceResponse{
+		Current:             current,
+		Compare:             compare,
+		AssetLines:          buildComparativeBalanceLines(current.AssetLines, compare.AssetLines),
+		LiabilityLines:      buildComparativeBalanceLines(current.LiabilityLines, compare.LiabilityLines),
+		TotalAssetsDelta:    current.TotalAssets - compare.TotalAssets,
+		TotalLiabilityDelta: current.TotalLiability - compare.TotalLiability,
+		EquityDelta:         current.Equity - compare.Equity,
+	}, nil
+}
+
+// buildComparativeBalanceLines matches the lines of two balance sheets by label, keeping the order of current lines first
+func buildComparativeBalanceLines(currentLines []*models.BalanceLine, compareLines []*models.BalanceLine) []*models.ComparativeBalanceLine {
+	lines := make([]*models.ComparativeBalanceLine, 0, len(currentLines))
+	linesByLabel := make(map[string]*models.ComparativeBalanceLine, len(currentLines))
+
+	for _, line := range currentLines {
+		comparativeLine := &models.ComparativeBalanceLine{Label: line.Label, Amount: line.Amount}
+		lines = append(lines, comparativeLine)
+		linesByLabel[line.Label] = comparativeLine
+	}
+
+	for _, line := range compareLines {
+		comparativeLine, exists := linesByLabel[line.Label]
+		if !exists {
+			comparativeLine = &models.ComparativeBalanceLine{Label: line.Label}
+			lines = append(lines, comparativeLine)
+			linesByLabel[line.Label] = comparativeLine
+		}
+		comparativeLine.CompareAmount = line.Amount
+	}
+
+	for _, line := range lines {
+		line.Delta = line.Amount - line.CompareAmount
+	}
+
+	return lines
+}
+
+// getAccountBalancesAsOf rebuilds the balance of every account from all confirmed transactions up to the specified time (in milliseconds)
+func (s *ReportService) getAccountBalancesAsOf(c core.Context, uid int64, asOfMs int64) (map[int64]int64, error) {
+	query := fmt.Sprintf(`SELECT t.account_id, SUM(CASE t.type
+			WHEN %d THEN t.related_account_amount
+			WHEN %d THEN t.amount
+			WHEN %d THEN -t.amount
+			WHEN %d THEN -t.amount
+			WHEN %d THEN t.amount
+			ELSE 0 END) as total_amount
+		FROM "transaction" t
+		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0 AND t.transaction_time <= ?
+		GROUP BY t.account_id`,
+		models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE,
+		models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+
+	var rows []struct {
+		AccountId int64 `xorm:"account_id"`
+		Amount    int64 `xorm:"total_amount"`
+	}
+
+	err := s.UserDataDB(uid).NewSession(c).SQL(query, uid, asOfMs).Find(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		balances[row.AccountId] = row.Amount
+	}
+
+	return balances, nil
+}
+
+// getObligationPaidAmountsAsOf returns the paid amounts as of the specified time (in milliseconds) of the obligations, the paid amounts of
+// obligations settled by payments are accumulated from the payments made by that time, and the others are taken from their status histories
+func (s *ReportService) getObligationPaidAmountsAsOf(c core.Context, uid int64, obligations []*models.Obligation, asOfMs int64) (map[int64]int64, error) {
+	paymentAmounts, err := s.getObligationPaymentAmountsAsOf(c, uid, asOfMs)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := getEntityStatesAsOf(s.UserDataDB(uid).NewSession(c), uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, asOfMs)
+	if err != nil {
+		return nil, err
+	}
+
+	paidAmounts := make(map[int64]int64, len(obligations))
+	for _, o := range obligations {
+		if paymentAmount, exists := paymentAmounts[o.ObligationId]; exists {
+			paidAmounts[o.ObligationId] = paymentAmount
+		} else if state, exists := states[o.ObligationId]; exists {
+			paidAmounts[o.ObligationId] = state.PaidAmount
+		} else {
+			paidAmounts[o.ObligationId] = o.PaidAmount
+		}
+	}
+
+	return paidAmounts, nil
+}
+
+// getObligationPaymentAmountsAsOf returns the amounts paid by the as-of time (in milliseconds) of all obligations which have payments
+func (s *ReportService) getObligationPaymentAmountsAsOf(c core.Context, uid int64, asOfMs int64) (map[int64]int64, error) {
+	query := `SELECT p.obligation_id, SUM(CASE WHEN p.payment_date <= ? THEN p.amount ELSE 0 END) as total_amount
+		FROM obligation_payment p
+		WHERE p.uid = ? AND p.deleted = 0
+		GROUP BY p.obligation_id`
+
+	var rows []struct {
+		ObligationId int64 `xorm:"obligation_id"`
+		Amount       int64 `xorm:"total_amount"`
+	}
+
+	err := s.UserDataDB(uid).NewSession(c).SQL(query, asOfMs, uid).Find(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	paidAmounts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		paidAmounts[row.ObligationId] = row.Amount
+	}
+
+	return paidAmounts, nil
+}
+
+// GetPaymentCalendar returns upcoming payments from four sources:
 //  1. Obligations (receivables/payables) with due dates in range
 //  2. Tax records with due dates in range
-//  3. Planned (unconfirmed) transactions with dates in range
+//  3. Taxes computed by tax regimes for the periods due in range which have no tax record yet
+//  4. Planned (unconfirmed) transactions with dates in range
 //
-// Results are sorted by date ascending.
-func (s *ReportService) GetPaymentCalendar(c core.Context, uid int64, startTime int64, endTime int64) (*models.PaymentCalendarResponse, error) {
+// Results are sorted by date ascending. Every item is converted into the reporting currency
+// of converter (unchanged if converter is nil), and the currency subtotals contain the net
+// cash flow (receivables and planned income minus payables, taxes and planned expenses).
+// Overdue obligations and tax records are flagged. The paid obligations and tax records due
+// in range are returned as settled items flagged whether they were paid late, and are not
+// counted in the currency subtotals.
+func (s *ReportService) GetPaymentCalendar(c core.Context, uid int64, startTime int64, endTime int64, converter *ReportCurrencyConverter) (*models.PaymentCalendarResponse, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -519,6 +832,24 @@ func (s *ReportService) GetPaymentCalendar(c core.Context, uid int64, startTime
 
 	items := []*models.PaymentCalendarItem{}
 	var warnings []string
+	subtotals := newReportCurrencyBreakdown(converter)
+	appendItem := func(item *models.PaymentCalendarItem, inflow bool) {
+		convertedAmount, rate, ok := converter.Convert(item.Amount, item.Currency)
+		item.ConvertedAmount = convertedAmount
+		item.RateMissing = !ok
+
+		if ok && converter != nil {
+			item.Rate = utils.Float64ToString(rate)
+		}
+
+		if inflow {
+			subtotals.add(item.Amount, item.Currency)
+		} else {
+			subtotals.add(-item.Amount, item.Currency)
+		}
+
+		items = append(items, item)
+	}
 
 	// 1. Obligations with due dates in range
 	var obligations []*models.Obligation
@@ -533,13 +864,14 @@ func (s *ReportService) GetPaymentCalendar(c core.Context, uid int64, startTime
 				typeName = models.PaymentTypePayable
 			}
 			remaining := o.Amount - o.PaidAmount
-			items = append(items, &models.PaymentCalendarItem{
+			appendItem(&models.PaymentCalendarItem{
 				Date:        o.DueDate,
 				Type:        typeName,
 				Amount:      remaining,
 				Description: o.Comment,
 				Currency:    o.Currency,
-			})
+				Overdue:     o.Status == models.OBLIGATION_STATUS_OVERDUE,
+			}, o.ObligationType == models.OBLIGATION_TYPE_RECEIVABLE)
 		}
 	}
 
@@ -552,48 +884,194 @@ func (s *ReportService) GetPaymentCalendar(c core.Context, uid int64, startTime
 	} else {
 		for _, tr := range taxRecords {
 			remaining := tr.TaxAmount - tr.PaidAmount
-			items = append(items, &models.PaymentCalendarItem{
+			appendItem(&models.PaymentCalendarItem{
 				Date:        tr.DueDate,
 				Type:        models.PaymentTypeTax,
 				Amount:      remaining,
 				Description: tr.Comment,
 				Currency:    tr.Currency,
-			})
+				Overdue:     tr.Status == models.TAX_STATUS_OVERDUE,
+			}, false)
+		}
+	}
+
+	// 3. Taxes scheduled by tax regimes in range
+	scheduledTaxes, err := getScheduledTaxCalculations(c, s.UserDataDB(uid), uid, startTimeMs, endTimeMs, time.Local)
+	if err != nil {
+		log.Warnf(c, "[reports.GetPaymentCalendar] failed to calculate scheduled taxes for uid:%d: %s", uid, err.Error())
+		warnings = append(warnings, "Failed to calculate taxes scheduled by tax regimes")
+	} else {
+		for _, tc := range scheduledTaxes {
+			appendItem(&models.PaymentCalendarItem{
+				Date:        tc.DueDate,
+				Type:        models.PaymentTypeEstimatedTax,
+				Amount:      tc.TaxAmount,
+				Description: fmt.Sprintf("%s for %s", tc.RegimeType, getTaxPeriodName(tc.PeriodYear, tc.PeriodQuarter)),
+				Currency:    tc.Currency,
+			}, false)
 		}
 	}
 
-	// 3. Planned transactions in range
+	// 4. Planned transactions in range
 	var plannedTransactions []*models.Transaction
 	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND planned=? AND transaction_time>=? AND transaction_time<?", uid, false, true, startTimeMs, endTimeMs).Find(&plannedTransactions)
 	if err != nil {
 		log.Warnf(c, "[reports.GetPaymentCalendar] failed to load planned transactions for uid:%d: %s", uid, err.Error())
 		warnings = append(warnings, "Failed to load planned transactions")
 	} else {
+		accountCurrencies := make(map[int64]string)
+
+		if len(plannedTransactions) > 0 {
+			var accounts []*models.Account
+			err = s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Find(&accounts)
+
+			if err != nil {
+				log.Warnf(c, "[reports.GetPaymentCalendar] failed to load accounts for uid:%d: %s", uid, err.Error())
+				warnings = append(warnings, "Failed to load account currencies of planned transactions")
+			}
+
+			for _, account := range accounts {
+				accountCurrencies[account.AccountId] = account.Currency
+			}
+		}
+
 		for _, t := range plannedTransactions {
 			typeName := models.PaymentTypePlanned
-			items = append(items, &models.PaymentCalendarItem{
+			appendItem(&models.PaymentCalendarItem{
 				Date:        t.TransactionTime,
 				Type:        typeName,
 				Amount:      t.Amount,
 				Description: t.Comment,
-				Currency:    "",
-			})
+				Currency:    accountCurrencies[t.AccountId],
+			}, t.Type == models.TRANSACTION_DB_TYPE_INCOME || t.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN)
 		}
 	}
 
+	// 5. Paid obligations and tax records with due dates in range
+	settledItems, err := s.getSettledPaymentCalendarItems(c, uid, startTimeMs, endTimeMs, converter)
+	if err != nil {
+		log.Warnf(c, "[reports.GetPaymentCalendar] failed to load paid obligations and tax records for uid:%d: %s", uid, err.Error())
+		warnings = append(warnings, "Failed to load paid obligations and tax records")
+	}
+
 	// Sort by date
 	sort.Slice(items, func(i, j int) bool {
 		return items[i].Date < items[j].Date
 	})
 
 	return &models.PaymentCalendarResponse{
-		Items:    items,
-		Warnings: warnings,
+		Items:             items,
+		SettledItems:      settledItems,
+		Currency:          converter.ReportingCurrency(),
+		CurrencySubtotals: subtotals.toList(),
+		Warnings:          append(warnings, converter.Warnings()...),
 	}, nil
 }
 
+// getSettledPaymentCalendarItems returns the paid obligations and tax records with due dates in range as payment calendar items sorted by date,
+// the items which were overdue or paid after the due dates are flagged as paid late
+func (s *ReportService) getSettledPaymentCalendarItems(c core.Context, uid int64, startTimeMs int64, endTimeMs int64, converter *ReportCurrencyConverter) ([]*models.PaymentCalendarItem, error) {
+	var obligations []*models.Obligation
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND status=? AND due_date>=? AND due_date<?", uid, false, models.OBLIGATION_STATUS_PAID, startTimeMs, endTimeMs).Find(&obligations)
+	if err != nil {
+		return nil, err
+	}
+
+	var taxRecords []*models.TaxRecord
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND status=? AND due_date>=? AND due_date<?", uid, false, models.TAX_STATUS_PAID, startTimeMs, endTimeMs).Find(&taxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	paidLateObligationIds, err := getPaidLateObligationIds(s.UserDataDB(uid).NewSession(c), uid, obligations)
+	if err != nil {
+		return nil, err
+	}
+
+	paidLateTaxIds, err := getPaidLateTaxRecordIds(s.UserDataDB(uid).NewSession(c), uid, taxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*models.PaymentCalendarItem
+	appendItem := func(item *models.PaymentCalendarItem) {
+		convertedAmount, rate, ok := converter.Convert(item.Amount, item.Currency)
+		item.ConvertedAmount = convertedAmount
+		item.RateMissing = !ok
+
+		if ok && converter != nil {
+			item.Rate = utils.Float64ToString(rate)
+		}
+
+		items = append(items, item)
+	}
+
+	for _, o := range obligations {
+		typeName := models.PaymentTypeReceivable
+		if o.ObligationType == models.OBLIGATION_TYPE_PAYABLE {
+			typeName = models.PaymentTypePayable
+		}
+		appendItem(&models.PaymentCalendarItem{
+			Date:        o.DueDate,
+			Type:        typeName,
+			Amount:      o.Amount,
+			Description: o.Comment,
+			Currency:    o.Currency,
+			PaidLate:    paidLateObligationIds[o.ObligationId],
+		})
+	}
+
+	for _, tr := range taxRecords {
+		appendItem(&models.PaymentCalendarItem{
+			Date:        tr.DueDate,
+			Type:        models.PaymentTypeTax,
+			Amount:      tr.TaxAmount,
+			Description: tr.Comment,
+			Currency:    tr.Currency,
+			PaidLate:    paidLateTaxIds[tr.TaxId],
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Date < items[j].Date
+	})
+
+	return items, nil
+}
+
+// getAssetBookAccountCurrencies returns the currencies of the book accounts of assets by account id,
+// the book accounts deleted later are included because the amounts posted on them are still in their currencies
+func getAssetBookAccountCurrencies(c core.Context, database *datastore.Database, uid int64, assets []*models.Asset) (map[int64]string, error) {
+	currencies := make(map[int64]string)
+	var accountIds []int64
+
+	for _, asset := range assets {
+		if asset.AccountId > 0 {
+			accountIds = append(accountIds, asset.AccountId)
+		}
+	}
+
+	if len(accountIds) < 1 {
+		return currencies, nil
+	}
+
+	var accounts []*models.Account
+	err := database.NewSession(c).Cols("account_id", "currency").Where("uid=?", uid).In("account_id", accountIds).Find(&accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		currencies[account.AccountId] = account.Currency
+	}
+
+	return currencies, nil
+}
+
 // calculateResidualValue calculates the residual (book) value of a fixed asset
-// at a given point in time using straight-line depreciation.
+// at a given point in time using the depreciation method of the asset.
+// Depreciation stops at the decommission date, and partial months are prorated by time.
 // If the asset has no commission date or zero useful life, returns purchase cost.
 // Returns at minimum the salvage value.
 func calculateResidualValue(asset *models.Asset, asOf time.Time) int64 {
@@ -601,21 +1079,7 @@ func calculateResidualValue(asset *models.Asset, asOf time.Time) int64 {
 		return asset.PurchaseCost
 	}
 
-	commDate := time.Unix(asset.CommissionDate, 0)
-	months := monthsBetween(commDate, asOf)
-	if months <= 0 {
-		return asset.PurchaseCost
-	}
-
-	monthlyDepr := (asset.PurchaseCost - asset.SalvageValue) / int64(asset.UsefulLifeMonths)
-	maxMonths := int64(asset.UsefulLifeMonths)
-
-	if months > maxMonths {
-		months = maxMonths
-	}
-
-	accumulated := months * monthlyDepr
-	residual := asset.PurchaseCost - accumulated
+	residual := asset.PurchaseCost - getAssetAccumulatedDepreciation(asset, asOf)
 	if residual < asset.SalvageValue {
 		residual = asset.SalvageValue
 	}
@@ -623,7 +1087,6 @@ func calculateResidualValue(asset *models.Asset, asOf time.Time) int64 {
 	return residual
 }
 
-
 // monthsBetween calculates the number of whole months between two dates.
 // Returns 0 if 'to' is before 'from'.
 func monthsBetween(from time.Time, to time.Time) int64 {
@@ -0,0 +1,239 @@
+// reports_aging.go provides the accounts receivable and payable aging report.
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const agingDayMillis = 24 * 60 * 60 * 1000
+
+// GetAging returns an Aging Report of open receivables and payables.
+// The remaining amount of every unpaid obligation is grouped by counterparty and bucketed by days overdue
+// at the as-of time (now if asOf is not set). If asOf is set, the obligations created later are excluded and
+// the paid amounts are rebuilt as of that time from the payments and status histories of obligations:
+//
+//	Current (not yet due or without due date), 1–30, 31–60, 61–90 and over 90 days overdue
+//
+// Optionally filtered by CFO. Amounts are converted into the reporting currency of converter (unchanged if converter is nil),
+// and counterparty lines are ordered by total open amount descending. Every counterparty line also counts the paid obligations
+// of the counterparty which were paid on time or late.
+func (s *ReportService) GetAging(c core.Context, uid int64, cfoId int64, asOf int64, converter *ReportCurrencyConverter) (*models.AgingReportResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	asOfMs := utils.ToMillisIfSeconds(asOf)
+	isHistorical := asOfMs > 0
+
+	if !isHistorical {
+		asOfMs = time.Now().UnixMilli()
+	}
+
+	var allObligations []*models.Obligation
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&allObligations)
+	if err != nil {
+		return nil, err
+	}
+
+	var paidAmountsAsOf map[int64]int64
+	if isHistorical {
+		paidAmountsAsOf, err = s.getObligationPaidAmountsAsOf(c, uid, allObligations, asOfMs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var obligations []*models.Obligation
+	var paidObligations []*models.Obligation
+	paidAmounts := make(map[int64]int64, len(allObligations))
+
+	for _, o := range allObligations {
+		if !isHistorical {
+			paidAmounts[o.ObligationId] = o.PaidAmount
+
+			if o.Status == models.OBLIGATION_STATUS_PAID {
+				paidObligations = append(paidObligations, o)
+			} else {
+				obligations = append(obligations, o)
+			}
+
+			continue
+		}
+
+		if utils.ToMillisIfSeconds(o.CreatedUnixTime) > asOfMs {
+			continue
+		}
+
+		paidAmounts[o.ObligationId] = paidAmountsAsOf[o.ObligationId]
+
+		if models.GetObligationStatus(o.Amount, paidAmountsAsOf[o.ObligationId]) == models.OBLIGATION_STATUS_PAID {
+			paidObligations = append(paidObligations, o)
+		} else {
+			obligations = append(obligations, o)
+		}
+	}
+
+	var warnings []string
+	counterpartyNames := make(map[int64]string)
+
+	var counterparties []*models.Counterparty
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Find(&counterparties)
+	if err != nil {
+		log.Warnf(c, "[reports.GetAging] failed to load counterparties for uid:%d: %s", uid, err.Error())
+		warnings = append(warnings, "Failed to load counterparty names")
+	} else {
+		for _, counterparty := range counterparties {
+			counterpartyNames[counterparty.CounterpartyId] = counterparty.Name
+		}
+	}
+
+	receivables := make(map[int64]*models.AgingLine)
+	payables := make(map[int64]*models.AgingLine)
+
+	for _, o := range obligations {
+		if !matchesCfo(cfoId, o.CfoId) {
+			continue
+		}
+
+		remaining := o.Amount - paidAmounts[o.ObligationId]
+		if remaining <= 0 {
+			continue
+		}
+
+		lines := receivables
+		if o.ObligationType == models.OBLIGATION_TYPE_PAYABLE {
+			lines = payables
+		} else if o.ObligationType != models.OBLIGATION_TYPE_RECEIVABLE {
+			continue
+		}
+
+		line, exists := lines[o.CounterpartyId]
+		if !exists {
+			line = &models.AgingLine{
+				CounterpartyId:   o.CounterpartyId,
+				CounterpartyName: counterpartyNames[o.CounterpartyId],
+				Buckets:          &models.AgingBuckets{},
+			}
+			lines[o.CounterpartyId] = line
+		}
+
+		convertedAmount, _, _ := converter.Convert(remaining, o.Currency)
+		addToAgingBucket(line.Buckets, getDaysOverdue(o.DueDate, asOfMs), convertedAmount)
+		line.ObligationCount++
+	}
+
+	err = s.countPaidObligationsOnTime(c, uid, cfoId, paidObligations, receivables, payables)
+	if err != nil {
+		log.Warnf(c, "[reports.GetAging] failed to count paid obligations for uid:%d: %s", uid, err.Error())
+		warnings = append(warnings, "Failed to count paid obligations")
+	}
+
+	return &models.AgingReportResponse{
+		AsOf:        asOfMs,
+		Receivables: buildAgingSection(receivables),
+		Payables:    buildAgingSection(payables),
+		Currency:    converter.ReportingCurrency(),
+		Warnings:    append(warnings, converter.Warnings()...),
+	}, nil
+}
+
+// countPaidObligationsOnTime counts the paid obligations of the counterparties which have aging lines by whether they were paid on time or late,
+// the obligation was paid late if it has ever been overdue or it became paid after the due date
+func (s *ReportService) countPaidObligationsOnTime(c core.Context, uid int64, cfoId int64, paidObligations []*models.Obligation, receivables map[int64]*models.AgingLine, payables map[int64]*models.AgingLine) error {
+	if len(receivables) < 1 && len(payables) < 1 {
+		return nil
+	}
+
+	paidLateIds, err := getPaidLateObligationIds(s.UserDataDB(uid).NewSession(c), uid, paidObligations)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range paidObligations {
+		if !matchesCfo(cfoId, o.CfoId) {
+			continue
+		}
+
+		lines := receivables
+		if o.ObligationType == models.OBLIGATION_TYPE_PAYABLE {
+			lines = payables
+		}
+
+		line, exists := lines[o.CounterpartyId]
+		if !exists {
+			continue
+		}
+
+		if paidLateIds[o.ObligationId] {
+			line.PaidLateCount++
+		} else {
+			line.PaidOnTimeCount++
+		}
+	}
+
+	return nil
+}
+
+// getDaysOverdue returns the number of whole days passed from the due date to the as-of time, or zero if the obligation is not yet due or has no due date
+func getDaysOverdue(dueDate int64, asOfMs int64) int64 {
+	dueDateMs := utils.ToMillisIfSeconds(dueDate)
+
+	if dueDateMs <= 0 || dueDateMs >= asOfMs {
+		return 0
+	}
+
+	return (asOfMs - dueDateMs) / agingDayMillis
+}
+
+// addToAgingBucket adds the amount into the bucket matching the days overdue
+func addToAgingBucket(buckets *models.AgingBuckets, daysOverdue int64, amount int64) {
+	switch {
+	case daysOverdue <= 0:
+		buckets.Current += amount
+	case daysOverdue <= 30:
+		buckets.Days1To30 += amount
+	case daysOverdue <= 60:
+		buckets.Days31To60 += amount
+	case daysOverdue <= 90:
+		buckets.Days61To90 += amount
+	default:
+		buckets.Over90Days += amount
+	}
+
+	buckets.Total += amount
+}
+
+// buildAgingSection returns the section which contains all counterparty lines ordered by total descending and their totals
+func buildAgingSection(lines map[int64]*models.AgingLine) *models.AgingSection {
+	section := &models.AgingSection{
+		Lines:  make([]*models.AgingLine, 0, len(lines)),
+		Totals: &models.AgingBuckets{},
+	}
+
+	for _, line := range lines {
+		section.Lines = append(section.Lines, line)
+		section.Totals.Current += line.Buckets.Current
+		section.Totals.Days1To30 += line.Buckets.Days1To30
+		section.Totals.Days31To60 += line.Buckets.Days31To60
+		section.Totals.Days61To90 += line.Buckets.Days61To90
+		section.Totals.Over90Days += line.Buckets.Over90Days
+		section.Totals.Total += line.Buckets.Total
+	}
+
+	sort.Slice(section.Lines, func(i, j int) bool {
+		if section.Lines[i].Buckets.Total != section.Lines[j].Buckets.Total {
+			return section.Lines[i].Buckets.Total > section.Lines[j].Buckets.Total
+		}
+
+		return section.Lines[i].CounterpartyId < section.Lines[j].CounterpartyId
+	})
+
+	return section
+}
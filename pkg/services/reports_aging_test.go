@@ -0,0 +1,213 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetDaysOverdue(t *testing.T) {
+	asOf := int64(1704067200000) // 2024-01-01 00:00:00 UTC
+
+	assert.Equal(t, int64(0), getDaysOverdue(0, asOf))
+	assert.Equal(t, int64(0), getDaysOverdue(asOf+agingDayMillis, asOf))
+	assert.Equal(t, int64(0), getDaysOverdue(asOf, asOf))
+	assert.Equal(t, int64(0), getDaysOverdue(asOf-agingDayMillis+1, asOf))
+	assert.Equal(t, int64(1), getDaysOverdue(asOf-agingDayMillis, asOf))
+	assert.Equal(t, int64(45), getDaysOverdue(asOf-45*agingDayMillis, asOf))
+	assert.Equal(t, int64(45), getDaysOverdue((asOf-45*agingDayMillis)/1000, asOf))
+}
+
+func TestAddToAgingBucket(t *testing.T) {
+	buckets := &models.AgingBuckets{}
+
+	addToAgingBucket(buckets, 0, 1)
+	addToAgingBucket(buckets, 1, 10)
+	addToAgingBucket(buckets, 30, 10)
+	addToAgingBucket(buckets, 31, 100)
+	addToAgingBucket(buckets, 60, 100)
+	addToAgingBucket(buckets, 61, 1000)
+	addToAgingBucket(buckets, 90, 1000)
+	addToAgingBucket(buckets, 91, 10000)
+
+	assert.Equal(t, int64(1), buckets.Current)
+	assert.Equal(t, int64(20), buckets.Days1To30)
+	assert.Equal(t, int64(200), buckets.Days31To60)
+	assert.Equal(t, int64(2000), buckets.Days61To90)
+	assert.Equal(t, int64(10000), buckets.Over90Days)
+	assert.Equal(t, int64(12221), buckets.Total)
+}
+
+func TestReportService_GetAging_InvalidUid(t *testing.T) {
+	svc := newTestReportService(&mockAssetProvider{}, &mockTaxRecordProvider{}, &mockInvestorDealProvider{}, &mockInvestorPaymentProvider{})
+
+	_, err := svc.GetAging(nil, 0, 0, 0, nil)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
+
+// TestReportService_GetAging_WithDB verifies that open obligations are grouped by counterparty
+// and bucketed by days overdue, with paid and other CFO obligations excluded.
+func TestReportService_GetAging_WithDB(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	asOf := int64(1704067200000) // 2024-01-01 00:00:00 UTC
+	dayMs := int64(agingDayMillis)
+
+	counterparties := []*models.Counterparty{
+		{CounterpartyId: 10, Uid: uid, Name: "Acme"},
+		{CounterpartyId: 20, Uid: uid, Name: "Globex"},
+	}
+	for _, counterparty := range counterparties {
+		_, err := tdb.engine.Insert(counterparty)
+		assert.Nil(t, err)
+	}
+
+	obligations := []*models.Obligation{
+		{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, CfoId: 1, Amount: 10000, Currency: "RUB", DueDate: asOf + 5*dayMs, Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 2, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, CfoId: 1, Amount: 20000, PaidAmount: 5000, Currency: "RUB", DueDate: asOf - 10*dayMs, Status: models.OBLIGATION_STATUS_PARTIAL},
+		{ObligationId: 3, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, CfoId: 1, Amount: 30000, Currency: "RUB", DueDate: asOf - 120*dayMs, Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 4, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 20, CfoId: 1, Amount: 70000, Currency: "RUB", DueDate: asOf - 45*dayMs, Status: models.OBLIGATION_STATUS_ACTIVE},
+		// paid obligation is excluded
+		{ObligationId: 5, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 20, CfoId: 1, Amount: 50000, PaidAmount: 50000, Currency: "RUB", DueDate: asOf - 45*dayMs, Status: models.OBLIGATION_STATUS_PAID},
+		// obligation of another CFO
+		{ObligationId: 6, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 20, CfoId: 2, Amount: 1000, Currency: "RUB", DueDate: asOf - 45*dayMs, Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 7, Uid: uid, ObligationType: models.OBLIGATION_TYPE_PAYABLE, CounterpartyId: 20, CfoId: 1, Amount: 8000, Currency: "RUB", DueDate: asOf - 75*dayMs, Status: models.OBLIGATION_STATUS_ACTIVE},
+	}
+	for _, o := range obligations {
+		_, err := tdb.engine.Insert(o)
+		assert.Nil(t, err)
+	}
+
+	result, err := svc.GetAging(nil, uid, 1, asOf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, asOf, result.AsOf)
+
+	assert.Equal(t, 2, len(result.Receivables.Lines))
+
+	globex := result.Receivables.Lines[0]
+	assert.Equal(t, int64(20), globex.CounterpartyId)
+	assert.Equal(t, "Globex", globex.CounterpartyName)
+	assert.Equal(t, 1, globex.ObligationCount)
+	assert.Equal(t, int64(70000), globex.Buckets.Days31To60)
+	assert.Equal(t, int64(70000), globex.Buckets.Total)
+
+	acme := result.Receivables.Lines[1]
+	assert.Equal(t, int64(10), acme.CounterpartyId)
+	assert.Equal(t, "Acme", acme.CounterpartyName)
+	assert.Equal(t, 3, acme.ObligationCount)
+	assert.Equal(t, int64(10000), acme.Buckets.Current)
+	assert.Equal(t, int64(15000), acme.Buckets.Days1To30)
+	assert.Equal(t, int64(30000), acme.Buckets.Over90Days)
+	assert.Equal(t, int64(55000), acme.Buckets.Total)
+
+	assert.Equal(t, int64(10000), result.Receivables.Totals.Current)
+	assert.Equal(t, int64(15000), result.Receivables.Totals.Days1To30)
+	assert.Equal(t, int64(70000), result.Receivables.Totals.Days31To60)
+	assert.Equal(t, int64(0), result.Receivables.Totals.Days61To90)
+	assert.Equal(t, int64(30000), result.Receivables.Totals.Over90Days)
+	assert.Equal(t, int64(125000), result.Receivables.Totals.Total)
+
+	assert.Equal(t, 1, len(result.Payables.Lines))
+	assert.Equal(t, int64(8000), result.Payables.Lines[0].Buckets.Days61To90)
+	assert.Equal(t, int64(8000), result.Payables.Totals.Total)
+
+	// Without CFO filter the obligation of another CFO is included
+	result, err = svc.GetAging(nil, uid, 0, asOf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(126000), result.Receivables.Totals.Total)
+}
+
+// TestReportService_GetAging_MultiCurrency verifies that open amounts are converted into the reporting currency
+func TestReportService_GetAging_MultiCurrency(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	asOf := int64(1704067200000)
+
+	obligations := []*models.Obligation{
+		{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 10000, Currency: "USD", Status: models.OBLIGATION_STATUS_ACTIVE},
+		{ObligationId: 2, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 50000, Currency: "RUB", Status: models.OBLIGATION_STATUS_ACTIVE},
+	}
+	for _, o := range obligations {
+		_, err := tdb.engine.Insert(o)
+		assert.Nil(t, err)
+	}
+
+	converter := NewReportCurrencyConverter("RUB", "RUB", &models.LatestExchangeRateResponse{
+		BaseCurrency: "RUB",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "0.0125"},
+		},
+	})
+
+	result, err := svc.GetAging(nil, uid, 0, asOf, converter)
+	assert.Nil(t, err)
+	assert.Equal(t, "RUB", result.Currency)
+	assert.Equal(t, 1, len(result.Receivables.Lines))
+	assert.Equal(t, int64(850000), result.Receivables.Totals.Current)
+}
+
+// TestReportService_GetAging_AsOf verifies that the aging report as of a past date excludes obligations created later
+// and counts the obligations paid later as open.
+func TestReportService_GetAging_AsOf(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	asOf := int64(1704067200000) // 2024-01-01 00:00:00 UTC
+	dayMs := int64(agingDayMillis)
+
+	obligations := []*models.Obligation{
+		// paid by a payment after the as-of date
+		{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 20000, PaidAmount: 20000, Currency: "RUB", DueDate: asOf - 10*dayMs, Status: models.OBLIGATION_STATUS_PAID, CreatedUnixTime: (asOf - 30*dayMs) / 1000},
+		// paid manually after the as-of date
+		{ObligationId: 2, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 30000, PaidAmount: 30000, Currency: "RUB", DueDate: asOf - 40*dayMs, Status: models.OBLIGATION_STATUS_PAID, CreatedUnixTime: (asOf - 60*dayMs) / 1000},
+		// paid by a payment before the as-of date
+		{ObligationId: 3, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 5000, PaidAmount: 5000, Currency: "RUB", DueDate: asOf + 10*dayMs, Status: models.OBLIGATION_STATUS_PAID, CreatedUnixTime: (asOf - 30*dayMs) / 1000},
+		// created after the as-of date
+		{ObligationId: 4, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 10, Amount: 70000, Currency: "RUB", DueDate: asOf - 5*dayMs, Status: models.OBLIGATION_STATUS_OVERDUE, CreatedUnixTime: (asOf + dayMs) / 1000},
+	}
+	for _, o := range obligations {
+		_, err := tdb.engine.Insert(o)
+		assert.Nil(t, err)
+	}
+
+	payments := []*models.ObligationPayment{
+		{PaymentId: 1, Uid: uid, ObligationId: 1, TransactionId: 1, PaymentDate: asOf - dayMs, Amount: 5000},
+		{PaymentId: 2, Uid: uid, ObligationId: 1, TransactionId: 2, PaymentDate: asOf + dayMs, Amount: 15000},
+		{PaymentId: 3, Uid: uid, ObligationId: 3, TransactionId: 1, PaymentDate: asOf - dayMs, Amount: 5000},
+	}
+	for _, payment := range payments {
+		_, err := tdb.engine.Insert(payment)
+		assert.Nil(t, err)
+	}
+
+	_, err := tdb.engine.Insert(&models.StatusHistory{Uid: uid, EntityType: models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, EntityId: 2, Sequence: 1, OldStatus: byte(models.OBLIGATION_STATUS_ACTIVE), NewStatus: byte(models.OBLIGATION_STATUS_PAID), DueDate: asOf - 40*dayMs, PaidAmount: 30000, ChangedUnixTime: (asOf + dayMs) / 1000})
+	assert.Nil(t, err)
+
+	result, err := svc.GetAging(nil, uid, 0, asOf, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(result.Receivables.Lines))
+	line := result.Receivables.Lines[0]
+	assert.Equal(t, 2, line.ObligationCount)
+	assert.Equal(t, int64(15000), line.Buckets.Days1To30)
+	assert.Equal(t, int64(30000), line.Buckets.Days31To60)
+	assert.Equal(t, int64(45000), line.Buckets.Total)
+	assert.Equal(t, 1, line.PaidOnTimeCount)
+	assert.Equal(t, 0, line.PaidLateCount)
+
+	// without as-of date the current paid amounts are used
+	result, err = svc.GetAging(nil, uid, 0, 0, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(result.Receivables.Lines))
+	assert.Equal(t, int64(70000), result.Receivables.Totals.Total)
+	assert.Equal(t, 3, result.Receivables.Lines[0].PaidOnTimeCount+result.Receivables.Lines[0].PaidLateCount)
+}
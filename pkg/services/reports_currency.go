@@ -0,0 +1,340 @@
+// reports_currency.go provides conversion of multi-currency report amounts into a single reporting currency.
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ReportCurrencyConverter converts amounts in different currencies into the reporting currency.
+// Exchange rates are expressed as units of currency per one unit of base currency,
+// the same way as the exchange rates data providers return them.
+// A nil converter keeps all amounts unchanged.
+type ReportCurrencyConverter struct {
+	reportingCurrency      string
+	defaultCurrency        string
+	baseCurrency           string
+	rates                  map[string]float64
+	historicalRatesLoader  func(rateDate string) *models.LatestExchangeRateResponse
+	historicalRates        map[string]*ReportCurrencyConverter
+	missingCurrencies      []string
+	missingHistoricalRates []string
+}
+
+// NewReportCurrencyConverter returns a converter for the specified reporting currency based on the latest exchange rates.
+// Amounts without currency are regarded as amounts in the default currency of user.
+func NewReportCurrencyConverter(reportingCurrency string, defaultCurrency string, exchangeRates *models.LatestExchangeRateResponse) *ReportCurrencyConverter {
+	converter := &ReportCurrencyConverter{
+		reportingCurrency: reportingCurrency,
+		defaultCurrency:   defaultCurrency,
+		rates:             make(map[string]float64),
+	}
+
+	if exchangeRates == nil {
+		return converter
+	}
+
+	converter.baseCurrency = exchangeRates.BaseCurrency
+
+	for i := 0; i < len(exchangeRates.ExchangeRates); i++ {
+		exchangeRate := exchangeRates.ExchangeRates[i]
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		converter.rates[exchangeRate.Currency] = rate
+	}
+
+	return converter
+}
+
+// SetHistoricalExchangeRatesLoader sets the function which returns the exchange rates in effect on the specified date,
+// the amounts converted by ConvertAt use the exchange rates returned by it instead of the exchange rates of this converter
+func (r *ReportCurrencyConverter) SetHistoricalExchangeRatesLoader(loader func(rateDate string) *models.LatestExchangeRateResponse) {
+	if r == nil {
+		return
+	}
+
+	r.historicalRatesLoader = loader
+	r.historicalRates = make(map[string]*ReportCurrencyConverter)
+}
+
+// ReportingCurrency returns the reporting currency, or empty string if the converter is nil
+func (r *ReportCurrencyConverter) ReportingCurrency() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.reportingCurrency
+}
+
+// Convert returns the amount in reporting currency, the exchange rate used, and whether the exchange rate exists
+func (r *ReportCurrencyConverter) Convert(amount int64, currency string) (int64, float64, bool) {
+	if r == nil {
+		return amount, 1, true
+	}
+
+	currency = r.normalizeCurrency(currency)
+
+	if currency == r.reportingCurrency {
+		return amount, 1, true
+	}
+
+	fromRate, fromExists := r.getRateToBaseCurrency(currency)
+	toRate, toExists := r.getRateToBaseCurrency(r.reportingCurrency)
+
+	if !fromExists || !toExists {
+		r.addMissingCurrency(currency)
+		return 0, 0, false
+	}
+
+	rate := toRate / fromRate
+
+	return int64(math.Round(float64(amount) * rate)), rate, true
+}
+
+// ConvertAt returns the amount in reporting currency converted by the exchange rates in effect at the specified transaction time (unix time in milliseconds),
+// the exchange rate used, and whether the exchange rate exists. The exchange rates of this converter are used
+// if there is no historical exchange rates loader or no exchange rates of the currency in effect at that time,
+// and the latter is reported in warnings
+func (r *ReportCurrencyConverter) ConvertAt(amount int64, currency string, transactionTime int64) (int64, float64, bool) {
+	if r == nil || r.historicalRatesLoader == nil || transactionTime <= 0 {
+		return r.Convert(amount, currency)
+	}
+
+	rateDate := models.GetExchangeRateHistoryDate(transactionTime / 1000)
+	historicalConverter, exists := r.historicalRates[rateDate]
+
+	if !exists {
+		exchangeRates := r.historicalRatesLoader(rateDate)
+
+		if exchangeRates != nil {
+			historicalConverter = NewReportCurrencyConverter(r.reportingCurrency, r.defaultCurrency, exchangeRates)
+		}
+
+		r.historicalRates[rateDate] = historicalConverter
+	}
+
+	normalizedCurrency := r.normalizeCurrency(currency)
+
+	if normalizedCurrency == r.reportingCurrency {
+		return r.Convert(amount, currency)
+	}
+
+	if historicalConverter != nil {
+		_, fromExists := historicalConverter.getRateToBaseCurrency(normalizedCurrency)
+		_, toExists := historicalConverter.getRateToBaseCurrency(r.reportingCurrency)
+
+		if fromExists && toExists {
+			return historicalConverter.Convert(amount, currency)
+		}
+	}
+
+	r.addMissingHistoricalRate(normalizedCurrency, rateDate)
+
+	return r.Convert(amount, currency)
+}
+
+// Warnings returns the warnings about all currencies which cannot be converted into the reporting currency,
+// and about all historical exchange rates which are missing and replaced by the latest exchange rates
+func (r *ReportCurrencyConverter) Warnings() []string {
+	if r == nil || len(r.missingCurrencies)+len(r.missingHistoricalRates) < 1 {
+		return nil
+	}
+
+	warnings := make([]string, 0, len(r.missingCurrencies)+len(r.missingHistoricalRates))
+
+	for i := 0; i < len(r.missingCurrencies); i++ {
+		warnings = append(warnings, fmt.Sprintf("Exchange rate from %s to %s is missing, amounts in %s are excluded from totals", r.missingCurrencies[i], r.reportingCurrency, r.missingCurrencies[i]))
+	}
+
+	warnings = append(warnings, r.missingHistoricalRates...)
+
+	return warnings
+}
+
+func (r *ReportCurrencyConverter) normalizeCurrency(currency string) string {
+	if currency == "" {
+		return r.defaultCurrency
+	}
+
+	return currency
+}
+
+func (r *ReportCurrencyConverter) getRateToBaseCurrency(currency string) (float64, bool) {
+	if currency == r.baseCurrency && currency != "" {
+		return 1, true
+	}
+
+	rate, exists := r.rates[currency]
+
+	return rate, exists
+}
+
+func (r *ReportCurrencyConverter) addMissingCurrency(currency string) {
+	for i := 0; i < len(r.missingCurrencies); i++ {
+		if r.missingCurrencies[i] == currency {
+			return
+		}
+	}
+
+	r.missingCurrencies = append(r.missingCurrencies, currency)
+}
+
+func (r *ReportCurrencyConverter) addMissingHistoricalRate(currency string, rateDate string) {
+	warning := fmt.Sprintf("Historical exchange rate from %s to %s on %s is missing, the latest exchange rate is used", currency, r.reportingCurrency, rateDate)
+
+	for i := 0; i < len(r.missingHistoricalRates); i++ {
+		if r.missingHistoricalRates[i] == warning {
+			return
+		}
+	}
+
+	r.missingHistoricalRates = append(r.missingHistoricalRates, warning)
+}
+
+// reportCurrencyBreakdown accumulates the original and converted amounts of a report line per currency
+type reportCurrencyBreakdown struct {
+	converter  *ReportCurrencyConverter
+	items      map[string]*models.ReportCurrencyAmount
+	mixedRates map[string]bool
+	total      int64
+}
+
+// newReportCurrencyBreakdown returns a new breakdown which converts amounts by the specified converter
+func newReportCurrencyBreakdown(converter *ReportCurrencyConverter) *reportCurrencyBreakdown {
+	return &reportCurrencyBreakdown{
+		converter:  converter,
+		items:      make(map[string]*models.ReportCurrencyAmount),
+		mixedRates: make(map[string]bool),
+	}
+}
+
+// add converts the amount into reporting currency, records it and returns the converted amount
+func (b *reportCurrencyBreakdown) add(amount int64, currency string) int64 {
+	convertedAmount, rate, ok := b.converter.Convert(amount, currency)
+
+	return b.record(amount, currency, convertedAmount, rate, ok)
+}
+
+// addAt converts the amount into reporting currency by the exchange rates in effect at the specified transaction time,
+// records it and returns the converted amount
+func (b *reportCurrencyBreakdown) addAt(amount int64, currency string, transactionTime int64) int64 {
+	convertedAmount, rate, ok := b.converter.ConvertAt(amount, currency, transactionTime)
+
+	return b.record(amount, currency, convertedAmount, rate, ok)
+}
+
+// record records the original and converted amount, and returns the converted amount
+func (b *reportCurrencyBreakdown) record(amount int64, currency string, convertedAmount int64, rate float64, ok bool) int64 {
+	if b.converter != nil {
+		currency = b.converter.normalizeCurrency(currency)
+	}
+
+	item, exists := b.items[currency]
+
+	if !exists {
+		item = &models.ReportCurrencyAmount{
+			Currency:    currency,
+			RateMissing: !ok,
+		}
+
+		if ok && b.converter != nil {
+			item.Rate = utils.Float64ToString(rate)
+		}
+
+		b.items[currency] = item
+	} else if b.converter != nil {
+		if ok && item.Rate != "" && item.Rate != utils.Float64ToString(rate) {
+			b.mixedRates[currency] = true
+		} else if ok && item.Rate == "" {
+			item.Rate = utils.Float64ToString(rate)
+		}
+
+		item.RateMissing = item.RateMissing || !ok
+	}
+
+	item.OriginalAmount += amount
+	item.Amount += convertedAmount
+	b.total += convertedAmount
+
+	return convertedAmount
+}
+
+// merge adds all amounts of another breakdown into this breakdown
+func (b *reportCurrencyBreakdown) merge(other *reportCurrencyBreakdown, sign int64) {
+	for _, item := range other.items {
+		existingItem, exists := b.items[item.Currency]
+
+		if !exists {
+			existingItem = &models.ReportCurrencyAmount{
+				Currency:    item.Currency,
+				Rate:        item.Rate,
+				RateMissing: item.RateMissing,
+			}
+
+			b.items[item.Currency] = existingItem
+		} else if existingItem.Rate != item.Rate && item.Rate != "" {
+			if existingItem.Rate == "" {
+				existingItem.Rate = item.Rate
+			} else {
+				b.mixedRates[item.Currency] = true
+			}
+		}
+
+		if other.mixedRates[item.Currency] {
+			b.mixedRates[item.Currency] = true
+		}
+
+		existingItem.RateMissing = existingItem.RateMissing || item.RateMissing
+		existingItem.OriginalAmount += sign * item.OriginalAmount
+		existingItem.Amount += sign * item.Amount
+	}
+
+	b.total += sign * other.total
+}
+
+// hasNonZeroAmount returns whether the breakdown contains any non-zero amount in original currencies
+func (b *reportCurrencyBreakdown) hasNonZeroAmount() bool {
+	if b.converter == nil {
+		return b.total != 0
+	}
+
+	for _, item := range b.items {
+		if item.OriginalAmount != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toList returns the per-currency amounts ordered by currency, or nil if there is no conversion.
+// The rate of the currency converted by different exchange rates is the average rate weighted by amount
+func (b *reportCurrencyBreakdown) toList() []*models.ReportCurrencyAmount {
+	if b.converter == nil || len(b.items) < 1 {
+		return nil
+	}
+
+	items := make([]*models.ReportCurrencyAmount, 0, len(b.items))
+
+	for currency, item := range b.items {
+		if b.mixedRates[currency] && item.OriginalAmount != 0 {
+			item.Rate = utils.Float64ToString(math.Round(float64(item.Amount)/float64(item.OriginalAmount)*1e6) / 1e6)
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Currency < items[j].Currency
+	})
+
+	return items
+}
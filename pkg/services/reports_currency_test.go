@@ -0,0 +1,281 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// newTestReportCurrencyConverter returns a converter with RUB as base currency, 1 RUB = 0.0125 USD = 0.01 EUR
+func newTestReportCurrencyConverter(reportingCurrency string) *ReportCurrencyConverter {
+	return NewReportCurrencyConverter(reportingCurrency, "RUB", &models.LatestExchangeRateResponse{
+		BaseCurrency: "RUB",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "0.0125"},
+			{Currency: "EUR", Rate: "0.01"},
+		},
+	})
+}
+
+func TestReportCurrencyConverter_NilConverterKeepsAmount(t *testing.T) {
+	var converter *ReportCurrencyConverter
+
+	amount, rate, ok := converter.Convert(12345, "USD")
+	assert.Equal(t, int64(12345), amount)
+	assert.Equal(t, float64(1), rate)
+	assert.True(t, ok)
+	assert.Equal(t, "", converter.ReportingCurrency())
+	assert.Nil(t, converter.Warnings())
+}
+
+func TestReportCurrencyConverter_ConvertToBaseCurrency(t *testing.T) {
+	converter := newTestReportCurrencyConverter("RUB")
+
+	amount, rate, ok := converter.Convert(10000, "USD")
+	assert.True(t, ok)
+	assert.Equal(t, float64(80), rate)
+	assert.Equal(t, int64(800000), amount)
+
+	amount, rate, ok = converter.Convert(10000, "RUB")
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), rate)
+	assert.Equal(t, int64(10000), amount)
+}
+
+func TestReportCurrencyConverter_ConvertBetweenNonBaseCurrencies(t *testing.T) {
+	converter := newTestReportCurrencyConverter("EUR")
+
+	amount, rate, ok := converter.Convert(10000, "USD")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.8, rate, 0.0000001)
+	assert.Equal(t, int64(8000), amount)
+}
+
+func TestReportCurrencyConverter_EmptyCurrencyUsesDefaultCurrency(t *testing.T) {
+	converter := newTestReportCurrencyConverter("USD")
+
+	amount, _, ok := converter.Convert(800000, "")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10000), amount)
+}
+
+func TestReportCurrencyConverter_MissingRateReturnsWarning(t *testing.T) {
+	converter := newTestReportCurrencyConverter("RUB")
+
+	amount, _, ok := converter.Convert(10000, "GBP")
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), amount)
+
+	_, _, ok = converter.Convert(500, "GBP")
+	assert.False(t, ok)
+
+	warnings := converter.Warnings()
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "GBP")
+}
+
+func TestReportCurrencyConverter_ConvertAtUsesHistoricalRates(t *testing.T) {
+	converter := newTestReportCurrencyConverter("RUB")
+	loadedDates := make(map[string]int)
+
+	converter.SetHistoricalExchangeRatesLoader(func(rateDate string) *models.LatestExchangeRateResponse {
+		loadedDates[rateDate]++
+
+		if rateDate != "2024-01-15" {
+			return nil
+		}
+
+		return &models.LatestExchangeRateResponse{
+			BaseCurrency: "RUB",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "0.01"},
+			},
+		}
+	})
+
+	// 2024-01-15 12:00:00 UTC, 1 USD = 100 RUB
+	amount, rate, ok := converter.ConvertAt(10000, "USD", 1705320000000)
+	assert.True(t, ok)
+	assert.Equal(t, float64(100), rate)
+	assert.Equal(t, int64(1000000), amount)
+
+	_, _, _ = converter.ConvertAt(10000, "USD", 1705320001000)
+	assert.Equal(t, 1, loadedDates["2024-01-15"])
+
+	// No exchange rates history on 2024-01-16, the exchange rates of converter are used
+	amount, rate, ok = converter.ConvertAt(10000, "USD", 1705406400000)
+	assert.True(t, ok)
+	assert.Equal(t, float64(80), rate)
+	assert.Equal(t, int64(800000), amount)
+
+	// EUR does not exist in exchange rates history, the exchange rates of converter are used
+	amount, _, ok = converter.ConvertAt(10000, "EUR", 1705320000000)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1000000), amount)
+
+	// Amounts in reporting currency never need the exchange rates history
+	_, _, _ = converter.ConvertAt(10000, "RUB", 1705406400000)
+
+	warnings := converter.Warnings()
+	assert.Equal(t, 2, len(warnings))
+	assert.Equal(t, "Historical exchange rate from USD to RUB on 2024-01-16 is missing, the latest exchange rate is used", warnings[0])
+	assert.Equal(t, "Historical exchange rate from EUR to RUB on 2024-01-15 is missing, the latest exchange rate is used", warnings[1])
+}
+
+func TestReportCurrencyBreakdown_AverageRateOfMixedRates(t *testing.T) {
+	converter := newTestReportCurrencyConverter("RUB")
+	converter.SetHistoricalExchangeRatesLoader(func(rateDate string) *models.LatestExchangeRateResponse {
+		if rateDate != "2024-01-15" {
+			return nil
+		}
+
+		return &models.LatestExchangeRateResponse{
+			BaseCurrency: "RUB",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "0.01"},
+			},
+		}
+	})
+
+	breakdown := newReportCurrencyBreakdown(converter)
+	breakdown.addAt(10000, "USD", 1705320000000)
+	breakdown.addAt(10000, "USD", 1705406400000)
+
+	items := breakdown.toList()
+	assert.Equal(t, int64(1800000), breakdown.total)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, int64(20000), items[0].OriginalAmount)
+	assert.Equal(t, "90", items[0].Rate)
+}
+
+func TestReportService_GetBalance_ConvertsMultiCurrencyAccounts(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+
+	accounts := []*models.Account{
+		{AccountId: 1, Uid: uid, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "RUB Cash", Balance: 1000000, Currency: "RUB"},
+		{AccountId: 2, Uid: uid, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "USD Bank", Balance: 10000, Currency: "USD"},
+		{AccountId: 3, Uid: uid, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "GBP Cash", Balance: 5000, Currency: "GBP"},
+		{AccountId: 4, Uid: uid, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "USD Card", Balance: 2000, Currency: "USD"},
+	}
+
+	for _, account := range accounts {
+		_, err := tdb.engine.Insert(account)
+		assert.Nil(t, err)
+	}
+
+	result, err := svc.GetBalance(nil, uid, 0, 0, newTestReportCurrencyConverter("RUB"))
+	assert.Nil(t, err)
+
+	// Assets: 1000000 RUB + 10000 USD * 80 = 1800000 RUB, GBP is excluded because of missing rate
+	assert.Equal(t, "RUB", result.Currency)
+	assert.Equal(t, int64(1800000), result.TotalAssets)
+	assert.Equal(t, int64(160000), result.TotalLiability)
+	assert.Equal(t, int64(1640000), result.Equity)
+
+	assert.Equal(t, 1, len(result.AssetLines))
+	assert.Equal(t, 3, len(result.AssetLines[0].Currencies))
+	assert.Equal(t, "GBP", result.AssetLines[0].Currencies[0].Currency)
+	assert.True(t, result.AssetLines[0].Currencies[0].RateMissing)
+	assert.Equal(t, "RUB", result.AssetLines[0].Currencies[1].Currency)
+	assert.Equal(t, "1", result.AssetLines[0].Currencies[1].Rate)
+	assert.Equal(t, "USD", result.AssetLines[0].Currencies[2].Currency)
+	assert.Equal(t, "80", result.AssetLines[0].Currencies[2].Rate)
+	assert.Equal(t, int64(10000), result.AssetLines[0].Currencies[2].OriginalAmount)
+	assert.Equal(t, int64(800000), result.AssetLines[0].Currencies[2].Amount)
+
+	// Equity per currency: USD = 10000 - 2000
+	assert.Equal(t, 3, len(result.CurrencySubtotals))
+	assert.Equal(t, "USD", result.CurrencySubtotals[2].Currency)
+	assert.Equal(t, int64(8000), result.CurrencySubtotals[2].OriginalAmount)
+	assert.Equal(t, int64(640000), result.CurrencySubtotals[2].Amount)
+
+	assert.Equal(t, 1, len(result.Warnings))
+	assert.Contains(t, result.Warnings[0], "GBP")
+}
+
+func TestReportService_GetBalanceAndPnL_ConvertAssetsInBookAccountCurrency(t *testing.T) {
+	baseTime := int64(1700000000)
+
+	svc, tdb := newTestReportServiceWithDB(t, func(s *ReportService) {
+		s.assets = &mockAssetProvider{assets: []*models.Asset{
+			{AssetId: 1, Uid: 1, Status: models.ASSET_STATUS_ACTIVE, PurchaseDate: baseTime, PurchaseCost: 10000, AccountId: 5},
+			{AssetId: 2, Uid: 1, Status: models.ASSET_STATUS_ACTIVE, PurchaseDate: baseTime, PurchaseCost: 50000},
+			{AssetId: 3, Uid: 1, Status: models.ASSET_STATUS_SOLD, PurchaseDate: baseTime, PurchaseCost: 1000, AccountId: 5, DecommissionDate: baseTime + 100, DisposalProceeds: 3000, DisposalBookValue: 1000},
+		}}
+	})
+	defer tdb.close()
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 5, Uid: 1, Category: models.ACCOUNT_CATEGORY_INVESTMENT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "USD Fixed Assets", Currency: "USD"})
+	assert.Nil(t, err)
+
+	// Residual values: 10000 USD * 80 in the book account currency, and 50000 RUB in the default currency for the asset without book account
+	balance, err := svc.GetBalance(nil, 1, 0, 0, newTestReportCurrencyConverter("RUB"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(850000), balance.TotalAssets)
+	assert.Equal(t, 1, len(balance.AssetLines))
+	assert.Equal(t, models.BalanceLabelFixedAssets, balance.AssetLines[0].Label)
+	assert.Equal(t, 2, len(balance.AssetLines[0].Currencies))
+	assert.Equal(t, "USD", balance.AssetLines[0].Currencies[1].Currency)
+	assert.Equal(t, int64(10000), balance.AssetLines[0].Currencies[1].OriginalAmount)
+
+	// Disposal gain: (3000 - 1000) USD * 80
+	pnl, err := svc.GetPnL(nil, 1, 0, baseTime, baseTime+1000, newTestReportCurrencyConverter("RUB"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(160000), pnl.DisposalGainLoss)
+}
+
+func TestReportService_GetCashFlow_ConvertsMultiCurrencyTransactions(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	baseTime := int64(1700000000000)
+
+	_, err := tdb.engine.Insert(&models.TransactionCategory{CategoryId: 100, Uid: uid, Type: models.CATEGORY_TYPE_INCOME, Name: "Sales", ActivityType: int32(models.ACTIVITY_TYPE_OPERATING)})
+	assert.Nil(t, err)
+
+	accounts := []*models.Account{
+		{AccountId: 1, Uid: uid, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "RUB Cash", Currency: "RUB"},
+		{AccountId: 2, Uid: uid, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "USD Cash", Currency: "USD"},
+	}
+
+	for _, account := range accounts {
+		_, err = tdb.engine.Insert(account)
+		assert.Nil(t, err)
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 100, AccountId: 1, TransactionTime: baseTime + 1, Amount: 100000},
+		{TransactionId: 2, Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 100, AccountId: 2, TransactionTime: baseTime + 2, Amount: 1000},
+		{TransactionId: 3, Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 100, AccountId: 2, TransactionTime: baseTime + 3, Amount: 500},
+	}
+
+	for _, transaction := range transactions {
+		_, err = tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	result, err := svc.GetCashFlow(nil, uid, 0, baseTime, baseTime+10000, newTestReportCurrencyConverter("RUB"))
+	assert.Nil(t, err)
+
+	// Income: 100000 RUB + 1000 USD * 80 = 180000 RUB, Expense: 500 USD * 80 = 40000 RUB
+	operating := result.Activities[0]
+	assert.Equal(t, int64(180000), operating.TotalIncome)
+	assert.Equal(t, int64(40000), operating.TotalExpense)
+	assert.Equal(t, int64(140000), result.TotalNet)
+
+	assert.Equal(t, 1, len(operating.Lines))
+	assert.Equal(t, 2, len(operating.Lines[0].Currencies))
+	assert.Equal(t, "USD", operating.Lines[0].Currencies[1].Currency)
+	assert.Equal(t, int64(500), operating.Lines[0].Currencies[1].OriginalAmount)
+	assert.Equal(t, int64(40000), operating.Lines[0].Currencies[1].Amount)
+
+	assert.Equal(t, "RUB", result.Currency)
+	assert.Equal(t, 2, len(result.CurrencySubtotals))
+	assert.Nil(t, result.Warnings)
+}
@@ -255,7 +255,7 @@ func TestReportService_GetCashFlow_WithDB(t *testing.T) {
 	startTime := baseTime
 	endTime := baseTime + 10000
 
-	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -308,7 +308,7 @@ func TestReportService_GetPnL_WithDB(t *testing.T) {
 	startTime := baseTime
 	endTime := baseTime + 10000
 
-	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -419,7 +419,7 @@ func TestReportService_GetBalance_WithDB(t *testing.T) {
 		}
 	}
 
-	result, err := svc.GetBalance(nil, uid, 0)
+	result, err := svc.GetBalance(nil, uid, 0, 0, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -478,7 +478,7 @@ func TestReportService_GetPnL_EmptyData(t *testing.T) {
 	startTime := int64(1700000000)
 	endTime := startTime + 10000
 
-	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -582,7 +582,7 @@ func TestReportService_GetCashFlow_WithCfoFilter(t *testing.T) {
 	endTime := baseTime + 10000
 
 	// With CFO filter: only transactions with cfo_id=42
-	result, err := svc.GetCashFlow(nil, uid, cfoId, startTime, endTime)
+	result, err := svc.GetCashFlow(nil, uid, cfoId, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -593,7 +593,7 @@ func TestReportService_GetCashFlow_WithCfoFilter(t *testing.T) {
 	assert.Equal(t, int64(70000), operating.TotalNet)
 
 	// Without CFO filter: all transactions
-	resultAll, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime)
+	resultAll, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	operatingAll := resultAll.Activities[0]
 	// Income: 100000 + 200000 = 300000, Expense: 30000
@@ -615,7 +615,7 @@ func TestReportService_GetCashFlow_ExcludesDeletedAndPlanned(t *testing.T) {
 	startTime := baseTime
 	endTime := baseTime + 10000
 
-	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 
 	// Total does NOT include deleted (999999) or planned (888888) amounts
@@ -639,7 +639,7 @@ func TestReportService_GetCashFlow_TimeRange(t *testing.T) {
 	startTime := baseTime + 50
 	endTime := baseTime + 120
 
-	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetCashFlow(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 
 	// Only the first Sales Revenue income (500000) falls in this window
@@ -677,7 +677,7 @@ func TestReportService_GetPnL_WithDepreciation(t *testing.T) {
 	startTime := commDate.Unix()
 	endTime := now.Unix() + 1
 
-	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -721,7 +721,7 @@ func TestReportService_GetPnL_WithTaxRecords(t *testing.T) {
 
 	seedTransactionData(t, tdb, uid, baseTime)
 
-	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime)
+	result, err := svc.GetPnL(nil, uid, 0, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 
@@ -739,7 +739,7 @@ func TestReportService_GetCashFlow_InvalidUid_WithDB(t *testing.T) {
 	svc, tdb := newTestReportServiceWithDB(t)
 	defer tdb.close()
 
-	result, err := svc.GetCashFlow(nil, 0, 0, 1000, 2000)
+	result, err := svc.GetCashFlow(nil, 0, 0, 1000, 2000, nil)
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 }
@@ -751,11 +751,11 @@ func TestReportService_GetCashFlow_InvalidTimeRange_WithDB(t *testing.T) {
 	defer tdb.close()
 
 	// startTime >= endTime
-	_, err := svc.GetCashFlow(nil, 1, 0, 2000, 1000)
+	_, err := svc.GetCashFlow(nil, 1, 0, 2000, 1000, nil)
 	assert.NotNil(t, err)
 
 	// Range too long (> 10 years)
-	_, err = svc.GetCashFlow(nil, 1, 0, 1000, 1000+maxReportRangeSeconds+1)
+	_, err = svc.GetCashFlow(nil, 1, 0, 1000, 1000+maxReportRangeMillis+1, nil)
 	assert.NotNil(t, err)
 }
 
@@ -765,7 +765,7 @@ func TestReportService_GetBalance_EmptyData(t *testing.T) {
 	svc, tdb := newTestReportServiceWithDB(t)
 	defer tdb.close()
 
-	result, err := svc.GetBalance(nil, 1, 0)
+	result, err := svc.GetBalance(nil, 1, 0, 0, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, int64(0), result.TotalAssets)
@@ -832,7 +832,7 @@ func TestReportService_GetPaymentCalendar_WithDB(t *testing.T) {
 	startTime := baseTime
 	endTime := baseTime + 10000
 
-	result, err := svc.GetPaymentCalendar(nil, uid, startTime, endTime)
+	result, err := svc.GetPaymentCalendar(nil, uid, startTime, endTime, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, 3, len(result.Items))
@@ -850,3 +850,221 @@ func TestReportService_GetPaymentCalendar_WithDB(t *testing.T) {
 	assert.Equal(t, models.PaymentTypePlanned, result.Items[2].Type)
 	assert.Equal(t, int64(20000), result.Items[2].Amount)
 }
+
+// TestReportService_GetBalance_AsOfHistoricalDate verifies that the balance sheet as of a past date
+// rebuilds account balances from the ledger and excludes later obligations, assets and payments.
+func TestReportService_GetBalance_AsOfHistoricalDate(t *testing.T) {
+	asOf := int64(1704067199000) // 2023-12-31 23:59:59 UTC
+	dayMs := int64(24 * 60 * 60 * 1000)
+
+	svc, tdb := newTestReportServiceWithDB(t, func(s *ReportService) {
+		s.assets = &mockAssetProvider{assets: []*models.Asset{
+			{AssetId: 1, Uid: 1, PurchaseDate: (asOf - 30*dayMs) / 1000, PurchaseCost: 120000},
+			{AssetId: 2, Uid: 1, PurchaseDate: (asOf + dayMs) / 1000, PurchaseCost: 50000},
+		}}
+		s.deals = &mockInvestorDealProvider{deals: []*models.InvestorDeal{
+			{DealId: 1, Uid: 1, InvestmentDate: asOf - 60*dayMs, TotalToRepay: 100000, Currency: "RUB"},
+			{DealId: 2, Uid: 1, InvestmentDate: asOf + dayMs, TotalToRepay: 70000, Currency: "RUB"},
+		}}
+		s.payments = &mockInvestorPaymentProvider{paymentsByDeal: map[int64][]*models.InvestorPayment{
+			1: {
+				{PaymentId: 1, DealId: 1, PaymentDate: asOf - dayMs, Amount: 30000},
+				{PaymentId: 2, DealId: 1, PaymentDate: asOf + dayMs, Amount: 20000},
+			},
+		}}
+	})
+	defer tdb.close()
+
+	uid := int64(1)
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 1, Uid: uid, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Checking", Balance: 90000, Currency: "RUB"})
+	assert.Nil(t, err)
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: uid, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, TransactionTime: asOf - 10*dayMs, RelatedAccountAmount: 100000},
+		{TransactionId: 2, Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: asOf - dayMs, Amount: 20000},
+		{TransactionId: 3, Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: asOf + dayMs, Amount: 10000},
+		{TransactionId: 4, Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: asOf - dayMs + 1, Amount: 5000, Planned: true},
+	}
+	for _, transaction := range transactions {
+		_, err = tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	obligations := []*models.Obligation{
+		// paid partially after the as-of date, so it is fully outstanding as of that date
+		{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, Amount: 40000, PaidAmount: 15000, Currency: "RUB", CreatedUnixTime: (asOf - 5*dayMs) / 1000, UpdatedUnixTime: (asOf + dayMs) / 1000},
+		// created after the as-of date
+		{ObligationId: 2, Uid: uid, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 25000, Currency: "RUB", CreatedUnixTime: (asOf + dayMs) / 1000, UpdatedUnixTime: (asOf + dayMs) / 1000},
+	}
+	for _, o := range obligations {
+		_, err = tdb.engine.Insert(o)
+		assert.Nil(t, err)
+	}
+
+	_, err = tdb.engine.Insert(&models.StatusHistory{Uid: uid, EntityType: models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, EntityId: 1, Sequence: 1, OldStatus: byte(models.OBLIGATION_STATUS_ACTIVE), NewStatus: byte(models.OBLIGATION_STATUS_PARTIAL), PaidAmount: 15000, ChangedUnixTime: (asOf + dayMs) / 1000})
+	assert.Nil(t, err)
+
+	result, err := svc.GetBalance(nil, uid, 0, asOf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, asOf, result.AsOf)
+
+	// Cash = 100000 - 20000 = 80000, Receivables = 40000, Fixed assets = 120000
+	assert.Equal(t, 3, len(result.AssetLines))
+	assert.Equal(t, models.BalanceLabelCashAndBank, result.AssetLines[0].Label)
+	assert.Equal(t, int64(80000), result.AssetLines[0].Amount)
+	assert.Equal(t, models.BalanceLabelReceivables, result.AssetLines[1].Label)
+	assert.Equal(t, int64(40000), result.AssetLines[1].Amount)
+	assert.Equal(t, models.BalanceLabelFixedAssets, result.AssetLines[2].Label)
+	assert.Equal(t, int64(120000), result.AssetLines[2].Amount)
+	assert.Equal(t, int64(240000), result.TotalAssets)
+
+	// Investor debt = 100000 - 30000 = 70000
+	assert.Equal(t, 1, len(result.LiabilityLines))
+	assert.Equal(t, models.BalanceLabelInvestorDebt, result.LiabilityLines[0].Label)
+	assert.Equal(t, int64(70000), result.LiabilityLines[0].Amount)
+	assert.Equal(t, int64(170000), result.Equity)
+}
+
+// TestReportService_GetBalance_AsOfWithObligationPayments verifies that the historical balance sheet
+// accumulates paid amounts of obligations from the payments made by the as-of time.
+func TestReportService_GetBalance_AsOfWithObligationPayments(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	asOf := int64(1704067199000) // 2023-12-31 23:59:59 UTC
+	dayMs := int64(24 * 60 * 60 * 1000)
+
+	_, err := tdb.engine.Insert(&models.Obligation{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, Amount: 40000, PaidAmount: 40000, Status: models.OBLIGATION_STATUS_PAID, Currency: "RUB", CreatedUnixTime: (asOf - 5*dayMs) / 1000, UpdatedUnixTime: (asOf + dayMs) / 1000})
+	assert.Nil(t, err)
+
+	payments := []*models.ObligationPayment{
+		{PaymentId: 1, Uid: uid, ObligationId: 1, TransactionId: 1, PaymentDate: asOf - dayMs, Amount: 15000},
+		{PaymentId: 2, Uid: uid, ObligationId: 1, TransactionId: 2, PaymentDate: asOf + dayMs, Amount: 25000},
+	}
+	for _, payment := range payments {
+		_, err = tdb.engine.Insert(payment)
+		assert.Nil(t, err)
+	}
+
+	result, err := svc.GetBalance(nil, uid, 0, asOf, nil)
+	assert.Nil(t, err)
+
+	// Only the first payment was made by the as-of date
+	assert.Equal(t, 1, len(result.AssetLines))
+	assert.Equal(t, models.BalanceLabelReceivables, result.AssetLines[0].Label)
+	assert.Equal(t, int64(25000), result.AssetLines[0].Amount)
+
+	result, err = svc.GetBalance(nil, uid, 0, asOf+2*dayMs, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(result.AssetLines))
+}
+
+// TestReportService_GetBalance_AsOfWithStatusHistories verifies that the historical balance sheet
+// takes the paid amounts and statuses of tax records and obligations without payments from their status histories,
+// so the changes made after the as-of time do not affect it.
+func TestReportService_GetBalance_AsOfWithStatusHistories(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	asOf := int64(1704067199000) // 2023-12-31 23:59:59 UTC
+	dayMs := int64(24 * 60 * 60 * 1000)
+
+	obligationSvc := &ObligationService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: initUuidContainer(t)},
+	}
+	taxSvc := &TaxRecordService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: initUuidContainer(t)},
+	}
+	svc.taxes = taxSvc
+
+	obligation := &models.Obligation{Uid: uid, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 40000, PaidAmount: 10000, Currency: "RUB"}
+	err := obligationSvc.CreateObligation(nil, obligation)
+	assert.Nil(t, err)
+
+	record := &models.TaxRecord{Uid: uid, TaxType: models.TAX_TYPE_INCOME, TaxAmount: 30000, PaidAmount: 5000, Status: models.TAX_STATUS_PENDING, Currency: "RUB"}
+	err = taxSvc.CreateTaxRecord(nil, record)
+	assert.Nil(t, err)
+
+	// move the creation back to before the as-of time, and then pay both in full after that
+	_, err = tdb.engine.Exec("UPDATE obligation SET created_unix_time=? WHERE obligation_id=?", (asOf-5*dayMs)/1000, obligation.ObligationId)
+	assert.Nil(t, err)
+	_, err = tdb.engine.Exec("UPDATE tax_record SET created_unix_time=? WHERE tax_id=?", (asOf-5*dayMs)/1000, record.TaxId)
+	assert.Nil(t, err)
+	_, err = tdb.engine.Exec("UPDATE status_history SET changed_unix_time=? WHERE uid=?", (asOf-5*dayMs)/1000, uid)
+	assert.Nil(t, err)
+
+	obligation.PaidAmount = 40000
+	err = obligationSvc.ModifyObligation(nil, obligation)
+	assert.Nil(t, err)
+
+	record.PaidAmount = 30000
+	record.Status = models.TAX_STATUS_PAID
+	err = taxSvc.ModifyTaxRecord(nil, record)
+	assert.Nil(t, err)
+
+	result, err := svc.GetBalance(nil, uid, 0, asOf, nil)
+	assert.Nil(t, err)
+
+	// Payables = 40000 - 10000 = 30000, Tax liabilities = 30000 - 5000 = 25000
+	assert.Equal(t, 2, len(result.LiabilityLines))
+	assert.Equal(t, models.BalanceLabelPayables, result.LiabilityLines[0].Label)
+	assert.Equal(t, int64(30000), result.LiabilityLines[0].Amount)
+	assert.Equal(t, models.BalanceLabelTaxLiabilities, result.LiabilityLines[1].Label)
+	assert.Equal(t, int64(25000), result.LiabilityLines[1].Amount)
+
+	result, err = svc.GetBalance(nil, uid, 0, 0, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(result.LiabilityLines))
+}
+
+// TestReportService_GetComparativeBalance verifies that the comparative balance sheet
+// matches lines by label and calculates deltas between two dates.
+func TestReportService_GetComparativeBalance(t *testing.T) {
+	svc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+
+	uid := int64(1)
+	compareAsOf := int64(1701388799000) // 2023-11-30 23:59:59 UTC
+	asOf := int64(1704067199000)        // 2023-12-31 23:59:59 UTC
+
+	_, err := tdb.engine.Insert(&models.Account{AccountId: 1, Uid: uid, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Cash", Currency: "RUB"})
+	assert.Nil(t, err)
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: compareAsOf - 1000, Amount: 50000},
+		{TransactionId: 2, Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: asOf - 1000, Amount: 30000},
+	}
+	for _, transaction := range transactions {
+		_, err = tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+
+	_, err = tdb.engine.Insert(&models.Obligation{ObligationId: 1, Uid: uid, ObligationType: models.OBLIGATION_TYPE_PAYABLE, Amount: 20000, Currency: "RUB", CreatedUnixTime: (asOf - 1000) / 1000, UpdatedUnixTime: (asOf - 1000) / 1000})
+	assert.Nil(t, err)
+
+	result, err := svc.GetComparativeBalance(nil, uid, 0, asOf, compareAsOf, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(80000), result.Current.TotalAssets)
+	assert.Equal(t, int64(50000), result.Compare.TotalAssets)
+
+	assert.Equal(t, 1, len(result.AssetLines))
+	assert.Equal(t, models.BalanceLabelCashAndBank, result.AssetLines[0].Label)
+	assert.Equal(t, int64(80000), result.AssetLines[0].Amount)
+	assert.Equal(t, int64(50000), result.AssetLines[0].CompareAmount)
+	assert.Equal(t, int64(30000), result.AssetLines[0].Delta)
+
+	assert.Equal(t, 1, len(result.LiabilityLines))
+	assert.Equal(t, models.BalanceLabelPayables, result.LiabilityLines[0].Label)
+	assert.Equal(t, int64(0), result.LiabilityLines[0].CompareAmount)
+	assert.Equal(t, int64(20000), result.LiabilityLines[0].Delta)
+
+	assert.Equal(t, int64(30000), result.TotalAssetsDelta)
+	assert.Equal(t, int64(20000), result.TotalLiabilityDelta)
+	assert.Equal(t, int64(10000), result.EquityDelta)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -39,6 +40,24 @@ func (m *mockAssetProvider) DeleteAsset(_ core.Context, _ int64, _ int64) error
 func (m *mockAssetProvider) ExistsAssetName(_ core.Context, _ int64, _ string) (bool, error) {
 	return false, nil
 }
+func (m *mockAssetProvider) GetAssetDepreciationSchedule(_ *models.Asset, _ *time.Location) []*models.AssetDepreciationScheduleItemResponse {
+	return nil
+}
+func (m *mockAssetProvider) SaveAssetProductionOutput(_ core.Context, _ int64, _ *models.AssetProductionOutputSaveRequest) (*models.Asset, error) {
+	return nil, nil
+}
+func (m *mockAssetProvider) GetAssetTransactionsByAssetId(_ core.Context, _ int64, _ int64) ([]*models.AssetTransaction, error) {
+	return nil, nil
+}
+func (m *mockAssetProvider) LinkPurchaseTransactions(_ core.Context, _ int64, _ int64, _ []int64) (*models.Asset, error) {
+	return nil, nil
+}
+func (m *mockAssetProvider) UnlinkPurchaseTransactions(_ core.Context, _ int64, _ int64, _ []int64) (*models.Asset, error) {
+	return nil, nil
+}
+func (m *mockAssetProvider) DisposeAsset(_ core.Context, _ int64, _ *models.AssetDisposeRequest) (*models.Asset, error) {
+	return nil, nil
+}
 
 type mockTaxRecordProvider struct {
 	records []*models.TaxRecord
@@ -58,6 +77,9 @@ func (m *mockTaxRecordProvider) ModifyTaxRecord(_ core.Context, _ *models.TaxRec
 	return nil
 }
 func (m *mockTaxRecordProvider) DeleteTaxRecord(_ core.Context, _ int64, _ int64) error { return nil }
+func (m *mockTaxRecordProvider) GetTaxRecordStatusHistories(_ core.Context, _ int64, _ int64) ([]*models.StatusHistory, error) {
+	return nil, nil
+}
 
 type mockInvestorDealProvider struct {
 	deals []*models.InvestorDeal
@@ -146,13 +168,13 @@ func TestReportService_ValidateTimeRange_Integration(t *testing.T) {
 	)
 
 	// Invalid: start >= end
-	_, err := svc.GetCashFlow(nil, 1, 0, 1000, 1000)
+	_, err := svc.GetCashFlow(nil, 1, 0, 1000, 1000, nil)
 	assert.NotNil(t, err)
 
-	_, err = svc.GetPnL(nil, 1, 0, 2000, 1000)
+	_, err = svc.GetPnL(nil, 1, 0, 2000, 1000, nil)
 	assert.NotNil(t, err)
 
-	_, err = svc.GetPaymentCalendar(nil, 1, 5000, 3000)
+	_, err = svc.GetPaymentCalendar(nil, 1, 5000, 3000, nil)
 	assert.NotNil(t, err)
 }
 
@@ -164,16 +186,16 @@ func TestReportService_ValidateUid_Integration(t *testing.T) {
 		&mockInvestorPaymentProvider{},
 	)
 
-	_, err := svc.GetCashFlow(nil, 0, 0, 1000, 2000)
+	_, err := svc.GetCashFlow(nil, 0, 0, 1000, 2000, nil)
 	assert.NotNil(t, err)
 
-	_, err = svc.GetPnL(nil, -1, 0, 1000, 2000)
+	_, err = svc.GetPnL(nil, -1, 0, 1000, 2000, nil)
 	assert.NotNil(t, err)
 
-	_, err = svc.GetBalance(nil, 0, 0)
+	_, err = svc.GetBalance(nil, 0, 0, 0, nil)
 	assert.NotNil(t, err)
 
-	_, err = svc.GetPaymentCalendar(nil, -5, 1000, 2000)
+	_, err = svc.GetPaymentCalendar(nil, -5, 1000, 2000, nil)
 	assert.NotNil(t, err)
 }
 
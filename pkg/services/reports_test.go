@@ -259,8 +259,9 @@ func TestCalculateResidualValue_SameMonthAsCommission(t *testing.T) {
 		CommissionDate:   commDate.Unix(),
 	}
 	asOf := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC) // same month
-	// monthsBetween returns 0 for same month → residual = purchase cost
-	assert.Equal(t, int64(100000), calculateResidualValue(asset, asOf))
+	// monthly depreciation = (100000 - 10000) / 12 = 7500
+	// partial month is prorated by time: 14 of 30 days → 3500 → residual = 96500
+	assert.Equal(t, int64(96500), calculateResidualValue(asset, asOf))
 }
 
 func TestCalculateResidualValue_ResidualNeverBelowSalvage(t *testing.T) {
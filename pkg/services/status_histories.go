@@ -0,0 +1,164 @@
+// status_histories.go records the status and paid amount changes of tax records and obligations, and derives the overdue statuses from due dates.
+package services
+
+import (
+	"xorm.io/builder"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// getTaxRecordStatusAsOf returns the status of tax record at the specified time, the tax record is paid if its paid amount covers the tax amount,
+// or it is marked as paid and has no tax amount, and is overdue if it is not paid after the due date
+func getTaxRecordStatusAsOf(record *models.TaxRecord, asOfMs int64) models.TaxStatus {
+	if record.PaidAmount >= record.TaxAmount && (record.TaxAmount > 0 || record.Status == models.TAX_STATUS_PAID) {
+		return models.TAX_STATUS_PAID
+	}
+
+	if record.TaxAmount > record.PaidAmount && getDaysOverdue(record.DueDate, asOfMs) > 0 {
+		return models.TAX_STATUS_OVERDUE
+	}
+
+	return models.TAX_STATUS_PENDING
+}
+
+// getObligationStatusAsOf returns the status of obligation at the specified time according to its amount, paid amount and due date,
+// the obligation which is not fully paid after the due date is overdue
+func getObligationStatusAsOf(amount int64, paidAmount int64, dueDate int64, asOfMs int64) models.ObligationStatus {
+	status := models.GetObligationStatus(amount, paidAmount)
+
+	if status != models.OBLIGATION_STATUS_PAID && getDaysOverdue(dueDate, asOfMs) > 0 {
+		return models.OBLIGATION_STATUS_OVERDUE
+	}
+
+	return status
+}
+
+// saveStatusChangeInSession appends the status or paid amount change of tax record or obligation to its status history,
+// nothing is saved if neither the status nor the paid amount is changed
+func saveStatusChangeInSession(sess *xorm.Session, uid int64, entityType models.StatusHistoryEntityType, entityId int64, oldStatus byte, newStatus byte, dueDate int64, oldPaidAmount int64, paidAmount int64, automatic bool, now int64) error {
+	if oldStatus == newStatus && oldPaidAmount == paidAmount {
+		return nil
+	}
+
+	count, err := sess.Where("uid=? AND entity_type=? AND entity_id=?", uid, entityType, entityId).Count(&models.StatusHistory{})
+
+	if err != nil {
+		return err
+	}
+
+	history := &models.StatusHistory{
+		Uid:             uid,
+		EntityType:      entityType,
+		EntityId:        entityId,
+		Sequence:        int32(count) + 1,
+		OldStatus:       oldStatus,
+		NewStatus:       newStatus,
+		DueDate:         dueDate,
+		PaidAmount:      paidAmount,
+		Automatic:       automatic,
+		ChangedUnixTime: now,
+	}
+
+	_, err = sess.Insert(history)
+
+	return err
+}
+
+// getStatusHistories returns the status and paid amount changes of tax record or obligation in the order they happened
+func getStatusHistories(sess *xorm.Session, uid int64, entityType models.StatusHistoryEntityType, entityId int64) ([]*models.StatusHistory, error) {
+	var histories []*models.StatusHistory
+	err := sess.Where("uid=? AND entity_type=? AND entity_id=?", uid, entityType, entityId).OrderBy("sequence asc").Find(&histories)
+
+	return histories, err
+}
+
+// getPaidLateEntityIds returns the ids of the specified tax records or obligations which were paid late, the entity was paid late
+// if it has ever been overdue, or it became paid after the due date at that time
+func getPaidLateEntityIds(sess *xorm.Session, uid int64, entityType models.StatusHistoryEntityType, entityIds []int64, overdueStatus byte, paidStatus byte) (map[int64]bool, error) {
+	paidLateIds := make(map[int64]bool)
+
+	if len(entityIds) < 1 {
+		return paidLateIds, nil
+	}
+
+	var histories []*models.StatusHistory
+	err := sess.Where("uid=? AND entity_type=?", uid, entityType).And(builder.In("entity_id", entityIds)).And(builder.In("new_status", overdueStatus, paidStatus)).Find(&histories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, history := range histories {
+		if history.NewStatus == overdueStatus {
+			paidLateIds[history.EntityId] = true
+		} else if history.OldStatus != history.NewStatus && getDaysOverdue(history.DueDate, history.ChangedUnixTime*1000) > 0 {
+			paidLateIds[history.EntityId] = true
+		}
+	}
+
+	return paidLateIds, nil
+}
+
+// getPaidLateObligationIds returns the ids of the specified obligations which were paid late
+func getPaidLateObligationIds(sess *xorm.Session, uid int64, obligations []*models.Obligation) (map[int64]bool, error) {
+	obligationIds := make([]int64, 0, len(obligations))
+
+	for _, obligation := range obligations {
+		obligationIds = append(obligationIds, obligation.ObligationId)
+	}
+
+	return getPaidLateEntityIds(sess, uid, models.STATUS_HISTORY_ENTITY_TYPE_OBLIGATION, utils.ToUniqueInt64Slice(obligationIds), byte(models.OBLIGATION_STATUS_OVERDUE), byte(models.OBLIGATION_STATUS_PAID))
+}
+
+// getPaidLateTaxRecordIds returns the ids of the specified tax records which were paid late
+func getPaidLateTaxRecordIds(sess *xorm.Session, uid int64, records []*models.TaxRecord) (map[int64]bool, error) {
+	taxIds := make([]int64, 0, len(records))
+
+	for _, record := range records {
+		taxIds = append(taxIds, record.TaxId)
+	}
+
+	return getPaidLateEntityIds(sess, uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, utils.ToUniqueInt64Slice(taxIds), byte(models.TAX_STATUS_OVERDUE), byte(models.TAX_STATUS_PAID))
+}
+
+// entityStateAsOf represents the status and paid amount of tax record or obligation at a specific time
+type entityStateAsOf struct {
+	Status     byte
+	PaidAmount int64
+}
+
+// getEntityStatesAsOf returns the statuses and paid amounts as of the specified time (in milliseconds) of all tax records or obligations
+// of the specified entity type which have status history. The state before the first recorded change is the old status of that change and
+// no paid amount, because the initial paid amount is recorded when the entity is created
+func getEntityStatesAsOf(sess *xorm.Session, uid int64, entityType models.StatusHistoryEntityType, asOfMs int64) (map[int64]*entityStateAsOf, error) {
+	var histories []*models.StatusHistory
+	err := sess.Where("uid=? AND entity_type=?", uid, entityType).OrderBy("entity_id asc, sequence asc").Find(&histories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[int64]*entityStateAsOf)
+
+	for _, history := range histories {
+		state, exists := states[history.EntityId]
+
+		if !exists {
+			state = &entityStateAsOf{
+				Status:     history.OldStatus,
+				PaidAmount: 0,
+			}
+
+			states[history.EntityId] = state
+		}
+
+		if history.ChangedUnixTime*1000 <= asOfMs {
+			state.Status = history.NewStatus
+			state.PaidAmount = history.PaidAmount
+		}
+	}
+
+	return states, nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTaxRecordServiceUpdateOverdueTaxRecords(t *testing.T) {
+	svc, tdb := newTestTaxRecordService(t)
+	defer tdb.close()
+
+	records := []*models.TaxRecord{
+		{TaxId: 301, Uid: 1, TaxType: models.TAX_TYPE_INCOME, TaxAmount: 10000, DueDate: 1700000000000, Status: models.TAX_STATUS_PENDING, Currency: "RUB"},
+		{TaxId: 302, Uid: 1, TaxType: models.TAX_TYPE_INCOME, TaxAmount: 10000, DueDate: 1800000000000, Status: models.TAX_STATUS_PENDING, Currency: "RUB"},
+		{TaxId: 303, Uid: 1, TaxType: models.TAX_TYPE_VAT, TaxAmount: 10000, PaidAmount: 10000, DueDate: 1700000000000, Status: models.TAX_STATUS_PENDING, Currency: "RUB"},
+		{TaxId: 304, Uid: 1, TaxType: models.TAX_TYPE_PROPERTY, TaxAmount: 10000, PaidAmount: 10000, DueDate: 1700000000000, Status: models.TAX_STATUS_PAID, Currency: "RUB"},
+		{TaxId: 305, Uid: 1, TaxType: models.TAX_TYPE_OTHER, TaxAmount: 15000, PaidAmount: 10000, DueDate: 1700000000000, Status: models.TAX_STATUS_PAID, Currency: "RUB", UpdatedUnixTime: 1690000000},
+	}
+
+	for _, record := range records {
+		_, err := tdb.engine.Insert(record)
+		assert.Nil(t, err)
+	}
+
+	err := svc.UpdateOverdueTaxRecords(nil, 1700000000+2*24*60*60)
+	assert.Nil(t, err)
+
+	expectedStatuses := map[int64]models.TaxStatus{
+		301: models.TAX_STATUS_OVERDUE,
+		302: models.TAX_STATUS_PENDING,
+		303: models.TAX_STATUS_PAID,
+		304: models.TAX_STATUS_PAID,
+		305: models.TAX_STATUS_OVERDUE,
+	}
+
+	for taxId, expectedStatus := range expectedStatuses {
+		got, err := svc.GetTaxRecordByTaxId(nil, 1, taxId)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedStatus, got.Status)
+	}
+
+	histories, err := svc.GetTaxRecordStatusHistories(nil, 1, 301)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(histories))
+	assert.Equal(t, byte(models.TAX_STATUS_PENDING), histories[0].OldStatus)
+	assert.Equal(t, byte(models.TAX_STATUS_OVERDUE), histories[0].NewStatus)
+	assert.True(t, histories[0].Automatic)
+	assert.Equal(t, int64(1700000000+2*24*60*60), histories[0].ChangedUnixTime)
+
+	histories, err = svc.GetTaxRecordStatusHistories(nil, 1, 302)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(histories))
+
+	// the paid tax record whose tax amount is raised after payment becomes overdue again, and its updated time is not changed
+	got, err := svc.GetTaxRecordByTaxId(nil, 1, 305)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1690000000), got.UpdatedUnixTime)
+
+	histories, err = svc.GetTaxRecordStatusHistories(nil, 1, 305)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(histories))
+	assert.Equal(t, byte(models.TAX_STATUS_PAID), histories[0].OldStatus)
+	assert.Equal(t, byte(models.TAX_STATUS_OVERDUE), histories[0].NewStatus)
+
+	// the overdue tax record becomes paid after its paid amount covers the tax amount
+	record, err := svc.GetTaxRecordByTaxId(nil, 1, 301)
+	assert.Nil(t, err)
+	record.PaidAmount = 10000
+	err = svc.ModifyTaxRecord(nil, record)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TAX_STATUS_PAID, record.Status)
+
+	histories, err = svc.GetTaxRecordStatusHistories(nil, 1, 301)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(histories))
+	assert.Equal(t, int32(2), histories[1].Sequence)
+	assert.Equal(t, byte(models.TAX_STATUS_OVERDUE), histories[1].OldStatus)
+	assert.Equal(t, byte(models.TAX_STATUS_PAID), histories[1].NewStatus)
+	assert.Equal(t, int64(10000), histories[1].PaidAmount)
+	assert.False(t, histories[1].Automatic)
+}
+
+func TestObligationServiceUpdateOverdueObligations(t *testing.T) {
+	svc, tdb := newTestObligationService(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	err := svc.UpdateOverdueObligations(nil, 1700000000+2*24*60*60)
+	assert.Nil(t, err)
+
+	// obligation 202 is overdue for 2 days, obligation 201 is overdue for less than a day, and the others have no due dates
+	got, err := svc.GetObligationByObligationId(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_OVERDUE, got.Status)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, got.Status)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 203)
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_ACTIVE, got.Status)
+
+	// the partially paid overdue obligation stays overdue
+	_, obligations, err := svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 202, Amount: 15000}}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_OVERDUE, obligations[0].Status)
+
+	// the overdue obligation becomes paid after it is fully paid
+	_, obligations, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{{ObligationId: 202, Amount: 25000}}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_PAID, obligations[0].Status)
+
+	histories, err := svc.GetObligationStatusHistories(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(histories))
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_ACTIVE), histories[0].OldStatus)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_OVERDUE), histories[0].NewStatus)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_OVERDUE), histories[1].OldStatus)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_OVERDUE), histories[1].NewStatus)
+	assert.Equal(t, int64(15000), histories[1].PaidAmount)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_OVERDUE), histories[2].OldStatus)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_PAID), histories[2].NewStatus)
+	assert.Equal(t, int64(40000), histories[2].PaidAmount)
+
+	// the paid obligation is not changed by the cron job any more
+	err = svc.UpdateOverdueObligations(nil, time.Now().Unix())
+	assert.Nil(t, err)
+
+	histories, err = svc.GetObligationStatusHistories(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(histories))
+
+	// the paid obligation whose amount is raised after payment becomes overdue again, and its updated time is not changed
+	_, err = tdb.engine.ID(202).Cols("amount", "updated_unix_time").Update(&models.Obligation{Amount: 50000, UpdatedUnixTime: 1690000000})
+	assert.Nil(t, err)
+
+	err = svc.UpdateOverdueObligations(nil, time.Now().Unix())
+	assert.Nil(t, err)
+
+	got, err = svc.GetObligationByObligationId(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, models.OBLIGATION_STATUS_OVERDUE, got.Status)
+	assert.Equal(t, int64(1690000000), got.UpdatedUnixTime)
+
+	histories, err = svc.GetObligationStatusHistories(nil, 1, 202)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(histories))
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_PAID), histories[3].OldStatus)
+	assert.Equal(t, byte(models.OBLIGATION_STATUS_OVERDUE), histories[3].NewStatus)
+	assert.True(t, histories[3].Automatic)
+}
+
+func TestReportServicePaidLateObligationsInAgingAndPaymentCalendar(t *testing.T) {
+	reportSvc, tdb := newTestReportServiceWithDB(t)
+	defer tdb.close()
+	insertTestObligationSettlementData(t, tdb)
+
+	svc := &ObligationService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: initUuidContainer(t)},
+	}
+
+	futureDueDate := time.Now().Add(30 * 24 * time.Hour).UnixMilli()
+	_, err := tdb.engine.Insert(&models.Obligation{ObligationId: 206, Uid: 1, ObligationType: models.OBLIGATION_TYPE_RECEIVABLE, CounterpartyId: 7, Amount: 5000, Currency: "RUB", DueDate: futureDueDate, Status: models.OBLIGATION_STATUS_ACTIVE})
+	assert.Nil(t, err)
+
+	// obligation 202 was overdue before it was paid, and obligation 206 is paid before its due date
+	err = svc.UpdateOverdueObligations(nil, 1700000000+2*24*60*60)
+	assert.Nil(t, err)
+
+	_, _, err = svc.AllocateTransaction(nil, 1, 101, []*models.ObligationAllocation{
+		{ObligationId: 202, Amount: 40000},
+		{ObligationId: 206, Amount: 5000},
+	}, "")
+	assert.Nil(t, err)
+
+	err = svc.UpdateOverdueObligations(nil, time.Now().Unix())
+	assert.Nil(t, err)
+
+	aging, err := reportSvc.GetAging(nil, 1, 0, 0, nil)
+	assert.Nil(t, err)
+
+	var line *models.AgingLine
+
+	for _, receivableLine := range aging.Receivables.Lines {
+		if receivableLine.CounterpartyId == 7 {
+			line = receivableLine
+		}
+	}
+
+	assert.NotNil(t, line)
+	assert.Equal(t, 1, line.PaidLateCount)
+	assert.Equal(t, 1, line.PaidOnTimeCount)
+
+	calendar, err := reportSvc.GetPaymentCalendar(nil, 1, 1699000000000, futureDueDate+24*60*60*1000, nil)
+	assert.Nil(t, err)
+
+	// obligation 201 is the only open obligation due in range and is overdue now
+	var obligationItems []*models.PaymentCalendarItem
+
+	for _, item := range calendar.Items {
+		if item.Type == models.PaymentTypeReceivable {
+			obligationItems = append(obligationItems, item)
+		}
+	}
+
+	assert.Equal(t, 1, len(obligationItems))
+	assert.Equal(t, int64(1700100000000), obligationItems[0].Date)
+	assert.True(t, obligationItems[0].Overdue)
+
+	assert.Equal(t, 2, len(calendar.SettledItems))
+	assert.Equal(t, int64(1700000000000), calendar.SettledItems[0].Date)
+	assert.Equal(t, int64(40000), calendar.SettledItems[0].Amount)
+	assert.True(t, calendar.SettledItems[0].PaidLate)
+	assert.Equal(t, futureDueDate, calendar.SettledItems[1].Date)
+	assert.False(t, calendar.SettledItems[1].PaidLate)
+}
@@ -0,0 +1,448 @@
+// tax_calculations.go computes the taxes of a period by tax regime from the transactions and assets of user or CFO.
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// taxPeriodKey identifies the tax record of a tax period
+type taxPeriodKey struct {
+	cfoId         int64
+	taxType       models.TaxType
+	periodYear    int32
+	periodQuarter int32
+}
+
+// taxableTransactionRow is a helper struct for the query results of taxable transactions
+type taxableTransactionRow struct {
+	CategoryId       int64  `xorm:"category_id"`
+	ParentCategoryId int64  `xorm:"parent_category_id"`
+	Type             int32  `xorm:"type"`
+	Currency         string `xorm:"currency"`
+	Amount           int64  `xorm:"total_amount"`
+}
+
+// buildTaxableTransactionQuery returns the SQL query for the income and expense transactions counted by tax regimes,
+// the non-cash depreciation and write-off transactions posted for assets are excluded
+func buildTaxableTransactionQuery() string {
+	return fmt.Sprintf(`SELECT t.category_id, COALESCE(tc.parent_category_id, 0) as parent_category_id, t.type, COALESCE(a.currency, '') as currency, SUM(t.amount) as total_amount
+		FROM "transaction" t
+		LEFT JOIN transaction_category tc ON t.category_id = tc.category_id AND tc.uid = t.uid
+		LEFT JOIN account a ON t.account_id = a.account_id AND a.uid = t.uid
+		WHERE t.uid = ? AND t.deleted = 0 AND t.planned = 0
+		AND t.transaction_time >= ? AND t.transaction_time < ?
+		AND t.type IN (%d, %d)
+		AND NOT EXISTS (SELECT 1 FROM asset_transaction ast WHERE ast.uid = t.uid AND ast.transaction_id = t.transaction_id AND ast.transaction_type IN (%d, %d))`,
+		models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE,
+		models.ASSET_TRANSACTION_TYPE_DEPRECIATION, models.ASSET_TRANSACTION_TYPE_DISPOSAL_WRITE_OFF)
+}
+
+// getTaxPeriodRange returns the start time (inclusive) and the end time (exclusive) of the tax period in the timezone
+func getTaxPeriodRange(periodType models.TaxRegimePeriodType, periodYear int32, periodQuarter int32, timezone *time.Location) (time.Time, time.Time, error) {
+	if periodType == models.TAX_REGIME_PERIOD_TYPE_QUARTERLY && periodQuarter >= 1 && periodQuarter <= 4 {
+		startTime := time.Date(int(periodYear), time.Month((periodQuarter-1)*3+1), 1, 0, 0, 0, 0, timezone)
+		return startTime, startTime.AddDate(0, 3, 0), nil
+	} else if periodType == models.TAX_REGIME_PERIOD_TYPE_ANNUAL && periodQuarter == 0 {
+		startTime := time.Date(int(periodYear), time.January, 1, 0, 0, 0, 0, timezone)
+		return startTime, startTime.AddDate(1, 0, 0), nil
+	}
+
+	return time.Time{}, time.Time{}, errs.ErrTaxPeriodInvalid
+}
+
+// getTaxDueDate returns the due date of the tax period which ends at the specified time,
+// the tax is due on the due day of the month which is the due months after the last month of the period,
+// and the due day after the last day of that month is clamped to the last day
+func getTaxDueDate(regime *models.TaxRegime, periodEndTime time.Time) time.Time {
+	dueMonthStartTime := time.Date(periodEndTime.Year(), periodEndTime.Month()-1+time.Month(regime.DueMonthsAfterPeriod), 1, 0, 0, 0, 0, periodEndTime.Location())
+	dueDay := min(int(regime.DueDayOfMonth), dueMonthStartTime.AddDate(0, 1, -1).Day())
+
+	return dueMonthStartTime.AddDate(0, 0, dueDay-1)
+}
+
+// getTaxPeriodName returns the display name of the tax period
+func getTaxPeriodName(periodYear int32, periodQuarter int32) string {
+	if periodQuarter > 0 {
+		return fmt.Sprintf("%d Q%d", periodYear, periodQuarter)
+	}
+
+	return fmt.Sprintf("%d", periodYear)
+}
+
+// applyTaxRate returns the amount multiplied by the rate in basis points, rounded half away from zero
+func applyTaxRate(amount int64, rate int32) int64 {
+	return roundedDivide(amount*int64(rate), models.TaxRateBasisPointsPerUnit)
+}
+
+// getIncludedVat returns the VAT included in the amount at the rate in basis points
+func getIncludedVat(amount int64, rate int32) int64 {
+	return roundedDivide(amount*int64(rate), models.TaxRateBasisPointsPerUnit+int64(rate))
+}
+
+// roundedDivide returns the quotient rounded half away from zero
+func roundedDivide(dividend int64, divisor int64) int64 {
+	if dividend < 0 {
+		return -((-dividend + divisor/2) / divisor)
+	}
+
+	return (dividend + divisor/2) / divisor
+}
+
+// calculateTaxByRegime computes the tax of the period by tax regime, the stored tax record of the period is not filled
+func calculateTaxByRegime(c core.Context, database *datastore.Database, regime *models.TaxRegime, periodYear int32, periodQuarter int32, timezone *time.Location) (*models.TaxCalculationResponse, error) {
+	startTime, endTime, err := getTaxPeriodRange(regime.PeriodType, periodYear, periodQuarter, timezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.TaxCalculationResponse{
+		CfoId:         regime.CfoId,
+		TaxType:       regime.TaxType,
+		RegimeType:    regime.RegimeType,
+		PeriodYear:    periodYear,
+		PeriodQuarter: periodQuarter,
+		StartTime:     startTime.UnixMilli(),
+		EndTime:       endTime.UnixMilli(),
+		DueDate:       getTaxDueDate(regime, endTime).UnixMilli(),
+		Rate:          regime.Rate,
+		Currency:      regime.Currency,
+		Lines:         []*models.TaxCalculationLine{},
+	}
+
+	appendLine := func(label string, amount int64) {
+		response.Lines = append(response.Lines, &models.TaxCalculationLine{Label: label, Amount: amount})
+	}
+
+	if regime.RegimeType == models.TAX_REGIME_TYPE_PROPERTY {
+		averageBookValue, warnings, err := getAverageAssetBookValue(c, database, regime, startTime, endTime)
+
+		if err != nil {
+			return nil, err
+		}
+
+		response.Warnings = warnings
+
+		months := monthsBetween(startTime, endTime)
+
+		// the rate of property tax is annual, so the tax of the period is prorated by months
+		response.TaxableIncome = averageBookValue
+		response.TaxAmount = roundedDivide(averageBookValue*int64(regime.Rate)*months, models.TaxRateBasisPointsPerUnit*12)
+		appendLine(models.TaxCalculationLabelAverageAssetBookValue, averageBookValue)
+		appendLine(models.TaxCalculationLabelTaxAmount, response.TaxAmount)
+
+		return response, nil
+	}
+
+	totalsStartTime := startTime
+
+	// the simplified tax on income minus expenses is computed on the income and expenses since the beginning of the year
+	if regime.RegimeType == models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES {
+		totalsStartTime = time.Date(int(periodYear), time.January, 1, 0, 0, 0, 0, timezone)
+	}
+
+	income, expenses, warnings, err := getTaxableTransactionTotals(c, database, regime, totalsStartTime.UnixMilli(), endTime.UnixMilli())
+
+	if err != nil {
+		return nil, err
+	}
+
+	response.Warnings = warnings
+	appendLine(models.TaxCalculationLabelIncome, income)
+
+	switch regime.RegimeType {
+	case models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME:
+		response.TaxableIncome = income
+		response.TaxAmount = applyTaxRate(income, regime.Rate)
+		appendLine(models.TaxCalculationLabelTaxAmount, response.TaxAmount)
+	case models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES:
+		// the tax of quarter is the advance of the tax since the beginning of the year minus the advances of previous quarters,
+		// and the minimum tax on income of the whole year only applies to the period which ends the year
+		taxBase := max(income-expenses, 0)
+		taxAtRate := applyTaxRate(taxBase, regime.Rate)
+		minimumTax := applyTaxRate(income, regime.MinimumTaxRate)
+		endsYear := periodQuarter == 0 || periodQuarter == 4
+		taxOfYear := taxAtRate
+
+		if endsYear && minimumTax > taxOfYear {
+			taxOfYear = minimumTax
+		}
+
+		advancePayments, err := getSimplifiedTaxAdvancePayments(c, database, regime, periodYear, periodQuarter-1, timezone)
+
+		if err != nil {
+			return nil, err
+		}
+
+		response.TaxableIncome = taxBase
+		response.TaxAmount = max(taxOfYear-advancePayments, 0)
+
+		appendLine(models.TaxCalculationLabelExpenses, expenses)
+		appendLine(models.TaxCalculationLabelTaxBase, taxBase)
+		appendLine(models.TaxCalculationLabelTaxAtRate, taxAtRate)
+
+		if endsYear && regime.MinimumTaxRate > 0 {
+			appendLine(models.TaxCalculationLabelMinimumTax, minimumTax)
+		}
+
+		if advancePayments > 0 {
+			appendLine(models.TaxCalculationLabelAdvancePayments, advancePayments)
+		}
+
+		appendLine(models.TaxCalculationLabelTaxAmount, response.TaxAmount)
+	case models.TAX_REGIME_TYPE_VAT:
+		// all amounts include VAT, the output VAT is charged on income and the input VAT is paid on expenses
+		outputVat := getIncludedVat(income, regime.Rate)
+		inputVat := getIncludedVat(expenses, regime.Rate)
+
+		response.TaxableIncome = income - outputVat
+		response.TaxAmount = outputVat - inputVat
+
+		appendLine(models.TaxCalculationLabelOutputVat, outputVat)
+		appendLine(models.TaxCalculationLabelExpenses, expenses)
+		appendLine(models.TaxCalculationLabelInputVat, inputVat)
+
+		if response.TaxAmount < 0 {
+			appendLine(models.TaxCalculationLabelVatRefundable, -response.TaxAmount)
+			response.TaxAmount = 0
+		}
+
+		appendLine(models.TaxCalculationLabelTaxAmount, response.TaxAmount)
+	}
+
+	return response, nil
+}
+
+// getSimplifiedTaxAdvancePayments returns the total advances of simplified tax on income minus expenses for the quarters of the year until the specified quarter,
+// the advance of each quarter is the tax at rate since the beginning of the year minus the advances of previous quarters, and is never negative
+func getSimplifiedTaxAdvancePayments(c core.Context, database *datastore.Database, regime *models.TaxRegime, periodYear int32, lastQuarter int32, timezone *time.Location) (int64, error) {
+	yearStartTime := time.Date(int(periodYear), time.January, 1, 0, 0, 0, 0, timezone)
+	advancePayments := int64(0)
+
+	for quarter := int32(1); quarter <= lastQuarter; quarter++ {
+		_, quarterEndTime, err := getTaxPeriodRange(models.TAX_REGIME_PERIOD_TYPE_QUARTERLY, periodYear, quarter, timezone)
+
+		if err != nil {
+			return 0, err
+		}
+
+		income, expenses, _, err := getTaxableTransactionTotals(c, database, regime, yearStartTime.UnixMilli(), quarterEndTime.UnixMilli())
+
+		if err != nil {
+			return 0, err
+		}
+
+		taxAtRate := applyTaxRate(max(income-expenses, 0), regime.Rate)
+		advancePayments += max(taxAtRate-advancePayments, 0)
+	}
+
+	return advancePayments, nil
+}
+
+// getTaxableTransactionTotals returns the total income and expenses of the transactions counted by tax regime in the time range (in milliseconds),
+// the transactions of excluded categories and of the accounts in other currencies than the tax regime are not counted
+func getTaxableTransactionTotals(c core.Context, database *datastore.Database, regime *models.TaxRegime, startTimeMs int64, endTimeMs int64) (int64, int64, []string, error) {
+	query := buildTaxableTransactionQuery()
+	args := []any{regime.Uid, startTimeMs, endTimeMs}
+
+	if regime.CfoId > 0 {
+		query += cfoFilterClause
+		args = append(args, regime.CfoId)
+	}
+
+	query += " GROUP BY t.category_id, COALESCE(tc.parent_category_id, 0), t.type, COALESCE(a.currency, '')"
+
+	var rows []*taxableTransactionRow
+	err := database.NewSession(c).SQL(query, args...).Find(&rows)
+
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	excludedCategoryIds := make(map[int64]bool)
+
+	for _, categoryId := range regime.GetExcludedCategoryIds() {
+		excludedCategoryIds[categoryId] = true
+	}
+
+	income := int64(0)
+	expenses := int64(0)
+	var warnings []string
+	otherCurrencies := make(map[string]bool)
+
+	for _, row := range rows {
+		if excludedCategoryIds[row.CategoryId] || (row.ParentCategoryId > 0 && excludedCategoryIds[row.ParentCategoryId]) {
+			continue
+		}
+
+		if row.Currency != regime.Currency {
+			if !otherCurrencies[row.Currency] {
+				otherCurrencies[row.Currency] = true
+				warnings = append(warnings, fmt.Sprintf("Transactions in %s are not counted", row.Currency))
+			}
+
+			continue
+		}
+
+		if row.Type == int32(models.TRANSACTION_DB_TYPE_INCOME) {
+			income += row.Amount
+		} else if row.Type == int32(models.TRANSACTION_DB_TYPE_EXPENSE) {
+			expenses += row.Amount
+		}
+	}
+
+	return income, expenses, warnings, nil
+}
+
+// getAverageAssetBookValue returns the average book value of the assets of tax regime in the period,
+// which is the average of the book values on the first day of each month and on the end of the period,
+// the assets whose book accounts are in other currencies than the tax regime are not counted,
+// and the assets without book account are regarded as in the currency of the tax regime
+func getAverageAssetBookValue(c core.Context, database *datastore.Database, regime *models.TaxRegime, startTime time.Time, endTime time.Time) (int64, []string, error) {
+	var allAssets []*models.Asset
+	err := database.NewSession(c).Where("uid=? AND deleted=?", regime.Uid, false).Find(&allAssets)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	assetCurrencies, err := getAssetBookAccountCurrencies(c, database, regime.Uid, allAssets)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var assets []*models.Asset
+	var warnings []string
+	otherCurrencies := make(map[string]bool)
+
+	for _, asset := range allAssets {
+		if !matchesCfo(regime.CfoId, asset.CfoId) {
+			continue
+		}
+
+		if currency, exists := assetCurrencies[asset.AccountId]; exists && currency != regime.Currency {
+			if !otherCurrencies[currency] {
+				otherCurrencies[currency] = true
+				warnings = append(warnings, fmt.Sprintf("Assets in %s are not counted", currency))
+			}
+
+			continue
+		}
+
+		assets = append(assets, asset)
+	}
+
+	var valuationTimes []time.Time
+
+	for valuationTime := startTime; valuationTime.Before(endTime); valuationTime = valuationTime.AddDate(0, 1, 0) {
+		valuationTimes = append(valuationTimes, valuationTime)
+	}
+
+	valuationTimes = append(valuationTimes, endTime)
+	totalBookValue := int64(0)
+
+	for _, valuationTime := range valuationTimes {
+		for _, asset := range assets {
+			totalBookValue += getAssetBookValueAt(asset, valuationTime)
+		}
+	}
+
+	return roundedDivide(totalBookValue, int64(len(valuationTimes))), warnings, nil
+}
+
+// getAssetBookValueAt returns the book value of asset at the specified time, or zero if the asset is not owned at that time
+func getAssetBookValueAt(asset *models.Asset, valuationTime time.Time) int64 {
+	if utils.ToMillisIfSeconds(asset.PurchaseDate) > valuationTime.UnixMilli() {
+		return 0
+	}
+
+	if asset.Status != models.ASSET_STATUS_ACTIVE && asset.DecommissionDate > 0 && utils.ToMillisIfSeconds(asset.DecommissionDate) <= valuationTime.UnixMilli() {
+		return 0
+	}
+
+	return calculateResidualValue(asset, valuationTime)
+}
+
+// fillStoredTaxRecord fills the stored tax record of the period into the tax calculation and flags whether the stored tax amount diverges from the computed one
+func fillStoredTaxRecord(calculation *models.TaxCalculationResponse, record *models.TaxRecord) {
+	calculation.TaxRecordId = record.TaxId
+	calculation.StoredTaxAmount = record.TaxAmount
+	calculation.Diverged = record.TaxAmount != calculation.TaxAmount
+}
+
+// getScheduledTaxCalculations returns the taxes computed by all tax regimes of user for the periods which are due in the time range (in milliseconds)
+// and have no tax record yet, the periods whose computed tax is zero are omitted
+func getScheduledTaxCalculations(c core.Context, database *datastore.Database, uid int64, startTimeMs int64, endTimeMs int64, timezone *time.Location) ([]*models.TaxCalculationResponse, error) {
+	var regimes []*models.TaxRegime
+	err := database.NewSession(c).Where("uid=?", uid).OrderBy("cfo_id asc, tax_type asc").Find(&regimes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regimes) < 1 {
+		return nil, nil
+	}
+
+	var records []*models.TaxRecord
+	err = database.NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&records)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recordPeriods := make(map[taxPeriodKey]bool, len(records))
+
+	for _, record := range records {
+		recordPeriods[taxPeriodKey{cfoId: record.CfoId, taxType: record.TaxType, periodYear: record.PeriodYear, periodQuarter: record.PeriodQuarter}] = true
+	}
+
+	// the due date is at most one year after the end of period, so the periods starting two years before the time range are enough
+	firstYear := int32(time.UnixMilli(startTimeMs).In(timezone).Year() - 2)
+	lastYear := int32(time.UnixMilli(endTimeMs).In(timezone).Year())
+	var calculations []*models.TaxCalculationResponse
+
+	for _, regime := range regimes {
+		quarters := []int32{0}
+
+		if regime.PeriodType == models.TAX_REGIME_PERIOD_TYPE_QUARTERLY {
+			quarters = []int32{1, 2, 3, 4}
+		}
+
+		for year := firstYear; year <= lastYear; year++ {
+			for _, quarter := range quarters {
+				_, periodEndTime, err := getTaxPeriodRange(regime.PeriodType, year, quarter, timezone)
+
+				if err != nil {
+					return nil, err
+				}
+
+				dueDate := getTaxDueDate(regime, periodEndTime).UnixMilli()
+
+				if dueDate < startTimeMs || dueDate >= endTimeMs || recordPeriods[taxPeriodKey{cfoId: regime.CfoId, taxType: regime.TaxType, periodYear: year, periodQuarter: quarter}] {
+					continue
+				}
+
+				calculation, err := calculateTaxByRegime(c, database, regime, year, quarter, timezone)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if calculation.TaxAmount > 0 {
+					calculations = append(calculations, calculation)
+				}
+			}
+		}
+	}
+
+	return calculations, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/uuid"
 )
@@ -65,12 +66,23 @@ func (s *TaxRecordService) GetTaxRecordByTaxId(c core.Context, uid int64, taxId
 	return record, nil
 }
 
-// CreateTaxRecord saves a new tax record model to database
+// CreateTaxRecord saves a new tax record model to database, the initial paid amount is recorded in its status history
 func (s *TaxRecordService) CreateTaxRecord(c core.Context, record *models.TaxRecord) error {
 	if record.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	return s.UserDataDB(record.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.CreateTaxRecordInSession(c, sess, record)
+	})
+}
+
+// CreateTaxRecordInSession saves a new tax record model and its initial status history to database in the given session
+func (s *TaxRecordService) CreateTaxRecordInSession(c core.Context, sess *xorm.Session, record *models.TaxRecord) error {
+	if record.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	record.TaxId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
 
 	if record.TaxId < 1 {
@@ -81,13 +93,16 @@ func (s *TaxRecordService) CreateTaxRecord(c core.Context, record *models.TaxRec
 	record.CreatedUnixTime = time.Now().Unix()
 	record.UpdatedUnixTime = time.Now().Unix()
 
-	return s.UserDataDB(record.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Insert(record)
+	_, err := sess.Insert(record)
+
+	if err != nil {
 		return err
-	})
+	}
+
+	return saveStatusChangeInSession(sess, record.Uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, record.TaxId, byte(record.Status), byte(record.Status), record.DueDate, 0, record.PaidAmount, false, record.CreatedUnixTime)
 }
 
-// ModifyTaxRecord saves an existed tax record model to database
+// ModifyTaxRecord saves an existed tax record model and its status or paid amount change to database, the overdue tax record whose paid amount covers the tax amount becomes paid
 func (s *TaxRecordService) ModifyTaxRecord(c core.Context, record *models.TaxRecord) error {
 	if record.Uid <= 0 {
 		return errs.ErrUserIdInvalid
@@ -95,7 +110,20 @@ func (s *TaxRecordService) ModifyTaxRecord(c core.Context, record *models.TaxRec
 
 	record.UpdatedUnixTime = time.Now().Unix()
 
+	if record.Status == models.TAX_STATUS_OVERDUE && record.TaxAmount > 0 && record.PaidAmount >= record.TaxAmount {
+		record.Status = models.TAX_STATUS_PAID
+	}
+
 	return s.UserDataDB(record.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldRecord := &models.TaxRecord{}
+		has, err := sess.ID(record.TaxId).Cols("status", "paid_amount").Where("uid=? AND deleted=?", record.Uid, false).Get(oldRecord)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTaxRecordNotFound
+		}
+
 		updatedRows, err := sess.ID(record.TaxId).Cols("cfo_id", "tax_type", "period_year", "period_quarter", "taxable_income", "tax_amount", "paid_amount", "due_date", "status", "comment", "currency", "updated_unix_time").Where("uid=? AND deleted=?", record.Uid, false).Update(record)
 
 		if err != nil {
@@ -104,7 +132,7 @@ func (s *TaxRecordService) ModifyTaxRecord(c core.Context, record *models.TaxRec
 			return errs.ErrTaxRecordNotFound
 		}
 
-		return err
+		return saveStatusChangeInSession(sess, record.Uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, record.TaxId, byte(oldRecord.Status), byte(record.Status), record.DueDate, oldRecord.PaidAmount, record.PaidAmount, false, record.UpdatedUnixTime)
 	})
 }
 
@@ -133,3 +161,78 @@ func (s *TaxRecordService) DeleteTaxRecord(c core.Context, uid int64, taxId int6
 		return err
 	})
 }
+
+// GetTaxRecordStatusHistories returns the status changes of the tax record in the order they happened
+func (s *TaxRecordService) GetTaxRecordStatusHistories(c core.Context, uid int64, taxId int64) ([]*models.StatusHistory, error) {
+	if _, err := s.GetTaxRecordByTaxId(c, uid, taxId); err != nil {
+		return nil, err
+	}
+
+	return getStatusHistories(s.UserDataDB(uid).NewSession(c), uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, taxId)
+}
+
+// UpdateOverdueTaxRecords updates the statuses of all unpaid tax records of all users according to their due dates and paid amounts,
+// the tax records which are not paid after the due dates become overdue, and the ones whose paid amounts cover the tax amounts become paid.
+// The paid tax records whose paid amounts are less than the tax amounts are checked again.
+func (s *TaxRecordService) UpdateOverdueTaxRecords(c core.Context, currentUnixTime int64) error {
+	var allRecords []*models.TaxRecord
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var records []*models.TaxRecord
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND (status<>? OR paid_amount<tax_amount)", false, models.TAX_STATUS_PAID).Find(&records)
+
+		if err != nil {
+			return err
+		}
+
+		allRecords = append(allRecords, records...)
+	}
+
+	updatedCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allRecords); i++ {
+		record := allRecords[i]
+		newStatus := getTaxRecordStatusAsOf(record, currentUnixTime*1000)
+
+		if newStatus == record.Status {
+			continue
+		}
+
+		err := s.updateTaxRecordStatus(c, record, newStatus, currentUnixTime)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[tax_records.UpdateOverdueTaxRecords] failed to update status of tax record \"id:%d\" for user \"uid:%d\", because %s", record.TaxId, record.Uid, err.Error())
+			continue
+		}
+
+		updatedCount++
+	}
+
+	if updatedCount > 0 || failedCount > 0 {
+		log.Infof(c, "[tax_records.UpdateOverdueTaxRecords] %d tax records have been updated, %d tax records failed", updatedCount, failedCount)
+	}
+
+	return nil
+}
+
+// updateTaxRecordStatus saves the status of tax record which is changed by system and its status change, the tax record modified after being loaded is not updated,
+// and the updated time of tax record is not changed since the change is recorded in the status history only
+func (s *TaxRecordService) updateTaxRecordStatus(c core.Context, record *models.TaxRecord, newStatus models.TaxStatus, now int64) error {
+	updateModel := &models.TaxRecord{
+		Status: newStatus,
+	}
+
+	return s.UserDataDB(record.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(record.TaxId).Cols("status").Where("uid=? AND deleted=? AND status=?", record.Uid, false, record.Status).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTaxRecordNotFound
+		}
+
+		return saveStatusChangeInSession(sess, record.Uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, record.TaxId, byte(record.Status), byte(newStatus), record.DueDate, record.PaidAmount, record.PaidAmount, true, now)
+	})
+}
@@ -0,0 +1,256 @@
+// tax_regimes.go provides the tax regimes of user or CFO, and generates tax records from the taxes computed by them.
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TaxRegimeService represents tax regime service
+type TaxRegimeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a tax regime service singleton instance
+var (
+	TaxRegimes = &TaxRegimeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllTaxRegimesByUid returns all tax regime models of user
+func (s *TaxRegimeService) GetAllTaxRegimesByUid(c core.Context, uid int64) ([]*models.TaxRegime, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var regimes []*models.TaxRegime
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).OrderBy("cfo_id asc, tax_type asc").Find(&regimes)
+
+	return regimes, err
+}
+
+// GetTaxRegime returns the tax regime model of the tax type of user or CFO
+func (s *TaxRegimeService) GetTaxRegime(c core.Context, uid int64, cfoId int64, taxType models.TaxType) (*models.TaxRegime, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	regime := &models.TaxRegime{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND cfo_id=? AND tax_type=?", uid, cfoId, taxType).Get(regime)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTaxRegimeNotFound
+	}
+
+	return regime, nil
+}
+
+// SaveTaxRegime saves the tax regime model to database, the existed tax regime of the same tax type of user or CFO is replaced
+func (s *TaxRegimeService) SaveTaxRegime(c core.Context, regime *models.TaxRegime) error {
+	if regime.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if regime.MinimumTaxRate > 0 && regime.RegimeType != models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES {
+		return errs.ErrTaxRegimeMinimumTaxNotSupported
+	}
+
+	regime.TaxType = regime.RegimeType.TaxType()
+	regime.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(regime.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND cfo_id=? AND tax_type=?", regime.Uid, regime.CfoId, regime.TaxType).Exist(&models.TaxRegime{})
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = sess.Cols("regime_type", "period_type", "rate", "minimum_tax_rate", "due_months_after_period", "due_day_of_month", "excluded_category_ids", "currency", "updated_unix_time").Where("uid=? AND cfo_id=? AND tax_type=?", regime.Uid, regime.CfoId, regime.TaxType).Update(regime)
+			return err
+		}
+
+		regime.CreatedUnixTime = regime.UpdatedUnixTime
+		_, err = sess.Insert(regime)
+
+		return err
+	})
+}
+
+// DeleteTaxRegime deletes the tax regime of the tax type of user or CFO from database
+func (s *TaxRegimeService) DeleteTaxRegime(c core.Context, uid int64, cfoId int64, taxType models.TaxType) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND cfo_id=? AND tax_type=?", uid, cfoId, taxType).Delete(&models.TaxRegime{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTaxRegimeNotFound
+		}
+
+		return nil
+	})
+}
+
+// CalculateTax computes the tax of the period by the tax regime of the tax type of user or CFO,
+// and compares the computed tax amount with the stored tax record of the period if it exists
+func (s *TaxRegimeService) CalculateTax(c core.Context, uid int64, cfoId int64, taxType models.TaxType, periodYear int32, periodQuarter int32, timezone *time.Location) (*models.TaxCalculationResponse, error) {
+	regime, err := s.GetTaxRegime(c, uid, cfoId, taxType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	calculation, err := calculateTaxByRegime(c, s.UserDataDB(uid), regime, periodYear, periodQuarter, timezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.TaxRecord{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND cfo_id=? AND tax_type=? AND period_year=? AND period_quarter=?", uid, false, cfoId, taxType, periodYear, periodQuarter).Get(record)
+
+	if err != nil {
+		return nil, err
+	} else if has {
+		fillStoredTaxRecord(calculation, record)
+	}
+
+	return calculation, nil
+}
+
+// GenerateTaxRecord computes the tax of the period by the tax regime of the tax type of user or CFO, and saves the taxable income,
+// tax amount and due date to the tax record of the period, the tax record is created if it does not exist
+func (s *TaxRegimeService) GenerateTaxRecord(c core.Context, uid int64, cfoId int64, taxType models.TaxType, periodYear int32, periodQuarter int32, timezone *time.Location) (*models.TaxRecord, *models.TaxCalculationResponse, error) {
+	regime, err := s.GetTaxRegime(c, uid, cfoId, taxType)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calculation, err := calculateTaxByRegime(c, s.UserDataDB(uid), regime, periodYear, periodQuarter, timezone)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.TaxRecord{}
+	now := time.Now().Unix()
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.Where("uid=? AND deleted=? AND cfo_id=? AND tax_type=? AND period_year=? AND period_quarter=?", uid, false, cfoId, taxType, periodYear, periodQuarter).Get(record)
+
+		if err != nil {
+			return err
+		}
+
+		oldStatus := record.Status
+
+		record.TaxableIncome = calculation.TaxableIncome
+		record.TaxAmount = calculation.TaxAmount
+		record.DueDate = calculation.DueDate
+		record.Currency = calculation.Currency
+		record.UpdatedUnixTime = now
+
+		if has {
+			// the paid tax record becomes unpaid again if its paid amount does not cover the regenerated tax amount
+			record.Status = getTaxRecordStatusAsOf(record, now*1000)
+			_, err = sess.ID(record.TaxId).Cols("taxable_income", "tax_amount", "due_date", "currency", "status", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(record)
+
+			if err != nil {
+				return err
+			}
+
+			return saveStatusChangeInSession(sess, uid, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, record.TaxId, byte(oldStatus), byte(record.Status), record.DueDate, record.PaidAmount, record.PaidAmount, false, now)
+		}
+
+		record.TaxId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+
+		if record.TaxId < 1 {
+			return errs.ErrSystemIsBusy
+		}
+
+		record.Uid = uid
+		record.CfoId = cfoId
+		record.TaxType = taxType
+		record.PeriodYear = periodYear
+		record.PeriodQuarter = periodQuarter
+		record.Status = models.TAX_STATUS_PENDING
+		record.Comment = fmt.Sprintf("%s for %s", regime.RegimeType, getTaxPeriodName(periodYear, periodQuarter))
+		record.CreatedUnixTime = now
+
+		_, err = sess.Insert(record)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fillStoredTaxRecord(calculation, record)
+
+	return record, calculation, nil
+}
+
+// CalculateTaxRecords computes the taxes of the periods of tax records by the tax regimes of user or CFO, and returns the tax calculations
+// by tax record id, the tax records which have no tax regime of the same tax type and period type are omitted
+func (s *TaxRegimeService) CalculateTaxRecords(c core.Context, uid int64, records []*models.TaxRecord, timezone *time.Location) (map[int64]*models.TaxCalculationResponse, error) {
+	regimes, err := s.GetAllTaxRegimesByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	regimesByKey := make(map[taxPeriodKey]*models.TaxRegime, len(regimes))
+
+	for _, regime := range regimes {
+		regimesByKey[taxPeriodKey{cfoId: regime.CfoId, taxType: regime.TaxType}] = regime
+	}
+
+	calculations := make(map[int64]*models.TaxCalculationResponse)
+
+	for _, record := range records {
+		regime, exists := regimesByKey[taxPeriodKey{cfoId: record.CfoId, taxType: record.TaxType}]
+
+		if !exists {
+			continue
+		}
+
+		if _, _, err := getTaxPeriodRange(regime.PeriodType, record.PeriodYear, record.PeriodQuarter, timezone); err != nil {
+			continue
+		}
+
+		calculation, err := calculateTaxByRegime(c, s.UserDataDB(uid), regime, record.PeriodYear, record.PeriodQuarter, timezone)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fillStoredTaxRecord(calculation, record)
+		calculations[record.TaxId] = calculation
+	}
+
+	return calculations, nil
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const (
+	testTaxUsdAccountId            = int64(11)
+	testTaxEurAccountId            = int64(12)
+	testTaxSalesCategoryId         = int64(21)
+	testTaxMaterialsCategoryId     = int64(22)
+	testTaxLoanCategoryId          = int64(23)
+	testTaxContributionsCategoryId = int64(24)
+	testTaxOwnerCategoryId         = int64(25)
+	testTaxCfoId                   = int64(7)
+)
+
+func newTestTaxRegimeService(t *testing.T) (*TaxRegimeService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	uuidContainer := initUuidContainer(t)
+	svc := &TaxRegimeService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: uuidContainer},
+	}
+	return svc, tdb
+}
+
+func seedTestTaxLedger(t *testing.T, tdb *testDB) {
+	t.Helper()
+
+	accounts := []*models.Account{
+		{AccountId: testTaxUsdAccountId, Uid: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		{AccountId: testTaxEurAccountId, Uid: 1, Name: "Euro Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "EUR"},
+	}
+
+	for _, account := range accounts {
+		_, err := tdb.engine.Insert(account)
+		assert.Nil(t, err)
+	}
+
+	categories := []*models.TransactionCategory{
+		{CategoryId: testTaxSalesCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Sales"},
+		{CategoryId: testTaxMaterialsCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Materials"},
+		{CategoryId: testTaxLoanCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Loan Repayment"},
+		{CategoryId: testTaxOwnerCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Owner"},
+		{CategoryId: testTaxContributionsCategoryId, Uid: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Contributions", ParentCategoryId: testTaxOwnerCategoryId},
+	}
+
+	for _, category := range categories {
+		_, err := tdb.engine.Insert(category)
+		assert.Nil(t, err)
+	}
+
+	q1Time := time.Date(2025, 2, 15, 12, 0, 0, 0, time.UTC).Unix()
+	q2Time := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC).Unix()
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time), Amount: 900000},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 1), Amount: 100000, CfoId: testTaxCfoId},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: testTaxMaterialsCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 2), Amount: 600000},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: testTaxLoanCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 3), Amount: 100000},
+		{TransactionId: 1005, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxContributionsCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 4), Amount: 200000},
+		{TransactionId: 1006, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxEurAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 5), Amount: 50000},
+		{TransactionId: 1007, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q1Time + 6), Amount: 70000, Planned: true},
+		{TransactionId: 1008, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q2Time), Amount: 300000},
+		{TransactionId: 1009, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: testTaxMaterialsCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(q2Time + 1), Amount: 290000},
+	}
+
+	for _, transaction := range transactions {
+		transaction.Uid = 1
+		_, err := tdb.engine.Insert(transaction)
+		assert.Nil(t, err)
+	}
+}
+
+func saveTestTaxRegime(t *testing.T, svc *TaxRegimeService, cfoId int64, regimeType models.TaxRegimeType, rate int32, minimumTaxRate int32) *models.TaxRegime {
+	t.Helper()
+	regime := &models.TaxRegime{
+		Uid:                  1,
+		CfoId:                cfoId,
+		RegimeType:           regimeType,
+		PeriodType:           models.TAX_REGIME_PERIOD_TYPE_QUARTERLY,
+		Rate:                 rate,
+		MinimumTaxRate:       minimumTaxRate,
+		DueMonthsAfterPeriod: 1,
+		DueDayOfMonth:        28,
+		ExcludedCategoryIds:  "23,25",
+		Currency:             "USD",
+	}
+	assert.Nil(t, svc.SaveTaxRegime(nil, regime))
+	return regime
+}
+
+func TestTaxRegimeServiceSaveReplacesRegimeOfSameTaxType(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES, 1500, 100)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_VAT, 2000, 0)
+
+	regimes, err := svc.GetAllTaxRegimesByUid(nil, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(regimes))
+	assert.Equal(t, models.TAX_TYPE_INCOME, regimes[0].TaxType)
+	assert.Equal(t, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES, regimes[0].RegimeType)
+	assert.Equal(t, int32(100), regimes[0].MinimumTaxRate)
+	assert.Equal(t, models.TAX_TYPE_VAT, regimes[1].TaxType)
+
+	err = svc.SaveTaxRegime(nil, &models.TaxRegime{Uid: 1, RegimeType: models.TAX_REGIME_TYPE_VAT, PeriodType: models.TAX_REGIME_PERIOD_TYPE_QUARTERLY, Rate: 2000, MinimumTaxRate: 100, Currency: "USD"})
+	assert.Equal(t, errs.ErrTaxRegimeMinimumTaxNotSupported, err)
+
+	assert.Nil(t, svc.DeleteTaxRegime(nil, 1, 0, models.TAX_TYPE_VAT))
+	assert.Equal(t, errs.ErrTaxRegimeNotFound, svc.DeleteTaxRegime(nil, 1, 0, models.TAX_TYPE_VAT))
+
+	_, err = svc.GetTaxRegime(nil, 1, 0, models.TAX_TYPE_VAT)
+	assert.Equal(t, errs.ErrTaxRegimeNotFound, err)
+}
+
+func TestTaxRegimeServiceCalculateSimplifiedIncomeTax(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+	saveTestTaxRegime(t, svc, testTaxCfoId, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+
+	// the excluded categories, the sub-categories of excluded categories, the planned transactions and the transactions in other currencies are not counted
+	calculation, err := svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000000), calculation.TaxableIncome)
+	assert.Equal(t, int64(60000), calculation.TaxAmount)
+	assert.Equal(t, time.Date(2025, 4, 28, 0, 0, 0, 0, time.UTC).UnixMilli(), calculation.DueDate)
+	assert.Equal(t, []string{"Transactions in EUR are not counted"}, calculation.Warnings)
+	assert.Equal(t, int64(0), calculation.TaxRecordId)
+
+	// the tax regime of CFO only counts the transactions of that CFO
+	calculation, err = svc.CalculateTax(nil, 1, testTaxCfoId, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100000), calculation.TaxableIncome)
+	assert.Equal(t, int64(6000), calculation.TaxAmount)
+
+	_, err = svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 0, time.UTC)
+	assert.Equal(t, errs.ErrTaxPeriodInvalid, err)
+
+	_, err = svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_VAT, 2025, 1, time.UTC)
+	assert.Equal(t, errs.ErrTaxRegimeNotFound, err)
+}
+
+func TestTaxRegimeServiceCalculateIncomeMinusExpensesTaxWithMinimumTax(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME_MINUS_EXPENSES, 1500, 100)
+
+	calculation, err := svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(400000), calculation.TaxableIncome)
+	assert.Equal(t, int64(60000), calculation.TaxAmount)
+	assert.Equal(t, []*models.TaxCalculationLine{
+		{Label: models.TaxCalculationLabelIncome, Amount: 1000000},
+		{Label: models.TaxCalculationLabelExpenses, Amount: 600000},
+		{Label: models.TaxCalculationLabelTaxBase, Amount: 400000},
+		{Label: models.TaxCalculationLabelTaxAtRate, Amount: 60000},
+		{Label: models.TaxCalculationLabelTaxAmount, Amount: 60000},
+	}, calculation.Lines)
+
+	// the tax of the second quarter is computed since the beginning of the year, and the advance of the first quarter is deducted
+	calculation, err = svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 2, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(410000), calculation.TaxableIncome)
+	assert.Equal(t, int64(1500), calculation.TaxAmount)
+	assert.Equal(t, []*models.TaxCalculationLine{
+		{Label: models.TaxCalculationLabelIncome, Amount: 1300000},
+		{Label: models.TaxCalculationLabelExpenses, Amount: 890000},
+		{Label: models.TaxCalculationLabelTaxBase, Amount: 410000},
+		{Label: models.TaxCalculationLabelTaxAtRate, Amount: 61500},
+		{Label: models.TaxCalculationLabelAdvancePayments, Amount: 60000},
+		{Label: models.TaxCalculationLabelTaxAmount, Amount: 1500},
+	}, calculation.Lines)
+
+	// the minimum tax on income of the whole year applies to the last quarter when it exceeds the tax on income minus expenses
+	_, err = tdb.engine.Insert(&models.Transaction{TransactionId: 1010, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: testTaxMaterialsCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC).Unix()), Amount: 400000})
+	assert.Nil(t, err)
+
+	calculation, err = svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 4, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10000), calculation.TaxableIncome)
+	assert.Equal(t, int64(0), calculation.TaxAmount)
+	assert.Equal(t, []*models.TaxCalculationLine{
+		{Label: models.TaxCalculationLabelIncome, Amount: 1300000},
+		{Label: models.TaxCalculationLabelExpenses, Amount: 1290000},
+		{Label: models.TaxCalculationLabelTaxBase, Amount: 10000},
+		{Label: models.TaxCalculationLabelTaxAtRate, Amount: 1500},
+		{Label: models.TaxCalculationLabelMinimumTax, Amount: 13000},
+		{Label: models.TaxCalculationLabelAdvancePayments, Amount: 61500},
+		{Label: models.TaxCalculationLabelTaxAmount, Amount: 0},
+	}, calculation.Lines)
+}
+
+func TestGetTaxDueDate_ClampDueDayToLastDayOfMonth(t *testing.T) {
+	regime := &models.TaxRegime{DueMonthsAfterPeriod: 2, DueDayOfMonth: 31}
+
+	// the tax of the fourth quarter is due in February
+	dueDate := getTaxDueDate(regime, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), dueDate)
+
+	dueDate = getTaxDueDate(regime, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), dueDate)
+}
+
+func TestTaxRegimeServiceCalculateVat(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_VAT, 2000, 0)
+
+	calculation, err := svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_VAT, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000000-166667), calculation.TaxableIncome)
+	assert.Equal(t, int64(166667-100000), calculation.TaxAmount)
+	assert.Equal(t, []*models.TaxCalculationLine{
+		{Label: models.TaxCalculationLabelIncome, Amount: 1000000},
+		{Label: models.TaxCalculationLabelOutputVat, Amount: 166667},
+		{Label: models.TaxCalculationLabelExpenses, Amount: 600000},
+		{Label: models.TaxCalculationLabelInputVat, Amount: 100000},
+		{Label: models.TaxCalculationLabelTaxAmount, Amount: 66667},
+	}, calculation.Lines)
+
+	// the input VAT exceeding the output VAT is refundable
+	_, err = tdb.engine.Insert(&models.Transaction{TransactionId: 1010, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: testTaxMaterialsCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Unix()), Amount: 120000})
+	assert.Nil(t, err)
+
+	calculation, err = svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_VAT, 2025, 2, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), calculation.TaxAmount)
+	assert.Equal(t, &models.TaxCalculationLine{Label: models.TaxCalculationLabelVatRefundable, Amount: 68333 - 50000}, calculation.Lines[4])
+}
+
+func TestTaxRegimeServiceCalculatePropertyTax(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	_, err := tdb.engine.Insert([]*models.Account{
+		{AccountId: testTaxUsdAccountId, Uid: 1, Name: "Equipment", Category: models.ACCOUNT_CATEGORY_INVESTMENT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		{AccountId: testTaxEurAccountId, Uid: 1, Name: "Euro Equipment", Category: models.ACCOUNT_CATEGORY_INVESTMENT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "EUR"},
+	})
+	assert.Nil(t, err)
+
+	_, err = tdb.engine.Insert(&models.Asset{
+		AssetId:          501,
+		Uid:              1,
+		Name:             "Lathe",
+		AccountId:        testTaxUsdAccountId,
+		Status:           models.ASSET_STATUS_ACTIVE,
+		PurchaseDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		PurchaseCost:     120000,
+		UsefulLifeMonths: 12,
+		CommissionDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	})
+	assert.Nil(t, err)
+
+	_, err = tdb.engine.Insert(&models.Asset{
+		AssetId:      502,
+		Uid:          1,
+		Name:         "Warehouse",
+		Status:       models.ASSET_STATUS_ACTIVE,
+		PurchaseDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		PurchaseCost: 1000000,
+	})
+	assert.Nil(t, err)
+
+	// the book account of asset is in other currency than the tax regime
+	_, err = tdb.engine.Insert(&models.Asset{
+		AssetId:      503,
+		Uid:          1,
+		Name:         "Euro Press",
+		Status:       models.ASSET_STATUS_ACTIVE,
+		PurchaseDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		PurchaseCost: 500000,
+		AccountId:    testTaxEurAccountId,
+	})
+	assert.Nil(t, err)
+
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_PROPERTY, 220, 0)
+
+	// the average of book values on January 1, February 1, March 1 and April 1, and the annual rate is prorated by 3 months
+	calculation, err := svc.CalculateTax(nil, 1, 0, models.TAX_TYPE_PROPERTY, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64((120000+110000+100000+90000)/4), calculation.TaxableIncome)
+	assert.Equal(t, int64(578), calculation.TaxAmount)
+	assert.Equal(t, []string{"Assets in EUR are not counted"}, calculation.Warnings)
+}
+
+func TestTaxRegimeServiceGenerateTaxRecordAndFlagDivergence(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+
+	record, calculation, err := svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.True(t, record.TaxId > 0)
+	assert.Equal(t, int64(60000), record.TaxAmount)
+	assert.Equal(t, int64(1000000), record.TaxableIncome)
+	assert.Equal(t, time.Date(2025, 4, 28, 0, 0, 0, 0, time.UTC).UnixMilli(), record.DueDate)
+	assert.Equal(t, models.TAX_STATUS_PENDING, record.Status)
+	assert.Equal(t, "USD", record.Currency)
+	assert.Equal(t, "Simplified tax on income for 2025 Q1", record.Comment)
+	assert.Equal(t, record.TaxId, calculation.TaxRecordId)
+	assert.False(t, calculation.Diverged)
+
+	_, err = tdb.engine.ID(record.TaxId).Cols("tax_amount", "paid_amount").Update(&models.TaxRecord{TaxAmount: 55000, PaidAmount: 55000})
+	assert.Nil(t, err)
+
+	var records []*models.TaxRecord
+	assert.Nil(t, tdb.engine.Where("uid=?", 1).Find(&records))
+
+	calculations, err := svc.CalculateTaxRecords(nil, 1, records, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(60000), calculations[record.TaxId].TaxAmount)
+	assert.Equal(t, int64(55000), calculations[record.TaxId].StoredTaxAmount)
+	assert.True(t, calculations[record.TaxId].Diverged)
+
+	// regenerating updates the computed amounts of the same tax record and keeps the paid amount
+	regeneratedRecord, _, err := svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, record.TaxId, regeneratedRecord.TaxId)
+
+	records = nil
+	assert.Nil(t, tdb.engine.Where("uid=?", 1).Find(&records))
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, int64(60000), records[0].TaxAmount)
+	assert.Equal(t, int64(55000), records[0].PaidAmount)
+}
+
+func TestTaxRegimeServiceRegeneratePaidTaxRecord(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+
+	record, _, err := svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+
+	_, err = tdb.engine.ID(record.TaxId).Cols("status", "paid_amount").Update(&models.TaxRecord{Status: models.TAX_STATUS_PAID, PaidAmount: 60000})
+	assert.Nil(t, err)
+
+	// the paid tax record stays paid while its paid amount covers the regenerated tax amount
+	record, _, err = svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TAX_STATUS_PAID, record.Status)
+
+	_, err = tdb.engine.Insert(&models.Transaction{TransactionId: 1010, Uid: 1, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: testTaxSalesCategoryId, AccountId: testTaxUsdAccountId, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).Unix()), Amount: 100000})
+	assert.Nil(t, err)
+
+	// the tax record is overdue after the due date once the regenerated tax amount exceeds its paid amount
+	record, _, err = svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(66000), record.TaxAmount)
+	assert.Equal(t, int64(60000), record.PaidAmount)
+	assert.Equal(t, models.TAX_STATUS_OVERDUE, record.Status)
+
+	histories, err := getStatusHistories(tdb.engine.NewSession(), 1, models.STATUS_HISTORY_ENTITY_TYPE_TAX_RECORD, record.TaxId)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TAX_STATUS_PAID, models.TaxStatus(histories[len(histories)-1].OldStatus))
+	assert.Equal(t, models.TAX_STATUS_OVERDUE, models.TaxStatus(histories[len(histories)-1].NewStatus))
+}
+
+func TestTaxRegimeServiceScheduleTaxesIntoPaymentCalendar(t *testing.T) {
+	svc, tdb := newTestTaxRegimeService(t)
+	defer tdb.close()
+
+	seedTestTaxLedger(t, tdb)
+	saveTestTaxRegime(t, svc, 0, models.TAX_REGIME_TYPE_SIMPLIFIED_INCOME, 600, 0)
+
+	reportSvc := &ReportService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+		assets:         &mockAssetProvider{},
+		taxes:          &mockTaxRecordProvider{},
+		deals:          &mockInvestorDealProvider{},
+		payments:       &mockInvestorPaymentProvider{},
+	}
+
+	startTime := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC).UnixMilli()
+	endTime := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	calendar, err := reportSvc.GetPaymentCalendar(nil, 1, startTime, endTime, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(calendar.Items))
+	assert.Equal(t, models.PaymentTypeEstimatedTax, calendar.Items[0].Type)
+	assert.Equal(t, int64(60000), calendar.Items[0].Amount)
+	assert.Equal(t, "Simplified tax on income for 2025 Q1", calendar.Items[0].Description)
+	assert.Equal(t, int64(18000), calendar.Items[1].Amount)
+	assert.Equal(t, "Simplified tax on income for 2025 Q2", calendar.Items[1].Description)
+
+	// the periods which have tax records are scheduled by the tax records
+	_, _, err = svc.GenerateTaxRecord(nil, 1, 0, models.TAX_TYPE_INCOME, 2025, 1, time.UTC)
+	assert.Nil(t, err)
+
+	calendar, err = reportSvc.GetPaymentCalendar(nil, 1, startTime, endTime, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(calendar.Items))
+	assert.Equal(t, models.PaymentTypeTax, calendar.Items[0].Type)
+	assert.Equal(t, int64(60000), calendar.Items[0].Amount)
+	assert.Equal(t, models.PaymentTypeEstimatedTax, calendar.Items[1].Type)
+}
@@ -30,14 +30,28 @@ func newTestDB(t *testing.T) *testDB {
 		new(models.Transaction),
 		new(models.TransactionCategory),
 		new(models.Account),
+		new(models.TransactionTag),
+		new(models.TransactionTagIndex),
+		new(models.TransactionSearchIndex),
+		new(models.TransactionPictureInfo),
 		new(models.Asset),
+		new(models.AssetTransaction),
+		new(models.InvestorScheduleTransaction),
 		new(models.Obligation),
+		new(models.ObligationPayment),
+		new(models.Counterparty),
 		new(models.TaxRecord),
+		new(models.TaxRegime),
+		new(models.StatusHistory),
+		new(models.NotificationPreference),
+		new(models.User),
 		new(models.CFO),
 		new(models.Location),
 		new(models.Budget),
 		new(models.InvestorDeal),
 		new(models.InvestorPayment),
+		new(models.ExchangeRateHistory),
+		new(models.MCPAuditLog),
 	)
 	if err != nil {
 		t.Fatalf("failed to sync tables: %v", err)
@@ -23,6 +23,20 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		return errs.ErrUserIdInvalid
 	}
 
+	userDataDb := s.UserDataDB(transaction.Uid)
+
+	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		return s.CreateTransactionWithTagsInSession(c, userDataDb, sess, transaction, tagIds, pictureIds, splitRequests...)
+	})
+}
+
+// CreateTransactionWithTagsInSession saves a new transaction of any type with its tags, pictures and splits to database in the given session,
+// unlike CreateTransactionInSession it is not limited to income and expense transactions
+func (s *TransactionService) CreateTransactionWithTagsInSession(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splitRequests ...[]models.TransactionSplitCreateRequest) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	// Check whether account id is valid
 	err := s.isAccountIdValid(transaction)
 
@@ -82,20 +96,53 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		UpdatedUnixTime: now,
 	}
 
-	userDataDb := s.UserDataDB(transaction.Uid)
+	err = s.doCreateTransaction(c, database, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+	if err != nil {
+		return err
+	}
 
-	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
-		if err != nil {
-			return err
-		}
+	// Create splits atomically within the same transaction
+	if len(splitRequests) > 0 && len(splitRequests[0]) > 0 {
+		return TransactionSplits.CreateSplitsInSession(sess, transaction.Uid, transaction.TransactionId, splitRequests[0])
+	}
+	return nil
+}
 
-		// Create splits atomically within the same transaction
-		if len(splitRequests) > 0 && len(splitRequests[0]) > 0 {
-			return TransactionSplits.CreateSplitsInSession(sess, transaction.Uid, transaction.TransactionId, splitRequests[0])
-		}
-		return nil
-	})
+// CreateTransactionInSession saves a new income or expense transaction without tags and pictures to database in the given session,
+// so other services can create transactions together with their own data in one database transaction
+func (s *TransactionService) CreateTransactionInSession(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	err := s.isAccountIdValid(transaction)
+
+	if err != nil {
+		return err
+	}
+
+	transaction.TransactionId = s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION)
+
+	if transaction.TransactionId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+	transaction.CreatedUnixTime = now
+	transaction.UpdatedUnixTime = now
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		TransactionId:   transaction.TransactionId,
+		UpdatedUnixTime: now,
+	}
+
+	return s.doCreateTransaction(c, database, sess, transaction, nil, nil, nil, pictureUpdateModel)
 }
 
 func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
@@ -269,6 +316,14 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
+	// Insert transaction search index
+	err = TransactionSearchIndexes.CreateSearchIndexesInSession(sess, transaction.Uid, []int64{transaction.TransactionId}, transaction.Comment)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction search index, because %s", err.Error())
+		return err
+	}
+
 	// Update transaction picture
 	if len(pictureIds) > 0 {
 		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
@@ -19,6 +19,17 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		return errs.ErrUserIdInvalid
 	}
 
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.DeleteTransactionInSession(c, sess, uid, transactionId)
+	})
+}
+
+// DeleteTransactionInSession deletes an existed transaction and reverts its account balance in the given session
+func (s *TransactionService) DeleteTransactionInSession(c core.Context, sess *xorm.Session, uid int64, transactionId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Transaction{
@@ -36,135 +47,160 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify current transaction
-		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+	// Get and verify current transaction
+	oldTransaction := &models.Transaction{}
+	has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
 
-		if err != nil {
-			return err
-		} else if !has {
-			return errs.ErrTransactionNotFound
-		}
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionNotFound
+	}
 
-		// Get and verify source and destination account
-		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
+	// Get and verify source and destination account
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-			return errs.ErrCannotDeleteTransactionInHiddenAccount
-		}
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return errs.ErrCannotDeleteTransactionInHiddenAccount
+	}
 
-		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
-			return errs.ErrCannotDeleteTransactionInParentAccount
-		}
+	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+		return errs.ErrCannotDeleteTransactionInParentAccount
+	}
 
-		// Update transaction row to deleted
-		deletedRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+	// Update transaction row to deleted
+	deletedRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrTransactionNotFound
+	}
+
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
 		if err != nil {
 			return err
 		} else if deletedRows < 1 {
 			return errs.ErrTransactionNotFound
 		}
+	}
 
-		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+	// Update transaction tag index
+	_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
 
-			if err != nil {
-				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionNotFound
-			}
-		}
+	if err != nil {
+		return err
+	}
 
-		// Update transaction tag index
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
+	// Delete transaction search index
+	searchIndexTransactionIds := []int64{oldTransaction.TransactionId}
 
-		if err != nil {
-			return err
-		}
+	if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		searchIndexTransactionIds = append(searchIndexTransactionIds, oldTransaction.RelatedId)
+	}
 
-		// Update transaction picture
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
+	err = TransactionSearchIndexes.DeleteSearchIndexesInSession(sess, uid, searchIndexTransactionIds)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
+
+	// Update transaction picture
+	_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	// Update obligation payments
+	err = deleteObligationPaymentsOfTransaction(sess, uid, oldTransaction.TransactionId, now)
+
+	if err != nil {
+		return err
+	}
+
+	// Update asset transactions
+	err = deleteAssetTransactionsOfTransaction(sess, uid, oldTransaction.TransactionId, now)
 
-		// Update account table (skip balance update for planned/future transactions)
-		if !oldTransaction.Planned {
-			switch oldTransaction.Type {
-			case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
-				if oldTransaction.RelatedAccountAmount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+	if err != nil {
+		return err
+	}
+
+	// Update account table (skip balance update for planned/future transactions)
+	if !oldTransaction.Planned {
+		switch oldTransaction.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			if oldTransaction.RelatedAccountAmount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
-			case models.TRANSACTION_DB_TYPE_INCOME:
-				if oldTransaction.Amount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+			}
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
-			case models.TRANSACTION_DB_TYPE_EXPENSE:
-				if oldTransaction.Amount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+			}
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
-			case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
-				if oldTransaction.Amount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						return err
-					} else if updatedSourceRows < 1 {
-						log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+			}
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedSourceRows < 1 {
+					log.Errorf(c, "[transactions.DeleteTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
+			}
 
-				if oldTransaction.RelatedAccountAmount != 0 {
-					destinationAccount.UpdatedUnixTime = now
-					updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+			if oldTransaction.RelatedAccountAmount != 0 {
+				destinationAccount.UpdatedUnixTime = now
+				updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
 
-					if err != nil {
-						return err
-					} else if updatedDestinationRows < 1 {
-						log.Errorf(c, "[transactions.DeleteTransaction] failed to update related account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+				if err != nil {
+					return err
+				} else if updatedDestinationRows < 1 {
+					log.Errorf(c, "[transactions.DeleteTransaction] failed to update related account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
-			case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
-				return errs.ErrTransactionTypeInvalid
 			}
+		case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+			return errs.ErrTransactionTypeInvalid
 		}
+	}
 
-		return err
-	})
+	return err
 }
 
 // DeleteAllTransactions deletes all existed transactions from database
@@ -211,6 +247,13 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 			return err
 		}
 
+		// Delete all transaction search index
+		err = TransactionSearchIndexes.DeleteSearchIndexesInSession(sess, uid, nil)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all transaction pictures to deleted
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(pictureUpdateModel)
 
@@ -304,6 +347,15 @@ func (s *TransactionService) DeleteAllFuturePlannedTransactions(c core.Context,
 			DeletedUnixTime: now,
 		}
 
+		var deletedTransactions []*models.Transaction
+		err = sess.Cols("transaction_id").Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
+			uid, false, sourceTransaction.SourceTemplateId, sourceTransaction.TransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
+			Find(&deletedTransactions)
+
+		if err != nil {
+			return err
+		}
+
 		// Delete planned transactions AND their TRANSFER_IN counterparts (which may have planned=false from legacy bug)
 		affectedCount, err = sess.Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
 			uid, false, sourceTransaction.SourceTemplateId, sourceTransaction.TransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
@@ -311,7 +363,11 @@ func (s *TransactionService) DeleteAllFuturePlannedTransactions(c core.Context,
 
 		log.Infof(c, "[transactions.DeleteAllFuturePlannedTransactions] delete result: affectedCount=%d, err=%v", affectedCount, err)
 
-		return err
+		if err != nil {
+			return err
+		}
+
+		return TransactionSearchIndexes.DeleteSearchIndexesInSession(sess, uid, s.GetTransactionIds(deletedTransactions))
 	})
 
 	if err != nil {
@@ -334,10 +390,28 @@ func (s *TransactionService) DeleteAllPlannedTransactionsByTemplate(c core.Conte
 		DeletedUnixTime: now,
 	}
 
-	affectedCount, err := s.UserDataDB(uid).NewSession(c).
-		Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
+	var affectedCount int64
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var deletedTransactions []*models.Transaction
+		err := sess.Cols("transaction_id").Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
+			uid, false, templateId, minTransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
+			Find(&deletedTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		affectedCount, err = sess.Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
 			uid, false, templateId, minTransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
-		Cols("deleted", "deleted_unix_time").Update(updateModel)
+			Cols("deleted", "deleted_unix_time").Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return TransactionSearchIndexes.DeleteSearchIndexesInSession(sess, uid, s.GetTransactionIds(deletedTransactions))
+	})
 
 	if err != nil {
 		return 0, err
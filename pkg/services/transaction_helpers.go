@@ -199,45 +199,8 @@ func (s *TransactionService) buildTransactionQueryCondition(params *models.Trans
 
 	// Amount filter
 	if amountFilter != "" {
-		amountFilterItems := strings.Split(amountFilter, ":")
-
-		if len(amountFilterItems) >= 2 {
-			switch amountFilterItems[0] {
-			case "gt":
-				if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
-					cond = cond.And(builder.Gt{"amount": value})
-				}
-			case "lt":
-				if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
-					cond = cond.And(builder.Lt{"amount": value})
-				}
-			case "eq":
-				if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
-					cond = cond.And(builder.Eq{"amount": value})
-				}
-			case "ne":
-				if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
-					cond = cond.And(builder.Neq{"amount": value})
-				}
-			case "bt":
-				if len(amountFilterItems) == 3 {
-					value1, err := utils.StringToInt64(amountFilterItems[1])
-					value2, err2 := utils.StringToInt64(amountFilterItems[2])
-
-					if err == nil && err2 == nil {
-						cond = cond.And(builder.Gte{"amount": value1}, builder.Lte{"amount": value2})
-					}
-				}
-			case "nb":
-				if len(amountFilterItems) == 3 {
-					value1, err := utils.StringToInt64(amountFilterItems[1])
-					value2, err2 := utils.StringToInt64(amountFilterItems[2])
-
-					if err == nil && err2 == nil {
-						cond = cond.And(builder.Or(builder.Lt{"amount": value1}, builder.Gt{"amount": value2}))
-					}
-				}
-			}
+		if amountCond := s.getTransactionAmountFilterCondition(amountFilter); amountCond != nil {
+			cond = cond.And(amountCond)
 		}
 	}
 
@@ -246,14 +209,258 @@ func (s *TransactionService) buildTransactionQueryCondition(params *models.Trans
 		cond = cond.And(builder.Like{"comment", "%" + keyword + "%"})
 	}
 
+	// Comment filter
+	if params.CommentFilter != nil {
+		cond = cond.And(s.getTransactionCommentFilterCondition(params.CommentFilter))
+	}
+
 	// Counterparty filter
 	if params.CounterpartyId > 0 {
 		cond = cond.And(builder.Eq{"counterparty_id": params.CounterpartyId})
 	}
 
+	// Cfo filter
+	if params.CfoId > 0 {
+		cond = cond.And(builder.Eq{"cfo_id": params.CfoId})
+	}
+
+	// Planned filter
+	if plannedCond := s.getTransactionPlannedFilterCondition(params.PlannedFilter); plannedCond != nil {
+		cond = cond.And(plannedCond)
+	}
+
+	// Source template filter
+	if params.SourceTemplateId > 0 {
+		cond = cond.And(builder.Eq{"source_template_id": params.SourceTemplateId})
+	}
+
+	// Geographic bounds filter
+	if params.GeoBounds != nil {
+		cond = cond.And(s.getTransactionGeoBoundsFilterCondition(params.GeoBounds))
+	}
+
+	// Condition groups filter, the transaction matches when it matches any group
+	if len(params.ConditionGroups) > 0 {
+		groupConds := make([]builder.Cond, 0, len(params.ConditionGroups))
+
+		for i := 0; i < len(params.ConditionGroups); i++ {
+			if params.ConditionGroups[i] == nil {
+				continue
+			}
+
+			groupConds = append(groupConds, s.getTransactionQueryConditionGroupCondition(params.ConditionGroups[i]))
+		}
+
+		if len(groupConds) > 0 {
+			cond = cond.And(builder.Or(groupConds...))
+		}
+	}
+
+	// Full-text search filter, the transaction matches when it matches all search terms
+	for i := 0; i < len(params.SearchTerms); i++ {
+		if params.SearchTerms[i] == nil || len(params.SearchTerms[i].Tokens) < 1 {
+			continue
+		}
+
+		cond = cond.And(s.getTransactionSearchTermCondition(uid, params.SearchTerms[i]))
+	}
+
+	return cond
+}
+
+func (s *TransactionService) getTransactionQueryConditionGroupCondition(conditionGroup *models.TransactionQueryConditionGroup) builder.Cond {
+	cond := builder.NewCond()
+
+	switch conditionGroup.TransactionType {
+	case models.TRANSACTION_TYPE_MODIFY_BALANCE, models.TRANSACTION_TYPE_INCOME, models.TRANSACTION_TYPE_EXPENSE:
+		if transactionDbType, err := conditionGroup.TransactionType.ToTransactionDbType(); err == nil {
+			cond = cond.And(builder.Eq{"type": transactionDbType})
+		}
+	case models.TRANSACTION_TYPE_TRANSFER:
+		cond = cond.And(builder.In("type", models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN))
+	}
+
+	if len(conditionGroup.CategoryIds) > 0 {
+		cond = cond.And(builder.In("category_id", conditionGroup.CategoryIds))
+	}
+
+	if len(conditionGroup.AccountIds) > 0 {
+		cond = cond.And(builder.In("account_id", conditionGroup.AccountIds))
+	}
+
+	if conditionGroup.AmountFilter != "" {
+		if amountCond := s.getTransactionAmountFilterCondition(conditionGroup.AmountFilter); amountCond != nil {
+			cond = cond.And(amountCond)
+		}
+	}
+
+	if conditionGroup.CommentFilter != nil {
+		cond = cond.And(s.getTransactionCommentFilterCondition(conditionGroup.CommentFilter))
+	}
+
+	if conditionGroup.CounterpartyId > 0 {
+		cond = cond.And(builder.Eq{"counterparty_id": conditionGroup.CounterpartyId})
+	}
+
+	if conditionGroup.CfoId > 0 {
+		cond = cond.And(builder.Eq{"cfo_id": conditionGroup.CfoId})
+	}
+
+	if plannedCond := s.getTransactionPlannedFilterCondition(conditionGroup.PlannedFilter); plannedCond != nil {
+		cond = cond.And(plannedCond)
+	}
+
+	if conditionGroup.SourceTemplateId > 0 {
+		cond = cond.And(builder.Eq{"source_template_id": conditionGroup.SourceTemplateId})
+	}
+
+	if conditionGroup.GeoBounds != nil {
+		cond = cond.And(s.getTransactionGeoBoundsFilterCondition(conditionGroup.GeoBounds))
+	}
+
+	// an empty group matches all transactions, same as the query without condition groups
+	if !cond.IsValid() {
+		return builder.Expr("1=1")
+	}
+
 	return cond
 }
 
+// getTransactionSearchTermCondition returns the condition which matches the transactions whose comment contains the term,
+// or whose counterparty or tags are in the matched counterparties or tags of the term
+func (s *TransactionService) getTransactionSearchTermCondition(uid int64, term *models.TransactionSearchTerm) builder.Cond {
+	subQuery := builder.Select("t0.transaction_id").From("transaction_search_index", "t0")
+	subQueryCondition := builder.And(builder.Eq{"t0.uid": uid}, s.getTransactionSearchTokenCondition("t0", term, 0))
+
+	for i := 1; i < len(term.Tokens); i++ {
+		alias := fmt.Sprintf("t%d", i)
+		joinCondition := builder.And(
+			builder.Expr(fmt.Sprintf("%s.uid=t0.uid AND %s.transaction_id=t0.transaction_id AND %s.position=t0.position+%d", alias, alias, alias, i)),
+			s.getTransactionSearchTokenCondition(alias, term, i),
+		)
+		subQuery = subQuery.InnerJoin("transaction_search_index "+alias, joinCondition)
+	}
+
+	subQuery = subQuery.Where(subQueryCondition)
+	conds := []builder.Cond{
+		builder.In("transaction_id", subQuery),
+		builder.In("related_id", subQuery),
+	}
+
+	if len(term.CounterpartyIds) > 0 {
+		conds = append(conds, builder.In("counterparty_id", term.CounterpartyIds))
+	}
+
+	if len(term.TagIds) > 0 {
+		tagSubQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false}, builder.In("tag_id", term.TagIds)))
+		conds = append(conds, builder.In("transaction_id", tagSubQuery), builder.In("related_id", tagSubQuery))
+	}
+
+	return builder.Or(conds...)
+}
+
+func (s *TransactionService) getTransactionSearchTokenCondition(alias string, term *models.TransactionSearchTerm, index int) builder.Cond {
+	if index == len(term.Tokens)-1 && term.Prefix {
+		return builder.Like{alias + ".token", term.Tokens[index] + "%"}
+	}
+
+	return builder.Eq{alias + ".token": term.Tokens[index]}
+}
+
+func (s *TransactionService) getTransactionAmountFilterCondition(amountFilter string) builder.Cond {
+	amountFilterItems := strings.Split(amountFilter, ":")
+
+	if len(amountFilterItems) < 2 {
+		return nil
+	}
+
+	switch amountFilterItems[0] {
+	case "gt":
+		if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
+			return builder.Gt{"amount": value}
+		}
+	case "lt":
+		if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
+			return builder.Lt{"amount": value}
+		}
+	case "eq":
+		if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
+			return builder.Eq{"amount": value}
+		}
+	case "ne":
+		if value, err := utils.StringToInt64(amountFilterItems[1]); err == nil {
+			return builder.Neq{"amount": value}
+		}
+	case "bt":
+		if len(amountFilterItems) == 3 {
+			value1, err := utils.StringToInt64(amountFilterItems[1])
+			value2, err2 := utils.StringToInt64(amountFilterItems[2])
+
+			if err == nil && err2 == nil {
+				return builder.And(builder.Gte{"amount": value1}, builder.Lte{"amount": value2})
+			}
+		}
+	case "nb":
+		if len(amountFilterItems) == 3 {
+			value1, err := utils.StringToInt64(amountFilterItems[1])
+			value2, err2 := utils.StringToInt64(amountFilterItems[2])
+
+			if err == nil && err2 == nil {
+				return builder.Or(builder.Lt{"amount": value1}, builder.Gt{"amount": value2})
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) getTransactionCommentFilterCondition(commentFilter *models.TransactionCommentFilter) builder.Cond {
+	switch commentFilter.Type {
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_CONTAINS:
+		return builder.Like{"comment", "%" + commentFilter.Value + "%"}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_NOT_CONTAINS:
+		return builder.Not{builder.Like{"comment", "%" + commentFilter.Value + "%"}}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_EQUALS:
+		return builder.Eq{"comment": commentFilter.Value}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_NOT_EQUALS:
+		return builder.Neq{"comment": commentFilter.Value}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_STARTS_WITH:
+		return builder.Like{"comment", commentFilter.Value + "%"}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_ENDS_WITH:
+		return builder.Like{"comment", "%" + commentFilter.Value}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_EMPTY:
+		return builder.Eq{"comment": ""}
+	case models.TRANSACTION_COMMENT_FILTER_TYPE_NOT_EMPTY:
+		return builder.Neq{"comment": ""}
+	}
+
+	return builder.Expr("1=1")
+}
+
+func (s *TransactionService) getTransactionPlannedFilterCondition(plannedFilter models.TransactionPlannedFilterType) builder.Cond {
+	switch plannedFilter {
+	case models.TRANSACTION_PLANNED_FILTER_TYPE_PLANNED:
+		return builder.Eq{"planned": true}
+	case models.TRANSACTION_PLANNED_FILTER_TYPE_CONFIRMED:
+		return builder.Eq{"planned": false}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) getTransactionGeoBoundsFilterCondition(geoBounds *models.TransactionGeoBoundsFilter) builder.Cond {
+	latitudeCond := builder.And(builder.Gte{"geo_latitude": geoBounds.MinLatitude}, builder.Lte{"geo_latitude": geoBounds.MaxLatitude})
+
+	// the transactions without location are saved with zero longitude and latitude
+	locationCond := builder.Or(builder.Neq{"geo_longitude": 0}, builder.Neq{"geo_latitude": 0})
+
+	if geoBounds.MinLongitude > geoBounds.MaxLongitude {
+		return builder.And(locationCond, latitudeCond, builder.Or(builder.Gte{"geo_longitude": geoBounds.MinLongitude}, builder.Lte{"geo_longitude": geoBounds.MaxLongitude}))
+	}
+
+	return builder.And(locationCond, latitudeCond, builder.Gte{"geo_longitude": geoBounds.MinLongitude}, builder.Lte{"geo_longitude": geoBounds.MaxLongitude})
+}
+
 func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Session, uid int64, maxTransactionTime int64, minTransactionTime int64, tagFilters []*models.TransactionTagFilter, noTags bool) *xorm.Session {
 	if noTags {
 		subQueryCondition := builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false})
@@ -814,3 +814,245 @@ func TestBuildTransactionQueryCondition_AllFilters(t *testing.T) {
 	assert.Contains(t, args, int64(1000))
 	assert.Contains(t, args, "%test%")
 }
+
+func TestBuildTransactionQueryCondition_WithCfoIdAndSourceTemplateId(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid:              100,
+		CfoId:            4001,
+		SourceTemplateId: 5001,
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "cfo_id")
+	assert.Contains(t, sql, "source_template_id")
+	assert.Contains(t, args, int64(4001))
+	assert.Contains(t, args, int64(5001))
+}
+
+func TestBuildTransactionQueryCondition_WithPlannedFilter(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid:           100,
+		PlannedFilter: models.TRANSACTION_PLANNED_FILTER_TYPE_PLANNED,
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "planned=?")
+	assert.Contains(t, args, true)
+
+	params.PlannedFilter = models.TRANSACTION_PLANNED_FILTER_TYPE_ALL
+	cond = Transactions.buildTransactionQueryCondition(params)
+	sql, _, err = testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, sql, "planned")
+}
+
+func TestBuildTransactionQueryCondition_WithCommentFilter(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		CommentFilter: &models.TransactionCommentFilter{
+			Type:  models.TRANSACTION_COMMENT_FILTER_TYPE_STARTS_WITH,
+			Value: "invoice",
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "comment LIKE ?")
+	assert.Contains(t, args, "invoice%")
+
+	params.CommentFilter = &models.TransactionCommentFilter{
+		Type: models.TRANSACTION_COMMENT_FILTER_TYPE_NOT_EMPTY,
+	}
+	cond = Transactions.buildTransactionQueryCondition(params)
+	sql, args, err = testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "comment<>?")
+	assert.Contains(t, args, "")
+}
+
+func TestBuildTransactionQueryCondition_WithGeoBounds(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		GeoBounds: &models.TransactionGeoBoundsFilter{
+			MinLongitude: 116.1,
+			MinLatitude:  39.7,
+			MaxLongitude: 116.7,
+			MaxLatitude:  40.2,
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "geo_longitude>=?")
+	assert.Contains(t, sql, "geo_longitude<=?")
+	assert.Contains(t, sql, "geo_latitude>=?")
+	assert.Contains(t, sql, "geo_latitude<=?")
+	assert.Contains(t, args, 116.1)
+	assert.Contains(t, args, 40.2)
+}
+
+func TestBuildTransactionQueryCondition_WithGeoBoundsCrossingAntimeridian(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		GeoBounds: &models.TransactionGeoBoundsFilter{
+			MinLongitude: 170,
+			MinLatitude:  -10,
+			MaxLongitude: -170,
+			MaxLatitude:  10,
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "(geo_longitude>=? OR geo_longitude<=?)")
+	assert.Contains(t, args, float64(170))
+	assert.Contains(t, args, float64(-170))
+}
+
+func TestBuildTransactionQueryCondition_WithConditionGroups(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		ConditionGroups: []*models.TransactionQueryConditionGroup{
+			{
+				TransactionType: models.TRANSACTION_TYPE_EXPENSE,
+				CfoId:           4001,
+				PlannedFilter:   models.TRANSACTION_PLANNED_FILTER_TYPE_PLANNED,
+			},
+			{
+				CategoryIds:  []int64{3001},
+				AmountFilter: "gt:1000",
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, " OR ")
+	assert.Contains(t, sql, "cfo_id")
+	assert.Contains(t, sql, "planned")
+	assert.Contains(t, sql, "category_id")
+	assert.Contains(t, sql, "amount")
+	assert.Contains(t, args, models.TRANSACTION_DB_TYPE_EXPENSE)
+	assert.Contains(t, args, int64(4001))
+	assert.Contains(t, args, int64(3001))
+	assert.Contains(t, args, int64(1000))
+}
+
+func TestBuildTransactionQueryCondition_WithEmptyConditionGroup(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		ConditionGroups: []*models.TransactionQueryConditionGroup{
+			{},
+			{
+				CfoId: 4001,
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, _, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "1=1")
+	assert.Contains(t, sql, "cfo_id")
+}
+
+func TestBuildTransactionQueryCondition_WithSearchTerm(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		SearchTerms: []*models.TransactionSearchTerm{
+			{
+				Tokens: []string{"coffee"},
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "transaction_id IN (SELECT t0.transaction_id FROM transaction_search_index t0")
+	assert.Contains(t, sql, "related_id IN (SELECT t0.transaction_id FROM transaction_search_index t0")
+	assert.Contains(t, sql, "t0.token=?")
+	assert.NotContains(t, sql, "counterparty_id")
+	assert.NotContains(t, sql, "transaction_tag_index")
+	assert.Contains(t, args, "coffee")
+}
+
+func TestBuildTransactionQueryCondition_WithPrefixSearchTerm(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		SearchTerms: []*models.TransactionSearchTerm{
+			{
+				Tokens: []string{"sta"},
+				Prefix: true,
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "t0.token LIKE ?")
+	assert.Contains(t, args, "sta%")
+}
+
+func TestBuildTransactionQueryCondition_WithPhraseSearchTerm(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		SearchTerms: []*models.TransactionSearchTerm{
+			{
+				Tokens: []string{"monthly", "rent"},
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "INNER JOIN transaction_search_index t1 ON")
+	assert.Contains(t, sql, "t1.position=t0.position+1")
+	assert.Contains(t, sql, "t1.token=?")
+	assert.Contains(t, args, "monthly")
+	assert.Contains(t, args, "rent")
+}
+
+func TestBuildTransactionQueryCondition_WithSearchTermMatchingCounterpartiesAndTags(t *testing.T) {
+	params := &models.TransactionQueryParams{
+		Uid: 100,
+		SearchTerms: []*models.TransactionSearchTerm{
+			{
+				Tokens:          []string{"coffee"},
+				CounterpartyIds: []int64{5001},
+				TagIds:          []int64{6001},
+			},
+		},
+	}
+
+	cond := Transactions.buildTransactionQueryCondition(params)
+	sql, args, err := testBuildCondToSQL(cond)
+
+	assert.Nil(t, err)
+	assert.Contains(t, sql, "counterparty_id IN (?)")
+	assert.Contains(t, sql, "SELECT transaction_id FROM transaction_tag_index")
+	assert.Contains(t, args, int64(5001))
+	assert.Contains(t, args, int64(6001))
+}
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/simplifiedchinese"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const importTestUid = int64(1234567890)
+
+type importFixture struct {
+	fileType string
+	data     []byte
+}
+
+func newTestTransactionService(t *testing.T) (*TransactionService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	uuidContainer := initUuidContainer(t)
+	svc := &TransactionService{
+		ServiceUsingDB:   ServiceUsingDB{container: tdb.container},
+		ServiceUsingUuid: ServiceUsingUuid{container: uuidContainer},
+	}
+	return svc, tdb
+}
+
+func getImportFixtures(t *testing.T) []importFixture {
+	t.Helper()
+
+	alipayData, err := simplifiedchinese.GB18030.NewEncoder().String("支付宝交易记录明细查询\n" +
+		"账号:[xxx@xxx.xxx]\n" +
+		"起始日期:[2024-01-01 00:00:00]    终止日期:[2024-09-01 23:59:59]\n" +
+		"---------------------------------交易记录明细列表------------------------------------\n" +
+		"交易创建时间              ,商品名称                ,金额（元）,收/支     ,交易状态    ,\n" +
+		"2024-09-01 01:23:45 ,xxxx            ,0.12   ,收入      ,交易成功    ,\n" +
+		"2024-09-01 12:34:56 ,xxxx            ,123.45  ,支出      ,交易成功    ,\n" +
+		"------------------------------------------------------------------------------------\n")
+	assert.Nil(t, err)
+
+	dengioperaciiData, err := os.ReadFile("../../testdata/dengioperacii_test_file.xlsx")
+	assert.Nil(t, err)
+
+	return []importFixture{
+		{
+			fileType: "ezbookkeeping_csv",
+			data: []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount\n" +
+				"2024-09-01 00:00:00,Balance Modification,,Test Account,123.45,,\n" +
+				"2024-09-01 01:23:45,Income,Test Category,Test Account,0.12,,\n" +
+				"2024-09-01 12:34:56,Expense,Test Category2,Test Account,1.00,,\n" +
+				"2024-09-01 23:59:59,Transfer,Test Category3,Test Account,0.05,Test Account2,0.05"),
+		},
+		{
+			fileType: "ofx",
+			data: []byte("<OFX>\n" +
+				"  <BANKMSGSRSV1>\n" +
+				"    <STMTTRNRS>\n" +
+				"      <STMTRS>\n" +
+				"        <CURDEF>CNY</CURDEF>\n" +
+				"        <BANKACCTFROM>\n" +
+				"          <ACCTID>123</ACCTID>\n" +
+				"        </BANKACCTFROM>\n" +
+				"        <BANKTRANLIST>\n" +
+				"          <STMTTRN>\n" +
+				"            <TRNTYPE>DEP</TRNTYPE>\n" +
+				"            <DTPOSTED>20240901012345.000[+8:CST]</DTPOSTED>\n" +
+				"            <TRNAMT>123.45</TRNAMT>\n" +
+				"          </STMTTRN>\n" +
+				"          <STMTTRN>\n" +
+				"            <TRNTYPE>CHECK</TRNTYPE>\n" +
+				"            <DTPOSTED>20240901123456.000[+8:CST]</DTPOSTED>\n" +
+				"            <TRNAMT>-0.12</TRNAMT>\n" +
+				"          </STMTTRN>\n" +
+				"        </BANKTRANLIST>\n" +
+				"      </STMTRS>\n" +
+				"    </STMTTRNRS>\n" +
+				"  </BANKMSGSRSV1>\n" +
+				"</OFX>"),
+		},
+		{
+			fileType: "qif_ymd",
+			data: []byte("!Type:Bank\n" +
+				"D2024-09-01\n" +
+				"T123.45\n" +
+				"POpening Balance\n" +
+				"L[Test Account]\n" +
+				"^\n" +
+				"D2024-09-02\n" +
+				"T0.12\n" +
+				"LTest Category\n" +
+				"^\n" +
+				"D2024-09-03\n" +
+				"T-1.00\n" +
+				"LTest Category2\n" +
+				"^\n" +
+				"D2024-09-04\n" +
+				"T-0.05\n" +
+				"L[Test Account2]\n" +
+				"^\n"),
+		},
+		{
+			fileType: "iif",
+			data: []byte("!ACCNT\tNAME\tACCNTTYPE\n" +
+				"ACCNT\tTest Account\tBANK\n" +
+				"ACCNT\tTest Account2\tBANK\n" +
+				"ACCNT\tTest Category\tINC\n" +
+				"ACCNT\tTest Category2\tEXP\n" +
+				"!TRNS\tTRNSTYPE\tDATE\tACCNT\tAMOUNT\n" +
+				"!SPL\tTRNSTYPE\tDATE\tACCNT\tAMOUNT\n" +
+				"!ENDTRNS\t\t\t\t\n" +
+				"TRNS\tDEPOSIT\t09/02/2024\tTest Account\t0.12\n" +
+				"SPL\tDEPOSIT\t09/02/2024\tTest Category\t-0.12\n" +
+				"ENDTRNS\t\t\t\t\n" +
+				"TRNS\tCREDIT CARD\t09/03/2024\tTest Account\t-1.00\n" +
+				"SPL\tCREDIT CARD\t09/03/2024\tTest Category2\t1.00\n" +
+				"ENDTRNS\t\t\t\t\n" +
+				"TRNS\tTRANSFER\t09/04/2024\tTest Account\t-0.05\n" +
+				"SPL\tTRANSFER\t09/04/2024\tTest Account2\t0.05\n" +
+				"ENDTRNS\t\t\t\t\n"),
+		},
+		{
+			fileType: "camt053",
+			data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+	<BkToCstmrStmt>
+		<Stmt>
+			<Acct>
+				<Id>
+					<IBAN>123</IBAN>
+				</Id>
+				<Ccy>CNY</Ccy>
+			</Acct>
+			<Ntry>
+				<BookgDt>
+					<DtTm>2024-09-01T01:23:45+08:00</DtTm>
+				</BookgDt>
+				<CdtDbtInd>CRDT</CdtDbtInd>
+				<Amt Ccy="CNY">123.45</Amt>
+			</Ntry>
+			<Ntry>
+				<BookgDt>
+					<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+				</BookgDt>
+				<CdtDbtInd>DBIT</CdtDbtInd>
+				<Amt Ccy="CNY">0.12</Amt>
+			</Ntry>
+		</Stmt>
+	</BkToCstmrStmt>
+</Document>`),
+		},
+		{
+			fileType: "mt940",
+			data: []byte(`{1:F01TESTBANK123456789}{2:I940TESTBANK}{4:
+:20:123456789
+:25:12345678
+:28C:123/1
+:60F:C250601CNY123,45
+:61:2506010602C123,45NTRFTEST//ABC123456
+:86:Transaction 1
+:61:2506020603D234,56NTRFFOOBAR
+:86:Transaction 2
+:62F:C250601CNY123,45
+-}`),
+		},
+		{
+			fileType: "beancount",
+			data: []byte("2024-09-01 *\n" +
+				"  Equity:Opening-Balances -123.45 CNY\n" +
+				"  Assets:TestAccount 123.45 CNY\n" +
+				"2024-09-02 *\n" +
+				"  Income:TestCategory -0.12 CNY\n" +
+				"  Assets:TestAccount 0.12 CNY\n" +
+				"2024-09-03 *\n" +
+				"  Assets:TestAccount -1.00 CNY\n" +
+				"  Expenses:TestCategory2 1.00 CNY\n" +
+				"2024-09-04 *\n" +
+				"  Assets:TestAccount -0.05 CNY\n" +
+				"  Assets:TestAccount2 0.05 CNY\n"),
+		},
+		{
+			fileType: "gnucash",
+			data: []byte("<?xml version=\"1.0\" encoding=\"utf-8\" ?>\n" +
+				"<gnc-v2\n" +
+				"     xmlns:gnc=\"http://www.gnucash.org/XML/gnc\"\n" +
+				"     xmlns:act=\"http://www.gnucash.org/XML/act\"\n" +
+				"     xmlns:book=\"http://www.gnucash.org/XML/book\"\n" +
+				"     xmlns:cmdty=\"http://www.gnucash.org/XML/cmdty\"\n" +
+				"     xmlns:split=\"http://www.gnucash.org/XML/split\"\n" +
+				"     xmlns:trn=\"http://www.gnucash.org/XML/trn\">\n" +
+				"<gnc:book version=\"2.0.0\">\n" +
+				"<gnc:account version=\"2.0.0\">\n" +
+				"  <act:name>Root Account</act:name>\n" +
+				"  <act:id type=\"guid\">00000000000000000000000000000001</act:id>\n" +
+				"  <act:type>ROOT</act:type>\n" +
+				"</gnc:account>\n" +
+				"<gnc:account version=\"2.0.0\">\n" +
+				"  <act:name>Test Category</act:name>\n" +
+				"  <act:id type=\"guid\">00000000000000000000000000000100</act:id>\n" +
+				"  <act:type>INCOME</act:type>\n" +
+				"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n" +
+				"</gnc:account>\n" +
+				"<gnc:account version=\"2.0.0\">\n" +
+				"  <act:name>Test Account</act:name>\n" +
+				"  <act:id type=\"guid\">00000000000000000000000000001000</act:id>\n" +
+				"  <act:type>BANK</act:type>\n" +
+				"  <act:commodity>\n" +
+				"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+				"    <cmdty:id>CNY</cmdty:id>\n" +
+				"  </act:commodity>\n" +
+				"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n" +
+				"</gnc:account>\n" +
+				"<gnc:transaction version=\"2.0.0\">\n" +
+				"  <trn:date-posted>\n" +
+				"    <ts:date>2024-09-01 01:23:45 +0000</ts:date>\n" +
+				"  </trn:date-posted>\n" +
+				"  <trn:splits>\n" +
+				"    <trn:split>\n" +
+				"      <split:quantity>12/100</split:quantity>\n" +
+				"      <split:account type=\"guid\">00000000000000000000000000001000</split:account>\n" +
+				"    </trn:split>\n" +
+				"    <trn:split>\n" +
+				"      <split:quantity>-12/100</split:quantity>\n" +
+				"      <split:account type=\"guid\">00000000000000000000000000000100</split:account>\n" +
+				"    </trn:split>\n" +
+				"  </trn:splits>\n" +
+				"</gnc:transaction>\n" +
+				"</gnc:book>\n" +
+				"</gnc-v2>\n"),
+		},
+		{
+			fileType: "firefly_iii_csv",
+			data: []byte("type,amount,date,source_name,destination_name,category\n" +
+				"\"Opening balance\",123.45,2024-09-01T00:00:00+08:00,\"Initial balance for \"\"Test Account\"\"\",\"Test Account\",\n" +
+				"Deposit,0.12,2024-09-01T01:23:45+08:00,\"A revenue account\",\"Test Account\",\"Test Category\"\n" +
+				"Withdrawal,-1.00,2024-09-01T12:34:56+08:00,\"Test Account\",\"A expense account\",\"Test Category2\"\n" +
+				"Transfer,0.05,2024-09-01T23:59:59+08:00,\"Test Account\",\"Test Account2\",\"Test Category3\""),
+		},
+		{
+			fileType: "feidee_mymoney_csv",
+			data: []byte("随手记导出文件(headers:v5;xxxxx)\n" +
+				"\"交易类型\",\"日期\",\"子类别\",\"账户\",\"金额\",\"备注\",\"关联Id\"\n" +
+				"\"收入\",\"2024-09-01 01:23:45\",\"Test Category\",\"Test Account\",\"0.12\",\"\",\"\"\n" +
+				"\"支出\",\"2024-09-01 12:34:56\",\"Test Category2\",\"Test Account\",\"1.00\",\"\",\"\"\n" +
+				"\"转出\",\"2024-09-01 23:59:59\",\"Test Category3\",\"Test Account\",\"0.05\",\"\",\"00000000-0000-0000-0000-000000000001\"\n" +
+				"\"转入\",\"2024-09-01 23:59:59\",\"Test Category3\",\"Test Account2\",\"0.05\",\"\",\"00000000-0000-0000-0000-000000000001\""),
+		},
+		{
+			fileType: "alipay_web_csv",
+			data:     []byte(alipayData),
+		},
+		{
+			fileType: "wechat_pay_app_csv",
+			data: []byte("微信支付账单明细,,,,\n" +
+				"微信昵称：[xxx],,,,\n" +
+				"起始时间：[2024-01-01 00:00:00] 终止时间：[2024-09-01 23:59:59],,,,\n" +
+				",,,,\n" +
+				"----------------------微信支付账单明细列表--------------------,,,,\n" +
+				"交易时间,交易类型,收/支,金额(元),当前状态\n" +
+				"2024-09-01 01:23:45,二维码收款,收入,￥0.12,已收钱\n" +
+				"2024-09-01 12:34:56,商户消费,支出,￥123.45,支付成功\n"),
+		},
+		{
+			fileType: "jdcom_finance_app_csv",
+			data: []byte("导出信息：\n" +
+				"京东账号名：xxxxxx\n" +
+				"日期区间：2025-01-01 至 2025-09-01\n" +
+				"\n" +
+				"交易时间,商户名称,交易说明,金额,收/付款方式,交易状态,收/支,交易分类\n" +
+				"2025-09-01 01:23:45,xxx,xxx,0.12,余额,交易成功,收入,其他\n" +
+				"2025-09-01 12:34:56,xxx,xxx,123.45,银行卡,交易成功,支出,其他网购\n"),
+		},
+		{
+			fileType: "dengioperacii_xlsx",
+			data:     dengioperaciiData,
+		},
+	}
+}
+
+// prepareImportedTransactions creates the accounts, categories and tags referenced by the parsed transactions and fills in their ids, as the import file handler does
+func prepareImportedTransactions(t *testing.T, tdb *testDB, svc *TransactionService, parsedTransactions models.ImportedTransactionSlice) {
+	t.Helper()
+
+	accountIds := make(map[string]int64)
+	categoryIds := make(map[string]int64)
+	tagIds := make(map[string]int64)
+
+	getAccountId := func(name string, currency string) int64 {
+		if accountId, exists := accountIds[name]; exists {
+			return accountId
+		}
+
+		if currency == "" {
+			currency = "CNY"
+		}
+
+		account := &models.Account{
+			AccountId: svc.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+			Uid:       importTestUid,
+			Category:  models.ACCOUNT_CATEGORY_CASH,
+			Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+			Name:      name,
+			Currency:  currency,
+		}
+
+		_, err := tdb.engine.Insert(account)
+		assert.Nil(t, err)
+
+		accountIds[name] = account.AccountId
+		return account.AccountId
+	}
+
+	getCategoryId := func(categoryType models.TransactionCategoryType, name string) int64 {
+		key := fmt.Sprintf("%d:%s", categoryType, name)
+
+		if categoryId, exists := categoryIds[key]; exists {
+			return categoryId
+		}
+
+		category := &models.TransactionCategory{
+			CategoryId: svc.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+			Uid:        importTestUid,
+			Type:       categoryType,
+			Name:       name,
+		}
+
+		_, err := tdb.engine.Insert(category)
+		assert.Nil(t, err)
+
+		categoryIds[key] = category.CategoryId
+		return category.CategoryId
+	}
+
+	getTagId := func(name string) int64 {
+		if tagId, exists := tagIds[name]; exists {
+			return tagId
+		}
+
+		tag := &models.TransactionTag{
+			TagId: svc.GenerateUuid(uuid.UUID_TYPE_TAG),
+			Uid:   importTestUid,
+			Name:  name,
+		}
+
+		_, err := tdb.engine.Insert(tag)
+		assert.Nil(t, err)
+
+		tagIds[name] = tag.TagId
+		return tag.TagId
+	}
+
+	for _, transaction := range parsedTransactions {
+		transaction.TagIds = make([]string, len(transaction.OriginalTagNames))
+
+		for i, tagName := range transaction.OriginalTagNames {
+			transaction.TagIds[i] = utils.Int64ToString(getTagId(tagName))
+		}
+
+		transaction.AccountId = getAccountId(transaction.OriginalSourceAccountName, transaction.OriginalSourceAccountCurrency)
+
+		switch transaction.Type {
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			transaction.CategoryId = getCategoryId(models.CATEGORY_TYPE_INCOME, transaction.OriginalCategoryName)
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			transaction.CategoryId = getCategoryId(models.CATEGORY_TYPE_EXPENSE, transaction.OriginalCategoryName)
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			transaction.CategoryId = getCategoryId(models.CATEGORY_TYPE_TRANSFER, transaction.OriginalCategoryName)
+			transaction.RelatedAccountId = getAccountId(transaction.OriginalDestinationAccountName, transaction.OriginalDestinationAccountCurrency)
+		}
+	}
+}
+
+func TestGetTransactionDataImporterImportsAllFormats(t *testing.T) {
+	for _, fixture := range getImportFixtures(t) {
+		t.Run(fixture.fileType, func(t *testing.T) {
+			svc, tdb := newTestTransactionService(t)
+			defer tdb.close()
+
+			importer, err := converters.GetTransactionDataImporter(fixture.fileType)
+			assert.Nil(t, err)
+
+			user := &models.User{
+				Uid:             importTestUid,
+				DefaultCurrency: "CNY",
+			}
+
+			parsedTransactions, _, _, _, _, _, err := importer.ParseImportedData(core.NewNullContext(), user, fixture.data, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+			assert.Nil(t, err)
+			assert.True(t, len(parsedTransactions) > 0)
+
+			sort.Sort(parsedTransactions)
+			prepareImportedTransactions(t, tdb, svc, parsedTransactions)
+
+			allTagIds, err := parsedTransactions.ToTransactionTagIdsMap()
+			assert.Nil(t, err)
+
+			err = svc.BatchCreateTransactions(core.NewNullContext(), importTestUid, parsedTransactions.ToTransactionsList(), allTagIds, nil)
+			assert.Nil(t, err)
+
+			var transactions []*models.Transaction
+			err = tdb.engine.Where("uid=? AND deleted=? AND type<>?", importTestUid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN).Find(&transactions)
+			assert.Nil(t, err)
+			assert.Equal(t, len(parsedTransactions), len(transactions))
+		})
+	}
+}
@@ -21,6 +21,17 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return errs.ErrUserIdInvalid
 	}
 
+	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.ModifyTransactionInSession(c, sess, transaction, currentTagIdsCount, addTagIds, removeTagIds, addPictureIds, removePictureIds)
+	})
+}
+
+// ModifyTransactionInSession saves an existed transaction to database in the given session, so the caller can save its own data in the same database transaction
+func (s *TransactionService) ModifyTransactionInSession(c core.Context, sess *xorm.Session, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
 	needTagIndexUuidCount := uint16(len(addTagIds))
 	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
 
@@ -53,299 +64,430 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
-	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify current transaction
-		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
+	// Get and verify current transaction
+	oldTransaction := &models.Transaction{}
+	has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
 
-		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction, because %s", err.Error())
-			return err
-		} else if !has {
-			return errs.ErrTransactionNotFound
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction, because %s", err.Error())
+		return err
+	} else if !has {
+		return errs.ErrTransactionNotFound
+	}
 
-		transaction.Type = oldTransaction.Type
+	transaction.Type = oldTransaction.Type
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			transaction.RelatedId = oldTransaction.RelatedId
-		}
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transaction.RelatedId = oldTransaction.RelatedId
+	}
+
+	// Check whether account id is valid
+	err = s.isAccountIdValid(transaction)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify source and destination account (if necessary)
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
-		// Check whether account id is valid
-		err = s.isAccountIdValid(transaction)
+	if err != nil {
+		log.Errorf(c, "[transactions.ModifyTransaction] failed to get account, because %s", err.Error())
+		return err
+	}
+
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return errs.ErrCannotModifyTransactionInHiddenAccount
+	}
+
+	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+		return errs.ErrCannotModifyTransactionInParentAccount
+	}
+
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
+		sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+		return errs.ErrTransactionSourceAndDestinationAmountNotEqual
+	}
+
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
+		(transaction.Amount < 0 || transaction.RelatedAccountAmount < 0) {
+		return errs.ErrTransferTransactionAmountCannotBeLessThanZero
+	}
+
+	oldSourceAccount, oldDestinationAccount, err := s.getOldAccountModels(sess, transaction, oldTransaction, sourceAccount, destinationAccount)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.ModifyTransaction] failed to get old account, because %s", err.Error())
+		return err
+	}
+
+	if oldSourceAccount.Hidden || (oldDestinationAccount != nil && oldDestinationAccount.Hidden) {
+		return errs.ErrCannotAddTransactionToHiddenAccount
+	}
+
+	// Append modified columns and verify
+	if transaction.CategoryId != oldTransaction.CategoryId {
+		// Get and verify category
+		err = s.isCategoryValid(sess, transaction)
 
 		if err != nil {
 			return err
 		}
 
-		// Get and verify source and destination account (if necessary)
-		sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+		updateCols = append(updateCols, "category_id")
+	}
+
+	modifyTransactionTime := false
+
+	if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			return errs.ErrBalanceModificationTransactionCannotModifyTime
+		}
+
+		sameSecondLatestTransaction := &models.Transaction{}
+		minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		has, err = sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
 
 		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get account, because %s", err.Error())
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get transaction time, because %s", err.Error())
 			return err
 		}
 
-		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-			return errs.ErrCannotModifyTransactionInHiddenAccount
+		if has && sameSecondLatestTransaction.TransactionTime < maxTransactionTime-1 {
+			transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
+		} else if has && sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
+			return errs.ErrTooMuchTransactionInOneSecond
 		}
 
-		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
-			return errs.ErrCannotModifyTransactionInParentAccount
-		}
+		updateCols = append(updateCols, "transaction_time")
+		modifyTransactionTime = true
+	}
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
-			sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
-			return errs.ErrTransactionSourceAndDestinationAmountNotEqual
-		}
+	if transaction.TimezoneUtcOffset != oldTransaction.TimezoneUtcOffset {
+		updateCols = append(updateCols, "timezone_utc_offset")
+	}
+
+	if transaction.AccountId != oldTransaction.AccountId {
+		updateCols = append(updateCols, "account_id")
+	}
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
-			(transaction.Amount < 0 || transaction.RelatedAccountAmount < 0) {
-			return errs.ErrTransferTransactionAmountCannotBeLessThanZero
+	if transaction.Amount != oldTransaction.Amount {
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			transaction.RelatedAccountAmount = oldTransaction.RelatedAccountAmount + transaction.Amount - oldTransaction.Amount
+			updateCols = append(updateCols, "related_account_amount")
 		}
 
-		oldSourceAccount, oldDestinationAccount, err := s.getOldAccountModels(sess, transaction, oldTransaction, sourceAccount, destinationAccount)
+		updateCols = append(updateCols, "amount")
+	}
 
-		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to get old account, because %s", err.Error())
-			return err
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
+			updateCols = append(updateCols, "related_account_id")
 		}
 
-		if oldSourceAccount.Hidden || (oldDestinationAccount != nil && oldDestinationAccount.Hidden) {
-			return errs.ErrCannotAddTransactionToHiddenAccount
+		if transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
+			updateCols = append(updateCols, "related_account_amount")
 		}
+	}
 
-		// Append modified columns and verify
-		if transaction.CategoryId != oldTransaction.CategoryId {
-			// Get and verify category
-			err = s.isCategoryValid(sess, transaction)
+	if transaction.HideAmount != oldTransaction.HideAmount {
+		updateCols = append(updateCols, "hide_amount")
+	}
 
-			if err != nil {
-				return err
-			}
+	if transaction.CounterpartyId != oldTransaction.CounterpartyId {
+		updateCols = append(updateCols, "counterparty_id")
+	}
 
-			updateCols = append(updateCols, "category_id")
-		}
+	if transaction.Comment != oldTransaction.Comment {
+		updateCols = append(updateCols, "comment")
+	}
 
-		modifyTransactionTime := false
+	if transaction.GeoLongitude != oldTransaction.GeoLongitude {
+		updateCols = append(updateCols, "geo_longitude")
+	}
 
-		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
-			if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-				return errs.ErrBalanceModificationTransactionCannotModifyTime
-			}
+	if transaction.GeoLatitude != oldTransaction.GeoLatitude {
+		updateCols = append(updateCols, "geo_latitude")
+	}
 
-			sameSecondLatestTransaction := &models.Transaction{}
-			minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-			maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+	// Get and verify tags
+	err = s.isTagsValid(sess, transaction, transactionTagIndexes, addTagIds)
 
-			has, err = sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to get transaction time, because %s", err.Error())
-				return err
-			}
+	// Get and verify pictures
+	err = s.isPicturesValid(sess, transaction, addPictureIds)
 
-			if has && sameSecondLatestTransaction.TransactionTime < maxTransactionTime-1 {
-				transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
-			} else if has && sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
+	if err != nil {
+		return err
+	}
 
-			updateCols = append(updateCols, "transaction_time")
-			modifyTransactionTime = true
-		}
+	// Not allow to add transaction before balance modification transaction
+	if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		otherTransactionExists := false
 
-		if transaction.TimezoneUtcOffset != oldTransaction.TimezoneUtcOffset {
-			updateCols = append(updateCols, "timezone_utc_offset")
+		if destinationAccount != nil && sourceAccount.AccountId != destinationAccount.AccountId {
+			otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?) AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, destinationAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
+		} else {
+			otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND account_id=? AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
 		}
 
-		if transaction.AccountId != oldTransaction.AccountId {
-			updateCols = append(updateCols, "account_id")
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get whether other transactions exist, because %s", err.Error())
+			return err
+		} else if otherTransactionExists {
+			return errs.ErrCannotAddTransactionBeforeBalanceModificationTransaction
 		}
+	}
 
-		if transaction.Amount != oldTransaction.Amount {
-			if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-				transaction.RelatedAccountAmount = oldTransaction.RelatedAccountAmount + transaction.Amount - oldTransaction.Amount
-				updateCols = append(updateCols, "related_account_amount")
-			}
+	// Update transaction row
+	updatedRows, err := sess.ID(transaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", transaction.Uid, false).Update(transaction)
 
-			updateCols = append(updateCols, "amount")
-		}
+	if err != nil {
+		log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction, because %s", err.Error())
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrTransactionNotFound
+	}
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
-				updateCols = append(updateCols, "related_account_id")
-			}
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction := s.GetRelatedTransferTransaction(transaction)
 
-			if transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
-				updateCols = append(updateCols, "related_account_amount")
-			}
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
+			return errs.ErrTooMuchTransactionInOneSecond
 		}
 
-		if transaction.HideAmount != oldTransaction.HideAmount {
-			updateCols = append(updateCols, "hide_amount")
+		relatedUpdateCols := s.getRelatedUpdateColumns(updateCols)
+		updatedRows, err := sess.ID(relatedTransaction.TransactionId).Cols(relatedUpdateCols...).Where("uid=? AND deleted=?", relatedTransaction.Uid, false).Update(relatedTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction, because %s", err.Error())
+			return err
+		} else if updatedRows < 1 {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction")
+			return errs.ErrDatabaseOperationFailed
 		}
+	}
 
-		if transaction.CounterpartyId != oldTransaction.CounterpartyId {
-			updateCols = append(updateCols, "counterparty_id")
+	// Update transaction tag index
+	if len(removeTagIds) > 0 {
+		tagIndexUpdateModel := &models.TransactionTagIndex{
+			Deleted:         true,
+			DeletedUnixTime: now,
 		}
 
-		if transaction.Comment != oldTransaction.Comment {
-			updateCols = append(updateCols, "comment")
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("tag_id", removeTagIds).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction tag index, because %s", err.Error())
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionTagNotFound
 		}
+	}
 
-		if transaction.GeoLongitude != oldTransaction.GeoLongitude {
-			updateCols = append(updateCols, "geo_longitude")
+	if len(transactionTagIndexes) > 0 {
+		for i := 0; i < len(transactionTagIndexes); i++ {
+			transactionTagIndex := transactionTagIndexes[i]
+			transactionTagIndex.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(transactionTagIndex)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction tag index, because %s", err.Error())
+				return err
+			}
+		}
+	} else if len(transactionTagIndexes) == 0 && currentTagIdsCount > 0 && modifyTransactionTime {
+		tagIndexUpdateModel := &models.TransactionTagIndex{
+			TransactionTime: transaction.TransactionTime,
 		}
 
-		if transaction.GeoLatitude != oldTransaction.GeoLatitude {
-			updateCols = append(updateCols, "geo_latitude")
+		_, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction tag index, because %s", err.Error())
+			return err
 		}
+	}
 
-		// Get and verify tags
-		err = s.isTagsValid(sess, transaction, transactionTagIndexes, addTagIds)
+	// Update transaction search index
+	if transaction.Comment != oldTransaction.Comment {
+		err = TransactionSearchIndexes.ModifySearchIndexesInSession(sess, transaction.Uid, []int64{transaction.TransactionId}, transaction.Comment)
 
 		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction search index, because %s", err.Error())
 			return err
 		}
+	}
+
+	// Update obligation payments, the payments no longer match the transaction whose amount, counterparty or account is changed
+	if transaction.Amount != oldTransaction.Amount || transaction.CounterpartyId != oldTransaction.CounterpartyId || transaction.AccountId != oldTransaction.AccountId {
+		err = deleteObligationPaymentsOfTransaction(sess, transaction.Uid, transaction.TransactionId, now)
 
-		// Get and verify pictures
-		err = s.isPicturesValid(sess, transaction, addPictureIds)
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to delete obligation payments, because %s", err.Error())
+			return err
+		}
+	} else if modifyTransactionTime {
+		err = updateObligationPaymentDatesOfTransaction(sess, transaction.Uid, transaction.TransactionId, transaction.TransactionTime, now)
 
 		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update obligation payment dates, because %s", err.Error())
 			return err
 		}
+	}
 
-		// Not allow to add transaction before balance modification transaction
-		if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			otherTransactionExists := false
+	// Update asset transactions
+	err = updateAssetTransactionsOfTransaction(sess, transaction, oldTransaction)
 
-			if destinationAccount != nil && sourceAccount.AccountId != destinationAccount.AccountId {
-				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?) AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, destinationAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
-			} else {
-				otherTransactionExists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND type=? AND account_id=? AND transaction_time>=?", transaction.Uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, sourceAccount.AccountId, transaction.TransactionTime).Limit(1).Exist(&models.Transaction{})
-			}
+	if err != nil {
+		log.Errorf(c, "[transactions.ModifyTransaction] failed to update asset transactions, because %s", err.Error())
+		return err
+	}
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to get whether other transactions exist, because %s", err.Error())
-				return err
-			} else if otherTransactionExists {
-				return errs.ErrCannotAddTransactionBeforeBalanceModificationTransaction
-			}
+	// Update transaction picture
+	if len(removePictureIds) > 0 {
+		pictureUpdateModel := &models.TransactionPictureInfo{
+			Deleted:         true,
+			DeletedUnixTime: now,
 		}
 
-		// Update transaction row
-		updatedRows, err := sess.ID(transaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", transaction.Uid, false).Update(transaction)
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Update(pictureUpdateModel)
 
 		if err != nil {
-			log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction, because %s", err.Error())
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction picture info, because %s", err.Error())
 			return err
-		} else if updatedRows < 1 {
-			return errs.ErrTransactionNotFound
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionPictureNotFound
 		}
+	}
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			relatedTransaction := s.GetRelatedTransferTransaction(transaction)
-
-			if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
+	if len(addPictureIds) > 0 {
+		pictureUpdateModel := &models.TransactionPictureInfo{
+			TransactionId:   transaction.TransactionId,
+			UpdatedUnixTime: now,
+		}
 
-			relatedUpdateCols := s.getRelatedUpdateColumns(updateCols)
-			updatedRows, err := sess.ID(relatedTransaction.TransactionId).Cols(relatedUpdateCols...).Where("uid=? AND deleted=?", relatedTransaction.Uid, false).Update(relatedTransaction)
+		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", addPictureIds).Update(pictureUpdateModel)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction, because %s", err.Error())
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update related transaction")
-				return errs.ErrDatabaseOperationFailed
-			}
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to update new transaction picture info, because %s", err.Error())
+			return err
 		}
+	}
 
-		// Update transaction tag index
-		if len(removeTagIds) > 0 {
-			tagIndexUpdateModel := &models.TransactionTagIndex{
-				Deleted:         true,
-				DeletedUnixTime: now,
-			}
+	// Update account table (skip balance update for planned/future transactions)
+	switch oldTransaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		if transaction.AccountId != oldTransaction.AccountId {
+			return errs.ErrBalanceModificationTransactionCannotChangeAccountId
+		}
 
-			deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("tag_id", removeTagIds).Update(tagIndexUpdateModel)
+		if !oldTransaction.Planned && transaction.Amount != oldTransaction.Amount && transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
+			sourceAccount.UpdatedUnixTime = now
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction tag index, because %s", err.Error())
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
 				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionTagNotFound
+			} else if updatedRows < 1 {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+				return errs.ErrDatabaseOperationFailed
 			}
 		}
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		if !oldTransaction.Planned {
+			var oldAccountNewAmount int64 = 0
+			var newAccountNewAmount int64 = 0
 
-		if len(transactionTagIndexes) > 0 {
-			for i := 0; i < len(transactionTagIndexes); i++ {
-				transactionTagIndex := transactionTagIndexes[i]
-				transactionTagIndex.TransactionTime = transaction.TransactionTime
+			if transaction.AccountId == oldTransaction.AccountId {
+				oldAccountNewAmount = transaction.Amount
+			} else {
+				newAccountNewAmount = transaction.Amount
+			}
 
-				_, err := sess.Insert(transactionTagIndex)
+			if oldAccountNewAmount != oldTransaction.Amount {
+				oldSourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
 
 				if err != nil {
-					log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction tag index, because %s", err.Error())
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
 					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
 			}
-		} else if len(transactionTagIndexes) == 0 && currentTagIdsCount > 0 && modifyTransactionTime {
-			tagIndexUpdateModel := &models.TransactionTagIndex{
-				TransactionTime: transaction.TransactionTime,
-			}
 
-			_, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(tagIndexUpdateModel)
+			if newAccountNewAmount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction tag index, because %s", err.Error())
-				return err
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
 			}
 		}
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		if !oldTransaction.Planned {
+			var oldAccountNewAmount int64 = 0
+			var newAccountNewAmount int64 = 0
 
-		// Update transaction picture
-		if len(removePictureIds) > 0 {
-			pictureUpdateModel := &models.TransactionPictureInfo{
-				Deleted:         true,
-				DeletedUnixTime: now,
+			if transaction.AccountId == oldTransaction.AccountId {
+				oldAccountNewAmount = transaction.Amount
+			} else {
+				newAccountNewAmount = transaction.Amount
 			}
 
-			deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Update(pictureUpdateModel)
+			if oldAccountNewAmount != oldTransaction.Amount {
+				oldSourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction picture info, because %s", err.Error())
-				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionPictureNotFound
-			}
-		}
-
-		if len(addPictureIds) > 0 {
-			pictureUpdateModel := &models.TransactionPictureInfo{
-				TransactionId:   transaction.TransactionId,
-				UpdatedUnixTime: now,
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
 			}
 
-			_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", addPictureIds).Update(pictureUpdateModel)
+			if newAccountNewAmount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				log.Errorf(c, "[transactions.ModifyTransaction] failed to update new transaction picture info, because %s", err.Error())
-				return err
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
 			}
 		}
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		if !oldTransaction.Planned {
+			var oldSourceAccountNewAmount int64 = 0
+			var newSourceAccountNewAmount int64 = 0
 
-		// Update account table (skip balance update for planned/future transactions)
-		switch oldTransaction.Type {
-		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
-			if transaction.AccountId != oldTransaction.AccountId {
-				return errs.ErrBalanceModificationTransactionCannotChangeAccountId
+			if transaction.AccountId == oldTransaction.AccountId {
+				oldSourceAccountNewAmount = transaction.Amount
+			} else {
+				newSourceAccountNewAmount = transaction.Amount
 			}
 
-			if !oldTransaction.Planned && transaction.Amount != oldTransaction.Amount && transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount {
-				sourceAccount.UpdatedUnixTime = now
-				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+			if oldSourceAccountNewAmount != oldTransaction.Amount {
+				oldSourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
 
 				if err != nil {
 					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
@@ -355,161 +497,57 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 					return errs.ErrDatabaseOperationFailed
 				}
 			}
-		case models.TRANSACTION_DB_TYPE_INCOME:
-			if !oldTransaction.Planned {
-				var oldAccountNewAmount int64 = 0
-				var newAccountNewAmount int64 = 0
-
-				if transaction.AccountId == oldTransaction.AccountId {
-					oldAccountNewAmount = transaction.Amount
-				} else {
-					newAccountNewAmount = transaction.Amount
-				}
 
-				if oldAccountNewAmount != oldTransaction.Amount {
-					oldSourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
+			if newSourceAccountNewAmount != 0 {
+				sourceAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-				if newAccountNewAmount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
 			}
-		case models.TRANSACTION_DB_TYPE_EXPENSE:
-			if !oldTransaction.Planned {
-				var oldAccountNewAmount int64 = 0
-				var newAccountNewAmount int64 = 0
-
-				if transaction.AccountId == oldTransaction.AccountId {
-					oldAccountNewAmount = transaction.Amount
-				} else {
-					newAccountNewAmount = transaction.Amount
-				}
 
-				if oldAccountNewAmount != oldTransaction.Amount {
-					oldSourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
+			var oldDestinationAccountNewAmount int64 = 0
+			var newDestinationAccountNewAmount int64 = 0
 
-				if newAccountNewAmount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
+			if transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
+				oldDestinationAccountNewAmount = transaction.RelatedAccountAmount
+			} else {
+				newDestinationAccountNewAmount = transaction.RelatedAccountAmount
 			}
-		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
-			if !oldTransaction.Planned {
-				var oldSourceAccountNewAmount int64 = 0
-				var newSourceAccountNewAmount int64 = 0
-
-				if transaction.AccountId == oldTransaction.AccountId {
-					oldSourceAccountNewAmount = transaction.Amount
-				} else {
-					newSourceAccountNewAmount = transaction.Amount
-				}
-
-				if oldSourceAccountNewAmount != oldTransaction.Amount {
-					oldSourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(oldSourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)-(%d)", oldTransaction.Amount, oldSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldSourceAccount.Uid, false).Update(oldSourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
-
-				if newSourceAccountNewAmount != 0 {
-					sourceAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", newSourceAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
 
-				var oldDestinationAccountNewAmount int64 = 0
-				var newDestinationAccountNewAmount int64 = 0
+			if oldDestinationAccountNewAmount != oldTransaction.RelatedAccountAmount {
+				oldDestinationAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(oldDestinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, oldDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldDestinationAccount.Uid, false).Update(oldDestinationAccount)
 
-				if transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
-					oldDestinationAccountNewAmount = transaction.RelatedAccountAmount
-				} else {
-					newDestinationAccountNewAmount = transaction.RelatedAccountAmount
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
+			}
 
-				if oldDestinationAccountNewAmount != oldTransaction.RelatedAccountAmount {
-					oldDestinationAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(oldDestinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)+(%d)", oldTransaction.RelatedAccountAmount, oldDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", oldDestinationAccount.Uid, false).Update(oldDestinationAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
-				}
+			if newDestinationAccountNewAmount != 0 {
+				destinationAccount.UpdatedUnixTime = now
+				updatedRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
 
-				if newDestinationAccountNewAmount != 0 {
-					destinationAccount.UpdatedUnixTime = now
-					updatedRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", newDestinationAccountNewAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
-
-					if err != nil {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
-						return err
-					} else if updatedRows < 1 {
-						log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
-						return errs.ErrDatabaseOperationFailed
-					}
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance, because %s", err.Error())
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
 				}
 			}
-		case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
-			return errs.ErrTransactionTypeInvalid
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
+	case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		return errs.ErrTransactionTypeInvalid
 	}
 
 	return nil
@@ -75,127 +75,138 @@ func (s *TransactionService) ConfirmPlannedTransaction(c core.Context, uid int64
 		return nil, errs.ErrUserIdInvalid
 	}
 
-	now := time.Now().Unix()
-
 	var transaction *models.Transaction
 
 	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		transaction = &models.Transaction{}
-		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+		var err error
+		transaction, err = s.ConfirmPlannedTransactionInSession(c, sess, uid, transactionId, clientTimezone)
+		return err
+	})
 
-		if err != nil {
-			return err
-		} else if !has {
-			return errs.ErrTransactionNotFound
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		if !transaction.Planned {
-			return errs.ErrNothingWillBeUpdated
-		}
+	return transaction, nil
+}
 
-		newTransactionTime := utils.GetMinTransactionTimeFromUnixTime(now)
-		transaction.Planned = false
-		transaction.TransactionTime = newTransactionTime
-		transaction.UpdatedUnixTime = now
+// ConfirmPlannedTransactionInSession confirms a planned transaction in the given session and returns the confirmed transaction
+func (s *TransactionService) ConfirmPlannedTransactionInSession(c core.Context, sess *xorm.Session, uid int64, transactionId int64, clientTimezone *time.Location) (*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	transaction := &models.Transaction{}
+	has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	if !transaction.Planned {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
 
-		_, err = sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Cols("planned", "transaction_time", "updated_unix_time").Update(transaction)
+	newTransactionTime := utils.GetMinTransactionTimeFromUnixTime(now)
+	transaction.Planned = false
+	transaction.TransactionTime = newTransactionTime
+	transaction.UpdatedUnixTime = now
 
+	_, err = sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Cols("planned", "transaction_time", "updated_unix_time").Update(transaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// If there is a related transfer transaction, update it too
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction := &models.Transaction{
+			Planned:         false,
+			TransactionTime: newTransactionTime,
+			UpdatedUnixTime: now,
+		}
+		_, err = sess.ID(transaction.RelatedId).Where("uid=? AND deleted=?", uid, false).Cols("planned", "transaction_time", "updated_unix_time").Update(relatedTransaction)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		// If there is a related transfer transaction, update it too
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			relatedTransaction := &models.Transaction{
-				Planned:         false,
-				TransactionTime: newTransactionTime,
-				UpdatedUnixTime: now,
-			}
-			_, err = sess.ID(transaction.RelatedId).Where("uid=? AND deleted=?", uid, false).Cols("planned", "transaction_time", "updated_unix_time").Update(relatedTransaction)
+	// Apply balance changes now that the planned transaction is confirmed
+	// Use the same 'now' timestamp captured at the start for consistency
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		if transaction.RelatedAccountAmount != 0 {
+			sourceAccount := &models.Account{UpdatedUnixTime: now}
+			updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
 			if err != nil {
-				return err
+				return nil, err
+			} else if updatedRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
 		}
-
-		// Apply balance changes now that the planned transaction is confirmed
-		// Use the same 'now' timestamp captured at the start for consistency
-		switch transaction.Type {
-		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
-			if transaction.RelatedAccountAmount != 0 {
-				sourceAccount := &models.Account{UpdatedUnixTime: now}
-				updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
-				if err != nil {
-					return err
-				} else if updatedRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
-			}
-		case models.TRANSACTION_DB_TYPE_INCOME:
-			if transaction.Amount != 0 {
-				sourceAccount := &models.Account{UpdatedUnixTime: now}
-				updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
-				if err != nil {
-					return err
-				} else if updatedRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		if transaction.Amount != 0 {
+			sourceAccount := &models.Account{UpdatedUnixTime: now}
+			updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
-		case models.TRANSACTION_DB_TYPE_EXPENSE:
-			if transaction.Amount != 0 {
-				sourceAccount := &models.Account{UpdatedUnixTime: now}
-				updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
-				if err != nil {
-					return err
-				} else if updatedRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+		}
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		if transaction.Amount != 0 {
+			sourceAccount := &models.Account{UpdatedUnixTime: now}
+			updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
-		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
-			if transaction.Amount != 0 {
-				sourceAccount := &models.Account{UpdatedUnixTime: now}
-				updatedSourceRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
-				if err != nil {
-					return err
-				} else if updatedSourceRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+		}
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		if transaction.Amount != 0 {
+			sourceAccount := &models.Account{UpdatedUnixTime: now}
+			updatedSourceRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedSourceRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
-			if transaction.RelatedAccountAmount != 0 {
-				destinationAccount := &models.Account{UpdatedUnixTime: now}
-				updatedDestRows, err := sess.ID(transaction.RelatedAccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(destinationAccount)
-				if err != nil {
-					return err
-				} else if updatedDestRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+		}
+		if transaction.RelatedAccountAmount != 0 {
+			destinationAccount := &models.Account{UpdatedUnixTime: now}
+			updatedDestRows, err := sess.ID(transaction.RelatedAccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(destinationAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedDestRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
-		case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
-			// TRANSFER_IN: AccountId is the destination (receiving money), apply balance
-			if transaction.Amount != 0 {
-				destinationAccount := &models.Account{UpdatedUnixTime: now}
-				updatedDestRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(destinationAccount)
-				if err != nil {
-					return err
-				} else if updatedDestRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+		}
+	case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		// TRANSFER_IN: AccountId is the destination (receiving money), apply balance
+		if transaction.Amount != 0 {
+			destinationAccount := &models.Account{UpdatedUnixTime: now}
+			updatedDestRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(destinationAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedDestRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
-			if transaction.RelatedAccountAmount != 0 {
-				sourceAccount := &models.Account{UpdatedUnixTime: now}
-				updatedSourceRows, err := sess.ID(transaction.RelatedAccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
-				if err != nil {
-					return err
-				} else if updatedSourceRows < 1 {
-					return errs.ErrDatabaseOperationFailed
-				}
+		}
+		if transaction.RelatedAccountAmount != 0 {
+			sourceAccount := &models.Account{UpdatedUnixTime: now}
+			updatedSourceRows, err := sess.ID(transaction.RelatedAccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(sourceAccount)
+			if err != nil {
+				return nil, err
+			} else if updatedSourceRows < 1 {
+				return nil, errs.ErrDatabaseOperationFailed
 			}
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
 	}
 
 	return transaction, nil
@@ -275,12 +286,31 @@ func (s *TransactionService) ModifyAllFuturePlannedTransactions(c core.Context,
 		log.Infof(c, "[transactions.ModifyAllFuturePlannedTransactions] updating where: uid=%d, planned=true, source_template_id=%d, transaction_time>=%d, updateCols=%v",
 			uid, sourceTransaction.SourceTemplateId, sourceTransaction.TransactionTime, updateCols)
 
+		var modifiedTransactions []*models.Transaction
+		err = sess.Cols("transaction_id").Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND planned=? AND type<>?",
+			uid, false, sourceTransaction.SourceTemplateId, sourceTransaction.TransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
+			Find(&modifiedTransactions)
+
+		if err != nil {
+			return err
+		}
+
 		affectedCount, err = sess.Where("uid=? AND deleted=? AND source_template_id=? AND transaction_time>=? AND (planned=? OR type=?)",
 			uid, false, sourceTransaction.SourceTemplateId, sourceTransaction.TransactionTime, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).
 			Cols(updateCols...).Update(updateTransaction)
 
 		log.Infof(c, "[transactions.ModifyAllFuturePlannedTransactions] update result: affectedCount=%d, err=%v", affectedCount, err)
 
+		if err != nil {
+			return err
+		}
+
+		err = TransactionSearchIndexes.ModifySearchIndexesInSession(sess, uid, s.GetTransactionIds(modifiedTransactions), modifyReq.Comment)
+
+		if err != nil {
+			return err
+		}
+
 		// Also update related TRANSFER_IN records with swapped amounts/accounts
 		if sourceTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || sourceTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 			relatedUpdateCols := []string{"updated_unix_time"}
@@ -329,7 +359,7 @@ func (s *TransactionService) ModifyAllFuturePlannedTransactions(c core.Context,
 	return affectedCount, nil
 }
 
-// UnconfirmTransaction converts an actual transaction back to planned by reversing balance changes
+// UnconfirmTransaction converts an actual transaction back to planned by reversing balance changes and deleting its obligation payments
 func (s *TransactionService) UnconfirmTransaction(c core.Context, uid int64, transactionId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
@@ -372,6 +402,13 @@ func (s *TransactionService) UnconfirmTransaction(c core.Context, uid int64, tra
 			}
 		}
 
+		// Planned transactions do not settle obligations
+		err = deleteObligationPaymentsOfTransaction(sess, uid, transactionId, now)
+
+		if err != nil {
+			return err
+		}
+
 		// Reverse balance changes (opposite of ConfirmPlannedTransaction)
 		// Use the same 'now' timestamp captured at the start for consistency
 		switch transaction.Type {
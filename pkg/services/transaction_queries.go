@@ -312,6 +312,13 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 		AmountFilter:       params.AmountFilter,
 		Keyword:            params.Keyword,
 		CounterpartyId:     params.CounterpartyId,
+		CfoId:              params.CfoId,
+		PlannedFilter:      params.PlannedFilter,
+		SourceTemplateId:   params.SourceTemplateId,
+		GeoBounds:          params.GeoBounds,
+		CommentFilter:      params.CommentFilter,
+		ConditionGroups:    params.ConditionGroups,
+		SearchTerms:        params.SearchTerms,
 		NoDuplicated:       true,
 	}
 
@@ -386,6 +393,13 @@ func (s *TransactionService) GetTransactionCount(c core.Context, params *models.
 		AmountFilter:       params.AmountFilter,
 		Keyword:            params.Keyword,
 		CounterpartyId:     params.CounterpartyId,
+		CfoId:              params.CfoId,
+		PlannedFilter:      params.PlannedFilter,
+		SourceTemplateId:   params.SourceTemplateId,
+		GeoBounds:          params.GeoBounds,
+		CommentFilter:      params.CommentFilter,
+		ConditionGroups:    params.ConditionGroups,
+		SearchTerms:        params.SearchTerms,
 		NoDuplicated:       true,
 	}
 
@@ -0,0 +1,236 @@
+// transaction_search_indexes.go maintains the full-text search index of transaction comments and resolves search queries.
+package services
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const transactionSearchIndexRebuildBatchSize = 500
+
+// TransactionSearchIndexService represents transaction search index service
+type TransactionSearchIndexService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction search index service singleton instance
+var (
+	TransactionSearchIndexes = &TransactionSearchIndexService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetTransactionSearchTerms parses the search query and returns the search terms with the ids of counterparties and tags whose names match each term
+func (s *TransactionSearchIndexService) GetTransactionSearchTerms(c core.Context, uid int64, query string) ([]*models.TransactionSearchTerm, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	terms, err := models.ParseTransactionSearchQuery(query)
+
+	if err != nil || len(terms) < 1 {
+		return nil, err
+	}
+
+	var counterparties []*models.Counterparty
+	err = s.UserDataDB(uid).NewSession(c).Cols("counterparty_id", "name").Where("uid=? AND deleted=?", uid, false).Find(&counterparties)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*models.TransactionTag
+	err = s.UserDataDB(uid).NewSession(c).Cols("tag_id", "name").Where("uid=? AND deleted=?", uid, false).Find(&tags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(counterparties); i++ {
+		counterpartyNameTokens := models.GetTransactionSearchTokens(counterparties[i].Name)
+
+		for j := 0; j < len(terms); j++ {
+			if terms[j].IsMatchTokens(counterpartyNameTokens) {
+				terms[j].CounterpartyIds = append(terms[j].CounterpartyIds, counterparties[i].CounterpartyId)
+			}
+		}
+	}
+
+	for i := 0; i < len(tags); i++ {
+		tagNameTokens := models.GetTransactionSearchTokens(tags[i].Name)
+
+		for j := 0; j < len(terms); j++ {
+			if terms[j].IsMatchTokens(tagNameTokens) {
+				terms[j].TagIds = append(terms[j].TagIds, tags[i].TagId)
+			}
+		}
+	}
+
+	return terms, nil
+}
+
+// RebuildTransactionSearchIndexes removes all search index of user and indexes the comments of all transactions again, returns the count of indexed transactions
+func (s *TransactionSearchIndexService) RebuildTransactionSearchIndexes(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Cols("transaction_id", "comment").Where("uid=? AND deleted=? AND type<>? AND comment<>?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN, "").Find(&transactions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.TransactionSearchIndex{})
+
+		if err != nil {
+			log.Errorf(c, "[transaction_search_indexes.RebuildTransactionSearchIndexes] failed to delete search index of user \"uid:%d\", because %s", uid, err.Error())
+			return err
+		}
+
+		searchIndexes := make([]*models.TransactionSearchIndex, 0, transactionSearchIndexRebuildBatchSize)
+
+		for i := 0; i < len(transactions); i++ {
+			searchIndexes = append(searchIndexes, s.getTransactionSearchIndexes(uid, transactions[i].TransactionId, transactions[i].Comment)...)
+
+			if len(searchIndexes) >= transactionSearchIndexRebuildBatchSize || i == len(transactions)-1 {
+				if len(searchIndexes) < 1 {
+					continue
+				}
+
+				_, err := sess.Insert(searchIndexes)
+
+				if err != nil {
+					log.Errorf(c, "[transaction_search_indexes.RebuildTransactionSearchIndexes] failed to add search index of user \"uid:%d\", because %s", uid, err.Error())
+					return err
+				}
+
+				searchIndexes = searchIndexes[:0]
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(transactions)), nil
+}
+
+// BuildMissingTransactionSearchIndexes indexes the comments of all transactions of the users who have commented transactions but no search index yet
+// (e.g. the transactions created before the search index is introduced), returns the count of these users and the count of indexed transactions
+func (s *TransactionSearchIndexService) BuildMissingTransactionSearchIndexes(c core.Context) (int, int64, error) {
+	query := fmt.Sprintf(`SELECT DISTINCT t.uid
+		FROM "transaction" t
+		WHERE t.deleted = 0 AND t.type <> %d AND t.comment <> ''
+		AND NOT EXISTS (SELECT 1 FROM transaction_search_index i WHERE i.uid = t.uid)`, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
+
+	userCount := 0
+	transactionCount := int64(0)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var rows []struct {
+			Uid int64 `xorm:"uid"`
+		}
+
+		err := s.UserDataDBByIndex(i).NewSession(c).SQL(query).Find(&rows)
+
+		if err != nil {
+			return userCount, transactionCount, err
+		}
+
+		for j := 0; j < len(rows); j++ {
+			count, err := s.RebuildTransactionSearchIndexes(c, rows[j].Uid)
+
+			if err != nil {
+				return userCount, transactionCount, err
+			}
+
+			userCount++
+			transactionCount += count
+		}
+	}
+
+	return userCount, transactionCount, nil
+}
+
+// CreateSearchIndexesInSession adds the search index of the transaction comment within an existing session
+func (s *TransactionSearchIndexService) CreateSearchIndexesInSession(sess *xorm.Session, uid int64, transactionIds []int64, comment string) error {
+	if len(transactionIds) < 1 {
+		return nil
+	}
+
+	searchIndexes := make([]*models.TransactionSearchIndex, 0)
+
+	for i := 0; i < len(transactionIds); i++ {
+		searchIndexes = append(searchIndexes, s.getTransactionSearchIndexes(uid, transactionIds[i], comment)...)
+	}
+
+	if len(searchIndexes) < 1 {
+		return nil
+	}
+
+	_, err := sess.Insert(searchIndexes)
+
+	return err
+}
+
+// DeleteSearchIndexesInSession removes the search index of the transactions within an existing session, all search index of user would be removed if transaction ids is nil
+func (s *TransactionSearchIndexService) DeleteSearchIndexesInSession(sess *xorm.Session, uid int64, transactionIds []int64) error {
+	if transactionIds == nil {
+		_, err := sess.Where("uid=?", uid).Delete(&models.TransactionSearchIndex{})
+		return err
+	}
+
+	if len(transactionIds) < 1 {
+		return nil
+	}
+
+	_, err := sess.Where("uid=?", uid).In("transaction_id", transactionIds).Delete(&models.TransactionSearchIndex{})
+
+	return err
+}
+
+// ModifySearchIndexesInSession replaces the search index of the transactions with the new comment within an existing session
+func (s *TransactionSearchIndexService) ModifySearchIndexesInSession(sess *xorm.Session, uid int64, transactionIds []int64, comment string) error {
+	if len(transactionIds) < 1 {
+		return nil
+	}
+
+	err := s.DeleteSearchIndexesInSession(sess, uid, transactionIds)
+
+	if err != nil {
+		return err
+	}
+
+	return s.CreateSearchIndexesInSession(sess, uid, transactionIds, comment)
+}
+
+func (s *TransactionSearchIndexService) getTransactionSearchIndexes(uid int64, transactionId int64, comment string) []*models.TransactionSearchIndex {
+	tokens := models.GetTransactionSearchTokens(comment)
+	searchIndexes := make([]*models.TransactionSearchIndex, len(tokens))
+
+	for i := 0; i < len(tokens); i++ {
+		searchIndexes[i] = &models.TransactionSearchIndex{
+			Uid:           uid,
+			Token:         tokens[i],
+			TransactionId: transactionId,
+			Position:      int32(i),
+		}
+	}
+
+	return searchIndexes
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func newTestTransactionSearchIndexService(t *testing.T) (*TransactionSearchIndexService, *TransactionService, *testDB) {
+	t.Helper()
+	tdb := newTestDB(t)
+	svc := &TransactionSearchIndexService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+	}
+	transactionSvc := &TransactionService{
+		ServiceUsingDB: ServiceUsingDB{container: tdb.container},
+	}
+	return svc, transactionSvc, tdb
+}
+
+func insertTestSearchTransaction(t *testing.T, tdb *testDB, transactionId int64, transactionType models.TransactionDbType, relatedId int64, counterpartyId int64, comment string) {
+	t.Helper()
+	_, err := tdb.engine.Insert(&models.Transaction{
+		TransactionId:   transactionId,
+		Uid:             1,
+		Type:            transactionType,
+		RelatedId:       relatedId,
+		CounterpartyId:  counterpartyId,
+		TransactionTime: transactionId,
+		Comment:         comment,
+	})
+	assert.Nil(t, err)
+}
+
+func findTestSearchTransactionIds(t *testing.T, tdb *testDB, transactionSvc *TransactionService, terms []*models.TransactionSearchTerm) []int64 {
+	t.Helper()
+	var transactions []*models.Transaction
+	err := tdb.engine.Where(transactionSvc.buildTransactionQueryCondition(&models.TransactionQueryParams{Uid: 1, SearchTerms: terms})).OrderBy("transaction_id").Find(&transactions)
+	assert.Nil(t, err)
+
+	transactionIds := make([]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = transactions[i].TransactionId
+	}
+
+	return transactionIds
+}
+
+func TestTransactionSearchIndexServiceRebuildAndSearch(t *testing.T) {
+	svc, transactionSvc, tdb := newTestTransactionSearchIndexService(t)
+	defer tdb.close()
+
+	insertTestSearchTransaction(t, tdb, 1001, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Coffee at Starbucks")
+	insertTestSearchTransaction(t, tdb, 1002, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Pay monthly rent")
+	insertTestSearchTransaction(t, tdb, 1003, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, 1004, 0, "Rent deposit")
+	insertTestSearchTransaction(t, tdb, 1004, models.TRANSACTION_DB_TYPE_TRANSFER_IN, 1003, 0, "Rent deposit")
+	insertTestSearchTransaction(t, tdb, 1005, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "")
+
+	count, err := svc.RebuildTransactionSearchIndexes(nil, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	terms, err := svc.GetTransactionSearchTerms(nil, 1, "rent")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1002, 1003, 1004}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	terms, err = svc.GetTransactionSearchTerms(nil, 1, "star*")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1001}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	terms, err = svc.GetTransactionSearchTerms(nil, 1, "\"monthly rent\"")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1002}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	terms, err = svc.GetTransactionSearchTerms(nil, 1, "\"rent monthly\"")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	terms, err = svc.GetTransactionSearchTerms(nil, 1, "rent pay")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1002}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+}
+
+func TestTransactionSearchIndexServiceBuildMissingTransactionSearchIndexes(t *testing.T) {
+	svc, transactionSvc, tdb := newTestTransactionSearchIndexService(t)
+	defer tdb.close()
+
+	insertTestSearchTransaction(t, tdb, 1001, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Coffee at Starbucks")
+	insertTestSearchTransaction(t, tdb, 1002, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Pay monthly rent")
+
+	// the transactions created before the search index is introduced are indexed
+	userCount, transactionCount, err := svc.BuildMissingTransactionSearchIndexes(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, userCount)
+	assert.Equal(t, int64(2), transactionCount)
+
+	terms, err := svc.GetTransactionSearchTerms(nil, 1, "rent")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1002}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	// the user whose search index has been built is skipped
+	userCount, transactionCount, err = svc.BuildMissingTransactionSearchIndexes(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, userCount)
+	assert.Equal(t, int64(0), transactionCount)
+}
+
+func TestTransactionSearchIndexServiceModifyAndDeleteInSession(t *testing.T) {
+	svc, transactionSvc, tdb := newTestTransactionSearchIndexService(t)
+	defer tdb.close()
+
+	insertTestSearchTransaction(t, tdb, 1001, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Coffee")
+	insertTestSearchTransaction(t, tdb, 1002, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Coffee beans")
+
+	sess := tdb.engine.NewSession()
+	defer sess.Close()
+
+	assert.Nil(t, svc.CreateSearchIndexesInSession(sess, 1, []int64{1001}, "Coffee"))
+	assert.Nil(t, svc.CreateSearchIndexesInSession(sess, 1, []int64{1002}, "Coffee beans"))
+
+	terms, err := svc.GetTransactionSearchTerms(nil, 1, "coffee")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1001, 1002}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	assert.Nil(t, svc.ModifySearchIndexesInSession(sess, 1, []int64{1001}, "Tea"))
+	assert.Equal(t, []int64{1002}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	assert.Nil(t, svc.DeleteSearchIndexesInSession(sess, 1, []int64{1002}))
+	assert.Equal(t, []int64{}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	assert.Nil(t, svc.DeleteSearchIndexesInSession(sess, 1, nil))
+
+	indexCount, err := tdb.engine.Where("uid=?", 1).Count(&models.TransactionSearchIndex{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), indexCount)
+}
+
+func TestTransactionSearchIndexServiceGetSearchTermsWithCounterpartiesAndTags(t *testing.T) {
+	svc, transactionSvc, tdb := newTestTransactionSearchIndexService(t)
+	defer tdb.close()
+
+	_, err := tdb.engine.Insert(&models.Counterparty{CounterpartyId: 5001, Uid: 1, Name: "Blue Bottle Coffee"})
+	assert.Nil(t, err)
+	_, err = tdb.engine.Insert(&models.TransactionTag{TagId: 6001, Uid: 1, Name: "Coffee Break"})
+	assert.Nil(t, err)
+	_, err = tdb.engine.Insert(&models.TransactionTagIndex{TagIndexId: 7001, Uid: 1, TagId: 6001, TransactionId: 1003})
+	assert.Nil(t, err)
+
+	insertTestSearchTransaction(t, tdb, 1001, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 5001, "Breakfast")
+	insertTestSearchTransaction(t, tdb, 1002, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Lunch")
+	insertTestSearchTransaction(t, tdb, 1003, models.TRANSACTION_DB_TYPE_EXPENSE, 0, 0, "Dinner")
+
+	terms, err := svc.GetTransactionSearchTerms(nil, 1, "coff*")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(terms))
+	assert.Equal(t, []int64{5001}, terms[0].CounterpartyIds)
+	assert.Equal(t, []int64{6001}, terms[0].TagIds)
+	assert.Equal(t, []int64{1001, 1003}, findTestSearchTransactionIds(t, tdb, transactionSvc, terms))
+
+	terms, err = svc.GetTransactionSearchTerms(nil, 1, "\"bottle coffee\"")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5001}, terms[0].CounterpartyIds)
+	assert.Nil(t, terms[0].TagIds)
+}
+
+func TestTransactionSearchIndexServiceGetSearchTermsInvalidQuery(t *testing.T) {
+	svc, _, tdb := newTestTransactionSearchIndexService(t)
+	defer tdb.close()
+
+	terms, err := svc.GetTransactionSearchTerms(nil, 1, "")
+	assert.Nil(t, err)
+	assert.Nil(t, terms)
+
+	_, err = svc.GetTransactionSearchTerms(nil, 1, "\"\"")
+	assert.Equal(t, errs.ErrTransactionSearchQueryInvalid, err)
+
+	_, err = svc.GetTransactionSearchTerms(nil, 0, "coffee")
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
@@ -77,6 +77,7 @@ const (
 	OllamaLLMProvider              string = "ollama"
 	LMStudioLLMProvider            string = "lm_studio"
 	GoogleAILLMProvider            string = "google_ai"
+	RulesLLMProvider               string = "rules"
 )
 
 // Uuid generator types
@@ -265,6 +266,7 @@ type LLMConfig struct {
 	LMStudioModelID                     string
 	GoogleAIAPIKey                      string
 	GoogleAIModelID                     string
+	RulesFilePath                       string
 	LargeLanguageModelAPIRequestTimeout uint32
 	LargeLanguageModelAPIProxy          string
 	LargeLanguageModelAPISkipTLSVerify  bool
@@ -338,10 +340,18 @@ type Config struct {
 	// Large Language Model
 	TransactionFromAIImageRecognition bool
 	MaxAIRecognitionPictureFileSize   uint32
+	TransactionImportAICategorization bool
+	TransactionNaturalLanguageQuery   bool
 
 	// Large Language Model for Receipt Image Recognition
 	ReceiptImageRecognitionLLMConfig *LLMConfig
 
+	// Large Language Model for Imported Transaction Categorization
+	TransactionImportCategorizationLLMConfig *LLMConfig
+
+	// Large Language Model for Natural Language Transaction Query
+	TransactionQueryLLMConfig *LLMConfig
+
 	// Uuid
 	UuidGeneratorType string
 	UuidServerId      uint8
@@ -357,6 +367,10 @@ type Config struct {
 	// Cron
 	EnableRemoveExpiredTokens        bool
 	EnableCreateScheduledTransaction bool
+	EnableSaveExchangeRatesHistory   bool
+	EnablePostAssetDepreciation      bool
+	EnableUpdateOverdueStatuses      bool
+	EnableSendNotifications          bool
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -514,7 +528,19 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
-	config.ReceiptImageRecognitionLLMConfig, err = loadLLMConfiguration(cfgFile, "llm_image_recognition")
+	config.ReceiptImageRecognitionLLMConfig, err = loadLLMConfiguration(config, cfgFile, "llm_image_recognition")
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.TransactionImportCategorizationLLMConfig, err = loadLLMConfiguration(config, cfgFile, "llm_import_categorization")
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.TransactionQueryLLMConfig, err = loadLLMConfiguration(config, cfgFile, "llm_transaction_query")
 
 	if err != nil {
 		return nil, err
@@ -861,11 +887,13 @@ func loadStorageConfiguration(config *Config, configFile *ini.File, sectionName
 func loadLLMGlobalConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.TransactionFromAIImageRecognition = getConfigItemBoolValue(configFile, sectionName, "transaction_from_ai_image_recognition", false)
 	config.MaxAIRecognitionPictureFileSize = getConfigItemUint32Value(configFile, sectionName, "max_ai_recognition_picture_size", defaultAIRecognitionPictureMaxSize)
+	config.TransactionImportAICategorization = getConfigItemBoolValue(configFile, sectionName, "transaction_import_ai_categorization", false)
+	config.TransactionNaturalLanguageQuery = getConfigItemBoolValue(configFile, sectionName, "transaction_natural_language_query", false)
 
 	return nil
 }
 
-func loadLLMConfiguration(configFile *ini.File, sectionName string) (*LLMConfig, error) {
+func loadLLMConfiguration(config *Config, configFile *ini.File, sectionName string) (*LLMConfig, error) {
 	llmConfig := &LLMConfig{}
 	llmProvider := getConfigItemStringValue(configFile, sectionName, "llm_provider")
 
@@ -887,6 +915,8 @@ func loadLLMConfiguration(configFile *ini.File, sectionName string) (*LLMConfig,
 		llmConfig.LLMProvider = LMStudioLLMProvider
 	} else if llmProvider == GoogleAILLMProvider {
 		llmConfig.LLMProvider = GoogleAILLMProvider
+	} else if llmProvider == RulesLLMProvider {
+		llmConfig.LLMProvider = RulesLLMProvider
 	} else {
 		return nil, errs.ErrInvalidLLMProvider
 	}
@@ -921,6 +951,19 @@ func loadLLMConfiguration(configFile *ini.File, sectionName string) (*LLMConfig,
 	llmConfig.GoogleAIAPIKey = getConfigItemStringValue(configFile, sectionName, "google_ai_api_key")
 	llmConfig.GoogleAIModelID = getConfigItemStringValue(configFile, sectionName, "google_ai_model_id")
 
+	rulesFilePath := getConfigItemStringValue(configFile, sectionName, "rules_file_path")
+
+	if rulesFilePath != "" {
+		finalRulesFilePath, err := getFinalPath(config.WorkingPath, rulesFilePath)
+		llmConfig.RulesFilePath = finalRulesFilePath
+
+		if llmConfig.LLMProvider == RulesLLMProvider && err != nil {
+			return nil, errs.ErrInvalidLLMRulesFilePath
+		}
+	} else if llmConfig.LLMProvider == RulesLLMProvider {
+		return nil, errs.ErrInvalidLLMRulesFilePath
+	}
+
 	llmConfig.LargeLanguageModelAPIProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
 	llmConfig.LargeLanguageModelAPIRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultLargeLanguageModelAPIRequestTimeout)
 	llmConfig.LargeLanguageModelAPISkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
@@ -972,6 +1015,10 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableSaveExchangeRatesHistory = getConfigItemBoolValue(configFile, sectionName, "enable_save_exchange_rates_history", false)
+	config.EnablePostAssetDepreciation = getConfigItemBoolValue(configFile, sectionName, "enable_post_asset_depreciation", false)
+	config.EnableUpdateOverdueStatuses = getConfigItemBoolValue(configFile, sectionName, "enable_update_overdue_statuses", false)
+	config.EnableSendNotifications = getConfigItemBoolValue(configFile, sectionName, "enable_send_notifications", false)
 
 	return nil
 }
@@ -4,7 +4,12 @@ type KnownTemplate string
 
 // Known templates
 const (
-	TEMPLATE_VERIFY_EMAIL                   KnownTemplate = "email/verify_email"
-	TEMPLATE_PASSWORD_RESET                 KnownTemplate = "email/password_reset"
-	SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION KnownTemplate = "prompt/receipt_image_recognition"
+	TEMPLATE_VERIFY_EMAIL                           KnownTemplate = "email/verify_email"
+	TEMPLATE_PASSWORD_RESET                         KnownTemplate = "email/password_reset"
+	TEMPLATE_WEEKLY_DIGEST                          KnownTemplate = "email/weekly_digest"
+	TEMPLATE_DUE_REMINDER                           KnownTemplate = "email/due_reminder"
+	SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION         KnownTemplate = "prompt/receipt_image_recognition"
+	SYSTEM_PROMPT_RECEIPT_FILE_RECOGNITION          KnownTemplate = "prompt/receipt_file_recognition"
+	SYSTEM_PROMPT_IMPORT_TRANSACTION_CATEGORIZATION KnownTemplate = "prompt/import_transaction_categorization"
+	SYSTEM_PROMPT_TRANSACTION_QUERY                 KnownTemplate = "prompt/transaction_query"
 )
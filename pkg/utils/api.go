@@ -76,7 +76,11 @@ func PrintJSONRPCSuccessResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCR
 // PrintJSONRPCErrorResult writes error response in JSON-RPC format to current http context
 func PrintJSONRPCErrorResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest, err *errs.Error) {
 	c.SetResponseError(err)
+	c.AbortWithStatusJSON(err.HttpStatusCode, GetJSONRPCErrorResponse(jsonRPCRequest, err))
+}
 
+// GetJSONRPCErrorResponse returns the JSON-RPC error response of the specified error
+func GetJSONRPCErrorResponse(jsonRPCRequest *core.JSONRPCRequest, err *errs.Error) *core.JSONRPCResponse {
 	var id any
 
 	if jsonRPCRequest != nil {
@@ -93,7 +97,7 @@ func PrintJSONRPCErrorResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCReq
 		jsonRPCError = core.JSONRPCInvalidParamsError
 	}
 
-	c.AbortWithStatusJSON(err.HttpStatusCode, core.NewJSONRPCErrorResponseWithCause(id, jsonRPCError, GetDisplayErrorMessage(err)))
+	return core.NewJSONRPCErrorResponseWithCause(id, jsonRPCError, GetDisplayErrorMessage(err))
 }
 
 // PrintDataErrorResult writes error response in custom content type to current http context
@@ -188,6 +192,12 @@ func getValidationErrorText(err validator.FieldError) string {
 		return errs.GetParameterInvalidAmountFilterMessage(fieldName)
 	case "validTagFilter":
 		return errs.GetParameterInvalidTagFilterMessage(fieldName)
+	case "validGeoBoundsFilter":
+		return errs.GetParameterInvalidGeoBoundsFilterMessage(fieldName)
+	case "validCommentFilter":
+		return errs.GetParameterInvalidCommentFilterMessage(fieldName)
+	case "validConditionGroups":
+		return errs.GetParameterInvalidConditionGroupsMessage(fieldName)
 	}
 
 	return errs.GetParameterInvalidMessage(fieldName)
@@ -15,6 +15,10 @@ var imageFileExtensionContentTypeMap = map[string]string{
 	"webp": "image/webp",
 }
 
+var documentFileExtensionContentTypeMap = map[string]string{
+	"pdf": "application/pdf",
+}
+
 // GetImageContentType returns the content type of specified image file extension or returns empty when the file extension is not image or not supported
 func GetImageContentType(fileExtension string) string {
 	contentType, exists := imageFileExtensionContentTypeMap[fileExtension]
@@ -26,6 +30,33 @@ func GetImageContentType(fileExtension string) string {
 	return contentType
 }
 
+// GetDocumentContentType returns the content type of specified document file extension or returns empty when the file extension is not document or not supported
+func GetDocumentContentType(fileExtension string) string {
+	contentType, exists := documentFileExtensionContentTypeMap[fileExtension]
+
+	if !exists {
+		return ""
+	}
+
+	return contentType
+}
+
+// GetDocumentFileExtension returns the file extension of specified document content type or returns empty when the content type is not document or not supported
+func GetDocumentFileExtension(contentType string) string {
+	for fileExtension, documentContentType := range documentFileExtensionContentTypeMap {
+		if documentContentType == contentType {
+			return fileExtension
+		}
+	}
+
+	return ""
+}
+
+// IsDocumentContentType returns whether the specified content type is a supported document content type
+func IsDocumentContentType(contentType string) bool {
+	return GetDocumentFileExtension(contentType) != ""
+}
+
 // ListFileNamesWithPrefixAndSuffix returns file name list which has specified prefix and suffix
 func ListFileNamesWithPrefixAndSuffix(path string, prefix string, suffix string) []string {
 	dir, err := os.Open(path)
@@ -18,6 +18,21 @@ func TestGetImageContentType(t *testing.T) {
 	assert.Equal(t, expectedContentType, actualContentType)
 }
 
+func TestGetDocumentContentType(t *testing.T) {
+	assert.Equal(t, "application/pdf", GetDocumentContentType("pdf"))
+	assert.Equal(t, "", GetDocumentContentType("png"))
+}
+
+func TestGetDocumentFileExtension(t *testing.T) {
+	assert.Equal(t, "pdf", GetDocumentFileExtension("application/pdf"))
+	assert.Equal(t, "", GetDocumentFileExtension("image/png"))
+}
+
+func TestIsDocumentContentType(t *testing.T) {
+	assert.True(t, IsDocumentContentType("application/pdf"))
+	assert.False(t, IsDocumentContentType("image/png"))
+}
+
 func TestGetFileNameWithoutExtension(t *testing.T) {
 	fileName := "name.ext"
 	expectedName := "name"
@@ -0,0 +1,22 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ValidCommentFilter returns whether the given comment filter is valid
+func ValidCommentFilter(fl validator.FieldLevel) bool {
+	if value, ok := fl.Field().Interface().(string); ok {
+		if value == "" {
+			return true
+		}
+
+		_, err := models.ParseTransactionCommentFilter(value)
+
+		return err == nil
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyCommentFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validCommentFilter", ValidCommentFilter)
+	assert.Nil(t, err)
+
+	err = validate.Var("", "validCommentFilter")
+	assert.Nil(t, err)
+}
+
+func TestValidCommentFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validCommentFilter", ValidCommentFilter)
+	assert.Nil(t, err)
+
+	filterTypes := []string{"ct", "nc", "eq", "ne", "sw", "ew"}
+
+	for _, filterType := range filterTypes {
+		err = validate.Var(filterType+":test", "validCommentFilter")
+		assert.Nil(t, err)
+
+		err = validate.Var(filterType+":a:b", "validCommentFilter")
+		assert.Nil(t, err)
+	}
+
+	err = validate.Var("em", "validCommentFilter")
+	assert.Nil(t, err)
+
+	err = validate.Var("nm", "validCommentFilter")
+	assert.Nil(t, err)
+}
+
+func TestInvalidCommentFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validCommentFilter", ValidCommentFilter)
+	assert.Nil(t, err)
+
+	err = validate.Var("ct", "validCommentFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("ct:", "validCommentFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("em:test", "validCommentFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("xx:test", "validCommentFilter")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,22 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ValidConditionGroups returns whether the given transaction query condition groups is valid
+func ValidConditionGroups(fl validator.FieldLevel) bool {
+	if value, ok := fl.Field().Interface().(string); ok {
+		if value == "" {
+			return true
+		}
+
+		_, err := models.ParseTransactionQueryConditionGroupRequests(value)
+
+		return err == nil
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyConditionGroups(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validConditionGroups", ValidConditionGroups)
+	assert.Nil(t, err)
+
+	err = validate.Var("", "validConditionGroups")
+	assert.Nil(t, err)
+}
+
+func TestValidConditionGroups(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validConditionGroups", ValidConditionGroups)
+	assert.Nil(t, err)
+
+	err = validate.Var(`[{"type":3,"cfoId":"1","plannedFilter":1},{"categoryIds":"1,2","commentFilter":"ct:test","geoBounds":"116.1,39.7,116.7,40.2"}]`, "validConditionGroups")
+	assert.Nil(t, err)
+}
+
+func TestInvalidConditionGroups(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validConditionGroups", ValidConditionGroups)
+	assert.Nil(t, err)
+
+	err = validate.Var(`{"type":3}`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var(`[{"type":5}]`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var(`[{"plannedFilter":3}]`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var(`[{"commentFilter":"xx:test"}]`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var(`[{"geoBounds":"1,2"}]`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var(`[null]`, "validConditionGroups")
+	assert.NotNil(t, err)
+
+	err = validate.Var("["+strings.Repeat(`{},`, 10)+"{}]", "validConditionGroups")
+	assert.NotNil(t, err)
+}
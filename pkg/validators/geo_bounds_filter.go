@@ -0,0 +1,22 @@
+package validators
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ValidGeoBoundsFilter returns whether the given geographic bounds filter is valid
+func ValidGeoBoundsFilter(fl validator.FieldLevel) bool {
+	if value, ok := fl.Field().Interface().(string); ok {
+		if value == "" {
+			return true
+		}
+
+		_, err := models.ParseTransactionGeoBoundsFilter(value)
+
+		return err == nil
+	}
+
+	return false
+}
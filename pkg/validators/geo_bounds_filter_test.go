@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyGeoBoundsFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validGeoBoundsFilter", ValidGeoBoundsFilter)
+	assert.Nil(t, err)
+
+	err = validate.Var("", "validGeoBoundsFilter")
+	assert.Nil(t, err)
+}
+
+func TestValidGeoBoundsFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validGeoBoundsFilter", ValidGeoBoundsFilter)
+	assert.Nil(t, err)
+
+	err = validate.Var("116.1,39.7,116.7,40.2", "validGeoBoundsFilter")
+	assert.Nil(t, err)
+
+	err = validate.Var("170,-10,-170,10", "validGeoBoundsFilter")
+	assert.Nil(t, err)
+}
+
+func TestInvalidGeoBoundsFilter(t *testing.T) {
+	validate := validator.New()
+	err := validate.RegisterValidation("validGeoBoundsFilter", ValidGeoBoundsFilter)
+	assert.Nil(t, err)
+
+	err = validate.Var("116.1,39.7,116.7", "validGeoBoundsFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("116.1,39.7,116.7,abc", "validGeoBoundsFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("116.1,40.2,116.7,39.7", "validGeoBoundsFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("-181,0,10,10", "validGeoBoundsFilter")
+	assert.NotNil(t, err)
+
+	err = validate.Var("0,-91,10,10", "validGeoBoundsFilter")
+	assert.NotNil(t, err)
+}